@@ -141,6 +141,9 @@ const (
 	// ConditionTypeAuthError describes an error condition related to Auth.
 	ConditionTypeAuthError = "AuthError"
 
+	// ConditionTypeAuthorizationPolicyError describes an error condition related to authorization policies.
+	ConditionTypeAuthorizationPolicyError = "AuthorizationPolicyError"
+
 	// ConditionTypeCORSError describes an error condition related to CORS.
 	ConditionTypeCORSError = "CORSError"
 
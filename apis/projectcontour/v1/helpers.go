@@ -14,7 +14,11 @@
 package v1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // AuthorizationConfigured returns whether authorization  is
@@ -204,6 +208,58 @@ func (status *HTTPProxyStatus) GetConditionFor(condType string) *DetailedConditi
 	return nil
 }
 
+// GetTrafficShiftFor returns a pointer to the status of the traffic
+// shift following the policy, or nil if there is none currently present.
+// The status of a shift that followed a previous version of the policy
+// is not returned, so that changing the policy starts the shift again.
+func (status *HTTPProxyStatus) GetTrafficShiftFor(policy *TrafficShiftPolicy) *TrafficShiftStatus {
+	hash := policy.Hash()
+	for i, shift := range status.TrafficShifts {
+		if shift.Stable == policy.Stable && shift.Canary == policy.Canary && shift.PolicyHash == hash {
+			return &status.TrafficShifts[i]
+		}
+	}
+
+	return nil
+}
+
+// Hash returns a short hash of the traffic shift policy.
+func (p *TrafficShiftPolicy) Hash() string {
+	// Marshaling a struct of strings, numbers and
+	// slices of them can't fail.
+	data, _ := json.Marshal(p)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// StepInterval returns how long each step of the traffic shift lasts.
+func (p *TrafficShiftPolicy) StepInterval() (time.Duration, error) {
+	if p.Interval == "" {
+		return time.Minute, nil
+	}
+
+	interval, err := time.ParseDuration(p.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q: %w", p.Interval, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid interval %q: must be positive", p.Interval)
+	}
+	return interval, nil
+}
+
+// GetConditionFor returns the a pointer to the condition for a given type,
+// or nil if there are none currently present.
+func (status *TLSCertificateDelegationStatus) GetConditionFor(condType string) *DetailedCondition {
+	for i, cond := range status.Conditions {
+		if cond.Type == condType {
+			return &status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
 // LongMessageLength specifies the maximum size any message field should be.
 // This is enforced on the apiserver side by CRD validation requirements.
 const LongMessageLength = 32760
@@ -19,6 +19,7 @@ package v1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -541,6 +542,62 @@ func TestGetConditionFor(t *testing.T) {
 	}
 }
 
+func TestGetTrafficShiftFor(t *testing.T) {
+	v2 := &TrafficShiftPolicy{Stable: "kuard", Canary: "kuard-v2", Steps: []uint32{10, 50}}
+	v3 := &TrafficShiftPolicy{Stable: "kuard", Canary: "kuard-v3", Steps: []uint32{10, 50}}
+
+	status := HTTPProxyStatus{
+		TrafficShifts: []TrafficShiftStatus{
+			{Stable: "kuard", Canary: "kuard-v2", PolicyHash: v2.Hash(), Weight: 10},
+			{Stable: "kuard", Canary: "kuard-v3", PolicyHash: v3.Hash(), Weight: 50},
+		},
+	}
+
+	assert.Equal(t, &status.TrafficShifts[1], status.GetTrafficShiftFor(v3))
+	assert.Nil(t, status.GetTrafficShiftFor(&TrafficShiftPolicy{Stable: "kuard-v2", Canary: "kuard", Steps: []uint32{10, 50}}))
+	assert.Nil(t, (&HTTPProxyStatus{}).GetTrafficShiftFor(v2))
+
+	// The status of a previous version of the policy is not returned.
+	assert.Nil(t, status.GetTrafficShiftFor(&TrafficShiftPolicy{Stable: "kuard", Canary: "kuard-v2", Steps: []uint32{20, 50}}))
+}
+
+func TestTrafficShiftPolicyHash(t *testing.T) {
+	policy := &TrafficShiftPolicy{Stable: "kuard", Canary: "kuard-v2", Steps: []uint32{10, 50}, Interval: "1m"}
+
+	assert.Len(t, policy.Hash(), 16)
+	assert.Equal(t, policy.Hash(), policy.DeepCopy().Hash())
+
+	changed := policy.DeepCopy()
+	changed.ErrorRate = &TrafficShiftErrorRate{Source: TrafficShiftErrorRateSourceEnvoy, Threshold: 5}
+	assert.NotEqual(t, policy.Hash(), changed.Hash())
+}
+
+func TestTrafficShiftPolicyStepInterval(t *testing.T) {
+	tests := map[string]struct {
+		interval string
+		want     time.Duration
+		wantErr  bool
+	}{
+		"default":  {interval: "", want: time.Minute},
+		"set":      {interval: "30s", want: 30 * time.Second},
+		"invalid":  {interval: "soon", wantErr: true},
+		"zero":     {interval: "0s", wantErr: true},
+		"negative": {interval: "-1m", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := (&TrafficShiftPolicy{Interval: tc.interval}).StepInterval()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestGetError(t *testing.T) {
 	dcWithErrors := &DetailedCondition{
 		Errors: []SubCondition{
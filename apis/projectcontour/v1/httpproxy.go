@@ -274,6 +274,66 @@ type AuthorizationServer struct {
 	// WithRequestBody specifies configuration for sending the client request's body to authorization server.
 	// +optional
 	WithRequestBody *AuthorizationServerBufferSettings `json:"withRequestBody,omitempty"`
+
+	// HTTPService configures Envoy to check client requests by sending
+	// them to the authorization server as plain HTTP requests, rather
+	// than using the gRPC authorization API. The authorization server
+	// allows a request by responding with a 2xx status code.
+	//
+	// +optional
+	HTTPService *AuthorizationHTTPService `json:"httpService,omitempty"`
+
+	// AllowedRequestHeaders lists the client request headers that are
+	// sent to the authorization server. If empty, all headers are sent
+	// to gRPC authorization servers, and only the Host, Method, Path,
+	// Content-Length and Authorization headers are sent to HTTP
+	// authorization servers.
+	//
+	// +optional
+	AllowedRequestHeaders []string `json:"allowedRequestHeaders,omitempty"`
+
+	// AllowedUpstreamHeaders lists the headers that the authorization
+	// server may add to, or overwrite on, an allowed client request
+	// before it is forwarded to the upstream service. If empty, gRPC
+	// authorization servers may set any header, and no headers are
+	// copied from the responses of HTTP authorization servers.
+	//
+	// +optional
+	AllowedUpstreamHeaders []string `json:"allowedUpstreamHeaders,omitempty"`
+
+	// AllowedClientHeaders lists the headers of a denied authorization
+	// response that are returned to the client. If empty, all headers
+	// are returned. Only HTTP authorization servers are supported, as
+	// gRPC authorization servers choose the headers of denied responses
+	// themselves.
+	//
+	// +optional
+	AllowedClientHeaders []string `json:"allowedClientHeaders,omitempty"`
+
+	// MetadataNamespaces lists the dynamic metadata namespaces, set by
+	// earlier HTTP filters, that are sent to the authorization server.
+	// Only gRPC authorization servers receive metadata.
+	//
+	// +optional
+	MetadataNamespaces []string `json:"metadataNamespaces,omitempty"`
+
+	// RouteMetadataNamespaces lists the route metadata namespaces that
+	// are sent to the authorization server. Only gRPC authorization
+	// servers receive metadata.
+	//
+	// +optional
+	RouteMetadataNamespaces []string `json:"routeMetadataNamespaces,omitempty"`
+}
+
+// AuthorizationHTTPService configures an authorization server that is
+// reached over plain HTTP.
+type AuthorizationHTTPService struct {
+	// PathPrefix is prepended to the client request path when sending
+	// the authorization request.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^/`
+	PathPrefix string `json:"pathPrefix,omitempty"`
 }
 
 // AuthorizationServerBufferSettings enables ExtAuthz filter to buffer client request data and send it as part of authorization request
@@ -358,6 +418,12 @@ type VirtualHost struct {
 	// Only one of IPAllowFilterPolicy and IPDenyFilterPolicy can be defined.
 	// The rules defined here may be overridden in a Route.
 	IPDenyFilterPolicy []IPFilterPolicy `json:"ipDenyPolicy,omitempty"`
+
+	// AuthorizationPolicy defines rules for which matching requests
+	// should be allowed or denied.
+	// The policy defined here may be overridden in a Route.
+	// +optional
+	AuthorizationPolicy *RBACPolicy `json:"authorizationPolicy,omitempty"`
 }
 
 // JWTProvider defines how to verify JWTs on requests.
@@ -387,14 +453,56 @@ type JWTProvider struct {
 	Audiences []string `json:"audiences,omitempty"`
 
 	// Remote JWKS to use for verifying JWT signatures.
-	// +kubebuilder:validation:Required
-	RemoteJWKS RemoteJWKS `json:"remoteJWKS"`
+	// Exactly one of RemoteJWKS and LocalJWKS must be specified.
+	// +optional
+	RemoteJWKS *RemoteJWKS `json:"remoteJWKS,omitempty"`
+
+	// Local JWKS to use for verifying JWT signatures.
+	// Exactly one of RemoteJWKS and LocalJWKS must be specified.
+	// +optional
+	LocalJWKS *LocalJWKS `json:"localJWKS,omitempty"`
 
 	// Whether the JWT should be forwarded to the backend
 	// service after successful verification. By default,
 	// the JWT is not forwarded.
 	// +optional
 	ForwardJWT bool `json:"forwardJWT,omitempty"`
+
+	// ClaimToHeaders lists JWT claims to copy into request
+	// headers after successful verification, so that they
+	// can be used by the backend service.
+	// +optional
+	ClaimToHeaders []JWTClaimToHeader `json:"claimToHeaders,omitempty"`
+}
+
+// LocalJWKS defines a JWKS stored in a ConfigMap or Secret in the
+// same namespace as the HTTPProxy. The JWKS is read from the
+// "jwks.json" key of the ConfigMap or Secret.
+type LocalJWKS struct {
+	// Kind of the object holding the JWKS, either ConfigMap or Secret.
+	// If not specified, defaults to ConfigMap.
+	// +optional
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the ConfigMap or Secret holding the JWKS.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// JWTClaimToHeader defines a JWT claim to copy into a request header.
+type JWTClaimToHeader struct {
+	// HeaderName is the name of the request header to set.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	HeaderName string `json:"headerName"`
+
+	// Claim is the name of the JWT claim to copy. Nested
+	// claims are separated by ".", e.g. "user.id".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Claim string `json:"claim"`
 }
 
 // RemoteJWKS defines how to fetch a JWKS from an HTTP endpoint.
@@ -448,6 +556,14 @@ type TLS struct {
 	// The name can be optionally prefixed with namespace "namespace/name".
 	// When cross-namespace reference is used, TLSCertificateDelegation resource must exist in the namespace to grant access to the secret.
 	SecretName string `json:"secretName,omitempty"`
+	// AdditionalSecretNames are the names of further TLS secrets to
+	// serve for the virtual host alongside SecretName, e.g. an ECDSA
+	// certificate in addition to an RSA one. Envoy presents the
+	// certificate best suited to the capabilities of the client.
+	// Each name follows the same rules as SecretName, which must
+	// also be specified.
+	// +optional
+	AdditionalSecretNames []string `json:"additionalSecretNames,omitempty"`
 	// MinimumProtocolVersion is the minimum TLS version this vhost should
 	// negotiate. Valid options are `1.2` (default) and `1.3`. Any other value
 	// defaults to TLS 1.2.
@@ -625,6 +741,17 @@ type Route struct {
 	// Only one of IPAllowFilterPolicy and IPDenyFilterPolicy can be defined.
 	// The rules defined here override any rules set on the root HTTPProxy.
 	IPDenyFilterPolicy []IPFilterPolicy `json:"ipDenyPolicy,omitempty"`
+
+	// AuthorizationPolicy defines rules for which matching requests
+	// should be allowed or denied.
+	// The policy defined here overrides any policy set on the root HTTPProxy.
+	// +optional
+	AuthorizationPolicy *RBACPolicy `json:"authorizationPolicy,omitempty"`
+
+	// The policy for progressively shifting the traffic of this route
+	// from a stable service to a canary service.
+	// +optional
+	TrafficShiftPolicy *TrafficShiftPolicy `json:"trafficShiftPolicy,omitempty"`
 }
 
 type JWTVerificationPolicy struct {
@@ -642,6 +769,29 @@ type JWTVerificationPolicy struct {
 	// "require" field can be specified.
 	// +optional
 	Disabled bool `json:"disabled,omitempty"`
+
+	// RequiredClaims lists JWT claims that the verified JWT must
+	// have for requests to the route to be allowed. Requests whose
+	// JWT does not have all of the claims are denied with a 403
+	// status code. Can only be specified when the route requires
+	// a JWT provider.
+	// +optional
+	RequiredClaims []JWTRequiredClaim `json:"requiredClaims,omitempty"`
+}
+
+// JWTRequiredClaim defines a JWT claim that must have one of a set of values.
+type JWTRequiredClaim struct {
+	// Name of the JWT claim. Nested claims are
+	// separated by ".", e.g. "user.role".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Values the claim is allowed to have. If the claim
+	// is a list, it must contain one of the values.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Values []string `json:"values"`
 }
 
 // IPFilterSource indicates which IP should be considered for filtering
@@ -667,6 +817,84 @@ type IPFilterPolicy struct {
 	CIDR string `json:"cidr"`
 }
 
+// RBACPolicy defines rules that allow or deny requests.
+// Requests matching any deny rule are denied. If allow rules
+// are specified, requests must also match at least one of them
+// to be allowed. Denied requests receive a 403 status code.
+type RBACPolicy struct {
+	// Allow is a list of rules for which matching requests
+	// should be allowed. If empty, all requests not matching
+	// a deny rule are allowed.
+	// +optional
+	Allow []RBACRule `json:"allow,omitempty"`
+
+	// Deny is a list of rules for which matching requests
+	// should be denied. Deny rules take precedence over
+	// allow rules.
+	// +optional
+	Deny []RBACRule `json:"deny,omitempty"`
+}
+
+// RBACRule matches requests. A request matches the rule if
+// it matches every condition specified in the rule. At least
+// one condition must be specified.
+type RBACRule struct {
+	// Methods matches requests whose HTTP method is one of the given methods.
+	// +optional
+	Methods []string `json:"methods,omitempty"`
+
+	// Paths matches requests whose path, excluding the query string,
+	// matches any of the given conditions.
+	// +optional
+	Paths []RBACPathMatch `json:"paths,omitempty"`
+
+	// Headers matches requests whose headers match all of the
+	// given conditions.
+	// +optional
+	Headers []HeaderMatchCondition `json:"headers,omitempty"`
+
+	// ClientCertificate matches requests made over a TLS connection
+	// with a client certificate matching the given conditions.
+	// +optional
+	ClientCertificate *RBACClientCertificateMatch `json:"clientCertificate,omitempty"`
+
+	// JWTClaims matches requests whose verified JWT has all of the
+	// given claims. Can only be specified for routes that require
+	// a JWT provider.
+	// +optional
+	JWTClaims []JWTRequiredClaim `json:"jwtClaims,omitempty"`
+
+	// SourceIPs matches requests whose source ip address is within
+	// any of the given ranges.
+	// +optional
+	SourceIPs []IPFilterPolicy `json:"sourceIPs,omitempty"`
+}
+
+// RBACPathMatch defines a condition on the request path. Exactly
+// one of Prefix, Exact or Regex must be specified.
+type RBACPathMatch struct {
+	// Prefix matches paths starting with the given value.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Exact matches paths equal to the given value.
+	// +optional
+	Exact string `json:"exact,omitempty"`
+
+	// Regex matches paths matching the given regular expression.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+}
+
+// RBACClientCertificateMatch defines conditions on the client certificate.
+type RBACClientCertificateMatch struct {
+	// Names matches client certificates having a URI SAN, a DNS SAN
+	// or a subject equal to one of the given names.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Names []string `json:"names"`
+}
+
 type HTTPDirectResponsePolicy struct {
 	// StatusCode is the HTTP response status to be returned.
 	// +required
@@ -767,6 +995,77 @@ type HTTPInternalRedirectPolicy struct {
 	DenyRepeatedRouteRedirect bool `json:"denyRepeatedRouteRedirect,omitempty"`
 }
 
+// TrafficShiftPolicy defines how the traffic of a route is shifted
+// from a stable service to a canary service in steps. Contour applies
+// the first step, then applies each following step once the previous
+// one has lasted for Interval. The progress of the shift is reported
+// in the trafficShifts status of the HTTPProxy.
+type TrafficShiftPolicy struct {
+	// Stable is the name of the service of the route that receives
+	// the traffic not sent to the canary.
+	// +kubebuilder:validation:MinLength=1
+	Stable string `json:"stable"`
+
+	// Canary is the name of the service of the route that traffic
+	// is shifted to.
+	// +kubebuilder:validation:MinLength=1
+	Canary string `json:"canary"`
+
+	// Steps are the percentages of traffic sent to the canary, in
+	// the order they are applied. The shift succeeds once the last
+	// step has lasted for Interval.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Maximum=100
+	Steps []uint32 `json:"steps"`
+
+	// Interval is how long each step lasts.
+	// If not specified, a default of 1m applies.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(((\d*(\.\d*)?h)|(\d*(\.\d*)?m)|(\d*(\.\d*)?s)|(\d*(\.\d*)?ms)|(\d*(\.\d*)?us)|(\d*(\.\d*)?µs)|(\d*(\.\d*)?ns))+)$`
+	Interval string `json:"interval,omitempty"`
+
+	// Header sends the requests that match it to the canary,
+	// whatever the current step, unless the shift was aborted.
+	// +optional
+	Header *HeaderMatchCondition `json:"header,omitempty"`
+
+	// ErrorRate aborts the shift, sending all traffic back to the
+	// stable service, if the error rate of the canary exceeds a
+	// threshold.
+	// +optional
+	ErrorRate *TrafficShiftErrorRate `json:"errorRate,omitempty"`
+}
+
+// TrafficShiftErrorRateSource is where the error rate of a canary is read from.
+// +kubebuilder:validation:Enum=Envoy;External
+type TrafficShiftErrorRateSource string
+
+const (
+	// TrafficShiftErrorRateSourceEnvoy reads the error rate from the
+	// statistics of the canary endpoints of the shift, scraped from
+	// the Envoys connected to Contour. It is the percentage of the
+	// requests the shift sent to the canary since the current step
+	// was applied that failed.
+	TrafficShiftErrorRateSourceEnvoy TrafficShiftErrorRateSource = "Envoy"
+
+	// TrafficShiftErrorRateSourceExternal reads the error rate, as a
+	// percentage, from the projectcontour.io/traffic-shift-error-rate
+	// annotation of the HTTPProxy, which is set by an external system.
+	TrafficShiftErrorRateSourceExternal TrafficShiftErrorRateSource = "External"
+)
+
+// TrafficShiftErrorRate defines the error rate above which a
+// traffic shift is aborted.
+type TrafficShiftErrorRate struct {
+	// Source is where the error rate of the canary is read from.
+	Source TrafficShiftErrorRateSource `json:"source"`
+
+	// Threshold is the error rate, as a percentage, above which
+	// the shift is aborted.
+	// +kubebuilder:validation:Maximum=100
+	Threshold uint32 `json:"threshold"`
+}
+
 type CookieRewritePolicy struct {
 	// Name is the name of the cookie for which attributes will be rewritten.
 	// +kubebuilder:validation:MinLength=1
@@ -864,6 +1163,68 @@ type LocalRateLimitPolicy struct {
 	// set when a request is rate-limited.
 	// +optional
 	ResponseHeadersToAdd []HeaderValue `json:"responseHeadersToAdd,omitempty"`
+
+	// Descriptors defines a list of descriptors, each with its own
+	// token bucket. A request that matches a descriptor is rate limited
+	// using only that descriptor's token bucket, rather than the token
+	// bucket defined by Requests, Unit and Burst. The first matching
+	// descriptor is used.
+	// +optional
+	Descriptors []LocalRateLimitDescriptor `json:"descriptors,omitempty"`
+
+	// MaxDynamicDescriptors defines how many distinct values are tracked,
+	// each with its own token bucket, for each descriptor that has
+	// entries without a value. When more values are seen, the least
+	// recently used bucket is dropped. Defaults to 20.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxDynamicDescriptors uint32 `json:"maxDynamicDescriptors,omitempty"`
+}
+
+// LocalRateLimitDescriptor defines a list of key-value pair generators
+// and the token bucket used for requests that generate them.
+type LocalRateLimitDescriptor struct {
+	// Entries is the list of key-value pair generators. A request
+	// matches the descriptor if every entry generates its value.
+	// +kubebuilder:validation:MinItems=1
+	Entries []LocalRateLimitDescriptorEntry `json:"entries,omitempty"`
+
+	// Requests defines how many requests per unit of time should
+	// be allowed before rate limiting occurs.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	Requests uint32 `json:"requests"`
+
+	// Unit defines the period of time within which requests
+	// over the limit will be rate limited. Valid values are
+	// "second", "minute" and "hour". The unit must not be shorter
+	// than the unit of the local rate limit policy.
+	// +kubebuilder:validation:Enum=second;minute;hour
+	// +required
+	Unit string `json:"unit"`
+
+	// Burst defines the number of requests above the requests per
+	// unit that should be allowed within a short period of time.
+	// +optional
+	Burst uint32 `json:"burst,omitempty"`
+}
+
+// LocalRateLimitDescriptorEntry is a key-value pair generator that a
+// request must match. Exactly one of the descriptor entry fields must
+// be set; the metadata entry is not supported for local rate limiting.
+type LocalRateLimitDescriptorEntry struct {
+	RateLimitDescriptorEntry `json:",inline"`
+
+	// Value defines the value the entry must generate for the request
+	// to match, for entries whose value is taken from the request:
+	// requestHeader, remoteAddress, maskedRemoteAddress, queryParameter
+	// and destinationCluster. If it is not set, the entry matches any
+	// value and each distinct value gets its own token bucket, so for
+	// example each client address is rate limited separately. It must
+	// not be set for genericKey and requestHeaderValueMatch entries,
+	// which generate a static value.
+	// +optional
+	Value string `json:"value,omitempty"`
 }
 
 // GlobalRateLimitPolicy defines global rate limiting parameters.
@@ -912,6 +1273,32 @@ type RateLimitDescriptorEntry struct {
 	// and a value equal to the client's IP address (from x-forwarded-for).
 	// +optional
 	RemoteAddress *RemoteAddressDescriptor `json:"remoteAddress,omitempty" yaml:"remoteAddress,omitempty"`
+
+	// MaskedRemoteAddress defines a descriptor entry with a key of "masked_cidr"
+	// and a value equal to the client's IP address (from x-forwarded-for)
+	// masked to the given prefix length, in CIDR notation.
+	// +optional
+	MaskedRemoteAddress *MaskedRemoteAddressDescriptor `json:"maskedRemoteAddress,omitempty" yaml:"maskedRemoteAddress,omitempty"`
+
+	// QueryParameter defines a descriptor entry that's populated only if
+	// a given query parameter is present on the request. The descriptor key
+	// is static, and the descriptor value is equal to the value of the
+	// query parameter.
+	// +optional
+	QueryParameter *QueryParameterDescriptor `json:"queryParameter,omitempty" yaml:"queryParameter,omitempty"`
+
+	// Metadata defines a descriptor entry that's populated only if the
+	// given dynamic metadata is present on the request, such as a claim
+	// from a verified JWT. The descriptor key is static, and the descriptor
+	// value is equal to the value of the metadata.
+	// +optional
+	Metadata *MetadataDescriptor `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// DestinationCluster defines a descriptor entry with a key of
+	// "destination_cluster" and a value equal to the name of the upstream
+	// cluster the request is routed to.
+	// +optional
+	DestinationCluster *DestinationClusterDescriptor `json:"destinationCluster,omitempty" yaml:"destinationCluster,omitempty"`
 }
 
 // GenericKeyDescriptor defines a descriptor entry with a static key and
@@ -970,6 +1357,77 @@ type RequestHeaderValueMatchDescriptor struct {
 // (from x-forwarded-for).
 type RemoteAddressDescriptor struct{}
 
+// MaskedRemoteAddressDescriptor defines a descriptor entry with a key of
+// "masked_cidr" and a value equal to the client's IP address (from
+// x-forwarded-for) masked to a prefix length, in CIDR notation.
+type MaskedRemoteAddressDescriptor struct {
+	// V4PrefixLength defines the prefix length to mask IPv4 addresses to.
+	// If not set, defaults to 32.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	V4PrefixLength uint32 `json:"v4PrefixLength,omitempty" yaml:"v4PrefixLength,omitempty"`
+
+	// V6PrefixLength defines the prefix length to mask IPv6 addresses to.
+	// If not set, defaults to 128.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=128
+	V6PrefixLength uint32 `json:"v6PrefixLength,omitempty" yaml:"v6PrefixLength,omitempty"`
+}
+
+// QueryParameterDescriptor defines a descriptor entry that's populated only
+// if a given query parameter is present on the request. The value of the
+// descriptor entry is equal to the value of the query parameter (if present).
+type QueryParameterDescriptor struct {
+	// ParameterName defines the name of the query parameter to look for
+	// on the request.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	ParameterName string `json:"parameterName,omitempty" yaml:"parameterName,omitempty"`
+
+	// DescriptorKey defines the key to use on the descriptor entry.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	DescriptorKey string `json:"descriptorKey,omitempty" yaml:"descriptorKey,omitempty"`
+}
+
+// MetadataDescriptor defines a descriptor entry that's populated only if
+// the given dynamic metadata is present on the request. The value of the
+// descriptor entry is equal to the value of the metadata (if present).
+//
+// The payload of a JWT verified by a provider is available under the
+// "envoy.filters.http.jwt_authn" filter at the path [<provider name>, <claim>].
+type MetadataDescriptor struct {
+	// Filter defines the metadata namespace to look in, usually the name
+	// of the Envoy filter that wrote the metadata.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Filter string `json:"filter,omitempty" yaml:"filter,omitempty"`
+
+	// Path defines the keys to follow, in order, within the metadata
+	// namespace. The value found at the end of the path must be a string.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	Path []string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// DescriptorKey defines the key to use on the descriptor entry.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	DescriptorKey string `json:"descriptorKey,omitempty" yaml:"descriptorKey,omitempty"`
+
+	// DefaultValue defines the descriptor value to use when the
+	// metadata is not present. If not set, the descriptor entry
+	// is not populated when the metadata is not present.
+	// +optional
+	DefaultValue string `json:"defaultValue,omitempty" yaml:"defaultValue,omitempty"`
+}
+
+// DestinationClusterDescriptor defines a descriptor entry with a key of
+// "destination_cluster" and a value equal to the name of the upstream
+// cluster the request is routed to.
+type DestinationClusterDescriptor struct{}
+
 // TCPProxy contains the set of services to proxy TCP connections.
 type TCPProxy struct {
 	// The load balancing policy for the backend services. Note that the
@@ -992,6 +1450,42 @@ type TCPProxy struct {
 	// The health check policy for this tcp proxy
 	// +optional
 	HealthCheckPolicy *TCPHealthCheckPolicy `json:"healthCheckPolicy,omitempty"`
+	// IdleTimeout is how long a connection may stay open without data
+	// being sent or received before it is closed.
+	// Set to "infinity" to disable the idle timeout.
+	// If not supplied, a default of 2h30m applies.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(((\d*(\.\d*)?h)|(\d*(\.\d*)?m)|(\d*(\.\d*)?s)|(\d*(\.\d*)?ms)|(\d*(\.\d*)?us)|(\d*(\.\d*)?µs)|(\d*(\.\d*)?ns))+|infinity|infinite)$`
+	IdleTimeout string `json:"idleTimeout,omitempty"`
+	// MaxConnectAttempts is the maximum number of unsuccessful attempts to
+	// connect to the upstream services before the client connection is closed.
+	// If not supplied, Envoy's default value of 1 applies.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConnectAttempts uint32 `json:"maxConnectAttempts,omitempty"`
+	// UpstreamProxyProtocol is the PROXY protocol version used to send
+	// the client's address to the upstream services at the start of each
+	// connection. If not supplied, no PROXY protocol header is sent.
+	// +optional
+	// +kubebuilder:validation:Enum=v1;v2
+	UpstreamProxyProtocol string `json:"upstreamProxyProtocol,omitempty"`
+	// AccessLog defines the access logging for connections through this tcp proxy.
+	// If not supplied, connections are logged like other connections to the HTTPS listener.
+	// +optional
+	AccessLog *TCPProxyAccessLog `json:"accessLog,omitempty"`
+}
+
+// TCPProxyAccessLog defines the access logging for a tcp proxy.
+type TCPProxyAccessLog struct {
+	// Disabled turns off access logging for this tcp proxy.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Format is the Envoy format string for this tcp proxy's access log
+	// entries, which are written to the HTTPS listener's access log. It
+	// must end in a newline.
+	// If not supplied, the format configured for Contour is used.
+	// +optional
+	Format string `json:"format,omitempty"`
 }
 
 // TCPProxyInclude describes a target HTTPProxy document which contains the TCPProxy details.
@@ -1008,6 +1502,16 @@ type Service struct {
 	// Name is the name of Kubernetes service to proxy traffic.
 	// Names defined here will be used to look up corresponding endpoints which contain the ips to route.
 	Name string `json:"name"`
+	// Kind is the kind of the Kubernetes resource named by Name.
+	// Service refers to a Service, and ServiceImport refers to a
+	// multi-cluster ServiceImport (multicluster.x-k8s.io) whose endpoints
+	// are read from the EndpointSlices derived for it. ServiceImports
+	// require the multiClusterServices feature flag.
+	// Defaults to Service.
+	//
+	// +kubebuilder:validation:Enum=Service;ServiceImport
+	// +optional
+	Kind string `json:"kind,omitempty"`
 	// Port (defined as Integer) to proxy traffic to since a service can have multiple defined.
 	//
 	// +required
@@ -1451,6 +1955,60 @@ type HTTPProxyStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []DetailedCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// +optional
+	// TrafficShifts reports the progress of the traffic shift policies
+	// of the routes of the HTTPProxy.
+	TrafficShifts []TrafficShiftStatus `json:"trafficShifts,omitempty"`
+}
+
+// TrafficShiftPhase is the phase of a traffic shift.
+type TrafficShiftPhase string
+
+const (
+	// TrafficShiftPhaseProgressing means the steps of the
+	// shift are being applied.
+	TrafficShiftPhaseProgressing TrafficShiftPhase = "Progressing"
+
+	// TrafficShiftPhaseSucceeded means the last step of the
+	// shift has lasted for its interval.
+	TrafficShiftPhaseSucceeded TrafficShiftPhase = "Succeeded"
+
+	// TrafficShiftPhaseAborted means the error rate of the canary
+	// exceeded the threshold, and all traffic is sent to the
+	// stable service.
+	TrafficShiftPhaseAborted TrafficShiftPhase = "Aborted"
+)
+
+// TrafficShiftStatus reports the progress of a traffic shift.
+type TrafficShiftStatus struct {
+	// Stable is the name of the stable service of the shift.
+	Stable string `json:"stable"`
+
+	// Canary is the name of the canary service of the shift.
+	Canary string `json:"canary"`
+
+	// PolicyHash identifies the traffic shift policy the shift
+	// follows. When the policy changes, the shift starts again
+	// from its first step.
+	// +optional
+	PolicyHash string `json:"policyHash,omitempty"`
+
+	// Step is the index of the current step of the shift.
+	Step int `json:"step"`
+
+	// Weight is the percentage of traffic sent to the canary.
+	Weight uint32 `json:"weight"`
+
+	// Phase is the phase of the shift.
+	Phase TrafficShiftPhase `json:"phase"`
+
+	// LastTransitionTime is when the current step was applied,
+	// or when the shift succeeded or was aborted.
+	LastTransitionTime meta_v1.Time `json:"lastTransitionTime"`
+
+	// Message is a human readable description of the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
@@ -1516,5 +2074,5 @@ type SlowStartPolicy struct {
 	MinimumWeightPercent uint32 `json:"minWeightPercent"`
 }
 
-// +kubebuilder:validation:Enum=grpcroutes;tlsroutes;extensionservices;backendtlspolicies
+// +kubebuilder:validation:Enum=grpcroutes;tlsroutes;extensionservices;backendtlspolicies;xbackendtrafficpolicies;xlistenersets
 type Feature string
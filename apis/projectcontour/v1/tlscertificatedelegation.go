@@ -24,29 +24,50 @@ type TLSCertificateDelegationSpec struct {
 
 // CertificateDelegation maps the authority to reference a secret
 // in the current namespace to a set of namespaces.
+//
+// Exactly one of SecretName or SecretSelector must be specified, and
+// at least one of TargetNamespaces or TargetNamespaceSelector.
 type CertificateDelegation struct {
-	// required, the name of a secret in the current namespace.
-	SecretName string `json:"secretName"`
+	// The name of a secret in the current namespace. The name may be
+	// a shell-style glob pattern, e.g. "wildcard-*", in which case all
+	// matching secrets are delegated.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// SecretSelector selects the secrets in the current namespace
+	// to delegate by their labels.
+	// +optional
+	SecretSelector *meta_v1.LabelSelector `json:"secretSelector,omitempty"`
 
-	// required, the namespaces the authority to reference the
+	// The namespaces the authority to reference the
 	// secret will be delegated to.
-	// If TargetNamespaces is nil or empty, the CertificateDelegation'
-	// is ignored. If the TargetNamespace list contains the character, "*"
+	// If the TargetNamespace list contains the character, "*"
 	// the secret will be delegated to all namespaces.
-	TargetNamespaces []string `json:"targetNamespaces"`
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+
+	// TargetNamespaceSelector selects the namespaces the authority
+	// to reference the secret will be delegated to by their labels,
+	// in addition to those listed in TargetNamespaces.
+	// +optional
+	TargetNamespaceSelector *meta_v1.LabelSelector `json:"targetNamespaceSelector,omitempty"`
 }
 
 // TLSCertificateDelegationStatus allows for the status of the delegation
 // to be presented to the user.
 type TLSCertificateDelegationStatus struct {
 	// +optional
-	// Conditions contains information about the current status of the HTTPProxy,
+	// Conditions contains information about the current status of the TLSCertificateDelegation,
 	// in an upstream-friendly container.
 	//
-	// Contour will update a single condition, `Valid`, that is in normal-true polarity.
-	// That is, when `currentStatus` is `valid`, the `Valid` condition will be `status: true`,
+	// Contour will update a `Valid` condition, that is in normal-true polarity.
+	// That is, when the delegations are valid, the `Valid` condition will be `status: true`,
 	// and vice versa.
 	//
+	// Contour will also update a `Consumed` condition, which is `status: true` when
+	// at least one HTTPProxy in another namespace references a delegated secret.
+	// Its message lists the HTTPProxies that reference each delegated secret.
+	//
 	// Contour will leave untouched any other Conditions set in this block,
 	// in case some other controller wants to add a Condition.
 	//
@@ -21,9 +21,25 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorizationHTTPService) DeepCopyInto(out *AuthorizationHTTPService) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationHTTPService.
+func (in *AuthorizationHTTPService) DeepCopy() *AuthorizationHTTPService {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorizationHTTPService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthorizationPolicy) DeepCopyInto(out *AuthorizationPolicy) {
 	*out = *in
@@ -60,6 +76,36 @@ func (in *AuthorizationServer) DeepCopyInto(out *AuthorizationServer) {
 		*out = new(AuthorizationServerBufferSettings)
 		**out = **in
 	}
+	if in.HTTPService != nil {
+		in, out := &in.HTTPService, &out.HTTPService
+		*out = new(AuthorizationHTTPService)
+		**out = **in
+	}
+	if in.AllowedRequestHeaders != nil {
+		in, out := &in.AllowedRequestHeaders, &out.AllowedRequestHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedUpstreamHeaders != nil {
+		in, out := &in.AllowedUpstreamHeaders, &out.AllowedUpstreamHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedClientHeaders != nil {
+		in, out := &in.AllowedClientHeaders, &out.AllowedClientHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MetadataNamespaces != nil {
+		in, out := &in.MetadataNamespaces, &out.MetadataNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RouteMetadataNamespaces != nil {
+		in, out := &in.RouteMetadataNamespaces, &out.RouteMetadataNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationServer.
@@ -125,11 +171,21 @@ func (in *CORSPolicy) DeepCopy() *CORSPolicy {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateDelegation) DeepCopyInto(out *CertificateDelegation) {
 	*out = *in
+	if in.SecretSelector != nil {
+		in, out := &in.SecretSelector, &out.SecretSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TargetNamespaces != nil {
 		in, out := &in.TargetNamespaces, &out.TargetNamespaces
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TargetNamespaceSelector != nil {
+		in, out := &in.TargetNamespaceSelector, &out.TargetNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateDelegation.
@@ -222,6 +278,21 @@ func (in *CookieRewritePolicy) DeepCopy() *CookieRewritePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DestinationClusterDescriptor) DeepCopyInto(out *DestinationClusterDescriptor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationClusterDescriptor.
+func (in *DestinationClusterDescriptor) DeepCopy() *DestinationClusterDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationClusterDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DetailedCondition) DeepCopyInto(out *DetailedCondition) {
 	*out = *in
@@ -488,6 +559,13 @@ func (in *HTTPProxyStatus) DeepCopyInto(out *HTTPProxyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TrafficShifts != nil {
+		in, out := &in.TrafficShifts, &out.TrafficShifts
+		*out = make([]TrafficShiftStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProxyStatus.
@@ -667,6 +745,21 @@ func (in *Include) DeepCopy() *Include {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTClaimToHeader) DeepCopyInto(out *JWTClaimToHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTClaimToHeader.
+func (in *JWTClaimToHeader) DeepCopy() *JWTClaimToHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTClaimToHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JWTProvider) DeepCopyInto(out *JWTProvider) {
 	*out = *in
@@ -675,7 +768,21 @@ func (in *JWTProvider) DeepCopyInto(out *JWTProvider) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	in.RemoteJWKS.DeepCopyInto(&out.RemoteJWKS)
+	if in.RemoteJWKS != nil {
+		in, out := &in.RemoteJWKS, &out.RemoteJWKS
+		*out = new(RemoteJWKS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalJWKS != nil {
+		in, out := &in.LocalJWKS, &out.LocalJWKS
+		*out = new(LocalJWKS)
+		**out = **in
+	}
+	if in.ClaimToHeaders != nil {
+		in, out := &in.ClaimToHeaders, &out.ClaimToHeaders
+		*out = make([]JWTClaimToHeader, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTProvider.
@@ -688,9 +795,36 @@ func (in *JWTProvider) DeepCopy() *JWTProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTRequiredClaim) DeepCopyInto(out *JWTRequiredClaim) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTRequiredClaim.
+func (in *JWTRequiredClaim) DeepCopy() *JWTRequiredClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTRequiredClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JWTVerificationPolicy) DeepCopyInto(out *JWTVerificationPolicy) {
 	*out = *in
+	if in.RequiredClaims != nil {
+		in, out := &in.RequiredClaims, &out.RequiredClaims
+		*out = make([]JWTRequiredClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTVerificationPolicy.
@@ -725,6 +859,59 @@ func (in *LoadBalancerPolicy) DeepCopy() *LoadBalancerPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalJWKS) DeepCopyInto(out *LocalJWKS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalJWKS.
+func (in *LocalJWKS) DeepCopy() *LocalJWKS {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalJWKS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalRateLimitDescriptor) DeepCopyInto(out *LocalRateLimitDescriptor) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]LocalRateLimitDescriptorEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalRateLimitDescriptor.
+func (in *LocalRateLimitDescriptor) DeepCopy() *LocalRateLimitDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalRateLimitDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalRateLimitDescriptorEntry) DeepCopyInto(out *LocalRateLimitDescriptorEntry) {
+	*out = *in
+	in.RateLimitDescriptorEntry.DeepCopyInto(&out.RateLimitDescriptorEntry)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalRateLimitDescriptorEntry.
+func (in *LocalRateLimitDescriptorEntry) DeepCopy() *LocalRateLimitDescriptorEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalRateLimitDescriptorEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalRateLimitPolicy) DeepCopyInto(out *LocalRateLimitPolicy) {
 	*out = *in
@@ -733,6 +920,13 @@ func (in *LocalRateLimitPolicy) DeepCopyInto(out *LocalRateLimitPolicy) {
 		*out = make([]HeaderValue, len(*in))
 		copy(*out, *in)
 	}
+	if in.Descriptors != nil {
+		in, out := &in.Descriptors, &out.Descriptors
+		*out = make([]LocalRateLimitDescriptor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalRateLimitPolicy.
@@ -745,6 +939,21 @@ func (in *LocalRateLimitPolicy) DeepCopy() *LocalRateLimitPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaskedRemoteAddressDescriptor) DeepCopyInto(out *MaskedRemoteAddressDescriptor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaskedRemoteAddressDescriptor.
+func (in *MaskedRemoteAddressDescriptor) DeepCopy() *MaskedRemoteAddressDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(MaskedRemoteAddressDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchCondition) DeepCopyInto(out *MatchCondition) {
 	*out = *in
@@ -770,6 +979,26 @@ func (in *MatchCondition) DeepCopy() *MatchCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataDescriptor) DeepCopyInto(out *MetadataDescriptor) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataDescriptor.
+func (in *MetadataDescriptor) DeepCopy() *MetadataDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PathRewritePolicy) DeepCopyInto(out *PathRewritePolicy) {
 	*out = *in
@@ -790,6 +1019,21 @@ func (in *PathRewritePolicy) DeepCopy() *PathRewritePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryParameterDescriptor) DeepCopyInto(out *QueryParameterDescriptor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryParameterDescriptor.
+func (in *QueryParameterDescriptor) DeepCopy() *QueryParameterDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryParameterDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QueryParameterHashOptions) DeepCopyInto(out *QueryParameterHashOptions) {
 	*out = *in
@@ -820,6 +1064,117 @@ func (in *QueryParameterMatchCondition) DeepCopy() *QueryParameterMatchCondition
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACClientCertificateMatch) DeepCopyInto(out *RBACClientCertificateMatch) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACClientCertificateMatch.
+func (in *RBACClientCertificateMatch) DeepCopy() *RBACClientCertificateMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACClientCertificateMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACPathMatch) DeepCopyInto(out *RBACPathMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACPathMatch.
+func (in *RBACPathMatch) DeepCopy() *RBACPathMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACPathMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACPolicy) DeepCopyInto(out *RBACPolicy) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]RBACRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]RBACRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACPolicy.
+func (in *RBACPolicy) DeepCopy() *RBACPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACRule) DeepCopyInto(out *RBACRule) {
+	*out = *in
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]RBACPathMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HeaderMatchCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(RBACClientCertificateMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JWTClaims != nil {
+		in, out := &in.JWTClaims, &out.JWTClaims
+		*out = make([]JWTRequiredClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SourceIPs != nil {
+		in, out := &in.SourceIPs, &out.SourceIPs
+		*out = make([]IPFilterPolicy, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACRule.
+func (in *RBACRule) DeepCopy() *RBACRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RateLimitDescriptor) DeepCopyInto(out *RateLimitDescriptor) {
 	*out = *in
@@ -865,6 +1220,26 @@ func (in *RateLimitDescriptorEntry) DeepCopyInto(out *RateLimitDescriptorEntry)
 		*out = new(RemoteAddressDescriptor)
 		**out = **in
 	}
+	if in.MaskedRemoteAddress != nil {
+		in, out := &in.MaskedRemoteAddress, &out.MaskedRemoteAddress
+		*out = new(MaskedRemoteAddressDescriptor)
+		**out = **in
+	}
+	if in.QueryParameter != nil {
+		in, out := &in.QueryParameter, &out.QueryParameter
+		*out = new(QueryParameterDescriptor)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(MetadataDescriptor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DestinationCluster != nil {
+		in, out := &in.DestinationCluster, &out.DestinationCluster
+		*out = new(DestinationClusterDescriptor)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitDescriptorEntry.
@@ -1124,7 +1499,7 @@ func (in *Route) DeepCopyInto(out *Route) {
 	if in.JWTVerificationPolicy != nil {
 		in, out := &in.JWTVerificationPolicy, &out.JWTVerificationPolicy
 		*out = new(JWTVerificationPolicy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.IPAllowFilterPolicy != nil {
 		in, out := &in.IPAllowFilterPolicy, &out.IPAllowFilterPolicy
@@ -1136,6 +1511,16 @@ func (in *Route) DeepCopyInto(out *Route) {
 		*out = make([]IPFilterPolicy, len(*in))
 		copy(*out, *in)
 	}
+	if in.AuthorizationPolicy != nil {
+		in, out := &in.AuthorizationPolicy, &out.AuthorizationPolicy
+		*out = new(RBACPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrafficShiftPolicy != nil {
+		in, out := &in.TrafficShiftPolicy, &out.TrafficShiftPolicy
+		*out = new(TrafficShiftPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
@@ -1270,6 +1655,11 @@ func (in *TCPProxy) DeepCopyInto(out *TCPProxy) {
 		*out = new(TCPHealthCheckPolicy)
 		**out = **in
 	}
+	if in.AccessLog != nil {
+		in, out := &in.AccessLog, &out.AccessLog
+		*out = new(TCPProxyAccessLog)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPProxy.
@@ -1282,6 +1672,21 @@ func (in *TCPProxy) DeepCopy() *TCPProxy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPProxyAccessLog) DeepCopyInto(out *TCPProxyAccessLog) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPProxyAccessLog.
+func (in *TCPProxyAccessLog) DeepCopy() *TCPProxyAccessLog {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPProxyAccessLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPProxyInclude) DeepCopyInto(out *TCPProxyInclude) {
 	*out = *in
@@ -1300,6 +1705,11 @@ func (in *TCPProxyInclude) DeepCopy() *TCPProxyInclude {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLS) DeepCopyInto(out *TLS) {
 	*out = *in
+	if in.AdditionalSecretNames != nil {
+		in, out := &in.AdditionalSecretNames, &out.AdditionalSecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ClientValidation != nil {
 		in, out := &in.ClientValidation, &out.ClientValidation
 		*out = new(DownstreamValidation)
@@ -1435,6 +1845,67 @@ func (in *TimeoutPolicy) DeepCopy() *TimeoutPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficShiftErrorRate) DeepCopyInto(out *TrafficShiftErrorRate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficShiftErrorRate.
+func (in *TrafficShiftErrorRate) DeepCopy() *TrafficShiftErrorRate {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficShiftErrorRate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficShiftPolicy) DeepCopyInto(out *TrafficShiftPolicy) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]uint32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(HeaderMatchCondition)
+		**out = **in
+	}
+	if in.ErrorRate != nil {
+		in, out := &in.ErrorRate, &out.ErrorRate
+		*out = new(TrafficShiftErrorRate)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficShiftPolicy.
+func (in *TrafficShiftPolicy) DeepCopy() *TrafficShiftPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficShiftPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficShiftStatus) DeepCopyInto(out *TrafficShiftStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficShiftStatus.
+func (in *TrafficShiftStatus) DeepCopy() *TrafficShiftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficShiftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpstreamValidation) DeepCopyInto(out *UpstreamValidation) {
 	*out = *in
@@ -1495,6 +1966,11 @@ func (in *VirtualHost) DeepCopyInto(out *VirtualHost) {
 		*out = make([]IPFilterPolicy, len(*in))
 		copy(*out, *in)
 	}
+	if in.AuthorizationPolicy != nil {
+		in, out := &in.AuthorizationPolicy, &out.AuthorizationPolicy
+		*out = new(RBACPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualHost.
@@ -85,11 +85,27 @@ type ContourConfigurationSpec struct {
 	// Tracing defines properties for exporting trace data to OpenTelemetry.
 	Tracing *TracingConfig `json:"tracing,omitempty"`
 
+	// TLSCertificates defines how Contour treats the expiry of TLS
+	// certificates referenced by HTTPProxies, Ingresses and Gateway Listeners.
+	// +optional
+	TLSCertificates *TLSCertificatesConfig `json:"tlsCertificates,omitempty"`
+
 	// FeatureFlags defines toggle to enable new contour features.
 	// Available toggles are:
 	// useEndpointSlices - Configures contour to fetch endpoint data
 	// from k8s endpoint slices. defaults to true,
 	// If false then reads endpoint data from the k8s endpoints.
+	// incrementalDAGRebuild - Configures contour to reuse the parts
+	// of the DAG computed from root HTTPProxies whose dependencies
+	// did not change. defaults to false.
+	// multiClusterServices - Configures contour to watch multi-cluster
+	// ServiceImports, so that HTTPProxy services and Gateway API
+	// backendRefs can reference them. Requires useEndpointSlices.
+	// defaults to false.
+	// routeStats - Configures Envoy to emit route-level, virtual
+	// cluster, TCP proxy and upstream cluster statistics named after the
+	// HTTPProxy, Ingress or Gateway API route that defines each route or
+	// TCP proxy. defaults to false.
 	FeatureFlags FeatureFlags `json:"featureFlags,omitempty"`
 }
 
@@ -142,7 +158,47 @@ type XDSServerConfig struct {
 type GatewayConfig struct {
 	// GatewayRef defines the specific Gateway that this Contour
 	// instance corresponds to.
+	//
+	// Exactly one of gatewayRef or gateways must be specified.
+	// +optional
+	GatewayRef NamespacedName `json:"gatewayRef,omitempty"`
+
+	// Gateways defines several Gateways, of the same GatewayClass,
+	// that this Contour instance corresponds to. Each Gateway is
+	// served by its own set of Envoys, which receive only the
+	// Gateway's listeners and routes.
+	//
+	// The Envoys of a Gateway are identified by their node cluster,
+	// set with Envoy's --service-cluster flag, which must be the
+	// Gateway's "namespace/name". The names of the Gateway's Envoy
+	// listeners and route configurations are prefixed with the same
+	// "namespace/name/".
+	//
+	// Exactly one of gatewayRef or gateways must be specified.
+	// +optional
+	Gateways []ServedGateway `json:"gateways,omitempty"`
+
+	// DataPlaneReadyQuorum is the number of Envoys that must acknowledge
+	// the configuration built from the Gateway's current generation before
+	// the Gateway's DataPlaneReady condition is set to true. Only Envoys
+	// connected to the Contour instance that holds the leader election
+	// lease are counted.
+	//
+	// If unset, the DataPlaneReady condition is not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DataPlaneReadyQuorum *int32 `json:"dataPlaneReadyQuorum,omitempty"`
+}
+
+// ServedGateway is one of several Gateways served by a Contour instance.
+type ServedGateway struct {
+	// GatewayRef defines the Gateway.
 	GatewayRef NamespacedName `json:"gatewayRef"`
+
+	// EnvoyService defines the Service of the Envoys serving the
+	// Gateway. Its load balancer addresses are written to the status
+	// of the Gateway.
+	EnvoyService NamespacedName `json:"envoyService"`
 }
 
 // TLS holds TLS file config details.
@@ -502,8 +558,41 @@ type EnvoyTLS struct {
 	// Note: This list is a superset of what is valid for stock Envoy builds and those using BoringSSL FIPS.
 	// +optional
 	CipherSuites []string `json:"cipherSuites,omitempty"`
+
+	// OCSPStaplePolicy defines how Envoy listeners use the OCSP
+	// responses stapled to serving certificates. Responses are read
+	// from the "tls.ocsp-staple" key of the TLS Secret.
+	//
+	// Values: `lenient` (default), `strict`, `must-staple`.
+	//
+	// When `lenient`, a certificate whose response is missing or
+	// expired is served without a staple.
+	// When `strict`, a certificate whose response has expired is not
+	// served; a certificate without a response is served without a staple.
+	// When `must-staple`, every serving certificate must have a
+	// current response, and Secrets without one are treated as invalid.
+	//
+	// This field is ignored for upstream TLS.
+	// +kubebuilder:validation:Enum=lenient;strict;must-staple
+	// +optional
+	OCSPStaplePolicy OCSPStaplePolicy `json:"ocspStaplePolicy,omitempty"`
 }
 
+// OCSPStaplePolicy defines how OCSP responses stapled to serving
+// certificates are used.
+type OCSPStaplePolicy string
+
+const (
+	// Serve the OCSP response if it is present and valid, otherwise
+	// serve the certificate without a staple.
+	// This is the default value.
+	LenientOCSPStaplePolicy OCSPStaplePolicy = "lenient"
+	// Do not serve a certificate whose OCSP response has expired.
+	StrictOCSPStaplePolicy OCSPStaplePolicy = "strict"
+	// Require a valid OCSP response for every serving certificate.
+	MustStapleOCSPStaplePolicy OCSPStaplePolicy = "must-staple"
+)
+
 // EnvoyListener defines parameters for an Envoy Listener.
 type EnvoyListener struct {
 	// Defines an Envoy Listener Address.
@@ -721,6 +810,29 @@ type HTTPProxyConfig struct {
 	FallbackCertificate *NamespacedName `json:"fallbackCertificate,omitempty"`
 }
 
+// TLSCertificatesConfig defines how Contour treats the expiry of TLS certificates.
+type TLSCertificatesConfig struct {
+	// ExpiryWarningPeriod defines how long before a serving certificate
+	// expires Contour adds a warning condition to the HTTPProxies and
+	// Gateway Listeners referencing it.
+	// Expired certificates always get a warning condition.
+	// The value must be a duration string, for example "336h".
+	//
+	// Contour's default is 0s, which disables the warning for
+	// certificates that have not yet expired.
+	// +optional
+	ExpiryWarningPeriod *string `json:"expiryWarningPeriod,omitempty"`
+
+	// RejectExpired defines whether Contour refuses to use expired
+	// serving certificates. When true, virtual hosts and Gateway Listeners
+	// referencing an expired certificate are treated as having an invalid
+	// TLS Secret.
+	//
+	// Contour's default is false.
+	// +optional
+	RejectExpired *bool `json:"rejectExpired,omitempty"`
+}
+
 // NetworkParameters hold various configurable network values.
 type NetworkParameters struct {
 	// XffNumTrustedHops defines the number of additional ingress proxy hops from the
@@ -879,6 +991,9 @@ type ContourConfigurationStatus struct {
 	// Conditions contains the current status of the Contour resource.
 	//
 	// Contour will update a single condition, `Valid`, that is in normal-true polarity.
+	// When the configuration has been changed in a way that only takes effect once
+	// Contour is restarted, the `Valid` condition has a `RestartRequired` warning
+	// listing the changed fields.
 	//
 	// Contour will not modify any other Conditions set in this block,
 	// in case some other controller wants to add a Condition.
@@ -891,6 +1006,11 @@ type ContourConfigurationStatus struct {
 	Conditions []contour_v1.DetailedCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
+// ConditionTypeRestartRequired is the type of the warning added to the `Valid`
+// condition of a ContourConfiguration when some of the changed fields only take
+// effect once Contour is restarted.
+const ConditionTypeRestartRequired = "RestartRequired"
+
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
@@ -17,14 +17,23 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
-const featureFlagUseEndpointSlices string = "useEndpointSlices"
+const (
+	featureFlagUseEndpointSlices     string = "useEndpointSlices"
+	featureFlagIncrementalDAGRebuild string = "incrementalDAGRebuild"
+	featureFlagMultiClusterServices  string = "multiClusterServices"
+	featureFlagRouteStats            string = "routeStats"
+)
 
 var featureFlagsMap = map[string]struct{}{
-	featureFlagUseEndpointSlices: {},
+	featureFlagUseEndpointSlices:     {},
+	featureFlagIncrementalDAGRebuild: {},
+	featureFlagMultiClusterServices:  {},
+	featureFlagRouteStats:            {},
 }
 
 // Validate configuration that is not already covered by CRD validation.
@@ -46,6 +55,9 @@ func (c *ContourConfigurationSpec) Validate() error {
 	if c.Tracing != nil {
 		validateFuncs = append(validateFuncs, c.Tracing.Validate)
 	}
+	if c.TLSCertificates != nil {
+		validateFuncs = append(validateFuncs, c.TLSCertificates.Validate)
+	}
 
 	for _, validate := range validateFuncs {
 		if err := validate(); err != nil {
@@ -97,6 +109,20 @@ func (t *TracingConfig) Validate() error {
 	return nil
 }
 
+func (t *TLSCertificatesConfig) Validate() error {
+	if t.ExpiryWarningPeriod != nil {
+		period, err := time.ParseDuration(*t.ExpiryWarningPeriod)
+		if err != nil {
+			return fmt.Errorf("invalid TLS certificate expiry warning period: %v", err)
+		}
+		if period < 0 {
+			return fmt.Errorf("invalid TLS certificate expiry warning period: must not be negative")
+		}
+	}
+
+	return nil
+}
+
 func (d ClusterDNSFamilyType) Validate() error {
 	switch d {
 	case AutoClusterDNSFamily, IPv4ClusterDNSFamily, IPv6ClusterDNSFamily, AllClusterDNSFamily:
@@ -209,6 +235,13 @@ func (e *EnvoyTLS) Validate() error {
 	if len(invalidCipherSuites) > 0 {
 		return fmt.Errorf("invalid cipher suites %q", invalidCipherSuites)
 	}
+
+	switch e.OCSPStaplePolicy {
+	case "", LenientOCSPStaplePolicy, StrictOCSPStaplePolicy, MustStapleOCSPStaplePolicy:
+	default:
+		return fmt.Errorf("invalid OCSP staple policy %q", e.OCSPStaplePolicy)
+	}
+
 	return nil
 }
 
@@ -255,12 +288,67 @@ func (f FeatureFlags) IsEndpointSliceEnabled() bool {
 	return true
 }
 
-// Validate ensures that GatewayRef namespace/name is specified.
+func (f FeatureFlags) IsIncrementalDAGRebuildEnabled() bool {
+	// only when the flag: 'incrementalDAGRebuild' or 'incrementalDAGRebuild=true' exists, return true
+	for _, flag := range f {
+		fields := strings.Split(flag, "=")
+		if fields[0] != featureFlagIncrementalDAGRebuild {
+			continue
+		}
+		if len(fields) == 1 || strings.ToLower(fields[1]) == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+func (f FeatureFlags) IsMultiClusterServicesEnabled() bool {
+	// only when the flag: 'multiClusterServices' or 'multiClusterServices=true' exists, return true
+	for _, flag := range f {
+		fields := strings.Split(flag, "=")
+		if fields[0] != featureFlagMultiClusterServices {
+			continue
+		}
+		if len(fields) == 1 || strings.ToLower(fields[1]) == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate ensures that either GatewayRef namespace/name, or the namespace/name
+// of each of the Gateways and their Envoy Services, is specified.
 func (g *GatewayConfig) Validate() error {
-	if g != nil && (g.GatewayRef.Namespace == "" || g.GatewayRef.Name == "") {
+	if g == nil {
+		return nil
+	}
+
+	if len(g.Gateways) > 0 {
+		if g.GatewayRef != (NamespacedName{}) {
+			return fmt.Errorf("invalid gateway configuration: gateway ref and gateways cannot both be specified")
+		}
+
+		seen := map[NamespacedName]bool{}
+		for _, gw := range g.Gateways {
+			if gw.GatewayRef.Namespace == "" || gw.GatewayRef.Name == "" {
+				return fmt.Errorf("invalid gateway configuration: gateways gateway ref namespace and name must be specified")
+			}
+			if gw.EnvoyService.Namespace == "" || gw.EnvoyService.Name == "" {
+				return fmt.Errorf("invalid gateway configuration: gateways envoy service namespace and name must be specified")
+			}
+			if seen[gw.GatewayRef] {
+				return fmt.Errorf("invalid gateway configuration: gateway %s/%s specified more than once", gw.GatewayRef.Namespace, gw.GatewayRef.Name)
+			}
+			seen[gw.GatewayRef] = true
+		}
+	} else if g.GatewayRef.Namespace == "" || g.GatewayRef.Name == "" {
 		return fmt.Errorf("invalid gateway configuration: gateway ref namespace and name must be specified")
 	}
 
+	if g.DataPlaneReadyQuorum != nil && *g.DataPlaneReadyQuorum < 1 {
+		return fmt.Errorf("invalid gateway configuration: data plane ready quorum must be at least 1")
+	}
+
 	return nil
 }
 
@@ -330,3 +418,17 @@ func endpointsInConfict(health *HealthConfig, metrics *MetricsConfig) error {
 	}
 	return nil
 }
+
+func (f FeatureFlags) IsRouteStatsEnabled() bool {
+	// only when the flag: 'routeStats' or 'routeStats=true' exists, return true
+	for _, flag := range f {
+		fields := strings.Split(flag, "=")
+		if fields[0] != featureFlagRouteStats {
+			continue
+		}
+		if len(fields) == 1 || strings.ToLower(fields[1]) == "true" {
+			return true
+		}
+	}
+	return false
+}
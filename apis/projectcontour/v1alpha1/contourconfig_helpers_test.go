@@ -162,6 +162,19 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 			"ECDHE-ECDSA-AES128-GCM-SHA256",
 		}
 		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.TLS.CipherSuites = nil
+		c.Envoy.Listener.TLS.OCSPStaplePolicy = contour_v1alpha1.LenientOCSPStaplePolicy
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.TLS.OCSPStaplePolicy = contour_v1alpha1.StrictOCSPStaplePolicy
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.TLS.OCSPStaplePolicy = contour_v1alpha1.MustStapleOCSPStaplePolicy
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.TLS.OCSPStaplePolicy = "always"
+		require.Error(t, c.Validate())
 	})
 
 	t.Run("gateway validation", func(t *testing.T) {
@@ -179,6 +192,46 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 		// empty name is not allowed
 		c.Gateway.GatewayRef = contour_v1alpha1.NamespacedName{Namespace: "ns"}
 		require.Error(t, c.Validate())
+
+		c.Gateway.GatewayRef = contour_v1alpha1.NamespacedName{Namespace: "ns", Name: "name"}
+		c.Gateway.DataPlaneReadyQuorum = ptr.To(int32(2))
+		require.NoError(t, c.Validate())
+
+		// quorum must be positive
+		c.Gateway.DataPlaneReadyQuorum = ptr.To(int32(0))
+		require.Error(t, c.Validate())
+
+		c.Gateway.DataPlaneReadyQuorum = nil
+		c.Gateway.GatewayRef = contour_v1alpha1.NamespacedName{}
+		c.Gateway.Gateways = []contour_v1alpha1.ServedGateway{
+			{
+				GatewayRef:   contour_v1alpha1.NamespacedName{Namespace: "ns", Name: "gateway-1"},
+				EnvoyService: contour_v1alpha1.NamespacedName{Namespace: "ns", Name: "envoy-1"},
+			},
+			{
+				GatewayRef:   contour_v1alpha1.NamespacedName{Namespace: "ns", Name: "gateway-2"},
+				EnvoyService: contour_v1alpha1.NamespacedName{Namespace: "ns", Name: "envoy-2"},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		// gatewayRef and gateways are mutually exclusive
+		c.Gateway.GatewayRef = contour_v1alpha1.NamespacedName{Namespace: "ns", Name: "name"}
+		require.Error(t, c.Validate())
+		c.Gateway.GatewayRef = contour_v1alpha1.NamespacedName{}
+
+		// gateways must be unique
+		c.Gateway.Gateways[1].GatewayRef.Name = "gateway-1"
+		require.Error(t, c.Validate())
+
+		// empty gateway name is not allowed
+		c.Gateway.Gateways[1].GatewayRef.Name = ""
+		require.Error(t, c.Validate())
+		c.Gateway.Gateways[1].GatewayRef.Name = "gateway-2"
+
+		// empty envoy service namespace is not allowed
+		c.Gateway.Gateways[1].EnvoyService.Namespace = ""
+		require.Error(t, c.Validate())
 	})
 
 	t.Run("tracing validation", func(t *testing.T) {
@@ -233,6 +286,22 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 		c.Tracing.CustomTags = customTags
 		require.Error(t, c.Validate())
 	})
+
+	t.Run("tls certificates validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			TLSCertificates: &contour_v1alpha1.TLSCertificatesConfig{},
+		}
+		require.NoError(t, c.Validate())
+
+		c.TLSCertificates.ExpiryWarningPeriod = ptr.To("336h")
+		require.NoError(t, c.Validate())
+
+		c.TLSCertificates.ExpiryWarningPeriod = ptr.To("two weeks")
+		require.Error(t, c.Validate())
+
+		c.TLSCertificates.ExpiryWarningPeriod = ptr.To("-1h")
+		require.Error(t, c.Validate())
+	})
 }
 
 func TestSanitizeCipherSuites(t *testing.T) {
@@ -413,3 +482,123 @@ func TestFeatureFlagsIsEndpointSliceEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestFeatureFlagsIsIncrementalDAGRebuildEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    contour_v1alpha1.FeatureFlags
+		expected bool
+	}{
+		{
+			name:     "empty flags",
+			flags:    contour_v1alpha1.FeatureFlags{},
+			expected: false,
+		},
+		{
+			name:     "valid flag: no value",
+			flags:    contour_v1alpha1.FeatureFlags{"incrementalDAGRebuild"},
+			expected: true,
+		},
+		{
+			name:     "valid flag: true",
+			flags:    contour_v1alpha1.FeatureFlags{"incrementalDAGRebuild=TRUE"},
+			expected: true,
+		},
+		{
+			name:     "valid flag: false",
+			flags:    contour_v1alpha1.FeatureFlags{"incrementalDAGRebuild=false"},
+			expected: false,
+		},
+		{
+			name:     "multi-flags",
+			flags:    contour_v1alpha1.FeatureFlags{"useEndpointSlices=false", "incrementalDAGRebuild"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.flags.IsIncrementalDAGRebuildEnabled())
+		})
+	}
+}
+
+func TestFeatureFlagsIsMultiClusterServicesEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    contour_v1alpha1.FeatureFlags
+		expected bool
+	}{
+		{
+			name:     "empty flags",
+			flags:    contour_v1alpha1.FeatureFlags{},
+			expected: false,
+		},
+		{
+			name:     "valid flag: no value",
+			flags:    contour_v1alpha1.FeatureFlags{"multiClusterServices"},
+			expected: true,
+		},
+		{
+			name:     "valid flag: true",
+			flags:    contour_v1alpha1.FeatureFlags{"multiClusterServices=TRUE"},
+			expected: true,
+		},
+		{
+			name:     "valid flag: false",
+			flags:    contour_v1alpha1.FeatureFlags{"multiClusterServices=false"},
+			expected: false,
+		},
+		{
+			name:     "multi-flags",
+			flags:    contour_v1alpha1.FeatureFlags{"incrementalDAGRebuild", "multiClusterServices"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.flags.IsMultiClusterServicesEnabled())
+		})
+	}
+}
+
+func TestFeatureFlagsIsRouteStatsEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    contour_v1alpha1.FeatureFlags
+		expected bool
+	}{
+		{
+			name:     "empty flags",
+			flags:    contour_v1alpha1.FeatureFlags{},
+			expected: false,
+		},
+		{
+			name:     "valid flag: no value",
+			flags:    contour_v1alpha1.FeatureFlags{"routeStats"},
+			expected: true,
+		},
+		{
+			name:     "valid flag: true",
+			flags:    contour_v1alpha1.FeatureFlags{"routeStats=TRUE"},
+			expected: true,
+		},
+		{
+			name:     "valid flag: false",
+			flags:    contour_v1alpha1.FeatureFlags{"routeStats=false"},
+			expected: false,
+		},
+		{
+			name:     "multi-flags",
+			flags:    contour_v1alpha1.FeatureFlags{"multiClusterServices", "routeStats"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.flags.IsRouteStatsEnabled())
+		})
+	}
+}
@@ -78,10 +78,12 @@ type ExtensionServiceSpec struct {
 	UpstreamValidation *contour_v1.UpstreamValidation `json:"validation,omitempty"`
 
 	// Protocol may be used to specify (or override) the protocol used to reach this Service.
-	// Values may be h2 or h2c. If omitted, protocol-selection falls back on Service annotations.
+	// Values may be h2, h2c, tls or http/1.1. If omitted, protocol-selection falls back on Service annotations.
+	// The tls and http/1.1 values reach the Service over HTTP/1.1, with and without TLS,
+	// and can only be used by authorization servers that set httpService.
 	//
 	// +optional
-	// +kubebuilder:validation:Enum=h2;h2c
+	// +kubebuilder:validation:Enum=h2;h2c;tls;http/1.1
 	Protocol *string `json:"protocol,omitempty"`
 
 	// The policy for load balancing GRPC service requests. Note that the
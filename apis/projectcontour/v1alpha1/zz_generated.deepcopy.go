@@ -187,7 +187,7 @@ func (in *ContourConfigurationSpec) DeepCopyInto(out *ContourConfigurationSpec)
 	if in.Gateway != nil {
 		in, out := &in.Gateway, &out.Gateway
 		*out = new(GatewayConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.HTTPProxy != nil {
 		in, out := &in.HTTPProxy, &out.HTTPProxy
@@ -224,6 +224,11 @@ func (in *ContourConfigurationSpec) DeepCopyInto(out *ContourConfigurationSpec)
 		*out = new(TracingConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLSCertificates != nil {
+		in, out := &in.TLSCertificates, &out.TLSCertificates
+		*out = new(TLSCertificatesConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FeatureFlags != nil {
 		in, out := &in.FeatureFlags, &out.FeatureFlags
 		*out = make(FeatureFlags, len(*in))
@@ -930,6 +935,16 @@ func (in FeatureFlags) DeepCopy() FeatureFlags {
 func (in *GatewayConfig) DeepCopyInto(out *GatewayConfig) {
 	*out = *in
 	out.GatewayRef = in.GatewayRef
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]ServedGateway, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataPlaneReadyQuorum != nil {
+		in, out := &in.DataPlaneReadyQuorum, &out.DataPlaneReadyQuorum
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfig.
@@ -1231,6 +1246,23 @@ func (in *RateLimitServiceConfig) DeepCopy() *RateLimitServiceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServedGateway) DeepCopyInto(out *ServedGateway) {
+	*out = *in
+	out.GatewayRef = in.GatewayRef
+	out.EnvoyService = in.EnvoyService
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServedGateway.
+func (in *ServedGateway) DeepCopy() *ServedGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(ServedGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SocketOptions) DeepCopyInto(out *SocketOptions) {
 	*out = *in
@@ -1266,6 +1298,31 @@ func (in *TLS) DeepCopy() *TLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificatesConfig) DeepCopyInto(out *TLSCertificatesConfig) {
+	*out = *in
+	if in.ExpiryWarningPeriod != nil {
+		in, out := &in.ExpiryWarningPeriod, &out.ExpiryWarningPeriod
+		*out = new(string)
+		**out = **in
+	}
+	if in.RejectExpired != nil {
+		in, out := &in.RejectExpired, &out.RejectExpired
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificatesConfig.
+func (in *TLSCertificatesConfig) DeepCopy() *TLSCertificatesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificatesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TimeoutParameters) DeepCopyInto(out *TimeoutParameters) {
 	*out = *in
@@ -18,15 +18,20 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	core_v1 "k8s.io/api/core/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	"github.com/projectcontour/contour/internal/certgen"
+	"github.com/projectcontour/contour/internal/httpsvc"
 	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/metrics"
 	"github.com/projectcontour/contour/pkg/certs"
 )
 
@@ -37,14 +42,18 @@ func registerCertGen(app *kingpin.Application) (*kingpin.CmdClause, *certgenConf
 	certgenApp := app.Command("certgen", "Generate new TLS certs for bootstrapping gRPC over TLS.")
 	certgenApp.Arg("outputdir", "Directory to write output files into (default \"certs\").").Default("certs").StringVar(&certgenConfig.OutputDir)
 
-	// NOTE: --certificate-lifetime can be used to accept Duration string once certificate rotation is supported.
 	certgenApp.Flag("certificate-lifetime", "Generated certificate lifetime (in days).").Default(strconv.Itoa(certs.DefaultCertificateLifetime)).UintVar(&certgenConfig.Lifetime)
+	certgenApp.Flag("http-address", "Address the metrics HTTP endpoint will bind to when rotating periodically.").Default("0.0.0.0").StringVar(&certgenConfig.MetricsAddress)
+	certgenApp.Flag("http-port", "Port the metrics HTTP endpoint will bind to when rotating periodically.").Default("8000").IntVar(&certgenConfig.MetricsPort)
 	certgenApp.Flag("incluster", "Use in cluster configuration.").BoolVar(&certgenConfig.InCluster)
 	certgenApp.Flag("kube", "Apply the generated certs directly to the current Kubernetes cluster.").BoolVar(&certgenConfig.OutputKube)
 	certgenApp.Flag("kubeconfig", "Path to kubeconfig (if not in running inside a cluster).").Default(filepath.Join(os.Getenv("HOME"), ".kube", "config")).StringVar(&certgenConfig.KubeConfig)
 	certgenApp.Flag("namespace", "Kubernetes namespace, used for Kube objects.").Default(certs.DefaultNamespace).Envar("CONTOUR_NAMESPACE").StringVar(&certgenConfig.Namespace)
 	certgenApp.Flag("overwrite", "Overwrite existing files or Secrets.").BoolVar(&certgenConfig.Overwrite)
 	certgenApp.Flag("pem", "Render the generated certs as individual PEM files to the current directory.").BoolVar(&certgenConfig.OutputPEM)
+	certgenApp.Flag("renew-before", "Renew certificates expiring within this duration when rotating.").Default("720h").DurationVar(&certgenConfig.RenewBefore)
+	certgenApp.Flag("rotate", "Renew the existing Kubernetes Secrets from the existing CA before they expire, rather than generating new ones.").BoolVar(&certgenConfig.Rotate)
+	certgenApp.Flag("rotate-interval", "Keep running and check the Kubernetes Secrets for rotation at this interval. Zero checks once and exits.").Default("0s").DurationVar(&certgenConfig.RotateInterval)
 	certgenApp.Flag("secrets-format", "Specify how to format the generated Kubernetes Secrets.").Default("legacy").StringVar(&certgenConfig.Format)
 	certgenApp.Flag("secrets-name-suffix", "Specify a suffix to be appended to the generated Kubernetes secrets' names.").StringVar(&certgenConfig.NameSuffix)
 	certgenApp.Flag("yaml", "Render the generated certs as Kubernetes Secrets in YAML form to the current directory.").BoolVar(&certgenConfig.OutputYAML)
@@ -86,6 +95,25 @@ type certgenConfig struct {
 
 	// NameSuffix specifies the suffix to use for the generated Kubernetes secrets' names.
 	NameSuffix string
+
+	// Rotate means that the existing Kubernetes Secrets are read back and only
+	// renewed when they are about to expire.
+	Rotate bool
+
+	// RenewBefore is how long before expiry certificates are renewed when rotating.
+	RenewBefore time.Duration
+
+	// RotateInterval is how often the Kubernetes Secrets are checked for rotation.
+	// Zero means they are checked once.
+	RotateInterval time.Duration
+
+	// MetricsAddress is the address certificate expiry metrics are served on
+	// when rotating periodically.
+	MetricsAddress string
+
+	// MetricsPort is the port certificate expiry metrics are served on
+	// when rotating periodically.
+	MetricsPort int
 }
 
 // OutputCerts outputs the certs in certs as directed by config.
@@ -137,6 +165,11 @@ func OutputCerts(config *certgenConfig, kubeclient *kubernetes.Clientset, certs
 }
 
 func doCertgen(config *certgenConfig, log logrus.FieldLogger) {
+	if config.Rotate {
+		doCertgenRotate(config, log)
+		return
+	}
+
 	generatedCerts, err := certs.GenerateCerts(
 		&certs.Configuration{
 			Lifetime:  config.Lifetime,
@@ -155,3 +188,87 @@ func doCertgen(config *certgenConfig, log logrus.FieldLogger) {
 		log.WithError(oerr).Fatalf("failed output certificates")
 	}
 }
+
+// doCertgenRotate renews the certificates stored in Kubernetes Secrets
+// before they expire, either once or periodically.
+func doCertgenRotate(config *certgenConfig, log logrus.FieldLogger) {
+	if !config.OutputKube {
+		log.Fatal("--rotate requires --kube")
+	}
+
+	coreClient, err := k8s.NewCoreClient(config.KubeConfig, config.InCluster)
+	if err != nil {
+		log.WithError(err).Fatalf("failed to create Kubernetes client")
+	}
+
+	registry := prometheus.NewRegistry()
+	expiryMetrics := certgen.NewExpiryMetrics(registry)
+
+	rotate := func() error {
+		current, err := certgen.ReadSecretsKube(coreClient, config.Namespace, config.NameSuffix)
+		if err != nil {
+			return fmt.Errorf("failed to read certificates: %w", err)
+		}
+
+		rotated, action, err := certgen.Rotate(
+			&certs.Configuration{
+				Lifetime:  config.Lifetime,
+				Namespace: config.Namespace,
+			}, current, config.RenewBefore, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to rotate certificates: %w", err)
+		}
+
+		log.WithField("action", action).Info("checked certificates for rotation")
+		expiryMetrics.Set(rotated)
+
+		if action == certgen.RotationNone {
+			return nil
+		}
+
+		caSecret, errs := certgen.AsCASecret(config.Namespace, config.NameSuffix, rotated)
+		if len(errs) > 0 {
+			return utilerrors.NewAggregate(errs)
+		}
+		if err := certgen.WriteSecretsKube(coreClient, []*core_v1.Secret{caSecret}, certgen.Overwrite); err != nil {
+			return fmt.Errorf("failed to write CA to %q: %w", config.Namespace, err)
+		}
+
+		rotateConfig := *config
+		rotateConfig.Overwrite = true
+		rotateConfig.OutputPEM = false
+		rotateConfig.OutputYAML = false
+		return OutputCerts(&rotateConfig, coreClient, rotated)
+	}
+
+	if config.RotateInterval == 0 {
+		if err := rotate(); err != nil {
+			log.WithError(err).Fatal("failed to rotate certificates")
+		}
+		return
+	}
+
+	ctx := signals.SetupSignalHandler()
+
+	metricsvc := &httpsvc.Service{
+		Addr:        config.MetricsAddress,
+		Port:        config.MetricsPort,
+		FieldLogger: log.WithField("context", "metricsvc"),
+	}
+	metricsvc.ServeMux.Handle("/metrics", metrics.Handler(registry))
+	go metricsvc.Start(ctx) // nolint:errcheck
+
+	ticker := time.NewTicker(config.RotateInterval)
+	defer ticker.Stop()
+	for {
+		if err := rotate(); err != nil {
+			log.WithError(err).Error("failed to rotate certificates")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
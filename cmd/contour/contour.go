@@ -78,6 +78,9 @@ func main() {
 	gatewayProvisioner, gatewayProvisionerConfig := registerGatewayProvisioner(app)
 
 	serve, serveCtx := registerServe(app)
+
+	webhookCmd, webhookConfig := registerWebhook(app)
+
 	version := app.Command("version", "Build information for Contour.")
 
 	args := os.Args[1:]
@@ -184,6 +187,14 @@ func main() {
 		if err := serve.doServe(); err != nil {
 			log.WithError(err).Fatal("Contour server failed")
 		}
+	case webhookCmd.FullCommand():
+		if err := webhookConfig.serveCtx.Config.Validate(); err != nil {
+			log.WithError(err).Fatal("invalid configuration")
+		}
+
+		if err := runWebhook(log, webhookConfig); err != nil {
+			log.WithError(err).Fatal("webhook server failed")
+		}
 	case version.FullCommand():
 		println(build.PrintBuildInfo())
 	default:
@@ -57,4 +57,7 @@ func TestOptionFlagsAreSorted(t *testing.T) {
 
 	serve, _ := registerServe(app)
 	assertOptionFlagsAreSorted(t, serve)
+
+	webhook, _ := registerWebhook(app)
+	assertOptionFlagsAreSorted(t, webhook)
 }
@@ -0,0 +1,235 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/contourconfig"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+// contourConfigurationReloader applies changes to the ContourConfiguration
+// resource Contour was started with without restarting Contour.
+//
+// The theory of operation of the contourConfigurationReloader is as follows:
+//
+//  1. On startup the contourConfigurationReloader registers itself as an
+//     event handler of the ContourConfiguration informer.
+//  2. Each time the ContourConfiguration is added or its generation changes,
+//     its spec is overlaid on the defaults and validated. Updates that leave
+//     the generation unchanged, such as the status updates written by the
+//     contourConfigurationReloader itself, are ignored.
+//  3. The fields that only affect DAG and xDS generation are applied on top
+//     of the configuration Contour was started with, and the result is passed
+//     to reconfigure if it differs from the configuration currently in use.
+//  4. The `Valid` condition of the ContourConfiguration is updated, listing
+//     the changed fields that only take effect once Contour is restarted.
+//     Since status updates are only written by the leader, the last status
+//     is sent again when this Contour is elected leader.
+type contourConfigurationReloader struct {
+	log           logrus.FieldLogger
+	cache         cache.Cache
+	statusUpdater k8s.StatusUpdater
+
+	// config is the configuration Contour was started with.
+	config contour_v1alpha1.ContourConfigurationSpec
+
+	// reconfigure is called with the configuration to use
+	// when the reloadable fields have changed.
+	reconfigure func(contour_v1alpha1.ContourConfigurationSpec) error
+
+	update  chan *contour_v1alpha1.ContourConfiguration
+	elected chan struct{}
+}
+
+func newContourConfigurationReloader(
+	log logrus.FieldLogger,
+	informerCache cache.Cache,
+	statusUpdater k8s.StatusUpdater,
+	config contour_v1alpha1.ContourConfigurationSpec,
+	reconfigure func(contour_v1alpha1.ContourConfigurationSpec) error,
+) *contourConfigurationReloader {
+	return &contourConfigurationReloader{
+		log:           log,
+		cache:         informerCache,
+		statusUpdater: statusUpdater,
+		config:        config,
+		reconfigure:   reconfigure,
+		update:        make(chan *contour_v1alpha1.ContourConfiguration),
+		elected:       make(chan struct{}, 1),
+	}
+}
+
+// NeedLeaderElection is included to implement manager.LeaderElectionRunnable.
+// Every Contour needs to apply configuration changes, not just the leader.
+func (r *contourConfigurationReloader) NeedLeaderElection() bool {
+	return false
+}
+
+// OnElectedLeader implements leadership.NeedLeaderElectionNotification.
+func (r *contourConfigurationReloader) OnElectedLeader() {
+	select {
+	case r.elected <- struct{}{}:
+	default:
+	}
+}
+
+func (r *contourConfigurationReloader) OnAdd(obj any, _ bool) {
+	if contourConfig, ok := obj.(*contour_v1alpha1.ContourConfiguration); ok {
+		r.update <- contourConfig
+	}
+}
+
+func (r *contourConfigurationReloader) OnUpdate(oldObj, newObj any) {
+	contourConfig, ok := newObj.(*contour_v1alpha1.ContourConfiguration)
+	if !ok {
+		return
+	}
+
+	// Only spec changes increment the generation, status
+	// updates and resyncs have nothing to reload.
+	if old, ok := oldObj.(*contour_v1alpha1.ContourConfiguration); ok && old.Generation == contourConfig.Generation {
+		return
+	}
+
+	r.update <- contourConfig
+}
+
+func (r *contourConfigurationReloader) OnDelete(any) {
+	r.log.Warn("ContourConfiguration was deleted, continuing with the current configuration")
+}
+
+func (r *contourConfigurationReloader) Start(ctx context.Context) error {
+	inf, err := r.cache.GetInformer(ctx, &contour_v1alpha1.ContourConfiguration{})
+	if err != nil {
+		return err
+	}
+
+	if _, err := inf.AddEventHandler(r); err != nil {
+		return err
+	}
+
+	current := r.config
+	var lastStatus *k8s.StatusUpdate
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case contourConfig := <-r.update:
+			var upd k8s.StatusUpdate
+			current, upd = r.reload(current, contourConfig)
+			lastStatus = &upd
+			r.statusUpdater.Send(upd)
+		case <-r.elected:
+			if lastStatus != nil {
+				r.statusUpdater.Send(*lastStatus)
+			}
+		}
+	}
+}
+
+// reload applies the given ContourConfiguration, if it differs from
+// the current configuration, and returns the configuration now in use
+// along with the status update to write for the ContourConfiguration.
+func (r *contourConfigurationReloader) reload(current contour_v1alpha1.ContourConfigurationSpec, contourConfig *contour_v1alpha1.ContourConfiguration) (contour_v1alpha1.ContourConfigurationSpec, k8s.StatusUpdate) {
+	log := r.log.WithField("name", contourConfig.Name).
+		WithField("namespace", contourConfig.Namespace).
+		WithField("generation", contourConfig.Generation)
+
+	desired, err := contourconfig.OverlayOnDefaults(contourConfig.Spec)
+	if err == nil {
+		err = desired.Validate()
+	}
+	if err != nil {
+		log.WithError(err).Error("invalid ContourConfiguration, continuing with the current configuration")
+		return current, contourConfigurationStatus(contourConfig, nil, err)
+	}
+
+	reloaded, restartRequired := contourconfig.Reload(r.config, desired)
+
+	if !reflect.DeepEqual(reloaded, current) {
+		if err := r.reconfigure(reloaded); err != nil {
+			log.WithError(err).Error("failed to apply ContourConfiguration, continuing with the current configuration")
+			return current, contourConfigurationStatus(contourConfig, nil, err)
+		}
+
+		log.Info("applied ContourConfiguration changes")
+		current = reloaded
+	}
+
+	if len(restartRequired) > 0 {
+		log.WithField("fields", restartRequired).Warn("some ContourConfiguration changes only take effect once Contour is restarted")
+	}
+
+	return current, contourConfigurationStatus(contourConfig, restartRequired, nil)
+}
+
+// contourConfigurationStatus returns a status update setting the
+// `Valid` condition of the given ContourConfiguration.
+func contourConfigurationStatus(contourConfig *contour_v1alpha1.ContourConfiguration, restartRequired []string, err error) k8s.StatusUpdate {
+	generation := contourConfig.Generation
+
+	return k8s.StatusUpdate{
+		NamespacedName: k8s.NamespacedNameOf(contourConfig),
+		Resource:       &contour_v1alpha1.ContourConfiguration{},
+		Mutator: k8s.StatusMutatorFunc(func(obj client.Object) client.Object {
+			o, ok := obj.(*contour_v1alpha1.ContourConfiguration)
+			if !ok {
+				return obj
+			}
+
+			cond := contour_v1.DetailedCondition{
+				Condition: contour_v1.Condition{
+					Type:               contour_v1.ValidConditionType,
+					Status:             contour_v1.ConditionTrue,
+					ObservedGeneration: generation,
+					LastTransitionTime: meta_v1.Now(),
+					Reason:             "Valid",
+					Message:            "Valid ContourConfiguration",
+				},
+			}
+
+			if err != nil {
+				cond.AddError(contour_v1.ConditionTypeSpecError, "InvalidConfiguration", err.Error())
+			}
+
+			if len(restartRequired) > 0 {
+				cond.AddWarning(contour_v1alpha1.ConditionTypeRestartRequired, "RestartRequired",
+					"the following fields only take effect once Contour is restarted: "+strings.Join(restartRequired, ", "))
+			}
+
+			updated := o.DeepCopy()
+			updated.Status.Conditions = nil
+			for _, existing := range o.Status.Conditions {
+				if existing.Type != contour_v1.ValidConditionType {
+					updated.Status.Conditions = append(updated.Status.Conditions, existing)
+				}
+			}
+			updated.Status.Conditions = append(updated.Status.Conditions, cond)
+
+			return updated
+		}),
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/contourconfig"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+func TestContourConfigurationReloaderReload(t *testing.T) {
+	tests := map[string]struct {
+		spec            contour_v1alpha1.ContourConfigurationSpec
+		wantReconfigure bool
+		wantStatus      contour_v1.ConditionStatus
+		wantErrors      []contour_v1.SubCondition
+		wantWarnings    []contour_v1.SubCondition
+	}{
+		"unchanged": {
+			spec:       contour_v1alpha1.ContourConfigurationSpec{},
+			wantStatus: contour_v1.ConditionTrue,
+		},
+		"reloadable field changed": {
+			spec: contour_v1alpha1.ContourConfigurationSpec{
+				Envoy: &contour_v1alpha1.EnvoyConfig{
+					Timeouts: &contour_v1alpha1.TimeoutParameters{
+						RequestTimeout: ptr.To("30s"),
+					},
+				},
+			},
+			wantReconfigure: true,
+			wantStatus:      contour_v1.ConditionTrue,
+		},
+		"restart required field changed": {
+			spec: contour_v1alpha1.ContourConfigurationSpec{
+				XDSServer: &contour_v1alpha1.XDSServerConfig{
+					Port: 9001,
+				},
+			},
+			wantStatus: contour_v1.ConditionTrue,
+			wantWarnings: []contour_v1.SubCondition{{
+				Type:    contour_v1alpha1.ConditionTypeRestartRequired,
+				Status:  contour_v1.ConditionTrue,
+				Reason:  "RestartRequired",
+				Message: "the following fields only take effect once Contour is restarted: xdsServer.port",
+			}},
+		},
+		"invalid": {
+			spec: contour_v1alpha1.ContourConfigurationSpec{
+				Envoy: &contour_v1alpha1.EnvoyConfig{
+					Cluster: &contour_v1alpha1.ClusterParameters{
+						DNSLookupFamily: "invalid",
+					},
+				},
+			},
+			wantStatus: contour_v1.ConditionFalse,
+			wantErrors: []contour_v1.SubCondition{{
+				Type:    contour_v1.ConditionTypeSpecError,
+				Status:  contour_v1.ConditionTrue,
+				Reason:  "InvalidConfiguration",
+				Message: "invalid cluster dns family type \"invalid\"",
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := contourconfig.Defaults()
+
+			var reconfigured *contour_v1alpha1.ContourConfigurationSpec
+			r := newContourConfigurationReloader(fixture.NewTestLogger(t), nil, nil, config,
+				func(spec contour_v1alpha1.ContourConfigurationSpec) error {
+					reconfigured = &spec
+					return nil
+				})
+
+			contourConfig := &contour_v1alpha1.ContourConfiguration{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:       "contour",
+					Namespace:  "projectcontour",
+					Generation: 2,
+				},
+				Spec: tc.spec,
+			}
+
+			current, upd := r.reload(config, contourConfig)
+
+			if tc.wantReconfigure {
+				require.NotNil(t, reconfigured)
+				assert.Equal(t, *reconfigured, current)
+				assert.NotEqual(t, config, current)
+			} else {
+				assert.Nil(t, reconfigured)
+				assert.Equal(t, config, current)
+			}
+
+			assert.Equal(t, k8s.NamespacedNameOf(contourConfig), upd.NamespacedName)
+
+			existing := contourConfig.DeepCopy()
+			existing.Status.Conditions = []contour_v1.DetailedCondition{
+				{Condition: contour_v1.Condition{Type: "Other", Status: contour_v1.ConditionTrue}},
+				{Condition: contour_v1.Condition{Type: contour_v1.ValidConditionType, Status: contour_v1.ConditionUnknown}},
+			}
+
+			updated, ok := upd.Mutator.Mutate(existing).(*contour_v1alpha1.ContourConfiguration)
+			require.True(t, ok)
+			require.Len(t, updated.Status.Conditions, 2)
+			assert.Equal(t, "Other", updated.Status.Conditions[0].Type)
+
+			valid := updated.Status.Conditions[1]
+			assert.Equal(t, contour_v1.ValidConditionType, valid.Type)
+			assert.Equal(t, tc.wantStatus, valid.Status)
+			assert.Equal(t, int64(2), valid.ObservedGeneration)
+			assert.Equal(t, tc.wantErrors, valid.Errors)
+			assert.Equal(t, tc.wantWarnings, valid.Warnings)
+		})
+	}
+}
+
+func TestContourConfigurationReloaderOnUpdate(t *testing.T) {
+	r := newContourConfigurationReloader(fixture.NewTestLogger(t), nil, nil, contourconfig.Defaults(),
+		func(contour_v1alpha1.ContourConfigurationSpec) error {
+			return nil
+		})
+	r.update = make(chan *contour_v1alpha1.ContourConfiguration, 1)
+
+	old := &contour_v1alpha1.ContourConfiguration{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:       "contour",
+			Namespace:  "projectcontour",
+			Generation: 2,
+		},
+	}
+
+	// A status update, e.g. the Valid condition written by the
+	// reloader, leaves the generation unchanged and is not reloaded.
+	statusUpdated := old.DeepCopy()
+	statusUpdated.Status.Conditions = []contour_v1.DetailedCondition{
+		{Condition: contour_v1.Condition{Type: contour_v1.ValidConditionType, Status: contour_v1.ConditionTrue}},
+	}
+	r.OnUpdate(old, statusUpdated)
+	assert.Empty(t, r.update)
+
+	specUpdated := statusUpdated.DeepCopy()
+	specUpdated.Generation = 3
+	specUpdated.Spec.XDSServer = &contour_v1alpha1.XDSServerConfig{Port: 9001}
+	r.OnUpdate(statusUpdated, specUpdated)
+	require.Len(t, r.update, 1)
+	assert.Equal(t, specUpdated, <-r.update)
+}
@@ -55,6 +55,11 @@ type loadBalancerStatusWriter struct {
 	statusUpdater     k8s.StatusUpdater
 	ingressClassNames []string
 	gatewayRef        *types.NamespacedName
+
+	// gatewayOnly, if set, only writes the address to the
+	// Gateway named by gatewayRef, and not to any Ingress or
+	// HTTPProxy objects.
+	gatewayOnly bool
 }
 
 func (isw *loadBalancerStatusWriter) NeedLeaderElection() bool {
@@ -81,9 +86,9 @@ func (isw *loadBalancerStatusWriter) Start(ctx context.Context) error {
 	// Create informers for the types that need load balancer
 	// address status. The cache should have already started
 	// informers, so new informers will auto-start.
-	resources := []client.Object{
-		&contour_v1.HTTPProxy{},
-		&networking_v1.Ingress{},
+	var resources []client.Object
+	if !isw.gatewayOnly {
+		resources = append(resources, &contour_v1.HTTPProxy{}, &networking_v1.Ingress{})
 	}
 
 	// Only create Gateway informer if a gateway was provided,
@@ -119,22 +124,8 @@ func (isw *loadBalancerStatusWriter) Start(ctx context.Context) error {
 
 			u.Set(lbs)
 
-			var ingressList networking_v1.IngressList
-			if err := isw.cache.List(context.Background(), &ingressList); err != nil {
-				isw.log.WithError(err).WithField("kind", "Ingress").Error("failed to list objects")
-			} else {
-				for i := range ingressList.Items {
-					u.OnAdd(&ingressList.Items[i], false)
-				}
-			}
-
-			var proxyList contour_v1.HTTPProxyList
-			if err := isw.cache.List(context.Background(), &proxyList); err != nil {
-				isw.log.WithError(err).WithField("kind", "HTTPProxy").Error("failed to list objects")
-			} else {
-				for i := range proxyList.Items {
-					u.OnAdd(&proxyList.Items[i], false)
-				}
+			if !isw.gatewayOnly {
+				isw.updateIngressesAndProxies(u)
 			}
 
 			// Only list Gateways if a gateway was configured,
@@ -153,6 +144,28 @@ func (isw *loadBalancerStatusWriter) Start(ctx context.Context) error {
 	}
 }
 
+// updateIngressesAndProxies updates the address of all Ingress
+// and HTTPProxy objects in the cache.
+func (isw *loadBalancerStatusWriter) updateIngressesAndProxies(u *k8s.StatusAddressUpdater) {
+	var ingressList networking_v1.IngressList
+	if err := isw.cache.List(context.Background(), &ingressList); err != nil {
+		isw.log.WithError(err).WithField("kind", "Ingress").Error("failed to list objects")
+	} else {
+		for i := range ingressList.Items {
+			u.OnAdd(&ingressList.Items[i], false)
+		}
+	}
+
+	var proxyList contour_v1.HTTPProxyList
+	if err := isw.cache.List(context.Background(), &proxyList); err != nil {
+		isw.log.WithError(err).WithField("kind", "HTTPProxy").Error("failed to list objects")
+	} else {
+		for i := range proxyList.Items {
+			u.OnAdd(&proxyList.Items[i], false)
+		}
+	}
+}
+
 func parseStatusFlag(status string) core_v1.LoadBalancerStatus {
 	// Support ','-separated lists.
 	var ingresses []core_v1.LoadBalancerIngress
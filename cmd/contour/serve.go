@@ -29,6 +29,7 @@ import (
 	core_v1 "k8s.io/api/core/v1"
 	discovery_v1 "k8s.io/api/discovery/v1"
 	networking_v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -46,6 +47,8 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -135,7 +138,7 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 	serve.Flag("debug", "Enable debug logging.").Short('d').BoolVar(&ctx.Config.Debug)
 	serve.Flag("debug-http-address", "Address the debug http endpoint will bind to.").PlaceHolder("<ipaddr>").StringVar(&ctx.debugAddr)
 	serve.Flag("debug-http-port", "Port the debug http endpoint will bind to.").PlaceHolder("<port>").IntVar(&ctx.debugPort)
-	serve.Flag("disable-feature", "Do not start an informer for the specified resources.").PlaceHolder("<extensionservices,tlsroutes,grpcroutes,tcproutes,backendtlspolicies>").EnumsVar(&ctx.disabledFeatures, "extensionservices", "tlsroutes", "grpcroutes", "tcproutes", "backendtlspolicies")
+	serve.Flag("disable-feature", "Do not start an informer for the specified resources.").PlaceHolder("<extensionservices,tlsroutes,grpcroutes,tcproutes,backendtlspolicies,xbackendtrafficpolicies,xlistenersets>").EnumsVar(&ctx.disabledFeatures, "extensionservices", "tlsroutes", "grpcroutes", "tcproutes", "backendtlspolicies", "xbackendtrafficpolicies", "xlistenersets", "backendlbpolicies")
 	serve.Flag("disable-leader-election", "Disable leader election mechanism.").BoolVar(&ctx.LeaderElection.Disable)
 
 	serve.Flag("envoy-http-access-log", "Envoy HTTP access log.").PlaceHolder("/path/to/file").StringVar(&ctx.httpAccessLog)
@@ -275,6 +278,11 @@ func NewServer(log logrus.FieldLogger, ctx *serveContext) (*Server, error) {
 							return obj, nil
 						}
 
+						// Keep ConfigMaps that have the jwks.json key because they may be used by JWT providers
+						if _, ok := configMap.Data[dag.JWKSKey]; ok {
+							return obj, nil
+						}
+
 						// Other types of ConfigMaps will never be referred to, so we can remove all data.
 						// Last-applied-configuration annotation might contain a copy of the complete data.
 						configMap.Data = map[string]string{}
@@ -309,6 +317,17 @@ func NewServer(log logrus.FieldLogger, ctx *serveContext) (*Server, error) {
 		}
 	}
 
+	if len(ctx.contourConfigurationName) > 0 {
+		// Only cache the ContourConfiguration that Contour is configured
+		// with, regardless of the namespaces being watched, so that changes
+		// to it can be applied without restarting.
+		key := contourConfigurationKey(ctx)
+		options.Cache.ByObject[&contour_v1alpha1.ContourConfiguration{}] = ctrl_cache.ByObject{
+			Namespaces: map[string]ctrl_cache.Config{key.Namespace: {}},
+			Field:      fields.OneTermEqualSelector("metadata.name", key.Name),
+		}
+	}
+
 	if ctx.LeaderElection.Disable {
 		log.Info("Leader election disabled")
 		options.LeaderElection = false
@@ -341,22 +360,25 @@ func NewServer(log logrus.FieldLogger, ctx *serveContext) (*Server, error) {
 	}, nil
 }
 
+// contourConfigurationKey returns the name/namespace of the ContourConfiguration
+// resource Contour is configured with. The namespace is taken from the environment
+// variable "CONTOUR_NAMESPACE" which should exist on the Contour deployment.
+//
+// If the env variable is not present, it will default to "projectcontour".
+func contourConfigurationKey(ctx *serveContext) client.ObjectKey {
+	return client.ObjectKey{
+		Namespace: config.GetenvOr("CONTOUR_NAMESPACE", "projectcontour"),
+		Name:      ctx.contourConfigurationName,
+	}
+}
+
 func (s *Server) getConfig() (contour_v1alpha1.ContourConfigurationSpec, error) {
 	var userConfig contour_v1alpha1.ContourConfigurationSpec
 
 	// Get the ContourConfiguration CRD if specified
 	if len(s.ctx.contourConfigurationName) > 0 {
-		// Determine the name/namespace of the configuration resource utilizing the environment
-		// variable "CONTOUR_NAMESPACE" which should exist on the Contour deployment.
-		//
-		// If the env variable is not present, it will default to "projectcontour".
-		contourNamespace, found := os.LookupEnv("CONTOUR_NAMESPACE")
-		if !found {
-			contourNamespace = "projectcontour"
-		}
-
 		contourConfig := &contour_v1alpha1.ContourConfiguration{}
-		key := client.ObjectKey{Namespace: contourNamespace, Name: s.ctx.contourConfigurationName}
+		key := contourConfigurationKey(s.ctx)
 
 		// Using GetAPIReader() here because the manager's caches won't be started yet,
 		// so reads from the manager's client (which uses the caches for reads) will fail.
@@ -442,50 +464,11 @@ func (s *Server) doServe() error {
 		}
 	}
 
-	timeouts, err := contourconfig.ParseTimeoutPolicy(contourConfiguration.Envoy.Timeouts)
+	listenerConfig, err := s.getListenerConfig(contourConfiguration)
 	if err != nil {
 		return err
 	}
 
-	listenerConfig := xdscache_v3.ListenerConfig{
-		Compression:                   contourConfiguration.Envoy.Listener.Compression,
-		UseProxyProto:                 *contourConfiguration.Envoy.Listener.UseProxyProto,
-		HTTPAccessLog:                 contourConfiguration.Envoy.HTTPListener.AccessLog,
-		HTTPSAccessLog:                contourConfiguration.Envoy.HTTPSListener.AccessLog,
-		AccessLogType:                 contourConfiguration.Envoy.Logging.AccessLogFormat,
-		AccessLogJSONFields:           contourConfiguration.Envoy.Logging.AccessLogJSONFields,
-		AccessLogLevel:                contourConfiguration.Envoy.Logging.AccessLogLevel,
-		AccessLogFormatString:         contourConfiguration.Envoy.Logging.AccessLogFormatString,
-		AccessLogFormatterExtensions:  contourConfiguration.Envoy.Logging.AccessLogFormatterExtensions(),
-		MinimumTLSVersion:             annotation.TLSVersion(contourConfiguration.Envoy.Listener.TLS.MinimumProtocolVersion, "1.2"),
-		MaximumTLSVersion:             annotation.TLSVersion(contourConfiguration.Envoy.Listener.TLS.MaximumProtocolVersion, "1.3"),
-		CipherSuites:                  contourConfiguration.Envoy.Listener.TLS.SanitizedCipherSuites(),
-		Timeouts:                      timeouts,
-		DefaultHTTPVersions:           parseDefaultHTTPVersions(contourConfiguration.Envoy.DefaultHTTPVersions),
-		AllowChunkedLength:            !*contourConfiguration.Envoy.Listener.DisableAllowChunkedLength,
-		MergeSlashes:                  !*contourConfiguration.Envoy.Listener.DisableMergeSlashes,
-		ServerHeaderTransformation:    contourConfiguration.Envoy.Listener.ServerHeaderTransformation,
-		XffNumTrustedHops:             *contourConfiguration.Envoy.Network.XffNumTrustedHops,
-		StripTrailingHostDot:          *contourConfiguration.Envoy.Network.EnvoyStripTrailingHostDot,
-		ConnectionBalancer:            contourConfiguration.Envoy.Listener.ConnectionBalancer,
-		MaxRequestsPerConnection:      contourConfiguration.Envoy.Listener.MaxRequestsPerConnection,
-		HTTP2MaxConcurrentStreams:     contourConfiguration.Envoy.Listener.HTTP2MaxConcurrentStreams,
-		PerConnectionBufferLimitBytes: contourConfiguration.Envoy.Listener.PerConnectionBufferLimitBytes,
-		SocketOptions:                 contourConfiguration.Envoy.Listener.SocketOptions,
-	}
-
-	if listenerConfig.TracingConfig, err = s.setupTracingService(contourConfiguration.Tracing); err != nil {
-		return err
-	}
-
-	if listenerConfig.RateLimitConfig, err = s.setupRateLimitService(contourConfiguration); err != nil {
-		return err
-	}
-
-	if listenerConfig.GlobalExternalAuthConfig, err = s.setupGlobalExternalAuthentication(contourConfiguration); err != nil {
-		return err
-	}
-
 	contourMetrics := metrics.NewMetrics(s.registry)
 
 	// Endpoints updates are handled directly by the EndpointsTranslator/EndpointSliceTranslator due to the high update volume.
@@ -500,8 +483,10 @@ func (s *Server) doServe() error {
 		XDSClusterName: envoy_v3.DefaultXDSClusterName,
 	})
 
+	listenerCache := xdscache_v3.NewListenerCache(listenerConfig, *contourConfiguration.Envoy.Metrics, *contourConfiguration.Envoy.Health, *contourConfiguration.Envoy.Network.EnvoyAdminPort, envoyGen)
+
 	resources := []xdscache.ResourceCache{
-		xdscache_v3.NewListenerCache(listenerConfig, *contourConfiguration.Envoy.Metrics, *contourConfiguration.Envoy.Health, *contourConfiguration.Envoy.Network.EnvoyAdminPort, envoyGen),
+		listenerCache,
 		xdscache_v3.NewSecretsCache(envoy_v3.StatsSecrets(contourConfiguration.Envoy.Metrics.TLS)),
 		&xdscache_v3.RouteCache{},
 		xdscache_v3.NewClusterCache(envoyGen),
@@ -514,7 +499,18 @@ func (s *Server) doServe() error {
 
 	// snapshotHandler triggers go-control-plane Snapshots based on
 	// the contents of the Contour xDS caches after the DAG is built.
-	snapshotHandler := xdscache_v3.NewSnapshotHandler(resources, s.log.WithField("context", "snapshotHandler"))
+	// When several Gateways are served, the Envoys of each Gateway are
+	// grouped by their node cluster, which is the Gateway's namespace/name.
+	var nodeGroups []string
+	for _, gatewayRef := range servedGatewayRefs(contourConfiguration.Gateway) {
+		nodeGroups = append(nodeGroups, gatewayRef.String())
+	}
+	snapshotHandler := xdscache_v3.NewSnapshotHandler(resources, nodeGroups, s.log.WithField("context", "snapshotHandler"))
+
+	// streamMetrics records metrics about the Envoys connected to the
+	// xDS server and the snapshots they ACK.
+	streamMetrics := contour_xds_v3.NewStreamMetrics(contourMetrics)
+	snapshotHandler.SetStreamMetrics(streamMetrics)
 
 	// register observer for endpoints updates.
 	endpointHandler.SetObserver(contour.ComposeObservers(snapshotHandler))
@@ -527,62 +523,17 @@ func (s *Server) doServe() error {
 		s.log.WithField("context", "envoy-client-certificate").Infof("enabled client certificate with secret: %q", contourConfiguration.Envoy.ClientCertificate)
 	}
 
-	var ingressClassNames []string
-	if contourConfiguration.Ingress != nil {
-		ingressClassNames = contourConfiguration.Ingress.ClassNames
-	}
-
-	var clientCert *types.NamespacedName
-	var fallbackCert *types.NamespacedName
-	if contourConfiguration.Envoy.ClientCertificate != nil {
-		clientCert = &types.NamespacedName{Name: contourConfiguration.Envoy.ClientCertificate.Name, Namespace: contourConfiguration.Envoy.ClientCertificate.Namespace}
-	}
-	if contourConfiguration.HTTPProxy.FallbackCertificate != nil {
-		fallbackCert = &types.NamespacedName{Name: contourConfiguration.HTTPProxy.FallbackCertificate.Name, Namespace: contourConfiguration.HTTPProxy.FallbackCertificate.Namespace}
-	}
-
 	sh := k8s.NewStatusUpdateHandler(s.log.WithField("context", "StatusUpdateHandler"), s.mgr.GetClient(), contourMetrics)
 	if err := s.mgr.Add(sh); err != nil {
 		return err
 	}
 
-	var gatewayRef *types.NamespacedName
-
-	if contourConfiguration.Gateway != nil {
-		gatewayRef = &types.NamespacedName{
-			Namespace: contourConfiguration.Gateway.GatewayRef.Namespace,
-			Name:      contourConfiguration.Gateway.GatewayRef.Name,
-		}
+	dbc, err := s.getDAGBuilderConfig(contourConfiguration, contourMetrics)
+	if err != nil {
+		return err
 	}
 
-	builder := s.getDAGBuilder(dagBuilderConfig{
-		ingressClassNames:                  ingressClassNames,
-		rootNamespaces:                     contourConfiguration.HTTPProxy.RootNamespaces,
-		gatewayRef:                         gatewayRef,
-		disablePermitInsecure:              *contourConfiguration.HTTPProxy.DisablePermitInsecure,
-		enableExternalNameService:          *contourConfiguration.EnableExternalNameService,
-		dnsLookupFamily:                    contourConfiguration.Envoy.Cluster.DNSLookupFamily,
-		headersPolicy:                      contourConfiguration.Policy,
-		clientCert:                         clientCert,
-		fallbackCert:                       fallbackCert,
-		connectTimeout:                     timeouts.ConnectTimeout,
-		client:                             s.mgr.GetClient(),
-		metrics:                            contourMetrics,
-		httpAddress:                        contourConfiguration.Envoy.HTTPListener.Address,
-		httpPort:                           contourConfiguration.Envoy.HTTPListener.Port,
-		httpsAddress:                       contourConfiguration.Envoy.HTTPSListener.Address,
-		httpsPort:                          contourConfiguration.Envoy.HTTPSListener.Port,
-		globalExternalAuthorizationService: contourConfiguration.GlobalExternalAuthorization,
-		globalRateLimitService:             contourConfiguration.RateLimitService,
-		maxRequestsPerConnection:           contourConfiguration.Envoy.Cluster.MaxRequestsPerConnection,
-		perConnectionBufferLimitBytes:      contourConfiguration.Envoy.Cluster.PerConnectionBufferLimitBytes,
-		globalCircuitBreakerDefaults:       contourConfiguration.Envoy.Cluster.GlobalCircuitBreakerDefaults,
-		upstreamTLS: &dag.UpstreamTLS{
-			MinimumProtocolVersion: annotation.TLSVersion(contourConfiguration.Envoy.Cluster.UpstreamTLS.MinimumProtocolVersion, "1.2"),
-			MaximumProtocolVersion: annotation.TLSVersion(contourConfiguration.Envoy.Cluster.UpstreamTLS.MaximumProtocolVersion, "1.3"),
-			CipherSuites:           contourConfiguration.Envoy.Cluster.UpstreamTLS.SanitizedCipherSuites(),
-		},
-	})
+	builder := s.getDAGBuilder(dbc)
 
 	// Build the core Kubernetes event handler.
 	xdsCaches := xdscache.ObserversOf(resources)
@@ -590,9 +541,53 @@ func (s *Server) doServe() error {
 		xdsCaches = append(xdsCaches, snapshotHandler)
 	}
 
+	xdsObserver := dag.ComposeObservers(xdsCaches...)
+
+	// Report whether enough Envoys have acknowledged the configuration
+	// of each Gateway, if configured.
+	if contourConfiguration.Gateway != nil && contourConfiguration.Gateway.DataPlaneReadyQuorum != nil {
+		gatewayReadiness := contour.NewGatewayReadinessObserver(
+			s.log.WithField("context", "gatewayReadiness"),
+			streamMetrics,
+			int(*contourConfiguration.Gateway.DataPlaneReadyQuorum),
+			len(nodeGroups) > 0,
+			sh.Writer(),
+			xdsObserver,
+		)
+		if err := s.mgr.Add(gatewayReadiness); err != nil {
+			return err
+		}
+		xdsObserver = gatewayReadiness
+	}
+
+	// Progress the traffic shift policies of HTTPProxy routes. Envoy
+	// error rates are read from the clusters endpoint of each Envoy,
+	// which is only served by the stats listener over plain HTTP.
+	var upstreamRequests contour.UpstreamRequestCounter
+	if contourConfiguration.Envoy.Metrics.TLS == nil {
+		upstreamRequests = contour.NewEnvoyStatsScraper(streamMetrics, contourConfiguration.Envoy.Metrics.Port)
+	}
+	trafficShifts := contour.NewTrafficShiftController(
+		s.log.WithField("context", "trafficShift"),
+		s.mgr.GetClient(),
+		dbc.ingressClassNames,
+		upstreamRequests,
+		sh.Writer(),
+	)
+	if err := s.mgr.Add(trafficShifts); err != nil {
+		return err
+	}
+
+	// Record problems with routing objects as Warning events on them.
+	warningEvents := contour.NewWarningEventObserver(
+		s.mgr.GetEventRecorderFor("contour"),
+		contour.DefaultWarningEventInterval,
+		xdsObserver,
+	)
+
 	observer := contour.NewRebuildMetricsObserver(
 		contourMetrics,
-		dag.ComposeObservers(xdsCaches...),
+		warningEvents,
 	)
 
 	hasSynced := func() bool {
@@ -626,6 +621,21 @@ func (s *Server) doServe() error {
 		"extensionservices":         &contour_v1alpha1.ExtensionService{},
 		"services":                  &core_v1.Service{},
 		"ingresses":                 &networking_v1.Ingress{},
+		// ConfigMaps are used by BackendTLSPolicies and
+		// by JWT providers with a local JWKS.
+		"configmaps": &core_v1.ConfigMap{},
+		// Namespaces are used by Gateway API and by
+		// TLSCertificateDelegation namespace selectors.
+		"namespaces": &core_v1.Namespace{},
+	}
+
+	// ServiceImports are only watched when multi-cluster services are enabled.
+	// Their endpoints are read from the EndpointSlices derived for them.
+	if contourConfiguration.FeatureFlags.IsMultiClusterServicesEnabled() {
+		if !contourConfiguration.FeatureFlags.IsEndpointSliceEnabled() {
+			return fmt.Errorf("the multiClusterServices feature flag requires the useEndpointSlices feature flag")
+		}
+		informerResources["serviceimports"] = &mcs_v1alpha1.ServiceImport{}
 	}
 
 	// Some of the resources are optional and can be disabled, do not create informers for those.
@@ -697,8 +707,8 @@ func (s *Server) doServe() error {
 		log:               s.log.WithField("context", "loadBalancerStatusWriter"),
 		cache:             s.mgr.GetCache(),
 		lbStatus:          make(chan core_v1.LoadBalancerStatus, 1),
-		ingressClassNames: ingressClassNames,
-		gatewayRef:        gatewayRef,
+		ingressClassNames: dbc.ingressClassNames,
+		gatewayRef:        dbc.gatewayRef,
 		statusUpdater:     sh.Writer(),
 	}
 	if err := s.mgr.Add(lbsw); err != nil {
@@ -730,11 +740,18 @@ func (s *Server) doServe() error {
 			Info("Watching Service for Ingress status")
 	}
 
+	// When several Gateways are served, set up a load balancer status
+	// writer for each Gateway, watching the Service of its Envoys.
+	if err := s.setupGatewayStatusWriters(contourConfiguration, sh.Writer()); err != nil {
+		return err
+	}
+
 	xdsServer := &xdsServer{
 		log:             s.log,
 		registry:        s.registry,
 		config:          *contourConfiguration.XDSServer,
 		snapshotHandler: snapshotHandler,
+		streamMetrics:   streamMetrics,
 		resources:       resources,
 		initialDagBuilt: contourHandler.HasBuiltInitialDag,
 	}
@@ -742,8 +759,40 @@ func (s *Server) doServe() error {
 		return err
 	}
 
+	toNotify := []leadership.NeedLeaderElectionNotification{contourHandler, observer, warningEvents}
+
+	// Apply changes to the ContourConfiguration without restarting, if configured.
+	if len(s.ctx.contourConfigurationName) > 0 {
+		reloader := newContourConfigurationReloader(
+			s.log.WithField("context", "contourConfigurationReloader"),
+			s.mgr.GetCache(),
+			sh.Writer(),
+			contourConfiguration,
+			func(contourConfiguration contour_v1alpha1.ContourConfigurationSpec) error {
+				listenerConfig, err := s.getListenerConfig(contourConfiguration)
+				if err != nil {
+					return err
+				}
+
+				dbc, err := s.getDAGBuilderConfig(contourConfiguration, contourMetrics)
+				if err != nil {
+					return err
+				}
+
+				contourHandler.Reconfigure(s.getDAGProcessors(dbc), func() {
+					listenerCache.Config = listenerConfig
+				})
+				return nil
+			},
+		)
+		if err := s.mgr.Add(reloader); err != nil {
+			return err
+		}
+		toNotify = append(toNotify, reloader)
+	}
+
 	notifier := &leadership.Notifier{
-		ToNotify: []leadership.NeedLeaderElectionNotification{contourHandler, observer},
+		ToNotify: toNotify,
 	}
 	if err := s.mgr.Add(notifier); err != nil {
 		return err
@@ -753,7 +802,117 @@ func (s *Server) doServe() error {
 	return s.mgr.Start(signals.SetupSignalHandler())
 }
 
-func (s *Server) getExtensionSvcConfig(name, namespace string) (xdscache_v3.ExtensionServiceConfig, error) {
+// servedGatewayRefs returns the names of the Gateways configured in
+// the given GatewayConfig when several Gateways are served.
+func servedGatewayRefs(gatewayConfig *contour_v1alpha1.GatewayConfig) []types.NamespacedName {
+	if gatewayConfig == nil {
+		return nil
+	}
+
+	var gatewayRefs []types.NamespacedName
+	for _, gw := range gatewayConfig.Gateways {
+		gatewayRefs = append(gatewayRefs, types.NamespacedName{Namespace: gw.GatewayRef.Namespace, Name: gw.GatewayRef.Name})
+	}
+	return gatewayRefs
+}
+
+// setupGatewayStatusWriters sets up a load balancer status writer for each of
+// several served Gateways, which writes the addresses of the Gateway's Envoy
+// Service to the Gateway's status.
+func (s *Server) setupGatewayStatusWriters(contourConfiguration contour_v1alpha1.ContourConfigurationSpec, statusUpdater k8s.StatusUpdater) error {
+	if contourConfiguration.Gateway == nil {
+		return nil
+	}
+
+	for _, gw := range contourConfiguration.Gateway.Gateways {
+		gatewayRef := types.NamespacedName{Namespace: gw.GatewayRef.Namespace, Name: gw.GatewayRef.Name}
+		log := s.log.WithField("context", "loadBalancerStatusWriter").WithField("gateway", gatewayRef.String())
+
+		lbsw := &loadBalancerStatusWriter{
+			log:           log,
+			cache:         s.mgr.GetCache(),
+			lbStatus:      make(chan core_v1.LoadBalancerStatus, 1),
+			gatewayRef:    &gatewayRef,
+			gatewayOnly:   true,
+			statusUpdater: statusUpdater,
+		}
+		if err := s.mgr.Add(lbsw); err != nil {
+			return err
+		}
+
+		serviceHandler := &k8s.ServiceStatusLoadBalancerWatcher{
+			ServiceName: gw.EnvoyService.Name,
+			LBStatus:    lbsw.lbStatus,
+			Log:         log.WithField("context", "serviceStatusLoadBalancerWatcher"),
+		}
+		handler := k8s.NewNamespaceFilter([]string{gw.EnvoyService.Namespace}, serviceHandler)
+
+		if err := s.informOnResource(&core_v1.Service{}, handler); err != nil {
+			s.log.WithError(err).WithField("resource", "services").Fatal("failed to create informer")
+		}
+
+		log.WithField("envoy-service-name", gw.EnvoyService.Name).
+			WithField("envoy-service-namespace", gw.EnvoyService.Namespace).
+			Info("Watching Service for Gateway status")
+	}
+
+	return nil
+}
+
+// getListenerConfig returns the configuration of the Envoy listeners
+// generated from the given ContourConfigurationSpec.
+func (s *Server) getListenerConfig(contourConfiguration contour_v1alpha1.ContourConfigurationSpec) (xdscache_v3.ListenerConfig, error) {
+	timeouts, err := contourconfig.ParseTimeoutPolicy(contourConfiguration.Envoy.Timeouts)
+	if err != nil {
+		return xdscache_v3.ListenerConfig{}, err
+	}
+
+	listenerConfig := xdscache_v3.ListenerConfig{
+		Compression:                   contourConfiguration.Envoy.Listener.Compression,
+		UseProxyProto:                 *contourConfiguration.Envoy.Listener.UseProxyProto,
+		HTTPAccessLog:                 contourConfiguration.Envoy.HTTPListener.AccessLog,
+		HTTPSAccessLog:                contourConfiguration.Envoy.HTTPSListener.AccessLog,
+		AccessLogType:                 contourConfiguration.Envoy.Logging.AccessLogFormat,
+		AccessLogJSONFields:           contourConfiguration.Envoy.Logging.AccessLogJSONFields,
+		AccessLogLevel:                contourConfiguration.Envoy.Logging.AccessLogLevel,
+		AccessLogFormatString:         contourConfiguration.Envoy.Logging.AccessLogFormatString,
+		AccessLogFormatterExtensions:  contourConfiguration.Envoy.Logging.AccessLogFormatterExtensions(),
+		MinimumTLSVersion:             annotation.TLSVersion(contourConfiguration.Envoy.Listener.TLS.MinimumProtocolVersion, "1.2"),
+		MaximumTLSVersion:             annotation.TLSVersion(contourConfiguration.Envoy.Listener.TLS.MaximumProtocolVersion, "1.3"),
+		CipherSuites:                  contourConfiguration.Envoy.Listener.TLS.SanitizedCipherSuites(),
+		OCSPStaplePolicy:              contourConfiguration.Envoy.Listener.TLS.OCSPStaplePolicy,
+		Timeouts:                      timeouts,
+		DefaultHTTPVersions:           parseDefaultHTTPVersions(contourConfiguration.Envoy.DefaultHTTPVersions),
+		AllowChunkedLength:            !*contourConfiguration.Envoy.Listener.DisableAllowChunkedLength,
+		MergeSlashes:                  !*contourConfiguration.Envoy.Listener.DisableMergeSlashes,
+		ServerHeaderTransformation:    contourConfiguration.Envoy.Listener.ServerHeaderTransformation,
+		XffNumTrustedHops:             *contourConfiguration.Envoy.Network.XffNumTrustedHops,
+		StripTrailingHostDot:          *contourConfiguration.Envoy.Network.EnvoyStripTrailingHostDot,
+		ConnectionBalancer:            contourConfiguration.Envoy.Listener.ConnectionBalancer,
+		MaxRequestsPerConnection:      contourConfiguration.Envoy.Listener.MaxRequestsPerConnection,
+		HTTP2MaxConcurrentStreams:     contourConfiguration.Envoy.Listener.HTTP2MaxConcurrentStreams,
+		PerConnectionBufferLimitBytes: contourConfiguration.Envoy.Listener.PerConnectionBufferLimitBytes,
+		SocketOptions:                 contourConfiguration.Envoy.Listener.SocketOptions,
+	}
+
+	if listenerConfig.TracingConfig, err = s.setupTracingService(contourConfiguration.Tracing); err != nil {
+		return xdscache_v3.ListenerConfig{}, err
+	}
+
+	if listenerConfig.RateLimitConfig, err = s.setupRateLimitService(contourConfiguration); err != nil {
+		return xdscache_v3.ListenerConfig{}, err
+	}
+
+	if listenerConfig.GlobalExternalAuthConfig, err = s.setupGlobalExternalAuthentication(contourConfiguration); err != nil {
+		return xdscache_v3.ListenerConfig{}, err
+	}
+
+	return listenerConfig, nil
+}
+
+// getExtensionSvcConfig returns the config of the named ExtensionService. Unless
+// allowHTTP1 is set, the ExtensionService must be reached over HTTP/2, as gRPC needs.
+func (s *Server) getExtensionSvcConfig(name, namespace string, allowHTTP1 bool) (xdscache_v3.ExtensionServiceConfig, error) {
 	extensionSvc := &contour_v1alpha1.ExtensionService{}
 	key := client.ObjectKey{
 		Namespace: namespace,
@@ -766,6 +925,10 @@ func (s *Server) getExtensionSvcConfig(name, namespace string) (xdscache_v3.Exte
 		return xdscache_v3.ExtensionServiceConfig{}, fmt.Errorf("error getting extension service %s: %v", key, err)
 	}
 
+	if protocol := ptr.Deref(extensionSvc.Spec.Protocol, ""); !allowHTTP1 && (protocol == "tls" || protocol == "http/1.1") {
+		return xdscache_v3.ExtensionServiceConfig{}, fmt.Errorf("extension service %s uses the %q protocol, which is only supported by authorization servers with httpService", key, protocol)
+	}
+
 	var responseTimeout timeout.Setting
 	var err error
 
@@ -796,7 +959,7 @@ func (s *Server) setupTracingService(tracingConfig *contour_v1alpha1.TracingConf
 	}
 
 	// ensure the specified ExtensionService exists
-	extensionSvcConfig, err := s.getExtensionSvcConfig(tracingConfig.ExtensionService.Name, tracingConfig.ExtensionService.Namespace)
+	extensionSvcConfig, err := s.getExtensionSvcConfig(tracingConfig.ExtensionService.Name, tracingConfig.ExtensionService.Namespace, false)
 	if err != nil {
 		return nil, err
 	}
@@ -841,7 +1004,7 @@ func (s *Server) setupRateLimitService(contourConfiguration contour_v1alpha1.Con
 	}
 
 	// ensure the specified ExtensionService exists
-	extensionSvcConfig, err := s.getExtensionSvcConfig(contourConfiguration.RateLimitService.ExtensionService.Name, contourConfiguration.RateLimitService.ExtensionService.Namespace)
+	extensionSvcConfig, err := s.getExtensionSvcConfig(contourConfiguration.RateLimitService.ExtensionService.Name, contourConfiguration.RateLimitService.ExtensionService.Namespace, false)
 	if err != nil {
 		return nil, err
 	}
@@ -862,7 +1025,8 @@ func (s *Server) setupGlobalExternalAuthentication(contourConfiguration contour_
 	}
 
 	// ensure the specified ExtensionService exists
-	extensionSvcConfig, err := s.getExtensionSvcConfig(contourConfiguration.GlobalExternalAuthorization.ExtensionServiceRef.Name, contourConfiguration.GlobalExternalAuthorization.ExtensionServiceRef.Namespace)
+	extensionSvcConfig, err := s.getExtensionSvcConfig(contourConfiguration.GlobalExternalAuthorization.ExtensionServiceRef.Name, contourConfiguration.GlobalExternalAuthorization.ExtensionServiceRef.Namespace,
+		contourConfiguration.GlobalExternalAuthorization.HTTPService != nil)
 	if err != nil {
 		return nil, err
 	}
@@ -885,6 +1049,20 @@ func (s *Server) setupGlobalExternalAuthentication(contourConfiguration contour_
 			MaxRequestBytes:     contourConfiguration.GlobalExternalAuthorization.WithRequestBody.MaxRequestBytes,
 		}
 	}
+
+	if contourConfiguration.GlobalExternalAuthorization.HTTPService != nil {
+		globalExternalAuthConfig.HTTPService = &dag.AuthorizationHTTPService{
+			PathPrefix: contourConfiguration.GlobalExternalAuthorization.HTTPService.PathPrefix,
+		}
+	}
+
+	globalExternalAuthConfig.Forwarding = &dag.AuthorizationForwarding{
+		AllowedRequestHeaders:   contourConfiguration.GlobalExternalAuthorization.AllowedRequestHeaders,
+		AllowedUpstreamHeaders:  contourConfiguration.GlobalExternalAuthorization.AllowedUpstreamHeaders,
+		AllowedClientHeaders:    contourConfiguration.GlobalExternalAuthorization.AllowedClientHeaders,
+		MetadataNamespaces:      contourConfiguration.GlobalExternalAuthorization.MetadataNamespaces,
+		RouteMetadataNamespaces: contourConfiguration.GlobalExternalAuthorization.RouteMetadataNamespaces,
+	}
 	return globalExternalAuthConfig, nil
 }
 
@@ -905,6 +1083,7 @@ type xdsServer struct {
 	registry        *prometheus.Registry
 	config          contour_v1alpha1.XDSServerConfig
 	snapshotHandler *xdscache_v3.SnapshotHandler
+	streamMetrics   *contour_xds_v3.StreamMetrics
 	resources       []xdscache.ResourceCache
 	initialDagBuilt func() bool
 }
@@ -925,7 +1104,7 @@ func (x *xdsServer) Start(ctx context.Context) error {
 	log.Info("the initial dag is built")
 
 	grpcServer := xds.NewServer(x.registry, grpcOptions(log, x.config.TLS)...)
-	contour_xds_v3.RegisterServer(envoy_server_v3.NewServer(ctx, x.snapshotHandler.GetCache(), contour_xds_v3.NewRequestLoggingCallbacks(log)), grpcServer)
+	contour_xds_v3.RegisterServer(envoy_server_v3.NewServer(ctx, x.snapshotHandler.GetCache(), contour_xds_v3.NewCallbacks(log, x.streamMetrics)), grpcServer)
 
 	addr := net.JoinHostPort(x.config.Address, strconv.Itoa(x.config.Port))
 	l, err := net.Listen("tcp", addr)
@@ -1003,30 +1182,33 @@ func (s *Server) setupHealth(healthConfig contour_v1alpha1.HealthConfig,
 	return nil
 }
 
+// disabledFeatureAliases maps former --disable-feature values
+// to the resources that replaced them.
+var disabledFeatureAliases = map[string]string{
+	// Session persistence moved from BackendLBPolicy to
+	// XBackendTrafficPolicy in Gateway API v1.3.0.
+	"backendlbpolicies": "xbackendtrafficpolicies",
+}
+
 func (s *Server) setupGatewayAPI(contourConfiguration contour_v1alpha1.ContourConfigurationSpec, eventHandler *contour.EventRecorder) {
 	// Watch resources for Gateway API if enabled.
 	if contourConfiguration.Gateway != nil {
 		resources := map[string]client.Object{
-			"gatewayclasses":     &gatewayapi_v1.GatewayClass{},
-			"gateways":           &gatewayapi_v1.Gateway{},
-			"httproutes":         &gatewayapi_v1.HTTPRoute{},
-			"referencegrants":    &gatewayapi_v1beta1.ReferenceGrant{},
-			"namespaces":         &core_v1.Namespace{},
-			"tlsroutes":          &gatewayapi_v1alpha2.TLSRoute{},
-			"grpcroutes":         &gatewayapi_v1.GRPCRoute{},
-			"tcproutes":          &gatewayapi_v1alpha2.TCPRoute{},
-			"backendtlspolicies": &gatewayapi_v1alpha3.BackendTLSPolicy{},
-			"configmaps":         &core_v1.ConfigMap{},
+			"gatewayclasses":          &gatewayapi_v1.GatewayClass{},
+			"gateways":                &gatewayapi_v1.Gateway{},
+			"httproutes":              &gatewayapi_v1.HTTPRoute{},
+			"referencegrants":         &gatewayapi_v1beta1.ReferenceGrant{},
+			"tlsroutes":               &gatewayapi_v1alpha2.TLSRoute{},
+			"grpcroutes":              &gatewayapi_v1.GRPCRoute{},
+			"tcproutes":               &gatewayapi_v1alpha2.TCPRoute{},
+			"backendtlspolicies":      &gatewayapi_v1alpha3.BackendTLSPolicy{},
+			"xbackendtrafficpolicies": &gatewayapi_x_v1alpha1.XBackendTrafficPolicy{},
+			"xlistenersets":           &gatewayapi_x_v1alpha1.XListenerSet{},
 		}
 
 		for _, disabled := range s.ctx.disabledFeatures {
 			delete(resources, disabled)
-
-			if disabled == "backendtlspolicies" {
-				// ConfigMaps are only watched because they're
-				// used by BackendTLSPolicies.
-				delete(resources, "configmaps")
-			}
+			delete(resources, disabledFeatureAliases[disabled])
 		}
 
 		for name, obj := range resources {
@@ -1041,6 +1223,7 @@ type dagBuilderConfig struct {
 	ingressClassNames                  []string
 	rootNamespaces                     []string
 	gatewayRef                         *types.NamespacedName
+	gatewayRefs                        []types.NamespacedName
 	disablePermitInsecure              bool
 	enableExternalNameService          bool
 	dnsLookupFamily                    contour_v1alpha1.ClusterDNSFamilyType
@@ -1060,9 +1243,89 @@ type dagBuilderConfig struct {
 	globalRateLimitService             *contour_v1alpha1.RateLimitServiceConfig
 	globalCircuitBreakerDefaults       *contour_v1alpha1.CircuitBreakers
 	upstreamTLS                        *dag.UpstreamTLS
+	certificateExpiryWarningPeriod     time.Duration
+	rejectExpiredCertificates          bool
+	requireOCSPStaple                  bool
+	incrementalRebuild                 bool
+	routeStats                         bool
 }
 
-func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
+// getDAGBuilderConfig returns the configuration of the DAG builder
+// generated from the given ContourConfigurationSpec.
+func (s *Server) getDAGBuilderConfig(contourConfiguration contour_v1alpha1.ContourConfigurationSpec, metrics *metrics.Metrics) (dagBuilderConfig, error) {
+	timeouts, err := contourconfig.ParseTimeoutPolicy(contourConfiguration.Envoy.Timeouts)
+	if err != nil {
+		return dagBuilderConfig{}, err
+	}
+
+	tlsCertificates := ptr.Deref(contourConfiguration.TLSCertificates, contour_v1alpha1.TLSCertificatesConfig{})
+	certificateExpiryWarningPeriod, err := time.ParseDuration(ptr.Deref(tlsCertificates.ExpiryWarningPeriod, "0s"))
+	if err != nil {
+		return dagBuilderConfig{}, fmt.Errorf("failed to parse TLS certificate expiry warning period: %w", err)
+	}
+
+	var ingressClassNames []string
+	if contourConfiguration.Ingress != nil {
+		ingressClassNames = contourConfiguration.Ingress.ClassNames
+	}
+
+	var clientCert *types.NamespacedName
+	var fallbackCert *types.NamespacedName
+	if contourConfiguration.Envoy.ClientCertificate != nil {
+		clientCert = &types.NamespacedName{Name: contourConfiguration.Envoy.ClientCertificate.Name, Namespace: contourConfiguration.Envoy.ClientCertificate.Namespace}
+	}
+	if contourConfiguration.HTTPProxy.FallbackCertificate != nil {
+		fallbackCert = &types.NamespacedName{Name: contourConfiguration.HTTPProxy.FallbackCertificate.Name, Namespace: contourConfiguration.HTTPProxy.FallbackCertificate.Namespace}
+	}
+
+	var gatewayRef *types.NamespacedName
+
+	if contourConfiguration.Gateway != nil && len(contourConfiguration.Gateway.Gateways) == 0 {
+		gatewayRef = &types.NamespacedName{
+			Namespace: contourConfiguration.Gateway.GatewayRef.Namespace,
+			Name:      contourConfiguration.Gateway.GatewayRef.Name,
+		}
+	}
+
+	return dagBuilderConfig{
+		ingressClassNames:                  ingressClassNames,
+		rootNamespaces:                     contourConfiguration.HTTPProxy.RootNamespaces,
+		gatewayRef:                         gatewayRef,
+		gatewayRefs:                        servedGatewayRefs(contourConfiguration.Gateway),
+		disablePermitInsecure:              *contourConfiguration.HTTPProxy.DisablePermitInsecure,
+		enableExternalNameService:          *contourConfiguration.EnableExternalNameService,
+		dnsLookupFamily:                    contourConfiguration.Envoy.Cluster.DNSLookupFamily,
+		headersPolicy:                      contourConfiguration.Policy,
+		clientCert:                         clientCert,
+		fallbackCert:                       fallbackCert,
+		connectTimeout:                     timeouts.ConnectTimeout,
+		client:                             s.mgr.GetClient(),
+		metrics:                            metrics,
+		httpAddress:                        contourConfiguration.Envoy.HTTPListener.Address,
+		httpPort:                           contourConfiguration.Envoy.HTTPListener.Port,
+		httpsAddress:                       contourConfiguration.Envoy.HTTPSListener.Address,
+		httpsPort:                          contourConfiguration.Envoy.HTTPSListener.Port,
+		globalExternalAuthorizationService: contourConfiguration.GlobalExternalAuthorization,
+		globalRateLimitService:             contourConfiguration.RateLimitService,
+		maxRequestsPerConnection:           contourConfiguration.Envoy.Cluster.MaxRequestsPerConnection,
+		perConnectionBufferLimitBytes:      contourConfiguration.Envoy.Cluster.PerConnectionBufferLimitBytes,
+		globalCircuitBreakerDefaults:       contourConfiguration.Envoy.Cluster.GlobalCircuitBreakerDefaults,
+		upstreamTLS: &dag.UpstreamTLS{
+			MinimumProtocolVersion: annotation.TLSVersion(contourConfiguration.Envoy.Cluster.UpstreamTLS.MinimumProtocolVersion, "1.2"),
+			MaximumProtocolVersion: annotation.TLSVersion(contourConfiguration.Envoy.Cluster.UpstreamTLS.MaximumProtocolVersion, "1.3"),
+			CipherSuites:           contourConfiguration.Envoy.Cluster.UpstreamTLS.SanitizedCipherSuites(),
+		},
+		certificateExpiryWarningPeriod: certificateExpiryWarningPeriod,
+		rejectExpiredCertificates:      ptr.Deref(tlsCertificates.RejectExpired, false),
+		requireOCSPStaple:              contourConfiguration.Envoy.Listener.TLS.OCSPStaplePolicy == contour_v1alpha1.MustStapleOCSPStaplePolicy,
+		incrementalRebuild:             contourConfiguration.FeatureFlags.IsIncrementalDAGRebuildEnabled(),
+		routeStats:                     contourConfiguration.FeatureFlags.IsRouteStatsEnabled(),
+	}, nil
+}
+
+// getDAGProcessors returns the DAG processors, in the order they
+// need to run, for the given configuration.
+func (s *Server) getDAGProcessors(dbc dagBuilderConfig) []dag.Processor {
 	var (
 		requestHeadersPolicy       dag.HeadersPolicy
 		responseHeadersPolicy      dag.HeadersPolicy
@@ -1156,10 +1419,11 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 			SetSourceMetadataOnRoutes:     true,
 			GlobalCircuitBreakerDefaults:  dbc.globalCircuitBreakerDefaults,
 			UpstreamTLS:                   dbc.upstreamTLS,
+			IncrementalRebuild:            dbc.incrementalRebuild,
 		},
 	}
 
-	if dbc.gatewayRef != nil {
+	if dbc.gatewayRef != nil || len(dbc.gatewayRefs) > 0 {
 		dagProcessors = append(dagProcessors, &dag.GatewayAPIProcessor{
 			EnableExternalNameService:     dbc.enableExternalNameService,
 			FieldLogger:                   s.log.WithField("context", "GatewayAPIProcessor"),
@@ -1172,6 +1436,16 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 		})
 	}
 
+	// The route stats processor names the statistics of the
+	// routes added by the other processors, so must run last.
+	if dbc.routeStats {
+		dagProcessors = append(dagProcessors, &dag.RouteStatsProcessor{})
+	}
+
+	return dagProcessors
+}
+
+func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 	var configuredSecretRefs []*types.NamespacedName
 	if dbc.fallbackCert != nil {
 		configuredSecretRefs = append(configuredSecretRefs, dbc.fallbackCert)
@@ -1182,15 +1456,19 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 
 	builder := &dag.Builder{
 		Source: dag.KubernetesCache{
-			RootNamespaces:           dbc.rootNamespaces,
-			IngressClassNames:        dbc.ingressClassNames,
-			ConfiguredGatewayToCache: dbc.gatewayRef,
-			ConfiguredSecretRefs:     configuredSecretRefs,
-			FieldLogger:              s.log.WithField("context", "KubernetesCache"),
-			Client:                   dbc.client,
-			Metrics:                  dbc.metrics,
+			RootNamespaces:                 dbc.rootNamespaces,
+			IngressClassNames:              dbc.ingressClassNames,
+			ConfiguredGatewayToCache:       dbc.gatewayRef,
+			ConfiguredGatewaysToCache:      dbc.gatewayRefs,
+			ConfiguredSecretRefs:           configuredSecretRefs,
+			CertificateExpiryWarningPeriod: dbc.certificateExpiryWarningPeriod,
+			RejectExpiredCertificates:      dbc.rejectExpiredCertificates,
+			RequireOCSPStaple:              dbc.requireOCSPStaple,
+			FieldLogger:                    s.log.WithField("context", "KubernetesCache"),
+			Client:                         dbc.client,
+			Metrics:                        dbc.metrics,
 		},
-		Processors: dagProcessors,
+		Processors: s.getDAGProcessors(dbc),
 		Metrics:    dbc.metrics,
 	}
 
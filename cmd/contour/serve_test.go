@@ -215,6 +215,19 @@ func TestGetDAGBuilder(t *testing.T) {
 		assert.EqualValues(t, ingressClassNames, got.Source.IngressClassNames)
 	})
 
+	t.Run("route stats enabled", func(t *testing.T) {
+		serve := &Server{
+			log: logrus.StandardLogger(),
+		}
+		got := serve.getDAGBuilder(dagBuilderConfig{
+			rootNamespaces:  []string{},
+			dnsLookupFamily: contour_v1alpha1.AutoClusterDNSFamily,
+			routeStats:      true,
+		})
+		require.Len(t, got.Processors, 5)
+		assert.IsType(t, &dag.RouteStatsProcessor{}, got.Processors[len(got.Processors)-1])
+	})
+
 	// TODO(3453): test additional properties of the DAG builder (processor fields, cache fields, Gateway tests (requires a client fake))
 }
 
@@ -303,6 +303,21 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 				Name:      ctx.Config.GatewayConfig.GatewayRef.Name,
 			},
 		}
+		for _, gw := range ctx.Config.GatewayConfig.Gateways {
+			gatewayConfig.Gateways = append(gatewayConfig.Gateways, contour_v1alpha1.ServedGateway{
+				GatewayRef: contour_v1alpha1.NamespacedName{
+					Namespace: gw.GatewayRef.Namespace,
+					Name:      gw.GatewayRef.Name,
+				},
+				EnvoyService: contour_v1alpha1.NamespacedName{
+					Namespace: gw.EnvoyService.Namespace,
+					Name:      gw.EnvoyService.Name,
+				},
+			})
+		}
+		if quorum := ctx.Config.GatewayConfig.DataPlaneReadyQuorum; quorum > 0 {
+			gatewayConfig.DataPlaneReadyQuorum = ptr.To(int32(quorum))
+		}
 	}
 
 	var cipherSuites []string
@@ -449,6 +464,16 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 		serverHeaderTransformation = contour_v1alpha1.PassThroughServerHeader
 	}
 
+	var ocspStaplePolicy contour_v1alpha1.OCSPStaplePolicy
+	switch ctx.Config.TLS.OCSPStaplePolicy {
+	case config.LenientOCSPStaplePolicy:
+		ocspStaplePolicy = contour_v1alpha1.LenientOCSPStaplePolicy
+	case config.StrictOCSPStaplePolicy:
+		ocspStaplePolicy = contour_v1alpha1.StrictOCSPStaplePolicy
+	case config.MustStapleOCSPStaplePolicy:
+		ocspStaplePolicy = contour_v1alpha1.MustStapleOCSPStaplePolicy
+	}
+
 	var globalExtAuth *contour_v1.AuthorizationServer
 	if ctx.Config.GlobalExternalAuthorization.ExtensionService != "" {
 		nsedName := k8s.NamespacedNameFrom(ctx.Config.GlobalExternalAuthorization.ExtensionService)
@@ -457,8 +482,13 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 				Name:      nsedName.Name,
 				Namespace: nsedName.Namespace,
 			},
-			ResponseTimeout: ctx.Config.GlobalExternalAuthorization.ResponseTimeout,
-			FailOpen:        ctx.Config.GlobalExternalAuthorization.FailOpen,
+			ResponseTimeout:         ctx.Config.GlobalExternalAuthorization.ResponseTimeout,
+			FailOpen:                ctx.Config.GlobalExternalAuthorization.FailOpen,
+			AllowedRequestHeaders:   ctx.Config.GlobalExternalAuthorization.AllowedRequestHeaders,
+			AllowedUpstreamHeaders:  ctx.Config.GlobalExternalAuthorization.AllowedUpstreamHeaders,
+			AllowedClientHeaders:    ctx.Config.GlobalExternalAuthorization.AllowedClientHeaders,
+			MetadataNamespaces:      ctx.Config.GlobalExternalAuthorization.MetadataNamespaces,
+			RouteMetadataNamespaces: ctx.Config.GlobalExternalAuthorization.RouteMetadataNamespaces,
 		}
 
 		if ctx.Config.GlobalExternalAuthorization.AuthPolicy != nil {
@@ -475,6 +505,12 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 				PackAsBytes:         ctx.Config.GlobalExternalAuthorization.WithRequestBody.PackAsBytes,
 			}
 		}
+
+		if ctx.Config.GlobalExternalAuthorization.HTTPService != nil {
+			globalExtAuth.HTTPService = &contour_v1.AuthorizationHTTPService{
+				PathPrefix: ctx.Config.GlobalExternalAuthorization.HTTPService.PathPrefix,
+			}
+		}
 	}
 
 	policy := &contour_v1alpha1.PolicyConfig{
@@ -505,6 +541,13 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 		}
 	}
 
+	tlsCertificates := &contour_v1alpha1.TLSCertificatesConfig{
+		RejectExpired: &ctx.Config.TLS.RejectExpiredCertificates,
+	}
+	if len(ctx.Config.TLS.CertificateExpiryWarningPeriod) > 0 {
+		tlsCertificates.ExpiryWarningPeriod = ptr.To(ctx.Config.TLS.CertificateExpiryWarningPeriod)
+	}
+
 	contourMetrics := contour_v1alpha1.MetricsConfig{
 		Address: ctx.metricsAddr,
 		Port:    ctx.metricsPort,
@@ -553,6 +596,7 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 					MinimumProtocolVersion: ctx.Config.TLS.MinimumProtocolVersion,
 					MaximumProtocolVersion: ctx.Config.TLS.MaximumProtocolVersion,
 					CipherSuites:           cipherSuites,
+					OCSPStaplePolicy:       ocspStaplePolicy,
 				},
 				SocketOptions: &contour_v1alpha1.SocketOptions{
 					TOS:          ctx.Config.Listener.SocketOptions.TOS,
@@ -616,6 +660,7 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 		Policy:                      policy,
 		Metrics:                     &contourMetrics,
 		Tracing:                     tracingConfig,
+		TLSCertificates:             tlsCertificates,
 		FeatureFlags:                ctx.Config.FeatureFlags,
 	}
 
@@ -504,6 +504,9 @@ func defaultContourConfiguration() contour_v1alpha1.ContourConfigurationSpec {
 			Address: "0.0.0.0",
 			Port:    8000,
 		},
+		TLSCertificates: &contour_v1alpha1.TLSCertificatesConfig{
+			RejectExpired: ptr.To(false),
+		},
 	}
 }
 
@@ -564,6 +567,30 @@ func TestConvertServeContext(t *testing.T) {
 				return cfg
 			},
 		},
+		"tls certificate expiry": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.TLS.CertificateExpiryWarningPeriod = "168h"
+				ctx.Config.TLS.RejectExpiredCertificates = true
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.TLSCertificates = &contour_v1alpha1.TLSCertificatesConfig{
+					ExpiryWarningPeriod: ptr.To("168h"),
+					RejectExpired:       ptr.To(true),
+				}
+				return cfg
+			},
+		},
+		"tls ocsp staple policy": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.TLS.OCSPStaplePolicy = config.MustStapleOCSPStaplePolicy
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.TLS.OCSPStaplePolicy = contour_v1alpha1.MustStapleOCSPStaplePolicy
+				return cfg
+			},
+		},
 		"gatewayapi": {
 			getServeContext: func(ctx *serveContext) *serveContext {
 				ctx.Config.GatewayConfig = &config.GatewayParameters{
@@ -584,6 +611,38 @@ func TestConvertServeContext(t *testing.T) {
 				return cfg
 			},
 		},
+		"gatewayapi multiple gateways": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.GatewayConfig = &config.GatewayParameters{
+					Gateways: []config.ServedGatewayParameters{{
+						GatewayRef: config.NamespacedName{
+							Namespace: "gateway-namespace",
+							Name:      "gateway-name",
+						},
+						EnvoyService: config.NamespacedName{
+							Namespace: "gateway-namespace",
+							Name:      "envoy-gateway-name",
+						},
+					}},
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Gateway = &contour_v1alpha1.GatewayConfig{
+					Gateways: []contour_v1alpha1.ServedGateway{{
+						GatewayRef: contour_v1alpha1.NamespacedName{
+							Namespace: "gateway-namespace",
+							Name:      "gateway-name",
+						},
+						EnvoyService: contour_v1alpha1.NamespacedName{
+							Namespace: "gateway-namespace",
+							Name:      "envoy-gateway-name",
+						},
+					}},
+				}
+				return cfg
+			},
+		},
 		"client certificate": {
 			getServeContext: func(ctx *serveContext) *serveContext {
 				ctx.Config.TLS.ClientCertificate = config.NamespacedName{
@@ -826,6 +885,39 @@ func TestConvertServeContext(t *testing.T) {
 				return cfg
 			},
 		},
+		"global external authorization http service and forwarding": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.GlobalExternalAuthorization = config.GlobalExternalAuthorization{
+					ExtensionService: "extauthns/extauthtext",
+					HTTPService: &config.GlobalAuthorizationHTTPService{
+						PathPrefix: "/verify",
+					},
+					AllowedRequestHeaders:   []string{"Cookie"},
+					AllowedUpstreamHeaders:  []string{"X-User"},
+					AllowedClientHeaders:    []string{"Location"},
+					MetadataNamespaces:      []string{"envoy.filters.http.jwt_authn"},
+					RouteMetadataNamespaces: []string{"example.com"},
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.GlobalExternalAuthorization = &contour_v1.AuthorizationServer{
+					ExtensionServiceRef: contour_v1.ExtensionServiceReference{
+						Name:      "extauthtext",
+						Namespace: "extauthns",
+					},
+					HTTPService: &contour_v1.AuthorizationHTTPService{
+						PathPrefix: "/verify",
+					},
+					AllowedRequestHeaders:   []string{"Cookie"},
+					AllowedUpstreamHeaders:  []string{"X-User"},
+					AllowedClientHeaders:    []string{"Location"},
+					MetadataNamespaces:      []string{"envoy.filters.http.jwt_authn"},
+					RouteMetadataNamespaces: []string{"example.com"},
+				}
+				return cfg
+			},
+		},
 		"tracing config normal": {
 			getServeContext: func(ctx *serveContext) *serveContext {
 				ctx.Config.Tracing = &config.Tracing{
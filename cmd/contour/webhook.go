@@ -0,0 +1,147 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	ctrl_webhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/webhook"
+	"github.com/projectcontour/contour/pkg/config"
+)
+
+// webhookConfig holds the configuration of the webhook subcommand.
+type webhookConfig struct {
+	// serveCtx holds the Contour configuration the objects
+	// being admitted are validated against.
+	serveCtx *serveContext
+
+	// address and port the webhook server will bind to.
+	address string
+	port    int
+
+	// certFile and keyFile are the paths of the serving certificate
+	// and key, which are reloaded when they change.
+	certFile string
+	keyFile  string
+}
+
+// registerWebhook registers the webhook subcommand and flags
+// with the Application provided.
+func registerWebhook(app *kingpin.Application) (*kingpin.CmdClause, *webhookConfig) {
+	cmd := app.Command("webhook", "Serve a validating admission webhook for Contour resources.")
+
+	cfg := &webhookConfig{
+		serveCtx: newServeContext(),
+		address:  "0.0.0.0",
+		port:     9443,
+		certFile: "/certs/tls.crt",
+		keyFile:  "/certs/tls.key",
+	}
+
+	var configFile string
+	parseConfig := func(_ *kingpin.ParseContext) error {
+		if cfg.serveCtx.contourConfigurationName != "" && configFile != "" {
+			return fmt.Errorf("cannot specify both %s and %s", "--contour-config-name", "-c/--config-path")
+		}
+
+		if configFile == "" {
+			return nil
+		}
+
+		f, err := os.Open(configFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		params, err := config.Parse(f)
+		if err != nil {
+			return err
+		}
+
+		if err := params.Validate(); err != nil {
+			return fmt.Errorf("invalid Contour configuration: %w", err)
+		}
+
+		cfg.serveCtx.Config = *params
+
+		return nil
+	}
+
+	cmd.Flag("address", "Address the webhook server will bind to.").PlaceHolder("<ipaddr>").Default(cfg.address).StringVar(&cfg.address)
+	cmd.Flag("cert-file", "Certificate file name for serving the webhook over TLS.").PlaceHolder("/path/to/file").Default(cfg.certFile).StringVar(&cfg.certFile)
+	cmd.Flag("config-path", "Path to base configuration.").Short('c').PlaceHolder("/path/to/file").Action(parseConfig).ExistingFileVar(&configFile)
+	cmd.Flag("contour-config-name", "Name of ContourConfiguration CRD.").PlaceHolder("contour").Action(parseConfig).StringVar(&cfg.serveCtx.contourConfigurationName)
+	cmd.Flag("incluster", "Use in cluster configuration.").BoolVar(&cfg.serveCtx.Config.InCluster)
+	cmd.Flag("key-file", "Key file name for serving the webhook over TLS.").PlaceHolder("/path/to/file").Default(cfg.keyFile).StringVar(&cfg.keyFile)
+	cmd.Flag("kubeconfig", "Path to kubeconfig (if not in running inside a cluster).").PlaceHolder("/path/to/file").StringVar(&cfg.serveCtx.Config.Kubeconfig)
+	cmd.Flag("port", "Port the webhook server will bind to.").PlaceHolder("<port>").Default("9443").IntVar(&cfg.port)
+
+	return cmd, cfg
+}
+
+// runWebhook runs the webhook subcommand.
+func runWebhook(log logrus.FieldLogger, cfg *webhookConfig) error {
+	// The webhook only reads from the API server,
+	// so every replica can serve requests.
+	cfg.serveCtx.LeaderElection.Disable = true
+
+	s, err := NewServer(log, cfg.serveCtx)
+	if err != nil {
+		return err
+	}
+
+	contourConfiguration, err := s.getConfig()
+	if err != nil {
+		return err
+	}
+
+	dbc, err := s.getDAGBuilderConfig(contourConfiguration, nil)
+	if err != nil {
+		return err
+	}
+
+	validator := webhook.NewValidator(
+		log.WithField("context", "webhook"),
+		s.mgr.GetClient(),
+		s.mgr.GetScheme(),
+		func() *dag.Builder { return s.getDAGBuilder(dbc) },
+	)
+
+	// The certificate watcher of the webhook server reloads the
+	// certificate and key when they are rotated, e.g. by certgen.
+	server := ctrl_webhook.NewServer(ctrl_webhook.Options{
+		Host:     cfg.address,
+		Port:     cfg.port,
+		CertDir:  filepath.Dir(cfg.certFile),
+		CertName: filepath.Base(cfg.certFile),
+		KeyName:  filepath.Base(cfg.keyFile),
+	})
+	server.Register("/validate", &ctrl_webhook.Admission{Handler: validator})
+
+	if err := s.mgr.Add(server); err != nil {
+		return err
+	}
+
+	log.WithField("address", fmt.Sprintf("%s:%d", cfg.address, cfg.port)).Info("starting webhook server")
+	return s.mgr.Start(signals.SetupSignalHandler())
+}
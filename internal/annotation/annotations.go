@@ -52,8 +52,26 @@ var annotationsByKind = map[string]map[string]struct{}{
 		"ingress.kubernetes.io/force-ssl-redirect":       {},
 		"kubernetes.io/ingress.allow-http":               {},
 		"kubernetes.io/ingress.class":                    {},
+		"projectcontour.io/cors-allow-credentials":       {},
+		"projectcontour.io/cors-allow-headers":           {},
+		"projectcontour.io/cors-allow-methods":           {},
+		"projectcontour.io/cors-allow-origin":            {},
+		"projectcontour.io/cors-expose-headers":          {},
+		"projectcontour.io/cors-max-age":                 {},
 		"projectcontour.io/ingress.class":                {},
+		"projectcontour.io/ip-allow-filter":              {},
+		"projectcontour.io/ip-deny-filter":               {},
+		"projectcontour.io/ip-filter-source":             {},
+		"projectcontour.io/load-balancer-strategy":       {},
+		"projectcontour.io/local-rate-limit-burst":       {},
+		"projectcontour.io/local-rate-limit-requests":    {},
+		"projectcontour.io/local-rate-limit-unit":        {},
 		"projectcontour.io/num-retries":                  {},
+		"projectcontour.io/path-rewrite":                 {},
+		"projectcontour.io/request-headers-remove":       {},
+		"projectcontour.io/request-headers-set":          {},
+		"projectcontour.io/response-headers-remove":      {},
+		"projectcontour.io/response-headers-set":         {},
 		"projectcontour.io/response-timeout":             {},
 		"projectcontour.io/retry-on":                     {},
 		"projectcontour.io/tls-minimum-protocol-version": {},
@@ -72,8 +90,9 @@ var annotationsByKind = map[string]map[string]struct{}{
 		"projectcontour.io/upstream-protocol.tls":    {},
 	},
 	"HTTPProxy": {
-		"kubernetes.io/ingress.class":     {},
-		"projectcontour.io/ingress.class": {},
+		"kubernetes.io/ingress.class":                {},
+		"projectcontour.io/ingress.class":            {},
+		"projectcontour.io/traffic-shift-error-rate": {},
 	},
 	"Secret": {
 		"projectcontour.io/generated-by-version": {},
@@ -104,6 +123,48 @@ func ContourAnnotation(o meta_v1.Object, key string) string {
 	return a["projectcontour.io/"+key]
 }
 
+// ContourAnnotationList returns the comma separated values of the given
+// annotation with the "projectcontour.io/" prefix. Whitespace around the
+// values is trimmed and empty values are omitted.
+func ContourAnnotationList(o meta_v1.Object, key string) []string {
+	var values []string
+	for _, v := range strings.Split(ContourAnnotation(o, key), ",") {
+		if value := strings.TrimSpace(v); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Header is an HTTP header name and value.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// ContourAnnotationHeaders parses the given annotation with the
+// "projectcontour.io/" prefix as a list of headers, one "Name: value"
+// pair per line. Blank lines are ignored.
+func ContourAnnotationHeaders(o meta_v1.Object, key string) ([]Header, error) {
+	var headers []Header
+	for _, line := range strings.Split(ContourAnnotation(o, key), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", strings.TrimSpace(line))
+		}
+
+		headers = append(headers, Header{
+			Name:  strings.TrimSpace(name),
+			Value: strings.TrimSpace(value),
+		})
+	}
+	return headers, nil
+}
+
 // ParseUInt32 parses the supplied string as if it were a uint32.
 // If the value is not present, or malformed, or outside uint32's range, zero is returned.
 func parseUInt32(s string) uint32 {
@@ -271,3 +332,15 @@ func MaxRetries(o meta_v1.Object) uint32 {
 func PerHostMaxConnections(o meta_v1.Object) uint32 {
 	return parseUInt32(ContourAnnotation(o, "per-host-max-connections"))
 }
+
+// TrafficShiftErrorRate returns the error rate of the canaries of an
+// HTTPProxy, as a percentage, set by an external system in the
+// "projectcontour.io/traffic-shift-error-rate" annotation, and whether
+// the annotation is set to a valid percentage.
+func TrafficShiftErrorRate(o meta_v1.Object) (float64, bool) {
+	rate, err := strconv.ParseFloat(ContourAnnotation(o, "traffic-shift-error-rate"), 64)
+	if err != nil || rate < 0 || rate > 100 {
+		return 0, false
+	}
+	return rate, true
+}
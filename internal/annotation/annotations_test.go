@@ -221,6 +221,82 @@ func TestParseUpstreamProtocols(t *testing.T) {
 	}
 }
 
+func TestContourAnnotationList(t *testing.T) {
+	tests := map[string]struct {
+		a    map[string]string
+		want []string
+	}{
+		"nada": {
+			a:    nil,
+			want: nil,
+		},
+		"empty with spaces": {
+			a:    map[string]string{"projectcontour.io/cors-allow-methods": ", ,"},
+			want: nil,
+		},
+		"multiple values": {
+			a:    map[string]string{"projectcontour.io/cors-allow-methods": "GET, POST,,OPTIONS "},
+			want: []string{"GET", "POST", "OPTIONS"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ing := &networking_v1.Ingress{
+				ObjectMeta: meta_v1.ObjectMeta{Annotations: tc.a},
+			}
+			assert.Equal(t, tc.want, ContourAnnotationList(ing, "cors-allow-methods"))
+		})
+	}
+}
+
+func TestContourAnnotationHeaders(t *testing.T) {
+	tests := map[string]struct {
+		a       map[string]string
+		want    []Header
+		wantErr string
+	}{
+		"nada": {
+			a:    nil,
+			want: nil,
+		},
+		"multiple headers": {
+			a: map[string]string{"projectcontour.io/request-headers-set": "X-Foo: bar\n\n  X-Url: http://example.com  \n"},
+			want: []Header{
+				{Name: "X-Foo", Value: "bar"},
+				{Name: "X-Url", Value: "http://example.com"},
+			},
+		},
+		"empty value": {
+			a:    map[string]string{"projectcontour.io/request-headers-set": "X-Foo:"},
+			want: []Header{{Name: "X-Foo", Value: ""}},
+		},
+		"missing colon": {
+			a:       map[string]string{"projectcontour.io/request-headers-set": "X-Foo bar"},
+			wantErr: `invalid header "X-Foo bar", expected "Name: value"`,
+		},
+		"missing name": {
+			a:       map[string]string{"projectcontour.io/request-headers-set": ": bar"},
+			wantErr: `invalid header ": bar", expected "Name: value"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ing := &networking_v1.Ingress{
+				ObjectMeta: meta_v1.ObjectMeta{Annotations: tc.a},
+			}
+			got, err := ContourAnnotationHeaders(ing, "request-headers-set")
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestWebsocketRoutes(t *testing.T) {
 	tests := map[string]struct {
 		a    *networking_v1.Ingress
@@ -390,6 +466,36 @@ func TestHttpAllowed(t *testing.T) {
 	}
 }
 
+func TestTrafficShiftErrorRate(t *testing.T) {
+	tests := map[string]struct {
+		value string
+		rate  float64
+		valid bool
+	}{
+		"not set":      {value: "", rate: 0, valid: false},
+		"integer":      {value: "5", rate: 5, valid: true},
+		"fraction":     {value: "0.25", rate: 0.25, valid: true},
+		"negative":     {value: "-1", rate: 0, valid: false},
+		"over 100":     {value: "101", rate: 0, valid: false},
+		"not a number": {value: "five", rate: 0, valid: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			proxy := &contour_v1.HTTPProxy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Annotations: map[string]string{
+						"projectcontour.io/traffic-shift-error-rate": tc.value,
+					},
+				},
+			}
+			rate, valid := TrafficShiftErrorRate(proxy)
+			assert.Equal(t, tc.rate, rate)
+			assert.Equal(t, tc.valid, valid)
+		})
+	}
+}
+
 func TestAnnotationCompat(t *testing.T) {
 	tests := map[string]struct {
 		svc   *core_v1.Service
@@ -26,13 +26,16 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 
-	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/pkg/certs"
 )
 
 const (
 	// CACertificateKey is the dictionary key for the CA certificate bundle.
 	CACertificateKey = "cacert.pem"
+	// TLSCACertificateKey is the dictionary key for the CA certificate
+	// bundle in the compact format Contour & Envoy Secrets. It matches
+	// the key Contour reads CA bundles from.
+	TLSCACertificateKey = "ca.crt"
 	// ContourCertificateKey is the dictionary key for the Contour certificate.
 	ContourCertificateKey = "contourcert.pem"
 	// ContourPrivateKeyKey is the dictionary key for the Contour private key.
@@ -127,7 +130,7 @@ func WriteSecretsYAML(outputDir string, secrets []*core_v1.Secret, force Overwri
 
 // WriteSecretsKube writes all the keypairs out to Kubernetes Secrets in the
 // compact format which is compatible with Secrets generated by cert-manager.
-func WriteSecretsKube(client *kubernetes.Clientset, secrets []*core_v1.Secret, force OverwritePolicy) error {
+func WriteSecretsKube(client kubernetes.Interface, secrets []*core_v1.Secret, force OverwritePolicy) error {
 	for _, s := range secrets {
 		if _, err := client.CoreV1().Secrets(s.Namespace).Create(context.TODO(), s, meta_v1.CreateOptions{}); err != nil {
 			if !k8serrors.IsAlreadyExists(err) {
@@ -166,7 +169,7 @@ func AsSecrets(namespace, nameSuffix string, certdata *certs.Certificates) ([]*c
 			"contourcert"+nameSuffix,
 			namespace,
 			map[string][]byte{
-				dag.CACertificateKey:     certdata.CACertificate,
+				TLSCACertificateKey:      certdata.CACertificate,
 				core_v1.TLSCertKey:       certdata.ContourCertificate,
 				core_v1.TLSPrivateKeyKey: certdata.ContourPrivateKey,
 			}),
@@ -175,7 +178,7 @@ func AsSecrets(namespace, nameSuffix string, certdata *certs.Certificates) ([]*c
 			"envoycert"+nameSuffix,
 			namespace,
 			map[string][]byte{
-				dag.CACertificateKey:     certdata.CACertificate,
+				TLSCACertificateKey:      certdata.CACertificate,
 				core_v1.TLSCertKey:       certdata.EnvoyCertificate,
 				core_v1.TLSPrivateKeyKey: certdata.EnvoyPrivateKey,
 			}),
@@ -0,0 +1,202 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certgen
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	core_v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/projectcontour/contour/pkg/certs"
+)
+
+const (
+	// CertificateExpiryGauge is the name of the metric holding the
+	// expiry time of the certificates managed by certgen.
+	CertificateExpiryGauge = "contour_certgen_certificate_expiry_timestamp_seconds"
+)
+
+// RotationAction describes the change made to a set of certificates
+// by Rotate.
+type RotationAction string
+
+const (
+	// RotationNone means the certificates are valid and were not changed.
+	RotationNone RotationAction = "none"
+	// RotationGenerate means no certificates existed and a new CA
+	// and new Contour & Envoy certificates were generated.
+	RotationGenerate RotationAction = "generate"
+	// RotationRenew means the Contour & Envoy certificates were
+	// re-issued from the existing CA.
+	RotationRenew RotationAction = "renew"
+	// RotationRolloverCA means a new CA was generated and added to
+	// the CA bundle. The Contour & Envoy certificates are re-issued
+	// from the new CA on the next rotation.
+	RotationRolloverCA RotationAction = "rollover-ca"
+)
+
+// Rotate checks the given certificates and returns the certificates that
+// should replace them, along with the action taken. Certificates expiring
+// within renewBefore of now are renewed. current may be nil, in which case
+// a new set of certificates is generated.
+//
+// The CA is rolled over in two steps to keep the xDS connection up: first
+// the new CA is added to the CA bundle next to the old one, then, once
+// the bundle has been distributed, the Contour & Envoy certificates are
+// re-issued from the new CA.
+func Rotate(config *certs.Configuration, current *certs.Certificates, renewBefore time.Duration, now time.Time) (*certs.Certificates, RotationAction, error) {
+	if current == nil ||
+		len(current.CACertificate) == 0 ||
+		len(current.ContourCertificate) == 0 ||
+		len(current.EnvoyCertificate) == 0 {
+		generated, err := certs.GenerateCerts(config)
+		return generated, RotationGenerate, err
+	}
+
+	caExpiry, err := certs.CertificateExpiry(current.CACertificate)
+	if err != nil {
+		return nil, RotationNone, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+
+	// Without the CA private key we can't issue new certificates
+	// from the existing CA, so roll over to a CA we have the key for.
+	if len(current.CAPrivateKey) == 0 || !now.Add(renewBefore).Before(caExpiry) {
+		rotated, err := certs.RotateCA(config, current)
+		return rotated, RotationRolloverCA, err
+	}
+
+	for _, cert := range [][]byte{current.ContourCertificate, current.EnvoyCertificate} {
+		expiry, err := certs.CertificateExpiry(cert)
+		if err != nil || !now.Add(renewBefore).Before(expiry) || !certs.IssuedBy(cert, current.CACertificate) {
+			renewed, err := certs.RenewCerts(config, current.CACertificate, current.CAPrivateKey)
+			return renewed, RotationRenew, err
+		}
+	}
+
+	return current, RotationNone, nil
+}
+
+// AsCASecret transforms the CA of the given Certificates struct into a
+// Secret holding the CA bundle and private key, so that later rotations
+// can issue new certificates from the same CA.
+func AsCASecret(namespace, nameSuffix string, certdata *certs.Certificates) (*core_v1.Secret, []error) {
+	if errs := validateSecretNamespaceAndName(namespace, "contourca"+nameSuffix); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return newSecret(
+		core_v1.SecretTypeTLS,
+		"contourca"+nameSuffix,
+		namespace,
+		map[string][]byte{
+			core_v1.TLSCertKey:       certdata.CACertificate,
+			core_v1.TLSPrivateKeyKey: certdata.CAPrivateKey,
+		}), nil
+}
+
+// ReadSecretsKube reads the certificates back from the Secrets written by
+// WriteSecretsKube, in either the legacy or compact format. It returns nil
+// if the Contour or Envoy Secret does not exist.
+func ReadSecretsKube(client kubernetes.Interface, namespace, nameSuffix string) (*certs.Certificates, error) {
+	get := func(name string) (*core_v1.Secret, error) {
+		secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), name+nameSuffix, meta_v1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return secret, err
+	}
+
+	contour, err := get("contourcert")
+	if err != nil || contour == nil {
+		return nil, err
+	}
+	envoy, err := get("envoycert")
+	if err != nil || envoy == nil {
+		return nil, err
+	}
+
+	certdata := &certs.Certificates{
+		CACertificate:      contour.Data[TLSCACertificateKey],
+		ContourCertificate: contour.Data[core_v1.TLSCertKey],
+		ContourPrivateKey:  contour.Data[core_v1.TLSPrivateKeyKey],
+		EnvoyCertificate:   envoy.Data[core_v1.TLSCertKey],
+		EnvoyPrivateKey:    envoy.Data[core_v1.TLSPrivateKeyKey],
+	}
+
+	// Legacy format Secrets keep the CA bundle in a separate Secret.
+	if len(certdata.CACertificate) == 0 {
+		ca, err := get("cacert")
+		if err != nil {
+			return nil, err
+		}
+		if ca != nil {
+			certdata.CACertificate = ca.Data[CACertificateKey]
+		}
+	}
+
+	// Only use the stored CA private key if it belongs to the CA
+	// heading the bundle that the certificates are verified against.
+	ca, err := get("contourca")
+	if err != nil {
+		return nil, err
+	}
+	if ca != nil && certs.IssuedBy(ca.Data[core_v1.TLSCertKey], certdata.CACertificate) {
+		certdata.CAPrivateKey = ca.Data[core_v1.TLSPrivateKeyKey]
+	}
+
+	return certdata, nil
+}
+
+// ExpiryMetrics records the expiry time of the certificates managed by certgen.
+type ExpiryMetrics struct {
+	expiry *prometheus.GaugeVec
+}
+
+// NewExpiryMetrics creates a new ExpiryMetrics and registers it with the
+// supplied registry.
+func NewExpiryMetrics(registry *prometheus.Registry) *ExpiryMetrics {
+	m := &ExpiryMetrics{
+		expiry: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: CertificateExpiryGauge,
+				Help: "Expiry time of the certificates managed by certgen, in seconds since the Unix epoch.",
+			},
+			[]string{"certificate"},
+		),
+	}
+	registry.MustRegister(m.expiry)
+	return m
+}
+
+// Set records the expiry time of each certificate in certdata.
+func (m *ExpiryMetrics) Set(certdata *certs.Certificates) {
+	for name, cert := range map[string][]byte{
+		"ca":      certdata.CACertificate,
+		"contour": certdata.ContourCertificate,
+		"envoy":   certdata.EnvoyCertificate,
+	} {
+		expiry, err := certs.CertificateExpiry(cert)
+		if err != nil {
+			m.expiry.DeleteLabelValues(name)
+			continue
+		}
+		m.expiry.WithLabelValues(name).Set(float64(expiry.Unix()))
+	}
+}
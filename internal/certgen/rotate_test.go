@@ -0,0 +1,122 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/projectcontour/contour/pkg/certs"
+)
+
+func TestRotate(t *testing.T) {
+	generated, err := certs.GenerateCerts(&certs.Configuration{Lifetime: 365})
+	require.NoError(t, err)
+
+	withoutCAKey := *generated
+	withoutCAKey.CAPrivateKey = nil
+
+	now := time.Now()
+
+	tests := map[string]struct {
+		current     *certs.Certificates
+		renewBefore time.Duration
+		now         time.Time
+		want        RotationAction
+	}{
+		"no certificates": {
+			current: nil,
+			want:    RotationGenerate,
+		},
+		"valid certificates": {
+			current:     generated,
+			renewBefore: 30 * 24 * time.Hour,
+			now:         now,
+			want:        RotationNone,
+		},
+		"certificates close to expiry": {
+			current:     generated,
+			renewBefore: 30 * 24 * time.Hour,
+			now:         now.Add(340 * 24 * time.Hour),
+			want:        RotationRolloverCA,
+		},
+		"no CA private key": {
+			current:     &withoutCAKey,
+			renewBefore: 30 * 24 * time.Hour,
+			now:         now,
+			want:        RotationRolloverCA,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, action, err := Rotate(&certs.Configuration{}, tc.current, tc.renewBefore, tc.now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, action)
+			assert.NotNil(t, got)
+		})
+	}
+}
+
+func TestRotateRenewsFromRolledOverCA(t *testing.T) {
+	generated, err := certs.GenerateCerts(&certs.Configuration{})
+	require.NoError(t, err)
+
+	rolledOver, err := certs.RotateCA(&certs.Configuration{}, generated)
+	require.NoError(t, err)
+
+	renewed, action, err := Rotate(&certs.Configuration{}, rolledOver, time.Hour, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, RotationRenew, action)
+	assert.True(t, certs.IssuedBy(renewed.ContourCertificate, rolledOver.CACertificate))
+	assert.True(t, certs.IssuedBy(renewed.EnvoyCertificate, rolledOver.CACertificate))
+
+	_, action, err = Rotate(&certs.Configuration{}, renewed, time.Hour, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, RotationNone, action)
+}
+
+func TestReadSecretsKube(t *testing.T) {
+	generated, err := certs.GenerateCerts(&certs.Configuration{})
+	require.NoError(t, err)
+
+	caSecret, errs := AsCASecret("projectcontour", "", generated)
+	require.Empty(t, errs)
+
+	for name, format := range map[string]func(string, string, *certs.Certificates) ([]*core_v1.Secret, []error){
+		"compact": AsSecrets,
+		"legacy":  AsLegacySecrets,
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewClientset()
+
+			got, err := ReadSecretsKube(client, "projectcontour", "")
+			require.NoError(t, err)
+			assert.Nil(t, got)
+
+			secrets, errs := format("projectcontour", "", generated)
+			require.Empty(t, errs)
+			require.NoError(t, WriteSecretsKube(client, append(secrets, caSecret), NoOverwrite))
+
+			got, err = ReadSecretsKube(client, "projectcontour", "")
+			require.NoError(t, err)
+			assert.Equal(t, generated, got)
+		})
+	}
+}
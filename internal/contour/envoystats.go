@@ -0,0 +1,148 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	envoy_admin_v3 "github.com/envoyproxy/go-control-plane/envoy/admin/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/projectcontour/contour/internal/xds"
+)
+
+// UpstreamRequestCounter counts the requests Envoy sent to the
+// canary services of traffic shifts.
+type UpstreamRequestCounter interface {
+	// CanaryRequests returns the number of requests the Envoys sent
+	// to the canary service of the traffic shift of the HTTPProxy
+	// from the stable service that completed, and how many of them
+	// failed.
+	CanaryRequests(ctx context.Context, proxy types.NamespacedName, stable, canary string) (completed, failed uint64, err error)
+}
+
+// NodeAddresser returns the addresses of the connected Envoy nodes.
+type NodeAddresser interface {
+	NodeAddresses() []string
+}
+
+// EnvoyStatsScraper is an UpstreamRequestCounter reading the
+// request stats of the endpoints of the canary service of a
+// traffic shift from the clusters endpoint of each connected Envoy.
+type EnvoyStatsScraper struct {
+	nodes  NodeAddresser
+	port   int
+	client *http.Client
+}
+
+// NewEnvoyStatsScraper returns an EnvoyStatsScraper reading the stats
+// of the Envoy nodes over plain HTTP on the given port.
+func NewEnvoyStatsScraper(nodes NodeAddresser, port int) *EnvoyStatsScraper {
+	return &EnvoyStatsScraper{
+		nodes:  nodes,
+		port:   port,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// CanaryRequests sums the rq_success and rq_error stats of the
+// endpoints of the canary service in the clusters of the traffic
+// shift over all connected Envoys. The services of a shift are each
+// a locality of the ClusterLoadAssignment of the shift, so only the
+// requests the shift sent to the canary are counted.
+func (e *EnvoyStatsScraper) CanaryRequests(ctx context.Context, proxy types.NamespacedName, stable, canary string) (uint64, uint64, error) {
+	addrs := e.nodes.NodeAddresses()
+	if len(addrs) == 0 {
+		return 0, 0, errors.New("no Envoy is connected")
+	}
+
+	prefix := xds.TrafficShiftClusterLoadAssignmentPrefix(proxy, stable, canary)
+	subZone := xds.LocalitySubZone(types.NamespacedName{Namespace: proxy.Namespace, Name: canary})
+
+	var completed, failed uint64
+	for _, addr := range addrs {
+		clusters, err := e.scrape(ctx, addr)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, cluster := range clusters.GetClusterStatuses() {
+			if !strings.HasPrefix(cluster.GetEdsServiceName(), prefix) {
+				continue
+			}
+			for _, host := range cluster.GetHostStatuses() {
+				if host.GetLocality().GetSubZone() != subZone {
+					continue
+				}
+				for _, stat := range host.GetStats() {
+					switch stat.GetName() {
+					case "rq_success":
+						completed += stat.GetValue()
+					case "rq_error":
+						completed += stat.GetValue()
+						failed += stat.GetValue()
+					}
+				}
+			}
+		}
+	}
+
+	return completed, failed, nil
+}
+
+// scrape returns the status of the clusters of the Envoy at addr.
+func (e *EnvoyStatsScraper) scrape(ctx context.Context, addr string) (*envoy_admin_v3.Clusters, error) {
+	u := url.URL{
+		Scheme:   "http",
+		Host:     net.JoinHostPort(addr, strconv.Itoa(e.port)),
+		Path:     "/clusters",
+		RawQuery: url.Values{"format": {"json"}}.Encode(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clusters of Envoy %s: %w", addr, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read clusters of Envoy %s: %s", addr, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clusters of Envoy %s: %w", addr, err)
+	}
+
+	clusters := &envoy_admin_v3.Clusters{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, clusters); err != nil {
+		return nil, fmt.Errorf("failed to parse clusters of Envoy %s: %w", addr, err)
+	}
+
+	return clusters, nil
+}
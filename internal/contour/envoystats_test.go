@@ -0,0 +1,140 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	envoy_admin_v3 "github.com/envoyproxy/go-control-plane/envoy/admin/v3"
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fakeNodeAddresser []string
+
+func (f fakeNodeAddresser) NodeAddresses() []string {
+	return f
+}
+
+func TestEnvoyStatsScraper(t *testing.T) {
+	host := func(subZone string, success, errors uint64) *envoy_admin_v3.HostStatus {
+		return &envoy_admin_v3.HostStatus{
+			Locality: &envoy_config_core_v3.Locality{SubZone: subZone},
+			Stats: []*envoy_admin_v3.SimpleMetric{
+				{Name: "rq_success", Value: success, Type: envoy_admin_v3.SimpleMetric_COUNTER},
+				{Name: "rq_error", Value: errors, Type: envoy_admin_v3.SimpleMetric_COUNTER},
+				{Name: "rq_total", Value: success + errors, Type: envoy_admin_v3.SimpleMetric_COUNTER},
+				{Name: "rq_active", Value: 7, Type: envoy_admin_v3.SimpleMetric_GAUGE},
+			},
+		}
+	}
+
+	clusters := &envoy_admin_v3.Clusters{
+		ClusterStatuses: []*envoy_admin_v3.ClusterStatus{{
+			// The shift, whose canary endpoints count.
+			Name:           "default/kuard/80/f769ec0e84",
+			EdsServiceName: "trafficshift/default/www.example.com/kuard/kuard-canary/80/8080",
+			HostStatuses: []*envoy_admin_v3.HostStatus{
+				host("default/kuard", 1000, 100),
+				host("default/kuard-canary", 97, 3),
+				host("default/kuard-canary", 48, 2),
+			},
+		}, {
+			// The same shift, between other ports.
+			Name:           "default/kuard/443/0a1b2c3d4e",
+			EdsServiceName: "trafficshift/default/www.example.com/kuard/kuard-canary/443/8443",
+			HostStatuses: []*envoy_admin_v3.HostStatus{
+				host("default/kuard-canary", 50, 0),
+			},
+		}, {
+			// The canary, routed to without the shift.
+			Name:           "default/kuard-canary/8080/da39a3ee5e",
+			EdsServiceName: "default/kuard-canary",
+			HostStatuses: []*envoy_admin_v3.HostStatus{
+				host("", 1000, 1000),
+			},
+		}, {
+			// A shift of another HTTPProxy.
+			Name:           "default/kuard/80/0123456789",
+			EdsServiceName: "trafficshift/default/other/kuard/kuard-canary/80/8080",
+			HostStatuses: []*envoy_admin_v3.HostStatus{
+				host("default/kuard-canary", 1000, 1000),
+			},
+		}},
+	}
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/clusters", r.URL.Path)
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+
+		body, err := protojson.Marshal(clusters)
+		assert.NoError(t, err)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	addr, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	// Both Envoys are served by the test server, so each stat counts twice.
+	scraper := NewEnvoyStatsScraper(fakeNodeAddresser{addr, addr}, p)
+
+	completed, failed, err := scraper.CanaryRequests(context.Background(),
+		types.NamespacedName{Namespace: "default", Name: "www.example.com"}, "kuard", "kuard-canary")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(400), completed)
+	assert.Equal(t, uint64(10), failed)
+	assert.Equal(t, 2, requests)
+
+	_, _, err = NewEnvoyStatsScraper(fakeNodeAddresser{}, p).CanaryRequests(context.Background(),
+		types.NamespacedName{Namespace: "default", Name: "www.example.com"}, "kuard", "kuard-canary")
+	require.Error(t, err)
+}
+
+func TestEnvoyStatsScraperError(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"unavailable": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+		"not json": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("default/kuard/80/da39a3ee5e::observability_name::default_kuard_80\n"))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+			require.NoError(t, err)
+			p, err := strconv.Atoi(port)
+			require.NoError(t, err)
+
+			_, _, err = NewEnvoyStatsScraper(fakeNodeAddresser{host}, p).CanaryRequests(context.Background(),
+				types.NamespacedName{Namespace: "default", Name: "kuard"}, "kuard", "kuard-canary")
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+// DefaultWarningEventInterval is the default minimum interval between
+// Warning events for the same problem. Events expire after an hour by
+// default, so a problem that persists is recorded again before its
+// previous event is removed.
+const DefaultWarningEventInterval = 30 * time.Minute
+
+// warningKey identifies a problem with an object.
+type warningKey struct {
+	kind    string
+	name    types.NamespacedName
+	uid     types.UID
+	reason  string
+	message string
+}
+
+// WarningEventObserver is a dag.Observer that records the warnings
+// in the status cache of each DAG as Warning events on the Ingress,
+// HTTPProxy, Gateway API route or ExtensionService they apply to.
+//
+// A warning is recorded when it first appears and, while it persists
+// across DAG rebuilds, again at most once per interval. Warnings are
+// only recorded once this Contour is elected leader, so replicas do
+// not record duplicate events.
+type WarningEventObserver struct {
+	recorder record.EventRecorder
+	interval time.Duration
+
+	// enabled becomes ready to read when this Contour becomes the leader.
+	enabled chan struct{}
+
+	// recorded holds the time each warning of the last DAG was recorded.
+	recorded map[warningKey]time.Time
+
+	// now returns the current time, overridden in tests.
+	now func() time.Time
+
+	nextObserver dag.Observer
+}
+
+func NewWarningEventObserver(recorder record.EventRecorder, interval time.Duration, nextObserver dag.Observer) *WarningEventObserver {
+	return &WarningEventObserver{
+		recorder:     recorder,
+		interval:     interval,
+		enabled:      make(chan struct{}),
+		recorded:     map[warningKey]time.Time{},
+		now:          time.Now,
+		nextObserver: nextObserver,
+	}
+}
+
+// OnElectedLeader implements leadership.NeedLeaderElectionNotification.
+func (w *WarningEventObserver) OnElectedLeader() {
+	close(w.enabled)
+}
+
+func (w *WarningEventObserver) OnChange(d *dag.DAG) {
+	w.nextObserver.OnChange(d)
+
+	select {
+	case <-w.enabled:
+	default:
+		return
+	}
+
+	now := w.now()
+
+	// Only the warnings of this DAG are kept, so a problem that is
+	// fixed and then reintroduced is recorded again straight away.
+	recorded := make(map[warningKey]time.Time)
+	for _, warning := range d.StatusCache.GetWarnings() {
+		key := warningKey{
+			kind:    k8s.KindOf(warning.Object),
+			name:    k8s.NamespacedNameOf(warning.Object),
+			uid:     warning.Object.GetUID(),
+			reason:  warning.Reason,
+			message: warning.Message,
+		}
+
+		if _, ok := recorded[key]; ok {
+			continue
+		}
+
+		if last, ok := w.recorded[key]; ok && now.Sub(last) < w.interval {
+			recorded[key] = last
+			continue
+		}
+
+		w.recorder.Event(warning.Object, core_v1.EventTypeWarning, warning.Reason, warning.Message)
+		recorded[key] = now
+	}
+
+	w.recorded = recorded
+}
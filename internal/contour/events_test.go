@@ -0,0 +1,93 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	networking_v1 "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/status"
+)
+
+func TestWarningEventObserver(t *testing.T) {
+	ingress := &networking_v1.Ingress{
+		ObjectMeta: fixture.ObjectMeta("default/kuard"),
+	}
+
+	buildDAG := func(messages ...string) *dag.DAG {
+		d := &dag.DAG{
+			StatusCache: status.NewCache(""),
+		}
+		for _, msg := range messages {
+			d.StatusCache.Warn(ingress, "RouteNotValid", msg)
+		}
+		return d
+	}
+
+	recorder := record.NewFakeRecorder(100)
+	var changes int
+	observer := NewWarningEventObserver(recorder, time.Minute, dag.ObserverFunc(func(*dag.DAG) {
+		changes++
+	}))
+
+	now := time.Now()
+	observer.now = func() time.Time { return now }
+
+	events := func() []string {
+		var events []string
+		for {
+			select {
+			case e := <-recorder.Events:
+				events = append(events, e)
+			default:
+				return events
+			}
+		}
+	}
+
+	// Warnings are not recorded until elected leader.
+	observer.OnChange(buildDAG("a"))
+	assert.Equal(t, 1, changes)
+	assert.Empty(t, events())
+
+	observer.OnElectedLeader()
+
+	// New warnings are recorded, once per DAG.
+	observer.OnChange(buildDAG("a", "a", "b"))
+	assert.Equal(t, []string{"Warning RouteNotValid a", "Warning RouteNotValid b"}, events())
+
+	// Persisting warnings are not recorded again within the interval.
+	now = now.Add(30 * time.Second)
+	observer.OnChange(buildDAG("a", "b", "c"))
+	assert.Equal(t, []string{"Warning RouteNotValid c"}, events())
+
+	// Persisting warnings are recorded again after the interval.
+	now = now.Add(45 * time.Second)
+	observer.OnChange(buildDAG("a", "b", "c"))
+	assert.Equal(t, []string{"Warning RouteNotValid a", "Warning RouteNotValid b"}, events())
+
+	// Fixed warnings are recorded straight away if they reappear.
+	observer.OnChange(buildDAG("a"))
+	assert.Empty(t, events())
+	observer.OnChange(buildDAG("a", "c"))
+	assert.Equal(t, []string{"Warning RouteNotValid c"}, events())
+
+	assert.Equal(t, 6, changes)
+}
@@ -0,0 +1,229 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/status"
+)
+
+// AckCounter counts the Envoy nodes that have acknowledged
+// the xDS configuration.
+type AckCounter interface {
+	// NodesAcked returns the number of Envoy nodes, of the given
+	// node cluster if not empty, that have ACKed a snapshot set at
+	// or after since for the given type URLs.
+	NodesAcked(cluster string, since time.Time, typeURLs ...string) int
+
+	// SnapshotVersion returns the version of the latest snapshot
+	// set for the given type URLs, and when it was set.
+	SnapshotVersion(typeURLs ...string) (string, time.Time)
+}
+
+// gatewayReadinessTypeURLs are the xDS resource types that must be
+// acknowledged for a Gateway's Listeners to be considered programmed.
+var gatewayReadinessTypeURLs = []string{
+	envoy_resource_v3.ListenerType,
+	envoy_resource_v3.RouteType,
+	envoy_resource_v3.ClusterType,
+	envoy_resource_v3.SecretType,
+}
+
+// GatewayReadinessObserver is a dag.Observer that adds a DataPlaneReady
+// condition to the status of programmed Gateways, reporting whether a
+// quorum of Envoys have acknowledged the latest snapshot, which holds
+// the Gateway's current configuration. A new generation of the Gateway,
+// or a change to its routes, Secrets or backends, sets a new snapshot
+// that has to be acknowledged again. Run with Start, it also updates
+// the condition once the quorum is reached, without waiting for the
+// next DAG rebuild.
+type GatewayReadinessObserver struct {
+	log           logrus.FieldLogger
+	acks          AckCounter
+	quorum        int
+	statusUpdater k8s.StatusUpdater
+	pollInterval  time.Duration
+
+	// nodeGroups is set when several Gateways are served, each by the
+	// Envoys whose node cluster is the Gateway's namespace/name. Only
+	// those Envoys are then counted towards the Gateway's quorum.
+	nodeGroups bool
+
+	// nextObserver contains the stack of dag.Observers that act on DAG rebuilds.
+	nextObserver dag.Observer
+
+	mu       sync.Mutex
+	gateways map[types.NamespacedName]*gatewayReadiness
+}
+
+// gatewayReadiness holds the readiness of a Gateway's generation
+// in a snapshot version.
+type gatewayReadiness struct {
+	generation int64
+	version    string
+
+	// since is when the snapshot version was set.
+	since time.Time
+	ready bool
+}
+
+// NewGatewayReadinessObserver returns a GatewayReadinessObserver requiring
+// quorum Envoys to ACK a Gateway's configuration. If nodeGroups is set,
+// only the Envoys of the Gateway's node group are counted.
+func NewGatewayReadinessObserver(log logrus.FieldLogger, acks AckCounter, quorum int, nodeGroups bool, statusUpdater k8s.StatusUpdater, nextObserver dag.Observer) *GatewayReadinessObserver {
+	return &GatewayReadinessObserver{
+		log:           log,
+		acks:          acks,
+		quorum:        quorum,
+		statusUpdater: statusUpdater,
+		nodeGroups:    nodeGroups,
+		pollInterval:  time.Second,
+		nextObserver:  nextObserver,
+		gateways:      map[types.NamespacedName]*gatewayReadiness{},
+	}
+}
+
+func (g *GatewayReadinessObserver) OnChange(d *dag.DAG) {
+	// The next observers set the xDS snapshot built from this DAG,
+	// or keep the last one if the configuration did not change.
+	g.nextObserver.OnChange(d)
+	version, since := g.acks.SnapshotVersion(gatewayReadinessTypeURLs...)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	seen := map[types.NamespacedName]bool{}
+
+	for _, gu := range d.StatusCache.GetGatewayUpdates() {
+		if cond, ok := gu.Conditions[gatewayapi_v1.GatewayConditionProgrammed]; !ok || cond.Status != meta_v1.ConditionTrue {
+			continue
+		}
+		seen[gu.FullName] = true
+
+		readiness, ok := g.gateways[gu.FullName]
+		if !ok || readiness.generation != gu.Generation || readiness.version != version {
+			readiness = &gatewayReadiness{
+				generation: gu.Generation,
+				version:    version,
+				since:      since,
+			}
+			g.gateways[gu.FullName] = readiness
+		}
+
+		if !readiness.ready && readiness.version != "" {
+			readiness.ready = g.nodesAcked(gu.FullName, readiness.since) >= g.quorum
+		}
+
+		condStatus, reason, message := g.condition(readiness.ready)
+		gu.AddCondition(status.GatewayConditionDataPlaneReady, condStatus, reason, message)
+	}
+
+	for name := range g.gateways {
+		if !seen[name] {
+			delete(g.gateways, name)
+		}
+	}
+}
+
+// nodesAcked returns the number of the Gateway's Envoys that have ACKed
+// a snapshot set at or after since.
+func (g *GatewayReadinessObserver) nodesAcked(gateway types.NamespacedName, since time.Time) int {
+	var cluster string
+	if g.nodeGroups {
+		cluster = gateway.String()
+	}
+	return g.acks.NodesAcked(cluster, since, gatewayReadinessTypeURLs...)
+}
+
+// condition returns the status, reason and message of the DataPlaneReady condition.
+func (g *GatewayReadinessObserver) condition(ready bool) (meta_v1.ConditionStatus, gatewayapi_v1.GatewayConditionReason, string) {
+	if ready {
+		return meta_v1.ConditionTrue, status.GatewayReasonAcknowledged,
+			fmt.Sprintf("Configuration acknowledged by at least %d Envoy(s)", g.quorum)
+	}
+	return meta_v1.ConditionFalse, status.GatewayReasonPending,
+		fmt.Sprintf("Waiting for %d Envoy(s) to acknowledge the configuration", g.quorum)
+}
+
+func (g *GatewayReadinessObserver) NeedLeaderElection() bool {
+	return true
+}
+
+// Start polls the Gateways waiting for a quorum of Envoys to ACK their
+// configuration, and updates their DataPlaneReady condition once it is.
+func (g *GatewayReadinessObserver) Start(ctx context.Context) error {
+	ticker := time.NewTicker(g.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			g.updateReadiness()
+		}
+	}
+}
+
+func (g *GatewayReadinessObserver) updateReadiness() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for name, readiness := range g.gateways {
+		if readiness.ready || readiness.version == "" || g.nodesAcked(name, readiness.since) < g.quorum {
+			continue
+		}
+		readiness.ready = true
+
+		g.log.WithField("name", name.Name).WithField("namespace", name.Namespace).
+			Info("gateway configuration acknowledged by envoy")
+
+		generation := readiness.generation
+		condStatus, reason, message := g.condition(true)
+		g.statusUpdater.Send(k8s.StatusUpdate{
+			NamespacedName: name,
+			Resource:       &gatewayapi_v1.Gateway{},
+			Mutator: k8s.StatusMutatorFunc(func(obj client.Object) client.Object {
+				gateway, ok := obj.(*gatewayapi_v1.Gateway)
+				if !ok || gateway.Generation != generation {
+					return obj
+				}
+
+				updated := gateway.DeepCopy()
+				meta.SetStatusCondition(&updated.Status.Conditions, meta_v1.Condition{
+					Type:               string(status.GatewayConditionDataPlaneReady),
+					Status:             condStatus,
+					Reason:             string(reason),
+					Message:            message,
+					ObservedGeneration: generation,
+				})
+				return updated
+			}),
+		})
+	}
+}
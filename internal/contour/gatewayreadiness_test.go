@@ -0,0 +1,285 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/projectcontour/contour/internal/status"
+	contour_xds_v3 "github.com/projectcontour/contour/internal/xds/v3"
+)
+
+type fakeAckCounter struct {
+	// acked holds the time each Envoy ACKed a snapshot set at.
+	acked []time.Time
+
+	// cluster holds the node cluster last asked about.
+	cluster string
+
+	// version and set hold the latest snapshot version,
+	// and when it was set.
+	version string
+	set     time.Time
+}
+
+func (f *fakeAckCounter) SnapshotVersion(...string) (string, time.Time) {
+	return f.version, f.set
+}
+
+// setSnapshot records that a new snapshot version was set,
+// a second after the previous one.
+func (f *fakeAckCounter) setSnapshot(version string) {
+	f.version = version
+	f.set = f.set.Add(time.Second)
+}
+
+func (f *fakeAckCounter) NodesAcked(cluster string, since time.Time, _ ...string) int {
+	f.cluster = cluster
+	count := 0
+	for _, t := range f.acked {
+		if !t.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+type statusUpdateRecorder struct {
+	updates []k8s.StatusUpdate
+}
+
+func (s *statusUpdateRecorder) Send(su k8s.StatusUpdate) {
+	s.updates = append(s.updates, su)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) OnChange(*dag.DAG) {}
+
+func TestGatewayReadinessObserver(t *testing.T) {
+	gatewayName := types.NamespacedName{Namespace: "projectcontour", Name: "contour"}
+
+	buildDAG := func(generation int64, programmed meta_v1.ConditionStatus) *dag.DAG {
+		d := &dag.DAG{
+			StatusCache: status.NewCache("projectcontour.io/gateway-controller"),
+		}
+		gu, commit := d.StatusCache.GatewayStatusAccessor(gatewayName, generation, &gatewayapi_v1.GatewayStatus{})
+		gu.AddCondition(gatewayapi_v1.GatewayConditionProgrammed, programmed, gatewayapi_v1.GatewayReasonProgrammed, status.MessageValidGateway)
+		commit()
+		return d
+	}
+
+	readyCondition := func(d *dag.DAG) *meta_v1.Condition {
+		updates := d.StatusCache.GetGatewayUpdates()
+		require.Len(t, updates, 1)
+		cond, ok := updates[0].Conditions[status.GatewayConditionDataPlaneReady]
+		if !ok {
+			return nil
+		}
+		return &cond
+	}
+
+	acks := &fakeAckCounter{}
+	updater := &statusUpdateRecorder{}
+	g := NewGatewayReadinessObserver(fixture.NewTestLogger(t), acks, 2, false, updater, nopObserver{})
+
+	// No Envoys have ACKed the configuration yet.
+	acks.setSnapshot("1")
+	d := buildDAG(1, meta_v1.ConditionTrue)
+	g.OnChange(d)
+	assert.Empty(t, acks.cluster)
+	cond := readyCondition(d)
+	require.NotNil(t, cond)
+	assert.Equal(t, meta_v1.ConditionFalse, cond.Status)
+	assert.Equal(t, string(status.GatewayReasonPending), cond.Reason)
+
+	// One of two Envoys has ACKed the configuration.
+	acks.acked = append(acks.acked, acks.set)
+	g.updateReadiness()
+	assert.Empty(t, updater.updates)
+
+	// Both Envoys have ACKed the configuration.
+	acks.acked = append(acks.acked, acks.set)
+	g.updateReadiness()
+	require.Len(t, updater.updates, 1)
+	assert.Equal(t, gatewayName, updater.updates[0].NamespacedName)
+
+	gateway := &gatewayapi_v1.Gateway{ObjectMeta: meta_v1.ObjectMeta{Generation: 1}}
+	updated, ok := updater.updates[0].Mutator.Mutate(gateway).(*gatewayapi_v1.Gateway)
+	require.True(t, ok)
+	cond = meta.FindStatusCondition(updated.Status.Conditions, string(status.GatewayConditionDataPlaneReady))
+	require.NotNil(t, cond)
+	assert.Equal(t, meta_v1.ConditionTrue, cond.Status)
+	assert.Equal(t, int64(1), cond.ObservedGeneration)
+
+	// The update is not applied to a newer generation of the Gateway.
+	gateway = &gatewayapi_v1.Gateway{ObjectMeta: meta_v1.ObjectMeta{Generation: 2}}
+	assert.Equal(t, gateway, updater.updates[0].Mutator.Mutate(gateway))
+
+	// The Gateway stays ready when the DAG is rebuilt
+	// without changing the snapshot.
+	d = buildDAG(1, meta_v1.ConditionTrue)
+	g.OnChange(d)
+	assert.Equal(t, meta_v1.ConditionTrue, readyCondition(d).Status)
+
+	// A new snapshot, e.g. for a changed HTTPRoute or Secret,
+	// must be ACKed again.
+	acks.setSnapshot("2")
+	d = buildDAG(1, meta_v1.ConditionTrue)
+	g.OnChange(d)
+	assert.Equal(t, meta_v1.ConditionFalse, readyCondition(d).Status)
+
+	acks.acked = []time.Time{acks.set, acks.set}
+	d = buildDAG(1, meta_v1.ConditionTrue)
+	g.OnChange(d)
+	assert.Equal(t, meta_v1.ConditionTrue, readyCondition(d).Status)
+
+	// A new generation of the Gateway must be ACKed again.
+	acks.setSnapshot("3")
+	d = buildDAG(2, meta_v1.ConditionTrue)
+	g.OnChange(d)
+	assert.Equal(t, meta_v1.ConditionFalse, readyCondition(d).Status)
+
+	// Gateways that are not programmed are not reported on.
+	d = buildDAG(3, meta_v1.ConditionFalse)
+	g.OnChange(d)
+	assert.Nil(t, readyCondition(d))
+	assert.Empty(t, g.gateways)
+}
+
+func TestGatewayReadinessObserverNodeGroups(t *testing.T) {
+	gatewayName := types.NamespacedName{Namespace: "projectcontour", Name: "gateway-1"}
+
+	d := &dag.DAG{
+		StatusCache: status.NewCache("projectcontour.io/gateway-controller"),
+	}
+	gu, commit := d.StatusCache.GatewayStatusAccessor(gatewayName, 1, &gatewayapi_v1.GatewayStatus{})
+	gu.AddCondition(gatewayapi_v1.GatewayConditionProgrammed, meta_v1.ConditionTrue, gatewayapi_v1.GatewayReasonProgrammed, status.MessageValidGateway)
+	commit()
+
+	acks := &fakeAckCounter{}
+	acks.setSnapshot("1")
+	g := NewGatewayReadinessObserver(fixture.NewTestLogger(t), acks, 1, true, &statusUpdateRecorder{}, nopObserver{})
+
+	// Only the Envoys of the Gateway's node group are counted.
+	g.OnChange(d)
+	assert.Equal(t, "projectcontour/gateway-1", acks.cluster)
+}
+
+// snapshotSetter sets a snapshot when the DAG changes, like the
+// snapshot handler that follows the GatewayReadinessObserver. If
+// ack is set, it is called when the snapshot is set, before it
+// is recorded, like an Envoy ACKing it immediately.
+type snapshotSetter struct {
+	streamMetrics *contour_xds_v3.StreamMetrics
+	version       string
+	ack           func(version string)
+}
+
+func (s *snapshotSetter) OnChange(*dag.DAG) {
+	if s.ack != nil {
+		s.ack(s.version)
+	}
+	s.streamMetrics.SnapshotSet(s.version, gatewayReadinessTypeURLs...)
+}
+
+func TestGatewayReadinessObserverStreamMetrics(t *testing.T) {
+	gatewayName := types.NamespacedName{Namespace: "projectcontour", Name: "contour"}
+
+	buildDAG := func() *dag.DAG {
+		d := &dag.DAG{
+			StatusCache: status.NewCache("projectcontour.io/gateway-controller"),
+		}
+		gu, commit := d.StatusCache.GatewayStatusAccessor(gatewayName, 1, &gatewayapi_v1.GatewayStatus{})
+		gu.AddCondition(gatewayapi_v1.GatewayConditionProgrammed, meta_v1.ConditionTrue, gatewayapi_v1.GatewayReasonProgrammed, status.MessageValidGateway)
+		commit()
+		return d
+	}
+
+	readyStatus := func(d *dag.DAG) meta_v1.ConditionStatus {
+		updates := d.StatusCache.GetGatewayUpdates()
+		require.Len(t, updates, 1)
+		return updates[0].Conditions[status.GatewayConditionDataPlaneReady].Status
+	}
+
+	streamMetrics := contour_xds_v3.NewStreamMetrics(metrics.NewMetrics(prometheus.NewRegistry()))
+	callbacks := contour_xds_v3.NewCallbacks(fixture.NewTestLogger(t), streamMetrics)
+
+	// An Envoy has subscribed to the listeners and routes.
+	require.NoError(t, callbacks.OnStreamOpen(context.Background(), 1, ""))
+	for _, typeURL := range gatewayReadinessTypeURLs[:2] {
+		require.NoError(t, callbacks.OnStreamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+			Node:    &envoy_config_core_v3.Node{Id: "envoy-1"},
+			TypeUrl: typeURL,
+		}))
+	}
+
+	ack := func(version string) {
+		for _, typeURL := range gatewayReadinessTypeURLs[:2] {
+			require.NoError(t, callbacks.OnStreamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+				TypeUrl:       typeURL,
+				VersionInfo:   version,
+				ResponseNonce: version,
+			}))
+		}
+	}
+
+	updater := &statusUpdateRecorder{}
+	setter := &snapshotSetter{streamMetrics: streamMetrics, version: "1"}
+	g := NewGatewayReadinessObserver(fixture.NewTestLogger(t), streamMetrics, 1, false, updater, setter)
+
+	d := buildDAG()
+	g.OnChange(d)
+	assert.Equal(t, meta_v1.ConditionFalse, readyStatus(d))
+
+	// The Envoy ACKs the snapshot set by the rebuild.
+	ack("1")
+	g.updateReadiness()
+	require.Len(t, updater.updates, 1)
+	assert.Equal(t, gatewayName, updater.updates[0].NamespacedName)
+
+	// A rebuild for a changed HTTPRoute or Secret sets a new
+	// snapshot, which has to be ACKed again.
+	setter.version = "2"
+	d = buildDAG()
+	g.OnChange(d)
+	assert.Equal(t, meta_v1.ConditionFalse, readyStatus(d))
+
+	ack("2")
+	g.updateReadiness()
+	require.Len(t, updater.updates, 2)
+
+	// The Envoy ACKs the next snapshot even before it is recorded.
+	setter.version = "3"
+	setter.ack = ack
+	d = buildDAG()
+	g.OnChange(d)
+	assert.Equal(t, meta_v1.ConditionTrue, readyStatus(d))
+}
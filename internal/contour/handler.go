@@ -95,6 +95,11 @@ type opDelete struct {
 	obj any
 }
 
+type opReconfigure struct {
+	processors []dag.Processor
+	apply      func()
+}
+
 func (e *EventHandler) OnAdd(obj any, isInInitialList bool) {
 	if isInInitialList {
 		e.syncTracker.Start()
@@ -110,6 +115,14 @@ func (e *EventHandler) OnDelete(obj any) {
 	e.update <- opDelete{obj: obj}
 }
 
+// Reconfigure replaces the DAG processors used to build the DAG and
+// schedules a rebuild. If apply is not nil, it is called from the
+// event handler's goroutine before the processors are replaced, so
+// it can safely change the configuration of the Observer.
+func (e *EventHandler) Reconfigure(processors []dag.Processor, apply func()) {
+	e.update <- opReconfigure{processors: processors, apply: apply}
+}
+
 // NeedLeaderElection is included to implement manager.LeaderElectionRunnable
 func (e *EventHandler) NeedLeaderElection() bool {
 	return false
@@ -141,6 +154,13 @@ func (e *EventHandler) Start(ctx context.Context) error {
 		// pending is a reference to the current timer's channel.
 		pending <-chan time.Time
 
+		// staleTimer holds the timer which will expire when the
+		// last DAG goes stale, e.g. because a certificate expires.
+		staleTimer *time.Timer
+
+		// stale is a reference to the current stale timer's channel.
+		stale <-chan time.Time
+
 		// lastDAGRebuild holds the last time rebuildDAG was called.
 		// lastDAGRebuild is seeded to the current time on entry to
 		// run to allow the holdoff timer to batch the updates from
@@ -163,13 +183,14 @@ func (e *EventHandler) Start(ctx context.Context) error {
 	}
 
 	for {
-		// In the main loop one of four things can happen.
+		// In the main loop one of five things can happen.
 		// 1. We're waiting for an event on op, stop, or pending, noting that
 		//    pending may be nil if there are no pending events.
 		// 2. We're processing an event.
 		// 3. The holdoff timer from a previous event has fired and we're
 		//    building a new DAG and sending to the Observer.
-		// 4. We're stopping.
+		// 4. The last DAG went stale and we're scheduling a rebuild.
+		// 5. We're stopping.
 		//
 		// Only one of these things can happen at a time.
 		select {
@@ -224,6 +245,25 @@ func (e *EventHandler) Start(ctx context.Context) error {
 
 			e.incSequence()
 			lastDAGRebuild = time.Now()
+
+			if staleTimer != nil {
+				staleTimer.Stop()
+				stale = nil
+			}
+			if !latestDAG.RebuildAt.IsZero() {
+				staleTimer = time.NewTimer(time.Until(latestDAG.RebuildAt))
+				stale = staleTimer.C
+			}
+		case <-stale:
+			// The last DAG went stale without any events, so
+			// schedule the rebuild immediately.
+			stale = nil
+			outstanding++
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(0)
+			pending = timer.C
 		case <-initialSyncPoll:
 			if e.syncTracker.HasSynced() {
 				// Informer caches are synced, stop the polling and allow xDS server to start.
@@ -272,6 +312,12 @@ func (e *EventHandler) onUpdate(op any) bool {
 		return false
 	case opDelete:
 		return e.builder.Source.Remove(op.obj)
+	case opReconfigure:
+		if op.apply != nil {
+			op.apply()
+		}
+		e.builder.Processors = op.processors
+		return true
 	case bool:
 		return op
 	default:
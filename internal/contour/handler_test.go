@@ -14,13 +14,111 @@
 package contour
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
 )
 
 func TestEventHandlerNotRequireLeaderElection(t *testing.T) {
 	var e manager.LeaderElectionRunnable = &EventHandler{}
 	require.False(t, e.NeedLeaderElection())
 }
+
+func TestEventHandlerRebuildsStaleDAG(t *testing.T) {
+	// The certificate expires during the test, so the
+	// DAG built before it expires goes stale.
+	cert, key := selfSignedCertificate(t, time.Now().Add(2*time.Second))
+	secret := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tls",
+			Namespace: "default",
+		},
+		Type: core_v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			core_v1.TLSCertKey:       cert,
+			core_v1.TLSPrivateKeyKey: key,
+		},
+	}
+
+	var lookups atomic.Int32
+	builder := &dag.Builder{
+		Source: dag.KubernetesCache{
+			RejectExpiredCertificates: true,
+			FieldLogger:               fixture.NewTestLogger(t),
+		},
+		Processors: []dag.Processor{
+			dag.ProcessorFunc(func(_ *dag.DAG, source *dag.KubernetesCache) {
+				_, _ = source.LookupTLSSecretInsecure(types.NamespacedName{Namespace: "default", Name: "tls"})
+				lookups.Add(1)
+			}),
+		},
+	}
+	builder.Source.Insert(secret)
+
+	var builds atomic.Int32
+	eh := NewEventHandler(EventHandlerConfig{
+		Logger:          fixture.NewTestLogger(t),
+		Builder:         builder,
+		Observer:        dag.ObserverFunc(func(*dag.DAG) { builds.Add(1) }),
+		HoldoffDelay:    time.Millisecond,
+		HoldoffMaxDelay: 10 * time.Millisecond,
+		StatusUpdater:   &k8s.StatusUpdateCacher{},
+	}, func() bool { return true })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = eh.Start(ctx) }()
+
+	eh.OnElectedLeader()
+
+	// The DAG is rebuilt once when the certificate expires,
+	// and not again since it then no longer goes stale.
+	require.Eventually(t, func() bool { return builds.Load() == 2 }, 10*time.Second, 50*time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, int32(2), builds.Load())
+	assert.Equal(t, int32(2), lookups.Load())
+}
+
+func selfSignedCertificate(t *testing.T, notAfter time.Time) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -89,9 +89,13 @@ func (m *RebuildMetricsObserver) OnChange(d *dag.DAG) {
 	m.metrics.SetDAGLastRebuilt(time.Now())
 	m.metrics.SetDAGRebuiltTotal()
 
+	// The xDS snapshot is set by the last of the next observers, so
+	// this also measures the time from the DAG rebuild to the snapshot.
 	timer := prometheus.NewTimer(m.metrics.CacheHandlerOnUpdateSummary)
 	m.nextObserver.OnChange(d)
-	timer.ObserveDuration()
+	m.metrics.SetXDSSnapshotSetDuration(timer.ObserveDuration())
+
+	m.metrics.SetCertificateExpiryMetric(calculateCertificateExpiryMetric(d))
 
 	select {
 	case <-m.httpProxyMetricsEnabled:
@@ -123,6 +127,51 @@ func calculateRouteMetric(updates []*status.ProxyUpdate) metrics.RouteMetric {
 	}
 }
 
+// calculateCertificateExpiryMetric returns the expiry time of each
+// Secret holding serving, client or CA certificates in the DAG.
+func calculateCertificateExpiryMetric(d *dag.DAG) map[metrics.CertificateMeta]time.Time {
+	expiry := make(map[metrics.CertificateMeta]time.Time)
+
+	addCert := func(s *dag.Secret, certType string) {
+		if s == nil {
+			return
+		}
+		if notAfter, ok := s.CertificateExpiry(); ok {
+			expiry[metrics.CertificateMeta{Namespace: s.Namespace(), Name: s.Name(), Type: certType}] = notAfter
+		}
+	}
+	addCA := func(pvc *dag.PeerValidationContext) {
+		if pvc == nil {
+			return
+		}
+		for _, s := range pvc.CACertificates {
+			if notAfter, ok := s.CACertificateExpiry(); ok {
+				expiry[metrics.CertificateMeta{Namespace: s.Namespace(), Name: s.Name(), Type: "ca"}] = notAfter
+			}
+		}
+	}
+
+	for _, l := range d.Listeners {
+		for _, svh := range l.SecureVirtualHosts {
+			for _, s := range svh.Secrets() {
+				addCert(s, "serving")
+			}
+			addCert(svh.FallbackCertificate, "serving")
+			addCA(svh.DownstreamValidation)
+		}
+	}
+	for _, c := range d.GetClusters() {
+		addCert(c.ClientCertificate, "client")
+		addCA(c.UpstreamValidation)
+	}
+	for _, ec := range d.GetExtensionClusters() {
+		addCert(ec.ClientCertificate, "client")
+		addCA(ec.UpstreamValidation)
+	}
+
+	return expiry
+}
+
 func calcMetrics(u *status.ProxyUpdate, metricValid, metricInvalid, metricOrphaned, metricTotal map[metrics.Meta]int) {
 	validCond := u.ConditionFor(status.ValidCondition)
 	switch validCond.Status {
@@ -15,8 +15,10 @@ package contour
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -24,6 +26,7 @@ import (
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/fixture"
 	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/projectcontour/contour/pkg/certs"
 )
 
 func TestHTTPProxyMetrics(t *testing.T) {
@@ -510,3 +513,58 @@ func TestHTTPProxyMetrics(t *testing.T) {
 		},
 	})
 }
+
+func TestCertificateExpiryMetrics(t *testing.T) {
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			FieldLogger: fixture.NewTestLogger(t),
+		},
+		Processors: []dag.Processor{
+			&dag.ListenerProcessor{},
+			&dag.HTTPProxyProcessor{},
+		},
+	}
+
+	builder.Source.Insert(fixture.SecretRootsCert)
+	builder.Source.Insert(&core_v1.Secret{
+		ObjectMeta: fixture.ObjectMeta("roots/ca"),
+		Type:       core_v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			dag.CACertificateKey: []byte(fixture.CA_CERT),
+		},
+	})
+	builder.Source.Insert(fixture.NewService("roots/kuard").
+		WithPorts(core_v1.ServicePort{Name: "http", Port: 8080}))
+	builder.Source.Insert(&contour_v1.HTTPProxy{
+		ObjectMeta: fixture.ObjectMeta("roots/example"),
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+					ClientValidation: &contour_v1.DownstreamValidation{
+						CACertificate: "ca",
+					},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	})
+
+	got := calculateCertificateExpiryMetric(builder.Build())
+
+	serving, err := certs.CertificateExpiry([]byte(fixture.CERTIFICATE))
+	require.NoError(t, err)
+	ca, err := certs.CertificateExpiry([]byte(fixture.CA_CERT))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[metrics.CertificateMeta]time.Time{
+		{Namespace: "roots", Name: fixture.SecretRootsCert.Name, Type: "serving"}: serving,
+		{Namespace: "roots", Name: "ca", Type: "ca"}:                              ca,
+	}, got)
+}
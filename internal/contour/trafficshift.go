@@ -0,0 +1,322 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/annotation"
+	"github.com/projectcontour/contour/internal/ingressclass"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+// TrafficShiftController progresses the traffic shift policies of
+// HTTPProxy routes. It records the current step of each shift in the
+// status of the HTTPProxy, which the DAG reads to weight the stable
+// and canary services of the ServiceCluster of the shift, moving to
+// the next step once the interval of the policy has elapsed and
+// aborting the shift if the error rate of the canary service goes
+// over the threshold of the policy. Only the HTTPProxies of the
+// configured ingress classes that the DAG accepted are progressed.
+//
+// The status is the only state of a shift that outlives the
+// controller, so a restarted controller carries on from the step
+// and transition time recorded there.
+type TrafficShiftController struct {
+	log               logrus.FieldLogger
+	client            client.Reader
+	ingressClassNames []string
+	counter           UpstreamRequestCounter
+	statusUpdater     k8s.StatusUpdater
+	pollInterval      time.Duration
+
+	// now is used in place of time.Now, for testing.
+	now func() time.Time
+
+	// baselines holds the request counts of the canary service
+	// at the start of the current step of each shift.
+	baselines map[trafficShiftKey]requestBaseline
+
+	// warned holds the shifts whose Envoy error rate can't be read
+	// that a warning was logged for.
+	warned map[trafficShiftKey]bool
+}
+
+// trafficShiftKey identifies a traffic shift of an HTTPProxy.
+type trafficShiftKey struct {
+	proxy  types.NamespacedName
+	stable string
+	canary string
+
+	// hash is the hash of the policy of the shift.
+	hash string
+}
+
+// requestBaseline holds the request counts of a canary service at
+// the start of a step.
+type requestBaseline struct {
+	step      int
+	completed uint64
+	failed    uint64
+}
+
+// NewTrafficShiftController returns a TrafficShiftController reading
+// the HTTPProxies of the given ingress classes from client. If counter
+// is nil, error rates can only be given by the
+// projectcontour.io/traffic-shift-error-rate annotation.
+func NewTrafficShiftController(log logrus.FieldLogger, client client.Reader, ingressClassNames []string, counter UpstreamRequestCounter, statusUpdater k8s.StatusUpdater) *TrafficShiftController {
+	return &TrafficShiftController{
+		log:               log,
+		client:            client,
+		ingressClassNames: ingressClassNames,
+		counter:           counter,
+		statusUpdater:     statusUpdater,
+		pollInterval:      5 * time.Second,
+		now:               time.Now,
+		baselines:         map[trafficShiftKey]requestBaseline{},
+		warned:            map[trafficShiftKey]bool{},
+	}
+}
+
+func (t *TrafficShiftController) NeedLeaderElection() bool {
+	return true
+}
+
+// Start progresses the traffic shifts of the HTTPProxies every poll
+// interval until ctx is done.
+func (t *TrafficShiftController) Start(ctx context.Context) error {
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.progress(ctx)
+		}
+	}
+}
+
+func (t *TrafficShiftController) progress(ctx context.Context) {
+	var proxies contour_v1.HTTPProxyList
+	if err := t.client.List(ctx, &proxies); err != nil {
+		t.log.WithError(err).Error("failed to list HTTPProxies")
+		return
+	}
+
+	seen := map[trafficShiftKey]bool{}
+	for i := range proxies.Items {
+		proxy := &proxies.Items[i]
+
+		// Leave the HTTPProxies of other Contours alone, and
+		// don't progress the shifts of rejected HTTPProxies,
+		// which include those with invalid policies.
+		if !ingressclass.MatchesHTTPProxy(proxy, t.ingressClassNames) || !accepted(proxy) {
+			continue
+		}
+
+		shifts := t.progressProxy(ctx, proxy, seen)
+		if equality.Semantic.DeepEqual(shifts, proxy.Status.TrafficShifts) {
+			continue
+		}
+
+		t.statusUpdater.Send(k8s.StatusUpdate{
+			NamespacedName: k8s.NamespacedNameOf(proxy),
+			Resource:       &contour_v1.HTTPProxy{},
+			Mutator: k8s.StatusMutatorFunc(func(obj client.Object) client.Object {
+				proxy, ok := obj.(*contour_v1.HTTPProxy)
+				if !ok {
+					return obj
+				}
+
+				updated := proxy.DeepCopy()
+				updated.Status.TrafficShifts = shifts
+				return updated
+			}),
+		})
+	}
+
+	for key := range t.baselines {
+		if !seen[key] {
+			delete(t.baselines, key)
+		}
+	}
+	for key := range t.warned {
+		if !seen[key] {
+			delete(t.warned, key)
+		}
+	}
+}
+
+// accepted returns whether the DAG accepted the current generation of
+// the HTTPProxy.
+func accepted(proxy *contour_v1.HTTPProxy) bool {
+	cond := proxy.Status.GetConditionFor(contour_v1.ValidConditionType)
+	return cond != nil && cond.Status == contour_v1.ConditionTrue && cond.ObservedGeneration == proxy.Generation
+}
+
+// progressProxy returns the traffic shift status of the routes of the
+// proxy, progressing the shifts that are due. Status entries of shifts
+// no longer configured are dropped.
+func (t *TrafficShiftController) progressProxy(ctx context.Context, proxy *contour_v1.HTTPProxy, seen map[trafficShiftKey]bool) []contour_v1.TrafficShiftStatus {
+	var shifts []contour_v1.TrafficShiftStatus
+
+	for _, route := range proxy.Spec.Routes {
+		policy := route.TrafficShiftPolicy
+		if policy == nil || len(policy.Steps) == 0 {
+			continue
+		}
+		key := trafficShiftKey{
+			proxy:  k8s.NamespacedNameOf(proxy),
+			stable: policy.Stable,
+			canary: policy.Canary,
+			hash:   policy.Hash(),
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		current := proxy.Status.GetTrafficShiftFor(policy)
+		shifts = append(shifts, t.progressShift(ctx, proxy, key, policy, current))
+	}
+
+	return shifts
+}
+
+// progressShift returns the next status of the traffic shift.
+func (t *TrafficShiftController) progressShift(ctx context.Context, proxy *contour_v1.HTTPProxy, key trafficShiftKey, policy *contour_v1.TrafficShiftPolicy, current *contour_v1.TrafficShiftStatus) contour_v1.TrafficShiftStatus {
+	now := meta_v1.NewTime(t.now())
+
+	if current == nil {
+		return contour_v1.TrafficShiftStatus{
+			Stable:             policy.Stable,
+			Canary:             policy.Canary,
+			PolicyHash:         key.hash,
+			Step:               0,
+			Weight:             policy.Steps[0],
+			Phase:              contour_v1.TrafficShiftPhaseProgressing,
+			LastTransitionTime: now,
+			Message:            t.progressMessage(proxy, key, policy, policy.Steps[0]),
+		}
+	}
+
+	next := *current
+	if next.Phase != contour_v1.TrafficShiftPhaseProgressing {
+		return next
+	}
+	next.Message = t.progressMessage(proxy, key, policy, next.Weight)
+
+	log := t.log.WithField("namespace", proxy.Namespace).WithField("name", proxy.Name).
+		WithField("stable", policy.Stable).WithField("canary", policy.Canary)
+
+	if policy.ErrorRate != nil {
+		rate, ok := t.errorRate(ctx, proxy, key, policy.ErrorRate.Source, current.Step)
+		if ok && rate > float64(policy.ErrorRate.Threshold) {
+			log.WithField("error_rate", rate).Info("aborting traffic shift")
+
+			next.Weight = 0
+			next.Phase = contour_v1.TrafficShiftPhaseAborted
+			next.LastTransitionTime = now
+			next.Message = fmt.Sprintf("Error rate of %s is %.2f%%, over the threshold of %d%%",
+				policy.Canary, rate, policy.ErrorRate.Threshold)
+			return next
+		}
+	}
+
+	// The policy was validated by the DAG, which does not weight the
+	// services by an invalid interval.
+	interval, err := policy.StepInterval()
+	if err != nil || now.Sub(current.LastTransitionTime.Time) < interval {
+		return next
+	}
+
+	next.LastTransitionTime = now
+	if current.Step+1 >= len(policy.Steps) {
+		log.Info("traffic shift succeeded")
+
+		next.Phase = contour_v1.TrafficShiftPhaseSucceeded
+		next.Message = fmt.Sprintf("Sending %d%% of requests to %s", next.Weight, policy.Canary)
+		return next
+	}
+
+	next.Step = current.Step + 1
+	next.Weight = policy.Steps[next.Step]
+	next.Message = t.progressMessage(proxy, key, policy, next.Weight)
+	log.WithField("weight", next.Weight).Info("traffic shift progressing")
+	return next
+}
+
+// progressMessage returns the status message of a progressing shift,
+// which tells when its error rate can't be read from Envoy.
+func (t *TrafficShiftController) progressMessage(proxy *contour_v1.HTTPProxy, key trafficShiftKey, policy *contour_v1.TrafficShiftPolicy, weight uint32) string {
+	message := fmt.Sprintf("Sending %d%% of requests to %s", weight, policy.Canary)
+
+	if policy.ErrorRate == nil || policy.ErrorRate.Source != contour_v1.TrafficShiftErrorRateSourceEnvoy || t.counter != nil {
+		return message
+	}
+
+	if !t.warned[key] {
+		t.warned[key] = true
+		t.log.WithField("namespace", proxy.Namespace).WithField("name", proxy.Name).
+			WithField("canary", policy.Canary).
+			Warn("traffic shift error rate can't be read from Envoy, whose stats are served over HTTPS")
+	}
+	return message + "; the error rate is not checked because the Envoy stats are served over HTTPS"
+}
+
+// errorRate returns the percentage of requests to the canary service
+// of the shift that failed during the current step, if known.
+func (t *TrafficShiftController) errorRate(ctx context.Context, proxy *contour_v1.HTTPProxy, key trafficShiftKey, source contour_v1.TrafficShiftErrorRateSource, step int) (float64, bool) {
+	switch source {
+	case contour_v1.TrafficShiftErrorRateSourceExternal:
+		return annotation.TrafficShiftErrorRate(proxy)
+	case contour_v1.TrafficShiftErrorRateSourceEnvoy:
+		if t.counter == nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	completed, failed, err := t.counter.CanaryRequests(ctx, key.proxy, key.stable, key.canary)
+	if err != nil {
+		t.log.WithError(err).WithField("namespace", proxy.Namespace).WithField("name", proxy.Name).
+			Warn("failed to read error rate of canary service")
+		return 0, false
+	}
+
+	// Measure from the start of the step, starting again if the
+	// counters went back because an Envoy restarted or went away.
+	baseline, ok := t.baselines[key]
+	if !ok || baseline.step != step || completed < baseline.completed || failed < baseline.failed {
+		t.baselines[key] = requestBaseline{step: step, completed: completed, failed: failed}
+		return 0, false
+	}
+
+	if completed == baseline.completed {
+		return 0, false
+	}
+	return 100 * float64(failed-baseline.failed) / float64(completed-baseline.completed), true
+}
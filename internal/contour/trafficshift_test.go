@@ -0,0 +1,357 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+type fakeRequestCounter struct {
+	completed, failed uint64
+}
+
+func (f *fakeRequestCounter) CanaryRequests(context.Context, types.NamespacedName, string, string) (uint64, uint64, error) {
+	return f.completed, f.failed, nil
+}
+
+func TestTrafficShiftController(t *testing.T) {
+	proxyName := types.NamespacedName{Namespace: "default", Name: "kuard"}
+
+	newProxy := func(errorRate *contour_v1.TrafficShiftErrorRate) *contour_v1.HTTPProxy {
+		return &contour_v1.HTTPProxy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Namespace:  proxyName.Namespace,
+				Name:       proxyName.Name,
+				Generation: 1,
+			},
+			Spec: contour_v1.HTTPProxySpec{
+				VirtualHost: &contour_v1.VirtualHost{Fqdn: "kuard.example.com"},
+				Routes: []contour_v1.Route{{
+					Services: []contour_v1.Service{
+						{Name: "kuard", Port: 80},
+						{Name: "kuard-canary", Port: 80},
+					},
+					TrafficShiftPolicy: &contour_v1.TrafficShiftPolicy{
+						Stable:    "kuard",
+						Canary:    "kuard-canary",
+						Steps:     []uint32{10, 50},
+						Interval:  "1m",
+						ErrorRate: errorRate,
+					},
+				}},
+			},
+			Status: contour_v1.HTTPProxyStatus{
+				Conditions: []contour_v1.DetailedCondition{{
+					Condition: contour_v1.Condition{
+						Type:               contour_v1.ValidConditionType,
+						Status:             contour_v1.ConditionTrue,
+						ObservedGeneration: 1,
+					},
+				}},
+			},
+		}
+	}
+
+	// update changes the HTTPProxy, as if the DAG accepted the change.
+	update := func(t *testing.T, c client.Client, mutate func(*contour_v1.HTTPProxy)) {
+		proxy := &contour_v1.HTTPProxy{}
+		require.NoError(t, c.Get(context.Background(), proxyName, proxy))
+		mutate(proxy)
+		proxy.Generation++
+		proxy.Status.Conditions[0].ObservedGeneration = proxy.Generation
+		require.NoError(t, c.Update(context.Background(), proxy))
+	}
+
+	setup := func(t *testing.T, proxy *contour_v1.HTTPProxy, counter UpstreamRequestCounter) (*TrafficShiftController, client.Client, *statusUpdateRecorder, *time.Time) {
+		scheme, err := k8s.NewContourScheme()
+		require.NoError(t, err)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy).Build()
+		recorder := &statusUpdateRecorder{}
+
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		controller := NewTrafficShiftController(fixture.NewTestLogger(t), c, nil, counter, recorder)
+		controller.now = func() time.Time { return now }
+		return controller, c, recorder, &now
+	}
+
+	// progress runs the controller and applies the status
+	// updates it sends, returning the traffic shift status.
+	progress := func(t *testing.T, controller *TrafficShiftController, c client.Client, recorder *statusUpdateRecorder) []contour_v1.TrafficShiftStatus {
+		controller.progress(context.Background())
+
+		for _, update := range recorder.updates {
+			proxy := &contour_v1.HTTPProxy{}
+			require.NoError(t, c.Get(context.Background(), update.NamespacedName, proxy))
+			require.NoError(t, c.Update(context.Background(), update.Mutator.Mutate(proxy)))
+		}
+		recorder.updates = nil
+
+		proxy := &contour_v1.HTTPProxy{}
+		require.NoError(t, c.Get(context.Background(), proxyName, proxy))
+		return proxy.Status.TrafficShifts
+	}
+
+	t.Run("steps progress after the interval", func(t *testing.T) {
+		controller, c, recorder, now := setup(t, newProxy(nil), nil)
+
+		shifts := progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, 0, shifts[0].Step)
+		assert.Equal(t, uint32(10), shifts[0].Weight)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseProgressing, shifts[0].Phase)
+
+		// Nothing changes before the interval has elapsed.
+		*now = now.Add(30 * time.Second)
+		controller.progress(context.Background())
+		assert.Empty(t, recorder.updates)
+
+		*now = now.Add(30 * time.Second)
+		shifts = progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, 1, shifts[0].Step)
+		assert.Equal(t, uint32(50), shifts[0].Weight)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseProgressing, shifts[0].Phase)
+
+		*now = now.Add(time.Minute)
+		shifts = progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, uint32(50), shifts[0].Weight)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseSucceeded, shifts[0].Phase)
+
+		// Succeeded shifts don't change.
+		*now = now.Add(time.Minute)
+		controller.progress(context.Background())
+		assert.Empty(t, recorder.updates)
+	})
+
+	t.Run("external error rate over the threshold aborts the shift", func(t *testing.T) {
+		controller, c, recorder, now := setup(t, newProxy(&contour_v1.TrafficShiftErrorRate{
+			Source:    contour_v1.TrafficShiftErrorRateSourceExternal,
+			Threshold: 5,
+		}), nil)
+
+		shifts := progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseProgressing, shifts[0].Phase)
+
+		update(t, c, func(proxy *contour_v1.HTTPProxy) {
+			proxy.Annotations = map[string]string{"projectcontour.io/traffic-shift-error-rate": "7.5"}
+		})
+
+		*now = now.Add(time.Second)
+		shifts = progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, uint32(0), shifts[0].Weight)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseAborted, shifts[0].Phase)
+		assert.Equal(t, "Error rate of kuard-canary is 7.50%, over the threshold of 5%", shifts[0].Message)
+
+		// Aborted shifts don't change.
+		*now = now.Add(time.Hour)
+		controller.progress(context.Background())
+		assert.Empty(t, recorder.updates)
+	})
+
+	t.Run("envoy error rate is measured from the start of the step", func(t *testing.T) {
+		counter := &fakeRequestCounter{completed: 1000, failed: 500}
+		controller, c, recorder, now := setup(t, newProxy(&contour_v1.TrafficShiftErrorRate{
+			Source:    contour_v1.TrafficShiftErrorRateSourceEnvoy,
+			Threshold: 10,
+		}), counter)
+
+		progress(t, controller, c, recorder)
+
+		// The first poll of the step records the baseline, so
+		// requests before the shift started don't count.
+		*now = now.Add(time.Second)
+		progress(t, controller, c, recorder)
+
+		counter.completed, counter.failed = 1100, 505
+		*now = now.Add(time.Second)
+		shifts := progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseProgressing, shifts[0].Phase)
+
+		counter.completed, counter.failed = 1200, 530
+		*now = now.Add(time.Second)
+		shifts = progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseAborted, shifts[0].Phase)
+		assert.Equal(t, "Error rate of kuard-canary is 15.00%, over the threshold of 10%", shifts[0].Message)
+	})
+
+	t.Run("status of removed policies is dropped", func(t *testing.T) {
+		controller, c, recorder, _ := setup(t, newProxy(nil), nil)
+
+		require.Len(t, progress(t, controller, c, recorder), 1)
+
+		update(t, c, func(proxy *contour_v1.HTTPProxy) {
+			proxy.Spec.Routes[0].TrafficShiftPolicy = nil
+		})
+
+		assert.Empty(t, progress(t, controller, c, recorder))
+		assert.Empty(t, controller.baselines)
+	})
+
+	t.Run("changing the policy restarts the shift", func(t *testing.T) {
+		controller, c, recorder, now := setup(t, newProxy(nil), nil)
+
+		progress(t, controller, c, recorder)
+		*now = now.Add(time.Minute)
+		progress(t, controller, c, recorder)
+		*now = now.Add(time.Minute)
+		shifts := progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseSucceeded, shifts[0].Phase)
+
+		update(t, c, func(proxy *contour_v1.HTTPProxy) {
+			proxy.Spec.Routes[0].TrafficShiftPolicy.Steps = []uint32{20, 100}
+		})
+
+		*now = now.Add(time.Second)
+		shifts = progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, 0, shifts[0].Step)
+		assert.Equal(t, uint32(20), shifts[0].Weight)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseProgressing, shifts[0].Phase)
+		assert.Equal(t, (&contour_v1.TrafficShiftPolicy{
+			Stable:   "kuard",
+			Canary:   "kuard-canary",
+			Steps:    []uint32{20, 100},
+			Interval: "1m",
+		}).Hash(), shifts[0].PolicyHash)
+	})
+
+	t.Run("proxies of other ingress classes are left alone", func(t *testing.T) {
+		proxy := newProxy(nil)
+		proxy.Spec.IngressClassName = "other"
+		controller, c, recorder, _ := setup(t, proxy, nil)
+
+		assert.Empty(t, progress(t, controller, c, recorder))
+	})
+
+	t.Run("rejected proxies are not progressed", func(t *testing.T) {
+		controller, c, recorder, _ := setup(t, newProxy(nil), nil)
+
+		// A change the DAG has not accepted yet.
+		proxy := &contour_v1.HTTPProxy{}
+		require.NoError(t, c.Get(context.Background(), proxyName, proxy))
+		proxy.Generation++
+		require.NoError(t, c.Update(context.Background(), proxy))
+		assert.Empty(t, progress(t, controller, c, recorder))
+
+		// A change the DAG rejected.
+		update(t, c, func(proxy *contour_v1.HTTPProxy) {
+			proxy.Spec.Routes[0].TrafficShiftPolicy.Canary = "kuard"
+			proxy.Status.Conditions[0].Status = contour_v1.ConditionFalse
+		})
+		assert.Empty(t, progress(t, controller, c, recorder))
+	})
+
+	t.Run("progress survives a restart", func(t *testing.T) {
+		counter := &fakeRequestCounter{completed: 1000, failed: 10}
+		controller, c, recorder, now := setup(t, newProxy(&contour_v1.TrafficShiftErrorRate{
+			Source:    contour_v1.TrafficShiftErrorRateSourceEnvoy,
+			Threshold: 10,
+		}), counter)
+
+		progress(t, controller, c, recorder)
+		*now = now.Add(time.Minute)
+		shifts := progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		require.Equal(t, 1, shifts[0].Step)
+		stepped := shifts[0].LastTransitionTime
+
+		// A new controller only has the status of the HTTPProxy
+		// to go by, and carries on from the recorded step.
+		restarted := NewTrafficShiftController(fixture.NewTestLogger(t), c, nil, counter, recorder)
+		restarted.now = func() time.Time { return *now }
+
+		// The counters went up while Contour was down, but only
+		// requests from the first poll of the new controller
+		// count towards the error rate of the step.
+		counter.completed, counter.failed = 2000, 900
+		*now = now.Add(30 * time.Second)
+		restarted.progress(context.Background())
+		assert.Empty(t, recorder.updates)
+
+		counter.completed, counter.failed = 2100, 905
+		*now = now.Add(10 * time.Second)
+		restarted.progress(context.Background())
+		assert.Empty(t, recorder.updates)
+
+		// The interval of the step runs from when the previous
+		// controller moved to it.
+		*now = stepped.Add(time.Minute)
+		shifts = progress(t, restarted, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, 1, shifts[0].Step)
+		assert.Equal(t, uint32(50), shifts[0].Weight)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseSucceeded, shifts[0].Phase)
+	})
+
+	t.Run("shift recorded before a restart is not restarted", func(t *testing.T) {
+		proxy := newProxy(nil)
+		started := meta_v1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(-30 * time.Second))
+		proxy.Status.TrafficShifts = []contour_v1.TrafficShiftStatus{{
+			Stable:             "kuard",
+			Canary:             "kuard-canary",
+			PolicyHash:         proxy.Spec.Routes[0].TrafficShiftPolicy.Hash(),
+			Step:               0,
+			Weight:             10,
+			Phase:              contour_v1.TrafficShiftPhaseProgressing,
+			LastTransitionTime: started,
+			Message:            "Sending 10% of requests to kuard-canary",
+		}}
+		controller, c, recorder, now := setup(t, proxy, nil)
+
+		controller.progress(context.Background())
+		assert.Empty(t, recorder.updates)
+
+		*now = now.Add(30 * time.Second)
+		shifts := progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, 1, shifts[0].Step)
+		assert.Equal(t, uint32(50), shifts[0].Weight)
+		assert.Equal(t, contour_v1.TrafficShiftPhaseProgressing, shifts[0].Phase)
+	})
+
+	t.Run("unreadable envoy error rate is reported", func(t *testing.T) {
+		controller, c, recorder, _ := setup(t, newProxy(&contour_v1.TrafficShiftErrorRate{
+			Source:    contour_v1.TrafficShiftErrorRateSourceEnvoy,
+			Threshold: 10,
+		}), nil)
+
+		shifts := progress(t, controller, c, recorder)
+		require.Len(t, shifts, 1)
+		assert.Equal(t, "Sending 10% of requests to kuard-canary; the error rate is not checked because the Envoy stats are served over HTTPS", shifts[0].Message)
+	})
+}
+
+func TestTrafficShiftControllerRequiresLeaderElection(t *testing.T) {
+	assert.True(t, (&TrafficShiftController{}).NeedLeaderElection())
+}
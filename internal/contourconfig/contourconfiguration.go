@@ -150,6 +150,10 @@ func Defaults() contour_v1alpha1.ContourConfigurationSpec {
 			Port:    8000,
 			TLS:     nil,
 		},
+		TLSCertificates: &contour_v1alpha1.TLSCertificatesConfig{
+			ExpiryWarningPeriod: ptr.To("0s"),
+			RejectExpired:       ptr.To(false),
+		},
 	}
 }
 
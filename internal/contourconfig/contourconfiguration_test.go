@@ -196,6 +196,10 @@ func TestOverlayOnDefaults(t *testing.T) {
 				KeyFile:  "keyfile.keyfile",
 			},
 		},
+		TLSCertificates: &contour_v1alpha1.TLSCertificatesConfig{
+			ExpiryWarningPeriod: ptr.To("720h"),
+			RejectExpired:       ptr.To(true),
+		},
 	}
 
 	tests := map[string]struct {
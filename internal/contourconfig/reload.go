@@ -0,0 +1,128 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contourconfig
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+)
+
+// Reload applies the fields of desired that only affect DAG and xDS
+// generation (policy, timeouts, listener options, tracing, rate limiting
+// and global external authorization) to current, and returns the result.
+// It also returns the JSON paths of the remaining fields that differ
+// between current and desired, since those only take effect once Contour
+// is restarted.
+//
+// Both current and desired are expected to have been overlaid on
+// the defaults.
+func Reload(current, desired contour_v1alpha1.ContourConfigurationSpec) (contour_v1alpha1.ContourConfigurationSpec, []string) {
+	reloaded := *current.DeepCopy()
+	desired = *desired.DeepCopy()
+
+	reloaded.Policy = desired.Policy
+	reloaded.Tracing = desired.Tracing
+	reloaded.RateLimitService = desired.RateLimitService
+	reloaded.GlobalExternalAuthorization = desired.GlobalExternalAuthorization
+
+	if reloaded.Envoy != nil && desired.Envoy != nil {
+		reloaded.Envoy.Timeouts = desired.Envoy.Timeouts
+		reloaded.Envoy.Logging = desired.Envoy.Logging
+		reloaded.Envoy.DefaultHTTPVersions = desired.Envoy.DefaultHTTPVersions
+
+		if reloaded.Envoy.HTTPListener != nil && desired.Envoy.HTTPListener != nil {
+			reloaded.Envoy.HTTPListener.AccessLog = desired.Envoy.HTTPListener.AccessLog
+		}
+		if reloaded.Envoy.HTTPSListener != nil && desired.Envoy.HTTPSListener != nil {
+			reloaded.Envoy.HTTPSListener.AccessLog = desired.Envoy.HTTPSListener.AccessLog
+		}
+
+		if reloaded.Envoy.Network != nil && desired.Envoy.Network != nil {
+			reloaded.Envoy.Network.XffNumTrustedHops = desired.Envoy.Network.XffNumTrustedHops
+			reloaded.Envoy.Network.EnvoyStripTrailingHostDot = desired.Envoy.Network.EnvoyStripTrailingHostDot
+		}
+
+		if current, desired := reloaded.Envoy.Listener, desired.Envoy.Listener; current != nil && desired != nil {
+			listener := desired.DeepCopy()
+
+			// The runtime settings are only sent to Envoy at startup
+			// and the OCSP stapling policy determines which certificates
+			// are accepted when Secrets are added to the cache.
+			listener.MaxRequestsPerIOCycle = current.MaxRequestsPerIOCycle
+			listener.MaxConnectionsPerListener = current.MaxConnectionsPerListener
+			if listener.TLS != nil {
+				listener.TLS.OCSPStaplePolicy = ""
+				if current.TLS != nil {
+					listener.TLS.OCSPStaplePolicy = current.TLS.OCSPStaplePolicy
+				}
+			}
+
+			reloaded.Envoy.Listener = listener
+		}
+	}
+
+	return reloaded, changedFields(reloaded, desired)
+}
+
+// changedFields returns the sorted JSON paths of the fields
+// that differ between a and b.
+func changedFields(a, b contour_v1alpha1.ContourConfigurationSpec) []string {
+	var changed []string
+	diffJSON("", toJSONValue(a), toJSONValue(b), &changed)
+	sort.Strings(changed)
+	return changed
+}
+
+func toJSONValue(spec contour_v1alpha1.ContourConfigurationSpec) any {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func diffJSON(path string, a, b any, changed *[]string) {
+	objA, okA := a.(map[string]any)
+	objB, okB := b.(map[string]any)
+	if !okA || !okB {
+		if !reflect.DeepEqual(a, b) {
+			*changed = append(*changed, path)
+		}
+		return
+	}
+
+	keys := map[string]struct{}{}
+	for k := range objA {
+		keys[k] = struct{}{}
+	}
+	for k := range objB {
+		keys[k] = struct{}{}
+	}
+
+	for k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		diffJSON(p, objA[k], objB[k], changed)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contourconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/contourconfig"
+)
+
+func TestReload(t *testing.T) {
+	tests := map[string]struct {
+		modify          func(*contour_v1alpha1.ContourConfigurationSpec)
+		want            func(*contour_v1alpha1.ContourConfigurationSpec)
+		restartRequired []string
+	}{
+		"no changes": {
+			modify: func(*contour_v1alpha1.ContourConfigurationSpec) {},
+			want:   func(*contour_v1alpha1.ContourConfigurationSpec) {},
+		},
+		"reloadable fields": {
+			modify: func(spec *contour_v1alpha1.ContourConfigurationSpec) {
+				spec.Policy.ApplyToIngress = ptr.To(true)
+				spec.Envoy.Timeouts.RequestTimeout = ptr.To("30s")
+				spec.Envoy.Listener.UseProxyProto = ptr.To(true)
+				spec.Envoy.Listener.TLS.MinimumProtocolVersion = "1.3"
+				spec.Envoy.HTTPListener.AccessLog = "/dev/stderr"
+				spec.Envoy.Network.XffNumTrustedHops = ptr.To(uint32(2))
+				spec.RateLimitService = &contour_v1alpha1.RateLimitServiceConfig{
+					ExtensionService: contour_v1alpha1.NamespacedName{Name: "ratelimit", Namespace: "projectcontour"},
+					Domain:           "contour",
+				}
+			},
+			want: func(spec *contour_v1alpha1.ContourConfigurationSpec) {
+				spec.Policy.ApplyToIngress = ptr.To(true)
+				spec.Envoy.Timeouts.RequestTimeout = ptr.To("30s")
+				spec.Envoy.Listener.UseProxyProto = ptr.To(true)
+				spec.Envoy.Listener.TLS.MinimumProtocolVersion = "1.3"
+				spec.Envoy.HTTPListener.AccessLog = "/dev/stderr"
+				spec.Envoy.Network.XffNumTrustedHops = ptr.To(uint32(2))
+				spec.RateLimitService = &contour_v1alpha1.RateLimitServiceConfig{
+					ExtensionService: contour_v1alpha1.NamespacedName{Name: "ratelimit", Namespace: "projectcontour"},
+					Domain:           "contour",
+				}
+			},
+		},
+		"restart required fields": {
+			modify: func(spec *contour_v1alpha1.ContourConfigurationSpec) {
+				spec.XDSServer.Port = 9001
+				spec.HTTPProxy.RootNamespaces = []string{"root"}
+				spec.Envoy.HTTPListener.Port = 8081
+				spec.Envoy.Listener.MaxRequestsPerIOCycle = ptr.To(uint32(10))
+				spec.Envoy.Listener.TLS.OCSPStaplePolicy = contour_v1alpha1.MustStapleOCSPStaplePolicy
+			},
+			want: func(*contour_v1alpha1.ContourConfigurationSpec) {},
+			restartRequired: []string{
+				"envoy.http.port",
+				"envoy.listener.maxRequestsPerIOCycle",
+				"envoy.listener.tls.ocspStaplePolicy",
+				"httpproxy.rootNamespaces",
+				"xdsServer.port",
+			},
+		},
+		"reloadable and restart required fields": {
+			modify: func(spec *contour_v1alpha1.ContourConfigurationSpec) {
+				spec.Envoy.Timeouts.ConnectTimeout = ptr.To("5s")
+				spec.Envoy.Cluster.DNSLookupFamily = contour_v1alpha1.IPv4ClusterDNSFamily
+			},
+			want: func(spec *contour_v1alpha1.ContourConfigurationSpec) {
+				spec.Envoy.Timeouts.ConnectTimeout = ptr.To("5s")
+			},
+			restartRequired: []string{
+				"envoy.cluster.dnsLookupFamily",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			current := contourconfig.Defaults()
+
+			desired := contourconfig.Defaults()
+			tc.modify(&desired)
+
+			want := contourconfig.Defaults()
+			tc.want(&want)
+
+			got, restartRequired := contourconfig.Reload(current, desired)
+			assert.Equal(t, want, got)
+			assert.Equal(t, tc.restartRequired, restartRequired)
+		})
+	}
+}
@@ -19,11 +19,11 @@ import (
 	"strings"
 
 	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/projectcontour/contour/internal/annotation"
-	"github.com/projectcontour/contour/internal/xds"
 )
 
 // EnsureService looks for a Kubernetes service in the cache matching the provided
@@ -75,6 +75,45 @@ func (d *DAG) EnsureService(meta types.NamespacedName, port, healthPort int, cac
 	}, nil
 }
 
+// EnsureServiceImport looks for a multi-cluster ServiceImport in the cache matching
+// the provided namespace, name and port, and returns a DAG service for it. The
+// endpoints of the service are those of the EndpointSlices derived for the
+// ServiceImport. If a matching ServiceImport cannot be found in the cache, an
+// error is returned.
+func (d *DAG) EnsureServiceImport(meta types.NamespacedName, port, healthPort int, cache *KubernetesCache) (*Service, error) {
+	svcImport, svcPort, err := cache.LookupServiceImport(meta, intstr.FromInt(port))
+	if err != nil {
+		return nil, err
+	}
+
+	healthSvcPort := svcPort
+	if healthPort != 0 && healthPort != port {
+		_, healthSvcPort, err = cache.LookupServiceImport(meta, intstr.FromInt(healthPort))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Service{
+		Weighted: WeightedService{
+			ServiceName:      svcImport.Name,
+			ServiceNamespace: svcImport.Namespace,
+			ServicePort:      svcPort,
+			HealthPort:       healthSvcPort,
+			Weight:           1,
+			Imported:         true,
+		},
+		Protocol: upstreamProtocol(svcImport, svcPort),
+		CircuitBreakers: CircuitBreakers{
+			MaxConnections:        annotation.MaxConnections(svcImport),
+			MaxPendingRequests:    annotation.MaxPendingRequests(svcImport),
+			MaxRequests:           annotation.MaxRequests(svcImport),
+			PerHostMaxConnections: annotation.PerHostMaxConnections(svcImport),
+			MaxRetries:            annotation.MaxRetries(svcImport),
+		},
+	}, nil
+}
+
 func validateExternalName(svc *core_v1.Service, enableExternalNameSvc bool) error {
 	// If this isn't an ExternalName Service, we're all good here.
 	en := externalName(svc)
@@ -125,14 +164,14 @@ func toContourProtocol(appProtocol string) (string, bool) {
 	return proto, ok
 }
 
-func upstreamProtocol(svc *core_v1.Service, port core_v1.ServicePort) string {
+func upstreamProtocol(svc meta_v1.Object, port core_v1.ServicePort) string {
 	// if appProtocol is not nil, check it only
 	if port.AppProtocol != nil {
 		proto, _ := toContourProtocol(*port.AppProtocol)
 		return proto
 	}
 
-	up := annotation.ParseUpstreamProtocols(svc.Annotations)
+	up := annotation.ParseUpstreamProtocols(svc.GetAnnotations())
 	proto := up[port.Name]
 	if proto == "" {
 		proto = up[strconv.Itoa(int(port.Port))]
@@ -264,8 +303,9 @@ func (d *DAG) GetDNSNameClusters() []*DNSNameCluster {
 	for _, listener := range d.Listeners {
 		for _, svhost := range listener.SecureVirtualHosts {
 			for _, provider := range svhost.JWTProviders {
-				provider := provider
-				res = append(res, &provider.RemoteJWKS.Cluster)
+				if provider.RemoteJWKS != nil {
+					res = append(res, &provider.RemoteJWKS.Cluster)
+				}
 			}
 		}
 	}
@@ -285,16 +325,19 @@ func (d *DAG) GetServiceClusters() []*ServiceCluster {
 			continue
 		}
 
+		// The endpoints of the services of a traffic shift are
+		// weighted by the current step of the shift, so that
+		// moving to the next step only updates EDS.
+		if cluster.TrafficShift != nil {
+			res = append(res, cluster.trafficShiftServiceCluster())
+			continue
+		}
+
 		// A Service has only one WeightedService entry. Fake up a
 		// ServiceCluster so that the visitor can pretend to not
 		// know this.
 		c := &ServiceCluster{
-			ClusterName: xds.ClusterLoadAssignmentName(
-				types.NamespacedName{
-					Name:      cluster.Upstream.Weighted.ServiceName,
-					Namespace: cluster.Upstream.Weighted.ServiceNamespace,
-				},
-				cluster.Upstream.Weighted.ServicePort.Name),
+			ClusterName: cluster.Upstream.Weighted.ClusterLoadAssignmentName(),
 			Services: []WeightedService{
 				cluster.Upstream.Weighted,
 			},
@@ -326,9 +369,7 @@ func (d *DAG) GetSecrets() []*Secret {
 	var res []*Secret
 	for _, l := range d.Listeners {
 		for _, svh := range l.SecureVirtualHosts {
-			if svh.Secret != nil {
-				res = append(res, svh.Secret)
-			}
+			res = append(res, svh.Secrets()...)
 			if svh.FallbackCertificate != nil {
 				res = append(res, svh.FallbackCertificate)
 			}
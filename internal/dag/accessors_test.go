@@ -384,4 +384,37 @@ func TestGetServiceClusters(t *testing.T) {
 	}
 	// We should get two clusters since we have mirrorPolicies.
 	assert.Len(t, dagWithMirror.GetServiceClusters(), 2)
+
+	stable := WeightedService{ServiceName: "stable", ServiceNamespace: "default", ServicePort: core_v1.ServicePort{Port: 80}}
+	canary := WeightedService{ServiceName: "canary", ServiceNamespace: "default", ServicePort: core_v1.ServicePort{Port: 8080}}
+	dagWithTrafficShift := &DAG{
+		Listeners: map[string]*Listener{
+			"http-1": {
+				VirtualHosts: []*VirtualHost{
+					{
+						Routes: map[string]*Route{
+							"foo": {
+								Clusters: []*Cluster{
+									{
+										Upstream: &Service{Weighted: stable},
+										TrafficShift: &TrafficShift{
+											ClusterLoadAssignmentName: "trafficshift/default/proxy/stable/canary/80/8080",
+											Canary:                    &Service{Weighted: canary},
+											Weight:                    30,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	// The services of a traffic shift are weighted by its step.
+	stable.Weight, canary.Weight = 70, 30
+	assert.Equal(t, []*ServiceCluster{{
+		ClusterName: "trafficshift/default/proxy/stable/canary/80/8080",
+		Services:    []WeightedService{stable, canary},
+	}}, dagWithTrafficShift.GetServiceClusters())
 }
@@ -15,12 +15,11 @@ package dag
 
 import (
 	"sort"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"k8s.io/apimachinery/pkg/types"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
 
-	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/metrics"
 	"github.com/projectcontour/contour/internal/status"
 )
@@ -57,17 +56,13 @@ type Builder struct {
 // Build builds and returns a new DAG by running the
 // configured DAG processors, in order.
 func (b *Builder) Build() *DAG {
-	gatewayNSName := types.NamespacedName{}
-	if b.Source.gateway != nil {
-		gatewayNSName = k8s.NamespacedNameOf(b.Source.gateway)
-	}
 	var gatewayController gatewayapi_v1.GatewayController
 	if b.Source.gatewayclass != nil {
 		gatewayController = b.Source.gatewayclass.Spec.ControllerName
 	}
 
 	dag := &DAG{
-		StatusCache: status.NewCache(gatewayNSName, gatewayController),
+		StatusCache: status.NewCache(gatewayController),
 		Listeners:   map[string]*Listener{},
 	}
 
@@ -76,10 +71,18 @@ func (b *Builder) Build() *DAG {
 		defer t.ObserveDuration()
 	}
 
+	b.Source.rebuildAt = time.Time{}
+
 	for _, p := range b.Processors {
 		p.Run(dag, &b.Source)
 	}
 
+	dag.RebuildAt = b.Source.rebuildAt
+
+	// Processors only need to know what changed
+	// since the last build.
+	b.Source.resetChanges()
+
 	// Prune invalid virtual hosts, and Listeners
 	// without any valid virtual hosts.
 	listeners := map[string]*Listener{}
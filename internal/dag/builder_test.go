@@ -30,6 +30,8 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/fixture"
@@ -49,6 +51,17 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 		},
 	}
 
+	kuardServiceImport := &mcs_v1alpha1.ServiceImport{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "projectcontour",
+		},
+		Spec: mcs_v1alpha1.ServiceImportSpec{
+			Type:  mcs_v1alpha1.ClusterSetIP,
+			Ports: []mcs_v1alpha1.ServicePort{{Name: "http", Protocol: "TCP", Port: 8080}},
+		},
+	}
+
 	kuardService2 := &core_v1.Service{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "kuard2",
@@ -249,7 +262,7 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 		},
 		Spec: gatewayapi_v1.GatewaySpec{
 			GatewayClassName: gatewayapi_v1.ObjectName(validClass.Name),
-			Addresses: []gatewayapi_v1.GatewayAddress{
+			Addresses: []gatewayapi_v1.GatewaySpecAddress{
 				{
 					Type:  ptr.To(gatewayapi_v1.IPAddressType),
 					Value: "1.2.3.4",
@@ -386,6 +399,15 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 		Data: secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY),
 	}
 
+	secECDSA := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "secret-ecdsa",
+			Namespace: "projectcontour",
+		},
+		Type: core_v1.SecretTypeTLS,
+		Data: secretdata(fixture.EC_CERTIFICATE, fixture.EC_PRIVATE_KEY),
+	}
+
 	gatewayTLSTerminateCertInDifferentNamespace := &gatewayapi_v1.Gateway{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "contour",
@@ -542,6 +564,84 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 		}},
 	})
 
+	secretCustomNs := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "custom",
+		},
+		Type: core_v1.SecretTypeTLS,
+		Data: secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY),
+	}
+
+	gatewayHTTPAllowedListeners := &gatewayapi_v1.Gateway{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "contour",
+			Namespace: "projectcontour",
+		},
+		Spec: gatewayapi_v1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1.ObjectName(validClass.Name),
+			Listeners: []gatewayapi_v1.Listener{{
+				Name:     "http",
+				Port:     80,
+				Protocol: gatewayapi_v1.HTTPProtocolType,
+				AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+					Namespaces: &gatewayapi_v1.RouteNamespaces{
+						From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+					},
+				},
+			}},
+			AllowedListeners: &gatewayapi_v1.AllowedListeners{
+				Namespaces: &gatewayapi_v1.ListenerNamespaces{
+					From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+				},
+			},
+		},
+	}
+
+	makeListenerSet := func(certificateRef gatewayapi_v1.SecretObjectReference) *gatewayapi_x_v1alpha1.XListenerSet {
+		return &gatewayapi_x_v1alpha1.XListenerSet{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "listenerset",
+				Namespace: "custom",
+			},
+			Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+				ParentRef: gatewayapi_x_v1alpha1.ParentGatewayReference{
+					Name:      "contour",
+					Namespace: ptr.To(gatewayapi_v1.Namespace("projectcontour")),
+				},
+				Listeners: []gatewayapi_x_v1alpha1.ListenerEntry{
+					{
+						// Conflicts with the Gateway's listener.
+						Name:     "http",
+						Port:     80,
+						Protocol: gatewayapi_v1.HTTPProtocolType,
+						AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+							Namespaces: &gatewayapi_v1.RouteNamespaces{
+								From: ptr.To(gatewayapi_v1.NamespacesFromSame),
+							},
+						},
+					},
+					{
+						Name:     "https",
+						Port:     443,
+						Protocol: gatewayapi_v1.HTTPSProtocolType,
+						TLS: &gatewayapi_v1.GatewayTLSConfig{
+							CertificateRefs: []gatewayapi_v1.SecretObjectReference{certificateRef},
+						},
+						AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+							Namespaces: &gatewayapi_v1.RouteNamespaces{
+								From: ptr.To(gatewayapi_v1.NamespacesFromSame),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	listenerSetHTTPRoute := makeHTTPRoute("listenerset", "custom", "listenerset.projectcontour.io", makeHTTPRouteRule(gatewayapi_v1.PathMatchPathPrefix, "/", "kuard", 8080, 1))
+	listenerSetHTTPRoute.Spec.ParentRefs = []gatewayapi_v1.ParentReference{gatewayapi.ListenerSetParentRef("", "listenerset")}
+
 	tests := map[string]struct {
 		objs         []any
 		gatewayclass *gatewayapi_v1.GatewayClass
@@ -1644,6 +1744,54 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 				},
 			),
 		},
+		"insert basic single route, single hostname, gateway with multiple TLS certificates": {
+			gatewayclass: validClass,
+			gateway: &gatewayapi_v1.Gateway{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "contour",
+					Namespace: "projectcontour",
+				},
+				Spec: gatewayapi_v1.GatewaySpec{
+					GatewayClassName: gatewayapi_v1.ObjectName(validClass.Name),
+					Listeners: []gatewayapi_v1.Listener{{
+						Port:     443,
+						Protocol: gatewayapi_v1.HTTPSProtocolType,
+						TLS: &gatewayapi_v1.GatewayTLSConfig{
+							CertificateRefs: []gatewayapi_v1.SecretObjectReference{
+								gatewayapi.CertificateRef(sec1.Name, sec1.Namespace),
+								gatewayapi.CertificateRef(secECDSA.Name, secECDSA.Namespace),
+							},
+						},
+						AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+							Namespaces: &gatewayapi_v1.RouteNamespaces{
+								From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+							},
+						},
+					}},
+				},
+			},
+			objs: []any{
+				sec1,
+				secECDSA,
+				kuardService,
+				basicHTTPRoute,
+			},
+			want: listeners(
+				&Listener{
+					Name: "https-443",
+					SecureVirtualHosts: securevirtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name:   "test.projectcontour.io",
+								Routes: routes(prefixrouteHTTPRoute("/", service(kuardService))),
+							},
+							Secret:            secret(sec1),
+							AdditionalSecrets: []*Secret{secret(secECDSA)},
+						},
+					),
+				},
+			),
+		},
 		"insert basic single route, single hostname, gateway with missing TLS certificate": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPSAllNamespaces,
@@ -4015,6 +4163,206 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 				},
 			),
 		},
+		"GRPCRoute with BackendTLSPolicy": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			upstreamTLS: &UpstreamTLS{
+				MinimumProtocolVersion: "1.2",
+				MaximumProtocolVersion: "1.2",
+			},
+			objs: []any{
+				tlsService,
+				configMapCert1,
+				&gatewayapi_v1.GRPCRoute{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1.GRPCRouteSpec{
+						CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+						},
+						Hostnames: []gatewayapi_v1.Hostname{"test.projectcontour.io"},
+						Rules: []gatewayapi_v1.GRPCRouteRule{{
+							Matches: []gatewayapi_v1.GRPCRouteMatch{{
+								Method: gatewayapi.GRPCMethodMatch(gatewayapi_v1.GRPCMethodMatchExact, "io.projectcontour", "Login"),
+							}},
+							BackendRefs: gatewayapi.GRPCRouteBackendRef("tlssvc", 443, 1),
+						}},
+					},
+				},
+				&gatewayapi_v1alpha3.BackendTLSPolicy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_v1alpha3.BackendTLSPolicySpec{
+						TargetRefs: []gatewayapi_v1alpha2.LocalPolicyTargetReferenceWithSectionName{
+							{
+								LocalPolicyTargetReference: gatewayapi_v1alpha2.LocalPolicyTargetReference{
+									Kind: "Service",
+									Name: "tlssvc",
+								},
+							},
+						},
+						Validation: gatewayapi_v1alpha3.BackendTLSPolicyValidation{
+							CACertificateRefs: []gatewayapi_v1.LocalObjectReference{{
+								Kind: "ConfigMap",
+								Name: gatewayapi_v1.ObjectName(configMapCert1.Name),
+							}},
+							Hostname: "example.com",
+						},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: "http-80",
+					VirtualHosts: virtualhosts(
+						virtualhost("test.projectcontour.io", &Route{
+							PathMatchCondition: &ExactMatchCondition{Path: "/io.projectcontour/Login"},
+							Clusters: []*Cluster{{
+								Weight:   1,
+								Upstream: grpcService(tlsService, "h2"),
+								Protocol: "h2",
+								UpstreamValidation: &PeerValidationContext{
+									CACertificates: []*Secret{
+										caSecret(cert1),
+									},
+									SubjectNames: []string{"example.com"},
+								},
+								UpstreamTLS: &UpstreamTLS{
+									MinimumProtocolVersion: "1.2",
+									MaximumProtocolVersion: "1.2",
+								},
+							}},
+						}),
+					),
+				},
+			),
+		},
+		"HTTPRoute with XBackendTrafficPolicy": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []any{
+				kuardService,
+				makeHTTPRoute("basic", "projectcontour", "", makeHTTPRouteRule(gatewayapi_v1.PathMatchPathPrefix, "/", "kuard", 8080, 1)),
+				&gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_x_v1alpha1.BackendTrafficPolicySpec{
+						TargetRefs: []gatewayapi_v1alpha2.LocalPolicyTargetReference{{
+							Kind: "Service",
+							Name: "kuard",
+						}},
+						SessionPersistence: &gatewayapi_v1.SessionPersistence{
+							SessionName:     ptr.To("session"),
+							AbsoluteTimeout: ptr.To(gatewayapi_v1.Duration("1h")),
+							CookieConfig: &gatewayapi_v1.CookieConfig{
+								LifetimeType: ptr.To(gatewayapi_v1.PermanentCookieLifetimeType),
+							},
+						},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: "http-80",
+					VirtualHosts: virtualhosts(
+						virtualhost("*", &Route{
+							PathMatchCondition: prefixString("/"),
+							Clusters: []*Cluster{{
+								Weight:             1,
+								Upstream:           service(kuardService),
+								LoadBalancerPolicy: LoadBalancerPolicyCookie,
+							}},
+							RequestHashPolicies: []RequestHashPolicy{{
+								CookieHashOptions: &CookieHashOptions{
+									CookieName: "session",
+									TTL:        time.Hour,
+									Path:       "/",
+								},
+							}},
+						}),
+					),
+				},
+			),
+		},
+		"HTTPRoute with XBackendTrafficPolicy using header session persistence": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []any{
+				kuardService,
+				makeHTTPRoute("basic", "projectcontour", "", makeHTTPRouteRule(gatewayapi_v1.PathMatchPathPrefix, "/", "kuard", 8080, 1)),
+				&gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "basic",
+						Namespace: "projectcontour",
+					},
+					Spec: gatewayapi_x_v1alpha1.BackendTrafficPolicySpec{
+						TargetRefs: []gatewayapi_v1alpha2.LocalPolicyTargetReference{{
+							Kind: "Service",
+							Name: "kuard",
+						}},
+						SessionPersistence: &gatewayapi_v1.SessionPersistence{
+							Type: ptr.To(gatewayapi_v1.HeaderBasedSessionPersistence),
+						},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: "http-80",
+					VirtualHosts: virtualhosts(
+						virtualhost("*", &Route{
+							PathMatchCondition: prefixString("/"),
+							Clusters: []*Cluster{{
+								Weight:             1,
+								Upstream:           service(kuardService),
+								LoadBalancerPolicy: LoadBalancerPolicyRequestHash,
+							}},
+							RequestHashPolicies: []RequestHashPolicy{{
+								HeaderHashOptions: &HeaderHashOptions{
+									HeaderName: "X-Contour-Session-Affinity",
+								},
+							}},
+						}),
+					),
+				},
+			),
+		},
+		"HTTPRoute with ServiceImport backendRef": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []any{
+				kuardService,
+				kuardServiceImport,
+				makeHTTPRoute("basic", "projectcontour", "", gatewayapi_v1.HTTPRouteRule{
+					Matches: gatewayapi.HTTPRouteMatch(gatewayapi_v1.PathMatchPathPrefix, "/"),
+					BackendRefs: []gatewayapi_v1.HTTPBackendRef{{
+						BackendRef: gatewayapi_v1.BackendRef{
+							BackendObjectReference: gatewayapi_v1.BackendObjectReference{
+								Group: ptr.To(gatewayapi_v1.Group(mcs_v1alpha1.GroupName)),
+								Kind:  ptr.To(gatewayapi_v1.Kind("ServiceImport")),
+								Name:  "kuard",
+								Port:  ptr.To(gatewayapi_v1.PortNumber(8080)),
+							},
+							Weight: ptr.To(int32(1)),
+						},
+					}},
+				}),
+			},
+			want: listeners(
+				&Listener{
+					Name: "http-80",
+					VirtualHosts: virtualhosts(
+						virtualhost("*", prefixrouteHTTPRoute("/", importedService(kuardServiceImport))),
+					),
+				},
+			),
+		},
 		"different weights for multiple forwardTos": {
 			gatewayclass: validClass,
 			gateway:      gatewayHTTPAllNamespaces,
@@ -5621,6 +5969,126 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 				VirtualHosts: virtualhosts(virtualhost("*", exactrouteGRPCRoute("/io.projectcontour/Login", grpcService(kuardService, "h2c")))),
 			}),
 		},
+		"ListenerSet listeners are merged with the Gateway's listeners": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllowedListeners,
+			objs: []any{
+				secretCustomNs,
+				kuardService,
+				kuardServiceCustomNs,
+				basicHTTPRoute,
+				makeListenerSet(gatewayapi.CertificateRef(secretCustomNs.Name, "")),
+				listenerSetHTTPRoute,
+			},
+			want: listeners(
+				&Listener{
+					Name: "http-80",
+					VirtualHosts: virtualhosts(
+						virtualhost("test.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+					),
+				},
+				&Listener{
+					Name: "https-443",
+					SecureVirtualHosts: securevirtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name:   "listenerset.projectcontour.io",
+								Routes: routes(prefixrouteHTTPRoute("/", service(kuardServiceCustomNs))),
+							},
+							Secret: secret(secretCustomNs),
+						},
+					),
+				},
+			),
+		},
+		"ListenerSet not allowed by the Gateway": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllNamespaces,
+			objs: []any{
+				secretCustomNs,
+				kuardService,
+				kuardServiceCustomNs,
+				basicHTTPRoute,
+				makeListenerSet(gatewayapi.CertificateRef(secretCustomNs.Name, "")),
+				listenerSetHTTPRoute,
+			},
+			want: listeners(
+				&Listener{
+					Name: "http-80",
+					VirtualHosts: virtualhosts(
+						virtualhost("test.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+					),
+				},
+			),
+		},
+		"ListenerSet listener with a certificate in another namespace, no ReferenceGrant": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllowedListeners,
+			objs: []any{
+				sec1,
+				kuardService,
+				kuardServiceCustomNs,
+				basicHTTPRoute,
+				makeListenerSet(gatewayapi.CertificateRef(sec1.Name, sec1.Namespace)),
+				listenerSetHTTPRoute,
+			},
+			want: listeners(
+				&Listener{
+					Name: "http-80",
+					VirtualHosts: virtualhosts(
+						virtualhost("test.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+					),
+				},
+			),
+		},
+		"ListenerSet listener with a certificate in another namespace, with ReferenceGrant": {
+			gatewayclass: validClass,
+			gateway:      gatewayHTTPAllowedListeners,
+			objs: []any{
+				sec1,
+				kuardService,
+				kuardServiceCustomNs,
+				basicHTTPRoute,
+				makeListenerSet(gatewayapi.CertificateRef(sec1.Name, sec1.Namespace)),
+				listenerSetHTTPRoute,
+				&gatewayapi_v1beta1.ReferenceGrant{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "tls-cert-reference-policy",
+						Namespace: sec1.Namespace,
+					},
+					Spec: gatewayapi_v1beta1.ReferenceGrantSpec{
+						From: []gatewayapi_v1beta1.ReferenceGrantFrom{{
+							Group:     gatewayapi_x_v1alpha1.GroupName,
+							Kind:      "XListenerSet",
+							Namespace: "custom",
+						}},
+						To: []gatewayapi_v1beta1.ReferenceGrantTo{{
+							Kind: "Secret",
+						}},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Name: "http-80",
+					VirtualHosts: virtualhosts(
+						virtualhost("test.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+					),
+				},
+				&Listener{
+					Name: "https-443",
+					SecureVirtualHosts: securevirtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name:   "listenerset.projectcontour.io",
+								Routes: routes(prefixrouteHTTPRoute("/", service(kuardServiceCustomNs))),
+							},
+							Secret: secret(sec1),
+						},
+					),
+				},
+			),
+		},
 	}
 
 	for name, tc := range tests {
@@ -5662,6 +6130,132 @@ func TestDAGInsertGatewayAPI(t *testing.T) {
 	}
 }
 
+func TestDAGInsertGatewayAPIMultipleGateways(t *testing.T) {
+	kuardService := &core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "projectcontour",
+		},
+		Spec: core_v1.ServiceSpec{
+			Ports: []core_v1.ServicePort{makeServicePort("http", "TCP", 8080, 8080)},
+		},
+	}
+
+	gatewayClass := &gatewayapi_v1.GatewayClass{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "contour",
+		},
+		Spec: gatewayapi_v1.GatewayClassSpec{
+			ControllerName: "projectcontour.io/contour",
+		},
+	}
+
+	gateway := func(name, className string) *gatewayapi_v1.Gateway {
+		return &gatewayapi_v1.Gateway{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      name,
+				Namespace: "projectcontour",
+			},
+			Spec: gatewayapi_v1.GatewaySpec{
+				GatewayClassName: gatewayapi_v1.ObjectName(className),
+				Listeners: []gatewayapi_v1.Listener{{
+					Name:     "http",
+					Port:     80,
+					Protocol: gatewayapi_v1.HTTPProtocolType,
+					AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1.RouteNamespaces{
+							From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	route := func(name, hostname string, gateways ...string) *gatewayapi_v1.HTTPRoute {
+		route := makeHTTPRoute(name, "projectcontour", hostname, makeHTTPRouteRule(gatewayapi_v1.PathMatchPathPrefix, "/", "kuard", 8080, 1))
+		route.Spec.ParentRefs = nil
+		for _, gateway := range gateways {
+			route.Spec.ParentRefs = append(route.Spec.ParentRefs, gatewayapi.GatewayParentRef("projectcontour", gateway))
+		}
+		return route
+	}
+
+	builder := Builder{
+		Source: KubernetesCache{
+			ConfiguredGatewaysToCache: []types.NamespacedName{
+				{Namespace: "projectcontour", Name: "gateway-1"},
+				{Namespace: "projectcontour", Name: "gateway-2"},
+				{Namespace: "projectcontour", Name: "gateway-3"},
+			},
+			gatewayclass: gatewayClass,
+			FieldLogger:  fixture.NewTestLogger(t),
+		},
+		Processors: []Processor{
+			&ListenerProcessor{
+				HTTPAddress:  "0.0.0.0",
+				HTTPSAddress: "0.0.0.0",
+			},
+			&GatewayAPIProcessor{
+				FieldLogger: fixture.NewTestLogger(t),
+			},
+		},
+	}
+
+	for _, o := range []any{
+		gateway("gateway-1", "contour"),
+		gateway("gateway-2", "contour"),
+		// Gateways of another GatewayClass are not processed.
+		gateway("gateway-3", "other"),
+		// Gateways that are not configured are not cached.
+		gateway("gateway-4", "contour"),
+		kuardService,
+		route("one", "one.projectcontour.io", "gateway-1"),
+		route("two", "two.projectcontour.io", "gateway-2"),
+		route("shared", "shared.projectcontour.io", "gateway-1", "gateway-2"),
+	} {
+		builder.Source.Insert(o)
+	}
+
+	dag := builder.Build()
+
+	listener := func(name string, vhosts ...*VirtualHost) *Listener {
+		return &Listener{
+			Name:             name,
+			Protocol:         "http",
+			Address:          "0.0.0.0",
+			Port:             8080,
+			EnableWebsockets: true,
+			VirtualHosts:     vhosts,
+		}
+	}
+
+	assert.Equal(t, map[string]*Listener{
+		"projectcontour/gateway-1/http-80": listener("projectcontour/gateway-1/http-80",
+			virtualhost("one.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+			virtualhost("shared.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+		),
+		"projectcontour/gateway-2/http-80": listener("projectcontour/gateway-2/http-80",
+			virtualhost("shared.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+			virtualhost("two.projectcontour.io", prefixrouteHTTPRoute("/", service(kuardService))),
+		),
+	}, dag.Listeners)
+
+	// Status is written for each processed Gateway.
+	var gateways []string
+	for _, gu := range dag.StatusCache.GetGatewayUpdates() {
+		gateways = append(gateways, gu.FullName.String())
+	}
+	assert.ElementsMatch(t, []string{"projectcontour/gateway-1", "projectcontour/gateway-2"}, gateways)
+
+	// Routes attached to several Gateways have an update for each.
+	var routeUpdates []string
+	for _, ru := range dag.StatusCache.GetRouteUpdates() {
+		routeUpdates = append(routeUpdates, ru.GatewayRef.Name+"/"+ru.FullName.Name)
+	}
+	assert.ElementsMatch(t, []string{"gateway-1/one", "gateway-2/two", "gateway-1/shared", "gateway-2/shared"}, routeUpdates)
+}
+
 func TestDAGInsert(t *testing.T) {
 	// The DAG is insensitive to ordering, adding an ingress, then a service,
 	// should have the same result as adding a service, then an ingress.
@@ -6570,6 +7164,44 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// proxyServiceImport balances between a local Service
+	// and a multi-cluster ServiceImport of the same name.
+	proxyServiceImport := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}, {
+					Name: "kuard",
+					Kind: "ServiceImport",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	si1 := &mcs_v1alpha1.ServiceImport{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: mcs_v1alpha1.ServiceImportSpec{
+			Type:  mcs_v1alpha1.ClusterSetIP,
+			Ports: []mcs_v1alpha1.ServicePort{{Name: "http", Protocol: "TCP", Port: 8080}},
+		},
+	}
+
 	proxyTLS12 := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "example-com",
@@ -10109,6 +10741,34 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert httpproxy referencing a service and a serviceimport": {
+			objs: []any{
+				proxyServiceImport, s1, si1,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", prefixroute("/", service(s1), importedService(si1))),
+					),
+				},
+			),
+		},
+		"insert httpproxy referencing a service and a missing serviceimport": {
+			objs: []any{
+				proxyServiceImport, s1,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", prefixroute("/", service(s1))),
+					),
+				},
+			),
+		},
 		"ingressv1: insert ingress w/ tls min proto annotation": {
 			objs: []any{
 				i10aV1,
@@ -11608,6 +12268,61 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert httpproxy w/ tcpproxy w/ idle timeout, access log and upstream proxy protocol": {
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: s1.Namespace,
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "passthrough.example.com",
+							TLS: &contour_v1.TLS{
+								Passthrough: true,
+							},
+						},
+						TCPProxy: &contour_v1.TCPProxy{
+							IdleTimeout:           "10m",
+							MaxConnectAttempts:    3,
+							UpstreamProxyProtocol: "v2",
+							AccessLog: &contour_v1.TCPProxyAccessLog{
+								Format: "%DOWNSTREAM_REMOTE_ADDRESS% %UPSTREAM_HOST%\n",
+							},
+							Services: []contour_v1.Service{{
+								Name: s1.Name,
+								Port: 8080,
+							}},
+						},
+					},
+				},
+				s1,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTPS_LISTENER_NAME,
+					Port: 8443,
+					SecureVirtualHosts: securevirtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "passthrough.example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters: []*Cluster{{
+									Upstream:              service(s1),
+									UpstreamProxyProtocol: "v2",
+								}},
+								IdleTimeout:        timeout.DurationSetting(10 * time.Minute),
+								MaxConnectAttempts: 3,
+								AccessLog: &TCPProxyAccessLog{
+									Format: "%DOWNSTREAM_REMOTE_ADDRESS% %UPSTREAM_HOST%\n",
+								},
+							},
+						},
+					),
+				},
+			),
+		},
 		"insert httproxy w/ route w/ no services": {
 			objs: []any{proxy41, s1},
 			want: listeners(), // expect empty, route is invalid so vhost is invalid
@@ -15965,6 +16680,24 @@ func weightedService(s *core_v1.Service, weight uint32) *Service {
 	}
 }
 
+func importedService(s *mcs_v1alpha1.ServiceImport) *Service {
+	port := core_v1.ServicePort{
+		Name:     s.Spec.Ports[0].Name,
+		Protocol: s.Spec.Ports[0].Protocol,
+		Port:     s.Spec.Ports[0].Port,
+	}
+	return &Service{
+		Weighted: WeightedService{
+			Weight:           1,
+			ServiceName:      s.Name,
+			ServiceNamespace: s.Namespace,
+			ServicePort:      port,
+			HealthPort:       port,
+			Imported:         true,
+		},
+	}
+}
+
 func grpcService(s *core_v1.Service, protocol string) *Service {
 	return &Service{
 		Protocol: protocol,
@@ -17,12 +17,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
@@ -32,6 +37,8 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -59,26 +66,63 @@ type KubernetesCache struct {
 	// If set, only the Gateway with this namespace/name will be kept.
 	ConfiguredGatewayToCache *types.NamespacedName
 
+	// ConfiguredGatewaysToCache is the optional names of several Gateways,
+	// of the same GatewayClass, to cache. If set, only the Gateways with
+	// these namespace/names will be kept, and ConfiguredGatewayToCache
+	// is not used.
+	ConfiguredGatewaysToCache []types.NamespacedName
+
 	// Secrets that are referred from the configuration file.
 	ConfiguredSecretRefs []*types.NamespacedName
 
+	// CertificateExpiryWarningPeriod defines how long before a serving
+	// certificate expires it is reported as expiring soon. Zero only
+	// reports expired certificates.
+	CertificateExpiryWarningPeriod time.Duration
+
+	// RejectExpiredCertificates defines whether expired serving
+	// certificates are treated as invalid TLS Secrets.
+	RejectExpiredCertificates bool
+
+	// RequireOCSPStaple defines whether serving certificates without
+	// an OCSP staple are treated as invalid TLS Secrets. It is set when
+	// listeners use the must-staple OCSP policy.
+	RequireOCSPStaple bool
+
 	ingresses                 map[types.NamespacedName]*networking_v1.Ingress
 	httpproxies               map[types.NamespacedName]*contour_v1.HTTPProxy
 	secrets                   map[types.NamespacedName]*Secret
 	configmapsecrets          map[types.NamespacedName]*Secret
+	jwksconfigmaps            map[types.NamespacedName]*core_v1.ConfigMap
 	tlscertificatedelegations map[types.NamespacedName]*contour_v1.TLSCertificateDelegation
 	services                  map[types.NamespacedName]*core_v1.Service
+	serviceimports            map[types.NamespacedName]*mcs_v1alpha1.ServiceImport
 	namespaces                map[string]*core_v1.Namespace
 	gatewayclass              *gatewayapi_v1.GatewayClass
 	gateway                   *gatewayapi_v1.Gateway
+	gateways                  map[types.NamespacedName]*gatewayapi_v1.Gateway
 	httproutes                map[types.NamespacedName]*gatewayapi_v1.HTTPRoute
 	tlsroutes                 map[types.NamespacedName]*gatewayapi_v1alpha2.TLSRoute
 	grpcroutes                map[types.NamespacedName]*gatewayapi_v1.GRPCRoute
 	tcproutes                 map[types.NamespacedName]*gatewayapi_v1alpha2.TCPRoute
 	referencegrants           map[types.NamespacedName]*gatewayapi_v1beta1.ReferenceGrant
 	backendtlspolicies        map[types.NamespacedName]*gatewayapi_v1alpha3.BackendTLSPolicy
+	backendtrafficpolicies    map[types.NamespacedName]*gatewayapi_x_v1alpha1.XBackendTrafficPolicy
+	listenersets              map[types.NamespacedName]*gatewayapi_x_v1alpha1.XListenerSet
 	extensions                map[types.NamespacedName]*contour_v1alpha1.ExtensionService
 
+	// changes and changedKinds hold the objects, and their kinds,
+	// inserted or removed since the last DAG build.
+	changes      map[dependency]struct{}
+	changedKinds map[string]struct{}
+
+	// deps, if set, records the objects looked up from the cache.
+	deps *dependencies
+
+	// rebuildAt is the earliest time after which the DAG
+	// being built is stale, or zero if there is none.
+	rebuildAt time.Time
+
 	// Metrics contains Prometheus metrics.
 	Metrics *metrics.Metrics
 
@@ -104,16 +148,23 @@ func (kc *KubernetesCache) init() {
 	kc.httpproxies = make(map[types.NamespacedName]*contour_v1.HTTPProxy)
 	kc.secrets = make(map[types.NamespacedName]*Secret)
 	kc.configmapsecrets = make(map[types.NamespacedName]*Secret)
+	kc.jwksconfigmaps = make(map[types.NamespacedName]*core_v1.ConfigMap)
 	kc.tlscertificatedelegations = make(map[types.NamespacedName]*contour_v1.TLSCertificateDelegation)
 	kc.services = make(map[types.NamespacedName]*core_v1.Service)
+	kc.serviceimports = make(map[types.NamespacedName]*mcs_v1alpha1.ServiceImport)
 	kc.namespaces = make(map[string]*core_v1.Namespace)
+	kc.gateways = make(map[types.NamespacedName]*gatewayapi_v1.Gateway)
 	kc.httproutes = make(map[types.NamespacedName]*gatewayapi_v1.HTTPRoute)
 	kc.referencegrants = make(map[types.NamespacedName]*gatewayapi_v1beta1.ReferenceGrant)
 	kc.tlsroutes = make(map[types.NamespacedName]*gatewayapi_v1alpha2.TLSRoute)
 	kc.grpcroutes = make(map[types.NamespacedName]*gatewayapi_v1.GRPCRoute)
 	kc.tcproutes = make(map[types.NamespacedName]*gatewayapi_v1alpha2.TCPRoute)
 	kc.backendtlspolicies = make(map[types.NamespacedName]*gatewayapi_v1alpha3.BackendTLSPolicy)
+	kc.backendtrafficpolicies = make(map[types.NamespacedName]*gatewayapi_x_v1alpha1.XBackendTrafficPolicy)
+	kc.listenersets = make(map[types.NamespacedName]*gatewayapi_x_v1alpha1.XListenerSet)
 	kc.extensions = make(map[types.NamespacedName]*contour_v1alpha1.ExtensionService)
+	kc.changes = make(map[dependency]struct{})
+	kc.changedKinds = make(map[string]struct{})
 }
 
 // Insert inserts obj into the KubernetesCache.
@@ -122,6 +173,7 @@ func (kc *KubernetesCache) init() {
 // and namespace exists, it will be overwritten.
 func (kc *KubernetesCache) Insert(obj any) bool {
 	kc.initialize.Do(kc.init)
+	kc.recordChange(obj)
 
 	maybeInsert := func(obj any) (bool, int) {
 		switch obj := obj.(type) {
@@ -133,17 +185,34 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 
 		case *core_v1.ConfigMap:
 			// Only insert configmaps that are CA certs, i.e has 'ca.crt' key,
-			// into cache.
-			if secret, isCA := kc.convertCACertConfigMapToSecret(obj); isCA {
-				kc.configmapsecrets[k8s.NamespacedNameOf(obj)] = &Secret{Object: secret}
-				return kc.configMapTriggersRebuild(obj), len(kc.configmapsecrets)
+			// or JWKSes, i.e. has 'jwks.json' key, into cache.
+			name := k8s.NamespacedNameOf(obj)
+			_, wasJWKS := kc.jwksconfigmaps[name]
+			_, isJWKS := obj.Data[JWKSKey]
+			if isJWKS {
+				kc.jwksconfigmaps[name] = obj
+			} else {
+				delete(kc.jwksconfigmaps, name)
+			}
+
+			secret, isCA := kc.convertCACertConfigMapToSecret(obj)
+			if isCA {
+				kc.configmapsecrets[name] = &Secret{Object: secret}
 			}
-			return false, len(kc.configmapsecrets)
+
+			if !isCA && !isJWKS && !wasJWKS {
+				return false, len(kc.configmapsecrets) + len(kc.jwksconfigmaps)
+			}
+			return kc.configMapTriggersRebuild(obj), len(kc.configmapsecrets) + len(kc.jwksconfigmaps)
 
 		case *core_v1.Service:
 			kc.services[k8s.NamespacedNameOf(obj)] = obj
 			return kc.serviceTriggersRebuild(obj), len(kc.services)
 
+		case *mcs_v1alpha1.ServiceImport:
+			kc.serviceimports[k8s.NamespacedNameOf(obj)] = obj
+			return kc.serviceImportTriggersRebuild(obj), len(kc.serviceimports)
+
 		case *core_v1.Namespace:
 			kc.namespaces[obj.Name] = obj
 			return true, len(kc.namespaces)
@@ -185,6 +254,18 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 
 		case *gatewayapi_v1.GatewayClass:
 			switch {
+			// Several gateways configured: make sure the incoming gateway
+			// class matches theirs.
+			case len(kc.ConfiguredGatewaysToCache) > 0:
+				if !kc.isGatewayClassOfCachedGateways(obj.Name) {
+					if kc.gatewayclass == nil {
+						return false, 0
+					}
+					return false, 1
+				}
+
+				kc.gatewayclass = obj
+				return true, 1
 			// Specific gateway configured: make sure the incoming gateway class
 			// matches that gateway's.
 			case kc.ConfiguredGatewayToCache != nil:
@@ -205,6 +286,26 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 
 		case *gatewayapi_v1.Gateway:
 			switch {
+			// Several gateways configured: make sure the incoming gateway
+			// is one of them, and get its gateway class if there is none
+			// yet. The gateways must all be of this gateway class.
+			case len(kc.ConfiguredGatewaysToCache) > 0:
+				if !slices.Contains(kc.ConfiguredGatewaysToCache, k8s.NamespacedNameOf(obj)) {
+					return false, len(kc.gateways)
+				}
+
+				kc.gateways[k8s.NamespacedNameOf(obj)] = obj
+
+				if kc.gatewayclass == nil {
+					gatewayClass := &gatewayapi_v1.GatewayClass{}
+					if err := kc.Client.Get(context.Background(), client.ObjectKey{Name: string(obj.Spec.GatewayClassName)}, gatewayClass); err != nil {
+						kc.WithError(err).Errorf("error getting gatewayclass for gateway %s/%s", obj.Namespace, obj.Name)
+					} else {
+						kc.gatewayclass = gatewayClass
+					}
+				}
+
+				return true, len(kc.gateways)
 			// Specific gateway configured: make sure the incoming gateway
 			// matches, and get its gateway class.
 			case kc.ConfiguredGatewayToCache != nil:
@@ -233,19 +334,19 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 
 		case *gatewayapi_v1.HTTPRoute:
 			kc.httproutes[k8s.NamespacedNameOf(obj)] = obj
-			return kc.routeTriggersRebuild(obj.Spec.ParentRefs), len(kc.httproutes)
+			return kc.routeTriggersRebuild(obj.Spec.ParentRefs, obj.Namespace), len(kc.httproutes)
 
 		case *gatewayapi_v1alpha2.TLSRoute:
 			kc.tlsroutes[k8s.NamespacedNameOf(obj)] = obj
-			return kc.routeTriggersRebuild(obj.Spec.ParentRefs), len(kc.tlsroutes)
+			return kc.routeTriggersRebuild(obj.Spec.ParentRefs, obj.Namespace), len(kc.tlsroutes)
 
 		case *gatewayapi_v1.GRPCRoute:
 			kc.grpcroutes[k8s.NamespacedNameOf(obj)] = obj
-			return kc.routeTriggersRebuild(obj.Spec.ParentRefs), len(kc.grpcroutes)
+			return kc.routeTriggersRebuild(obj.Spec.ParentRefs, obj.Namespace), len(kc.grpcroutes)
 
 		case *gatewayapi_v1alpha2.TCPRoute:
 			kc.tcproutes[k8s.NamespacedNameOf(obj)] = obj
-			return kc.routeTriggersRebuild(obj.Spec.ParentRefs), len(kc.tcproutes)
+			return kc.routeTriggersRebuild(obj.Spec.ParentRefs, obj.Namespace), len(kc.tcproutes)
 
 		case *gatewayapi_v1beta1.ReferenceGrant:
 			kc.referencegrants[k8s.NamespacedNameOf(obj)] = obj
@@ -255,6 +356,14 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 			kc.backendtlspolicies[k8s.NamespacedNameOf(obj)] = obj
 			return true, len(kc.backendtlspolicies)
 
+		case *gatewayapi_x_v1alpha1.XBackendTrafficPolicy:
+			kc.backendtrafficpolicies[k8s.NamespacedNameOf(obj)] = obj
+			return true, len(kc.backendtrafficpolicies)
+
+		case *gatewayapi_x_v1alpha1.XListenerSet:
+			kc.listenersets[k8s.NamespacedNameOf(obj)] = obj
+			return kc.listenerSetTriggersRebuild(obj), len(kc.listenersets)
+
 		case *contour_v1alpha1.ExtensionService:
 			kc.extensions[k8s.NamespacedNameOf(obj)] = obj
 			return true, len(kc.extensions)
@@ -306,6 +415,7 @@ func (kc *KubernetesCache) Remove(obj any) bool {
 
 	switch obj := obj.(type) {
 	default:
+		kc.recordChange(obj)
 		ok, count := kc.remove(obj)
 		kc.Metrics.SetDAGCacheObjectMetric(k8s.KindOf(obj), count)
 		return ok
@@ -325,13 +435,19 @@ func (kc *KubernetesCache) remove(obj any) (bool, int) {
 	case *core_v1.ConfigMap:
 		m := k8s.NamespacedNameOf(obj)
 		delete(kc.configmapsecrets, m)
-		return kc.configMapTriggersRebuild(obj), len(kc.configmapsecrets)
+		delete(kc.jwksconfigmaps, m)
+		return kc.configMapTriggersRebuild(obj), len(kc.configmapsecrets) + len(kc.jwksconfigmaps)
 
 	case *core_v1.Service:
 		m := k8s.NamespacedNameOf(obj)
 		delete(kc.services, m)
 		return kc.serviceTriggersRebuild(obj), len(kc.services)
 
+	case *mcs_v1alpha1.ServiceImport:
+		m := k8s.NamespacedNameOf(obj)
+		delete(kc.serviceimports, m)
+		return kc.serviceImportTriggersRebuild(obj), len(kc.serviceimports)
+
 	case *core_v1.Namespace:
 		_, ok := kc.namespaces[obj.Name]
 		delete(kc.namespaces, obj.Name)
@@ -357,7 +473,7 @@ func (kc *KubernetesCache) remove(obj any) (bool, int) {
 
 	case *gatewayapi_v1.GatewayClass:
 		switch {
-		case kc.ConfiguredGatewayToCache != nil:
+		case kc.ConfiguredGatewayToCache != nil, len(kc.ConfiguredGatewaysToCache) > 0:
 			if kc.gatewayclass == nil {
 				return false, 0
 			}
@@ -374,6 +490,11 @@ func (kc *KubernetesCache) remove(obj any) (bool, int) {
 
 	case *gatewayapi_v1.Gateway:
 		switch {
+		case len(kc.ConfiguredGatewaysToCache) > 0:
+			m := k8s.NamespacedNameOf(obj)
+			_, ok := kc.gateways[m]
+			delete(kc.gateways, m)
+			return ok, len(kc.gateways)
 		case kc.ConfiguredGatewayToCache != nil:
 			if kc.gateway == nil {
 				return false, 0
@@ -390,22 +511,22 @@ func (kc *KubernetesCache) remove(obj any) (bool, int) {
 	case *gatewayapi_v1.HTTPRoute:
 		m := k8s.NamespacedNameOf(obj)
 		delete(kc.httproutes, m)
-		return kc.routeTriggersRebuild(obj.Spec.ParentRefs), len(kc.httproutes)
+		return kc.routeTriggersRebuild(obj.Spec.ParentRefs, obj.Namespace), len(kc.httproutes)
 
 	case *gatewayapi_v1alpha2.TLSRoute:
 		m := k8s.NamespacedNameOf(obj)
 		delete(kc.tlsroutes, m)
-		return kc.routeTriggersRebuild(obj.Spec.ParentRefs), len(kc.tlsroutes)
+		return kc.routeTriggersRebuild(obj.Spec.ParentRefs, obj.Namespace), len(kc.tlsroutes)
 
 	case *gatewayapi_v1.GRPCRoute:
 		m := k8s.NamespacedNameOf(obj)
 		delete(kc.grpcroutes, m)
-		return kc.routeTriggersRebuild(obj.Spec.ParentRefs), len(kc.grpcroutes)
+		return kc.routeTriggersRebuild(obj.Spec.ParentRefs, obj.Namespace), len(kc.grpcroutes)
 
 	case *gatewayapi_v1alpha2.TCPRoute:
 		m := k8s.NamespacedNameOf(obj)
 		delete(kc.tcproutes, m)
-		return kc.routeTriggersRebuild(obj.Spec.ParentRefs), len(kc.tcproutes)
+		return kc.routeTriggersRebuild(obj.Spec.ParentRefs, obj.Namespace), len(kc.tcproutes)
 
 	case *gatewayapi_v1beta1.ReferenceGrant:
 		m := k8s.NamespacedNameOf(obj)
@@ -419,6 +540,17 @@ func (kc *KubernetesCache) remove(obj any) (bool, int) {
 		delete(kc.backendtlspolicies, m)
 		return ok, len(kc.backendtlspolicies)
 
+	case *gatewayapi_x_v1alpha1.XBackendTrafficPolicy:
+		m := k8s.NamespacedNameOf(obj)
+		_, ok := kc.backendtrafficpolicies[m]
+		delete(kc.backendtrafficpolicies, m)
+		return ok, len(kc.backendtrafficpolicies)
+
+	case *gatewayapi_x_v1alpha1.XListenerSet:
+		m := k8s.NamespacedNameOf(obj)
+		delete(kc.listenersets, m)
+		return kc.listenerSetTriggersRebuild(obj), len(kc.listenersets)
+
 	case *contour_v1alpha1.ExtensionService:
 		m := k8s.NamespacedNameOf(obj)
 		_, ok := kc.extensions[m]
@@ -528,6 +660,79 @@ func isRefToService(ref gatewayapi_v1.BackendObjectReference, service *core_v1.S
 		string(ref.Name) == service.Name
 }
 
+// serviceImportTriggersRebuild returns true if this ServiceImport is
+// referenced by an HTTPProxy or a Gateway API route in this cache.
+func (kc *KubernetesCache) serviceImportTriggersRebuild(serviceImport *mcs_v1alpha1.ServiceImport) bool {
+	for _, proxy := range kc.httpproxies {
+		if proxy.Namespace != serviceImport.Namespace {
+			continue
+		}
+		for _, route := range proxy.Spec.Routes {
+			for _, s := range route.Services {
+				if s.Kind == KindServiceImport && s.Name == serviceImport.Name {
+					return true
+				}
+			}
+		}
+		if tcpproxy := proxy.Spec.TCPProxy; tcpproxy != nil {
+			for _, s := range tcpproxy.Services {
+				if s.Kind == KindServiceImport && s.Name == serviceImport.Name {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, route := range kc.httproutes {
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				if isRefToServiceImport(backend.BackendObjectReference, serviceImport, route.Namespace) {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, route := range kc.grpcroutes {
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				if isRefToServiceImport(backend.BackendObjectReference, serviceImport, route.Namespace) {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, route := range kc.tlsroutes {
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				if isRefToServiceImport(backend.BackendObjectReference, serviceImport, route.Namespace) {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, route := range kc.tcproutes {
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				if isRefToServiceImport(backend.BackendObjectReference, serviceImport, route.Namespace) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func isRefToServiceImport(ref gatewayapi_v1.BackendObjectReference, serviceImport *mcs_v1alpha1.ServiceImport, routeNamespace string) bool {
+	return ref.Group != nil && *ref.Group == mcs_v1alpha1.GroupName &&
+		ref.Kind != nil && *ref.Kind == KindServiceImport &&
+		((ref.Namespace != nil && string(*ref.Namespace) == serviceImport.Namespace) || (ref.Namespace == nil && routeNamespace == serviceImport.Namespace)) &&
+		string(ref.Name) == serviceImport.Name
+}
+
 // secretTriggersRebuild returns true if this secret is referenced by an Ingress
 // or HTTPProxy object, or by the configuration file.
 // If the secret is not in the same namespace the function ignores TLSCertificateDelegation.
@@ -572,12 +777,22 @@ func (kc *KubernetesCache) secretTriggersRebuild(secretObj *core_v1.Secret) bool
 			return true
 		}
 
+		for _, name := range tls.AdditionalSecretNames {
+			if secret == k8s.NamespacedNameFrom(name, k8s.DefaultNamespace(proxy.Namespace)) {
+				return true
+			}
+		}
+
 		cv := tls.ClientValidation
 		if cv != nil && secret == k8s.NamespacedNameFrom(cv.CertificateRevocationList, k8s.DefaultNamespace(proxy.Namespace)) {
 			return true
 		}
 	}
 
+	if kc.jwksReferenced("Secret", secret) {
+		return true
+	}
+
 	// Secrets referred by the configuration file shall also trigger rebuild.
 	for _, s := range kc.ConfiguredSecretRefs {
 		if secret == *s {
@@ -585,18 +800,32 @@ func (kc *KubernetesCache) secretTriggersRebuild(secretObj *core_v1.Secret) bool
 		}
 	}
 
-	if kc.gateway != nil {
-		for _, listener := range kc.gateway.Spec.Listeners {
+	for _, gateway := range kc.servedGateways() {
+		for _, listener := range gateway.Spec.Listeners {
 			if listener.TLS == nil {
 				continue
 			}
 
 			for _, certificateRef := range listener.TLS.CertificateRefs {
-				if isRefToSecret(certificateRef, secretObj, kc.gateway.Namespace) {
+				if isRefToSecret(certificateRef, secretObj, gateway.Namespace) {
 					return true
 				}
 			}
 		}
+
+		for _, listenerSet := range kc.gatewayListenerSets(gateway) {
+			for _, listener := range listenerSet.Spec.Listeners {
+				if listener.TLS == nil {
+					continue
+				}
+
+				for _, certificateRef := range listener.TLS.CertificateRefs {
+					if isRefToSecret(certificateRef, secretObj, listenerSet.Namespace) {
+						return true
+					}
+				}
+			}
+		}
 	}
 
 	return false
@@ -610,13 +839,17 @@ func isRefToSecret(ref gatewayapi_v1.SecretObjectReference, secret *core_v1.Secr
 }
 
 // configMapTriggersRebuild returns true if this configmap is referenced by a
-// BackendTLSPolicy object.
+// BackendTLSPolicy object or by an HTTPProxy JWT provider.
 func (kc *KubernetesCache) configMapTriggersRebuild(configMapObj *core_v1.ConfigMap) bool {
 	configMap := types.NamespacedName{
 		Namespace: configMapObj.Namespace,
 		Name:      configMapObj.Name,
 	}
 
+	if kc.jwksReferenced("ConfigMap", configMap) {
+		return true
+	}
+
 	for _, backendtlspolicy := range kc.backendtlspolicies {
 		for _, caCertRef := range backendtlspolicy.Spec.Validation.CACertificateRefs {
 			if caCertRef.Group != "" || caCertRef.Kind != "ConfigMap" {
@@ -635,14 +868,48 @@ func (kc *KubernetesCache) configMapTriggersRebuild(configMapObj *core_v1.Config
 	return false
 }
 
-// routeTriggersRebuild returns true if this route references gateway in this cache.
-func (kc *KubernetesCache) routeTriggersRebuild(parentRefs []gatewayapi_v1.ParentReference) bool {
-	if kc.gateway == nil {
-		return false
+// jwksReferenced returns true if an HTTPProxy JWT provider
+// reads its JWKS from the named object of the given kind.
+func (kc *KubernetesCache) jwksReferenced(kind string, name types.NamespacedName) bool {
+	for _, proxy := range kc.httpproxies {
+		if proxy.Namespace != name.Namespace || proxy.Spec.VirtualHost == nil {
+			continue
+		}
+
+		for _, provider := range proxy.Spec.VirtualHost.JWTProviders {
+			if provider.LocalJWKS != nil && localJWKSKind(provider.LocalJWKS) == kind && provider.LocalJWKS.Name == name.Name {
+				return true
+			}
+		}
 	}
+	return false
+}
+
+// routeTriggersRebuild returns true if this route references a gateway in this cache,
+// or a ListenerSet of one.
+func (kc *KubernetesCache) routeTriggersRebuild(parentRefs []gatewayapi_v1.ParentReference, routeNamespace string) bool {
+	for _, gateway := range kc.servedGateways() {
+		for _, parentRef := range parentRefs {
+			if gatewayapi.IsRefToGateway(parentRef, k8s.NamespacedNameOf(gateway)) {
+				return true
+			}
+
+			for _, listenerSet := range kc.gatewayListenerSets(gateway) {
+				if gatewayapi.IsRefToListenerSet(parentRef, routeNamespace, k8s.NamespacedNameOf(listenerSet)) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
 
-	for _, parentRef := range parentRefs {
-		if gatewayapi.IsRefToGateway(parentRef, k8s.NamespacedNameOf(kc.gateway)) {
+// listenerSetTriggersRebuild returns true if this ListenerSet's
+// parent is a gateway in this cache.
+func (kc *KubernetesCache) listenerSetTriggersRebuild(listenerSet *gatewayapi_x_v1alpha1.XListenerSet) bool {
+	for _, gateway := range kc.servedGateways() {
+		if gatewayapi.IsListenerSetOfGateway(listenerSet, k8s.NamespacedNameOf(gateway)) {
 			return true
 		}
 	}
@@ -650,6 +917,91 @@ func (kc *KubernetesCache) routeTriggersRebuild(parentRefs []gatewayapi_v1.Paren
 	return false
 }
 
+// gatewayListenerSets returns the ListenerSets whose parent is the
+// given Gateway, sorted by creation timestamp and then by namespace/name.
+func (kc *KubernetesCache) gatewayListenerSets(gateway *gatewayapi_v1.Gateway) []*gatewayapi_x_v1alpha1.XListenerSet {
+	var listenerSets []*gatewayapi_x_v1alpha1.XListenerSet
+	for _, listenerSet := range kc.listenersets {
+		if gatewayapi.IsListenerSetOfGateway(listenerSet, k8s.NamespacedNameOf(gateway)) {
+			listenerSets = append(listenerSets, listenerSet)
+		}
+	}
+
+	gatewayapi.SortListenerSets(listenerSets)
+	return listenerSets
+}
+
+// servedGateways returns the Gateways in this cache, sorted by
+// namespace/name when several Gateways are configured.
+func (kc *KubernetesCache) servedGateways() []*gatewayapi_v1.Gateway {
+	if len(kc.ConfiguredGatewaysToCache) == 0 {
+		if kc.gateway == nil {
+			return nil
+		}
+		return []*gatewayapi_v1.Gateway{kc.gateway}
+	}
+
+	gateways := make([]*gatewayapi_v1.Gateway, 0, len(kc.gateways))
+	for _, gateway := range kc.gateways {
+		gateways = append(gateways, gateway)
+	}
+	sort.Slice(gateways, func(i, j int) bool {
+		return k8s.NamespacedNameOf(gateways[i]).String() < k8s.NamespacedNameOf(gateways[j]).String()
+	})
+	return gateways
+}
+
+// listenerSetAllowed returns true if the Gateway's allowedListeners
+// allow the ListenerSet to attach to it.
+func (kc *KubernetesCache) listenerSetAllowed(gateway *gatewayapi_v1.Gateway, listenerSet *gatewayapi_x_v1alpha1.XListenerSet) bool {
+	return gatewayapi.ListenerSetAllowed(gateway, listenerSet, kc.namespaces[listenerSet.Namespace])
+}
+
+// allowedListenerSetListeners returns the listeners of the ListenerSets
+// the given Gateway allows, in the order of gatewayListenerSets.
+func (kc *KubernetesCache) allowedListenerSetListeners(gateway *gatewayapi_v1.Gateway) []gatewayapi.ListenerSetListeners {
+	var listenerSetListeners []gatewayapi.ListenerSetListeners
+	for _, listenerSet := range kc.gatewayListenerSets(gateway) {
+		if !kc.listenerSetAllowed(gateway, listenerSet) {
+			continue
+		}
+
+		listenerSetListeners = append(listenerSetListeners, gatewayapi.ListenerSetListeners{
+			ListenerSet: k8s.NamespacedNameOf(listenerSet),
+			Listeners:   gatewayapi.ListenersOfListenerSet(listenerSet),
+		})
+	}
+	return listenerSetListeners
+}
+
+// gatewayListenerName returns the name of the DAG listener for the
+// named Envoy listener of the given Gateway. When several Gateways are
+// configured, the name is prefixed with GatewayListenerPrefix.
+func (kc *KubernetesCache) gatewayListenerName(gateway *gatewayapi_v1.Gateway, name string) string {
+	if len(kc.ConfiguredGatewaysToCache) == 0 {
+		return name
+	}
+	return GatewayListenerPrefix(k8s.NamespacedNameOf(gateway)) + name
+}
+
+// GatewayListenerPrefix returns the prefix of the names of the listeners,
+// and so of the route configurations, of the given Gateway when several
+// Gateways are served by Contour.
+func GatewayListenerPrefix(gateway types.NamespacedName) string {
+	return gateway.String() + "/"
+}
+
+// isGatewayClassOfCachedGateways returns true if any of the
+// Gateways in this cache is of the named GatewayClass.
+func (kc *KubernetesCache) isGatewayClassOfCachedGateways(name string) bool {
+	for _, gateway := range kc.gateways {
+		if string(gateway.Spec.GatewayClassName) == name {
+			return true
+		}
+	}
+	return false
+}
+
 // LookupTLSSecret returns Secret with TLS certificate and private key from cache.
 // If name (referred Secret) is in different namespace than targetNamespace (the referring object),
 // then delegation check is performed.
@@ -657,7 +1009,7 @@ func (kc *KubernetesCache) LookupTLSSecret(name types.NamespacedName, targetName
 	if !kc.delegationPermitted(name, targetNamespace) {
 		return nil, NewDelegationNotPermittedError(fmt.Errorf("Certificate delegation not permitted"))
 	}
-	return kc.LookupTLSSecretInsecure(name)
+	return kc.lookupServingSecret(name)
 }
 
 // LookupCASecret returns Secret with CA certificate from cache.
@@ -668,6 +1020,7 @@ func (kc *KubernetesCache) LookupCASecret(name types.NamespacedName, targetNames
 		return nil, NewDelegationNotPermittedError(fmt.Errorf("Certificate delegation not permitted"))
 	}
 
+	kc.dependOn("Secret", name)
 	sec, ok := kc.secrets[name]
 	if !ok {
 		return nil, fmt.Errorf("Secret not found")
@@ -689,6 +1042,7 @@ func (kc *KubernetesCache) LookupCASecret(name types.NamespacedName, targetNames
 
 // LookupCAConfigMap returns ConfigMap converted into dag.Secret with CA certificate from cache.
 func (kc *KubernetesCache) LookupCAConfigMap(name types.NamespacedName) (*Secret, error) {
+	kc.dependOn("ConfigMap", name)
 	sec, ok := kc.configmapsecrets[name]
 	if !ok {
 		return nil, fmt.Errorf("ConfigMap not found")
@@ -708,6 +1062,46 @@ func (kc *KubernetesCache) LookupCAConfigMap(name types.NamespacedName) (*Secret
 	return sec, nil
 }
 
+// LookupJWKS returns the JWKS held by the named ConfigMap or Secret from the cache.
+func (kc *KubernetesCache) LookupJWKS(jwks *contour_v1.LocalJWKS, namespace string) (string, error) {
+	kind := localJWKSKind(jwks)
+	name := types.NamespacedName{Namespace: namespace, Name: jwks.Name}
+	kc.dependOn(kind, name)
+
+	var data string
+	switch kind {
+	case "Secret":
+		sec, ok := kc.secrets[name]
+		if !ok {
+			return "", fmt.Errorf("Secret not found")
+		}
+		jwks, ok := sec.Object.Data[JWKSKey]
+		if !ok {
+			return "", fmt.Errorf("Secret is missing the %q key", JWKSKey)
+		}
+		data = string(jwks)
+	default:
+		configMap, ok := kc.jwksconfigmaps[name]
+		if !ok {
+			return "", fmt.Errorf("ConfigMap not found or is missing the %q key", JWKSKey)
+		}
+		data = configMap.Data[JWKSKey]
+	}
+
+	if len(data) == 0 {
+		return "", fmt.Errorf("%s key %q is empty", kind, JWKSKey)
+	}
+	return data, nil
+}
+
+// localJWKSKind returns the kind of the object holding the JWKS.
+func localJWKSKind(jwks *contour_v1.LocalJWKS) string {
+	if jwks.Kind == "" {
+		return "ConfigMap"
+	}
+	return jwks.Kind
+}
+
 // LookupCRLSecret returns Secret with CRL from the cache.
 // If name (referred Secret) is in different namespace than targetNamespace (the referring object),
 // then delegation check is performed.
@@ -716,6 +1110,7 @@ func (kc *KubernetesCache) LookupCRLSecret(name types.NamespacedName, targetName
 		return nil, NewDelegationNotPermittedError(fmt.Errorf("Certificate delegation not permitted"))
 	}
 
+	kc.dependOn("Secret", name)
 	sec, ok := kc.secrets[name]
 	if !ok {
 		return nil, fmt.Errorf("Secret not found")
@@ -780,6 +1175,7 @@ func (kc *KubernetesCache) LookupUpstreamValidation(uv *contour_v1.UpstreamValid
 // LookupTLSSecretInsecure returns Secret with TLS certificate and private key from cache.
 // No delegation check is performed.
 func (kc *KubernetesCache) LookupTLSSecretInsecure(name types.NamespacedName) (*Secret, error) {
+	kc.dependOn("Secret", name)
 	sec, ok := kc.secrets[name]
 	if !ok {
 		return nil, fmt.Errorf("Secret not found")
@@ -796,47 +1192,171 @@ func (kc *KubernetesCache) LookupTLSSecretInsecure(name types.NamespacedName) (*
 	if err := sec.ValidTLSSecret.Error; err != nil {
 		return nil, err
 	}
+
+	if kc.RejectExpiredCertificates {
+		if notAfter, ok := sec.CertificateExpiry(); ok {
+			if time.Now().After(notAfter) {
+				return nil, fmt.Errorf("certificate expired at %s", notAfter.UTC().Format(time.RFC3339))
+			}
+			kc.dependUntil(notAfter)
+		}
+	}
+
 	return sec, nil
 }
 
-// delegationPermitted returns true if the referenced secret has been delegated
-// to the namespace where the ingress object is located.
-func (kc *KubernetesCache) delegationPermitted(secret types.NamespacedName, targetNamespace string) bool {
-	contains := func(haystack []string, needle string) bool {
-		if len(haystack) == 1 && haystack[0] == "*" {
-			return true
+// lookupServingSecret returns Secret with TLS certificate and private
+// key from cache, checking the requirements that only apply to serving
+// certificates. No delegation check is performed.
+func (kc *KubernetesCache) lookupServingSecret(name types.NamespacedName) (*Secret, error) {
+	sec, err := kc.LookupTLSSecretInsecure(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if kc.RequireOCSPStaple {
+		staple := sec.OCSPStaple()
+		if len(staple) == 0 {
+			return nil, fmt.Errorf("missing %q key required by the must-staple OCSP policy", OCSPStapleKey)
 		}
-		for _, h := range haystack {
-			if h == needle {
-				return true
+
+		// Envoy rejects the whole listener if a staple required by the
+		// must-staple policy is not current, so reject the Secret until
+		// it is, or until the staple is refreshed.
+		thisUpdate, nextUpdate, err := ocspStapleWindow(staple)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OCSP staple: %v", err)
+		}
+		now := time.Now()
+		if now.Before(thisUpdate) {
+			kc.dependUntil(thisUpdate)
+			return nil, fmt.Errorf("OCSP staple not valid before %s", thisUpdate.UTC().Format(time.RFC3339))
+		}
+		if !nextUpdate.IsZero() {
+			if !now.Before(nextUpdate) {
+				return nil, fmt.Errorf("OCSP staple expired at %s", nextUpdate.UTC().Format(time.RFC3339))
 			}
+			kc.dependUntil(nextUpdate)
 		}
-		return false
 	}
 
+	return sec, nil
+}
+
+const (
+	certificateExpiredReason      = "CertificateExpired"
+	certificateExpiringSoonReason = "CertificateExpiringSoon"
+)
+
+// certificateExpiryWarning returns a reason and message if the serving
+// certificate of the given Secret has expired, or expires within the
+// CertificateExpiryWarningPeriod. Both are empty otherwise.
+func (kc *KubernetesCache) certificateExpiryWarning(sec *Secret) (string, string) {
+	notAfter, ok := sec.CertificateExpiry()
+	if !ok {
+		return "", ""
+	}
+
+	kc.dependUntil(notAfter)
+	if kc.CertificateExpiryWarningPeriod > 0 {
+		kc.dependUntil(notAfter.Add(-kc.CertificateExpiryWarningPeriod))
+	}
+
+	now := time.Now()
+	switch {
+	case now.After(notAfter):
+		return certificateExpiredReason, fmt.Sprintf("certificate expired at %s", notAfter.UTC().Format(time.RFC3339))
+	case kc.CertificateExpiryWarningPeriod > 0 && now.Add(kc.CertificateExpiryWarningPeriod).After(notAfter):
+		return certificateExpiringSoonReason, fmt.Sprintf("certificate expires at %s", notAfter.UTC().Format(time.RFC3339))
+	default:
+		return "", ""
+	}
+}
+
+// delegationPermitted returns true if the referenced secret has been delegated
+// to the namespace where the ingress object is located.
+func (kc *KubernetesCache) delegationPermitted(secret types.NamespacedName, targetNamespace string) bool {
 	if secret.Namespace == targetNamespace {
 		// secret is in the same namespace as target
 		return true
 	}
 
+	kc.dependOnKind("TLSCertificateDelegation")
+
 	for _, d := range kc.tlscertificatedelegations {
 		if d.Namespace != secret.Namespace {
 			continue
 		}
 		for _, d := range d.Spec.Delegations {
-			if contains(d.TargetNamespaces, targetNamespace) {
-				if secret.Name == d.SecretName {
-					return true
-				}
+			if kc.delegates(d, secret, targetNamespace) {
+				return true
 			}
 		}
 	}
 	return false
 }
 
+// delegates returns true if the CertificateDelegation permits the
+// target namespace to reference the secret, which must be in the
+// namespace of the TLSCertificateDelegation. Invalid delegations
+// don't permit anything.
+func (kc *KubernetesCache) delegates(d contour_v1.CertificateDelegation, secret types.NamespacedName, targetNamespace string) bool {
+	return kc.delegatesSecret(d, secret) && kc.delegatesTo(d, targetNamespace)
+}
+
+// delegatesSecret returns true if the CertificateDelegation
+// selects the secret by name pattern or by label selector.
+func (kc *KubernetesCache) delegatesSecret(d contour_v1.CertificateDelegation, secret types.NamespacedName) bool {
+	switch {
+	case d.SecretName != "" && d.SecretSelector != nil:
+		return false
+	case d.SecretName != "":
+		matched, err := path.Match(d.SecretName, secret.Name)
+		return err == nil && matched
+	case d.SecretSelector != nil:
+		selector, err := meta_v1.LabelSelectorAsSelector(d.SecretSelector)
+		if err != nil {
+			return false
+		}
+
+		kc.dependOn("Secret", secret)
+		sec, ok := kc.secrets[secret]
+		return ok && selector.Matches(labels.Set(sec.Object.Labels))
+	default:
+		return false
+	}
+}
+
+// delegatesTo returns true if the CertificateDelegation lists
+// the target namespace, or selects it by label selector.
+func (kc *KubernetesCache) delegatesTo(d contour_v1.CertificateDelegation, targetNamespace string) bool {
+	if len(d.TargetNamespaces) == 1 && d.TargetNamespaces[0] == "*" {
+		return true
+	}
+	for _, ns := range d.TargetNamespaces {
+		if ns == targetNamespace {
+			return true
+		}
+	}
+
+	if d.TargetNamespaceSelector == nil {
+		return false
+	}
+
+	selector, err := meta_v1.LabelSelectorAsSelector(d.TargetNamespaceSelector)
+	if err != nil {
+		return false
+	}
+
+	kc.dependOnKind("Namespace")
+	ns, ok := kc.namespaces[targetNamespace]
+	return ok && selector.Matches(labels.Set(ns.Labels))
+}
+
 // LookupService returns the Kubernetes service and port matching the provided parameters,
 // or an error if a match can't be found.
 func (kc *KubernetesCache) LookupService(meta types.NamespacedName, port intstr.IntOrString) (*core_v1.Service, core_v1.ServicePort, error) {
+	kc.dependOn("Service", meta)
 	svc, ok := kc.services[meta]
 	if !ok {
 		return nil, core_v1.ServicePort{}, fmt.Errorf("service %q not found", meta)
@@ -857,6 +1377,36 @@ func (kc *KubernetesCache) LookupService(meta types.NamespacedName, port intstr.
 	return nil, core_v1.ServicePort{}, fmt.Errorf("port %q on service %q not matched", port.String(), meta)
 }
 
+// LookupServiceImport returns the multi-cluster ServiceImport and port matching
+// the provided parameters, or an error if a match can't be found. The port is
+// returned as a core_v1.ServicePort so it can be matched against the ports of
+// the EndpointSlices derived for the ServiceImport.
+func (kc *KubernetesCache) LookupServiceImport(meta types.NamespacedName, port intstr.IntOrString) (*mcs_v1alpha1.ServiceImport, core_v1.ServicePort, error) {
+	kc.dependOn(KindServiceImport, meta)
+	svcImport, ok := kc.serviceimports[meta]
+	if !ok {
+		return nil, core_v1.ServicePort{}, fmt.Errorf("serviceimport %q not found", meta)
+	}
+
+	for _, p := range svcImport.Spec.Ports {
+		if int(p.Port) == port.IntValue() || port.String() == p.Name {
+			switch p.Protocol {
+			case "", core_v1.ProtocolTCP:
+				return svcImport, core_v1.ServicePort{
+					Name:        p.Name,
+					Protocol:    p.Protocol,
+					AppProtocol: p.AppProtocol,
+					Port:        p.Port,
+				}, nil
+			default:
+				return nil, core_v1.ServicePort{}, fmt.Errorf("unsupported serviceimport protocol %q", p.Protocol)
+			}
+		}
+	}
+
+	return nil, core_v1.ServicePort{}, fmt.Errorf("port %q on serviceimport %q not matched", port.String(), meta)
+}
+
 // LookupBackendTLSPolicyByTargetRef returns the Kubernetes BackendTLSPolicy that matches the provided targetRef with
 // a SectionName, if possible. A BackendTLSPolicy may be returned if there is a BackendTLSPolicy matching the targetRef
 // but has no SectionName.
@@ -902,6 +1452,42 @@ func (kc *KubernetesCache) LookupBackendTLSPolicyByTargetRef(targetRef gatewayap
 	return nil, false
 }
 
+// LookupBackendTrafficPolicyByTargetRef returns the Kubernetes XBackendTrafficPolicy that matches
+// the provided targetRef.
+//
+// The namespace provided is intended to be the namespace of the backend we are looking up a reference to (since only
+// namespace-local references are allowed) and is used to match the namespace on the resulting XBackendTrafficPolicy.
+//
+// If several policies match, the oldest one is returned, falling back to the
+// one first in alphabetical order by namespace and name. If a policy is found,
+// true is returned.
+func (kc *KubernetesCache) LookupBackendTrafficPolicyByTargetRef(targetRef gatewayapi_v1alpha2.LocalPolicyTargetReference, namespace string) (*gatewayapi_x_v1alpha1.XBackendTrafficPolicy, bool) {
+	var found *gatewayapi_x_v1alpha1.XBackendTrafficPolicy
+	for _, v := range kc.backendtrafficpolicies {
+		// Make sure the XBackendTrafficPolicy namespace matches the backend namespace.
+		if v.Namespace != namespace {
+			continue
+		}
+
+		if !slices.Contains(v.Spec.TargetRefs, targetRef) {
+			continue
+		}
+
+		switch {
+		case found == nil:
+			found = v
+		case v.CreationTimestamp.Equal(&found.CreationTimestamp):
+			if k8s.NamespacedNameOf(v).String() < k8s.NamespacedNameOf(found).String() {
+				found = v
+			}
+		case v.CreationTimestamp.Before(&found.CreationTimestamp):
+			found = v
+		}
+	}
+
+	return found, found != nil
+}
+
 func (kc *KubernetesCache) convertCACertConfigMapToSecret(configMap *core_v1.ConfigMap) (*core_v1.Secret, bool) {
 	if _, ok := configMap.Data[CACertificateKey]; !ok {
 		return nil, false
@@ -17,6 +17,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,6 +32,8 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -937,6 +940,100 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
+		"insert serviceimport": {
+			obj: &mcs_v1alpha1.ServiceImport{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: "default",
+				},
+			},
+			want: false,
+		},
+		"insert serviceimport referenced by httpproxy": {
+			pre: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "service",
+								Kind: "ServiceImport",
+							}},
+						}},
+					},
+				},
+			},
+			obj: &mcs_v1alpha1.ServiceImport{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
+		"insert serviceimport with the name of a service referenced by httpproxy": {
+			pre: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "service",
+							}},
+						}},
+					},
+				},
+			},
+			obj: &mcs_v1alpha1.ServiceImport{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: "default",
+				},
+			},
+			want: false,
+		},
+		"insert serviceimport referenced by httproute": {
+			pre: []any{
+				&gatewayapi_v1.HTTPRoute{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "httproute",
+						Namespace: "default",
+					},
+					Spec: gatewayapi_v1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+							ParentRefs: []gatewayapi_v1.ParentReference{
+								gatewayapi.GatewayParentRef("projectcontour", "contour"),
+							},
+						},
+						Rules: []gatewayapi_v1.HTTPRouteRule{{
+							BackendRefs: []gatewayapi_v1.HTTPBackendRef{{
+								BackendRef: gatewayapi_v1.BackendRef{
+									BackendObjectReference: gatewayapi_v1.BackendObjectReference{
+										Group: ptr.To(gatewayapi_v1.Group(mcs_v1alpha1.GroupName)),
+										Kind:  ptr.To(gatewayapi_v1.Kind("ServiceImport")),
+										Name:  "service",
+										Port:  ptr.To(gatewayapi_v1.PortNumber(80)),
+									},
+								},
+							}},
+						}},
+					},
+				},
+			},
+			obj: &mcs_v1alpha1.ServiceImport{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
 		"insert namespace": {
 			obj: &core_v1.Namespace{
 				ObjectMeta: meta_v1.ObjectMeta{
@@ -998,6 +1095,88 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
+		"insert gateway-api HTTPRoute, has reference to a ListenerSet of the Gateway": {
+			pre: []any{
+				&gatewayapi_v1.Gateway{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Namespace: "gateway-namespace",
+						Name:      "gateway-name",
+					},
+				},
+				&gatewayapi_x_v1alpha1.XListenerSet{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Namespace: "default",
+						Name:      "listenerset",
+					},
+					Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+						ParentRef: gatewayapi_x_v1alpha1.ParentGatewayReference{
+							Namespace: ptr.To(gatewayapi_v1.Namespace("gateway-namespace")),
+							Name:      "gateway-name",
+						},
+					},
+				},
+			},
+			obj: &gatewayapi_v1.HTTPRoute{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "httproute",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1.ParentReference{
+							gatewayapi.ListenerSetParentRef("", "listenerset"),
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		"insert gateway-api XListenerSet, no reference to Gateway": {
+			pre: []any{
+				&gatewayapi_v1.Gateway{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Namespace: "gateway-namespace",
+						Name:      "gateway-name",
+					},
+				},
+			},
+			obj: &gatewayapi_x_v1alpha1.XListenerSet{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Namespace: "default",
+					Name:      "listenerset",
+				},
+				Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+					ParentRef: gatewayapi_x_v1alpha1.ParentGatewayReference{
+						Namespace: ptr.To(gatewayapi_v1.Namespace("gateway-namespace")),
+						Name:      "some-other-gateway-name",
+					},
+				},
+			},
+			want: false,
+		},
+		"insert gateway-api XListenerSet, has reference to Gateway": {
+			pre: []any{
+				&gatewayapi_v1.Gateway{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Namespace: "gateway-namespace",
+						Name:      "gateway-name",
+					},
+				},
+			},
+			obj: &gatewayapi_x_v1alpha1.XListenerSet{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Namespace: "default",
+					Name:      "listenerset",
+				},
+				Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+					ParentRef: gatewayapi_x_v1alpha1.ParentGatewayReference{
+						Namespace: ptr.To(gatewayapi_v1.Namespace("gateway-namespace")),
+						Name:      "gateway-name",
+					},
+				},
+			},
+			want: true,
+		},
 		"insert gateway-api TLSRoute, no reference to Gateway": {
 			obj: &gatewayapi_v1alpha2.TLSRoute{
 				ObjectMeta: meta_v1.ObjectMeta{
@@ -1167,6 +1346,21 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
+		"insert xbackendtrafficpolicy": {
+			obj: &gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "xbackendtrafficpolicy",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_x_v1alpha1.BackendTrafficPolicySpec{
+					TargetRefs: []gatewayapi_v1alpha2.LocalPolicyTargetReference{{
+						Kind: "Service",
+						Name: "service",
+					}},
+				},
+			},
+			want: true,
+		},
 
 		// SPECIFIC GATEWAY TESTS
 		"specific gateway configured, insert gatewayclass, no gateway cached": {
@@ -1430,6 +1624,37 @@ func TestKubernetesCacheRemove(t *testing.T) {
 			},
 			want: false,
 		},
+		"remove serviceimport with reference to HTTPProxy": {
+			cache: cache(
+				&mcs_v1alpha1.ServiceImport{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "service",
+						Namespace: "default",
+					},
+				},
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						TCPProxy: &contour_v1.TCPProxy{
+							Services: []contour_v1.Service{{
+								Name: "service",
+								Kind: "ServiceImport",
+							}},
+						},
+					},
+				},
+			),
+			obj: &mcs_v1alpha1.ServiceImport{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "service",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
 
 		"remove namespace": {
 			cache: cache(&core_v1.Namespace{
@@ -2716,6 +2941,37 @@ func TestSecretTriggersRebuild(t *testing.T) {
 			secret: secret("projectcontour", "tlscert"),
 			want:   true,
 		},
+		"gateway listenerset listener references secret, triggers rebuild": {
+			cache: cache(
+				&gatewayapi_v1.Gateway{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "contour",
+						Namespace: "projectcontour",
+					},
+				},
+				&gatewayapi_x_v1alpha1.XListenerSet{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "listenerset",
+						Namespace: "default",
+					},
+					Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+						ParentRef: gatewayapi_x_v1alpha1.ParentGatewayReference{
+							Name:      "contour",
+							Namespace: ptr.To(gatewayapi_v1.Namespace("projectcontour")),
+						},
+						Listeners: []gatewayapi_x_v1alpha1.ListenerEntry{{
+							TLS: &gatewayapi_v1.GatewayTLSConfig{
+								CertificateRefs: []gatewayapi_v1.SecretObjectReference{
+									gatewayapi.CertificateRef("tlscert", ""),
+								},
+							},
+						}},
+					},
+				},
+			),
+			secret: secret("default", "tlscert"),
+			want:   true,
+		},
 		"HTTPProxy with client validation and CRL triggers rebuild": {
 			cache:  cache(httpProxyWithClientValidation("user", "proxy", "crl")),
 			secret: secret("user", "crl"),
@@ -2892,10 +3148,10 @@ func TestRouteTriggersRebuild(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			if tc.httproute != nil {
-				assert.Equal(t, tc.want, tc.cache.routeTriggersRebuild(tc.httproute.Spec.ParentRefs))
+				assert.Equal(t, tc.want, tc.cache.routeTriggersRebuild(tc.httproute.Spec.ParentRefs, tc.httproute.Namespace))
 			}
 			if tc.tlsroute != nil {
-				assert.Equal(t, tc.want, tc.cache.routeTriggersRebuild(tc.tlsroute.Spec.ParentRefs))
+				assert.Equal(t, tc.want, tc.cache.routeTriggersRebuild(tc.tlsroute.Spec.ParentRefs, tc.tlsroute.Namespace))
 			}
 		})
 	}
@@ -2996,6 +3252,174 @@ func TestLookupUpstreamValidation(t *testing.T) {
 	}
 }
 
+func TestCertificateExpiry(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	expiredCert, expiredKey := selfSignedCertificate(t, now.Add(-time.Hour))
+	expiringCert, expiringKey := selfSignedCertificate(t, now.Add(time.Hour))
+	validCert, validKey := selfSignedCertificate(t, now.Add(30*24*time.Hour))
+
+	secret := func(cert, key string) *core_v1.Secret {
+		return &core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tls",
+				Namespace: "default",
+			},
+			Type: core_v1.SecretTypeTLS,
+			Data: secretdata(cert, key),
+		}
+	}
+
+	tests := map[string]struct {
+		secret        *core_v1.Secret
+		warningPeriod time.Duration
+		rejectExpired bool
+		wantErr       error
+		wantReason    string
+		wantMsg       string
+		wantRebuildAt time.Time
+	}{
+		"valid certificate": {
+			secret:        secret(validCert, validKey),
+			warningPeriod: 24 * time.Hour,
+			wantRebuildAt: now.Add(29 * 24 * time.Hour),
+		},
+		"expiring certificate without warning period": {
+			secret:        secret(expiringCert, expiringKey),
+			wantRebuildAt: now.Add(time.Hour),
+		},
+		"expiring certificate within warning period": {
+			secret:        secret(expiringCert, expiringKey),
+			warningPeriod: 24 * time.Hour,
+			wantReason:    certificateExpiringSoonReason,
+			wantMsg:       "certificate expires at " + now.Add(time.Hour).UTC().Format(time.RFC3339),
+			wantRebuildAt: now.Add(time.Hour),
+		},
+		"expired certificate": {
+			secret:     secret(expiredCert, expiredKey),
+			wantReason: certificateExpiredReason,
+			wantMsg:    "certificate expired at " + now.Add(-time.Hour).UTC().Format(time.RFC3339),
+		},
+		"expired certificate rejected": {
+			secret:        secret(expiredCert, expiredKey),
+			rejectExpired: true,
+			wantErr:       errors.New("certificate expired at " + now.Add(-time.Hour).UTC().Format(time.RFC3339)),
+		},
+		"valid certificate not rejected": {
+			secret:        secret(validCert, validKey),
+			rejectExpired: true,
+			wantRebuildAt: now.Add(30 * 24 * time.Hour),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := KubernetesCache{
+				CertificateExpiryWarningPeriod: tc.warningPeriod,
+				RejectExpiredCertificates:      tc.rejectExpired,
+				FieldLogger:                    fixture.NewTestLogger(t),
+			}
+			cache.Insert(tc.secret)
+
+			sec, err := cache.LookupTLSSecretInsecure(types.NamespacedName{Namespace: "default", Name: "tls"})
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			reason, msg := cache.certificateExpiryWarning(sec)
+			assert.Equal(t, tc.wantReason, reason)
+			assert.Equal(t, tc.wantMsg, msg)
+			assert.True(t, tc.wantRebuildAt.Equal(cache.rebuildAt), "rebuild at %s, want %s", cache.rebuildAt, tc.wantRebuildAt)
+		})
+	}
+}
+
+func TestLookupTLSSecretRequireOCSPStaple(t *testing.T) {
+	cert, key := selfSignedCertificate(t, time.Now().Add(time.Hour))
+
+	unstapled := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "unstapled",
+			Namespace: "default",
+		},
+		Type: core_v1.SecretTypeTLS,
+		Data: secretdata(cert, key),
+	}
+	stapled := unstapled.DeepCopy()
+	stapled.Name = "stapled"
+	now := time.Now().Truncate(time.Second)
+	nextUpdate := now.Add(time.Hour)
+	stapled.Data[OCSPStapleKey] = ocspStapleAt(t, cert, key, 1, now, nextUpdate)
+	expired := unstapled.DeepCopy()
+	expired.Name = "expired"
+	expired.Data[OCSPStapleKey] = ocspStapleAt(t, cert, key, 1, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	future := unstapled.DeepCopy()
+	future.Name = "future"
+	future.Data[OCSPStapleKey] = ocspStapleAt(t, cert, key, 1, nextUpdate, nextUpdate.Add(time.Hour))
+
+	tests := map[string]struct {
+		secret            *core_v1.Secret
+		requireOCSPStaple bool
+		wantErr           string
+		wantStaple        []byte
+		wantRebuildAt     time.Time
+	}{
+		"unstapled certificate, staple not required": {
+			secret: unstapled,
+		},
+		"unstapled certificate, staple required": {
+			secret:            unstapled,
+			requireOCSPStaple: true,
+			wantErr:           `missing "tls.ocsp-staple" key required by the must-staple OCSP policy`,
+		},
+		"stapled certificate, staple required": {
+			secret:            stapled,
+			requireOCSPStaple: true,
+			wantStaple:        stapled.Data[OCSPStapleKey],
+			wantRebuildAt:     nextUpdate,
+		},
+		"expired staple, staple not required": {
+			secret:     expired,
+			wantStaple: expired.Data[OCSPStapleKey],
+		},
+		"expired staple, staple required": {
+			secret:            expired,
+			requireOCSPStaple: true,
+			wantErr:           "OCSP staple expired at " + now.Add(-time.Hour).UTC().Format(time.RFC3339),
+		},
+		"staple not yet valid, staple required": {
+			secret:            future,
+			requireOCSPStaple: true,
+			wantErr:           "OCSP staple not valid before " + nextUpdate.UTC().Format(time.RFC3339),
+			wantRebuildAt:     nextUpdate,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := KubernetesCache{
+				RequireOCSPStaple: tc.requireOCSPStaple,
+				FieldLogger:       fixture.NewTestLogger(t),
+			}
+			cache.Insert(tc.secret)
+
+			sec, err := cache.LookupTLSSecret(types.NamespacedName{Namespace: "default", Name: tc.secret.Name}, "default")
+			assert.True(t, tc.wantRebuildAt.Equal(cache.rebuildAt), "rebuild at %s, want %s", cache.rebuildAt, tc.wantRebuildAt)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStaple, sec.OCSPStaple())
+
+			// Client certificates are not subject to the staple requirement.
+			_, err = cache.LookupTLSSecretInsecure(types.NamespacedName{Namespace: "default", Name: tc.secret.Name})
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestLookupBackendTLSPolicyByTargetRef(t *testing.T) {
 	targetRef := func(group, kind, name string, sectionName *string) gatewayapi_v1alpha2.LocalPolicyTargetReferenceWithSectionName {
 		var sn *gatewayapi_v1alpha2.SectionName
@@ -3157,6 +3581,99 @@ func TestLookupBackendTLSPolicyByTargetRef(t *testing.T) {
 	}
 }
 
+func TestLookupBackendTrafficPolicyByTargetRef(t *testing.T) {
+	serviceTargetRef := func(name string) gatewayapi_v1alpha2.LocalPolicyTargetReference {
+		return gatewayapi_v1alpha2.LocalPolicyTargetReference{
+			Kind: "Service",
+			Name: gatewayapi_v1.ObjectName(name),
+		}
+	}
+
+	backendTrafficPolicy := func(name, namespace string, created time.Time, targetRefs ...gatewayapi_v1alpha2.LocalPolicyTargetReference) *gatewayapi_x_v1alpha1.XBackendTrafficPolicy {
+		return &gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: meta_v1.NewTime(created),
+			},
+			Spec: gatewayapi_x_v1alpha1.BackendTrafficPolicySpec{
+				TargetRefs: targetRefs,
+			},
+		}
+	}
+
+	now := time.Now()
+
+	tests := map[string]struct {
+		targetRef              gatewayapi_v1alpha2.LocalPolicyTargetReference
+		namespace              string
+		backendTrafficPolicies []*gatewayapi_x_v1alpha1.XBackendTrafficPolicy
+		want                   *gatewayapi_x_v1alpha1.XBackendTrafficPolicy
+	}{
+		"finds the XBackendTrafficPolicy with the matching targetRef": {
+			targetRef: serviceTargetRef("backend-service"),
+			namespace: "ns1",
+			backendTrafficPolicies: []*gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+				backendTrafficPolicy("blp", "ns1", now, serviceTargetRef("other-service"), serviceTargetRef("backend-service")),
+				backendTrafficPolicy("blp1", "ns1", now, serviceTargetRef("other-service")),
+			},
+			want: backendTrafficPolicy("blp", "ns1", now, serviceTargetRef("other-service"), serviceTargetRef("backend-service")),
+		},
+		"finds the oldest XBackendTrafficPolicy when several match": {
+			targetRef: serviceTargetRef("backend-service"),
+			namespace: "ns1",
+			backendTrafficPolicies: []*gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+				backendTrafficPolicy("blp", "ns1", now, serviceTargetRef("backend-service")),
+				backendTrafficPolicy("blp1", "ns1", now.Add(-time.Hour), serviceTargetRef("backend-service")),
+			},
+			want: backendTrafficPolicy("blp1", "ns1", now.Add(-time.Hour), serviceTargetRef("backend-service")),
+		},
+		"finds the first XBackendTrafficPolicy by name when several match and have the same age": {
+			targetRef: serviceTargetRef("backend-service"),
+			namespace: "ns1",
+			backendTrafficPolicies: []*gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+				backendTrafficPolicy("blp2", "ns1", now, serviceTargetRef("backend-service")),
+				backendTrafficPolicy("blp1", "ns1", now, serviceTargetRef("backend-service")),
+			},
+			want: backendTrafficPolicy("blp1", "ns1", now, serviceTargetRef("backend-service")),
+		},
+		"does not find the XBackendTrafficPolicy if the namespace does not match": {
+			targetRef: serviceTargetRef("backend-service"),
+			namespace: "ns1",
+			backendTrafficPolicies: []*gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+				backendTrafficPolicy("blp", "other-ns", now, serviceTargetRef("backend-service")),
+			},
+		},
+		"does not find the XBackendTrafficPolicy if the group does not match": {
+			targetRef: gatewayapi_v1alpha2.LocalPolicyTargetReference{
+				Group: "core",
+				Kind:  "Service",
+				Name:  "backend-service",
+			},
+			namespace: "ns1",
+			backendTrafficPolicies: []*gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+				backendTrafficPolicy("blp", "ns1", now, serviceTargetRef("backend-service")),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := KubernetesCache{
+				FieldLogger: fixture.NewTestLogger(t),
+			}
+
+			for _, backendTrafficPolicy := range tc.backendTrafficPolicies {
+				cache.Insert(backendTrafficPolicy)
+			}
+
+			got, gotFound := cache.LookupBackendTrafficPolicyByTargetRef(tc.targetRef, tc.namespace)
+			assert.Equal(t, tc.want != nil, gotFound)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestLookupCAConfigMap(t *testing.T) {
 	cache := func(objs ...any) *KubernetesCache {
 		cache := KubernetesCache{
@@ -3236,3 +3753,222 @@ func TestLookupCAConfigMap(t *testing.T) {
 		})
 	}
 }
+
+func TestDelegationPermitted(t *testing.T) {
+	cache := func(objs ...any) *KubernetesCache {
+		cache := KubernetesCache{
+			FieldLogger: fixture.NewTestLogger(t),
+		}
+		for _, o := range objs {
+			cache.Insert(o)
+		}
+		return &cache
+	}
+
+	namespace := func(name string, labels map[string]string) *core_v1.Namespace {
+		return &core_v1.Namespace{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:   name,
+				Labels: labels,
+			},
+		}
+	}
+
+	secret := func(name string, labels map[string]string) *core_v1.Secret {
+		return &core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      name,
+				Namespace: "secrets",
+				Labels:    labels,
+			},
+		}
+	}
+
+	delegation := func(delegations ...contour_v1.CertificateDelegation) *contour_v1.TLSCertificateDelegation {
+		return &contour_v1.TLSCertificateDelegation{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "delegation",
+				Namespace: "secrets",
+			},
+			Spec: contour_v1.TLSCertificateDelegationSpec{
+				Delegations: delegations,
+			},
+		}
+	}
+
+	tenants := &meta_v1.LabelSelector{
+		MatchLabels: map[string]string{"tenant": "true"},
+	}
+
+	tests := map[string]struct {
+		cache           *KubernetesCache
+		secret          string
+		targetNamespace string
+		want            bool
+	}{
+		"same namespace": {
+			cache:           cache(),
+			secret:          "wildcard",
+			targetNamespace: "secrets",
+			want:            true,
+		},
+		"no delegation": {
+			cache:           cache(),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            false,
+		},
+		"exact secret name and target namespace": {
+			cache: cache(delegation(contour_v1.CertificateDelegation{
+				SecretName:       "wildcard",
+				TargetNamespaces: []string{"tenant-a"},
+			})),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            true,
+		},
+		"all target namespaces": {
+			cache: cache(delegation(contour_v1.CertificateDelegation{
+				SecretName:       "wildcard",
+				TargetNamespaces: []string{"*"},
+			})),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            true,
+		},
+		"secret name glob matches": {
+			cache: cache(delegation(contour_v1.CertificateDelegation{
+				SecretName:       "wildcard-*",
+				TargetNamespaces: []string{"tenant-a"},
+			})),
+			secret:          "wildcard-example-com",
+			targetNamespace: "tenant-a",
+			want:            true,
+		},
+		"secret name glob does not match": {
+			cache: cache(delegation(contour_v1.CertificateDelegation{
+				SecretName:       "wildcard-*",
+				TargetNamespaces: []string{"tenant-a"},
+			})),
+			secret:          "other",
+			targetNamespace: "tenant-a",
+			want:            false,
+		},
+		"invalid secret name glob": {
+			cache: cache(delegation(contour_v1.CertificateDelegation{
+				SecretName:       "wildcard-[",
+				TargetNamespaces: []string{"tenant-a"},
+			})),
+			secret:          "wildcard-[",
+			targetNamespace: "tenant-a",
+			want:            false,
+		},
+		"secret selector matches": {
+			cache: cache(
+				secret("wildcard", map[string]string{"delegated": "true"}),
+				delegation(contour_v1.CertificateDelegation{
+					SecretSelector: &meta_v1.LabelSelector{
+						MatchLabels: map[string]string{"delegated": "true"},
+					},
+					TargetNamespaces: []string{"tenant-a"},
+				}),
+			),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            true,
+		},
+		"secret selector does not match": {
+			cache: cache(
+				secret("wildcard", nil),
+				delegation(contour_v1.CertificateDelegation{
+					SecretSelector: &meta_v1.LabelSelector{
+						MatchLabels: map[string]string{"delegated": "true"},
+					},
+					TargetNamespaces: []string{"tenant-a"},
+				}),
+			),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            false,
+		},
+		"secret selector with missing secret": {
+			cache: cache(
+				delegation(contour_v1.CertificateDelegation{
+					SecretSelector:   &meta_v1.LabelSelector{},
+					TargetNamespaces: []string{"tenant-a"},
+				}),
+			),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            false,
+		},
+		"secret name and selector are both specified": {
+			cache: cache(
+				secret("wildcard", nil),
+				delegation(contour_v1.CertificateDelegation{
+					SecretName:       "wildcard",
+					SecretSelector:   &meta_v1.LabelSelector{},
+					TargetNamespaces: []string{"tenant-a"},
+				}),
+			),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            false,
+		},
+		"target namespace selector matches": {
+			cache: cache(
+				namespace("tenant-a", map[string]string{"tenant": "true"}),
+				delegation(contour_v1.CertificateDelegation{
+					SecretName:              "wildcard",
+					TargetNamespaceSelector: tenants,
+				}),
+			),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            true,
+		},
+		"target namespace selector does not match": {
+			cache: cache(
+				namespace("tenant-a", map[string]string{"tenant": "false"}),
+				delegation(contour_v1.CertificateDelegation{
+					SecretName:              "wildcard",
+					TargetNamespaceSelector: tenants,
+				}),
+			),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            false,
+		},
+		"target namespace selector with missing namespace": {
+			cache: cache(
+				delegation(contour_v1.CertificateDelegation{
+					SecretName:              "wildcard",
+					TargetNamespaceSelector: tenants,
+				}),
+			),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            false,
+		},
+		"target namespace listed or selected": {
+			cache: cache(
+				namespace("tenant-b", map[string]string{"tenant": "true"}),
+				delegation(contour_v1.CertificateDelegation{
+					SecretName:              "wildcard",
+					TargetNamespaces:        []string{"tenant-a"},
+					TargetNamespaceSelector: tenants,
+				}),
+			),
+			secret:          "wildcard",
+			targetNamespace: "tenant-a",
+			want:            true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tc.cache.delegationPermitted(types.NamespacedName{Namespace: "secrets", Name: tc.secret}, tc.targetNamespace)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/projectcontour/contour/internal/status"
 	"github.com/projectcontour/contour/internal/timeout"
+	"github.com/projectcontour/contour/internal/xds"
 )
 
 // Observer is an interface for receiving notification of DAG updates.
@@ -68,6 +69,11 @@ type DAG struct {
 	// and Listeners are derived from the Gateway's Listeners, or
 	// false otherwise.
 	HasDynamicListeners bool
+
+	// RebuildAt is the time after which the DAG is stale even
+	// if nothing changes in the cache, e.g. because a certificate
+	// expires. It is zero if the DAG does not go stale.
+	RebuildAt time.Time
 }
 
 type MatchCondition interface {
@@ -356,6 +362,10 @@ type Route struct {
 	// host to be used to validate JWTs on requests to this route.
 	JWTProvider string
 
+	// JWTRequiredClaims lists the claims that the JWT validated
+	// by JWTProvider must have for requests to be allowed.
+	JWTRequiredClaims []JWTRequiredClaim
+
 	// InternalRedirectPolicy defines if envoy should handle redirect
 	// response internally instead of sending it downstream.
 	InternalRedirectPolicy *InternalRedirectPolicy
@@ -370,10 +380,18 @@ type Route struct {
 	// by IPFilterAllow.
 	IPFilterRules []IPFilterRule
 
+	// RBACPolicy defines the rules for which matching requests
+	// should be allowed or denied.
+	RBACPolicy *RBACPolicy
+
 	// Metadata fields that can be used for access logging.
 	Kind      string
 	Namespace string
 	Name      string
+
+	// StatPrefix names the route-level and virtual cluster statistics
+	// Envoy emits for this route. If empty, they are not emitted.
+	StatPrefix string
 }
 
 // HasPathPrefix returns whether this route has a PrefixPathCondition.
@@ -486,6 +504,25 @@ type RateLimitPolicy struct {
 	Global *GlobalRateLimitPolicy
 }
 
+// jwtPayloadProviders returns the names of the JWT providers
+// whose payloads the global rate limit descriptors read from
+// the request metadata.
+func (p *RateLimitPolicy) jwtPayloadProviders() []string {
+	if p == nil || p.Global == nil {
+		return nil
+	}
+
+	var names []string
+	for _, d := range p.Global.Descriptors {
+		for _, entry := range d.Entries {
+			if entry.Metadata != nil && entry.Metadata.Filter == JWTAuthnMetadataNamespace {
+				names = append(names, entry.Metadata.Path[0])
+			}
+		}
+	}
+	return names
+}
+
 // LocalRateLimitPolicy holds local rate limiting parameters.
 type LocalRateLimitPolicy struct {
 	MaxTokens            uint32
@@ -493,6 +530,35 @@ type LocalRateLimitPolicy struct {
 	FillInterval         time.Duration
 	ResponseStatusCode   uint32
 	ResponseHeadersToAdd map[string]string
+
+	// Descriptors holds the token buckets for requests
+	// that match the descriptors' entries.
+	Descriptors []*LocalRateLimitDescriptor
+
+	// MaxDynamicDescriptors is the number of token buckets kept for
+	// the distinct values of descriptors with wildcard entries, or
+	// zero for Envoy's default.
+	MaxDynamicDescriptors uint32
+}
+
+// LocalRateLimitDescriptor holds a token bucket for
+// requests that generate all of the descriptor's entries.
+type LocalRateLimitDescriptor struct {
+	Entries       []LocalRateLimitDescriptorEntry
+	MaxTokens     uint32
+	TokensPerFill uint32
+	FillInterval  time.Duration
+}
+
+// LocalRateLimitDescriptorEntry is a descriptor entry and
+// the value it must generate for a request to match.
+type LocalRateLimitDescriptorEntry struct {
+	RateLimitDescriptorEntry
+
+	// Value is empty for entries with a static value, and for
+	// wildcard entries, which match any value with a token bucket
+	// per distinct value.
+	Value string
 }
 
 // HeaderHashOptions contains options for hashing a HTTP header.
@@ -552,10 +618,14 @@ type RateLimitDescriptor struct {
 // RateLimitDescriptorEntry is an entry in a rate limit descriptor.
 // Exactly one field should be non-nil.
 type RateLimitDescriptorEntry struct {
-	GenericKey       *GenericKeyDescriptorEntry
-	HeaderMatch      *HeaderMatchDescriptorEntry
-	HeaderValueMatch *HeaderValueMatchDescriptorEntry
-	RemoteAddress    *RemoteAddressDescriptorEntry
+	GenericKey          *GenericKeyDescriptorEntry
+	HeaderMatch         *HeaderMatchDescriptorEntry
+	HeaderValueMatch    *HeaderValueMatchDescriptorEntry
+	RemoteAddress       *RemoteAddressDescriptorEntry
+	MaskedRemoteAddress *MaskedRemoteAddressDescriptorEntry
+	QueryParameter      *QueryParameterDescriptorEntry
+	Metadata            *MetadataDescriptorEntry
+	DestinationCluster  *DestinationClusterDescriptorEntry
 }
 
 // GenericKeyDescriptorEntry  configures a descriptor entry
@@ -601,6 +671,39 @@ type RateLimitPerRoute struct {
 // that contains the remote address (i.e. client IP).
 type RemoteAddressDescriptorEntry struct{}
 
+// MaskedRemoteAddressDescriptorEntry configures a descriptor entry
+// that contains the remote address masked to a prefix length.
+// A zero prefix length means the Envoy default.
+type MaskedRemoteAddressDescriptorEntry struct {
+	V4PrefixLength uint32
+	V6PrefixLength uint32
+}
+
+// QueryParameterDescriptorEntry configures a descriptor entry
+// that's populated only if the specified query parameter is
+// present on the request.
+type QueryParameterDescriptorEntry struct {
+	ParameterName string
+	Key           string
+}
+
+// JWTAuthnMetadataNamespace is the dynamic metadata namespace
+// holding the payloads of validated JWTs, keyed by provider name.
+const JWTAuthnMetadataNamespace = "envoy.filters.http.jwt_authn"
+
+// MetadataDescriptorEntry configures a descriptor entry
+// that's populated from the request's dynamic metadata.
+type MetadataDescriptorEntry struct {
+	Filter       string
+	Path         []string
+	Key          string
+	DefaultValue string
+}
+
+// DestinationClusterDescriptorEntry configures a descriptor entry
+// that contains the name of the upstream cluster.
+type DestinationClusterDescriptorEntry struct{}
+
 // CORSAllowOriginMatchType differentiates different CORS origin matching
 // methods.
 type CORSAllowOriginMatchType int
@@ -781,6 +884,17 @@ func conditionsToString(r *Route) string {
 	return strings.Join(s, ",")
 }
 
+// HasRBACPolicy returns true if any of the virtual host's
+// routes has an authorization policy.
+func (v *VirtualHost) HasRBACPolicy() bool {
+	for _, route := range v.Routes {
+		if route.RBACPolicy != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (v *VirtualHost) Valid() bool {
 	// A VirtualHost is valid if it has at least one route.
 	return len(v.Routes) > 0
@@ -799,6 +913,10 @@ type SecureVirtualHost struct {
 	// The cert and key for this host.
 	Secret *Secret
 
+	// AdditionalSecrets are further certs and keys for this host,
+	// e.g. an ECDSA cert served alongside an RSA one.
+	AdditionalSecrets []*Secret
+
 	// FallbackCertificate
 	FallbackCertificate *Secret
 
@@ -817,11 +935,32 @@ type SecureVirtualHost struct {
 }
 
 type JWTProvider struct {
-	Name       string
-	Issuer     string
-	Audiences  []string
-	RemoteJWKS RemoteJWKS
-	ForwardJWT bool
+	Name      string
+	Issuer    string
+	Audiences []string
+
+	// RemoteJWKS is set if the JWKS is fetched from
+	// an HTTP endpoint, otherwise LocalJWKS holds
+	// the JWKS.
+	RemoteJWKS *RemoteJWKS
+	LocalJWKS  string
+
+	ForwardJWT     bool
+	ClaimToHeaders []JWTClaimToHeader
+
+	// PayloadInMetadata is true if routes require
+	// claims of the JWTs validated by the provider.
+	PayloadInMetadata bool
+}
+
+type JWTClaimToHeader struct {
+	HeaderName string
+	Claim      string
+}
+
+type JWTRequiredClaim struct {
+	Name   string
+	Values []string
 }
 
 type RemoteJWKS struct {
@@ -858,6 +997,52 @@ type IPFilterRule struct {
 	CIDR net.IPNet
 }
 
+// RBACPolicy defines rules that allow or deny requests.
+// Requests matching any Deny rule are denied. If there are Allow
+// rules, requests must also match at least one of them.
+type RBACPolicy struct {
+	Allow []RBACRule
+	Deny  []RBACRule
+}
+
+// HasJWTClaims returns true if any of the policy's rules match on JWT claims.
+func (p *RBACPolicy) HasJWTClaims() bool {
+	if p == nil {
+		return false
+	}
+	for _, rules := range [][]RBACRule{p.Allow, p.Deny} {
+		for _, rule := range rules {
+			if len(rule.JWTClaims) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RBACRule matches requests that match all of its conditions.
+type RBACRule struct {
+	// Methods matches any of the HTTP methods.
+	Methods []string
+
+	// Paths matches any of the path conditions.
+	Paths []MatchCondition
+
+	// Headers matches all of the header conditions.
+	Headers []HeaderMatchCondition
+
+	// ClientCertificateNames matches any of the names
+	// against the client certificate's SANs and subject.
+	ClientCertificateNames []string
+
+	// JWTClaims matches all of the claims of the JWT
+	// validated by the route's JWT provider.
+	JWTClaims []JWTRequiredClaim
+
+	// SourceIPs matches any of the ip ranges.
+	SourceIPs []IPFilterRule
+}
+
 // ExternalAuthorization contains the configuration for enabling
 // the ExtAuthz filter.
 type ExternalAuthorization struct {
@@ -879,6 +1064,47 @@ type ExternalAuthorization struct {
 	// AuthorizationServerWithRequestBody specifies configuration
 	// for buffering request data sent to AuthorizationServer
 	AuthorizationServerWithRequestBody *AuthorizationServerBufferSettings
+
+	// AuthorizationHTTPService, if set, configures the authorization
+	// server to be reached over plain HTTP rather than gRPC.
+	AuthorizationHTTPService *AuthorizationHTTPService
+
+	// AuthorizationForwarding, if set, restricts the headers and
+	// metadata exchanged with the authorization server.
+	AuthorizationForwarding *AuthorizationForwarding
+}
+
+// AuthorizationHTTPService configures an authorization
+// server that is reached over plain HTTP.
+type AuthorizationHTTPService struct {
+	// PathPrefix is prepended to the client request
+	// path when sending the authorization request.
+	PathPrefix string
+}
+
+// AuthorizationForwarding configures which headers and metadata
+// are exchanged with the authorization server.
+type AuthorizationForwarding struct {
+	// AllowedRequestHeaders lists the client request headers
+	// that are sent to the authorization server.
+	AllowedRequestHeaders []string
+
+	// AllowedUpstreamHeaders lists the headers that the
+	// authorization server may set on an allowed client
+	// request before it is forwarded upstream.
+	AllowedUpstreamHeaders []string
+
+	// AllowedClientHeaders lists the headers of a denied
+	// authorization response that are returned to the client.
+	AllowedClientHeaders []string
+
+	// MetadataNamespaces lists the dynamic metadata
+	// namespaces sent to the authorization server.
+	MetadataNamespaces []string
+
+	// RouteMetadataNamespaces lists the route metadata
+	// namespaces sent to the authorization server.
+	RouteMetadataNamespaces []string
 }
 
 // AuthorizationServerBufferSettings enables ExtAuthz filter to buffer client
@@ -901,6 +1127,15 @@ type AuthorizationServerBufferSettings struct {
 	PackAsBytes bool
 }
 
+// Secrets returns the certs and keys for this host, starting
+// with Secret, or nil if TLS is not terminated.
+func (s *SecureVirtualHost) Secrets() []*Secret {
+	if s.Secret == nil {
+		return nil
+	}
+	return append([]*Secret{s.Secret}, s.AdditionalSecrets...)
+}
+
 func (s *SecureVirtualHost) Valid() bool {
 	// A SecureVirtualHost is valid if either
 	// 1. it has a secret and at least one route.
@@ -960,6 +1195,39 @@ type TCPProxy struct {
 	// Clusters is the, possibly weighted, set
 	// of upstream services to forward decrypted traffic.
 	Clusters []*Cluster
+
+	// IdleTimeout is the idle timeout for connections
+	// through this proxy.
+	IdleTimeout timeout.Setting
+
+	// MaxConnectAttempts is the maximum number of unsuccessful
+	// upstream connection attempts. Zero means the Envoy default.
+	MaxConnectAttempts uint32
+
+	// AccessLog overrides the listener's access logging for
+	// connections through this proxy, if set.
+	AccessLog *TCPProxyAccessLog
+
+	// StatPrefix names the statistics Envoy emits for connections
+	// through this proxy. If empty, they are named after the listener.
+	StatPrefix string
+
+	// Metadata fields that identify the routing object of this proxy.
+	// They are prefixed, unlike those of a Route, since a TCPProxy is
+	// embedded in its SecureVirtualHost.
+	SourceKind      string
+	SourceNamespace string
+	SourceName      string
+}
+
+// TCPProxyAccessLog holds the access log settings of a TCPProxy.
+type TCPProxyAccessLog struct {
+	// Disabled turns off access logging.
+	Disabled bool
+
+	// Format is the Envoy format string for access log entries,
+	// or empty to use the listener's format.
+	Format string
 }
 
 // Service represents a single Kubernetes' Service's Port.
@@ -1050,6 +1318,51 @@ type Cluster struct {
 
 	// UpstreamTLS contains the TLS version and cipher suite configurations for upstream connections
 	UpstreamTLS *UpstreamTLS
+
+	// UpstreamProxyProtocol is the PROXY protocol version, "v1" or "v2",
+	// sent to the upstream at the start of each connection. Empty if no
+	// PROXY protocol header is sent.
+	UpstreamProxyProtocol string
+
+	// StatPrefix is prepended to the stat name of this cluster, so that
+	// its statistics are attributed to the routing object that owns it.
+	// It does not change the identity of the cluster, so a cluster
+	// shared by routing objects with different StatPrefixes keeps
+	// its default stat name.
+	StatPrefix string
+
+	// TrafficShift, if set, balances the requests of this Cluster
+	// between its Upstream, the stable service of a traffic shift,
+	// and the canary service of the shift.
+	TrafficShift *TrafficShift
+}
+
+// TrafficShift balances the requests of a Cluster between the
+// endpoints of a stable and a canary service, by weighting them
+// in a ClusterLoadAssignment of their own.
+type TrafficShift struct {
+	// ClusterLoadAssignmentName is the name of the EDS
+	// ClusterLoadAssignment holding the endpoints of
+	// both services.
+	ClusterLoadAssignmentName string
+
+	// Canary is the service the traffic is shifted to.
+	Canary *Service
+
+	// Weight is the percentage of requests sent to Canary.
+	Weight uint32
+}
+
+// trafficShiftServiceCluster returns the ServiceCluster weighting the stable
+// service of the Cluster against the canary service of the shift.
+func (c *Cluster) trafficShiftServiceCluster() *ServiceCluster {
+	stable, canary := c.Upstream.Weighted, c.TrafficShift.Canary.Weighted
+	stable.Weight, canary.Weight = 100-c.TrafficShift.Weight, c.TrafficShift.Weight
+
+	return &ServiceCluster{
+		ClusterName: c.TrafficShift.ClusterLoadAssignmentName,
+		Services:    []WeightedService{stable, canary},
+	}
 }
 
 // WeightedService represents the load balancing weight of a
@@ -1065,6 +1378,19 @@ type WeightedService struct {
 	ServicePort core_v1.ServicePort
 	// HealthPort is the port for healthcheck.
 	HealthPort core_v1.ServicePort
+	// Imported is true if ServiceName and ServiceNamespace name a
+	// multi-cluster ServiceImport rather than a core_v1.Service.
+	Imported bool
+}
+
+// ClusterLoadAssignmentName returns the name of the EDS
+// ClusterLoadAssignment for the port of this service.
+func (w *WeightedService) ClusterLoadAssignmentName() string {
+	name := types.NamespacedName{Name: w.ServiceName, Namespace: w.ServiceNamespace}
+	if w.Imported {
+		return xds.ServiceImportClusterLoadAssignmentName(name, w.ServicePort.Name)
+	}
+	return xds.ClusterLoadAssignmentName(name, w.ServicePort.Name)
 }
 
 // ServiceCluster capture the set of Kubernetes Services that will
@@ -1180,6 +1506,27 @@ func (s *Secret) PrivateKey() []byte {
 	return s.Object.Data[core_v1.TLSPrivateKeyKey]
 }
 
+// OCSPStaple returns the secret's DER encoded OCSP response, if any.
+func (s *Secret) OCSPStaple() []byte {
+	return s.Object.Data[OCSPStapleKey]
+}
+
+// CertificateExpiry returns the earliest expiry time of the
+// certificates in the secret's tls certificate bundle, or false
+// if the bundle holds no parsable certificate.
+func (s *Secret) CertificateExpiry() (time.Time, bool) {
+	notAfter := certificatesNotAfter(s.Cert())
+	return notAfter, !notAfter.IsZero()
+}
+
+// CACertificateExpiry returns the earliest expiry time of the
+// certificates in the secret's CA bundle, or false if the bundle
+// holds no parsable certificate.
+func (s *Secret) CACertificateExpiry() (time.Time, bool) {
+	notAfter := certificatesNotAfter(s.Object.Data[CACertificateKey])
+	return notAfter, !notAfter.IsZero()
+}
+
 type SecretValidationStatus struct {
 	Error error
 }
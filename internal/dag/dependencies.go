@@ -0,0 +1,134 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"reflect"
+	"time"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+// dependency identifies an object in the KubernetesCache.
+type dependency struct {
+	kind string
+	name types.NamespacedName
+}
+
+// dependencies records the KubernetesCache objects, and the DAG
+// extension clusters, that a part of the DAG was computed from.
+type dependencies struct {
+	objects           map[dependency]struct{}
+	kinds             map[string]struct{}
+	extensionClusters map[string]*ExtensionCluster
+
+	// expires is the time after which the computed part of
+	// the DAG is stale even if none of its dependencies
+	// changed, e.g. because a certificate expires.
+	expires time.Time
+}
+
+func newDependencies() *dependencies {
+	return &dependencies{
+		objects:           map[dependency]struct{}{},
+		kinds:             map[string]struct{}{},
+		extensionClusters: map[string]*ExtensionCluster{},
+	}
+}
+
+// changed returns true if any of the dependencies changed in the
+// KubernetesCache since the last DAG build, if any of the extension
+// clusters differ from the ones in the given DAG, or if the
+// dependencies expired.
+func (d *dependencies) changed(kc *KubernetesCache, dag *DAG, now time.Time) bool {
+	if !d.expires.IsZero() && !now.Before(d.expires) {
+		return true
+	}
+
+	for kind := range d.kinds {
+		if _, ok := kc.changedKinds[kind]; ok {
+			return true
+		}
+	}
+
+	for dep := range d.objects {
+		if _, ok := kc.changes[dep]; ok {
+			return true
+		}
+	}
+
+	for name, ec := range d.extensionClusters {
+		if !reflect.DeepEqual(ec, dag.GetExtensionCluster(name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordChange records that obj was inserted into, or removed
+// from, the KubernetesCache.
+func (kc *KubernetesCache) recordChange(obj any) {
+	o, ok := obj.(meta_v1.Object)
+	if !ok {
+		return
+	}
+
+	kind := k8s.KindOf(obj)
+	kc.changes[dependency{kind: kind, name: k8s.NamespacedNameOf(o)}] = struct{}{}
+	kc.changedKinds[kind] = struct{}{}
+}
+
+// resetChanges forgets the changes recorded since the last DAG build.
+func (kc *KubernetesCache) resetChanges() {
+	kc.initialize.Do(kc.init)
+
+	clear(kc.changes)
+	clear(kc.changedKinds)
+}
+
+// dependOn records that the part of the DAG being computed
+// depends on the named object of the given kind, whether or
+// not it exists.
+func (kc *KubernetesCache) dependOn(kind string, name types.NamespacedName) {
+	if kc.deps != nil {
+		kc.deps.objects[dependency{kind: kind, name: name}] = struct{}{}
+	}
+}
+
+// dependOnKind records that the part of the DAG being computed
+// depends on all objects of the given kind.
+func (kc *KubernetesCache) dependOnKind(kind string) {
+	if kc.deps != nil {
+		kc.deps.kinds[kind] = struct{}{}
+	}
+}
+
+// dependUntil records that the part of the DAG being computed,
+// and so the whole DAG, is stale after t. Times in the past are
+// ignored.
+func (kc *KubernetesCache) dependUntil(t time.Time) {
+	if !t.After(time.Now()) {
+		return
+	}
+	if kc.rebuildAt.IsZero() || t.Before(kc.rebuildAt) {
+		kc.rebuildAt = t
+	}
+	if kc.deps != nil && (kc.deps.expires.IsZero() || t.Before(kc.deps.expires)) {
+		kc.deps.expires = t
+	}
+}
@@ -166,7 +166,7 @@ func (p *ExtensionServiceProcessor) buildExtensionService(
 			".Spec.TimeoutPolicy.Idle")
 	}
 
-	// API server validation ensures that the protocol is "h2" or "h2c".
+	// API server validation ensures that the protocol is "h2", "h2c", "tls" or "http/1.1".
 	if ext.Spec.Protocol != nil {
 		extension.Protocol = stringOrDefault(*ext.Spec.Protocol, extension.Protocol)
 	}
@@ -200,7 +200,7 @@ func (p *ExtensionServiceProcessor) buildExtensionService(
 		// TODO(jpeach): expose SNI in the API, https://github.com/projectcontour/contour/issues/2893.
 		extension.SNI = uv.SubjectNames[0]
 
-		if extension.Protocol != "h2" {
+		if extension.Protocol != "h2" && extension.Protocol != "tls" {
 			validCondition.AddErrorf(contour_v1.ConditionTypeSpecError, "InconsistentProtocol",
 				"upstream TLS validation not supported for %q protocol", extension.Protocol)
 		}
@@ -31,6 +31,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/gatewayapi"
@@ -45,6 +47,8 @@ const (
 	KindGRPCRoute = "GRPCRoute"
 	KindTCPRoute  = "TCPRoute"
 	KindGateway   = "Gateway"
+
+	KindServiceImport = "ServiceImport"
 )
 
 // GatewayAPIProcessor translates Gateway API types into DAG
@@ -55,6 +59,13 @@ type GatewayAPIProcessor struct {
 	dag    *DAG
 	source *KubernetesCache
 
+	// gateway is the Gateway being processed.
+	gateway *gatewayapi_v1.Gateway
+
+	// listenerSets are the ListenerSets attached to the Gateway
+	// being processed, whether or not the Gateway allows them.
+	listenerSets []*gatewayapi_x_v1alpha1.XListenerSet
+
 	// EnableExternalNameService allows processing of ExternalNameServices
 	// This is normally disabled for security reasons.
 	// See https://github.com/projectcontour/contour/security/advisories/GHSA-5ph6-qq5x-7jwc for details.
@@ -100,10 +111,13 @@ func (p *GatewayAPIProcessor) Run(dag *DAG, source *KubernetesCache) {
 	defer func() {
 		p.dag = nil
 		p.source = nil
+		p.gateway = nil
+		p.listenerSets = nil
 	}()
 
 	// Gateway and GatewayClass must be defined for resources to be processed.
-	if p.source.gateway == nil {
+	gateways := p.source.servedGateways()
+	if len(gateways) == 0 {
 		p.Info("Gateway not found in cache.")
 		return
 	}
@@ -112,16 +126,32 @@ func (p *GatewayAPIProcessor) Run(dag *DAG, source *KubernetesCache) {
 		return
 	}
 
+	for _, gateway := range gateways {
+		// When several Gateways are served, they must all be of the
+		// cached GatewayClass.
+		if len(p.source.ConfiguredGatewaysToCache) > 0 && string(gateway.Spec.GatewayClassName) != p.source.gatewayclass.Name {
+			p.Infof("Gateway %s/%s is not of GatewayClass %s, skipping.", gateway.Namespace, gateway.Name, p.source.gatewayclass.Name)
+			continue
+		}
+
+		p.gateway = gateway
+		p.processGateway()
+	}
+}
+
+// processGateway translates the Gateway being processed, and the routes
+// attached to it, into DAG objects and sets their status.
+func (p *GatewayAPIProcessor) processGateway() {
 	gwAccessor, commit := p.dag.StatusCache.GatewayStatusAccessor(
-		k8s.NamespacedNameOf(p.source.gateway),
-		p.source.gateway.Generation,
-		&p.source.gateway.Status,
+		k8s.NamespacedNameOf(p.gateway),
+		p.gateway.Generation,
+		&p.gateway.Status,
 	)
 	defer commit()
 
 	var gatewayNotProgrammedCondition *meta_v1.Condition
 
-	if !isAddressAssigned(p.source.gateway.Spec.Addresses, p.source.gateway.Status.Addresses) {
+	if !isAddressAssigned(p.gateway.Spec.Addresses, p.gateway.Status.Addresses) {
 		// TODO(sk) resolve condition type-reason mismatch
 		gatewayNotProgrammedCondition = &meta_v1.Condition{
 			Type:    string(gatewayapi_v1.GatewayConditionProgrammed),
@@ -131,28 +161,59 @@ func (p *GatewayAPIProcessor) Run(dag *DAG, source *KubernetesCache) {
 		}
 	}
 
-	// Validate listener protocols, ports and hostnames and add conditions
-	// for all invalid listeners.
-	validateListenersResult := gatewayapi.ValidateListeners(p.source.gateway.Spec.Listeners)
-	for name, cond := range validateListenersResult.InvalidListenerConditions {
-		gwAccessor.AddListenerCondition(
-			string(name),
-			gatewayapi_v1.ListenerConditionType(cond.Type),
-			cond.Status,
-			gatewayapi_v1.ListenerConditionReason(cond.Reason),
-			cond.Message,
+	// Set the status of the ListenerSets the Gateway does not allow,
+	// and keep the status accessors of the ones it does.
+	p.listenerSets = p.source.gatewayListenerSets(p.gateway)
+	listenerSetAccessors := map[types.NamespacedName]*status.ListenerSetStatusUpdate{}
+	for _, listenerSet := range p.listenerSets {
+		lsAccessor, commit := p.dag.StatusCache.ListenerSetStatusAccessor(
+			k8s.NamespacedNameOf(listenerSet),
+			listenerSet.Generation,
+			&listenerSet.Status,
 		)
+		defer commit()
+
+		if !p.source.listenerSetAllowed(p.gateway, listenerSet) {
+			msg := "ListenerSet is not allowed by the Gateway's Spec.AllowedListeners"
+			lsAccessor.AddCondition(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted, meta_v1.ConditionFalse, gatewayapi_x_v1alpha1.ListenerSetReasonNotAllowed, msg)
+			lsAccessor.AddCondition(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed, meta_v1.ConditionFalse, gatewayapi_x_v1alpha1.ListenerSetReasonNotAllowed, msg)
+			continue
+		}
+
+		listenerSetAccessors[k8s.NamespacedNameOf(listenerSet)] = lsAccessor
+	}
+
+	// Validate listener protocols, ports and hostnames of the Gateway and
+	// of the ListenerSets it allows, and add conditions for all invalid
+	// listeners. The Gateway's listeners take precedence.
+	validateListenersResult := gatewayapi.ValidateListeners(p.gateway.Spec.Listeners, p.source.allowedListenerSetListeners(p.gateway)...)
+	addInvalidListenerConditions(gwAccessor, validateListenersResult.InvalidListenerConditions)
+	for name, result := range validateListenersResult.ListenerSets {
+		addInvalidListenerConditions(listenerSetAccessors[name], result.InvalidListenerConditions)
 	}
 
 	// Compute listeners and save a list of the valid/ready ones.
 	var listenerInfos []*listenerInfo
-	for _, listener := range p.source.gateway.Spec.Listeners {
-		listenerInfos = append(listenerInfos, p.computeListener(listener, gwAccessor, validateListenersResult))
+	for _, listener := range p.gateway.Spec.Listeners {
+		listenerInfos = append(listenerInfos, p.computeListener(listener, nil, gwAccessor, validateListenersResult.ListenerNames, validateListenersResult.InvalidListenerConditions))
+	}
+	for _, listenerSet := range p.listenerSets {
+		lsAccessor, ok := listenerSetAccessors[k8s.NamespacedNameOf(listenerSet)]
+		if !ok {
+			continue
+		}
+
+		result := validateListenersResult.ListenerSets[k8s.NamespacedNameOf(listenerSet)]
+		for _, listener := range gatewayapi.ListenersOfListenerSet(listenerSet) {
+			listenerInfos = append(listenerInfos, p.computeListener(listener, listenerSet, lsAccessor, result.ListenerNames, result.InvalidListenerConditions))
+		}
+
+		computeListenerSetConditions(lsAccessor, gatewayNotProgrammedCondition)
 	}
 
 	// Keep track of the number of routes attached
 	// to each Listener so we can set status properly.
-	listenerAttachedRoutes := map[string]int{}
+	listenerAttachedRoutes := map[*listenerInfo]int{}
 
 	// Process sorted HTTPRoutes.
 	for _, httpRoute := range sortHTTPRoutes(p.source.httproutes) {
@@ -174,32 +235,78 @@ func (p *GatewayAPIProcessor) Run(dag *DAG, source *KubernetesCache) {
 		p.processRoute(KindTCPRoute, tcpRoute, tcpRoute.Spec.ParentRefs, gatewayNotProgrammedCondition, listenerInfos, listenerAttachedRoutes, &gatewayapi_v1alpha2.TCPRoute{})
 	}
 
-	for listenerName, attachedRoutes := range listenerAttachedRoutes {
-		gwAccessor.SetListenerAttachedRoutes(listenerName, int32(attachedRoutes)) //nolint:gosec // disable G115
+	for listener, attachedRoutes := range listenerAttachedRoutes {
+		listener.status.SetListenerAttachedRoutes(string(listener.listener.Name), int32(attachedRoutes)) //nolint:gosec // disable G115
 	}
 
 	p.computeGatewayConditions(gwAccessor, gatewayNotProgrammedCondition)
 }
 
+// addInvalidListenerConditions adds the conditions of the invalid
+// listeners found by gatewayapi.ValidateListeners to their status.
+func addInvalidListenerConditions(listenerStatus listenerStatusUpdate, invalidListenerConditions map[gatewayapi_v1.SectionName]meta_v1.Condition) {
+	for name, cond := range invalidListenerConditions {
+		listenerStatus.AddListenerCondition(
+			string(name),
+			gatewayapi_v1.ListenerConditionType(cond.Type),
+			cond.Status,
+			gatewayapi_v1.ListenerConditionReason(cond.Reason),
+			cond.Message,
+		)
+	}
+}
+
+// parentRefListenerSet returns the ListenerSet of the Gateway being
+// processed that the parent ref of a route in routeNamespace refers to,
+// and whether there is one.
+func (p *GatewayAPIProcessor) parentRefListenerSet(routeParentRef gatewayapi_v1.ParentReference, routeNamespace string) (*gatewayapi_x_v1alpha1.XListenerSet, bool) {
+	for _, listenerSet := range p.listenerSets {
+		if gatewayapi.IsRefToListenerSet(routeParentRef, routeNamespace, k8s.NamespacedNameOf(listenerSet)) {
+			return listenerSet, true
+		}
+	}
+
+	return nil, false
+}
+
+// policyAncestorRef returns the ancestor ref to set the status of a policy
+// for, when it applies to a route through the given parent ref. This is the
+// parent ref itself, or the Gateway for a parent ref to a ListenerSet.
+func (p *GatewayAPIProcessor) policyAncestorRef(routeParentRef gatewayapi_v1.ParentReference) gatewayapi_v1.ParentReference {
+	if gatewayapi.IsRefToGateway(routeParentRef, k8s.NamespacedNameOf(p.gateway)) {
+		return routeParentRef
+	}
+
+	return gatewayapi.GatewayParentRef(p.gateway.Namespace, p.gateway.Name)
+}
+
 func (p *GatewayAPIProcessor) processRoute(
 	routeKind gatewayapi_v1.Kind,
 	route client.Object,
 	parentRefs []gatewayapi_v1.ParentReference,
 	gatewayNotProgrammedCondition *meta_v1.Condition,
 	listeners []*listenerInfo,
-	listenerAttachedRoutes map[string]int,
+	listenerAttachedRoutes map[*listenerInfo]int,
 	emptyResource client.Object,
 ) {
 	routeStatus, commit := p.dag.StatusCache.RouteConditionsAccessor(
+		k8s.NamespacedNameOf(p.gateway),
 		k8s.NamespacedNameOf(route),
+		route.GetUID(),
 		route.GetGeneration(),
 		emptyResource,
 	)
 	defer commit()
 
+	for _, listenerSet := range p.listenerSets {
+		routeStatus.ListenerSetRefs = append(routeStatus.ListenerSetRefs, k8s.NamespacedNameOf(listenerSet))
+	}
+
 	for _, routeParentRef := range parentRefs {
-		// If this parent ref is to a different Gateway, ignore it.
-		if !gatewayapi.IsRefToGateway(routeParentRef, k8s.NamespacedNameOf(p.source.gateway)) {
+		// If this parent ref is to a different Gateway, or to a
+		// ListenerSet of a different Gateway, ignore it.
+		listenerSet, isRefToListenerSet := p.parentRefListenerSet(routeParentRef, route.GetNamespace())
+		if !isRefToListenerSet && !gatewayapi.IsRefToGateway(routeParentRef, k8s.NamespacedNameOf(p.gateway)) {
 			continue
 		}
 
@@ -214,7 +321,7 @@ func (p *GatewayAPIProcessor) processRoute(
 		// (a) included by this parent ref, and
 		// (b) allow the route (based on kind, namespace), and
 		// (c) the 'listenerInfo.ready' is true
-		allowedListeners := p.getListenersForRouteParentRef(routeParentRef, route.GetNamespace(), routeKind, listeners, listenerAttachedRoutes, routeParentStatus)
+		allowedListeners := p.getListenersForRouteParentRef(routeParentRef, listenerSet, route.GetNamespace(), routeKind, listeners, listenerAttachedRoutes, routeParentStatus)
 		if len(allowedListeners) == 0 {
 			p.resolveRouteRefs(route, routeParentStatus)
 		}
@@ -224,7 +331,7 @@ func (p *GatewayAPIProcessor) processRoute(
 		otherListenerHostnames := []string{}
 		for _, listener := range listeners {
 			name := string(listener.listener.Name)
-			if _, ok := allowedListeners[name]; !ok && listener.listener.Hostname != nil && len(*listener.listener.Hostname) > 0 {
+			if allowedListeners[name] != listener && listener.listener.Hostname != nil && len(*listener.listener.Hostname) > 0 {
 				otherListenerHostnames = append(otherListenerHostnames, string(*listener.listener.Hostname))
 			}
 		}
@@ -272,11 +379,11 @@ func (p *GatewayAPIProcessor) processRoute(
 			case *gatewayapi_v1.HTTPRoute:
 				p.computeHTTPRouteForListener(route, routeParentStatus, routeParentRef, listener, hosts)
 			case *gatewayapi_v1alpha2.TLSRoute:
-				p.computeTLSRouteForListener(route, routeParentStatus, listener, hosts)
+				p.computeTLSRouteForListener(route, routeParentStatus, routeParentRef, listener, hosts)
 			case *gatewayapi_v1.GRPCRoute:
-				p.computeGRPCRouteForListener(route, routeParentStatus, listener, hosts)
+				p.computeGRPCRouteForListener(route, routeParentStatus, routeParentRef, listener, hosts)
 			case *gatewayapi_v1alpha2.TCPRoute:
-				p.computeTCPRouteForListener(route, routeParentStatus, listener)
+				p.computeTCPRouteForListener(route, routeParentStatus, routeParentRef, listener)
 			}
 
 			hostCount += hosts.Len()
@@ -314,12 +421,16 @@ func (p *GatewayAPIProcessor) processRoute(
 	}
 }
 
+// getListenersForRouteParentRef returns the listeners selected by the parent
+// ref, by name, that allow the route. The listeners of the given ListenerSet
+// are selected, or those of the Gateway itself if listenerSet is nil.
 func (p *GatewayAPIProcessor) getListenersForRouteParentRef(
 	routeParentRef gatewayapi_v1.ParentReference,
+	listenerSet *gatewayapi_x_v1alpha1.XListenerSet,
 	routeNamespace string,
 	routeKind gatewayapi_v1.Kind,
 	listeners []*listenerInfo,
-	attachedRoutes map[string]int,
+	attachedRoutes map[*listenerInfo]int,
 	routeParentStatusAccessor *status.RouteParentStatusUpdate,
 ) map[string]*listenerInfo {
 	// Find the set of valid listeners that are relevant given this
@@ -328,6 +439,13 @@ func (p *GatewayAPIProcessor) getListenersForRouteParentRef(
 	// or none of them, if the listener(s) the ref targets are invalid).
 	var selectedListeners []*listenerInfo
 	for _, listener := range listeners {
+		// A parent ref to the Gateway only selects its own listeners,
+		// and a parent ref to a ListenerSet only selects the listeners
+		// of that ListenerSet.
+		if listener.listenerSet != listenerSet {
+			continue
+		}
+
 		// We've already verified the parent ref is for this Gateway,
 		// now check if it has a listener name and port specified.
 		// Both need to match the listener if specified.
@@ -357,11 +475,11 @@ func (p *GatewayAPIProcessor) getListenersForRouteParentRef(
 		}
 
 		// Check if the route is in a namespace that the listener allows.
-		if !p.namespaceMatches(selectedListener.listener.AllowedRoutes.Namespaces, selectedListener.namespaceSelector, routeNamespace) {
+		if !p.namespaceMatches(selectedListener.listener.AllowedRoutes.Namespaces, selectedListener.namespaceSelector, selectedListener.namespace(p.gateway), routeNamespace) {
 			continue
 		}
 
-		attachedRoutes[string(selectedListener.listener.Name)]++
+		attachedRoutes[selectedListener]++
 
 		if selectedListener.ready {
 			allowedListeners[string(selectedListener.listener.Name)] = selectedListener
@@ -397,7 +515,34 @@ type listenerInfo struct {
 	allowedKinds      []gatewayapi_v1.Kind
 	namespaceSelector labels.Selector
 	tlsSecret         *Secret
-	ready             bool
+	// tlsAdditionalSecrets holds the secrets of any
+	// further certificateRefs after the first.
+	tlsAdditionalSecrets []*Secret
+	ready                bool
+	// listenerSet is the ListenerSet the listener belongs
+	// to, or nil for a listener of the Gateway itself.
+	listenerSet *gatewayapi_x_v1alpha1.XListenerSet
+	// status records the status of the listener on
+	// its Gateway or ListenerSet.
+	status listenerStatusUpdate
+}
+
+// listenerStatusUpdate records the status of the listeners
+// of a Gateway or of a ListenerSet.
+type listenerStatusUpdate interface {
+	AddListenerCondition(listenerName string, cond gatewayapi_v1.ListenerConditionType, status meta_v1.ConditionStatus, reason gatewayapi_v1.ListenerConditionReason, message string) meta_v1.Condition
+	SetListenerSupportedKinds(listenerName string, kinds []gatewayapi_v1.Kind)
+	SetListenerAttachedRoutes(listenerName string, numRoutes int32)
+	ListenerConditions(listenerName string) []meta_v1.Condition
+}
+
+// namespace returns the namespace of the listener's
+// ListenerSet, or else of the given Gateway.
+func (l *listenerInfo) namespace(gateway *gatewayapi_v1.Gateway) string {
+	if l.listenerSet != nil {
+		return l.listenerSet.Namespace
+	}
+	return gateway.Namespace
 }
 
 func (l *listenerInfo) AllowsKind(kind gatewayapi_v1.Kind) bool {
@@ -412,7 +557,7 @@ func (l *listenerInfo) AllowsKind(kind gatewayapi_v1.Kind) bool {
 
 // isAddressAssigned returns true if either there are no addresses requested in specAddresses,
 // or if at least one address from specAddresses appears in statusAddresses.
-func isAddressAssigned(specAddresses []gatewayapi_v1.GatewayAddress, statusAddresses []gatewayapi_v1.GatewayStatusAddress) bool {
+func isAddressAssigned(specAddresses []gatewayapi_v1.GatewaySpecAddress, statusAddresses []gatewayapi_v1.GatewayStatusAddress) bool {
 	if len(specAddresses) == 0 {
 		return true
 	}
@@ -439,20 +584,25 @@ func isAddressAssigned(specAddresses []gatewayapi_v1.GatewayAddress, statusAddre
 
 // computeListener processes a Listener's spec, including TLS details,
 // allowed routes, etc., and sets the appropriate conditions on it in
-// the Gateway's .status.listeners. It returns a listenerInfo struct with
+// the .status.listeners of its Gateway, or of its ListenerSet if
+// listenerSet is not nil. It returns a listenerInfo struct with
 // the allowed route kinds and TLS secret (if any).
 func (p *GatewayAPIProcessor) computeListener(
 	listener gatewayapi_v1.Listener,
-	gwAccessor *status.GatewayStatusUpdate,
-	validateListenersResult gatewayapi.ValidateListenersResult,
+	listenerSet *gatewayapi_x_v1alpha1.XListenerSet,
+	listenerStatus listenerStatusUpdate,
+	listenerNames map[string]string,
+	invalidListenerConditions map[gatewayapi_v1.SectionName]meta_v1.Condition,
 ) *listenerInfo {
 	info := &listenerInfo{
 		listener:        listener,
-		dagListenerName: validateListenersResult.ListenerNames[string(listener.Name)],
+		dagListenerName: p.source.gatewayListenerName(p.gateway, listenerNames[string(listener.Name)]),
+		listenerSet:     listenerSet,
+		status:          listenerStatus,
 	}
 
 	addInvalidListenerCondition := func(msg string) {
-		gwAccessor.AddListenerCondition(
+		listenerStatus.AddListenerCondition(
 			string(listener.Name),
 			gatewayapi_v1.ListenerConditionProgrammed,
 			meta_v1.ConditionFalse,
@@ -461,27 +611,58 @@ func (p *GatewayAPIProcessor) computeListener(
 		)
 	}
 
+	// Report an expired or soon to expire TLS certificate after the
+	// required Listener conditions have been set below, since it does
+	// not affect whether the Listener is programmed.
+	defer func() {
+		if info.tlsSecret == nil {
+			return
+		}
+
+		var reason, msg string
+		for _, secret := range append([]*Secret{info.tlsSecret}, info.tlsAdditionalSecrets...) {
+			if reason, msg = p.source.certificateExpiryWarning(secret); reason != "" {
+				break
+			}
+		}
+		if reason == "" {
+			return
+		}
+
+		condStatus := meta_v1.ConditionTrue
+		if reason == certificateExpiredReason {
+			condStatus = meta_v1.ConditionFalse
+		}
+		listenerStatus.AddListenerCondition(
+			string(listener.Name),
+			status.ListenerConditionCertificateValid,
+			condStatus,
+			gatewayapi_v1.ListenerConditionReason(reason),
+			fmt.Sprintf("Listener.TLS.CertificateRefs %s", msg),
+		)
+	}()
+
 	// Set required Listener conditions (Programmed, Accepted, ResolvedRefs)
 	// if they haven't already been set.
 	defer func() {
-		listenerStatus := gwAccessor.ListenerStatus[string(listener.Name)]
+		listenerConditions := listenerStatus.ListenerConditions(string(listener.Name))
 
-		if listenerStatus == nil || len(listenerStatus.Conditions) == 0 {
-			gwAccessor.AddListenerCondition(
+		if len(listenerConditions) == 0 {
+			listenerStatus.AddListenerCondition(
 				string(listener.Name),
 				gatewayapi_v1.ListenerConditionProgrammed,
 				meta_v1.ConditionTrue,
 				gatewayapi_v1.ListenerReasonProgrammed,
 				"Valid listener",
 			)
-			gwAccessor.AddListenerCondition(
+			listenerStatus.AddListenerCondition(
 				string(listener.Name),
 				gatewayapi_v1.ListenerConditionAccepted,
 				meta_v1.ConditionTrue,
 				gatewayapi_v1.ListenerReasonAccepted,
 				"Listener accepted",
 			)
-			gwAccessor.AddListenerCondition(
+			listenerStatus.AddListenerCondition(
 				string(listener.Name),
 				gatewayapi_v1.ListenerConditionResolvedRefs,
 				meta_v1.ConditionTrue,
@@ -493,7 +674,7 @@ func (p *GatewayAPIProcessor) computeListener(
 			acceptedConditionExists := false
 			resolvedRefsConditionExists := false
 
-			for _, cond := range listenerStatus.Conditions {
+			for _, cond := range listenerConditions {
 				if cond.Type == string(gatewayapi_v1.ListenerConditionProgrammed) {
 					programmedConditionExists = true
 				}
@@ -515,7 +696,7 @@ func (p *GatewayAPIProcessor) computeListener(
 			// Set Accepted condition to true if not
 			// explicitly set otherwise.
 			if !acceptedConditionExists {
-				gwAccessor.AddListenerCondition(
+				listenerStatus.AddListenerCondition(
 					string(listener.Name),
 					gatewayapi_v1.ListenerConditionAccepted,
 					meta_v1.ConditionTrue,
@@ -526,7 +707,7 @@ func (p *GatewayAPIProcessor) computeListener(
 			// Set ResolvedRefs condition to true if not
 			// explicitly set otherwise.
 			if !resolvedRefsConditionExists {
-				gwAccessor.AddListenerCondition(
+				listenerStatus.AddListenerCondition(
 					string(listener.Name),
 					gatewayapi_v1.ListenerConditionResolvedRefs,
 					meta_v1.ConditionTrue,
@@ -538,8 +719,8 @@ func (p *GatewayAPIProcessor) computeListener(
 	}()
 
 	// Get a list of the route kinds that the listener accepts.
-	info.allowedKinds = p.getListenerRouteKinds(listener, gwAccessor)
-	gwAccessor.SetListenerSupportedKinds(string(listener.Name), info.allowedKinds)
+	info.allowedKinds = p.getListenerRouteKinds(listener, listenerStatus)
+	listenerStatus.SetListenerSupportedKinds(string(listener.Name), info.allowedKinds)
 
 	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil &&
 		listener.AllowedRoutes.Namespaces.From != nil && *listener.AllowedRoutes.Namespaces.From == gatewayapi_v1.NamespacesFromSelector {
@@ -564,11 +745,11 @@ func (p *GatewayAPIProcessor) computeListener(
 
 	// If the listener had an invalid protocol/port/hostname, we reach here just for pick the information to compute the AttachedRoutes later,
 	// we don't need to go any further.
-	if _, invalid := validateListenersResult.InvalidListenerConditions[listener.Name]; invalid {
+	if _, invalid := invalidListenerConditions[listener.Name]; invalid {
 		return info
 	}
 
-	var listenerSecret *Secret
+	var listenerSecrets []*Secret
 
 	// Validate TLS details for HTTPS/TLS protocol listeners.
 	switch listener.Protocol {
@@ -588,7 +769,7 @@ func (p *GatewayAPIProcessor) computeListener(
 		}
 
 		// Resolve the TLS secret.
-		if listenerSecret = p.resolveListenerSecret(listener.TLS.CertificateRefs, string(listener.Name), gwAccessor); listenerSecret == nil {
+		if listenerSecrets = p.resolveListenerSecrets(listener.TLS.CertificateRefs, string(listener.Name), listenerSet, listenerStatus); listenerSecrets == nil {
 			// If TLS was configured on the Listener, but the secret ref is invalid, don't allow any
 			// routes to be bound to this listener since it can't serve TLS traffic.
 			return info
@@ -605,7 +786,7 @@ func (p *GatewayAPIProcessor) computeListener(
 		switch {
 		case listener.TLS.Mode == nil || *listener.TLS.Mode == gatewayapi_v1.TLSModeTerminate:
 			// Resolve the TLS secret.
-			if listenerSecret = p.resolveListenerSecret(listener.TLS.CertificateRefs, string(listener.Name), gwAccessor); listenerSecret == nil {
+			if listenerSecrets = p.resolveListenerSecrets(listener.TLS.CertificateRefs, string(listener.Name), listenerSet, listenerStatus); listenerSecrets == nil {
 				// If TLS was configured on the Listener, but the secret ref is invalid, don't allow any
 				// routes to be bound to this listener since it can't serve TLS traffic.
 				return info
@@ -621,14 +802,19 @@ func (p *GatewayAPIProcessor) computeListener(
 		}
 	}
 
-	info.tlsSecret = listenerSecret
+	if len(listenerSecrets) > 0 {
+		info.tlsSecret = listenerSecrets[0]
+	}
+	if len(listenerSecrets) > 1 {
+		info.tlsAdditionalSecrets = listenerSecrets[1:]
+	}
 	info.ready = true
 	return info
 }
 
 // getListenerRouteKinds gets a list of the valid route kinds that
 // the listener accepts.
-func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1.Listener, gwAccessor *status.GatewayStatusUpdate) []gatewayapi_v1.Kind {
+func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1.Listener, listenerStatus listenerStatusUpdate) []gatewayapi_v1.Kind {
 	// None specified on the listener: return the default based on
 	// the listener's protocol.
 	if len(listener.AllowedRoutes.Kinds) == 0 {
@@ -648,7 +834,7 @@ func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1.Liste
 
 	for _, routeKind := range listener.AllowedRoutes.Kinds {
 		if routeKind.Group != nil && *routeKind.Group != gatewayapi_v1.GroupName {
-			gwAccessor.AddListenerCondition(
+			listenerStatus.AddListenerCondition(
 				string(listener.Name),
 				gatewayapi_v1.ListenerConditionResolvedRefs,
 				meta_v1.ConditionFalse,
@@ -658,7 +844,7 @@ func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1.Liste
 			continue
 		}
 		if routeKind.Kind != KindHTTPRoute && routeKind.Kind != KindTLSRoute && routeKind.Kind != KindGRPCRoute && routeKind.Kind != KindTCPRoute {
-			gwAccessor.AddListenerCondition(
+			listenerStatus.AddListenerCondition(
 				string(listener.Name),
 				gatewayapi_v1.ListenerConditionResolvedRefs,
 				meta_v1.ConditionFalse,
@@ -668,7 +854,7 @@ func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1.Liste
 			continue
 		}
 		if routeKind.Kind == KindTLSRoute && listener.Protocol != gatewayapi_v1.TLSProtocolType {
-			gwAccessor.AddListenerCondition(
+			listenerStatus.AddListenerCondition(
 				string(listener.Name),
 				gatewayapi_v1.ListenerConditionResolvedRefs,
 				meta_v1.ConditionFalse,
@@ -678,7 +864,7 @@ func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1.Liste
 			continue
 		}
 		if routeKind.Kind == KindTCPRoute && listener.Protocol != gatewayapi_v1.TCPProtocolType && listener.Protocol != gatewayapi_v1.TLSProtocolType {
-			gwAccessor.AddListenerCondition(
+			listenerStatus.AddListenerCondition(
 				string(listener.Name),
 				gatewayapi_v1.ListenerConditionResolvedRefs,
 				meta_v1.ConditionFalse,
@@ -694,29 +880,60 @@ func (p *GatewayAPIProcessor) getListenerRouteKinds(listener gatewayapi_v1.Liste
 	return routeKinds
 }
 
-// resolveListenerSecret validates and resolves a Listener TLS secret
-// from a given list of certificateRefs. There must be exactly one
-// certificate ref, to a core_v1.Secret, that exists, is allowed to be referenced
-// based on namespace and ReferenceGrants, and is a valid TLS secret.
-// Conditions are set if any of these requirements are not met.
-func (p *GatewayAPIProcessor) resolveListenerSecret(certificateRefs []gatewayapi_v1.SecretObjectReference, listenerName string, gwAccessor *status.GatewayStatusUpdate) *Secret {
-	if len(certificateRefs) != 1 {
-		gwAccessor.AddListenerCondition(
+// resolveListenerSecrets validates and resolves the Listener TLS secrets
+// from a given list of certificateRefs. There must be at least one
+// certificate ref, and each must be resolvable by resolveListenerSecret.
+// Envoy serves all of the certificates, presenting the one best suited
+// to each client. Conditions are set if any of these requirements are
+// not met, in which case nil is returned.
+func (p *GatewayAPIProcessor) resolveListenerSecrets(certificateRefs []gatewayapi_v1.SecretObjectReference, listenerName string, listenerSet *gatewayapi_x_v1alpha1.XListenerSet, listenerStatus listenerStatusUpdate) []*Secret {
+	if len(certificateRefs) == 0 {
+		listenerStatus.AddListenerCondition(
 			listenerName,
 			gatewayapi_v1.ListenerConditionProgrammed,
 			meta_v1.ConditionFalse,
 			gatewayapi_v1.ListenerReasonInvalid,
-			"Listener.TLS.CertificateRefs must contain exactly one entry",
+			"Listener.TLS.CertificateRefs must contain at least one entry",
 		)
 		return nil
 	}
 
-	certificateRef := certificateRefs[0]
+	secrets := make([]*Secret, 0, len(certificateRefs))
+	for _, certificateRef := range certificateRefs {
+		secret := p.resolveListenerSecret(certificateRef, listenerName, listenerSet, listenerStatus)
+		if secret == nil {
+			return nil
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets
+}
+
+// resolveListenerSecret validates and resolves a Listener TLS secret
+// from a certificate ref. The ref must be to a core_v1.Secret, that exists,
+// is allowed to be referenced based on namespace and ReferenceGrants, and
+// is a valid TLS secret. The ref is relative to the namespace of the
+// listener's ListenerSet, if not nil, or else of the Gateway. Conditions
+// are set if any of these requirements are not met.
+func (p *GatewayAPIProcessor) resolveListenerSecret(certificateRef gatewayapi_v1.SecretObjectReference, listenerName string, listenerSet *gatewayapi_x_v1alpha1.XListenerSet, listenerStatus listenerStatusUpdate) *Secret {
+	from := crossNamespaceFrom{
+		group:     gatewayapi_v1.GroupName,
+		kind:      KindGateway,
+		namespace: p.gateway.Namespace,
+	}
+	if listenerSet != nil {
+		from = crossNamespaceFrom{
+			group:     gatewayapi_x_v1alpha1.GroupName,
+			kind:      gatewayapi.KindListenerSet,
+			namespace: listenerSet.Namespace,
+		}
+	}
 
 	// Validate a core_v1.Secret is referenced which can be kind: secret & group: core.
 	// ref: https://github.com/kubernetes-sigs/gateway-api/pull/562
 	if !isSecretRef(certificateRef) {
-		gwAccessor.AddListenerCondition(
+		listenerStatus.AddListenerCondition(
 			listenerName,
 			gatewayapi_v1.ListenerConditionResolvedRefs,
 			meta_v1.ConditionFalse,
@@ -726,15 +943,12 @@ func (p *GatewayAPIProcessor) resolveListenerSecret(certificateRefs []gatewayapi
 		return nil
 	}
 
-	// If the secret is in a different namespace than the gateway, then we need to
-	// check for a ReferenceGrant that allows the reference.
-	if certificateRef.Namespace != nil && string(*certificateRef.Namespace) != p.source.gateway.Namespace {
+	// If the secret is in a different namespace than the gateway, or than
+	// the ListenerSet, then we need to check for a ReferenceGrant that
+	// allows the reference.
+	if certificateRef.Namespace != nil && string(*certificateRef.Namespace) != from.namespace {
 		if !p.validCrossNamespaceRef(
-			crossNamespaceFrom{
-				group:     gatewayapi_v1.GroupName,
-				kind:      KindGateway,
-				namespace: p.source.gateway.Namespace,
-			},
+			from,
 			crossNamespaceTo{
 				group:     "",
 				kind:      "Secret",
@@ -742,12 +956,12 @@ func (p *GatewayAPIProcessor) resolveListenerSecret(certificateRefs []gatewayapi
 				name:      string(certificateRef.Name),
 			},
 		) {
-			gwAccessor.AddListenerCondition(
+			listenerStatus.AddListenerCondition(
 				listenerName,
 				gatewayapi_v1.ListenerConditionResolvedRefs,
 				meta_v1.ConditionFalse,
 				gatewayapi_v1.ListenerReasonRefNotPermitted,
-				fmt.Sprintf("Spec.VirtualHost.TLS.CertificateRefs %q namespace must match the Gateway's namespace or be covered by a ReferenceGrant", certificateRef.Name),
+				fmt.Sprintf("Spec.VirtualHost.TLS.CertificateRefs %q namespace must match the %s's namespace or be covered by a ReferenceGrant", certificateRef.Name, from.kind),
 			)
 			return nil
 		}
@@ -757,14 +971,14 @@ func (p *GatewayAPIProcessor) resolveListenerSecret(certificateRefs []gatewayapi
 	if certificateRef.Namespace != nil {
 		meta = types.NamespacedName{Name: string(certificateRef.Name), Namespace: string(*certificateRef.Namespace)}
 	} else {
-		meta = types.NamespacedName{Name: string(certificateRef.Name), Namespace: p.source.gateway.Namespace}
+		meta = types.NamespacedName{Name: string(certificateRef.Name), Namespace: from.namespace}
 	}
 
-	// Use LookupTLSSecretInsecure instead of LookupTLSSecret since Gateway API uses its own mechanism (ReferenceGrant, not TLSCertificateDelegation)
+	// Use lookupServingSecret instead of LookupTLSSecret since Gateway API uses its own mechanism (ReferenceGrant, not TLSCertificateDelegation)
 	// to control access to secrets across namespaces.
-	listenerSecret, err := p.source.LookupTLSSecretInsecure(meta)
+	listenerSecret, err := p.source.lookupServingSecret(meta)
 	if err != nil {
-		gwAccessor.AddListenerCondition(
+		listenerStatus.AddListenerCondition(
 			listenerName,
 			gatewayapi_v1.ListenerConditionResolvedRefs,
 			meta_v1.ConditionFalse,
@@ -935,13 +1149,14 @@ func hostnameMatchesWildcardHostname(hostname, wildcardHostname string) bool {
 
 // namespaceMatches returns true if namespaces allows
 // the provided route namespace.
-func (p *GatewayAPIProcessor) namespaceMatches(namespaces *gatewayapi_v1.RouteNamespaces, namespaceSelector labels.Selector, routeNamespace string) bool {
+func (p *GatewayAPIProcessor) namespaceMatches(namespaces *gatewayapi_v1.RouteNamespaces, namespaceSelector labels.Selector, listenerNamespace, routeNamespace string) bool {
 	// From indicates where Routes will be selected for this Gateway.
 	// Possible values are:
 	//   * All: Routes in all namespaces may be used by this Gateway.
 	//   * Selector: Routes in namespaces selected by the selector may be used by
 	//     this Gateway.
 	//   * Same: Only Routes in the same namespace may be used by this Gateway.
+	//     For a listener of a ListenerSet, this is the ListenerSet's namespace.
 
 	if namespaces == nil || namespaces.From == nil {
 		return true
@@ -951,7 +1166,7 @@ func (p *GatewayAPIProcessor) namespaceMatches(namespaces *gatewayapi_v1.RouteNa
 	case gatewayapi_v1.NamespacesFromAll:
 		return true
 	case gatewayapi_v1.NamespacesFromSame:
-		return p.source.gateway.Namespace == routeNamespace
+		return listenerNamespace == routeNamespace
 	case gatewayapi_v1.NamespacesFromSelector:
 		// Look up the route's namespace in the list of cached namespaces.
 		if ns := p.source.namespaces[routeNamespace]; ns != nil {
@@ -1012,7 +1227,37 @@ func (p *GatewayAPIProcessor) computeGatewayConditions(gwAccessor *status.Gatewa
 	}
 }
 
-func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alpha2.TLSRoute, routeAccessor *status.RouteParentStatusUpdate, listener *listenerInfo, hosts sets.Set[string]) bool {
+// computeListenerSetConditions sets the Accepted and Programmed conditions
+// of a ListenerSet allowed by the Gateway, once its listeners are computed.
+func computeListenerSetConditions(lsAccessor *status.ListenerSetStatusUpdate, gatewayNotProgrammedCondition *meta_v1.Condition) {
+	// Check for any listeners with a Programmed: false condition.
+	programmedListeners := 0
+	for _, ls := range lsAccessor.ListenerStatus {
+		for _, cond := range ls.Conditions {
+			if cond.Type == string(gatewayapi_v1.ListenerConditionProgrammed) && cond.Status == meta_v1.ConditionTrue {
+				programmedListeners++
+				break
+			}
+		}
+	}
+
+	if programmedListeners == 0 {
+		lsAccessor.AddCondition(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted, meta_v1.ConditionFalse, gatewayapi_x_v1alpha1.ListenerSetReasonListenersNotValid, "None of the listeners are valid")
+	} else {
+		lsAccessor.AddCondition(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted, meta_v1.ConditionTrue, gatewayapi_x_v1alpha1.ListenerSetReasonAccepted, "ListenerSet is accepted")
+	}
+
+	switch {
+	case gatewayNotProgrammedCondition != nil:
+		lsAccessor.AddCondition(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed, meta_v1.ConditionFalse, status.ListenerSetReasonParentNotProgrammed, "Parent Gateway is not programmed")
+	case programmedListeners < len(lsAccessor.ListenerStatus):
+		lsAccessor.AddCondition(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed, meta_v1.ConditionFalse, gatewayapi_x_v1alpha1.ListenerSetReasonListenersNotValid, "Listeners are not valid")
+	default:
+		lsAccessor.AddCondition(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed, meta_v1.ConditionTrue, gatewayapi_x_v1alpha1.ListenerSetReasonProgrammed, status.MessageValidListenerSet)
+	}
+}
+
+func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alpha2.TLSRoute, routeAccessor *status.RouteParentStatusUpdate, routeParentRef gatewayapi_v1.ParentReference, listener *listenerInfo, hosts sets.Set[string]) bool {
 	var programmed bool
 	for _, rule := range route.Spec.Rules {
 		if len(rule.BackendRefs) == 0 {
@@ -1023,6 +1268,12 @@ func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alp
 		var proxy TCPProxy
 		var totalWeight uint32
 
+		if p.SetSourceMetadataOnRoutes {
+			proxy.SourceKind = KindTLSRoute
+			proxy.SourceNamespace = route.Namespace
+			proxy.SourceName = route.Name
+		}
+
 		for _, backendRef := range rule.BackendRefs {
 
 			service, cond := p.validateBackendRef(backendRef, KindTLSRoute, route.Namespace)
@@ -1041,15 +1292,30 @@ func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alp
 			// used later to understand if all the weights are set to zero.
 			totalWeight += routeWeight
 
+			// A BackendTLSPolicy can only originate TLS to the backend
+			// when the listener terminates the client's TLS session.
+			var protocol string
+			var upstreamValidation *PeerValidationContext
+			var upstreamTLS *UpstreamTLS
+			if listener.tlsSecret != nil {
+				upstreamValidation, upstreamTLS = p.computeBackendTLSPolicies(route.Namespace, backendRef, service, routeParentRef)
+				if upstreamValidation != nil {
+					protocol = "tls"
+				}
+			}
+
 			// https://github.com/projectcontour/contour/issues/3593
 			service.Weighted.Weight = routeWeight
 			proxy.Clusters = append(proxy.Clusters, &Cluster{
 				Upstream:                      service,
 				SNI:                           service.ExternalName,
 				Weight:                        routeWeight,
+				Protocol:                      protocol,
 				TimeoutPolicy:                 ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
 				MaxRequestsPerConnection:      p.MaxRequestsPerConnection,
 				PerConnectionBufferLimitBytes: p.PerConnectionBufferLimitBytes,
+				UpstreamValidation:            upstreamValidation,
+				UpstreamTLS:                   upstreamTLS,
 			})
 		}
 
@@ -1071,6 +1337,7 @@ func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alp
 
 			if listener.tlsSecret != nil {
 				secure.Secret = listener.tlsSecret
+				secure.AdditionalSecrets = listener.tlsAdditionalSecrets
 			}
 
 			secure.TCPProxy = &proxy
@@ -1461,13 +1728,14 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(
 			)
 		} else {
 			// Get clusters from rule backendRefs
-			clusters, totalWeight, ok := p.httpClusters(route.Namespace, rule.BackendRefs, routeAccessor, routeParentRef)
+			clusters, requestHashPolicies, totalWeight, ok := p.httpClusters(route.Namespace, rule.BackendRefs, routeAccessor, routeParentRef)
 			if !ok {
 				continue
 			}
 			routes = p.clusterRoutes(
 				matchconditions,
 				clusters,
+				requestHashPolicies,
 				totalWeight,
 				priority,
 				KindHTTPRoute,
@@ -1490,6 +1758,7 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(
 					case listener.tlsSecret != nil:
 						svhost := p.dag.EnsureSecureVirtualHost(listener.dagListenerName, host)
 						svhost.Secret = listener.tlsSecret
+						svhost.AdditionalSecrets = listener.tlsAdditionalSecrets
 						svhost.AddRoute(route)
 					default:
 						vhost := p.dag.EnsureVirtualHost(listener.dagListenerName, host)
@@ -1533,7 +1802,7 @@ func (p *GatewayAPIProcessor) hasConflictRoute(listener *listenerInfo, hosts set
 	return false
 }
 
-func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1.GRPCRoute, routeAccessor *status.RouteParentStatusUpdate, listener *listenerInfo, hosts sets.Set[string]) bool {
+func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1.GRPCRoute, routeAccessor *status.RouteParentStatusUpdate, routeParentRef gatewayapi_v1.ParentReference, listener *listenerInfo, hosts sets.Set[string]) bool {
 	var programmed bool
 	invalidRuleCnt := 0
 	for ruleIndex, rule := range route.Spec.Rules {
@@ -1639,13 +1908,14 @@ func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1.G
 		// conditions, all with the same action.
 		var routes []*Route
 
-		clusters, totalWeight, ok := p.grpcClusters(route.Namespace, rule.BackendRefs, routeAccessor, listener.listener.Protocol)
+		clusters, requestHashPolicies, totalWeight, ok := p.grpcClusters(route.Namespace, rule.BackendRefs, routeAccessor, routeParentRef, listener.listener.Protocol)
 		if !ok {
 			continue
 		}
 		routes = p.clusterRoutes(
 			matchconditions,
 			clusters,
+			requestHashPolicies,
 			totalWeight,
 			priority,
 			KindGRPCRoute,
@@ -1668,6 +1938,7 @@ func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1.G
 					case listener.tlsSecret != nil:
 						svhost := p.dag.EnsureSecureVirtualHost(listener.dagListenerName, host)
 						svhost.Secret = listener.tlsSecret
+						svhost.AdditionalSecrets = listener.tlsAdditionalSecrets
 						svhost.AddRoute(route)
 					default:
 						vhost := p.dag.EnsureVirtualHost(listener.dagListenerName, host)
@@ -1757,7 +2028,7 @@ func gatewayGRPCHeaderMatchConditions(matches []gatewayapi_v1.GRPCHeaderMatch) (
 	return headerMatchConditions, nil
 }
 
-func (p *GatewayAPIProcessor) computeTCPRouteForListener(route *gatewayapi_v1alpha2.TCPRoute, routeAccessor *status.RouteParentStatusUpdate, listener *listenerInfo) bool {
+func (p *GatewayAPIProcessor) computeTCPRouteForListener(route *gatewayapi_v1alpha2.TCPRoute, routeAccessor *status.RouteParentStatusUpdate, routeParentRef gatewayapi_v1.ParentReference, listener *listenerInfo) bool {
 	if len(route.Spec.Rules) != 1 {
 		routeAccessor.AddCondition(
 			gatewayapi_v1.RouteConditionAccepted,
@@ -1784,6 +2055,12 @@ func (p *GatewayAPIProcessor) computeTCPRouteForListener(route *gatewayapi_v1alp
 	var proxy TCPProxy
 	var totalWeight uint32
 
+	if p.SetSourceMetadataOnRoutes {
+		proxy.SourceKind = KindTCPRoute
+		proxy.SourceNamespace = route.Namespace
+		proxy.SourceName = route.Name
+	}
+
 	for _, backendRef := range rule.BackendRefs {
 		service, cond := p.validateBackendRef(backendRef, KindTCPRoute, route.Namespace)
 		if cond != nil {
@@ -1806,15 +2083,24 @@ func (p *GatewayAPIProcessor) computeTCPRouteForListener(route *gatewayapi_v1alp
 		// used later to understand if all the weights are set to zero.
 		totalWeight += routeWeight
 
+		var protocol string
+		upstreamValidation, upstreamTLS := p.computeBackendTLSPolicies(route.Namespace, backendRef, service, routeParentRef)
+		if upstreamValidation != nil {
+			protocol = "tls"
+		}
+
 		// https://github.com/projectcontour/contour/issues/3593
 		service.Weighted.Weight = routeWeight
 		proxy.Clusters = append(proxy.Clusters, &Cluster{
 			Upstream:                      service,
 			SNI:                           service.ExternalName,
 			Weight:                        routeWeight,
+			Protocol:                      protocol,
 			TimeoutPolicy:                 ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
 			MaxRequestsPerConnection:      p.MaxRequestsPerConnection,
 			PerConnectionBufferLimitBytes: p.PerConnectionBufferLimitBytes,
+			UpstreamValidation:            upstreamValidation,
+			UpstreamTLS:                   upstreamTLS,
 		})
 	}
 
@@ -1839,6 +2125,7 @@ func (p *GatewayAPIProcessor) computeTCPRouteForListener(route *gatewayapi_v1alp
 	if listener.tlsSecret != nil {
 		secure := p.dag.EnsureSecureVirtualHost(listener.dagListenerName, "*")
 		secure.Secret = listener.tlsSecret
+		secure.AdditionalSecrets = listener.tlsAdditionalSecrets
 		secure.TCPProxy = &proxy
 	} else {
 		p.dag.Listeners[listener.dagListenerName].TCPProxy = &proxy
@@ -1872,12 +2159,18 @@ func (p *GatewayAPIProcessor) validateBackendObjectRef(
 	routeKind string,
 	routeNamespace string,
 ) (*Service, *meta_v1.Condition) {
-	if !(backendObjectRef.Group == nil || *backendObjectRef.Group == "") {
-		return nil, ptr.To(resolvedRefsFalse(gatewayapi_v1.RouteReasonInvalidKind, fmt.Sprintf("%s.Group must be \"\"", field)))
-	}
+	// Multi-cluster ServiceImports are supported in addition to Services.
+	isServiceImport := ptr.Deref(backendObjectRef.Group, "") == mcs_v1alpha1.GroupName &&
+		ptr.Deref(backendObjectRef.Kind, "") == KindServiceImport
+
+	if !isServiceImport {
+		if !(backendObjectRef.Group == nil || *backendObjectRef.Group == "") {
+			return nil, ptr.To(resolvedRefsFalse(gatewayapi_v1.RouteReasonInvalidKind, fmt.Sprintf("%s.Group must be \"\"", field)))
+		}
 
-	if !(backendObjectRef.Kind != nil && *backendObjectRef.Kind == "Service") {
-		return nil, ptr.To(resolvedRefsFalse(gatewayapi_v1.RouteReasonInvalidKind, fmt.Sprintf("%s.Kind must be 'Service'", field)))
+		if !(backendObjectRef.Kind != nil && *backendObjectRef.Kind == "Service") {
+			return nil, ptr.To(resolvedRefsFalse(gatewayapi_v1.RouteReasonInvalidKind, fmt.Sprintf("%s.Kind must be 'Service'", field)))
+		}
 	}
 
 	if backendObjectRef.Name == "" {
@@ -1898,8 +2191,8 @@ func (p *GatewayAPIProcessor) validateBackendObjectRef(
 				namespace: routeNamespace,
 			},
 			crossNamespaceTo{
-				group:     "",
-				kind:      "Service",
+				group:     string(ptr.Deref(backendObjectRef.Group, "")),
+				kind:      string(*backendObjectRef.Kind),
 				namespace: string(*backendObjectRef.Namespace),
 				name:      string(backendObjectRef.Name),
 			},
@@ -1915,9 +2208,18 @@ func (p *GatewayAPIProcessor) validateBackendObjectRef(
 		meta = types.NamespacedName{Name: string(backendObjectRef.Name), Namespace: routeNamespace}
 	}
 
-	service, err := p.dag.EnsureService(meta, int(*backendObjectRef.Port), int(*backendObjectRef.Port), p.source, p.EnableExternalNameService)
-	if err != nil {
-		return nil, ptr.To(resolvedRefsFalse(gatewayapi_v1.RouteReasonBackendNotFound, fmt.Sprintf("service %q is invalid: %s", meta.Name, err)))
+	var service *Service
+	var err error
+	if isServiceImport {
+		service, err = p.dag.EnsureServiceImport(meta, int(*backendObjectRef.Port), int(*backendObjectRef.Port), p.source)
+		if err != nil {
+			return nil, ptr.To(resolvedRefsFalse(gatewayapi_v1.RouteReasonBackendNotFound, fmt.Sprintf("serviceimport %q is invalid: %s", meta.Name, err)))
+		}
+	} else {
+		service, err = p.dag.EnsureService(meta, int(*backendObjectRef.Port), int(*backendObjectRef.Port), p.source, p.EnableExternalNameService)
+		if err != nil {
+			return nil, ptr.To(resolvedRefsFalse(gatewayapi_v1.RouteReasonBackendNotFound, fmt.Sprintf("service %q is invalid: %s", meta.Name, err)))
+		}
 	}
 
 	service = serviceCircuitBreakerPolicy(service, p.GlobalCircuitBreakerDefaults)
@@ -2066,16 +2368,18 @@ func gatewayQueryParamMatchConditions(matches []gatewayapi_v1.HTTPQueryParamMatc
 	return dagMatchConditions, nil
 }
 
-// httpClusters builds clusters from backendRef.
-func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []gatewayapi_v1.HTTPBackendRef, routeAccessor *status.RouteParentStatusUpdate, routeParentRef gatewayapi_v1.ParentReference) ([]*Cluster, uint32, bool) {
+// httpClusters builds clusters from backendRef, along with the request hash
+// policies of the first backend with session persistence.
+func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []gatewayapi_v1.HTTPBackendRef, routeAccessor *status.RouteParentStatusUpdate, routeParentRef gatewayapi_v1.ParentReference) ([]*Cluster, []RequestHashPolicy, uint32, bool) {
 	totalWeight := uint32(0)
 
 	if len(backendRefs) == 0 {
 		routeAccessor.AddCondition(gatewayapi_v1.RouteConditionResolvedRefs, meta_v1.ConditionFalse, status.ReasonDegraded, "At least one Spec.Rules.BackendRef must be specified.")
-		return nil, totalWeight, false
+		return nil, nil, totalWeight, false
 	}
 
 	var clusters []*Cluster
+	var requestHashPolicies []RequestHashPolicy
 
 	// Validate the backend refs.
 	for _, backendRef := range backendRefs {
@@ -2085,11 +2389,16 @@ func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []
 			continue
 		}
 
-		upstreamValidation, upstreamTLS := p.computeBackendTLSPolicies(routeNamespace, backendRef, service, routeParentRef)
+		upstreamValidation, upstreamTLS := p.computeBackendTLSPolicies(routeNamespace, backendRef.BackendRef, service, routeParentRef)
 		if upstreamValidation != nil {
 			service.Protocol = "tls"
 		}
 
+		loadBalancerPolicy, hashPolicies := p.computeBackendTrafficPolicy(routeNamespace, backendRef.BackendRef, routeParentRef)
+		if requestHashPolicies == nil {
+			requestHashPolicies = hashPolicies
+		}
+
 		var clusterRequestHeaderPolicy *HeadersPolicy
 		var clusterResponseHeaderPolicy *HeadersPolicy
 
@@ -2153,9 +2462,10 @@ func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []
 			PerConnectionBufferLimitBytes: p.PerConnectionBufferLimitBytes,
 			UpstreamValidation:            upstreamValidation,
 			UpstreamTLS:                   upstreamTLS,
+			LoadBalancerPolicy:            loadBalancerPolicy,
 		})
 	}
-	return clusters, totalWeight, true
+	return clusters, requestHashPolicies, totalWeight, true
 }
 
 // computeBackendTLSPolicies returns the upstreamValidation and upstreamTLS
@@ -2164,7 +2474,7 @@ func (p *GatewayAPIProcessor) httpClusters(routeNamespace string, backendRefs []
 //
 // If no BackendTLSPolicy is found or the BackendTLSPolicy is invalid then nil
 // is returned for both fields.
-func (p *GatewayAPIProcessor) computeBackendTLSPolicies(routeNamespace string, backendRef gatewayapi_v1.HTTPBackendRef, service *Service, routeParentRef gatewayapi_v1.ParentReference) (*PeerValidationContext, *UpstreamTLS) {
+func (p *GatewayAPIProcessor) computeBackendTLSPolicies(routeNamespace string, backendRef gatewayapi_v1.BackendRef, service *Service, routeParentRef gatewayapi_v1.ParentReference) (*PeerValidationContext, *UpstreamTLS) {
 	var upstreamValidation *PeerValidationContext
 	var upstreamTLS *UpstreamTLS
 
@@ -2198,11 +2508,12 @@ func (p *GatewayAPIProcessor) computeBackendTLSPolicies(routeNamespace string, b
 	backendTLSPolicy, found := p.source.LookupBackendTLSPolicyByTargetRef(policyTargetRef, string(*backendNamespace))
 	if found {
 		backendTLSPolicyAccessor, commit := p.dag.StatusCache.BackendTLSPolicyConditionsAccessor(
+			k8s.NamespacedNameOf(p.gateway),
 			k8s.NamespacedNameOf(backendTLSPolicy),
 			backendTLSPolicy.GetGeneration(),
 		)
 		defer commit()
-		backendTLSPolicyAncestorStatus := backendTLSPolicyAccessor.StatusUpdateFor(routeParentRef)
+		backendTLSPolicyAncestorStatus := backendTLSPolicyAccessor.StatusUpdateFor(p.policyAncestorRef(routeParentRef))
 
 		if backendTLSPolicy.Spec.Validation.WellKnownCACertificates != nil && *backendTLSPolicy.Spec.Validation.WellKnownCACertificates != "" {
 			backendTLSPolicyAncestorStatus.AddCondition(gatewayapi_v1alpha2.PolicyConditionAccepted, meta_v1.ConditionFalse, gatewayapi_v1alpha2.PolicyReasonInvalid, "BackendTLSPolicy.Spec.Validation.WellKnownCACertificates is unsupported.")
@@ -2271,16 +2582,103 @@ func (p *GatewayAPIProcessor) computeBackendTLSPolicies(routeNamespace string, b
 	return upstreamValidation, upstreamTLS
 }
 
-// grpcClusters builds clusters from backendRef.
-func (p *GatewayAPIProcessor) grpcClusters(routeNamespace string, backendRefs []gatewayapi_v1.GRPCBackendRef, routeAccessor *status.RouteParentStatusUpdate, protocolType gatewayapi_v1.ProtocolType) ([]*Cluster, uint32, bool) {
+// computeBackendTrafficPolicy returns the load balancer policy and the request hash
+// policies implementing the session persistence of the XBackendTrafficPolicy for the
+// service being referenced.
+//
+// If no XBackendTrafficPolicy is found or the XBackendTrafficPolicy is invalid then an
+// empty load balancer policy and no request hash policies are returned.
+func (p *GatewayAPIProcessor) computeBackendTrafficPolicy(routeNamespace string, backendRef gatewayapi_v1.BackendRef, routeParentRef gatewayapi_v1.ParentReference) (string, []RequestHashPolicy) {
+	backendNamespace := routeNamespace
+	if backendRef.Namespace != nil && *backendRef.Namespace != "" {
+		backendNamespace = string(*backendRef.Namespace)
+	}
+
+	policyTargetRef := gatewayapi_v1alpha2.LocalPolicyTargetReference{
+		Group: ptr.Deref(backendRef.Group, ""),
+		Kind:  ptr.Deref(backendRef.Kind, ""),
+		Name:  backendRef.Name,
+	}
+
+	backendTrafficPolicy, found := p.source.LookupBackendTrafficPolicyByTargetRef(policyTargetRef, backendNamespace)
+	if !found || backendTrafficPolicy.Spec.SessionPersistence == nil {
+		return "", nil
+	}
+
+	backendTrafficPolicyAccessor, commit := p.dag.StatusCache.BackendTrafficPolicyConditionsAccessor(
+		k8s.NamespacedNameOf(p.gateway),
+		k8s.NamespacedNameOf(backendTrafficPolicy),
+		backendTrafficPolicy.GetGeneration(),
+	)
+	defer commit()
+	backendTrafficPolicyAncestorStatus := backendTrafficPolicyAccessor.StatusUpdateFor(p.policyAncestorRef(routeParentRef))
+
+	sessionPersistence := backendTrafficPolicy.Spec.SessionPersistence
+
+	if sessionPersistence.IdleTimeout != nil {
+		backendTrafficPolicyAncestorStatus.AddCondition(gatewayapi_v1alpha2.PolicyConditionAccepted, meta_v1.ConditionFalse, gatewayapi_v1alpha2.PolicyReasonInvalid, "XBackendTrafficPolicy.Spec.SessionPersistence.IdleTimeout is unsupported.")
+		return "", nil
+	}
+
+	sessionName := ptr.Deref(sessionPersistence.SessionName, "")
+	if sessionName == "" {
+		sessionName = "X-Contour-Session-Affinity"
+	}
+
+	if ptr.Deref(sessionPersistence.Type, gatewayapi_v1.CookieBasedSessionPersistence) == gatewayapi_v1.HeaderBasedSessionPersistence {
+		if sessionPersistence.AbsoluteTimeout != nil {
+			backendTrafficPolicyAncestorStatus.AddCondition(gatewayapi_v1alpha2.PolicyConditionAccepted, meta_v1.ConditionFalse, gatewayapi_v1alpha2.PolicyReasonInvalid, "XBackendTrafficPolicy.Spec.SessionPersistence.AbsoluteTimeout is unsupported for Header session persistence.")
+			return "", nil
+		}
+
+		backendTrafficPolicyAncestorStatus.AddCondition(gatewayapi_v1alpha2.PolicyConditionAccepted, meta_v1.ConditionTrue, gatewayapi_v1alpha2.PolicyReasonAccepted, "Accepted XBackendTrafficPolicy")
+		return LoadBalancerPolicyRequestHash, []RequestHashPolicy{
+			{HeaderHashOptions: &HeaderHashOptions{HeaderName: sessionName}},
+		}
+	}
+
+	// A session cookie has no expiry, so the absolute timeout can
+	// only be honored by a permanent cookie.
+	var ttl time.Duration
+	if sessionPersistence.CookieConfig != nil && ptr.Deref(sessionPersistence.CookieConfig.LifetimeType, gatewayapi_v1.SessionCookieLifetimeType) == gatewayapi_v1.PermanentCookieLifetimeType {
+		if sessionPersistence.AbsoluteTimeout == nil {
+			backendTrafficPolicyAncestorStatus.AddCondition(gatewayapi_v1alpha2.PolicyConditionAccepted, meta_v1.ConditionFalse, gatewayapi_v1alpha2.PolicyReasonInvalid, "XBackendTrafficPolicy.Spec.SessionPersistence.AbsoluteTimeout must be specified for Permanent cookies.")
+			return "", nil
+		}
+
+		var err error
+		ttl, err = time.ParseDuration(string(*sessionPersistence.AbsoluteTimeout))
+		if err != nil || ttl <= 0 {
+			backendTrafficPolicyAncestorStatus.AddCondition(gatewayapi_v1alpha2.PolicyConditionAccepted, meta_v1.ConditionFalse, gatewayapi_v1alpha2.PolicyReasonInvalid, fmt.Sprintf("XBackendTrafficPolicy.Spec.SessionPersistence.AbsoluteTimeout %q is invalid.", *sessionPersistence.AbsoluteTimeout))
+			return "", nil
+		}
+	} else if sessionPersistence.AbsoluteTimeout != nil {
+		backendTrafficPolicyAncestorStatus.AddCondition(gatewayapi_v1alpha2.PolicyConditionAccepted, meta_v1.ConditionFalse, gatewayapi_v1alpha2.PolicyReasonInvalid, "XBackendTrafficPolicy.Spec.SessionPersistence.AbsoluteTimeout is only supported for Permanent cookies.")
+		return "", nil
+	}
+
+	backendTrafficPolicyAncestorStatus.AddCondition(gatewayapi_v1alpha2.PolicyConditionAccepted, meta_v1.ConditionTrue, gatewayapi_v1alpha2.PolicyReasonAccepted, "Accepted XBackendTrafficPolicy")
+	return LoadBalancerPolicyCookie, []RequestHashPolicy{
+		{CookieHashOptions: &CookieHashOptions{
+			CookieName: sessionName,
+			TTL:        ttl,
+			Path:       "/",
+		}},
+	}
+}
+
+// grpcClusters builds clusters from backendRef, along with the request hash
+// policies of the first backend with session persistence.
+func (p *GatewayAPIProcessor) grpcClusters(routeNamespace string, backendRefs []gatewayapi_v1.GRPCBackendRef, routeAccessor *status.RouteParentStatusUpdate, routeParentRef gatewayapi_v1.ParentReference, protocolType gatewayapi_v1.ProtocolType) ([]*Cluster, []RequestHashPolicy, uint32, bool) {
 	totalWeight := uint32(0)
 
 	if len(backendRefs) == 0 {
 		routeAccessor.AddCondition(gatewayapi_v1.RouteConditionResolvedRefs, meta_v1.ConditionFalse, status.ReasonDegraded, "At least one Spec.Rules.BackendRef must be specified.")
-		return nil, totalWeight, false
+		return nil, nil, totalWeight, false
 	}
 
 	var clusters []*Cluster
+	var requestHashPolicies []RequestHashPolicy
 
 	// Validate the backend refs.
 	for _, backendRef := range backendRefs {
@@ -2342,6 +2740,18 @@ func (p *GatewayAPIProcessor) grpcClusters(routeNamespace string, backendRefs []
 		// If protocol is not set on the service, need to set a default one based on listener's protocol type.
 		setDefaultServiceProtocol(service, protocolType)
 
+		// gRPC backends always speak HTTP/2, which is over TLS when
+		// a BackendTLSPolicy applies.
+		upstreamValidation, upstreamTLS := p.computeBackendTLSPolicies(routeNamespace, backendRef.BackendRef, service, routeParentRef)
+		if upstreamValidation != nil {
+			service.Protocol = "h2"
+		}
+
+		loadBalancerPolicy, hashPolicies := p.computeBackendTrafficPolicy(routeNamespace, backendRef.BackendRef, routeParentRef)
+		if requestHashPolicies == nil {
+			requestHashPolicies = hashPolicies
+		}
+
 		// https://github.com/projectcontour/contour/issues/3593
 		service.Weighted.Weight = routeWeight
 		clusters = append(clusters, &Cluster{
@@ -2353,15 +2763,19 @@ func (p *GatewayAPIProcessor) grpcClusters(routeNamespace string, backendRefs []
 			TimeoutPolicy:                 ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
 			MaxRequestsPerConnection:      p.MaxRequestsPerConnection,
 			PerConnectionBufferLimitBytes: p.PerConnectionBufferLimitBytes,
+			UpstreamValidation:            upstreamValidation,
+			UpstreamTLS:                   upstreamTLS,
+			LoadBalancerPolicy:            loadBalancerPolicy,
 		})
 	}
-	return clusters, totalWeight, true
+	return clusters, requestHashPolicies, totalWeight, true
 }
 
 // clusterRoutes builds a []*dag.Route for the supplied set of matchConditions, headerPolicies and backendRefs.
 func (p *GatewayAPIProcessor) clusterRoutes(
 	matchConditions []*matchConditions,
 	clusters []*Cluster,
+	requestHashPolicies []RequestHashPolicy,
 	totalWeight uint32,
 	priority uint8,
 	kind string,
@@ -2394,6 +2808,7 @@ func (p *GatewayAPIProcessor) clusterRoutes(
 			MirrorPolicies:            mirrorPolicies,
 			Priority:                  priority,
 			PathRewritePolicy:         pathRewritePolicy,
+			RequestHashPolicies:       requestHashPolicies,
 		}
 		if timeoutPolicy != nil {
 			route.TimeoutPolicy = *timeoutPolicy
@@ -462,13 +462,13 @@ func TestNamespaceMatches(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			processor := &GatewayAPIProcessor{
 				FieldLogger: fixture.NewTestLogger(t),
-				source: &KubernetesCache{
-					gateway: &gatewayapi_v1.Gateway{
-						ObjectMeta: meta_v1.ObjectMeta{
-							Name:      "contour",
-							Namespace: "projectcontour",
-						},
+				gateway: &gatewayapi_v1.Gateway{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "contour",
+						Namespace: "projectcontour",
 					},
+				},
+				source: &KubernetesCache{
 					namespaces: map[string]*core_v1.Namespace{
 						"projectcontour": {
 							ObjectMeta: meta_v1.ObjectMeta{
@@ -507,7 +507,7 @@ func TestNamespaceMatches(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			got := processor.namespaceMatches(tc.namespaces, selector, tc.namespace)
+			got := processor.namespaceMatches(tc.namespaces, selector, processor.gateway.Namespace, tc.namespace)
 			assert.Equal(t, tc.valid, got)
 		})
 	}
@@ -810,14 +810,13 @@ func TestGetListenersForRouteParentRef(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			processor := &GatewayAPIProcessor{
 				FieldLogger: fixture.NewTestLogger(t),
-				source: &KubernetesCache{
-					gateway: &gatewayapi_v1.Gateway{
-						ObjectMeta: meta_v1.ObjectMeta{
-							Name:      "contour",
-							Namespace: "projectcontour",
-						},
+				gateway: &gatewayapi_v1.Gateway{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "contour",
+						Namespace: "projectcontour",
 					},
 				},
+				source: &KubernetesCache{},
 			}
 
 			rsu := &status.RouteStatusUpdate{}
@@ -825,10 +824,11 @@ func TestGetListenersForRouteParentRef(t *testing.T) {
 
 			got := processor.getListenersForRouteParentRef(
 				tc.routeParentRef,
+				nil,
 				tc.routeNamespace,
 				gatewayapi_v1.Kind(tc.routeKind),
 				tc.listeners,
-				map[string]int{},
+				map[*listenerInfo]int{},
 				rpsu)
 
 			var want map[string]*listenerInfo
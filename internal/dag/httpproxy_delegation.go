@@ -0,0 +1,190 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/status"
+)
+
+// computeDelegationStatus sets the status of each TLSCertificateDelegation,
+// validating its delegations and listing the HTTPProxies in other namespaces
+// that reference the secrets it delegates.
+func (p *HTTPProxyProcessor) computeDelegationStatus() {
+	refs := map[types.NamespacedName][]types.NamespacedName{}
+	for _, proxy := range p.source.httpproxies {
+		for _, secret := range httpProxySecretRefs(proxy) {
+			if secret.Namespace == proxy.Namespace {
+				continue
+			}
+			refs[secret] = append(refs[secret], k8s.NamespacedNameOf(proxy))
+		}
+	}
+
+	for _, delegation := range p.source.tlscertificatedelegations {
+		ds, commit := status.DelegationAccessor(&p.dag.StatusCache, delegation)
+
+		validCond := ds.ConditionFor(status.ValidCondition)
+		for i, d := range delegation.Spec.Delegations {
+			if err := validateCertificateDelegation(d); err != nil {
+				validCond.AddErrorf(contour_v1.ConditionTypeSpecError, "DelegationNotValid",
+					"Spec.Delegations[%d] is invalid: %s", i, err)
+			}
+		}
+		if len(validCond.Errors) == 0 {
+			validCond.Status = contour_v1.ConditionTrue
+			validCond.Reason = "Valid"
+			validCond.Message = "Valid TLSCertificateDelegation"
+		}
+
+		consumers := map[string][]string{}
+		for secret, proxies := range refs {
+			if secret.Namespace != delegation.Namespace {
+				continue
+			}
+			for _, proxy := range proxies {
+				if slices.ContainsFunc(delegation.Spec.Delegations, func(d contour_v1.CertificateDelegation) bool {
+					return p.source.delegates(d, secret, proxy.Namespace)
+				}) {
+					consumers[secret.Name] = append(consumers[secret.Name], proxy.String())
+				}
+			}
+		}
+
+		consumedCond := ds.ConditionFor(status.ConsumedCondition)
+		if len(consumers) == 0 {
+			consumedCond.Status = contour_v1.ConditionFalse
+			consumedCond.Reason = "NotConsumed"
+			consumedCond.Message = "No HTTPProxy references a delegated secret"
+		} else {
+			consumedCond.Status = contour_v1.ConditionTrue
+			consumedCond.Reason = "SecretsConsumed"
+			consumedCond.Message = consumersMessage(consumers)
+		}
+
+		commit()
+	}
+}
+
+// validateCertificateDelegation returns an error if the
+// CertificateDelegation can't permit any reference.
+func validateCertificateDelegation(d contour_v1.CertificateDelegation) error {
+	switch {
+	case d.SecretName != "" && d.SecretSelector != nil:
+		return fmt.Errorf("only one of secretName or secretSelector may be specified")
+	case d.SecretName != "":
+		if _, err := path.Match(d.SecretName, ""); err != nil {
+			return fmt.Errorf("secretName %q is not a valid pattern: %w", d.SecretName, err)
+		}
+	case d.SecretSelector != nil:
+		if _, err := meta_v1.LabelSelectorAsSelector(d.SecretSelector); err != nil {
+			return fmt.Errorf("secretSelector is not a valid label selector: %w", err)
+		}
+	default:
+		return fmt.Errorf("one of secretName or secretSelector must be specified")
+	}
+
+	if len(d.TargetNamespaces) == 0 && d.TargetNamespaceSelector == nil {
+		return fmt.Errorf("at least one of targetNamespaces or targetNamespaceSelector must be specified")
+	}
+
+	if d.TargetNamespaceSelector != nil {
+		if _, err := meta_v1.LabelSelectorAsSelector(d.TargetNamespaceSelector); err != nil {
+			return fmt.Errorf("targetNamespaceSelector is not a valid label selector: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// consumersMessage formats the HTTPProxies referencing each
+// delegated secret, sorted by secret and HTTPProxy name.
+func consumersMessage(consumers map[string][]string) string {
+	secrets := make([]string, 0, len(consumers))
+	for secret := range consumers {
+		secrets = append(secrets, secret)
+	}
+	slices.Sort(secrets)
+
+	parts := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		proxies := consumers[secret]
+		slices.Sort(proxies)
+		parts = append(parts, fmt.Sprintf("secret %q is referenced by HTTPProxies %s", secret, strings.Join(proxies, ", ")))
+	}
+
+	message := strings.Join(parts, "; ")
+	if len(message) > contour_v1.LongMessageLength {
+		message = message[:contour_v1.LongMessageLength]
+	}
+	return message
+}
+
+// httpProxySecretRefs returns the secrets the HTTPProxy
+// references that are subject to certificate delegation.
+func httpProxySecretRefs(proxy *contour_v1.HTTPProxy) []types.NamespacedName {
+	var refs []types.NamespacedName
+
+	add := func(name string) {
+		if name != "" {
+			refs = append(refs, k8s.NamespacedNameFrom(name, k8s.DefaultNamespace(proxy.Namespace)))
+		}
+	}
+
+	addServices := func(services []contour_v1.Service) {
+		for _, service := range services {
+			if service.UpstreamValidation != nil {
+				add(service.UpstreamValidation.CACertificate)
+			}
+		}
+	}
+
+	if vh := proxy.Spec.VirtualHost; vh != nil {
+		if tls := vh.TLS; tls != nil {
+			add(tls.SecretName)
+			for _, name := range tls.AdditionalSecretNames {
+				add(name)
+			}
+			if cv := tls.ClientValidation; cv != nil {
+				add(cv.CACertificate)
+				add(cv.CertificateRevocationList)
+			}
+		}
+
+		for _, provider := range vh.JWTProviders {
+			if provider.RemoteJWKS != nil && provider.RemoteJWKS.UpstreamValidation != nil {
+				add(provider.RemoteJWKS.UpstreamValidation.CACertificate)
+			}
+		}
+	}
+
+	for _, route := range proxy.Spec.Routes {
+		addServices(route.Services)
+	}
+
+	if tcpproxy := proxy.Spec.TCPProxy; tcpproxy != nil {
+		addServices(tcpproxy.Services)
+	}
+
+	return refs
+}
@@ -0,0 +1,210 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/status"
+)
+
+func TestDelegationStatus(t *testing.T) {
+	proxy := func(namespace, name, secret string) *contour_v1.HTTPProxy {
+		return &contour_v1.HTTPProxy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: contour_v1.HTTPProxySpec{
+				VirtualHost: &contour_v1.VirtualHost{
+					Fqdn: name + "." + namespace + ".example.com",
+					TLS: &contour_v1.TLS{
+						SecretName: secret,
+					},
+				},
+			},
+		}
+	}
+
+	delegation := func(delegations ...contour_v1.CertificateDelegation) *contour_v1.TLSCertificateDelegation {
+		return &contour_v1.TLSCertificateDelegation{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "delegation",
+				Namespace: "secrets",
+			},
+			Spec: contour_v1.TLSCertificateDelegationSpec{
+				Delegations: delegations,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		objs         []any
+		wantValid    contour_v1.DetailedCondition
+		wantConsumed contour_v1.Condition
+	}{
+		"no consumers": {
+			objs: []any{
+				delegation(contour_v1.CertificateDelegation{
+					SecretName:       "wildcard",
+					TargetNamespaces: []string{"*"},
+				}),
+				proxy("secrets", "same-namespace", "wildcard"),
+			},
+			wantValid: contour_v1.DetailedCondition{
+				Condition: contour_v1.Condition{
+					Type:    "Valid",
+					Status:  contour_v1.ConditionTrue,
+					Reason:  "Valid",
+					Message: "Valid TLSCertificateDelegation",
+				},
+			},
+			wantConsumed: contour_v1.Condition{
+				Type:    "Consumed",
+				Status:  contour_v1.ConditionFalse,
+				Reason:  "NotConsumed",
+				Message: "No HTTPProxy references a delegated secret",
+			},
+		},
+		"consumers of each delegated secret": {
+			objs: []any{
+				&core_v1.Namespace{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:   "tenant-c",
+						Labels: map[string]string{"tenant": "true"},
+					},
+				},
+				delegation(contour_v1.CertificateDelegation{
+					SecretName:       "wildcard-*",
+					TargetNamespaces: []string{"tenant-a", "tenant-b"},
+				}, contour_v1.CertificateDelegation{
+					SecretName: "other",
+					TargetNamespaceSelector: &meta_v1.LabelSelector{
+						MatchLabels: map[string]string{"tenant": "true"},
+					},
+				}),
+				proxy("tenant-b", "www", "secrets/wildcard-one"),
+				proxy("tenant-a", "www", "secrets/wildcard-one"),
+				proxy("tenant-a", "api", "secrets/wildcard-two"),
+				proxy("tenant-c", "www", "secrets/other"),
+				// Not delegated to tenant-d.
+				proxy("tenant-d", "www", "secrets/wildcard-one"),
+				// Not a delegated secret.
+				proxy("tenant-a", "blog", "secrets/blog"),
+			},
+			wantValid: contour_v1.DetailedCondition{
+				Condition: contour_v1.Condition{
+					Type:    "Valid",
+					Status:  contour_v1.ConditionTrue,
+					Reason:  "Valid",
+					Message: "Valid TLSCertificateDelegation",
+				},
+			},
+			wantConsumed: contour_v1.Condition{
+				Type:   "Consumed",
+				Status: contour_v1.ConditionTrue,
+				Reason: "SecretsConsumed",
+				Message: `secret "other" is referenced by HTTPProxies tenant-c/www; ` +
+					`secret "wildcard-one" is referenced by HTTPProxies tenant-a/www, tenant-b/www; ` +
+					`secret "wildcard-two" is referenced by HTTPProxies tenant-a/api`,
+			},
+		},
+		"invalid delegations": {
+			objs: []any{
+				delegation(contour_v1.CertificateDelegation{
+					TargetNamespaces: []string{"*"},
+				}, contour_v1.CertificateDelegation{
+					SecretName:       "wildcard-[",
+					TargetNamespaces: []string{"*"},
+				}, contour_v1.CertificateDelegation{
+					SecretName: "wildcard",
+				}, contour_v1.CertificateDelegation{
+					SecretName: "wildcard",
+					TargetNamespaceSelector: &meta_v1.LabelSelector{
+						MatchExpressions: []meta_v1.LabelSelectorRequirement{{
+							Key:      "tenant",
+							Operator: "Bogus",
+						}},
+					},
+				}),
+			},
+			wantValid: contour_v1.DetailedCondition{
+				Condition: contour_v1.Condition{
+					Type:    "Valid",
+					Status:  contour_v1.ConditionFalse,
+					Reason:  "ErrorPresent",
+					Message: "At least one error present, see Errors for details",
+				},
+				Errors: []contour_v1.SubCondition{{
+					Type:    contour_v1.ConditionTypeSpecError,
+					Status:  contour_v1.ConditionTrue,
+					Reason:  "DelegationNotValid",
+					Message: "Spec.Delegations[0] is invalid: one of secretName or secretSelector must be specified",
+				}, {
+					Type:    contour_v1.ConditionTypeSpecError,
+					Status:  contour_v1.ConditionTrue,
+					Reason:  "DelegationNotValid",
+					Message: `Spec.Delegations[1] is invalid: secretName "wildcard-[" is not a valid pattern: syntax error in pattern`,
+				}, {
+					Type:    contour_v1.ConditionTypeSpecError,
+					Status:  contour_v1.ConditionTrue,
+					Reason:  "DelegationNotValid",
+					Message: "Spec.Delegations[2] is invalid: at least one of targetNamespaces or targetNamespaceSelector must be specified",
+				}, {
+					Type:    contour_v1.ConditionTypeSpecError,
+					Status:  contour_v1.ConditionTrue,
+					Reason:  "DelegationNotValid",
+					Message: `Spec.Delegations[3] is invalid: targetNamespaceSelector is not a valid label selector: "Bogus" is not a valid label selector operator`,
+				}},
+			},
+			wantConsumed: contour_v1.Condition{
+				Type:    "Consumed",
+				Status:  contour_v1.ConditionFalse,
+				Reason:  "NotConsumed",
+				Message: "No HTTPProxy references a delegated secret",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					FieldLogger: fixture.NewTestLogger(t),
+				},
+				Processors: []Processor{
+					&ListenerProcessor{},
+					&HTTPProxyProcessor{},
+				},
+			}
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+
+			entry, ok := dag.StatusCache.Get(delegation()).(*status.DelegationCacheEntry)
+			require.True(t, ok)
+
+			assert.Equal(t, tc.wantValid, *entry.Conditions[status.ValidCondition])
+			assert.Equal(t, tc.wantConsumed, entry.Conditions[status.ConsumedCondition].Condition)
+		})
+	}
+}
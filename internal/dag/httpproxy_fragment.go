@@ -0,0 +1,259 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/status"
+)
+
+// proxyFragment holds the part of the DAG computed from a root
+// HTTPProxy, along with the objects it was computed from.
+type proxyFragment struct {
+	// dag holds the virtual hosts and status updates
+	// computed from the root HTTPProxy.
+	dag *DAG
+
+	// claimed holds the HTTPProxies included by the root
+	// HTTPProxy, which are therefore not orphaned.
+	claimed []types.NamespacedName
+
+	deps *dependencies
+}
+
+// computeHTTPProxiesIncremental computes the given HTTPProxies,
+// reusing the fragment computed in the previous run for each root
+// HTTPProxy whose dependencies did not change.
+func (p *HTTPProxyProcessor) computeHTTPProxiesIncremental(proxies []*contour_v1.HTTPProxy) {
+	// Fragments are computed against a particular set of
+	// Listeners, so start over if those change.
+	if listeners := listenersKey(p.dag); listeners != p.listeners || p.fragments == nil {
+		p.listeners = listeners
+		p.fragments = map[types.NamespacedName]*proxyFragment{}
+	}
+
+	fragments := make(map[types.NamespacedName]*proxyFragment, len(p.fragments))
+	now := time.Now()
+
+	for _, proxy := range proxies {
+		// Non-root HTTPProxies only get marked as orphaned,
+		// so there's nothing to reuse.
+		if proxy.Spec.VirtualHost == nil {
+			p.computeHTTPProxy(proxy)
+			continue
+		}
+
+		// If another processor already added a virtual host
+		// for this FQDN, the HTTPProxy has to be computed
+		// on top of it.
+		if p.hasVirtualHost(proxy.Spec.VirtualHost.Fqdn) {
+			p.computeHTTPProxy(proxy)
+			continue
+		}
+
+		name := k8s.NamespacedNameOf(proxy)
+		fragment, ok := p.fragments[name]
+		if !ok || fragment.deps.changed(p.source, p.dag, now) {
+			fragment = p.computeHTTPProxyFragment(proxy)
+		} else {
+			// The reused fragment still goes stale
+			// when it would have.
+			p.source.dependUntil(fragment.deps.expires)
+		}
+		fragments[name] = fragment
+
+		p.applyFragment(fragment)
+	}
+
+	p.fragments = fragments
+}
+
+// computeHTTPProxyFragment computes the given root HTTPProxy
+// into a new fragment, recording the objects it depends on.
+func (p *HTTPProxyProcessor) computeHTTPProxyFragment(proxy *contour_v1.HTTPProxy) *proxyFragment {
+	dag := p.dag
+
+	p.fragment = &proxyFragment{
+		dag:  newFragmentDAG(dag),
+		deps: newDependencies(),
+	}
+	p.dag = p.fragment.dag
+	p.source.deps = p.fragment.deps
+
+	defer func() {
+		p.dag = dag
+		p.source.deps = nil
+		p.fragment = nil
+	}()
+
+	p.source.dependOn("HTTPProxy", k8s.NamespacedNameOf(proxy))
+	p.computeHTTPProxy(proxy)
+
+	return p.fragment
+}
+
+// applyFragment adds copies of the virtual hosts and status updates
+// held by the fragment to the DAG. The routes, TCP proxies and clusters
+// of the virtual hosts are copied too, since the processors running
+// after the HTTPProxyProcessor may change them.
+func (p *HTTPProxyProcessor) applyFragment(fragment *proxyFragment) {
+	for name, listener := range fragment.dag.Listeners {
+		for _, vhost := range listener.VirtualHosts {
+			vh := *vhost
+			vh.Routes = copyRoutes(vhost.Routes)
+
+			p.dag.Listeners[name].VirtualHosts = append(p.dag.Listeners[name].VirtualHosts, &vh)
+			p.dag.Listeners[name].vhostsByName[vh.Name] = &vh
+		}
+
+		for _, svhost := range listener.SecureVirtualHosts {
+			svh := *svhost
+			svh.Routes = copyRoutes(svhost.Routes)
+			if svhost.TCPProxy != nil {
+				tcpproxy := *svhost.TCPProxy
+				tcpproxy.Clusters = copyClusters(svhost.TCPProxy.Clusters)
+				svh.TCPProxy = &tcpproxy
+			}
+
+			p.dag.Listeners[name].SecureVirtualHosts = append(p.dag.Listeners[name].SecureVirtualHosts, &svh)
+			p.dag.Listeners[name].svhostsByName[svh.Name] = &svh
+		}
+	}
+
+	for _, pu := range fragment.dag.StatusCache.GetProxyUpdates() {
+		p.dag.StatusCache.CommitProxyUpdate(pu)
+	}
+
+	for _, name := range fragment.claimed {
+		delete(p.orphaned, name)
+	}
+}
+
+// copyRoutes returns copies of the given routes, along with
+// copies of the clusters they forward and mirror traffic to.
+func copyRoutes(routes map[string]*Route) map[string]*Route {
+	copies := make(map[string]*Route, len(routes))
+	for name, route := range routes {
+		r := *route
+		r.Clusters = copyClusters(route.Clusters)
+		if route.MirrorPolicies != nil {
+			r.MirrorPolicies = make([]*MirrorPolicy, len(route.MirrorPolicies))
+			for i, mp := range route.MirrorPolicies {
+				m := *mp
+				if mp.Cluster != nil {
+					cluster := *mp.Cluster
+					m.Cluster = &cluster
+				}
+				r.MirrorPolicies[i] = &m
+			}
+		}
+		copies[name] = &r
+	}
+	return copies
+}
+
+// copyClusters returns copies of the given clusters. The values
+// they point to are shared, and must not be changed.
+func copyClusters(clusters []*Cluster) []*Cluster {
+	if clusters == nil {
+		return nil
+	}
+
+	copies := make([]*Cluster, len(clusters))
+	for i, cluster := range clusters {
+		c := *cluster
+		copies[i] = &c
+	}
+	return copies
+}
+
+// hasVirtualHost returns true if any Listener in the
+// DAG has a virtual host for the given FQDN.
+func (p *HTTPProxyProcessor) hasVirtualHost(fqdn string) bool {
+	for _, listener := range p.dag.Listeners {
+		if listener.vhostsByName[fqdn] != nil || listener.svhostsByName[fqdn] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupHTTPProxy returns the named HTTPProxy from the cache.
+func (p *HTTPProxyProcessor) lookupHTTPProxy(name types.NamespacedName) (*contour_v1.HTTPProxy, bool) {
+	p.source.dependOn("HTTPProxy", name)
+	proxy, ok := p.source.httpproxies[name]
+	return proxy, ok
+}
+
+// claim marks the named HTTPProxy as included by a root HTTPProxy.
+func (p *HTTPProxyProcessor) claim(name types.NamespacedName) {
+	delete(p.orphaned, name)
+	if p.fragment != nil {
+		p.fragment.claimed = append(p.fragment.claimed, name)
+	}
+}
+
+// getExtensionCluster returns the named extension cluster from the DAG.
+func (p *HTTPProxyProcessor) getExtensionCluster(name string) *ExtensionCluster {
+	ec := p.dag.GetExtensionCluster(name)
+	if p.fragment != nil {
+		p.fragment.deps.extensionClusters[name] = ec
+	}
+	return ec
+}
+
+// newFragmentDAG returns an empty DAG with the same
+// Listeners and extension clusters as the given DAG.
+func newFragmentDAG(dag *DAG) *DAG {
+	fragment := &DAG{
+		StatusCache:         status.NewCache(""),
+		Listeners:           make(map[string]*Listener, len(dag.Listeners)),
+		ExtensionClusters:   dag.ExtensionClusters,
+		HasDynamicListeners: dag.HasDynamicListeners,
+	}
+
+	for name, listener := range dag.Listeners {
+		fragment.Listeners[name] = &Listener{
+			Name:                        listener.Name,
+			Protocol:                    listener.Protocol,
+			Address:                     listener.Address,
+			Port:                        listener.Port,
+			RouteConfigName:             listener.RouteConfigName,
+			FallbackCertRouteConfigName: listener.FallbackCertRouteConfigName,
+			EnableWebsockets:            listener.EnableWebsockets,
+			vhostsByName:                map[string]*VirtualHost{},
+			svhostsByName:               map[string]*SecureVirtualHost{},
+		}
+	}
+
+	return fragment
+}
+
+// listenersKey returns a string identifying the
+// names and protocols of the Listeners in the DAG.
+func listenersKey(dag *DAG) string {
+	var listeners []string
+	for name, listener := range dag.Listeners {
+		listeners = append(listeners, name+"/"+listener.Protocol)
+	}
+	sort.Strings(listeners)
+	return strings.Join(listeners, ",")
+}
@@ -0,0 +1,278 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/status"
+)
+
+func TestHTTPProxyProcessorIncrementalRebuild(t *testing.T) {
+	newBuilder := func(incremental bool) *Builder {
+		return &Builder{
+			Source: KubernetesCache{
+				FieldLogger: fixture.NewTestLogger(t),
+			},
+			Processors: []Processor{
+				&ListenerProcessor{},
+				&IngressProcessor{
+					FieldLogger: fixture.NewTestLogger(t),
+				},
+				&HTTPProxyProcessor{
+					IncrementalRebuild: incremental,
+				},
+				// Later processors may change the routes and
+				// clusters, which must not change the ones
+				// reused by the next build.
+				ProcessorFunc(func(dag *DAG, _ *KubernetesCache) {
+					seen := map[any]bool{}
+					for _, listener := range dag.Listeners {
+						for _, vhost := range listener.VirtualHosts {
+							appendStatPrefixes(vhost.Routes, seen)
+						}
+						for _, svhost := range listener.SecureVirtualHosts {
+							appendStatPrefixes(svhost.Routes, seen)
+						}
+					}
+				}),
+			},
+		}
+	}
+
+	service := func(name string, port int32) *core_v1.Service {
+		return &core_v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: core_v1.ServiceSpec{
+				Ports: []core_v1.ServicePort{{
+					Protocol:   "TCP",
+					Port:       port,
+					TargetPort: intstr.FromInt(8080),
+				}},
+			},
+		}
+	}
+
+	route := func(service string, port int) contour_v1.Route {
+		return contour_v1.Route{
+			Services: []contour_v1.Service{{Name: service, Port: port}},
+		}
+	}
+
+	proxy := func(name, fqdn string, tls bool, routes []contour_v1.Route, includes ...string) *contour_v1.HTTPProxy {
+		p := &contour_v1.HTTPProxy{
+			ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: contour_v1.HTTPProxySpec{
+				Routes: routes,
+			},
+		}
+		if fqdn != "" {
+			p.Spec.VirtualHost = &contour_v1.VirtualHost{Fqdn: fqdn}
+			if tls {
+				p.Spec.VirtualHost.TLS = &contour_v1.TLS{SecretName: "secret"}
+			}
+		}
+		for _, include := range includes {
+			p.Spec.Includes = append(p.Spec.Includes, contour_v1.Include{
+				Name:       include,
+				Conditions: []contour_v1.MatchCondition{{Prefix: "/" + include}},
+			})
+		}
+		return p
+	}
+
+	secret := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "secret", Namespace: "default"},
+		Type:       core_v1.SecretTypeTLS,
+		Data:       secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY),
+	}
+
+	full := newBuilder(false)
+	incremental := newBuilder(true)
+
+	insert := func(objs ...any) {
+		for _, o := range objs {
+			full.Source.Insert(o)
+			incremental.Source.Insert(o)
+		}
+	}
+	remove := func(objs ...any) {
+		for _, o := range objs {
+			full.Source.Remove(o)
+			incremental.Source.Remove(o)
+		}
+	}
+
+	// proxyStatuses returns the status updates of the DAG
+	// without their transition times.
+	proxyStatuses := func(dag *DAG) map[types.NamespacedName]status.ProxyUpdate {
+		statuses := map[types.NamespacedName]status.ProxyUpdate{}
+		for _, pu := range dag.StatusCache.GetProxyUpdates() {
+			statuses[pu.Fullname] = status.ProxyUpdate{
+				Fullname:   pu.Fullname,
+				Generation: pu.Generation,
+				Vhost:      pu.Vhost,
+				Conditions: pu.Conditions,
+			}
+		}
+		return statuses
+	}
+
+	// build builds both DAGs, checks they are the same
+	// and returns the incrementally built one.
+	build := func() *DAG {
+		want := full.Build()
+		got := incremental.Build()
+
+		assert.Equal(t, want.Listeners, got.Listeners)
+		assert.Equal(t, proxyStatuses(want), proxyStatuses(got))
+
+		return got
+	}
+
+	// fragmentOf returns the fragment the incremental
+	// build computed for the named root HTTPProxy.
+	fragmentOf := func(name string) *proxyFragment {
+		p, ok := incremental.Processors[2].(*HTTPProxyProcessor)
+		require.True(t, ok)
+		fragment := p.fragments[types.NamespacedName{Namespace: "default", Name: name}]
+		require.NotNil(t, fragment)
+		return fragment
+	}
+
+	insert(
+		service("kuard", 8080),
+		service("other", 8080),
+		secret,
+		proxy("root-a", "a.example.com", false, []contour_v1.Route{route("kuard", 8080)}),
+		proxy("root-b", "b.example.com", true, nil, "child"),
+		proxy("child", "", false, []contour_v1.Route{route("other", 8080)}),
+		proxy("orphan", "", false, []contour_v1.Route{route("kuard", 8080)}),
+	)
+	build()
+	fragmentA := fragmentOf("root-a")
+
+	// Changing a Service only recomputes the root
+	// HTTPProxies that route to it.
+	insert(service("other", 8081))
+	build()
+	assert.Same(t, fragmentA, fragmentOf("root-a"))
+
+	// Rebuilding after changing it again reuses the
+	// fragment once more.
+	insert(service("other", 8082))
+	build()
+	assert.Same(t, fragmentA, fragmentOf("root-a"))
+
+	// Changing an included HTTPProxy recomputes the root.
+	insert(proxy("child", "", false, []contour_v1.Route{route("other", 8081)}))
+	build()
+	assert.Same(t, fragmentA, fragmentOf("root-a"))
+
+	// Changing a TLS Secret recomputes the root, twice.
+	fragmentB := fragmentOf("root-b")
+	for range 2 {
+		updated := secret.DeepCopy()
+		updated.Data = secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY)
+		insert(updated)
+		build()
+		assert.NotSame(t, fragmentB, fragmentOf("root-b"))
+		assert.Same(t, fragmentA, fragmentOf("root-a"))
+		fragmentB = fragmentOf("root-b")
+	}
+
+	// Removing a TLS Secret recomputes the root.
+	remove(secret)
+	build()
+
+	// Conflicting FQDNs invalidate both roots.
+	insert(proxy("root-c", "a.example.com", false, []contour_v1.Route{route("other", 8081)}))
+	build()
+
+	// Removing the conflict makes the root valid again.
+	remove(proxy("root-c", "a.example.com", false, nil))
+	insert(secret)
+	build()
+	assert.NotSame(t, fragmentA, fragmentOf("root-a"))
+
+	// Removing an included HTTPProxy recomputes the root.
+	remove(proxy("child", "", false, nil))
+	build()
+
+	// Rebuilding without changes reuses everything.
+	fragmentA = fragmentOf("root-a")
+	build()
+	assert.Same(t, fragmentA, fragmentOf("root-a"))
+}
+
+// appendStatPrefixes changes the routes, and the clusters they
+// forward to, that are not yet seen in a way that only gives the
+// same result on each build if they are not shared between builds.
+func appendStatPrefixes(routes map[string]*Route, seen map[any]bool) {
+	for _, route := range routes {
+		if !seen[route] {
+			seen[route] = true
+			route.StatPrefix += "_route"
+		}
+		for _, cluster := range route.Clusters {
+			if !seen[cluster] {
+				seen[cluster] = true
+				cluster.StatPrefix += "_cluster"
+			}
+		}
+	}
+}
+
+func TestDependenciesChanged(t *testing.T) {
+	kc := KubernetesCache{FieldLogger: fixture.NewTestLogger(t)}
+	kc.Insert(&core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "kuard", Namespace: "default"}})
+
+	now := time.Now()
+	dag := &DAG{}
+
+	deps := newDependencies()
+	deps.objects[dependency{kind: "Service", name: types.NamespacedName{Name: "other", Namespace: "default"}}] = struct{}{}
+	assert.False(t, deps.changed(&kc, dag, now))
+
+	deps.objects[dependency{kind: "Service", name: types.NamespacedName{Name: "kuard", Namespace: "default"}}] = struct{}{}
+	assert.True(t, deps.changed(&kc, dag, now))
+
+	kc.resetChanges()
+	assert.False(t, deps.changed(&kc, dag, now))
+
+	deps.expires = now.Add(time.Minute)
+	assert.False(t, deps.changed(&kc, dag, now))
+	assert.True(t, deps.changed(&kc, dag, now.Add(time.Minute)))
+	deps.expires = time.Time{}
+
+	deps.kinds["TLSCertificateDelegation"] = struct{}{}
+	kc.Insert(&contour_v1.TLSCertificateDelegation{ObjectMeta: meta_v1.ObjectMeta{Name: "delegation", Namespace: "default"}})
+	assert.True(t, deps.changed(&kc, dag, now))
+	kc.resetChanges()
+
+	deps.extensionClusters["extension/default/ext"] = nil
+	assert.False(t, deps.changed(&kc, dag, now))
+	dag.ExtensionClusters = []*ExtensionCluster{{Name: "extension/default/ext"}}
+	assert.True(t, deps.changed(&kc, dag, now))
+}
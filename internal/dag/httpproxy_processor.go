@@ -19,7 +19,9 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -34,6 +36,7 @@ import (
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/status"
 	"github.com/projectcontour/contour/internal/timeout"
+	"github.com/projectcontour/contour/internal/xds"
 )
 
 // defaultMaxRequestBytes specifies default value maxRequestBytes for AuthorizationServer
@@ -119,6 +122,23 @@ type HTTPProxyProcessor struct {
 	// UpstreamTLS defines the TLS settings like min/max version
 	// and cipher suites for upstream connections.
 	UpstreamTLS *UpstreamTLS
+
+	// IncrementalRebuild enables reusing the virtual hosts computed
+	// from a root HTTPProxy in the previous run if none of the
+	// objects they were computed from changed.
+	IncrementalRebuild bool
+
+	// fragments holds the parts of the DAG computed from each
+	// root HTTPProxy in the previous run, if IncrementalRebuild
+	// is enabled.
+	fragments map[types.NamespacedName]*proxyFragment
+
+	// fragment is the fragment being computed, if any.
+	fragment *proxyFragment
+
+	// listeners identifies the Listeners the fragments were
+	// computed against.
+	listeners string
 }
 
 // Run translates HTTPProxies into DAG objects and
@@ -135,8 +155,12 @@ func (p *HTTPProxyProcessor) Run(dag *DAG, source *KubernetesCache) {
 		p.orphaned = nil
 	}()
 
-	for _, proxy := range p.validHTTPProxies() {
-		p.computeHTTPProxy(proxy)
+	if p.IncrementalRebuild {
+		p.computeHTTPProxiesIncremental(p.validHTTPProxies())
+	} else {
+		for _, proxy := range p.validHTTPProxies() {
+			p.computeHTTPProxy(proxy)
+		}
 	}
 
 	for meta := range p.orphaned {
@@ -149,6 +173,8 @@ func (p *HTTPProxyProcessor) Run(dag *DAG, source *KubernetesCache) {
 			commit()
 		}
 	}
+
+	p.computeDelegationStatus()
 }
 
 func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
@@ -220,6 +246,12 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 			return
 		}
 
+		if len(tls.AdditionalSecretNames) > 0 && isBlank(tls.SecretName) {
+			validCond.AddError(contour_v1.ConditionTypeTLSError, "TLSConfigNotValid",
+				"Spec.VirtualHost.TLS: AdditionalSecretNames requires SecretName to be specified")
+			return
+		}
+
 		if isBlank(tls.SecretName) && !tls.Passthrough {
 			validCond.AddError(contour_v1.ConditionTypeTLSError, "TLSConfigNotValid",
 				"Spec.VirtualHost.TLS: neither Passthrough nor SecretName were specified")
@@ -236,17 +268,30 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 
 		// Attach secrets to TLS enabled vhosts.
 		if !tls.Passthrough {
-			secretName := k8s.NamespacedNameFrom(tls.SecretName, k8s.DefaultNamespace(proxy.Namespace))
-			sec, err := p.source.LookupTLSSecret(secretName, proxy.Namespace)
-			if err != nil {
-				if _, ok := err.(DelegationNotPermittedError); ok {
-					validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "DelegationNotPermitted",
-						"Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", tls.SecretName)
-				} else {
-					validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "SecretNotValid",
-						"Spec.VirtualHost.TLS Secret %q is invalid: %s", tls.SecretName, err)
+			secretNames := append([]string{tls.SecretName}, tls.AdditionalSecretNames...)
+			secrets := make([]*Secret, 0, len(secretNames))
+			seen := map[types.NamespacedName]bool{}
+			for _, name := range secretNames {
+				secretName := k8s.NamespacedNameFrom(name, k8s.DefaultNamespace(proxy.Namespace))
+				if seen[secretName] {
+					validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "DuplicateSecret",
+						"Spec.VirtualHost.TLS Secret %q is specified more than once", name)
+					return
 				}
-				return
+				seen[secretName] = true
+
+				sec, err := p.source.LookupTLSSecret(secretName, proxy.Namespace)
+				if err != nil {
+					if _, ok := err.(DelegationNotPermittedError); ok {
+						validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "DelegationNotPermitted",
+							"Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", name)
+					} else {
+						validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "SecretNotValid",
+							"Spec.VirtualHost.TLS Secret %q is invalid: %s", name, err)
+					}
+					return
+				}
+				secrets = append(secrets, sec)
 			}
 
 			listener, err := p.dag.GetSingleListener("https")
@@ -266,8 +311,18 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 				return
 			}
 
+			for i, sec := range secrets {
+				if reason, msg := p.source.certificateExpiryWarning(sec); reason != "" {
+					validCond.AddWarningf(contour_v1.ConditionTypeTLSError, reason,
+						"Spec.VirtualHost.TLS Secret %q %s", secretNames[i], msg)
+				}
+			}
+
 			svhost := p.dag.EnsureSecureVirtualHost(listener.Name, host)
-			svhost.Secret = sec
+			svhost.Secret = secrets[0]
+			if len(secrets) > 1 {
+				svhost.AdditionalSecrets = secrets[1:]
+			}
 			svhost.MinTLSVersion = minTLSVer
 			svhost.MaxTLSVersion = maxTLSVer
 
@@ -297,7 +352,7 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 					return
 				}
 
-				sec, err = p.source.LookupTLSSecret(*p.FallbackCertificate, proxy.Namespace)
+				sec, err := p.source.LookupTLSSecret(*p.FallbackCertificate, proxy.Namespace)
 				if err != nil {
 					if _, ok := err.(DelegationNotPermittedError); ok {
 						validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "FallbackNotDelegated",
@@ -309,6 +364,11 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 					return
 				}
 
+				if reason, msg := p.source.certificateExpiryWarning(sec); reason != "" {
+					validCond.AddWarningf(contour_v1.ConditionTypeTLSError, reason,
+						"Spec.VirtualHost.TLS fallback certificate Secret %q %s", p.FallbackCertificate, msg)
+				}
+
 				svhost.FallbackCertificate = sec
 			}
 
@@ -390,120 +450,46 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 					defaultJWTProvider = jwtProvider.Name
 				}
 
-				jwksURL, err := url.Parse(jwtProvider.RemoteJWKS.URI)
-				if err != nil {
-					validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSURIInvalid",
-						"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI is invalid: %s", err)
-					return
+				provider := JWTProvider{
+					Name:       jwtProvider.Name,
+					Issuer:     jwtProvider.Issuer,
+					Audiences:  jwtProvider.Audiences,
+					ForwardJWT: jwtProvider.ForwardJWT,
 				}
 
-				if jwksURL.Scheme != "http" && jwksURL.Scheme != "https" {
-					validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSSchemeInvalid",
-						"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI has invalid scheme %q, must be http or https", jwksURL.Scheme)
+				switch {
+				case jwtProvider.RemoteJWKS != nil && jwtProvider.LocalJWKS != nil:
+					validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "JWKSSourceInvalid",
+						"Spec.VirtualHost.JWTProviders is invalid: at most one of RemoteJWKS and LocalJWKS can be specified")
 					return
-				}
-
-				var uv *PeerValidationContext
-
-				if jwtProvider.RemoteJWKS.UpstreamValidation != nil {
-					if jwksURL.Scheme == "http" {
-						validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSUpstreamValidationInvalid",
-							"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation must not be specified when URI scheme is http.")
-						return
-					}
-
-					caCertNamespacedName := k8s.NamespacedNameFrom(jwtProvider.RemoteJWKS.UpstreamValidation.CACertificate, k8s.DefaultNamespace(proxy.Namespace))
-					uv, err = p.source.LookupUpstreamValidation(jwtProvider.RemoteJWKS.UpstreamValidation, caCertNamespacedName, proxy.Namespace)
-					if err != nil {
-						if _, ok := err.(DelegationNotPermittedError); ok {
-							validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSCACertificateNotDelegated",
-								"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation.CACertificate Secret %q is not configured for certificate delegation", caCertNamespacedName)
-						} else {
-							validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSUpstreamValidationInvalid",
-								"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation is invalid: %s", err)
-						}
-						return
-					}
-				}
-
-				jwksTimeout := time.Second
-				if len(jwtProvider.RemoteJWKS.Timeout) > 0 {
-					res, err := time.ParseDuration(jwtProvider.RemoteJWKS.Timeout)
-					if err != nil {
-						validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSTimeoutInvalid",
-							"Spec.VirtualHost.JWTProviders.RemoteJWKS.Timeout is invalid: %s", err)
+				case jwtProvider.RemoteJWKS != nil:
+					remoteJWKS, ok := p.computeRemoteJWKS(jwtProvider.RemoteJWKS, validCond, proxy)
+					if !ok {
 						return
 					}
-
-					jwksTimeout = res
-				}
-
-				var cacheDuration *time.Duration
-				if len(jwtProvider.RemoteJWKS.CacheDuration) > 0 {
-					res, err := time.ParseDuration(jwtProvider.RemoteJWKS.CacheDuration)
+					provider.RemoteJWKS = remoteJWKS
+				case jwtProvider.LocalJWKS != nil:
+					jwks, err := p.source.LookupJWKS(jwtProvider.LocalJWKS, proxy.Namespace)
 					if err != nil {
-						validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSCacheDurationInvalid",
-							"Spec.VirtualHost.JWTProviders.RemoteJWKS.CacheDuration is invalid: %s", err)
+						validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "LocalJWKSInvalid",
+							"Spec.VirtualHost.JWTProviders.LocalJWKS %s %q is invalid: %s", localJWKSKind(jwtProvider.LocalJWKS), jwtProvider.LocalJWKS.Name, err)
 						return
 					}
-
-					cacheDuration = &res
-				}
-
-				// Check for a specified port and use it, else use the
-				// standard ports by scheme.
-				var port int
-				switch {
-				case len(jwksURL.Port()) > 0:
-					p, err := strconv.Atoi(jwksURL.Port())
-					if err != nil {
-						// This theoretically shouldn't be possible as jwksURL.Port() will
-						// only return a value if it's numeric, but we need to convert to
-						// int anyway so handle the error.
-						validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSPortInvalid",
-							"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI has an invalid port: %s", err)
-						return
-					}
-					port = p
-				case jwksURL.Scheme == "http":
-					port = 80
-				case jwksURL.Scheme == "https":
-					port = 443
-				}
-
-				// Get the DNS lookup family if specified, otherwise
-				// default to the Contour-wide setting.
-				dnsLookupFamily := ""
-				switch jwtProvider.RemoteJWKS.DNSLookupFamily {
-				case "auto", "v4", "v6", "all":
-					dnsLookupFamily = jwtProvider.RemoteJWKS.DNSLookupFamily
-				case "":
-					dnsLookupFamily = string(p.DNSLookupFamily)
+					provider.LocalJWKS = jwks
 				default:
-					validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSDNSLookupFamilyInvalid",
-						"Spec.VirtualHost.JWTProviders.RemoteJWKS.DNSLookupFamily has an invalid value %q, must be auto, all, v4 or v6", jwtProvider.RemoteJWKS.DNSLookupFamily)
+					validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "JWKSSourceInvalid",
+						"Spec.VirtualHost.JWTProviders is invalid: one of RemoteJWKS and LocalJWKS must be specified")
 					return
 				}
 
-				svhost.JWTProviders = append(svhost.JWTProviders, JWTProvider{
-					Name:      jwtProvider.Name,
-					Issuer:    jwtProvider.Issuer,
-					Audiences: jwtProvider.Audiences,
-					RemoteJWKS: RemoteJWKS{
-						URI:     jwtProvider.RemoteJWKS.URI,
-						Timeout: jwksTimeout,
-						Cluster: DNSNameCluster{
-							Address:            jwksURL.Hostname(),
-							Scheme:             jwksURL.Scheme,
-							Port:               port,
-							DNSLookupFamily:    dnsLookupFamily,
-							UpstreamValidation: uv,
-							UpstreamTLS:        p.UpstreamTLS,
-						},
-						CacheDuration: cacheDuration,
-					},
-					ForwardJWT: jwtProvider.ForwardJWT,
-				})
+				for _, claimToHeader := range jwtProvider.ClaimToHeaders {
+					provider.ClaimToHeaders = append(provider.ClaimToHeaders, JWTClaimToHeader{
+						HeaderName: claimToHeader.HeaderName,
+						Claim:      claimToHeader.Claim,
+					})
+				}
+
+				svhost.JWTProviders = append(svhost.JWTProviders, provider)
 			}
 		}
 	}
@@ -521,6 +507,25 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 
 	routes := p.computeRoutes(validCond, proxy, proxy, nil, nil, tlsEnabled, defaultJWTProvider)
 
+	rbacPolicy, ok := toRBACPolicy(proxy.Spec.VirtualHost.AuthorizationPolicy, "Spec.VirtualHost.AuthorizationPolicy", validCond)
+	if !ok {
+		return
+	}
+	if rbacPolicy != nil {
+		for _, route := range routes {
+			// Route policies override the virtual host policy.
+			if route.RBACPolicy != nil {
+				continue
+			}
+			if rbacPolicy.HasJWTClaims() && len(route.JWTProvider) == 0 {
+				validCond.AddError(contour_v1.ConditionTypeAuthorizationPolicyError, "AuthorizationPolicyNotValid",
+					"Spec.VirtualHost.AuthorizationPolicy cannot match JWT claims for routes that do not require a JWT provider")
+				return
+			}
+			route.RBACPolicy = rbacPolicy
+		}
+	}
+
 	listener, err := p.dag.GetSingleListener("http")
 	if err != nil {
 		validCond.AddError(contour_v1.ConditionTypeListenerError, "ErrorIdentifyingListener", err.Error())
@@ -600,9 +605,16 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 			// specifies a JWT provider that does not exist.
 			if len(route.JWTProvider) > 0 {
 				var found bool
-				for _, provider := range secure.JWTProviders {
+				for i, provider := range secure.JWTProviders {
 					if provider.Name == route.JWTProvider {
 						found = true
+
+						// Required claims and authorization policy claims
+						// are matched against the JWT payload in the
+						// request metadata.
+						if len(route.JWTRequiredClaims) > 0 || route.RBACPolicy.HasJWTClaims() {
+							secure.JWTProviders[i].PayloadInMetadata = true
+						}
 						break
 					}
 				}
@@ -614,9 +626,138 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 				}
 			}
 		}
+
+		// Global rate limit descriptors can read the claims of
+		// validated JWTs from the request metadata.
+		policies := []*RateLimitPolicy{secure.RateLimitPolicy}
+		for _, route := range routes {
+			policies = append(policies, route.RateLimitPolicy)
+		}
+		for _, policy := range policies {
+			for _, name := range policy.jwtPayloadProviders() {
+				for i := range secure.JWTProviders {
+					if secure.JWTProviders[i].Name == name {
+						secure.JWTProviders[i].PayloadInMetadata = true
+					}
+				}
+			}
+		}
 	}
 }
 
+// computeRemoteJWKS validates the given RemoteJWKS and returns the
+// DAG representation of it.
+func (p *HTTPProxyProcessor) computeRemoteJWKS(remoteJWKS *contour_v1.RemoteJWKS, validCond *contour_v1.DetailedCondition, proxy *contour_v1.HTTPProxy) (*RemoteJWKS, bool) {
+	jwksURL, err := url.Parse(remoteJWKS.URI)
+	if err != nil {
+		validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSURIInvalid",
+			"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI is invalid: %s", err)
+		return nil, false
+	}
+
+	if jwksURL.Scheme != "http" && jwksURL.Scheme != "https" {
+		validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSSchemeInvalid",
+			"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI has invalid scheme %q, must be http or https", jwksURL.Scheme)
+		return nil, false
+	}
+
+	var uv *PeerValidationContext
+
+	if remoteJWKS.UpstreamValidation != nil {
+		if jwksURL.Scheme == "http" {
+			validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSUpstreamValidationInvalid",
+				"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation must not be specified when URI scheme is http.")
+			return nil, false
+		}
+
+		caCertNamespacedName := k8s.NamespacedNameFrom(remoteJWKS.UpstreamValidation.CACertificate, k8s.DefaultNamespace(proxy.Namespace))
+		uv, err = p.source.LookupUpstreamValidation(remoteJWKS.UpstreamValidation, caCertNamespacedName, proxy.Namespace)
+		if err != nil {
+			if _, ok := err.(DelegationNotPermittedError); ok {
+				validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSCACertificateNotDelegated",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation.CACertificate Secret %q is not configured for certificate delegation", caCertNamespacedName)
+			} else {
+				validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSUpstreamValidationInvalid",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.UpstreamValidation is invalid: %s", err)
+			}
+			return nil, false
+		}
+	}
+
+	jwksTimeout := time.Second
+	if len(remoteJWKS.Timeout) > 0 {
+		res, err := time.ParseDuration(remoteJWKS.Timeout)
+		if err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSTimeoutInvalid",
+				"Spec.VirtualHost.JWTProviders.RemoteJWKS.Timeout is invalid: %s", err)
+			return nil, false
+		}
+
+		jwksTimeout = res
+	}
+
+	var cacheDuration *time.Duration
+	if len(remoteJWKS.CacheDuration) > 0 {
+		res, err := time.ParseDuration(remoteJWKS.CacheDuration)
+		if err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSCacheDurationInvalid",
+				"Spec.VirtualHost.JWTProviders.RemoteJWKS.CacheDuration is invalid: %s", err)
+			return nil, false
+		}
+
+		cacheDuration = &res
+	}
+
+	// Check for a specified port and use it, else use the
+	// standard ports by scheme.
+	var port int
+	switch {
+	case len(jwksURL.Port()) > 0:
+		p, err := strconv.Atoi(jwksURL.Port())
+		if err != nil {
+			// This theoretically shouldn't be possible as jwksURL.Port() will
+			// only return a value if it's numeric, but we need to convert to
+			// int anyway so handle the error.
+			validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSPortInvalid",
+				"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI has an invalid port: %s", err)
+			return nil, false
+		}
+		port = p
+	case jwksURL.Scheme == "http":
+		port = 80
+	case jwksURL.Scheme == "https":
+		port = 443
+	}
+
+	// Get the DNS lookup family if specified, otherwise
+	// default to the Contour-wide setting.
+	dnsLookupFamily := ""
+	switch remoteJWKS.DNSLookupFamily {
+	case "auto", "v4", "v6", "all":
+		dnsLookupFamily = remoteJWKS.DNSLookupFamily
+	case "":
+		dnsLookupFamily = string(p.DNSLookupFamily)
+	default:
+		validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSDNSLookupFamilyInvalid",
+			"Spec.VirtualHost.JWTProviders.RemoteJWKS.DNSLookupFamily has an invalid value %q, must be auto, all, v4 or v6", remoteJWKS.DNSLookupFamily)
+		return nil, false
+	}
+
+	return &RemoteJWKS{
+		URI:     remoteJWKS.URI,
+		Timeout: jwksTimeout,
+		Cluster: DNSNameCluster{
+			Address:            jwksURL.Hostname(),
+			Scheme:             jwksURL.Scheme,
+			Port:               port,
+			DNSLookupFamily:    dnsLookupFamily,
+			UpstreamValidation: uv,
+			UpstreamTLS:        p.UpstreamTLS,
+		},
+		CacheDuration: cacheDuration,
+	}, true
+}
+
 type vhost interface {
 	AddRoute(*Route)
 }
@@ -713,7 +854,7 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			continue
 		}
 
-		includedProxy, ok := p.source.httpproxies[types.NamespacedName{Name: include.Name, Namespace: namespace}]
+		includedProxy, ok := p.lookupHTTPProxy(types.NamespacedName{Name: include.Name, Namespace: namespace})
 		if !ok {
 			validCond.AddErrorf(contour_v1.ConditionTypeIncludeError, "IncludeNotFound",
 				"include %s/%s not found", namespace, include.Name)
@@ -737,7 +878,7 @@ func (p *HTTPProxyProcessor) computeRoutes(
 		incCommit()
 
 		// dest is not an orphaned httpproxy, as there is an httpproxy that points to it
-		delete(p.orphaned, types.NamespacedName{Name: includedProxy.Name, Namespace: includedProxy.Namespace})
+		p.claim(types.NamespacedName{Name: includedProxy.Name, Namespace: includedProxy.Namespace})
 	}
 
 	dynamicHeaders := map[string]string{
@@ -951,8 +1092,7 @@ func (p *HTTPProxyProcessor) computeRoutes(
 				healthPort = service.Port
 			}
 
-			m := types.NamespacedName{Name: service.Name, Namespace: proxy.Namespace}
-			s, err := p.dag.EnsureService(m, service.Port, healthPort, p.source, p.EnableExternalNameService)
+			s, err := p.ensureService(service, proxy.Namespace, healthPort)
 			if err != nil {
 				validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference",
 					"Spec.Routes unresolved service reference: %s", err)
@@ -1105,11 +1245,49 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			r.JWTProvider = defaultJWTProvider
 		}
 
+		if jwt != nil && len(jwt.RequiredClaims) > 0 {
+			if len(r.JWTProvider) == 0 {
+				validCond.AddError(contour_v1.ConditionTypeJWTVerificationError, "InvalidJWTVerificationPolicy",
+					"route's JWT verification policy cannot specify required claims without requiring a JWT provider")
+				return nil
+			}
+
+			for _, claim := range jwt.RequiredClaims {
+				r.JWTRequiredClaims = append(r.JWTRequiredClaims, JWTRequiredClaim{
+					Name:   claim.Name,
+					Values: claim.Values,
+				})
+			}
+		}
+
 		r.IPFilterAllow, r.IPFilterRules, err = toIPFilterRules(route.IPAllowFilterPolicy, route.IPDenyFilterPolicy, validCond)
 		if err != nil {
 			return nil
 		}
 
+		var ok bool
+		r.RBACPolicy, ok = toRBACPolicy(route.AuthorizationPolicy, "Spec.Routes.AuthorizationPolicy", validCond)
+		if !ok {
+			return nil
+		}
+		if r.RBACPolicy.HasJWTClaims() && len(r.JWTProvider) == 0 {
+			validCond.AddError(contour_v1.ConditionTypeAuthorizationPolicyError, "AuthorizationPolicyNotValid",
+				"Spec.Routes.AuthorizationPolicy cannot match JWT claims without requiring a JWT provider")
+			return nil
+		}
+
+		if route.TrafficShiftPolicy != nil {
+			canaryRoute, err := applyTrafficShiftPolicy(r, route, proxy)
+			if err != nil {
+				validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "TrafficShiftPolicyInvalid",
+					"route.trafficShiftPolicy is invalid: %s", err)
+				return nil
+			}
+			if canaryRoute != nil {
+				routes = append(routes, canaryRoute)
+			}
+		}
+
 		routes = append(routes, r)
 	}
 
@@ -1140,17 +1318,8 @@ func toIPFilterRules(allowPolicy, denyPolicy []contour_v1.IPFilterPolicy, validC
 	}
 	filters = make([]IPFilterRule, 0, len(ipPolicies))
 	for _, p := range ipPolicies {
-		// convert bare IPs to CIDRs
-		unparsedCIDR := p.CIDR
-		if !strings.Contains(unparsedCIDR, "/") {
-			if strings.Contains(unparsedCIDR, ":") {
-				unparsedCIDR += "/128"
-			} else {
-				unparsedCIDR += "/32"
-			}
-		}
 		var cidr *net.IPNet
-		_, cidr, err = net.ParseCIDR(unparsedCIDR)
+		cidr, err = parseIPFilterCIDR(p.CIDR)
 		if err != nil {
 			validCond.AddErrorf(contour_v1.ConditionTypeIPFilterError, "InvalidCIDR",
 				"%s failed to parse: %s", p.CIDR, err)
@@ -1168,6 +1337,143 @@ func toIPFilterRules(allowPolicy, denyPolicy []contour_v1.IPFilterPolicy, validC
 	return
 }
 
+// parseIPFilterCIDR parses a CIDR block, converting bare IPs to CIDRs.
+func parseIPFilterCIDR(unparsedCIDR string) (*net.IPNet, error) {
+	if !strings.Contains(unparsedCIDR, "/") {
+		if strings.Contains(unparsedCIDR, ":") {
+			unparsedCIDR += "/128"
+		} else {
+			unparsedCIDR += "/32"
+		}
+	}
+	_, cidr, err := net.ParseCIDR(unparsedCIDR)
+	return cidr, err
+}
+
+// toRBACPolicy converts an authorization policy from the api into the
+// dag representation. The field argument names the policy in any error
+// recorded on the condition.
+func toRBACPolicy(policy *contour_v1.RBACPolicy, field string, validCond *contour_v1.DetailedCondition) (*RBACPolicy, bool) {
+	if policy == nil {
+		return nil, true
+	}
+
+	if len(policy.Allow) == 0 && len(policy.Deny) == 0 {
+		validCond.AddErrorf(contour_v1.ConditionTypeAuthorizationPolicyError, "AuthorizationPolicyNotValid",
+			"%s is invalid: at least one allow or deny rule must be specified", field)
+		return nil, false
+	}
+
+	rbac := &RBACPolicy{}
+	for i, rule := range policy.Allow {
+		r, err := toRBACRule(rule)
+		if err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeAuthorizationPolicyError, "AuthorizationPolicyNotValid",
+				"%s.Allow[%d] is invalid: %s", field, i, err)
+			return nil, false
+		}
+		rbac.Allow = append(rbac.Allow, r)
+	}
+	for i, rule := range policy.Deny {
+		r, err := toRBACRule(rule)
+		if err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeAuthorizationPolicyError, "AuthorizationPolicyNotValid",
+				"%s.Deny[%d] is invalid: %s", field, i, err)
+			return nil, false
+		}
+		rbac.Deny = append(rbac.Deny, r)
+	}
+
+	return rbac, true
+}
+
+func toRBACRule(rule contour_v1.RBACRule) (RBACRule, error) {
+	var r RBACRule
+
+	if len(rule.Methods) == 0 && len(rule.Paths) == 0 && len(rule.Headers) == 0 &&
+		rule.ClientCertificate == nil && len(rule.JWTClaims) == 0 && len(rule.SourceIPs) == 0 {
+		return r, errors.New("at least one condition must be specified")
+	}
+
+	for _, method := range rule.Methods {
+		if len(method) == 0 {
+			return r, errors.New("methods cannot be empty")
+		}
+		r.Methods = append(r.Methods, method)
+	}
+
+	for _, path := range rule.Paths {
+		switch {
+		case len(path.Prefix) > 0 && len(path.Exact) == 0 && len(path.Regex) == 0:
+			if path.Prefix[0] != '/' {
+				return r, fmt.Errorf("prefix %q must start with /", path.Prefix)
+			}
+			r.Paths = append(r.Paths, &PrefixMatchCondition{Prefix: path.Prefix})
+		case len(path.Exact) > 0 && len(path.Prefix) == 0 && len(path.Regex) == 0:
+			if path.Exact[0] != '/' {
+				return r, fmt.Errorf("exact path %q must start with /", path.Exact)
+			}
+			r.Paths = append(r.Paths, &ExactMatchCondition{Path: path.Exact})
+		case len(path.Regex) > 0 && len(path.Prefix) == 0 && len(path.Exact) == 0:
+			if err := ValidateRegex(path.Regex); err != nil {
+				return r, fmt.Errorf("path regex %q is invalid: %s", path.Regex, err)
+			}
+			r.Paths = append(r.Paths, &RegexMatchCondition{Regex: path.Regex})
+		default:
+			return r, errors.New("exactly one of prefix, exact or regex must be specified for a path")
+		}
+	}
+
+	if len(rule.Headers) > 0 {
+		conds := make([]contour_v1.MatchCondition, 0, len(rule.Headers))
+		for i := range rule.Headers {
+			conds = append(conds, contour_v1.MatchCondition{Header: &rule.Headers[i]})
+		}
+		if err := headerMatchConditionsValid(conds); err != nil {
+			return r, err
+		}
+		r.Headers = headerMatchConditions(rule.Headers)
+		if len(r.Headers) != len(rule.Headers) {
+			return r, errors.New("each header condition must specify a match type")
+		}
+	}
+
+	if rule.ClientCertificate != nil {
+		if len(rule.ClientCertificate.Names) == 0 {
+			return r, errors.New("client certificate names must be specified")
+		}
+		for _, name := range rule.ClientCertificate.Names {
+			if len(name) == 0 {
+				return r, errors.New("client certificate names cannot be empty")
+			}
+		}
+		r.ClientCertificateNames = rule.ClientCertificate.Names
+	}
+
+	for _, claim := range rule.JWTClaims {
+		if len(claim.Name) == 0 || len(claim.Values) == 0 {
+			return r, errors.New("JWT claims must specify a name and at least one value")
+		}
+		r.JWTClaims = append(r.JWTClaims, JWTRequiredClaim{
+			Name:   claim.Name,
+			Values: claim.Values,
+		})
+	}
+
+	for _, source := range rule.SourceIPs {
+		cidr, err := parseIPFilterCIDR(source.CIDR)
+		if err != nil {
+			return r, fmt.Errorf("source ip %s failed to parse: %s", source.CIDR, err)
+		}
+		r.SourceIPs = append(r.SourceIPs, IPFilterRule{
+			Remote: source.Source == contour_v1.IPFilterSourceRemote,
+			CIDR:   *cidr,
+		})
+	}
+
+	return r, nil
+}
+
 // processHTTPProxyTCPProxy processes the spec.tcpproxy stanza in a HTTPProxy document
 // following the chain of spec.tcpproxy.include references. It returns true if processing
 // was successful, otherwise false if an error was encountered. The details of the error
@@ -1205,7 +1511,36 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 	}
 
 	if len(tcpproxy.Services) > 0 {
-		var proxy TCPProxy
+		idleTimeout, err := timeout.Parse(tcpproxy.IdleTimeout)
+		if err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyError, "IdleTimeoutInvalid",
+				"Spec.TCPProxy.IdleTimeout is invalid: %s", err)
+			return false
+		}
+
+		proxy := TCPProxy{
+			IdleTimeout:        idleTimeout,
+			MaxConnectAttempts: tcpproxy.MaxConnectAttempts,
+		}
+
+		if p.SetSourceMetadataOnRoutes {
+			proxy.SourceKind = "HTTPProxy"
+			proxy.SourceNamespace = httpproxy.Namespace
+			proxy.SourceName = httpproxy.Name
+		}
+
+		if al := tcpproxy.AccessLog; al != nil {
+			if err := contour_v1alpha1.AccessLogFormatString(al.Format).Validate(); err != nil {
+				validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyError, "AccessLogFormatInvalid",
+					"Spec.TCPProxy.AccessLog.Format is invalid: %s", err)
+				return false
+			}
+			proxy.AccessLog = &TCPProxyAccessLog{
+				Disabled: al.Disabled,
+				Format:   al.Format,
+			}
+		}
+
 		for _, service := range httpproxy.Spec.TCPProxy.Services {
 			var healthPort int
 			healthPolicy := tcpHealthCheckPolicy(tcpproxy.HealthCheckPolicy)
@@ -1215,8 +1550,7 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 				healthPort = service.Port
 			}
 
-			m := types.NamespacedName{Name: service.Name, Namespace: httpproxy.Namespace}
-			s, err := p.dag.EnsureService(m, service.Port, healthPort, p.source, p.EnableExternalNameService)
+			s, err := p.ensureService(service, httpproxy.Namespace, healthPort)
 			if err != nil {
 				validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyError, "ServiceUnresolvedReference",
 					"Spec.TCPProxy unresolved service reference: %s", err)
@@ -1250,16 +1584,17 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 			}
 
 			proxy.Clusters = append(proxy.Clusters, &Cluster{
-				Upstream:             s,
-				Weight:               uint32(service.Weight), //nolint:gosec // disable G115
-				Protocol:             protocol,
-				LoadBalancerPolicy:   lbPolicy,
-				TCPHealthCheckPolicy: healthPolicy,
-				SNI:                  s.ExternalName,
-				TimeoutPolicy:        ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
-				UpstreamTLS:          p.UpstreamTLS,
-				UpstreamValidation:   uv,
-				ClientCertificate:    clientCertSecret,
+				Upstream:              s,
+				Weight:                uint32(service.Weight), //nolint:gosec // disable G115
+				Protocol:              protocol,
+				LoadBalancerPolicy:    lbPolicy,
+				TCPHealthCheckPolicy:  healthPolicy,
+				SNI:                   s.ExternalName,
+				TimeoutPolicy:         ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+				UpstreamTLS:           p.UpstreamTLS,
+				UpstreamValidation:    uv,
+				ClientCertificate:     clientCertSecret,
+				UpstreamProxyProtocol: tcpproxy.UpstreamProxyProtocol,
 			})
 		}
 
@@ -1289,7 +1624,7 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 	}
 
 	m := types.NamespacedName{Name: tcpProxyInclude.Name, Namespace: namespace}
-	dest, ok := p.source.httpproxies[m]
+	dest, ok := p.lookupHTTPProxy(m)
 	if !ok {
 		validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyIncludeError, "IncludeNotFound",
 			"include %s/%s not found", m.Namespace, m.Name)
@@ -1304,7 +1639,7 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 	}
 
 	// dest is no longer an orphan
-	delete(p.orphaned, k8s.NamespacedNameOf(dest))
+	p.claim(k8s.NamespacedNameOf(dest))
 
 	// ensure we are not following an edge that produces a cycle
 	var path []string
@@ -1386,12 +1721,19 @@ func (p *HTTPProxyProcessor) computeVirtualHostAuthorization(auth *contour_v1.Au
 	ok, ext := validateExternalAuthExtensionService(defaultExtensionRef(auth.ExtensionServiceRef),
 		validCond,
 		httpproxy,
-		p.dag.GetExtensionCluster,
+		p.getExtensionCluster,
 	)
 	if !ok {
 		return nil
 	}
 
+	// The gRPC authorization API needs HTTP/2.
+	if auth.HTTPService == nil && ext.Protocol != "h2" && ext.Protocol != "h2c" {
+		validCond.AddErrorf(contour_v1.ConditionTypeAuthError, "AuthProtocolNotValid",
+			"Spec.Virtualhost.Authorization.ServiceRef extension service uses the %q protocol, which is only supported with httpService", ext.Protocol)
+		return nil
+	}
+
 	ok, respTimeout := determineExternalAuthTimeout(auth.ResponseTimeout, validCond, ext)
 	if !ok {
 		return nil
@@ -1414,9 +1756,37 @@ func (p *HTTPProxyProcessor) computeVirtualHostAuthorization(auth *contour_v1.Au
 			PackAsBytes:         auth.WithRequestBody.PackAsBytes,
 		}
 	}
+
+	if auth.HTTPService != nil {
+		globalExternalAuthorization.AuthorizationHTTPService = &AuthorizationHTTPService{
+			PathPrefix: auth.HTTPService.PathPrefix,
+		}
+	}
+
+	globalExternalAuthorization.AuthorizationForwarding = authorizationForwarding(auth)
 	return globalExternalAuthorization
 }
 
+// authorizationForwarding returns the headers and metadata exchanged
+// with the authorization server, or nil if they are not restricted.
+func authorizationForwarding(auth *contour_v1.AuthorizationServer) *AuthorizationForwarding {
+	if len(auth.AllowedRequestHeaders) == 0 &&
+		len(auth.AllowedUpstreamHeaders) == 0 &&
+		len(auth.AllowedClientHeaders) == 0 &&
+		len(auth.MetadataNamespaces) == 0 &&
+		len(auth.RouteMetadataNamespaces) == 0 {
+		return nil
+	}
+
+	return &AuthorizationForwarding{
+		AllowedRequestHeaders:   auth.AllowedRequestHeaders,
+		AllowedUpstreamHeaders:  auth.AllowedUpstreamHeaders,
+		AllowedClientHeaders:    auth.AllowedClientHeaders,
+		MetadataNamespaces:      auth.MetadataNamespaces,
+		RouteMetadataNamespaces: auth.RouteMetadataNamespaces,
+	}
+}
+
 func validateExternalAuthExtensionService(ref contour_v1.ExtensionServiceReference, validCond *contour_v1.DetailedCondition, httpproxy *contour_v1.HTTPProxy, getExtensionCluster func(name string) *ExtensionCluster) (bool, *ExtensionCluster) {
 	if ref.APIVersion != contour_v1alpha1.GroupVersion.String() {
 		validCond.AddErrorf(contour_v1.ConditionTypeAuthError, "AuthBadResourceVersion",
@@ -1522,6 +1892,16 @@ func (p *HTTPProxyProcessor) GlobalAuthorizationContext() map[string]string {
 	return nil
 }
 
+// ensureService returns the DAG service for the Service, or the
+// multi-cluster ServiceImport, referenced by service.
+func (p *HTTPProxyProcessor) ensureService(service contour_v1.Service, namespace string, healthPort int) (*Service, error) {
+	m := types.NamespacedName{Name: service.Name, Namespace: namespace}
+	if service.Kind == KindServiceImport {
+		return p.dag.EnsureServiceImport(m, service.Port, healthPort, p.source)
+	}
+	return p.dag.EnsureService(m, service.Port, healthPort, p.source, p.EnableExternalNameService)
+}
+
 func (p *HTTPProxyProcessor) peerValidationContext(validCond *contour_v1.DetailedCondition, httpproxy *contour_v1.HTTPProxy, service contour_v1.Service) *PeerValidationContext {
 	caCertNamespacedName := k8s.NamespacedNameFrom(service.UpstreamValidation.CACertificate, k8s.DefaultNamespace(httpproxy.Namespace))
 	// we can only validate TLS connections to services that talk TLS
@@ -1989,6 +2369,123 @@ func directResponsePolicy(direct *contour_v1.HTTPDirectResponsePolicy) *DirectRe
 	return directResponse(uint32(direct.StatusCode), direct.Body) //nolint:gosec // disable G115
 }
 
+// applyTrafficShiftPolicy replaces the stable and canary clusters of r
+// with a single cluster balancing its requests between the endpoints of
+// both services, weighted by the current step of the traffic shift
+// policy of the route, which the traffic shift controller records in
+// the status of the HTTPProxy. Until the controller records it for the
+// current version of the policy, the first step applies. If the policy
+// has a header, it also returns a route sending the requests that match
+// the header to the canary, unless the shift was aborted.
+func applyTrafficShiftPolicy(r *Route, route contour_v1.Route, proxy *contour_v1.HTTPProxy) (*Route, error) {
+	policy := route.TrafficShiftPolicy
+
+	if policy.Stable == policy.Canary {
+		return nil, errors.New("stable and canary must be different services")
+	}
+	if len(policy.Steps) == 0 {
+		return nil, errors.New("at least one step must be specified")
+	}
+	for _, step := range policy.Steps {
+		if step > 100 {
+			return nil, fmt.Errorf("step %d is not a percentage", step)
+		}
+	}
+	if _, err := policy.StepInterval(); err != nil {
+		return nil, err
+	}
+
+	var stables, canaries int
+	for _, service := range route.Services {
+		switch {
+		case service.Mirror:
+			continue
+		case service.Name == policy.Stable:
+			stables++
+		case service.Name == policy.Canary:
+			canaries++
+		default:
+			return nil, fmt.Errorf("service %q is neither the stable nor the canary service", service.Name)
+		}
+	}
+	switch {
+	case stables == 0:
+		return nil, fmt.Errorf("stable service %q is not a service of the route", policy.Stable)
+	case canaries == 0:
+		return nil, fmt.Errorf("canary service %q is not a service of the route", policy.Canary)
+	case stables > 1 || canaries > 1:
+		return nil, errors.New("stable and canary services must each be listed once")
+	}
+
+	var header []contour_v1.MatchCondition
+	if policy.Header != nil {
+		header = []contour_v1.MatchCondition{{Header: policy.Header}}
+		if err := headerMatchConditionsValid(header); err != nil {
+			return nil, err
+		}
+	}
+
+	var stable, canary *Cluster
+	for _, c := range r.Clusters {
+		if c.Upstream.Weighted.ServiceName == policy.Canary {
+			canary = c
+		} else {
+			stable = c
+		}
+	}
+	if err := trafficShiftable(stable, canary); err != nil {
+		return nil, err
+	}
+
+	weight, aborted := policy.Steps[0], false
+	if shift := proxy.Status.GetTrafficShiftFor(policy); shift != nil {
+		weight, aborted = min(shift.Weight, 100), shift.Phase == contour_v1.TrafficShiftPhaseAborted
+	}
+
+	shifted := *stable
+	shifted.TrafficShift = &TrafficShift{
+		ClusterLoadAssignmentName: xds.TrafficShiftClusterLoadAssignmentName(k8s.NamespacedNameOf(proxy),
+			policy.Stable, policy.Canary, stable.Upstream.Weighted.ServicePort.Port, canary.Upstream.Weighted.ServicePort.Port),
+		Canary: canary.Upstream,
+		Weight: weight,
+	}
+	r.Clusters = []*Cluster{&shifted}
+
+	if header == nil || aborted {
+		return nil, nil
+	}
+
+	canaryRoute := *r
+	canaryRoute.HeaderMatchConditions = append(slices.Clone(r.HeaderMatchConditions), mergeHeaderMatchConditions(header)...)
+	canaryRoute.Clusters = []*Cluster{canary}
+	return &canaryRoute, nil
+}
+
+// trafficShiftable returns an error if the requests of the stable
+// cluster can't be shifted to the canary cluster. Both services are
+// balanced by the same Envoy cluster, which can only be configured
+// one way, and is weighted by locality, which Envoy does not support
+// with the ring hash load balancer.
+func trafficShiftable(stable, canary *Cluster) error {
+	if stable.Upstream.ExternalName != "" || canary.Upstream.ExternalName != "" {
+		return errors.New("ExternalName services can't be shifted")
+	}
+
+	switch stable.LoadBalancerPolicy {
+	case LoadBalancerPolicyCookie, LoadBalancerPolicyRequestHash:
+		return fmt.Errorf("the %s load balancer strategy can't be shifted", stable.LoadBalancerPolicy)
+	}
+
+	s, c := *stable, *canary
+	s.Upstream, c.Upstream = nil, nil
+	s.Weight, c.Weight = 0, 0
+	if !reflect.DeepEqual(s, c) || !reflect.DeepEqual(stable.Upstream.CircuitBreakers, canary.Upstream.CircuitBreakers) {
+		return errors.New("stable and canary services must have the same protocol, validation, health check, header, cookie, slow start and circuit breaker settings")
+	}
+
+	return nil
+}
+
 func internalRedirectPolicy(internal *contour_v1.HTTPInternalRedirectPolicy) *InternalRedirectPolicy {
 	if internal == nil {
 		return nil
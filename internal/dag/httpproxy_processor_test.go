@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
@@ -991,6 +992,169 @@ func TestToIPFilterRule(t *testing.T) {
 	}
 }
 
+func TestToRBACPolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy            *contour_v1.RBACPolicy
+		want              *RBACPolicy
+		wantOK            bool
+		wantConditionErrs []contour_v1.SubCondition
+	}{
+		"no policy": {
+			policy: nil,
+			want:   nil,
+			wantOK: true,
+		},
+		"no rules": {
+			policy: &contour_v1.RBACPolicy{},
+			wantConditionErrs: []contour_v1.SubCondition{{
+				Type:    "AuthorizationPolicyError",
+				Status:  "True",
+				Reason:  "AuthorizationPolicyNotValid",
+				Message: "Spec.VirtualHost.AuthorizationPolicy is invalid: at least one allow or deny rule must be specified",
+			}},
+		},
+		"rule without conditions": {
+			policy: &contour_v1.RBACPolicy{
+				Deny: []contour_v1.RBACRule{{}},
+			},
+			wantConditionErrs: []contour_v1.SubCondition{{
+				Type:    "AuthorizationPolicyError",
+				Status:  "True",
+				Reason:  "AuthorizationPolicyNotValid",
+				Message: "Spec.VirtualHost.AuthorizationPolicy.Deny[0] is invalid: at least one condition must be specified",
+			}},
+		},
+		"path with multiple match types": {
+			policy: &contour_v1.RBACPolicy{
+				Allow: []contour_v1.RBACRule{{
+					Paths: []contour_v1.RBACPathMatch{{Prefix: "/foo", Exact: "/foo"}},
+				}},
+			},
+			wantConditionErrs: []contour_v1.SubCondition{{
+				Type:    "AuthorizationPolicyError",
+				Status:  "True",
+				Reason:  "AuthorizationPolicyNotValid",
+				Message: "Spec.VirtualHost.AuthorizationPolicy.Allow[0] is invalid: exactly one of prefix, exact or regex must be specified for a path",
+			}},
+		},
+		"invalid path regex": {
+			policy: &contour_v1.RBACPolicy{
+				Allow: []contour_v1.RBACRule{{
+					Paths: []contour_v1.RBACPathMatch{{Regex: "/foo["}},
+				}},
+			},
+			wantConditionErrs: []contour_v1.SubCondition{{
+				Type:    "AuthorizationPolicyError",
+				Status:  "True",
+				Reason:  "AuthorizationPolicyNotValid",
+				Message: "Spec.VirtualHost.AuthorizationPolicy.Allow[0] is invalid: path regex \"/foo[\" is invalid: error parsing regexp: missing closing ]: `[`",
+			}},
+		},
+		"contradictory header conditions": {
+			policy: &contour_v1.RBACPolicy{
+				Allow: []contour_v1.RBACRule{{
+					Headers: []contour_v1.HeaderMatchCondition{
+						{Name: "x-foo", Present: true},
+						{Name: "x-foo", NotPresent: true},
+					},
+				}},
+			},
+			wantConditionErrs: []contour_v1.SubCondition{{
+				Type:    "AuthorizationPolicyError",
+				Status:  "True",
+				Reason:  "AuthorizationPolicyNotValid",
+				Message: "Spec.VirtualHost.AuthorizationPolicy.Allow[0] is invalid: cannot specify contradictory 'present' and 'notpresent' conditions for the same route and header",
+			}},
+		},
+		"invalid source ip": {
+			policy: &contour_v1.RBACPolicy{
+				Deny: []contour_v1.RBACRule{{
+					SourceIPs: []contour_v1.IPFilterPolicy{{
+						Source: contour_v1.IPFilterSourcePeer,
+						CIDR:   "2.2.2.2/512",
+					}},
+				}},
+			},
+			wantConditionErrs: []contour_v1.SubCondition{{
+				Type:    "AuthorizationPolicyError",
+				Status:  "True",
+				Reason:  "AuthorizationPolicyNotValid",
+				Message: "Spec.VirtualHost.AuthorizationPolicy.Deny[0] is invalid: source ip 2.2.2.2/512 failed to parse: invalid CIDR address: 2.2.2.2/512",
+			}},
+		},
+		"parses allow and deny rules": {
+			policy: &contour_v1.RBACPolicy{
+				Allow: []contour_v1.RBACRule{{
+					Methods: []string{"GET", "HEAD"},
+					Paths: []contour_v1.RBACPathMatch{
+						{Prefix: "/api"},
+						{Exact: "/health"},
+						{Regex: "/v[0-9]+/.*"},
+					},
+					Headers: []contour_v1.HeaderMatchCondition{
+						{Name: "x-tenant", Exact: "acme"},
+					},
+				}, {
+					ClientCertificate: &contour_v1.RBACClientCertificateMatch{
+						Names: []string{"spiffe://cluster.local/ns/admin/sa/admin"},
+					},
+					JWTClaims: []contour_v1.JWTRequiredClaim{{
+						Name:   "groups",
+						Values: []string{"admin"},
+					}},
+				}},
+				Deny: []contour_v1.RBACRule{{
+					SourceIPs: []contour_v1.IPFilterPolicy{{
+						Source: contour_v1.IPFilterSourceRemote,
+						CIDR:   "10.0.0.1",
+					}},
+				}},
+			},
+			wantOK: true,
+			want: &RBACPolicy{
+				Allow: []RBACRule{{
+					Methods: []string{"GET", "HEAD"},
+					Paths: []MatchCondition{
+						&PrefixMatchCondition{Prefix: "/api"},
+						&ExactMatchCondition{Path: "/health"},
+						&RegexMatchCondition{Regex: "/v[0-9]+/.*"},
+					},
+					Headers: []HeaderMatchCondition{{
+						Name:      "x-tenant",
+						Value:     "acme",
+						MatchType: HeaderMatchTypeExact,
+					}},
+				}, {
+					ClientCertificateNames: []string{"spiffe://cluster.local/ns/admin/sa/admin"},
+					JWTClaims: []JWTRequiredClaim{{
+						Name:   "groups",
+						Values: []string{"admin"},
+					}},
+				}},
+				Deny: []RBACRule{{
+					SourceIPs: []IPFilterRule{{
+						Remote: true,
+						CIDR: net.IPNet{
+							IP:   net.ParseIP("10.0.0.1").To4(),
+							Mask: net.CIDRMask(32, 32),
+						},
+					}},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cond := contour_v1.DetailedCondition{}
+			got, gotOK := toRBACPolicy(tc.policy, "Spec.VirtualHost.AuthorizationPolicy", &cond)
+			require.Equal(t, tc.wantOK, gotOK)
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.wantConditionErrs, cond.Errors)
+		})
+	}
+}
+
 func TestValidateVirtualHostRateLimitPolicy(t *testing.T) {
 	tests := map[string]struct {
 		rateLimitServiceConfig *contour_v1alpha1.RateLimitServiceConfig
@@ -1523,8 +1687,205 @@ func TestDetermineUpstreamTLS(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := (*UpstreamTLS)(tc.envoyTLS)
+			var got *UpstreamTLS
+			if tc.envoyTLS != nil {
+				// OCSPStaplePolicy only applies to listeners.
+				got = &UpstreamTLS{
+					MinimumProtocolVersion: tc.envoyTLS.MinimumProtocolVersion,
+					MaximumProtocolVersion: tc.envoyTLS.MaximumProtocolVersion,
+					CipherSuites:           tc.envoyTLS.CipherSuites,
+				}
+			}
 			assert.Equal(t, tc.want, got)
 		})
 	}
 }
+
+func TestApplyTrafficShiftPolicy(t *testing.T) {
+	services := []contour_v1.Service{
+		{Name: "stable", Port: 80},
+		{Name: "canary", Port: 80},
+	}
+
+	route := func(policy *contour_v1.TrafficShiftPolicy, services []contour_v1.Service) contour_v1.Route {
+		return contour_v1.Route{
+			Services:           services,
+			TrafficShiftPolicy: policy,
+		}
+	}
+
+	tests := map[string]struct {
+		route         contour_v1.Route
+		shifts        []contour_v1.TrafficShiftStatus
+		mutate        func(stable, canary *Cluster)
+		wantWeight    uint32
+		wantHeaderMCs []HeaderMatchCondition
+		wantErr       bool
+	}{
+		"first step applies without status": {
+			route:      route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10, 50}}, services),
+			wantWeight: 10,
+		},
+		"weight recorded in status applies": {
+			route: route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10, 50}}, services),
+			shifts: []contour_v1.TrafficShiftStatus{{
+				Stable:     "stable",
+				Canary:     "canary",
+				PolicyHash: (&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10, 50}}).Hash(),
+				Step:       1,
+				Weight:     50,
+				Phase:      contour_v1.TrafficShiftPhaseProgressing,
+			}},
+			wantWeight: 50,
+		},
+		"status of a previous policy is ignored": {
+			route: route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{20, 50}}, services),
+			shifts: []contour_v1.TrafficShiftStatus{{
+				Stable:     "stable",
+				Canary:     "canary",
+				PolicyHash: (&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10, 50}}).Hash(),
+				Step:       1,
+				Weight:     50,
+				Phase:      contour_v1.TrafficShiftPhaseProgressing,
+			}},
+			wantWeight: 20,
+		},
+		"aborted shift sends nothing to the canary": {
+			route: route(&contour_v1.TrafficShiftPolicy{
+				Stable: "stable",
+				Canary: "canary",
+				Steps:  []uint32{10, 50},
+				Header: &contour_v1.HeaderMatchCondition{Name: "x-canary", Exact: "true"},
+			}, services),
+			shifts: []contour_v1.TrafficShiftStatus{{
+				Stable: "stable",
+				Canary: "canary",
+				PolicyHash: (&contour_v1.TrafficShiftPolicy{
+					Stable: "stable",
+					Canary: "canary",
+					Steps:  []uint32{10, 50},
+					Header: &contour_v1.HeaderMatchCondition{Name: "x-canary", Exact: "true"},
+				}).Hash(),
+				Phase: contour_v1.TrafficShiftPhaseAborted,
+			}},
+			wantWeight: 0,
+		},
+		"header sends matching requests to the canary": {
+			route: route(&contour_v1.TrafficShiftPolicy{
+				Stable: "stable",
+				Canary: "canary",
+				Steps:  []uint32{10},
+				Header: &contour_v1.HeaderMatchCondition{Name: "x-canary", Exact: "true"},
+			}, services),
+			wantWeight: 10,
+			wantHeaderMCs: []HeaderMatchCondition{{
+				Name:      "x-canary",
+				Value:     "true",
+				MatchType: HeaderMatchTypeExact,
+			}},
+		},
+		"stable and canary are the same": {
+			route:   route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "stable", Steps: []uint32{10}}, services),
+			wantErr: true,
+		},
+		"step above 100": {
+			route:   route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{101}}, services),
+			wantErr: true,
+		},
+		"invalid interval": {
+			route:   route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10}, Interval: "soon"}, services),
+			wantErr: true,
+		},
+		"canary is not a service of the route": {
+			route:   route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "other", Steps: []uint32{10}}, services),
+			wantErr: true,
+		},
+		"route has a third service": {
+			route: route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10}},
+				append(services, contour_v1.Service{Name: "other", Port: 80})),
+			wantErr: true,
+		},
+		"stable listed twice": {
+			route: route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10}},
+				append(services, contour_v1.Service{Name: "stable", Port: 8080})),
+			wantErr: true,
+		},
+		"ExternalName canary": {
+			route: route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10}}, services),
+			mutate: func(_, canary *Cluster) {
+				canary.Upstream.ExternalName = "canary.example.com"
+			},
+			wantErr: true,
+		},
+		"ring hash load balancer": {
+			route: route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10}}, services),
+			mutate: func(stable, canary *Cluster) {
+				stable.LoadBalancerPolicy = LoadBalancerPolicyCookie
+				canary.LoadBalancerPolicy = LoadBalancerPolicyCookie
+			},
+			wantErr: true,
+		},
+		"stable and canary protocols differ": {
+			route: route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{10}}, services),
+			mutate: func(_, canary *Cluster) {
+				canary.Protocol = "h2c"
+			},
+			wantErr: true,
+		},
+		"mirror service is ignored": {
+			route: route(&contour_v1.TrafficShiftPolicy{Stable: "stable", Canary: "canary", Steps: []uint32{20}},
+				append(services, contour_v1.Service{Name: "mirror", Port: 80, Mirror: true})),
+			wantWeight: 20,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stable := &Cluster{Upstream: &Service{Weighted: WeightedService{
+				ServiceName:      "stable",
+				ServiceNamespace: "default",
+				ServicePort:      core_v1.ServicePort{Port: 80},
+			}}}
+			canary := &Cluster{Upstream: &Service{Weighted: WeightedService{
+				ServiceName:      "canary",
+				ServiceNamespace: "default",
+				ServicePort:      core_v1.ServicePort{Port: 8080},
+			}}}
+			if tc.mutate != nil {
+				tc.mutate(stable, canary)
+			}
+			r := &Route{Clusters: []*Cluster{stable, canary}}
+
+			proxy := &contour_v1.HTTPProxy{
+				ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "proxy"},
+				Status:     contour_v1.HTTPProxyStatus{TrafficShifts: tc.shifts},
+			}
+
+			canaryRoute, err := applyTrafficShiftPolicy(r, tc.route, proxy)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// The stable and canary are weighted by EDS,
+			// through a cluster of their own.
+			require.Len(t, r.Clusters, 1)
+			assert.Equal(t, stable.Upstream, r.Clusters[0].Upstream)
+			assert.Equal(t, &TrafficShift{
+				ClusterLoadAssignmentName: "trafficshift/default/proxy/stable/canary/80/8080",
+				Canary:                    canary.Upstream,
+				Weight:                    tc.wantWeight,
+			}, r.Clusters[0].TrafficShift)
+
+			if tc.wantHeaderMCs == nil {
+				assert.Nil(t, canaryRoute)
+				return
+			}
+			require.NotNil(t, canaryRoute)
+			assert.Equal(t, tc.wantHeaderMCs, canaryRoute.HeaderMatchConditions)
+			assert.Empty(t, r.HeaderMatchConditions)
+			assert.Equal(t, []*Cluster{canary}, canaryRoute.Clusters)
+		})
+	}
+}
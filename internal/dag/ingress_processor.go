@@ -14,6 +14,9 @@
 package dag
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/annotation"
 	"github.com/projectcontour/contour/internal/k8s"
@@ -97,7 +101,7 @@ func (p *IngressProcessor) Run(dag *DAG, source *KubernetesCache) {
 // computeSecureVirtualhosts populates tls parameters of
 // secure virtual hosts.
 func (p *IngressProcessor) computeSecureVirtualhosts() {
-	for _, ing := range p.source.ingresses {
+	for _, ing := range sortIngresses(p.source.ingresses) {
 		for _, tls := range ing.Spec.TLS {
 			secretName := k8s.NamespacedNameFrom(tls.SecretName, k8s.TLSCertAnnotationNamespace(ing), k8s.DefaultNamespace(ing.GetNamespace()))
 			sec, err := p.source.LookupTLSSecret(secretName, ing.GetNamespace())
@@ -158,17 +162,75 @@ func (p *IngressProcessor) computeSecureVirtualhosts() {
 
 func (p *IngressProcessor) computeIngresses() {
 	// deconstruct each ingress into routes and virtualhost entries
-	for _, ing := range p.source.ingresses {
+	for _, ing := range sortIngresses(p.source.ingresses) {
+		// Routes are not added for Ingresses with invalid
+		// annotations, rather than ignoring policies such
+		// as IP filters.
+		validCond := &contour_v1.DetailedCondition{}
+		policy := ingressPolicy(ing, validCond)
+		if len(validCond.Errors) > 0 {
+			for _, e := range validCond.Errors {
+				p.recordError(ing, e.Reason, e.Message)
+			}
+			continue
+		}
 
 		// rewrite the default ingress to a stock ingress rule.
 		rules := rulesFromSpec(ing.Spec)
 		for _, rule := range rules {
-			p.computeIngressRule(ing, rule)
+			p.computeIngressRule(ing, rule, policy)
+		}
+	}
+}
+
+// sortIngresses sorts ingresses based on creationTimestamp in ascending order
+// if creationTimestamps are the same, sort based on namespaced name ("<namespace>/<name>") in alphetical ascending order,
+// so that the oldest Ingress wins when Ingresses set conflicting policies on a virtual host.
+func sortIngresses(m map[types.NamespacedName]*networking_v1.Ingress) []*networking_v1.Ingress {
+	ingresses := []*networking_v1.Ingress{}
+	for _, ing := range m {
+		ingresses = append(ingresses, ing)
+	}
+	sort.SliceStable(ingresses, func(i, j int) bool {
+		// if the creation time is the same, compare the ingress name
+		if ingresses[i].CreationTimestamp.Equal(&ingresses[j].CreationTimestamp) {
+			return k8s.NamespacedNameOf(ingresses[i]).String() <
+				k8s.NamespacedNameOf(ingresses[j]).String()
 		}
+		return ingresses[i].CreationTimestamp.Before(&ingresses[j].CreationTimestamp)
+	})
+
+	return ingresses
+}
+
+// recordError logs an error with the given Ingress and adds it to
+// the status cache, to be recorded as a warning event on the Ingress.
+func (p *IngressProcessor) recordError(ing *networking_v1.Ingress, reason, message string) {
+	p.WithField("name", ing.GetName()).
+		WithField("namespace", ing.GetNamespace()).
+		WithField("reason", reason).
+		Error(message)
+
+	p.dag.StatusCache.Warn(ing, reason, message)
+}
+
+// setCORSPolicy sets the CORS policy of the given Ingress on the
+// virtual host, unless an older Ingress set a different one.
+func (p *IngressProcessor) setCORSPolicy(ing *networking_v1.Ingress, vhost *VirtualHost, cp *CORSPolicy) {
+	if cp == nil {
+		return
 	}
+
+	if vhost.CORSPolicy != nil && !reflect.DeepEqual(vhost.CORSPolicy, cp) {
+		p.recordError(ing, "CORSPolicyConflict",
+			fmt.Sprintf("virtual host %q already has a different CORS policy", vhost.Name))
+		return
+	}
+
+	vhost.CORSPolicy = cp
 }
 
-func (p *IngressProcessor) computeIngressRule(ing *networking_v1.Ingress, rule networking_v1.IngressRule) {
+func (p *IngressProcessor) computeIngressRule(ing *networking_v1.Ingress, rule networking_v1.IngressRule, policy *ingressRoutePolicy) {
 	host := rule.Host
 
 	// If host name is blank, rewrite to Envoy's * default host.
@@ -223,13 +285,9 @@ func (p *IngressProcessor) computeIngressRule(ing *networking_v1.Ingress, rule n
 		}
 		s = serviceCircuitBreakerPolicy(s, p.GlobalCircuitBreakerDefaults)
 
-		r, err := p.route(ing, rule.Host, path, pathType, s, clientCertSecret, be.Service.Name, be.Service.Port.Number, p.FieldLogger)
+		r, err := p.route(ing, rule.Host, path, pathType, s, clientCertSecret, be.Service.Name, be.Service.Port.Number, policy, p.FieldLogger)
 		if err != nil {
-			p.WithError(err).
-				WithField("name", ing.GetName()).
-				WithField("namespace", ing.GetNamespace()).
-				WithField("regex", path).
-				Errorf("path regex is not valid")
+			p.recordError(ing, "RouteNotValid", fmt.Sprintf("path %q is not valid: %s", path, err))
 			return
 		}
 
@@ -246,6 +304,7 @@ func (p *IngressProcessor) computeIngressRule(ing *networking_v1.Ingress, rule n
 
 			vhost := p.dag.EnsureVirtualHost(listener.Name, host)
 			vhost.AddRoute(r)
+			p.setCORSPolicy(ing, vhost, policy.CORSPolicy)
 		}
 
 		listener, err := p.dag.GetSingleListener("https")
@@ -262,12 +321,13 @@ func (p *IngressProcessor) computeIngressRule(ing *networking_v1.Ingress, rule n
 		// it is correctly configured for TLS.
 		if svh := p.dag.GetSecureVirtualHost(listener.Name, host); svh != nil && host != "*" {
 			svh.AddRoute(r)
+			p.setCORSPolicy(ing, &svh.VirtualHost, policy.CORSPolicy)
 		}
 	}
 }
 
 // route builds a dag.Route for the supplied Ingress.
-func (p *IngressProcessor) route(ingress *networking_v1.Ingress, host, path string, pathType networking_v1.PathType, service *Service, clientCertSecret *Secret, serviceName string, servicePort int32, log logrus.FieldLogger) (*Route, error) {
+func (p *IngressProcessor) route(ingress *networking_v1.Ingress, host, path string, pathType networking_v1.PathType, service *Service, clientCertSecret *Secret, serviceName string, servicePort int32, policy *ingressRoutePolicy, log logrus.FieldLogger) (*Route, error) {
 	log = log.WithFields(logrus.Fields{
 		"name":      ingress.Name,
 		"namespace": ingress.Namespace,
@@ -290,13 +350,20 @@ func (p *IngressProcessor) route(ingress *networking_v1.Ingress, host, path stri
 	}
 
 	r := &Route{
-		HTTPSUpgrade:  annotation.TLSRequired(ingress),
-		Websocket:     annotation.WebsocketRoutes(ingress)[path],
-		TimeoutPolicy: ingressTimeoutPolicy(ingress, log),
-		RetryPolicy:   ingressRetryPolicy(ingress, log),
+		HTTPSUpgrade:          annotation.TLSRequired(ingress),
+		Websocket:             annotation.WebsocketRoutes(ingress)[path],
+		TimeoutPolicy:         ingressTimeoutPolicy(ingress, log),
+		RetryPolicy:           ingressRetryPolicy(ingress, log),
+		RateLimitPolicy:       policy.RateLimitPolicy,
+		RequestHeadersPolicy:  policy.RequestHeadersPolicy,
+		ResponseHeadersPolicy: policy.ResponseHeadersPolicy,
+		RequestHashPolicies:   policy.RequestHashPolicies,
+		IPFilterAllow:         policy.IPFilterAllow,
+		IPFilterRules:         policy.IPFilterRules,
 		Clusters: []*Cluster{{
 			Upstream:                      service,
 			Protocol:                      service.Protocol,
+			LoadBalancerPolicy:            policy.LoadBalancerPolicy,
 			ClientCertificate:             clientCertSecret,
 			RequestHeadersPolicy:          reqHP,
 			ResponseHeadersPolicy:         respHP,
@@ -341,6 +408,17 @@ func (p *IngressProcessor) route(ingress *networking_v1.Ingress, host, path stri
 		}
 	}
 
+	if policy.PathRewrite != "" {
+		switch r.PathMatchCondition.(type) {
+		case *PrefixMatchCondition:
+			r.PathRewritePolicy = &PathRewritePolicy{PrefixRewrite: policy.PathRewrite}
+		case *ExactMatchCondition:
+			r.PathRewritePolicy = &PathRewritePolicy{FullPathRewrite: policy.PathRewrite}
+		default:
+			return nil, fmt.Errorf("path-rewrite is not supported for regex paths")
+		}
+	}
+
 	// If we have a wildcard match, add a header match regex rule to match the
 	// hostname so we can be sure to only match one DNS label. This is required
 	// as Envoy's virtualhost hostname wildcard matching can match multiple
@@ -15,10 +15,17 @@ package dag
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/projectcontour/contour/internal/fixture"
 )
 
 func TestHttpPaths(t *testing.T) {
@@ -67,3 +74,231 @@ func TestHttpPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestIngressProcessorAnnotations(t *testing.T) {
+	service := &core_v1.Service{
+		ObjectMeta: fixture.ObjectMeta("default/kuard"),
+		Spec: core_v1.ServiceSpec{
+			Ports: []core_v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	ingress := func(path string, pathType networking_v1.PathType, annotations map[string]string) *networking_v1.Ingress {
+		return &networking_v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:        "kuard",
+				Namespace:   "default",
+				Annotations: annotations,
+			},
+			Spec: networking_v1.IngressSpec{
+				Rules: []networking_v1.IngressRule{{
+					Host: "kuard.projectcontour.io",
+					IngressRuleValue: networking_v1.IngressRuleValue{
+						HTTP: &networking_v1.HTTPIngressRuleValue{
+							Paths: []networking_v1.HTTPIngressPath{{
+								Path:     path,
+								PathType: ptr.To(pathType),
+								Backend:  *backendv1("kuard", intstr.FromInt(8080)),
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		ingress      *networking_v1.Ingress
+		wantWarnings []string
+		wantRewrite  *PathRewritePolicy
+		wantCORS     bool
+	}{
+		"prefix path rewrite": {
+			ingress: ingress("/api", networking_v1.PathTypePrefix, map[string]string{
+				"projectcontour.io/path-rewrite": "/v2",
+			}),
+			wantRewrite: &PathRewritePolicy{PrefixRewrite: "/v2"},
+		},
+		"exact path rewrite": {
+			ingress: ingress("/api", networking_v1.PathTypeExact, map[string]string{
+				"projectcontour.io/path-rewrite": "/v2",
+			}),
+			wantRewrite: &PathRewritePolicy{FullPathRewrite: "/v2"},
+		},
+		"regex path rewrite": {
+			ingress: ingress("/api/[a-z]+", networking_v1.PathTypeImplementationSpecific, map[string]string{
+				"projectcontour.io/path-rewrite": "/v2",
+			}),
+			wantWarnings: []string{`RouteNotValid: path "/api/[a-z]+" is not valid: path-rewrite is not supported for regex paths`},
+		},
+		"cors": {
+			ingress: ingress("/", networking_v1.PathTypePrefix, map[string]string{
+				"projectcontour.io/cors-allow-origin":  "*",
+				"projectcontour.io/cors-allow-methods": "GET",
+			}),
+			wantCORS: true,
+		},
+		"invalid annotations": {
+			ingress: ingress("/", networking_v1.PathTypePrefix, map[string]string{
+				"projectcontour.io/load-balancer-strategy": "Fastest",
+				"projectcontour.io/ip-allow-filter":        "not-an-ip",
+			}),
+			wantWarnings: []string{
+				`InvalidCIDR: not-an-ip failed to parse: invalid CIDR address: not-an-ip/32`,
+				`LoadBalancerPolicyNotValid: invalid load-balancer-strategy "Fastest", must be one of RoundRobin, WeightedLeastRequest, Random or Cookie`,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					FieldLogger: fixture.NewTestLogger(t),
+				},
+				Processors: []Processor{
+					&ListenerProcessor{},
+					&IngressProcessor{
+						FieldLogger: fixture.NewTestLogger(t),
+					},
+				},
+			}
+			builder.Source.Insert(service)
+			builder.Source.Insert(tc.ingress)
+
+			dag := builder.Build()
+
+			var warnings []string
+			for _, w := range dag.StatusCache.GetWarnings() {
+				assert.Equal(t, tc.ingress, w.Object)
+				warnings = append(warnings, w.Reason+": "+w.Message)
+			}
+			assert.Equal(t, tc.wantWarnings, warnings)
+
+			listener := dag.Listeners[HTTP_LISTENER_NAME]
+			if len(tc.wantWarnings) > 0 {
+				assert.Nil(t, listener)
+				return
+			}
+
+			require.NotNil(t, listener)
+			require.Len(t, listener.VirtualHosts, 1)
+			vhost := listener.VirtualHosts[0]
+			require.Len(t, vhost.Routes, 1)
+			for _, r := range vhost.Routes {
+				assert.Equal(t, tc.wantRewrite, r.PathRewritePolicy)
+			}
+			assert.Equal(t, tc.wantCORS, vhost.CORSPolicy != nil)
+		})
+	}
+}
+
+func TestIngressProcessorCORSPolicyConflict(t *testing.T) {
+	service := &core_v1.Service{
+		ObjectMeta: fixture.ObjectMeta("default/kuard"),
+		Spec: core_v1.ServiceSpec{
+			Ports: []core_v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	ingress := func(name string, created time.Time, path, origin string) *networking_v1.Ingress {
+		return &networking_v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: meta_v1.NewTime(created),
+				Annotations: map[string]string{
+					"projectcontour.io/cors-allow-origin":  origin,
+					"projectcontour.io/cors-allow-methods": "GET",
+				},
+			},
+			Spec: networking_v1.IngressSpec{
+				Rules: []networking_v1.IngressRule{{
+					Host: "kuard.projectcontour.io",
+					IngressRuleValue: networking_v1.IngressRuleValue{
+						HTTP: &networking_v1.HTTPIngressRuleValue{
+							Paths: []networking_v1.HTTPIngressPath{{
+								Path:     path,
+								PathType: ptr.To(networking_v1.PathTypePrefix),
+								Backend:  *backendv1("kuard", intstr.FromInt(8080)),
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		ingresses   []*networking_v1.Ingress
+		wantOrigin  string
+		wantWarning string
+	}{
+		"oldest ingress wins": {
+			ingresses: []*networking_v1.Ingress{
+				ingress("a", created.Add(time.Minute), "/a", "https://a.example.com"),
+				ingress("b", created, "/b", "https://b.example.com"),
+			},
+			wantOrigin:  "https://b.example.com",
+			wantWarning: "a",
+		},
+		"first ingress by name wins if created at the same time": {
+			ingresses: []*networking_v1.Ingress{
+				ingress("b", created, "/b", "https://b.example.com"),
+				ingress("a", created, "/a", "https://a.example.com"),
+			},
+			wantOrigin:  "https://a.example.com",
+			wantWarning: "b",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Build several times, as the Ingresses are held in a map.
+			for range 10 {
+				builder := Builder{
+					Source: KubernetesCache{
+						FieldLogger: fixture.NewTestLogger(t),
+					},
+					Processors: []Processor{
+						&ListenerProcessor{},
+						&IngressProcessor{
+							FieldLogger: fixture.NewTestLogger(t),
+						},
+					},
+				}
+				builder.Source.Insert(service)
+				for _, ing := range tc.ingresses {
+					builder.Source.Insert(ing)
+				}
+
+				dag := builder.Build()
+
+				warnings := dag.StatusCache.GetWarnings()
+				require.Len(t, warnings, 1)
+				assert.Equal(t, tc.wantWarning, warnings[0].Object.GetName())
+				assert.Equal(t, "CORSPolicyConflict", warnings[0].Reason)
+
+				listener := dag.Listeners[HTTP_LISTENER_NAME]
+				require.NotNil(t, listener)
+				require.Len(t, listener.VirtualHosts, 1)
+				cors := listener.VirtualHosts[0].CORSPolicy
+				require.NotNil(t, cors)
+				require.Len(t, cors.AllowOrigin, 1)
+				assert.Equal(t, tc.wantOrigin, cors.AllowOrigin[0].Value)
+			}
+		})
+	}
+}
@@ -32,22 +32,27 @@ type ListenerProcessor struct {
 
 // Run adds HTTP and HTTPS listeners to the DAG.
 func (p *ListenerProcessor) Run(dag *DAG, cache *KubernetesCache) {
-	if cache.gateway != nil {
+	if gateways := cache.servedGateways(); len(gateways) > 0 {
 		dag.HasDynamicListeners = true
 
-		for _, port := range gatewayapi.ValidateListeners(cache.gateway.Spec.Listeners).Ports {
-			address := p.HTTPAddress
-			if port.Protocol == "https" {
-				address = p.HTTPSAddress
-			}
-			dag.Listeners[port.Name] = &Listener{
-				Name:             port.Name,
-				Protocol:         port.Protocol,
-				Address:          address,
-				Port:             int(port.ContainerPort),
-				EnableWebsockets: true,
-				vhostsByName:     map[string]*VirtualHost{},
-				svhostsByName:    map[string]*SecureVirtualHost{},
+		for _, gateway := range gateways {
+			// The listeners of the ListenerSets allowed by the Gateway
+			// are merged after the Gateway's own listeners.
+			for _, port := range gatewayapi.ValidateListeners(gateway.Spec.Listeners, cache.allowedListenerSetListeners(gateway)...).Ports {
+				address := p.HTTPAddress
+				if port.Protocol == "https" {
+					address = p.HTTPSAddress
+				}
+				name := cache.gatewayListenerName(gateway, port.Name)
+				dag.Listeners[name] = &Listener{
+					Name:             name,
+					Protocol:         port.Protocol,
+					Address:          address,
+					Port:             int(port.ContainerPort),
+					EnableWebsockets: true,
+					vhostsByName:     map[string]*VirtualHost{},
+					svhostsByName:    map[string]*SecureVirtualHost{},
+				}
 			}
 		}
 	} else {
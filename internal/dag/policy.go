@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -447,6 +448,208 @@ func ingressTimeoutPolicy(ingress *networking_v1.Ingress, log logrus.FieldLogger
 	return tp
 }
 
+// ingressRoutePolicy holds the route and virtual host policies
+// configured by the annotations of an Ingress.
+type ingressRoutePolicy struct {
+	CORSPolicy            *CORSPolicy
+	RateLimitPolicy       *RateLimitPolicy
+	RequestHeadersPolicy  *HeadersPolicy
+	ResponseHeadersPolicy *HeadersPolicy
+	IPFilterAllow         bool
+	IPFilterRules         []IPFilterRule
+	LoadBalancerPolicy    string
+	RequestHashPolicies   []RequestHashPolicy
+
+	// PathRewrite replaces the part of the path matched
+	// by the Ingress path, if not empty.
+	PathRewrite string
+}
+
+var corsAnnotations = []string{
+	"cors-allow-credentials",
+	"cors-allow-headers",
+	"cors-allow-methods",
+	"cors-allow-origin",
+	"cors-expose-headers",
+	"cors-max-age",
+}
+
+var localRateLimitAnnotations = []string{
+	"local-rate-limit-burst",
+	"local-rate-limit-requests",
+	"local-rate-limit-unit",
+}
+
+// ingressPolicy builds the policies configured by the annotations
+// of the given Ingress. Invalid annotations are added as errors to
+// validCond.
+func ingressPolicy(ingress *networking_v1.Ingress, validCond *contour_v1.DetailedCondition) *ingressRoutePolicy {
+	policy := &ingressRoutePolicy{
+		PathRewrite: annotation.ContourAnnotation(ingress, "path-rewrite"),
+	}
+
+	var err error
+	if policy.CORSPolicy, err = ingressCORSPolicy(ingress); err != nil {
+		validCond.AddErrorf(contour_v1.ConditionTypeCORSError, "PolicyDidNotParse",
+			"cors annotations: %s", err)
+	}
+
+	if policy.RateLimitPolicy, err = ingressRateLimitPolicy(ingress); err != nil {
+		validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "RateLimitPolicyNotValid",
+			"local-rate-limit annotations: %s", err)
+	}
+
+	dynamicHeaders := map[string]string{
+		"CONTOUR_NAMESPACE": ingress.Namespace,
+	}
+
+	if policy.RequestHeadersPolicy, err = ingressHeadersPolicy(ingress, "request", true /* allow Host */, dynamicHeaders); err != nil {
+		validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "RequestHeadersPolicyInvalid",
+			"%s on request headers", err)
+	}
+
+	if policy.ResponseHeadersPolicy, err = ingressHeadersPolicy(ingress, "response", false /* disallow Host */, dynamicHeaders); err != nil {
+		validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "ResponseHeaderPolicyInvalid",
+			"%s on response headers", err)
+	}
+
+	var source contour_v1.IPFilterSource
+	switch s := annotation.ContourAnnotation(ingress, "ip-filter-source"); {
+	case s == "", strings.EqualFold(s, string(contour_v1.IPFilterSourcePeer)):
+		source = contour_v1.IPFilterSourcePeer
+	case strings.EqualFold(s, string(contour_v1.IPFilterSourceRemote)):
+		source = contour_v1.IPFilterSourceRemote
+	default:
+		validCond.AddErrorf(contour_v1.ConditionTypeIPFilterError, "IPFilterPolicyNotValid",
+			"invalid ip-filter-source %q, must be Peer or Remote", s)
+	}
+
+	ipFilterPolicies := func(key string) []contour_v1.IPFilterPolicy {
+		var policies []contour_v1.IPFilterPolicy
+		for _, cidr := range annotation.ContourAnnotationList(ingress, key) {
+			policies = append(policies, contour_v1.IPFilterPolicy{Source: source, CIDR: cidr})
+		}
+		return policies
+	}
+
+	// toIPFilterRules adds any errors to validCond itself.
+	policy.IPFilterAllow, policy.IPFilterRules, _ = toIPFilterRules(ipFilterPolicies("ip-allow-filter"), ipFilterPolicies("ip-deny-filter"), validCond)
+
+	switch strategy := annotation.ContourAnnotation(ingress, "load-balancer-strategy"); strategy {
+	case "", LoadBalancerPolicyRoundRobin:
+	case LoadBalancerPolicyWeightedLeastRequest, LoadBalancerPolicyRandom, LoadBalancerPolicyCookie:
+		policy.RequestHashPolicies, policy.LoadBalancerPolicy = loadBalancerRequestHashPolicies(&contour_v1.LoadBalancerPolicy{Strategy: strategy}, validCond)
+	default:
+		validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "LoadBalancerPolicyNotValid",
+			"invalid load-balancer-strategy %q, must be one of %s, %s, %s or %s", strategy,
+			LoadBalancerPolicyRoundRobin, LoadBalancerPolicyWeightedLeastRequest, LoadBalancerPolicyRandom, LoadBalancerPolicyCookie)
+	}
+
+	return policy
+}
+
+// ingressCORSPolicy builds a CORSPolicy from the cors-* annotations
+// of the given Ingress, or returns nil if none are set.
+func ingressCORSPolicy(ingress *networking_v1.Ingress) (*CORSPolicy, error) {
+	if !hasContourAnnotation(ingress, corsAnnotations...) {
+		return nil, nil
+	}
+
+	headerValues := func(key string) []contour_v1.CORSHeaderValue {
+		var values []contour_v1.CORSHeaderValue
+		for _, v := range annotation.ContourAnnotationList(ingress, key) {
+			values = append(values, contour_v1.CORSHeaderValue(v))
+		}
+		return values
+	}
+
+	cors := &contour_v1.CORSPolicy{
+		AllowOrigin:   annotation.ContourAnnotationList(ingress, "cors-allow-origin"),
+		AllowMethods:  headerValues("cors-allow-methods"),
+		AllowHeaders:  headerValues("cors-allow-headers"),
+		ExposeHeaders: headerValues("cors-expose-headers"),
+		MaxAge:        annotation.ContourAnnotation(ingress, "cors-max-age"),
+	}
+
+	if v := annotation.ContourAnnotation(ingress, "cors-allow-credentials"); v != "" {
+		allowCredentials, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow credentials value %q", v)
+		}
+		cors.AllowCredentials = allowCredentials
+	}
+
+	return toCORSPolicy(cors)
+}
+
+// ingressRateLimitPolicy builds a local RateLimitPolicy from the
+// local-rate-limit-* annotations of the given Ingress, or returns
+// nil if none are set. The unit defaults to "second".
+func ingressRateLimitPolicy(ingress *networking_v1.Ingress) (*RateLimitPolicy, error) {
+	if !hasContourAnnotation(ingress, localRateLimitAnnotations...) {
+		return nil, nil
+	}
+
+	local := &contour_v1.LocalRateLimitPolicy{
+		Unit: annotation.ContourAnnotation(ingress, "local-rate-limit-unit"),
+	}
+	if local.Unit == "" {
+		local.Unit = "second"
+	}
+
+	requests, err := strconv.ParseUint(annotation.ContourAnnotation(ingress, "local-rate-limit-requests"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid requests value %q", annotation.ContourAnnotation(ingress, "local-rate-limit-requests"))
+	}
+	local.Requests = uint32(requests) //nolint:gosec // disable G115
+
+	if v := annotation.ContourAnnotation(ingress, "local-rate-limit-burst"); v != "" {
+		burst, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid burst value %q", v)
+		}
+		local.Burst = uint32(burst) //nolint:gosec // disable G115
+	}
+
+	return rateLimitPolicy(&contour_v1.RateLimitPolicy{Local: local})
+}
+
+// ingressHeadersPolicy builds a HeadersPolicy from the
+// <direction>-headers-set and <direction>-headers-remove annotations
+// of the given Ingress, or returns nil if neither is set.
+func ingressHeadersPolicy(ingress *networking_v1.Ingress, direction string, allowHostRewrite bool, dynamicHeaders map[string]string) (*HeadersPolicy, error) {
+	set, err := annotation.ContourAnnotationHeaders(ingress, direction+"-headers-set")
+	if err != nil {
+		return nil, err
+	}
+
+	remove := annotation.ContourAnnotationList(ingress, direction+"-headers-remove")
+
+	if len(set) == 0 && len(remove) == 0 {
+		return nil, nil
+	}
+
+	policy := &contour_v1.HeadersPolicy{
+		Remove: remove,
+	}
+	for _, header := range set {
+		policy.Set = append(policy.Set, contour_v1.HeaderValue{Name: header.Name, Value: header.Value})
+	}
+
+	return headersPolicyRoute(policy, allowHostRewrite, dynamicHeaders)
+}
+
+// hasContourAnnotation returns true if any of the given
+// annotations with the "projectcontour.io/" prefix is set.
+func hasContourAnnotation(ingress *networking_v1.Ingress, keys ...string) bool {
+	for _, key := range keys {
+		if annotation.ContourAnnotation(ingress, key) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func timeoutPolicy(tp *contour_v1.TimeoutPolicy, connectTimeout time.Duration) (RouteTimeoutPolicy, ClusterTimeoutPolicy, error) {
 	if tp == nil {
 		return RouteTimeoutPolicy{
@@ -595,23 +798,17 @@ func localRateLimitPolicy(in *contour_v1.LocalRateLimitPolicy) (*LocalRateLimitP
 		return nil, fmt.Errorf("invalid requests value %d in local rate limit policy", in.Requests)
 	}
 
-	var fillInterval time.Duration
-	switch in.Unit {
-	case "second":
-		fillInterval = time.Second
-	case "minute":
-		fillInterval = time.Minute
-	case "hour":
-		fillInterval = time.Hour
-	default:
+	fillInterval, ok := rateLimitUnits[in.Unit]
+	if !ok {
 		return nil, fmt.Errorf("invalid unit %q in local rate limit policy", in.Unit)
 	}
 
 	res := &LocalRateLimitPolicy{
-		MaxTokens:          in.Requests + in.Burst,
-		TokensPerFill:      in.Requests,
-		FillInterval:       fillInterval,
-		ResponseStatusCode: in.ResponseStatusCode,
+		MaxTokens:             in.Requests + in.Burst,
+		TokensPerFill:         in.Requests,
+		FillInterval:          fillInterval,
+		ResponseStatusCode:    in.ResponseStatusCode,
+		MaxDynamicDescriptors: in.MaxDynamicDescriptors,
 	}
 
 	for _, header := range in.ResponseHeadersToAdd {
@@ -630,6 +827,71 @@ func localRateLimitPolicy(in *contour_v1.LocalRateLimitPolicy) (*LocalRateLimitP
 		res.ResponseHeadersToAdd[key] = escapeHeaderValue(header.Value, map[string]string{})
 	}
 
+	for _, d := range in.Descriptors {
+		descriptor, err := localRateLimitDescriptor(d, fillInterval)
+		if err != nil {
+			return nil, err
+		}
+		res.Descriptors = append(res.Descriptors, descriptor)
+	}
+
+	return res, nil
+}
+
+// rateLimitUnits maps rate limit units to token bucket fill intervals.
+var rateLimitUnits = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+}
+
+// localRateLimitDescriptor validates and returns the DAG representation
+// of the given local rate limit descriptor. Envoy requires the fill
+// interval of a descriptor to be a multiple of the policy's fill interval.
+func localRateLimitDescriptor(in contour_v1.LocalRateLimitDescriptor, policyFillInterval time.Duration) (*LocalRateLimitDescriptor, error) {
+	if in.Requests <= 0 {
+		return nil, fmt.Errorf("invalid requests value %d in local rate limit descriptor", in.Requests)
+	}
+
+	fillInterval, ok := rateLimitUnits[in.Unit]
+	if !ok {
+		return nil, fmt.Errorf("invalid unit %q in local rate limit descriptor", in.Unit)
+	}
+	if fillInterval < policyFillInterval {
+		return nil, fmt.Errorf("unit %q in local rate limit descriptor must not be shorter than the policy unit", in.Unit)
+	}
+
+	if len(in.Entries) == 0 {
+		return nil, errors.New("local rate limit descriptor must have at least one entry")
+	}
+
+	res := &LocalRateLimitDescriptor{
+		MaxTokens:     in.Requests + in.Burst,
+		TokensPerFill: in.Requests,
+		FillInterval:  fillInterval,
+	}
+
+	for _, entry := range in.Entries {
+		e, err := rateLimitDescriptorEntry(entry.RateLimitDescriptorEntry)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case e.Metadata != nil:
+			return nil, errors.New("metadata descriptor entries are not supported for local rate limiting")
+		case e.GenericKey != nil, e.HeaderValueMatch != nil:
+			if entry.Value != "" {
+				return nil, errors.New("value must not be set for generic key and header value match descriptor entries")
+			}
+		}
+
+		res.Entries = append(res.Entries, LocalRateLimitDescriptorEntry{
+			RateLimitDescriptorEntry: e,
+			Value:                    entry.Value,
+		})
+	}
+
 	return res, nil
 }
 
@@ -644,57 +906,106 @@ func globalRateLimitPolicy(in *contour_v1.GlobalRateLimitPolicy) (*GlobalRateLim
 		var rld RateLimitDescriptor
 
 		for _, entry := range d.Entries {
-			// ensure exactly one field is populated on the entry
-			var set int
-
-			if entry.GenericKey != nil {
-				set++
-
-				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
-					GenericKey: &GenericKeyDescriptorEntry{
-						Key:   entry.GenericKey.Key,
-						Value: entry.GenericKey.Value,
-					},
-				})
+			e, err := rateLimitDescriptorEntry(entry)
+			if err != nil {
+				return nil, err
 			}
+			rld.Entries = append(rld.Entries, e)
+		}
 
-			if entry.RequestHeader != nil {
-				set++
+		res.Descriptors = append(res.Descriptors, &rld)
+	}
 
-				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
-					HeaderMatch: &HeaderMatchDescriptorEntry{
-						HeaderName: entry.RequestHeader.HeaderName,
-						Key:        entry.RequestHeader.DescriptorKey,
-					},
-				})
-			}
+	return res, nil
+}
 
-			if entry.RequestHeaderValueMatch != nil {
-				set++
+// rateLimitDescriptorEntry validates and returns the DAG
+// representation of the given rate limit descriptor entry.
+func rateLimitDescriptorEntry(entry contour_v1.RateLimitDescriptorEntry) (RateLimitDescriptorEntry, error) {
+	var res RateLimitDescriptorEntry
 
-				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
-					HeaderValueMatch: &HeaderValueMatchDescriptorEntry{
-						Headers:     headerMatchConditions(entry.RequestHeaderValueMatch.Headers),
-						ExpectMatch: entry.RequestHeaderValueMatch.ExpectMatch,
-						Value:       entry.RequestHeaderValueMatch.Value,
-					},
-				})
-			}
+	// ensure exactly one field is populated on the entry
+	var set int
 
-			if entry.RemoteAddress != nil {
-				set++
+	if entry.GenericKey != nil {
+		set++
 
-				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
-					RemoteAddress: &RemoteAddressDescriptorEntry{},
-				})
-			}
+		res.GenericKey = &GenericKeyDescriptorEntry{
+			Key:   entry.GenericKey.Key,
+			Value: entry.GenericKey.Value,
+		}
+	}
 
-			if set != 1 {
-				return nil, errors.New("rate limit descriptor entry must have exactly one field set")
-			}
+	if entry.RequestHeader != nil {
+		set++
+
+		res.HeaderMatch = &HeaderMatchDescriptorEntry{
+			HeaderName: entry.RequestHeader.HeaderName,
+			Key:        entry.RequestHeader.DescriptorKey,
 		}
+	}
 
-		res.Descriptors = append(res.Descriptors, &rld)
+	if entry.RequestHeaderValueMatch != nil {
+		set++
+
+		res.HeaderValueMatch = &HeaderValueMatchDescriptorEntry{
+			Headers:     headerMatchConditions(entry.RequestHeaderValueMatch.Headers),
+			ExpectMatch: entry.RequestHeaderValueMatch.ExpectMatch,
+			Value:       entry.RequestHeaderValueMatch.Value,
+		}
+	}
+
+	if entry.RemoteAddress != nil {
+		set++
+
+		res.RemoteAddress = &RemoteAddressDescriptorEntry{}
+	}
+
+	if entry.MaskedRemoteAddress != nil {
+		set++
+
+		if entry.MaskedRemoteAddress.V4PrefixLength > 32 || entry.MaskedRemoteAddress.V6PrefixLength > 128 {
+			return res, errors.New("masked remote address prefix length must be at most 32 for IPv4 and 128 for IPv6")
+		}
+
+		res.MaskedRemoteAddress = &MaskedRemoteAddressDescriptorEntry{
+			V4PrefixLength: entry.MaskedRemoteAddress.V4PrefixLength,
+			V6PrefixLength: entry.MaskedRemoteAddress.V6PrefixLength,
+		}
+	}
+
+	if entry.QueryParameter != nil {
+		set++
+
+		res.QueryParameter = &QueryParameterDescriptorEntry{
+			ParameterName: entry.QueryParameter.ParameterName,
+			Key:           entry.QueryParameter.DescriptorKey,
+		}
+	}
+
+	if entry.Metadata != nil {
+		set++
+
+		if entry.Metadata.Filter == "" || len(entry.Metadata.Path) == 0 {
+			return res, errors.New("metadata descriptor entry must have a filter and a path")
+		}
+
+		res.Metadata = &MetadataDescriptorEntry{
+			Filter:       entry.Metadata.Filter,
+			Path:         entry.Metadata.Path,
+			Key:          entry.Metadata.DescriptorKey,
+			DefaultValue: entry.Metadata.DefaultValue,
+		}
+	}
+
+	if entry.DestinationCluster != nil {
+		set++
+
+		res.DestinationCluster = &DestinationClusterDescriptorEntry{}
+	}
+
+	if set != 1 {
+		return res, errors.New("rate limit descriptor entry must have exactly one field set")
 	}
 
 	return res, nil
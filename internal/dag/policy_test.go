@@ -17,6 +17,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"testing"
 	"time"
 
@@ -121,6 +122,228 @@ func TestRetryPolicyIngress(t *testing.T) {
 	}
 }
 
+func TestIngressPolicy(t *testing.T) {
+	ingress := func(annotations map[string]string) *networking_v1.Ingress {
+		return &networking_v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Namespace:   "default",
+				Annotations: annotations,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		i          *networking_v1.Ingress
+		want       *ingressRoutePolicy
+		wantErrors []string
+	}{
+		"no annotations": {
+			i:    ingress(nil),
+			want: &ingressRoutePolicy{},
+		},
+		"cors": {
+			i: ingress(map[string]string{
+				"projectcontour.io/cors-allow-origin":      "*",
+				"projectcontour.io/cors-allow-methods":     "GET, POST",
+				"projectcontour.io/cors-allow-headers":     "authorization",
+				"projectcontour.io/cors-allow-credentials": "true",
+				"projectcontour.io/cors-max-age":           "10m",
+			}),
+			want: &ingressRoutePolicy{
+				CORSPolicy: &CORSPolicy{
+					AllowCredentials: true,
+					AllowOrigin:      []CORSAllowOriginMatch{{Type: CORSAllowOriginMatchExact, Value: "*"}},
+					AllowMethods:     []string{"GET", "POST"},
+					AllowHeaders:     []string{"authorization"},
+					ExposeHeaders:    []string{},
+					MaxAge:           timeout.DurationSetting(10 * time.Minute),
+				},
+			},
+		},
+		"cors without allowed origin": {
+			i: ingress(map[string]string{
+				"projectcontour.io/cors-allow-methods": "GET",
+			}),
+			wantErrors: []string{"PolicyDidNotParse"},
+		},
+		"cors with invalid allow credentials": {
+			i: ingress(map[string]string{
+				"projectcontour.io/cors-allow-origin":      "*",
+				"projectcontour.io/cors-allow-methods":     "GET",
+				"projectcontour.io/cors-allow-credentials": "yes please",
+			}),
+			wantErrors: []string{"PolicyDidNotParse"},
+		},
+		"local rate limit": {
+			i: ingress(map[string]string{
+				"projectcontour.io/local-rate-limit-requests": "100",
+				"projectcontour.io/local-rate-limit-unit":     "minute",
+				"projectcontour.io/local-rate-limit-burst":    "50",
+			}),
+			want: &ingressRoutePolicy{
+				RateLimitPolicy: &RateLimitPolicy{
+					Local: &LocalRateLimitPolicy{
+						MaxTokens:     150,
+						TokensPerFill: 100,
+						FillInterval:  time.Minute,
+					},
+				},
+			},
+		},
+		"local rate limit defaults to per second": {
+			i: ingress(map[string]string{
+				"projectcontour.io/local-rate-limit-requests": "10",
+			}),
+			want: &ingressRoutePolicy{
+				RateLimitPolicy: &RateLimitPolicy{
+					Local: &LocalRateLimitPolicy{
+						MaxTokens:     10,
+						TokensPerFill: 10,
+						FillInterval:  time.Second,
+					},
+				},
+			},
+		},
+		"local rate limit without requests": {
+			i: ingress(map[string]string{
+				"projectcontour.io/local-rate-limit-unit": "minute",
+			}),
+			wantErrors: []string{"RateLimitPolicyNotValid"},
+		},
+		"local rate limit with invalid unit": {
+			i: ingress(map[string]string{
+				"projectcontour.io/local-rate-limit-requests": "10",
+				"projectcontour.io/local-rate-limit-unit":     "fortnight",
+			}),
+			wantErrors: []string{"RateLimitPolicyNotValid"},
+		},
+		"header policies": {
+			i: ingress(map[string]string{
+				"projectcontour.io/request-headers-set":     "X-Foo: bar\nX-Namespace: %CONTOUR_NAMESPACE%\n",
+				"projectcontour.io/request-headers-remove":  "x-baz",
+				"projectcontour.io/response-headers-remove": "server, x-powered-by",
+			}),
+			want: &ingressRoutePolicy{
+				RequestHeadersPolicy: &HeadersPolicy{
+					Set:    map[string]string{"X-Foo": "bar", "X-Namespace": "default"},
+					Remove: []string{"X-Baz"},
+				},
+				ResponseHeadersPolicy: &HeadersPolicy{
+					Remove: []string{"Server", "X-Powered-By"},
+				},
+			},
+		},
+		"malformed request header": {
+			i: ingress(map[string]string{
+				"projectcontour.io/request-headers-set": "X-Foo",
+			}),
+			wantErrors: []string{"RequestHeadersPolicyInvalid"},
+		},
+		"response host rewrite": {
+			i: ingress(map[string]string{
+				"projectcontour.io/response-headers-set": "Host: example.com",
+			}),
+			wantErrors: []string{"ResponseHeaderPolicyInvalid"},
+		},
+		"ip allow filter": {
+			i: ingress(map[string]string{
+				"projectcontour.io/ip-allow-filter":  "10.0.0.0/8, 192.168.1.1",
+				"projectcontour.io/ip-filter-source": "remote",
+			}),
+			want: &ingressRoutePolicy{
+				IPFilterAllow: true,
+				IPFilterRules: []IPFilterRule{
+					{Remote: true, CIDR: net.IPNet{IP: net.ParseIP("10.0.0.0").To4(), Mask: net.CIDRMask(8, 32)}},
+					{Remote: true, CIDR: net.IPNet{IP: net.ParseIP("192.168.1.1").To4(), Mask: net.CIDRMask(32, 32)}},
+				},
+			},
+		},
+		"ip deny filter": {
+			i: ingress(map[string]string{
+				"projectcontour.io/ip-deny-filter": "10.0.0.0/8",
+			}),
+			want: &ingressRoutePolicy{
+				IPFilterRules: []IPFilterRule{
+					{CIDR: net.IPNet{IP: net.ParseIP("10.0.0.0").To4(), Mask: net.CIDRMask(8, 32)}},
+				},
+			},
+		},
+		"ip allow and deny filters": {
+			i: ingress(map[string]string{
+				"projectcontour.io/ip-allow-filter": "10.0.0.0/8",
+				"projectcontour.io/ip-deny-filter":  "10.1.0.0/16",
+			}),
+			wantErrors: []string{"IncompatibleIPAddressFilters"},
+		},
+		"invalid ip filter cidr": {
+			i: ingress(map[string]string{
+				"projectcontour.io/ip-allow-filter": "10.0.0.0/88",
+			}),
+			wantErrors: []string{"InvalidCIDR"},
+		},
+		"invalid ip filter source": {
+			i: ingress(map[string]string{
+				"projectcontour.io/ip-allow-filter":  "10.0.0.0/8",
+				"projectcontour.io/ip-filter-source": "elsewhere",
+			}),
+			wantErrors: []string{"IPFilterPolicyNotValid"},
+		},
+		"load balancer strategy": {
+			i: ingress(map[string]string{
+				"projectcontour.io/load-balancer-strategy": "WeightedLeastRequest",
+			}),
+			want: &ingressRoutePolicy{
+				LoadBalancerPolicy: LoadBalancerPolicyWeightedLeastRequest,
+			},
+		},
+		"cookie load balancer strategy": {
+			i: ingress(map[string]string{
+				"projectcontour.io/load-balancer-strategy": "Cookie",
+			}),
+			want: &ingressRoutePolicy{
+				LoadBalancerPolicy: LoadBalancerPolicyCookie,
+				RequestHashPolicies: []RequestHashPolicy{{
+					CookieHashOptions: &CookieHashOptions{
+						CookieName: "X-Contour-Session-Affinity",
+						Path:       "/",
+					},
+				}},
+			},
+		},
+		"invalid load balancer strategy": {
+			i: ingress(map[string]string{
+				"projectcontour.io/load-balancer-strategy": "RequestHash",
+			}),
+			wantErrors: []string{"LoadBalancerPolicyNotValid"},
+		},
+		"path rewrite": {
+			i: ingress(map[string]string{
+				"projectcontour.io/path-rewrite": "/v2",
+			}),
+			want: &ingressRoutePolicy{
+				PathRewrite: "/v2",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			validCond := &contour_v1.DetailedCondition{}
+			got := ingressPolicy(tc.i, validCond)
+
+			var gotErrors []string
+			for _, e := range validCond.Errors {
+				gotErrors = append(gotErrors, e.Reason)
+			}
+			assert.Equal(t, tc.wantErrors, gotErrors)
+
+			if tc.want != nil {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
 func TestRetryPolicy(t *testing.T) {
 	tests := map[string]struct {
 		rp   *contour_v1.RetryPolicy
@@ -801,6 +1024,193 @@ func TestRateLimitPolicy(t *testing.T) {
 			},
 			wantErr: "invalid requests value 0 in local rate limit policy",
 		},
+		"local - descriptors": {
+			in: &contour_v1.RateLimitPolicy{
+				Local: &contour_v1.LocalRateLimitPolicy{
+					Requests: 100,
+					Unit:     "second",
+					Descriptors: []contour_v1.LocalRateLimitDescriptor{
+						{
+							Entries: []contour_v1.LocalRateLimitDescriptorEntry{
+								{
+									RateLimitDescriptorEntry: contour_v1.RateLimitDescriptorEntry{
+										RemoteAddress: &contour_v1.RemoteAddressDescriptor{},
+									},
+									Value: "10.0.0.1",
+								},
+								{
+									RateLimitDescriptorEntry: contour_v1.RateLimitDescriptorEntry{
+										GenericKey: &contour_v1.GenericKeyDescriptor{Value: "api"},
+									},
+								},
+							},
+							Requests: 5,
+							Unit:     "minute",
+						},
+					},
+				},
+			},
+			want: &RateLimitPolicy{
+				Local: &LocalRateLimitPolicy{
+					MaxTokens:     100,
+					TokensPerFill: 100,
+					FillInterval:  time.Second,
+					Descriptors: []*LocalRateLimitDescriptor{
+						{
+							Entries: []LocalRateLimitDescriptorEntry{
+								{
+									RateLimitDescriptorEntry: RateLimitDescriptorEntry{
+										RemoteAddress: &RemoteAddressDescriptorEntry{},
+									},
+									Value: "10.0.0.1",
+								},
+								{
+									RateLimitDescriptorEntry: RateLimitDescriptorEntry{
+										GenericKey: &GenericKeyDescriptorEntry{Value: "api"},
+									},
+								},
+							},
+							MaxTokens:     5,
+							TokensPerFill: 5,
+							FillInterval:  time.Minute,
+						},
+					},
+				},
+			},
+		},
+		"local - descriptor unit shorter than policy unit": {
+			in: &contour_v1.RateLimitPolicy{
+				Local: &contour_v1.LocalRateLimitPolicy{
+					Requests: 100,
+					Unit:     "minute",
+					Descriptors: []contour_v1.LocalRateLimitDescriptor{
+						{
+							Entries: []contour_v1.LocalRateLimitDescriptorEntry{
+								{
+									RateLimitDescriptorEntry: contour_v1.RateLimitDescriptorEntry{
+										RemoteAddress: &contour_v1.RemoteAddressDescriptor{},
+									},
+									Value: "10.0.0.1",
+								},
+							},
+							Requests: 5,
+							Unit:     "second",
+						},
+					},
+				},
+			},
+			wantErr: "unit \"second\" in local rate limit descriptor must not be shorter than the policy unit",
+		},
+		"local - wildcard descriptor entry": {
+			in: &contour_v1.RateLimitPolicy{
+				Local: &contour_v1.LocalRateLimitPolicy{
+					Requests:              100,
+					Unit:                  "second",
+					MaxDynamicDescriptors: 1000,
+					Descriptors: []contour_v1.LocalRateLimitDescriptor{
+						{
+							Entries: []contour_v1.LocalRateLimitDescriptorEntry{
+								{
+									RateLimitDescriptorEntry: contour_v1.RateLimitDescriptorEntry{
+										RemoteAddress: &contour_v1.RemoteAddressDescriptor{},
+									},
+								},
+							},
+							Requests: 5,
+							Unit:     "minute",
+						},
+					},
+				},
+			},
+			want: &RateLimitPolicy{
+				Local: &LocalRateLimitPolicy{
+					MaxTokens:             100,
+					TokensPerFill:         100,
+					FillInterval:          time.Second,
+					MaxDynamicDescriptors: 1000,
+					Descriptors: []*LocalRateLimitDescriptor{
+						{
+							Entries: []LocalRateLimitDescriptorEntry{
+								{
+									RateLimitDescriptorEntry: RateLimitDescriptorEntry{
+										RemoteAddress: &RemoteAddressDescriptorEntry{},
+									},
+								},
+							},
+							MaxTokens:     5,
+							TokensPerFill: 5,
+							FillInterval:  time.Minute,
+						},
+					},
+				},
+			},
+		},
+		"local - descriptor entry with static value": {
+			in: &contour_v1.RateLimitPolicy{
+				Local: &contour_v1.LocalRateLimitPolicy{
+					Requests: 100,
+					Unit:     "second",
+					Descriptors: []contour_v1.LocalRateLimitDescriptor{
+						{
+							Entries: []contour_v1.LocalRateLimitDescriptorEntry{
+								{
+									RateLimitDescriptorEntry: contour_v1.RateLimitDescriptorEntry{
+										GenericKey: &contour_v1.GenericKeyDescriptor{Value: "api"},
+									},
+									Value: "api",
+								},
+							},
+							Requests: 5,
+							Unit:     "minute",
+						},
+					},
+				},
+			},
+			wantErr: "value must not be set for generic key and header value match descriptor entries",
+		},
+		"local - metadata descriptor entry": {
+			in: &contour_v1.RateLimitPolicy{
+				Local: &contour_v1.LocalRateLimitPolicy{
+					Requests: 100,
+					Unit:     "second",
+					Descriptors: []contour_v1.LocalRateLimitDescriptor{
+						{
+							Entries: []contour_v1.LocalRateLimitDescriptorEntry{
+								{
+									RateLimitDescriptorEntry: contour_v1.RateLimitDescriptorEntry{
+										Metadata: &contour_v1.MetadataDescriptor{
+											Filter:        "envoy.filters.http.jwt_authn",
+											Path:          []string{"provider-1", "sub"},
+											DescriptorKey: "subject",
+										},
+									},
+									Value: "alice",
+								},
+							},
+							Requests: 5,
+							Unit:     "minute",
+						},
+					},
+				},
+			},
+			wantErr: "metadata descriptor entries are not supported for local rate limiting",
+		},
+		"local - descriptor without entries": {
+			in: &contour_v1.RateLimitPolicy{
+				Local: &contour_v1.LocalRateLimitPolicy{
+					Requests: 100,
+					Unit:     "second",
+					Descriptors: []contour_v1.LocalRateLimitDescriptor{
+						{
+							Entries:  []contour_v1.LocalRateLimitDescriptorEntry{},
+							Requests: 5,
+							Unit:     "minute",
+						},
+					},
+				},
+			},
+			wantErr: "local rate limit descriptor must have at least one entry",
+		},
 		"global - multiple descriptors": {
 			in: &contour_v1.RateLimitPolicy{
 				Global: &contour_v1.GlobalRateLimitPolicy{
@@ -957,6 +1367,109 @@ func TestRateLimitPolicy(t *testing.T) {
 				},
 			},
 		},
+		"global - query parameter, metadata, masked remote address and destination cluster": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									QueryParameter: &contour_v1.QueryParameterDescriptor{
+										ParameterName: "api_key",
+										DescriptorKey: "api-key",
+									},
+								},
+								{
+									Metadata: &contour_v1.MetadataDescriptor{
+										Filter:        "envoy.filters.http.jwt_authn",
+										Path:          []string{"provider-1", "sub"},
+										DescriptorKey: "subject",
+										DefaultValue:  "anonymous",
+									},
+								},
+								{
+									MaskedRemoteAddress: &contour_v1.MaskedRemoteAddressDescriptor{
+										V4PrefixLength: 24,
+									},
+								},
+								{
+									DestinationCluster: &contour_v1.DestinationClusterDescriptor{},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &RateLimitPolicy{
+				Global: &GlobalRateLimitPolicy{
+					Descriptors: []*RateLimitDescriptor{
+						{
+							Entries: []RateLimitDescriptorEntry{
+								{
+									QueryParameter: &QueryParameterDescriptorEntry{
+										ParameterName: "api_key",
+										Key:           "api-key",
+									},
+								},
+								{
+									Metadata: &MetadataDescriptorEntry{
+										Filter:       "envoy.filters.http.jwt_authn",
+										Path:         []string{"provider-1", "sub"},
+										Key:          "subject",
+										DefaultValue: "anonymous",
+									},
+								},
+								{
+									MaskedRemoteAddress: &MaskedRemoteAddressDescriptorEntry{
+										V4PrefixLength: 24,
+									},
+								},
+								{
+									DestinationCluster: &DestinationClusterDescriptorEntry{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"global - masked remote address prefix length too long": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									MaskedRemoteAddress: &contour_v1.MaskedRemoteAddressDescriptor{
+										V6PrefixLength: 129,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "masked remote address prefix length must be at most 32 for IPv4 and 128 for IPv6",
+		},
+		"global - metadata without path": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									Metadata: &contour_v1.MetadataDescriptor{
+										Filter:        "envoy.filters.http.jwt_authn",
+										DescriptorKey: "subject",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "metadata descriptor entry must have a filter and a path",
+		},
 		"global and local": {
 			in: &contour_v1.RateLimitPolicy{
 				Local: &contour_v1.LocalRateLimitPolicy{
@@ -20,7 +20,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"golang.org/x/crypto/ocsp"
 	core_v1 "k8s.io/api/core/v1"
 )
 
@@ -30,6 +32,13 @@ const (
 
 	// CRLKey is the key name for accessing CRL bundles in Kubernetes Secrets.
 	CRLKey = "crl.pem"
+
+	// JWKSKey is the key name for accessing JWKSes in Kubernetes ConfigMaps and Secrets.
+	JWKSKey = "jwks.json"
+
+	// OCSPStapleKey is the key name for accessing DER encoded OCSP
+	// responses for the serving certificate in Kubernetes Secrets.
+	OCSPStapleKey = "tls.ocsp-staple"
 )
 
 // validTLSSecret returns an error if the Secret is not of type TLS or Opaque or
@@ -58,6 +67,12 @@ func validTLSSecret(secret *core_v1.Secret) error {
 		return fmt.Errorf("invalid TLS private key: %v", err)
 	}
 
+	if staple := secret.Data[OCSPStapleKey]; len(staple) > 0 {
+		if err := validateOCSPStaple(staple, secret.Data[core_v1.TLSCertKey]); err != nil {
+			return fmt.Errorf("invalid OCSP staple: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -239,6 +254,69 @@ func validatePrivateKey(data []byte) error {
 	}
 }
 
+// validateOCSPStaple checks that the staple is a DER encoded OCSP
+// response for the first certificate in the serving bundle, signed by
+// that certificate's issuer if the bundle holds it. Envoy rejects the
+// whole listener if a staple does not match its certificate.
+func validateOCSPStaple(staple []byte, bundle []byte) error {
+	var certs []*x509.Certificate
+	for containsPEMHeader(bundle) {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return errors.New("failed to locate certificate")
+	}
+
+	cert := certs[0]
+	resp, err := ocsp.ParseResponse(staple, issuerOf(cert, certs))
+	if err != nil {
+		return err
+	}
+	if resp.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		return errors.New("response does not match the serving certificate")
+	}
+
+	return nil
+}
+
+// issuerOf returns the certificate in the chain that issued cert,
+// or nil if the chain does not hold it. A self-issued certificate
+// is its own issuer.
+func issuerOf(cert *x509.Certificate, chain []*x509.Certificate) *x509.Certificate {
+	for _, c := range chain[1:] {
+		if bytes.Equal(c.RawSubject, cert.RawIssuer) {
+			return c
+		}
+	}
+	if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return cert
+	}
+	return nil
+}
+
+// ocspStapleWindow returns the time range in which the DER encoded
+// OCSP response is current. The end of the range is zero if the
+// responder did not set a next update time.
+func ocspStapleWindow(staple []byte) (time.Time, time.Time, error) {
+	resp, err := ocsp.ParseResponse(staple, nil)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return resp.ThisUpdate, resp.NextUpdate, nil
+}
+
 // validateCRL checks that PEM file contains at least one CRL.
 func validateCRL(data []byte) error {
 	for containsPEMHeader(data) {
@@ -254,3 +332,30 @@ func validateCRL(data []byte) error {
 
 	return errors.New("failed to locate CRL")
 }
+
+// certificatesNotAfter returns the earliest expiry time of the
+// certificates in the given PEM bundle, or the zero time if the
+// bundle holds no parsable certificate.
+func certificatesNotAfter(data []byte) time.Time {
+	var notAfter time.Time
+
+	for containsPEMHeader(data) {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if notAfter.IsZero() || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+		}
+	}
+
+	return notAfter
+}
@@ -14,11 +14,21 @@
 package dag
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
 	core_v1 "k8s.io/api/core/v1"
 
 	"github.com/projectcontour/contour/internal/fixture"
@@ -246,6 +256,94 @@ func TestValidSecrets(t *testing.T) {
 	}
 }
 
+func TestValidOCSPStaple(t *testing.T) {
+	cert, key := selfSignedCertificate(t, time.Now().Add(time.Hour))
+	otherCert, otherKey := selfSignedCertificate(t, time.Now().Add(time.Hour))
+
+	tests := map[string]struct {
+		staple  []byte
+		wantErr string
+	}{
+		"no staple": {
+			staple: nil,
+		},
+		"matching staple": {
+			staple: ocspStaple(t, cert, key, 1),
+		},
+		"staple for another certificate": {
+			staple:  ocspStaple(t, cert, key, 2),
+			wantErr: "invalid OCSP staple: response does not match the serving certificate",
+		},
+		"staple signed by another issuer": {
+			staple:  ocspStaple(t, otherCert, otherKey, 1),
+			wantErr: "invalid OCSP staple: ",
+		},
+		"malformed staple": {
+			staple:  []byte("not an OCSP response"),
+			wantErr: "invalid OCSP staple: ",
+		},
+		// Whether a stale staple can be served depends on the
+		// OCSP staple policy, see TestLookupTLSSecretRequireOCSPStaple.
+		"expired staple": {
+			staple: ocspStapleAt(t, cert, key, 1, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := secretdata(cert, key)
+			if tc.staple != nil {
+				data[OCSPStapleKey] = tc.staple
+			}
+
+			err := validTLSSecret(makeTLSSecret(data))
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestCertificatesNotAfter(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	early, _ := selfSignedCertificate(t, now.Add(time.Hour))
+	late, _ := selfSignedCertificate(t, now.Add(2*time.Hour))
+
+	tests := map[string]struct {
+		data []byte
+		want time.Time
+	}{
+		"single certificate": {
+			data: []byte(late),
+			want: now.Add(2 * time.Hour),
+		},
+		"earliest certificate in bundle": {
+			data: []byte(pemBundle(late, early)),
+			want: now.Add(time.Hour),
+		},
+		"bundle with non-PEM data": {
+			data: caBundleData(late, early)[CACertificateKey],
+			want: now.Add(time.Hour),
+		},
+		"no certificate": {
+			data: []byte(fixture.RSA_PRIVATE_KEY),
+			want: time.Time{},
+		},
+		"empty": {
+			data: nil,
+			want: time.Time{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, tc.want.Equal(certificatesNotAfter(tc.data)))
+		})
+	}
+}
+
 func secretdata(cert, key string) map[string][]byte {
 	return map[string][]byte{
 		core_v1.TLSCertKey:       []byte(cert),
@@ -295,3 +393,62 @@ func makeTLSSecret(data map[string][]byte) *core_v1.Secret {
 func makeOpaqueSecret(data map[string][]byte) *core_v1.Secret {
 	return &core_v1.Secret{Type: core_v1.SecretTypeOpaque, Data: data}
 }
+
+// selfSignedCertificate returns a PEM encoded self-signed certificate
+// expiring at notAfter, along with its PEM encoded private key.
+func selfSignedCertificate(t *testing.T, notAfter time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+// ocspStaple returns a DER encoded OCSP response for the given serial
+// number, signed by the given certificate and key.
+func ocspStaple(t *testing.T, certPEM, keyPEM string, serial int64) []byte {
+	now := time.Now()
+	return ocspStapleAt(t, certPEM, keyPEM, serial, now, now.Add(time.Hour))
+}
+
+// ocspStapleAt returns a DER encoded OCSP response for the given serial
+// number that is current between thisUpdate and nextUpdate, signed by
+// the given certificate and key.
+func ocspStapleAt(t *testing.T, certPEM, keyPEM string, serial int64, thisUpdate, nextUpdate time.Time) []byte {
+	t.Helper()
+
+	block, _ := pem.Decode([]byte(certPEM))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	block, _ = pem.Decode([]byte(keyPEM))
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	require.NoError(t, err)
+
+	staple, err := ocsp.CreateResponse(cert, cert, ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: big.NewInt(serial),
+		ThisUpdate:   thisUpdate,
+		NextUpdate:   nextUpdate,
+	}, key)
+	require.NoError(t, err)
+
+	return staple
+}
@@ -0,0 +1,89 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import "strings"
+
+// RouteStatsProcessor names the statistics Envoy emits for each
+// route and TCP proxy, and for the clusters they forward to, after
+// the HTTPProxy, Ingress or Gateway API route that defines them.
+//
+// It must run after the processors that add routes to the DAG,
+// and relies on them setting the source metadata of each route
+// and TCP proxy.
+type RouteStatsProcessor struct{}
+
+// Run sets the StatPrefix of the routes, TCP proxies and clusters
+// in the DAG.
+func (p *RouteStatsProcessor) Run(dag *DAG, _ *KubernetesCache) {
+	for _, listener := range dag.Listeners {
+		setTCPProxyStatPrefix(listener.TCPProxy)
+		for _, vh := range listener.VirtualHosts {
+			setStatPrefixes(vh.Routes)
+		}
+		for _, svh := range listener.SecureVirtualHosts {
+			setStatPrefixes(svh.Routes)
+			setTCPProxyStatPrefix(svh.TCPProxy)
+		}
+	}
+}
+
+func setStatPrefixes(routes map[string]*Route) {
+	for _, route := range routes {
+		prefix := statPrefix(route.Kind, route.Namespace, route.Name)
+		if prefix == "" {
+			continue
+		}
+
+		route.StatPrefix = prefix
+		for _, cluster := range route.Clusters {
+			cluster.StatPrefix = prefix
+		}
+		for _, mp := range route.MirrorPolicies {
+			mp.Cluster.StatPrefix = prefix
+		}
+	}
+}
+
+func setTCPProxyStatPrefix(proxy *TCPProxy) {
+	if proxy == nil {
+		return
+	}
+
+	prefix := statPrefix(proxy.SourceKind, proxy.SourceNamespace, proxy.SourceName)
+	if prefix == "" {
+		return
+	}
+
+	proxy.StatPrefix = prefix
+	for _, cluster := range proxy.Clusters {
+		cluster.StatPrefix = prefix
+	}
+}
+
+// statPrefix returns the stat prefix for a routing object, in
+// the form kind_namespace_name, or an empty string if the
+// source metadata is not set. Dots separate the elements of
+// Envoy stat names, so they are replaced in object names.
+func statPrefix(kind, namespace, name string) string {
+	if kind == "" || namespace == "" || name == "" {
+		return ""
+	}
+
+	return strings.Join([]string{
+		strings.ToLower(kind),
+		namespace,
+		strings.ReplaceAll(name, ".", "_"),
+	}, "_")
+}
@@ -0,0 +1,104 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteStatsProcessor(t *testing.T) {
+	proxyCluster := &Cluster{}
+	mirrorCluster := &Cluster{}
+	proxyRoute := &Route{
+		Kind:           "HTTPProxy",
+		Namespace:      "default",
+		Name:           "www.example.com",
+		Clusters:       []*Cluster{proxyCluster},
+		MirrorPolicies: []*MirrorPolicy{{Cluster: mirrorCluster}},
+	}
+
+	routeCluster := &Cluster{}
+	httpRoute := &Route{
+		Kind:      "HTTPRoute",
+		Namespace: "projectcontour",
+		Name:      "basic",
+		Clusters:  []*Cluster{routeCluster},
+	}
+
+	unnamedCluster := &Cluster{}
+	unnamedRoute := &Route{
+		Clusters: []*Cluster{unnamedCluster},
+	}
+
+	tcpProxyCluster := &Cluster{}
+	tcpProxy := &TCPProxy{
+		SourceKind:      "HTTPProxy",
+		SourceNamespace: "default",
+		SourceName:      "tcp.example.com",
+		Clusters:        []*Cluster{tcpProxyCluster},
+	}
+
+	tcpRouteCluster := &Cluster{}
+	tcpRoute := &TCPProxy{
+		SourceKind:      "TCPRoute",
+		SourceNamespace: "projectcontour",
+		SourceName:      "tcp",
+		Clusters:        []*Cluster{tcpRouteCluster},
+	}
+
+	dag := &DAG{
+		Listeners: map[string]*Listener{
+			HTTP_LISTENER_NAME: {
+				VirtualHosts: []*VirtualHost{{
+					Routes: map[string]*Route{"proxy": proxyRoute, "unnamed": unnamedRoute},
+				}},
+			},
+			HTTPS_LISTENER_NAME: {
+				SecureVirtualHosts: []*SecureVirtualHost{
+					{
+						VirtualHost: VirtualHost{
+							Routes: map[string]*Route{"route": httpRoute},
+						},
+					},
+					{
+						TCPProxy: tcpProxy,
+					},
+				},
+			},
+			"tcp-8000": {
+				TCPProxy: tcpRoute,
+			},
+		},
+	}
+
+	(&RouteStatsProcessor{}).Run(dag, nil)
+
+	assert.Equal(t, "httpproxy_default_www_example_com", proxyRoute.StatPrefix)
+	assert.Equal(t, "httpproxy_default_www_example_com", proxyCluster.StatPrefix)
+	assert.Equal(t, "httpproxy_default_www_example_com", mirrorCluster.StatPrefix)
+
+	assert.Equal(t, "httproute_projectcontour_basic", httpRoute.StatPrefix)
+	assert.Equal(t, "httproute_projectcontour_basic", routeCluster.StatPrefix)
+
+	assert.Empty(t, unnamedRoute.StatPrefix)
+	assert.Empty(t, unnamedCluster.StatPrefix)
+
+	assert.Equal(t, "httpproxy_default_tcp_example_com", tcpProxy.StatPrefix)
+	assert.Equal(t, "httpproxy_default_tcp_example_com", tcpProxyCluster.StatPrefix)
+
+	assert.Equal(t, "tcproute_projectcontour_tcp", tcpRoute.StatPrefix)
+	assert.Equal(t, "tcproute_projectcontour_tcp", tcpRouteCluster.StatPrefix)
+}
@@ -31,6 +31,7 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/fixture"
@@ -847,6 +848,39 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	// proxyInvalidServiceImportInvalid is invalid because it references a missing
+	// ServiceImport, even though a Service of the same name exists.
+	proxyInvalidServiceImportInvalid := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "invalidir",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Kind: "ServiceImport",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "proxy missing serviceimport is invalid", testcase{
+		objs: []any{proxyInvalidServiceImportInvalid, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyInvalidServiceImportInvalid.Name, Namespace: proxyInvalidServiceImportInvalid.Namespace}: fixture.NewValidCondition().
+				WithGeneration(proxyInvalidServiceImportInvalid.Generation).
+				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: serviceimport "roots/home" not found`),
+		},
+	})
+
 	// proxyInvalidServicePortInvalid is invalid because it references an invalid port on a service
 	proxyInvalidServicePortInvalid := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
@@ -1014,6 +1048,50 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	expiredCert, expiredKey := selfSignedCertificate(t, time.Now().Add(-time.Hour).Truncate(time.Second))
+	secretExpired := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "expired",
+			Namespace: "roots",
+		},
+		Type: core_v1.SecretTypeTLS,
+		Data: secretdata(expiredCert, expiredKey),
+	}
+	expiredNotAfter, _ := (&Secret{Object: secretExpired}).CertificateExpiry()
+
+	proxyExpiredCertificate := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "expired",
+			Namespace: "roots",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "expired.example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: secretExpired.Name,
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "root proxy with expired TLS certificate", testcase{
+		objs: []any{secretExpired, fixture.ServiceRootsKuard, proxyExpiredCertificate},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyExpiredCertificate.Name, Namespace: proxyExpiredCertificate.Namespace}: func() contour_v1.DetailedCondition {
+				dc := fixture.NewValidCondition().WithGeneration(proxyExpiredCertificate.Generation).Valid()
+				dc.AddWarningf(contour_v1.ConditionTypeTLSError, "CertificateExpired",
+					`Spec.VirtualHost.TLS Secret "expired" certificate expired at %s`, expiredNotAfter.UTC().Format(time.RFC3339))
+				return dc
+			}(),
+		},
+	})
+
 	proxyIncludesRootDifferentFQDN := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "root-blog",
@@ -1579,6 +1657,68 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	proxyTCPInvalidIdleTimeout := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "roots",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "passthrough.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				IdleTimeout: "invalid",
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	run(t, "tcpproxy invalid idle timeout", testcase{
+		objs: []any{proxyTCPInvalidIdleTimeout, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyTCPInvalidIdleTimeout.Name, Namespace: proxyTCPInvalidIdleTimeout.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTCPProxyError, "IdleTimeoutInvalid", `Spec.TCPProxy.IdleTimeout is invalid: unable to parse timeout string "invalid": time: invalid duration "invalid"`),
+		},
+	})
+
+	proxyTCPInvalidAccessLogFormat := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "roots",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "passthrough.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				AccessLog: &contour_v1.TCPProxyAccessLog{
+					Format: "%UPSTREAM_HOST%",
+				},
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	run(t, "tcpproxy invalid access log format", testcase{
+		objs: []any{proxyTCPInvalidAccessLogFormat, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyTCPInvalidAccessLogFormat.Name, Namespace: proxyTCPInvalidAccessLogFormat.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTCPProxyError, "AccessLogFormatInvalid", "Spec.TCPProxy.AccessLog.Format is invalid: invalid access log format: must end in newline"),
+		},
+	})
+
 	proxyTCPIncludesFoo := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "simple",
@@ -2938,6 +3078,73 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	tlsAdditionalSecretNamesOnly := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					AdditionalSecretNames: []string{fixture.SecretRootsCert.Name},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "httpproxy with TLS additional secret names but no secret name", testcase{
+		objs: []any{
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsKuard,
+			tlsAdditionalSecretNamesOnly,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: "invalid", Namespace: fixture.ServiceRootsKuard.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTLSError, "TLSConfigNotValid", "Spec.VirtualHost.TLS: AdditionalSecretNames requires SecretName to be specified"),
+		},
+	})
+
+	tlsDuplicateSecretNames := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName:            fixture.SecretRootsCert.Name,
+					AdditionalSecretNames: []string{fixture.SecretRootsCert.Namespace + "/" + fixture.SecretRootsCert.Name},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "httpproxy with the same TLS secret specified twice", testcase{
+		objs: []any{
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsKuard,
+			tlsDuplicateSecretNames,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: "invalid", Namespace: fixture.ServiceRootsKuard.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTLSError, "DuplicateSecret", `Spec.VirtualHost.TLS Secret "roots/ssl-cert" is specified more than once`),
+		},
+	})
+
 	emptyProxy := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "empty",
@@ -3448,7 +3655,7 @@ func TestDAGStatus(t *testing.T) {
 						Name:      "provider-1",
 						Issuer:    "jwt.example.com",
 						Audiences: []string{"foo", "bar"},
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "10s",
 							CacheDuration: "1h",
@@ -3496,13 +3703,13 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 						},
 					},
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 						},
 					},
@@ -3553,14 +3760,14 @@ func TestDAGStatus(t *testing.T) {
 					{
 						Name:    "provider-1",
 						Default: true,
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 						},
 					},
 					{
 						Name:    "provider-2",
 						Default: true,
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 						},
 					},
@@ -3610,7 +3817,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: ":/invalid-uri",
 						},
 					},
@@ -3660,7 +3867,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "ftp://jwt.example.com/jwks.json",
 						},
 					},
@@ -3710,7 +3917,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:     "http://jwt.example.com/jwks.json",
 							Timeout: "invalid-timeout-string",
 						},
@@ -3761,7 +3968,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "http://jwt.example.com/jwks.json",
 							CacheDuration: "invalid-duration-string",
 						},
@@ -3812,7 +4019,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:             "http://jwt.example.com/jwks.json",
 							DNSLookupFamily: "v7",
 						},
@@ -3849,10 +4056,10 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
-	jwtVerificationNoProvidersRouteHasRef := &contour_v1.HTTPProxy{
+	jwtVerificationNoJWKSSource := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-no-providers-route-has-ref",
+			Name:      "jwt-verification-no-jwks-source",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
@@ -3860,6 +4067,11 @@ func TestDAGStatus(t *testing.T) {
 				TLS: &contour_v1.TLS{
 					SecretName: fixture.SecretRootsCert.Name,
 				},
+				JWTProviders: []contour_v1.JWTProvider{
+					{
+						Name: "provider-1",
+					},
+				},
 			},
 			Routes: []contour_v1.Route{
 				{
@@ -3870,32 +4082,31 @@ func TestDAGStatus(t *testing.T) {
 						Name: "home",
 						Port: 8080,
 					}},
-					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "provider-1"},
 				},
 			},
 		},
 	}
 
-	run(t, "JWT verification no providers defined, route has provider ref", testcase{
+	run(t, "JWT verification provider without a JWKS source", testcase{
 		objs: []any{
-			jwtVerificationNoProvidersRouteHasRef,
+			jwtVerificationNoJWKSSource,
 			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationNoProvidersRouteHasRef): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationNoJWKSSource): fixture.NewValidCondition().
 				WithError(
 					contour_v1.ConditionTypeJWTVerificationError,
-					"JWTProviderNotDefined",
-					"Route references an undefined JWT provider \"provider-1\"",
+					"JWKSSourceInvalid",
+					"Spec.VirtualHost.JWTProviders is invalid: one of RemoteJWKS and LocalJWKS must be specified",
 				),
 		},
 	})
 
-	jwtVerificationRouteReferencesNonexistentProvider := &contour_v1.HTTPProxy{
+	jwtVerificationMissingLocalJWKS := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-route-references-nonexistent-provider",
+			Name:      "jwt-verification-missing-local-jwks",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
@@ -3906,15 +4117,14 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
-							URI: "http://jwt.example.com/jwks.json",
+						LocalJWKS: &contour_v1.LocalJWKS{
+							Name: "missing",
 						},
 					},
 				},
 			},
 			Routes: []contour_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "nonexistent-provider"},
 					Conditions: []contour_v1.MatchCondition{{
 						Prefix: "/foo",
 					}},
@@ -3927,34 +4137,37 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification route references nonexistent provider", testcase{
+	run(t, "JWT verification local JWKS not found", testcase{
 		objs: []any{
-			jwtVerificationRouteReferencesNonexistentProvider,
+			jwtVerificationMissingLocalJWKS,
 			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationRouteReferencesNonexistentProvider): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationMissingLocalJWKS): fixture.NewValidCondition().
 				WithError(
 					contour_v1.ConditionTypeJWTVerificationError,
-					"JWTProviderNotDefined",
-					"Route references an undefined JWT provider \"nonexistent-provider\"",
+					"LocalJWKSInvalid",
+					"Spec.VirtualHost.JWTProviders.LocalJWKS ConfigMap \"missing\" is invalid: ConfigMap not found or is missing the \"jwks.json\" key",
 				),
 		},
 	})
 
-	jwtVerificationInvalidTLSNotConfigured := &contour_v1.HTTPProxy{
+	jwtVerificationRequiredClaimsWithoutProvider := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-invalid-tls-not-configured",
+			Name:      "jwt-verification-required-claims-without-provider",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
 				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 						},
 					},
@@ -3962,10 +4175,15 @@ func TestDAGStatus(t *testing.T) {
 			},
 			Routes: []contour_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "provider-1"},
 					Conditions: []contour_v1.MatchCondition{{
 						Prefix: "/foo",
 					}},
+					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{
+						RequiredClaims: []contour_v1.JWTRequiredClaim{{
+							Name:   "groups",
+							Values: []string{"admin"},
+						}},
+					},
 					Services: []contour_v1.Service{{
 						Name: "home",
 						Port: 8080,
@@ -3975,44 +4193,41 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification invalid TLS not configured", testcase{
+	run(t, "JWT verification required claims without a provider", testcase{
 		objs: []any{
-			jwtVerificationInvalidTLSNotConfigured,
+			jwtVerificationRequiredClaimsWithoutProvider,
+			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidTLSNotConfigured): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationRequiredClaimsWithoutProvider): fixture.NewValidCondition().
 				WithError(
 					contour_v1.ConditionTypeJWTVerificationError,
-					"JWTVerificationNotPermitted",
-					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+					"InvalidJWTVerificationPolicy",
+					"route's JWT verification policy cannot specify required claims without requiring a JWT provider",
 				),
 		},
 	})
 
-	jwtVerificationInvalidTLSPassthroughConfigured := &contour_v1.HTTPProxy{
+	authorizationPolicyJWTClaimsWithoutProvider := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-invalid-tls-passthrough-configured",
+			Name:      "authorization-policy-jwt-claims-without-provider",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
 				Fqdn: "example.com",
-				TLS: &contour_v1.TLS{
-					Passthrough: true,
-				},
-				JWTProviders: []contour_v1.JWTProvider{
-					{
-						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-						},
-					},
+				AuthorizationPolicy: &contour_v1.RBACPolicy{
+					Allow: []contour_v1.RBACRule{{
+						JWTClaims: []contour_v1.JWTRequiredClaim{{
+							Name:   "groups",
+							Values: []string{"admin"},
+						}},
+					}},
 				},
 			},
 			Routes: []contour_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "provider-1"},
 					Conditions: []contour_v1.MatchCondition{{
 						Prefix: "/foo",
 					}},
@@ -4025,44 +4240,35 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification invalid TLS passthrough configured", testcase{
+	run(t, "authorization policy matching JWT claims without a JWT provider", testcase{
 		objs: []any{
-			jwtVerificationInvalidTLSPassthroughConfigured,
+			authorizationPolicyJWTClaimsWithoutProvider,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidTLSPassthroughConfigured): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(authorizationPolicyJWTClaimsWithoutProvider): fixture.NewValidCondition().
 				WithError(
-					contour_v1.ConditionTypeJWTVerificationError,
-					"JWTVerificationNotPermitted",
-					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+					contour_v1.ConditionTypeAuthorizationPolicyError,
+					"AuthorizationPolicyNotValid",
+					"Spec.VirtualHost.AuthorizationPolicy cannot match JWT claims for routes that do not require a JWT provider",
 				),
 		},
 	})
 
-	jwtVerificationInvalidTLSFallbackConfigured := &contour_v1.HTTPProxy{
+	jwtVerificationNoProvidersRouteHasRef := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-invalid-tls-fallback-configured",
+			Name:      "jwt-verification-no-providers-route-has-ref",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
 				Fqdn: "example.com",
 				TLS: &contour_v1.TLS{
-					EnableFallbackCertificate: true,
-				},
-				JWTProviders: []contour_v1.JWTProvider{
-					{
-						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
-						},
-					},
+					SecretName: fixture.SecretRootsCert.Name,
 				},
 			},
 			Routes: []contour_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "provider-1"},
 					Conditions: []contour_v1.MatchCondition{{
 						Prefix: "/foo",
 					}},
@@ -4070,30 +4276,32 @@ func TestDAGStatus(t *testing.T) {
 						Name: "home",
 						Port: 8080,
 					}},
+					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "provider-1"},
 				},
 			},
 		},
 	}
 
-	run(t, "JWT verification invalid TLS fallback configured", testcase{
+	run(t, "JWT verification no providers defined, route has provider ref", testcase{
 		objs: []any{
-			jwtVerificationInvalidTLSFallbackConfigured,
+			jwtVerificationNoProvidersRouteHasRef,
+			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidTLSFallbackConfigured): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationNoProvidersRouteHasRef): fixture.NewValidCondition().
 				WithError(
 					contour_v1.ConditionTypeJWTVerificationError,
-					"JWTVerificationNotPermitted",
-					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+					"JWTProviderNotDefined",
+					"Route references an undefined JWT provider \"provider-1\"",
 				),
 		},
 	})
 
-	jwtVerificationInvalidRequireAndDisabledSpecified := &contour_v1.HTTPProxy{
+	jwtVerificationRouteReferencesNonexistentProvider := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace: "roots",
-			Name:      "jwt-verification-invalid-require-and-disabled-specified",
+			Name:      "jwt-verification-route-references-nonexistent-provider",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
@@ -4104,18 +4312,15 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
-							URI: "https://jwt.example.com/jwks.json",
+						RemoteJWKS: &contour_v1.RemoteJWKS{
+							URI: "http://jwt.example.com/jwks.json",
 						},
 					},
 				},
 			},
 			Routes: []contour_v1.Route{
 				{
-					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{
-						Require:  "provider-1",
-						Disabled: true,
-					},
+					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "nonexistent-provider"},
 					Conditions: []contour_v1.MatchCondition{{
 						Prefix: "/foo",
 					}},
@@ -4128,18 +4333,219 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "JWT verification invalid route specifies both requires and disabled", testcase{
+	run(t, "JWT verification route references nonexistent provider", testcase{
 		objs: []any{
-			jwtVerificationInvalidRequireAndDisabledSpecified,
+			jwtVerificationRouteReferencesNonexistentProvider,
 			fixture.SecretRootsCert,
 			fixture.ServiceRootsHome,
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			k8s.NamespacedNameOf(jwtVerificationInvalidRequireAndDisabledSpecified): fixture.NewValidCondition().
+			k8s.NamespacedNameOf(jwtVerificationRouteReferencesNonexistentProvider): fixture.NewValidCondition().
 				WithError(
 					contour_v1.ConditionTypeJWTVerificationError,
-					"InvalidJWTVerificationPolicy",
-					"route's JWT verification policy cannot specify both require and disabled",
+					"JWTProviderNotDefined",
+					"Route references an undefined JWT provider \"nonexistent-provider\"",
+				),
+		},
+	})
+
+	jwtVerificationInvalidTLSNotConfigured := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-invalid-tls-not-configured",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				JWTProviders: []contour_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: &contour_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "provider-1"},
+					Conditions: []contour_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid TLS not configured", testcase{
+		objs: []any{
+			jwtVerificationInvalidTLSNotConfigured,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidTLSNotConfigured): fixture.NewValidCondition().
+				WithError(
+					contour_v1.ConditionTypeJWTVerificationError,
+					"JWTVerificationNotPermitted",
+					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+				),
+		},
+	})
+
+	jwtVerificationInvalidTLSPassthroughConfigured := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-invalid-tls-passthrough-configured",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+				JWTProviders: []contour_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: &contour_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "provider-1"},
+					Conditions: []contour_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid TLS passthrough configured", testcase{
+		objs: []any{
+			jwtVerificationInvalidTLSPassthroughConfigured,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidTLSPassthroughConfigured): fixture.NewValidCondition().
+				WithError(
+					contour_v1.ConditionTypeJWTVerificationError,
+					"JWTVerificationNotPermitted",
+					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+				),
+		},
+	})
+
+	jwtVerificationInvalidTLSFallbackConfigured := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-invalid-tls-fallback-configured",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					EnableFallbackCertificate: true,
+				},
+				JWTProviders: []contour_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: &contour_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{Require: "provider-1"},
+					Conditions: []contour_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid TLS fallback configured", testcase{
+		objs: []any{
+			jwtVerificationInvalidTLSFallbackConfigured,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidTLSFallbackConfigured): fixture.NewValidCondition().
+				WithError(
+					contour_v1.ConditionTypeJWTVerificationError,
+					"JWTVerificationNotPermitted",
+					"Spec.VirtualHost.JWTProviders can only be defined for root HTTPProxies that terminate TLS",
+				),
+		},
+	})
+
+	jwtVerificationInvalidRequireAndDisabledSpecified := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-invalid-require-and-disabled-specified",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: &contour_v1.RemoteJWKS{
+							URI: "https://jwt.example.com/jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_v1.Route{
+				{
+					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{
+						Require:  "provider-1",
+						Disabled: true,
+					},
+					Conditions: []contour_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification invalid route specifies both requires and disabled", testcase{
+		objs: []any{
+			jwtVerificationInvalidRequireAndDisabledSpecified,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationInvalidRequireAndDisabledSpecified): fixture.NewValidCondition().
+				WithError(
+					contour_v1.ConditionTypeJWTVerificationError,
+					"InvalidJWTVerificationPolicy",
+					"route's JWT verification policy cannot specify both require and disabled",
 				),
 		},
 	})
@@ -4158,7 +4564,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "http://jwt.example.com/jwks.json",
 							UpstreamValidation: &contour_v1.UpstreamValidation{
 								CACertificate: "foo",
@@ -4215,7 +4621,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 							UpstreamValidation: &contour_v1.UpstreamValidation{
 								CACertificate: "nonexistent",
@@ -4280,7 +4686,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 							UpstreamValidation: &contour_v1.UpstreamValidation{
 								CACertificate: "cacert",
@@ -4345,7 +4751,7 @@ func TestDAGStatus(t *testing.T) {
 				JWTProviders: []contour_v1.JWTProvider{
 					{
 						Name: "provider-1",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 							UpstreamValidation: &contour_v1.UpstreamValidation{
 								CACertificate: "default/cacert",
@@ -6706,6 +7112,58 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 		wantGatewayStatusUpdate: validGatewayStatusUpdate("https", gatewayapi_v1.HTTPSProtocolType, 0),
 	})
 
+	expiredCert, expiredKey := selfSignedCertificate(t, time.Now().Add(-time.Hour).Truncate(time.Second))
+	expiredNotAfter, _ := (&Secret{Object: &core_v1.Secret{Data: secretdata(expiredCert, expiredKey)}}).CertificateExpiry()
+
+	run(t, "Gateway references expired TLS cert", testcase{
+		gateway: &gatewayapi_v1.Gateway{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "contour",
+				Namespace: "projectcontour",
+			},
+			Spec: gatewayapi_v1.GatewaySpec{
+				GatewayClassName: gatewayapi_v1.ObjectName("projectcontour.io/contour"),
+				Listeners: []gatewayapi_v1.Listener{{
+					Name:     "https",
+					Port:     443,
+					Protocol: gatewayapi_v1.HTTPSProtocolType,
+					TLS: &gatewayapi_v1.GatewayTLSConfig{
+						Mode: ptr.To(gatewayapi_v1.TLSModeTerminate),
+						CertificateRefs: []gatewayapi_v1.SecretObjectReference{
+							gatewayapi.CertificateRef("expired", ""),
+						},
+					},
+					AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1.RouteNamespaces{
+							From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+						},
+					},
+				}},
+			},
+		},
+		objs: []any{
+			&core_v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "expired",
+					Namespace: "projectcontour",
+				},
+				Type: core_v1.SecretTypeTLS,
+				Data: secretdata(expiredCert, expiredKey),
+			},
+		},
+		wantGatewayStatusUpdate: func() []*status.GatewayStatusUpdate {
+			updates := validGatewayStatusUpdate("https", gatewayapi_v1.HTTPSProtocolType, 0)
+			listener := updates[0].ListenerStatus["https"]
+			listener.Conditions = append(listener.Conditions, meta_v1.Condition{
+				Type:    string(status.ListenerConditionCertificateValid),
+				Status:  meta_v1.ConditionFalse,
+				Reason:  "CertificateExpired",
+				Message: "Listener.TLS.CertificateRefs certificate expired at " + expiredNotAfter.UTC().Format(time.RFC3339),
+			})
+			return updates
+		}(),
+	})
+
 	run(t, "Gateway references TLS cert in different namespace, with invalid ReferenceGrant (policy in wrong namespace)", testcase{
 		gateway: &gatewayapi_v1.Gateway{
 			ObjectMeta: meta_v1.ObjectMeta{
@@ -8562,7 +9020,7 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 				Namespace: "projectcontour",
 			},
 			Spec: gatewayapi_v1.GatewaySpec{
-				Addresses: []gatewayapi_v1.GatewayAddress{{
+				Addresses: []gatewayapi_v1.GatewaySpecAddress{{
 					Value: "1.2.3.4",
 				}},
 				Listeners: []gatewayapi_v1.Listener{{
@@ -9216,7 +9674,7 @@ func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
 							Type:    string(gatewayapi_v1.ListenerConditionProgrammed),
 							Status:  meta_v1.ConditionFalse,
 							Reason:  string(gatewayapi_v1.ListenerReasonInvalid),
-							Message: "Listener.TLS.CertificateRefs must contain exactly one entry",
+							Message: "Listener.TLS.CertificateRefs must contain at least one entry",
 						},
 						listenerAcceptedCondition(),
 						listenerResolvedRefsCondition(),
@@ -12328,6 +12786,750 @@ func TestGatewayAPIBackendTLSPolicyDAGStatus(t *testing.T) {
 			},
 		}},
 	})
+
+	run(t, "grpcroute with backendtlspolicy", testcase{
+		objs: []any{
+			tlsService,
+			configMapCert1,
+			&gatewayapi_v1.GRPCRoute{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "projectcontour",
+				},
+				Spec: gatewayapi_v1.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Rules: []gatewayapi_v1.GRPCRouteRule{{
+						Matches: []gatewayapi_v1.GRPCRouteMatch{{
+							Method: gatewayapi.GRPCMethodMatch(gatewayapi_v1.GRPCMethodMatchExact, "com.example.service", "Login"),
+						}},
+						BackendRefs: gatewayapi.GRPCRouteBackendRef("tlssvc", 443, 1),
+					}},
+				},
+			},
+			&gatewayapi_v1alpha3.BackendTLSPolicy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "projectcontour",
+				},
+				Spec: gatewayapi_v1alpha3.BackendTLSPolicySpec{
+					TargetRefs: []gatewayapi_v1alpha2.LocalPolicyTargetReferenceWithSectionName{
+						{
+							LocalPolicyTargetReference: gatewayapi_v1alpha2.LocalPolicyTargetReference{
+								Kind: "Service",
+								Name: "tlssvc",
+							},
+						},
+					},
+					Validation: gatewayapi_v1alpha3.BackendTLSPolicyValidation{
+						CACertificateRefs: []gatewayapi_v1.LocalObjectReference{{
+							Kind: "ConfigMap",
+							Name: gatewayapi_v1.ObjectName(configMapCert1.Name),
+						}},
+						Hostname: "example.com",
+					},
+				},
+			},
+		},
+		wantBackendTLSPolicyConditions: []*status.BackendTLSPolicyStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "projectcontour", Name: "basic"},
+			PolicyAncestorStatuses: []*gatewayapi_v1alpha2.PolicyAncestorStatus{
+				{
+					AncestorRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []meta_v1.Condition{
+						{
+							Type:    string(gatewayapi_v1alpha2.PolicyConditionAccepted),
+							Status:  meta_v1.ConditionTrue,
+							Reason:  string(gatewayapi_v1alpha2.PolicyReasonAccepted),
+							Message: "Accepted BackendTLSPolicy",
+						},
+					},
+				},
+			},
+		}},
+	})
+
+	run(t, "tcproute with backendtlspolicy", testcase{
+		gateway: &gatewayapi_v1.Gateway{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "contour",
+				Namespace: "projectcontour",
+			},
+			Spec: gatewayapi_v1.GatewaySpec{
+				Listeners: []gatewayapi_v1.Listener{{
+					Name:     "tcp",
+					Port:     10000,
+					Protocol: gatewayapi_v1.TCPProtocolType,
+					AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1.RouteNamespaces{
+							From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+						},
+					},
+				}},
+			},
+		},
+		objs: []any{
+			tlsService,
+			configMapCert1,
+			&gatewayapi_v1alpha2.TCPRoute{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "projectcontour",
+				},
+				Spec: gatewayapi_v1alpha2.TCPRouteSpec{
+					CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1.ParentReference{gatewayapi.GatewayParentRef("projectcontour", "contour")},
+					},
+					Rules: []gatewayapi_v1alpha2.TCPRouteRule{{
+						BackendRefs: gatewayapi.TLSRouteBackendRef("tlssvc", 443, nil),
+					}},
+				},
+			},
+			&gatewayapi_v1alpha3.BackendTLSPolicy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "projectcontour",
+				},
+				Spec: gatewayapi_v1alpha3.BackendTLSPolicySpec{
+					TargetRefs: []gatewayapi_v1alpha2.LocalPolicyTargetReferenceWithSectionName{
+						{
+							LocalPolicyTargetReference: gatewayapi_v1alpha2.LocalPolicyTargetReference{
+								Kind: "Service",
+								Name: "tlssvc",
+							},
+						},
+					},
+					Validation: gatewayapi_v1alpha3.BackendTLSPolicyValidation{
+						CACertificateRefs: []gatewayapi_v1.LocalObjectReference{{
+							Kind: "ConfigMap",
+							Name: gatewayapi_v1.ObjectName(configMapCert1.Name),
+						}},
+						Hostname: "example.com",
+					},
+				},
+			},
+		},
+		wantBackendTLSPolicyConditions: []*status.BackendTLSPolicyStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "projectcontour", Name: "basic"},
+			PolicyAncestorStatuses: []*gatewayapi_v1alpha2.PolicyAncestorStatus{
+				{
+					AncestorRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []meta_v1.Condition{
+						{
+							Type:    string(gatewayapi_v1alpha2.PolicyConditionAccepted),
+							Status:  meta_v1.ConditionTrue,
+							Reason:  string(gatewayapi_v1alpha2.PolicyReasonAccepted),
+							Message: "Accepted BackendTLSPolicy",
+						},
+					},
+				},
+			},
+		}},
+	})
+}
+
+func TestGatewayAPIXBackendTrafficPolicyDAGStatus(t *testing.T) {
+	type testcase struct {
+		objs                                []any
+		wantXBackendTrafficPolicyConditions []*status.BackendTLSPolicyStatusUpdate
+	}
+
+	run := func(t *testing.T, desc string, tc testcase) {
+		t.Helper()
+		t.Run(desc, func(t *testing.T) {
+			t.Helper()
+			builder := Builder{
+				Source: KubernetesCache{
+					FieldLogger: fixture.NewTestLogger(t),
+					gatewayclass: &gatewayapi_v1.GatewayClass{
+						ObjectMeta: meta_v1.ObjectMeta{
+							Name: "test-gc",
+						},
+						Spec: gatewayapi_v1.GatewayClassSpec{
+							ControllerName: "projectcontour.io/contour",
+						},
+						Status: gatewayapi_v1.GatewayClassStatus{
+							Conditions: []meta_v1.Condition{
+								{
+									Type:   string(gatewayapi_v1.GatewayClassConditionStatusAccepted),
+									Status: meta_v1.ConditionTrue,
+								},
+							},
+						},
+					},
+					gateway: &gatewayapi_v1.Gateway{
+						ObjectMeta: meta_v1.ObjectMeta{
+							Name:      "contour",
+							Namespace: "projectcontour",
+						},
+						Spec: gatewayapi_v1.GatewaySpec{
+							Listeners: []gatewayapi_v1.Listener{{
+								Name:     "http",
+								Port:     80,
+								Protocol: gatewayapi_v1.HTTPProtocolType,
+								AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+									Namespaces: &gatewayapi_v1.RouteNamespaces{
+										From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+									},
+								},
+							}},
+						},
+					},
+				},
+				Processors: []Processor{
+					&ListenerProcessor{},
+					&GatewayAPIProcessor{
+						FieldLogger: fixture.NewTestLogger(t),
+					},
+				},
+			}
+
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+
+			dag := builder.Build()
+			gotXBackendTrafficPolicyUpdates := dag.StatusCache.GetBackendTrafficPolicyUpdates()
+
+			ops := []cmp.Option{
+				cmpopts.IgnoreFields(meta_v1.Condition{}, "LastTransitionTime"),
+				cmpopts.IgnoreFields(status.BackendTLSPolicyStatusUpdate{}, "GatewayRef"),
+				cmpopts.IgnoreFields(status.BackendTLSPolicyStatusUpdate{}, "Generation"),
+				cmpopts.IgnoreFields(status.BackendTLSPolicyStatusUpdate{}, "TransitionTime"),
+				cmpopts.SortSlices(func(i, j *status.BackendTLSPolicyStatusUpdate) bool {
+					return i.FullName.String() < j.FullName.String()
+				}),
+			}
+
+			for _, u := range tc.wantXBackendTrafficPolicyConditions {
+				u.GatewayController = builder.Source.gatewayclass.Spec.ControllerName
+
+				for _, pas := range u.PolicyAncestorStatuses {
+					pas.ControllerName = builder.Source.gatewayclass.Spec.ControllerName
+				}
+			}
+
+			if diff := cmp.Diff(tc.wantXBackendTrafficPolicyConditions, gotXBackendTrafficPolicyUpdates, ops...); diff != "" {
+				t.Fatalf("expected backend lb policy status: %v, got %v", tc.wantXBackendTrafficPolicyConditions, diff)
+			}
+		})
+	}
+
+	kuard := &core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "projectcontour",
+		},
+		Spec: core_v1.ServiceSpec{
+			Ports: []core_v1.ServicePort{makeServicePort("http", "TCP", 8080, 8080)},
+		},
+	}
+
+	backendTrafficPolicy := func(targetName string, sessionPersistence *gatewayapi_v1.SessionPersistence) *gatewayapi_x_v1alpha1.XBackendTrafficPolicy {
+		return &gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "basic",
+				Namespace: "projectcontour",
+			},
+			Spec: gatewayapi_x_v1alpha1.BackendTrafficPolicySpec{
+				TargetRefs: []gatewayapi_v1alpha2.LocalPolicyTargetReference{{
+					Kind: "Service",
+					Name: gatewayapi_v1.ObjectName(targetName),
+				}},
+				SessionPersistence: sessionPersistence,
+			},
+		}
+	}
+
+	policyCondition := func(accepted bool, message string) []*status.BackendTLSPolicyStatusUpdate {
+		cond := meta_v1.Condition{
+			Type:    string(gatewayapi_v1alpha2.PolicyConditionAccepted),
+			Status:  meta_v1.ConditionTrue,
+			Reason:  string(gatewayapi_v1alpha2.PolicyReasonAccepted),
+			Message: message,
+		}
+		if !accepted {
+			cond.Status = meta_v1.ConditionFalse
+			cond.Reason = string(gatewayapi_v1alpha2.PolicyReasonInvalid)
+		}
+
+		return []*status.BackendTLSPolicyStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "projectcontour", Name: "basic"},
+			PolicyAncestorStatuses: []*gatewayapi_v1alpha2.PolicyAncestorStatus{{
+				AncestorRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				Conditions:  []meta_v1.Condition{cond},
+			}},
+		}}
+	}
+
+	httpRoute := makeHTTPRoute("basic", "projectcontour", "", makeHTTPRouteRule(gatewayapi_v1.PathMatchPathPrefix, "/", "kuard", 8080, 1))
+
+	run(t, "cookie session persistence", testcase{
+		objs: []any{
+			kuard,
+			httpRoute,
+			backendTrafficPolicy("kuard", &gatewayapi_v1.SessionPersistence{
+				SessionName: ptr.To("session"),
+			}),
+		},
+		wantXBackendTrafficPolicyConditions: policyCondition(true, "Accepted XBackendTrafficPolicy"),
+	})
+
+	run(t, "permanent cookie session persistence", testcase{
+		objs: []any{
+			kuard,
+			httpRoute,
+			backendTrafficPolicy("kuard", &gatewayapi_v1.SessionPersistence{
+				AbsoluteTimeout: ptr.To(gatewayapi_v1.Duration("1h")),
+				CookieConfig: &gatewayapi_v1.CookieConfig{
+					LifetimeType: ptr.To(gatewayapi_v1.PermanentCookieLifetimeType),
+				},
+			}),
+		},
+		wantXBackendTrafficPolicyConditions: policyCondition(true, "Accepted XBackendTrafficPolicy"),
+	})
+
+	run(t, "header session persistence", testcase{
+		objs: []any{
+			kuard,
+			httpRoute,
+			backendTrafficPolicy("kuard", &gatewayapi_v1.SessionPersistence{
+				Type: ptr.To(gatewayapi_v1.HeaderBasedSessionPersistence),
+			}),
+		},
+		wantXBackendTrafficPolicyConditions: policyCondition(true, "Accepted XBackendTrafficPolicy"),
+	})
+
+	run(t, "xbackendtrafficpolicy with a targetref that cannot be found does not set any conditions", testcase{
+		objs: []any{
+			kuard,
+			httpRoute,
+			backendTrafficPolicy("nonexistent", &gatewayapi_v1.SessionPersistence{}),
+		},
+		wantXBackendTrafficPolicyConditions: nil,
+	})
+
+	run(t, "idle timeout is unsupported", testcase{
+		objs: []any{
+			kuard,
+			httpRoute,
+			backendTrafficPolicy("kuard", &gatewayapi_v1.SessionPersistence{
+				IdleTimeout: ptr.To(gatewayapi_v1.Duration("10m")),
+			}),
+		},
+		wantXBackendTrafficPolicyConditions: policyCondition(false, "XBackendTrafficPolicy.Spec.SessionPersistence.IdleTimeout is unsupported."),
+	})
+
+	run(t, "absolute timeout with a session cookie is unsupported", testcase{
+		objs: []any{
+			kuard,
+			httpRoute,
+			backendTrafficPolicy("kuard", &gatewayapi_v1.SessionPersistence{
+				AbsoluteTimeout: ptr.To(gatewayapi_v1.Duration("1h")),
+			}),
+		},
+		wantXBackendTrafficPolicyConditions: policyCondition(false, "XBackendTrafficPolicy.Spec.SessionPersistence.AbsoluteTimeout is only supported for Permanent cookies."),
+	})
+
+	run(t, "absolute timeout with header session persistence is unsupported", testcase{
+		objs: []any{
+			kuard,
+			httpRoute,
+			backendTrafficPolicy("kuard", &gatewayapi_v1.SessionPersistence{
+				Type:            ptr.To(gatewayapi_v1.HeaderBasedSessionPersistence),
+				AbsoluteTimeout: ptr.To(gatewayapi_v1.Duration("1h")),
+			}),
+		},
+		wantXBackendTrafficPolicyConditions: policyCondition(false, "XBackendTrafficPolicy.Spec.SessionPersistence.AbsoluteTimeout is unsupported for Header session persistence."),
+	})
+}
+
+func TestGatewayAPIXListenerSetDAGStatus(t *testing.T) {
+	type testcase struct {
+		objs                        []any
+		allowedListeners            *gatewayapi_v1.AllowedListeners
+		wantRouteConditions         []*status.RouteStatusUpdate
+		wantListenerSetStatusUpdate []*status.ListenerSetStatusUpdate
+	}
+
+	run := func(t *testing.T, desc string, tc testcase) {
+		t.Helper()
+		t.Run(desc, func(t *testing.T) {
+			t.Helper()
+			builder := Builder{
+				Source: KubernetesCache{
+					FieldLogger: fixture.NewTestLogger(t),
+					gatewayclass: &gatewayapi_v1.GatewayClass{
+						ObjectMeta: meta_v1.ObjectMeta{
+							Name: "test-gc",
+						},
+						Spec: gatewayapi_v1.GatewayClassSpec{
+							ControllerName: "projectcontour.io/contour",
+						},
+						Status: gatewayapi_v1.GatewayClassStatus{
+							Conditions: []meta_v1.Condition{
+								{
+									Type:   string(gatewayapi_v1.GatewayClassConditionStatusAccepted),
+									Status: meta_v1.ConditionTrue,
+								},
+							},
+						},
+					},
+					gateway: &gatewayapi_v1.Gateway{
+						ObjectMeta: meta_v1.ObjectMeta{
+							Name:      "contour",
+							Namespace: "projectcontour",
+						},
+						Spec: gatewayapi_v1.GatewaySpec{
+							Listeners: []gatewayapi_v1.Listener{{
+								Name:     "http",
+								Port:     80,
+								Protocol: gatewayapi_v1.HTTPProtocolType,
+								Hostname: ptr.To(gatewayapi_v1.Hostname("gateway.projectcontour.io")),
+								AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+									Namespaces: &gatewayapi_v1.RouteNamespaces{
+										From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+									},
+								},
+							}},
+							AllowedListeners: tc.allowedListeners,
+						},
+					},
+				},
+				Processors: []Processor{
+					&ListenerProcessor{},
+					&GatewayAPIProcessor{
+						FieldLogger: fixture.NewTestLogger(t),
+					},
+				},
+			}
+
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+
+			dag := builder.Build()
+			gotRouteUpdates := dag.StatusCache.GetRouteUpdates()
+			gotListenerSetUpdates := dag.StatusCache.GetListenerSetUpdates()
+
+			ops := []cmp.Option{
+				cmpopts.IgnoreFields(meta_v1.Condition{}, "LastTransitionTime"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "GatewayRef"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "Generation"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "TransitionTime"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "Resource"),
+				cmpopts.IgnoreFields(status.RouteStatusUpdate{}, "GatewayController"),
+				cmpopts.IgnoreFields(gatewayapi_v1.RouteParentStatus{}, "ControllerName"),
+				cmpopts.IgnoreFields(status.ListenerSetStatusUpdate{}, "ExistingConditions"),
+				cmpopts.IgnoreFields(status.ListenerSetStatusUpdate{}, "Generation"),
+				cmpopts.IgnoreFields(status.ListenerSetStatusUpdate{}, "TransitionTime"),
+				cmpopts.SortSlices(func(i, j meta_v1.Condition) bool {
+					return i.Message < j.Message
+				}),
+				cmpopts.SortSlices(func(i, j *status.RouteStatusUpdate) bool {
+					return i.FullName.String() < j.FullName.String()
+				}),
+				cmpopts.SortSlices(func(i, j *status.ListenerSetStatusUpdate) bool {
+					return i.FullName.String() < j.FullName.String()
+				}),
+			}
+
+			if diff := cmp.Diff(tc.wantRouteConditions, gotRouteUpdates, ops...); diff != "" {
+				t.Fatalf("expected route status: %v, got %v", tc.wantRouteConditions, diff)
+			}
+
+			if diff := cmp.Diff(tc.wantListenerSetStatusUpdate, gotListenerSetUpdates, ops...); diff != "" {
+				t.Fatalf("expected listenerset status: %v, got %v", tc.wantListenerSetStatusUpdate, diff)
+			}
+		})
+	}
+
+	kuardService := &core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: core_v1.ServiceSpec{
+			Ports: []core_v1.ServicePort{makeServicePort("http", "TCP", 8080, 8080)},
+		},
+	}
+
+	sec1 := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "projectcontour",
+		},
+		Type: core_v1.SecretTypeTLS,
+		Data: secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY),
+	}
+
+	allowAllListeners := &gatewayapi_v1.AllowedListeners{
+		Namespaces: &gatewayapi_v1.ListenerNamespaces{
+			From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+		},
+	}
+
+	allowedRoutesSame := &gatewayapi_v1.AllowedRoutes{
+		Namespaces: &gatewayapi_v1.RouteNamespaces{
+			From: ptr.To(gatewayapi_v1.NamespacesFromSame),
+		},
+	}
+
+	makeListenerSet := func(listeners ...gatewayapi_x_v1alpha1.ListenerEntry) *gatewayapi_x_v1alpha1.XListenerSet {
+		return &gatewayapi_x_v1alpha1.XListenerSet{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "listenerset",
+				Namespace: "default",
+			},
+			Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+				ParentRef: gatewayapi_x_v1alpha1.ParentGatewayReference{
+					Name:      "contour",
+					Namespace: ptr.To(gatewayapi_v1.Namespace("projectcontour")),
+				},
+				Listeners: listeners,
+			},
+		}
+	}
+
+	httpListenerEntry := gatewayapi_x_v1alpha1.ListenerEntry{
+		Name:          "http",
+		Port:          80,
+		Protocol:      gatewayapi_v1.HTTPProtocolType,
+		Hostname:      ptr.To(gatewayapi_v1.Hostname("listenerset.projectcontour.io")),
+		AllowedRoutes: allowedRoutesSame,
+	}
+
+	httpListenerSupportedKinds := []gatewayapi_v1.RouteGroupKind{
+		{
+			Group: ptr.To(gatewayapi_v1.Group(gatewayapi_v1.GroupName)),
+			Kind:  "HTTPRoute",
+		},
+		{
+			Group: ptr.To(gatewayapi_v1.Group(gatewayapi_v1.GroupName)),
+			Kind:  "GRPCRoute",
+		},
+	}
+
+	listenerSetRef := gatewayapi.ListenerSetParentRef("", "listenerset")
+
+	listenerSetRoute := &gatewayapi_v1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "basic",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1.ParentReference{listenerSetRef},
+			},
+			Rules: []gatewayapi_v1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("kuard", 8080, 1),
+			}},
+		},
+	}
+
+	run(t, "route attached to a ListenerSet listener", testcase{
+		allowedListeners: allowAllListeners,
+		objs: []any{
+			kuardService,
+			makeListenerSet(httpListenerEntry),
+			listenerSetRoute,
+		},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName:        types.NamespacedName{Namespace: "default", Name: "basic"},
+			ListenerSetRefs: []types.NamespacedName{{Namespace: "default", Name: "listenerset"}},
+			RouteParentStatuses: []*gatewayapi_v1.RouteParentStatus{
+				{
+					ParentRef: listenerSetRef,
+					Conditions: []meta_v1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedHTTPRouteCondition(),
+					},
+				},
+			},
+		}},
+		wantListenerSetStatusUpdate: []*status.ListenerSetStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "listenerset"},
+			Conditions: map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition{
+				gatewayapi_x_v1alpha1.ListenerSetConditionAccepted: {
+					Type:    string(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted),
+					Status:  meta_v1.ConditionTrue,
+					Reason:  string(gatewayapi_x_v1alpha1.ListenerSetReasonAccepted),
+					Message: "ListenerSet is accepted",
+				},
+				gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed: {
+					Type:    string(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed),
+					Status:  meta_v1.ConditionTrue,
+					Reason:  string(gatewayapi_x_v1alpha1.ListenerSetReasonProgrammed),
+					Message: status.MessageValidListenerSet,
+				},
+			},
+			ListenerStatus: map[string]*gatewayapi_v1.ListenerStatus{
+				"http": {
+					Name:           "http",
+					AttachedRoutes: 1,
+					SupportedKinds: httpListenerSupportedKinds,
+					Conditions:     listenerValidConditions(),
+				},
+			},
+		}},
+	})
+
+	run(t, "ListenerSet not allowed by the Gateway", testcase{
+		objs: []any{
+			kuardService,
+			makeListenerSet(httpListenerEntry),
+			listenerSetRoute,
+		},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName:        types.NamespacedName{Namespace: "default", Name: "basic"},
+			ListenerSetRefs: []types.NamespacedName{{Namespace: "default", Name: "listenerset"}},
+			RouteParentStatuses: []*gatewayapi_v1.RouteParentStatus{
+				{
+					ParentRef: listenerSetRef,
+					Conditions: []meta_v1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedFalse(gatewayapi_v1.RouteReasonNoMatchingParent, "No listeners match this parent ref"),
+					},
+				},
+			},
+		}},
+		wantListenerSetStatusUpdate: []*status.ListenerSetStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "listenerset"},
+			Conditions: map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition{
+				gatewayapi_x_v1alpha1.ListenerSetConditionAccepted: {
+					Type:    string(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted),
+					Status:  meta_v1.ConditionFalse,
+					Reason:  string(gatewayapi_x_v1alpha1.ListenerSetReasonNotAllowed),
+					Message: "ListenerSet is not allowed by the Gateway's Spec.AllowedListeners",
+				},
+				gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed: {
+					Type:    string(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed),
+					Status:  meta_v1.ConditionFalse,
+					Reason:  string(gatewayapi_x_v1alpha1.ListenerSetReasonNotAllowed),
+					Message: "ListenerSet is not allowed by the Gateway's Spec.AllowedListeners",
+				},
+			},
+		}},
+	})
+
+	run(t, "ListenerSet listener conflicts with the Gateway's listener", testcase{
+		allowedListeners: allowAllListeners,
+		objs: []any{
+			kuardService,
+			makeListenerSet(
+				httpListenerEntry,
+				gatewayapi_x_v1alpha1.ListenerEntry{
+					Name:          "http-conflict",
+					Port:          80,
+					Protocol:      gatewayapi_v1.HTTPProtocolType,
+					Hostname:      ptr.To(gatewayapi_v1.Hostname("gateway.projectcontour.io")),
+					AllowedRoutes: allowedRoutesSame,
+				},
+			),
+		},
+		wantListenerSetStatusUpdate: []*status.ListenerSetStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "listenerset"},
+			Conditions: map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition{
+				gatewayapi_x_v1alpha1.ListenerSetConditionAccepted: {
+					Type:    string(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted),
+					Status:  meta_v1.ConditionTrue,
+					Reason:  string(gatewayapi_x_v1alpha1.ListenerSetReasonAccepted),
+					Message: "ListenerSet is accepted",
+				},
+				gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed: {
+					Type:    string(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed),
+					Status:  meta_v1.ConditionFalse,
+					Reason:  string(gatewayapi_x_v1alpha1.ListenerSetReasonListenersNotValid),
+					Message: "Listeners are not valid",
+				},
+			},
+			ListenerStatus: map[string]*gatewayapi_v1.ListenerStatus{
+				"http": {
+					Name:           "http",
+					SupportedKinds: httpListenerSupportedKinds,
+					Conditions:     listenerValidConditions(),
+				},
+				"http-conflict": {
+					Name:           "http-conflict",
+					SupportedKinds: httpListenerSupportedKinds,
+					Conditions: []meta_v1.Condition{
+						{
+							Type:    string(gatewayapi_v1.ListenerConditionConflicted),
+							Status:  meta_v1.ConditionTrue,
+							Reason:  string(gatewayapi_v1.ListenerReasonHostnameConflict),
+							Message: "All Listener hostnames for a given port must be unique",
+						},
+						{
+							Type:    string(gatewayapi_v1.ListenerConditionProgrammed),
+							Status:  meta_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1.ListenerReasonInvalid),
+							Message: "Invalid listener, see other listener conditions for details",
+						},
+						listenerAcceptedCondition(),
+						listenerResolvedRefsCondition(),
+					},
+				},
+			},
+		}},
+	})
+
+	run(t, "ListenerSet listener with a certificate in another namespace, no ReferenceGrant", testcase{
+		allowedListeners: allowAllListeners,
+		objs: []any{
+			sec1,
+			makeListenerSet(gatewayapi_x_v1alpha1.ListenerEntry{
+				Name:     "https",
+				Port:     443,
+				Protocol: gatewayapi_v1.HTTPSProtocolType,
+				TLS: &gatewayapi_v1.GatewayTLSConfig{
+					CertificateRefs: []gatewayapi_v1.SecretObjectReference{
+						gatewayapi.CertificateRef(sec1.Name, sec1.Namespace),
+					},
+				},
+				AllowedRoutes: allowedRoutesSame,
+			}),
+		},
+		wantListenerSetStatusUpdate: []*status.ListenerSetStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "listenerset"},
+			Conditions: map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition{
+				gatewayapi_x_v1alpha1.ListenerSetConditionAccepted: {
+					Type:    string(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted),
+					Status:  meta_v1.ConditionFalse,
+					Reason:  string(gatewayapi_x_v1alpha1.ListenerSetReasonListenersNotValid),
+					Message: "None of the listeners are valid",
+				},
+				gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed: {
+					Type:    string(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed),
+					Status:  meta_v1.ConditionFalse,
+					Reason:  string(gatewayapi_x_v1alpha1.ListenerSetReasonListenersNotValid),
+					Message: "Listeners are not valid",
+				},
+			},
+			ListenerStatus: map[string]*gatewayapi_v1.ListenerStatus{
+				"https": {
+					Name:           "https",
+					SupportedKinds: httpListenerSupportedKinds,
+					Conditions: []meta_v1.Condition{
+						{
+							Type:    string(gatewayapi_v1.ListenerConditionProgrammed),
+							Status:  meta_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1.ListenerReasonInvalid),
+							Message: "Invalid listener, see other listener conditions for details",
+						},
+						listenerAcceptedCondition(),
+						{
+							Type:    string(gatewayapi_v1.ListenerConditionResolvedRefs),
+							Status:  meta_v1.ConditionFalse,
+							Reason:  string(gatewayapi_v1.ListenerReasonRefNotPermitted),
+							Message: "Spec.VirtualHost.TLS.CertificateRefs \"secret\" namespace must match the XListenerSet's namespace or be covered by a ReferenceGrant",
+						},
+					},
+				},
+			},
+		}},
+	})
 }
 
 func gatewayAcceptedCondition() meta_v1.Condition {
@@ -128,9 +128,9 @@ func collectDag(b DagBuilder) (nodeCollection, edgeCollection) {
 				}
 			}
 
-			if vhost.Secret != nil {
-				edges[pair{vhost, vhost.Secret}] = true
-				nodes[vhost.Secret] = true
+			for _, secret := range vhost.Secrets() {
+				edges[pair{vhost, secret}] = true
+				nodes[secret] = true
 			}
 		}
 
@@ -54,13 +54,21 @@ func Clustername(cluster *dag.Cluster) string {
 			buf += uv.SubjectNames[0]
 		}
 	}
-	buf += cluster.Protocol + cluster.SNI
+	buf += cluster.Protocol + cluster.SNI + cluster.UpstreamProxyProtocol
+	if service.Weighted.Imported {
+		buf += "ServiceImport"
+	}
 	if !cluster.TimeoutPolicy.IdleConnectionTimeout.UseDefault() {
 		buf += cluster.TimeoutPolicy.IdleConnectionTimeout.Duration().String()
 	}
 	if cluster.SlowStartConfig != nil {
 		buf += cluster.SlowStartConfig.String()
 	}
+	if ts := cluster.TrafficShift; ts != nil {
+		// The weight of the shift is set by EDS, so that
+		// each step of the shift keeps the same cluster.
+		buf += ts.ClusterLoadAssignmentName
+	}
 
 	// This isn't a crypto hash, we just want a unique name.
 	hash := sha1.Sum([]byte(buf)) // nolint:gosec
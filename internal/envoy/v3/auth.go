@@ -114,8 +114,10 @@ func validationContext(ca []byte, subjectNames []string, skipVerifyPeerCert bool
 	return vc
 }
 
-// DownstreamTLSContext creates a new DownstreamTlsContext.
-func (e *EnvoyGen) DownstreamTLSContext(serverSecret *dag.Secret, tlsMinProtoVersion, tlsMaxProtoVersion envoy_transport_socket_tls_v3.TlsParameters_TlsProtocol, cipherSuites []string, peerValidationContext *dag.PeerValidationContext, alpnProtos ...string) *envoy_transport_socket_tls_v3.DownstreamTlsContext {
+// DownstreamTLSContext creates a new DownstreamTlsContext serving
+// the given certificates. Envoy presents the first certificate
+// that the client supports.
+func (e *EnvoyGen) DownstreamTLSContext(serverSecrets []*dag.Secret, tlsMinProtoVersion, tlsMaxProtoVersion envoy_transport_socket_tls_v3.TlsParameters_TlsProtocol, cipherSuites []string, peerValidationContext *dag.PeerValidationContext, alpnProtos ...string) *envoy_transport_socket_tls_v3.DownstreamTlsContext {
 	context := &envoy_transport_socket_tls_v3.DownstreamTlsContext{
 		CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 			TlsParams: &envoy_transport_socket_tls_v3.TlsParameters{
@@ -123,13 +125,15 @@ func (e *EnvoyGen) DownstreamTLSContext(serverSecret *dag.Secret, tlsMinProtoVer
 				TlsMaximumProtocolVersion: tlsMaxProtoVersion,
 				CipherSuites:              cipherSuites,
 			},
-			TlsCertificateSdsSecretConfigs: []*envoy_transport_socket_tls_v3.SdsSecretConfig{{
-				Name:      envoy.Secretname(serverSecret),
-				SdsConfig: e.GetConfigSource(),
-			}},
 			AlpnProtocols: alpnProtos,
 		},
 	}
+	for _, secret := range serverSecrets {
+		context.CommonTlsContext.TlsCertificateSdsSecretConfigs = append(context.CommonTlsContext.TlsCertificateSdsSecretConfigs, &envoy_transport_socket_tls_v3.SdsSecretConfig{
+			Name:      envoy.Secretname(secret),
+			SdsConfig: e.GetConfigSource(),
+		})
+	}
 	if peerValidationContext != nil {
 		vc := validationContext(peerValidationContext.GetCACertificate(), []string{}, peerValidationContext.SkipClientCertValidation,
 			peerValidationContext.GetCRL(), peerValidationContext.OnlyVerifyLeafCertCrl)
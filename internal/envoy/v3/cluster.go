@@ -25,13 +25,11 @@ import (
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
-	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
 	"github.com/projectcontour/contour/internal/timeout"
-	"github.com/projectcontour/contour/internal/xds"
 )
 
 func clusterDefaults() *envoy_config_cluster_v3.Cluster {
@@ -49,6 +47,9 @@ func (e *EnvoyGen) Cluster(c *dag.Cluster) *envoy_config_cluster_v3.Cluster {
 
 	cluster.Name = envoy.Clustername(c)
 	cluster.AltStatName = envoy.AltStatName(service)
+	if c.StatPrefix != "" {
+		cluster.AltStatName = c.StatPrefix + "_" + cluster.AltStatName
+	}
 	cluster.LbPolicy = lbPolicy(c.LoadBalancerPolicy)
 	cluster.HealthChecks = edshealthcheck(c)
 	cluster.DnsLookupFamily = parseDNSLookupFamily(c.DNSLookupFamily)
@@ -62,6 +63,15 @@ func (e *EnvoyGen) Cluster(c *dag.Cluster) *envoy_config_cluster_v3.Cluster {
 		// external name not set, cluster will be discovered via EDS
 		cluster.ClusterDiscoveryType = ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS)
 		cluster.EdsClusterConfig = e.edsconfig(service)
+		if ts := c.TrafficShift; ts != nil {
+			// The stable and canary services are localities
+			// of the ClusterLoadAssignment of the shift,
+			// weighted by its current step.
+			cluster.EdsClusterConfig.ServiceName = ts.ClusterLoadAssignmentName
+			cluster.CommonLbConfig.LocalityConfigSpecifier = &envoy_config_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+				LocalityWeightedLbConfig: &envoy_config_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig{},
+			}
+		}
 	default:
 		// external name set, use hard coded DNS name
 		// external name set to LOGICAL_DNS when user selects the ALL loookup family
@@ -107,6 +117,10 @@ func (e *EnvoyGen) Cluster(c *dag.Cluster) *envoy_config_cluster_v3.Cluster {
 		httpVersion = HTTPVersion2
 	}
 
+	if c.UpstreamProxyProtocol != "" {
+		cluster.TransportSocket = UpstreamProxyProtocolTransportSocket(c.UpstreamProxyProtocol, cluster.TransportSocket)
+	}
+
 	if c.TimeoutPolicy.ConnectTimeout > time.Duration(0) {
 		cluster.ConnectTimeout = durationpb.New(c.TimeoutPolicy.ConnectTimeout)
 	}
@@ -177,6 +191,15 @@ func (e *EnvoyGen) ExtensionCluster(ext *dag.ExtensionCluster) *envoy_config_clu
 		)
 	case "h2c":
 		http2Version = HTTPVersion2
+	case "tls":
+		cluster.TransportSocket = UpstreamTLSTransportSocket(
+			e.UpstreamTLSContext(
+				ext.UpstreamValidation,
+				ext.SNI,
+				ext.ClientCertificate,
+				ext.UpstreamTLS,
+			),
+		)
 	}
 
 	if ext.ClusterTimeoutPolicy.ConnectTimeout > time.Duration(0) {
@@ -233,11 +256,8 @@ func (e *EnvoyGen) DNSNameCluster(c *dag.DNSNameCluster) *envoy_config_cluster_v
 
 func (e *EnvoyGen) edsconfig(service *dag.Service) *envoy_config_cluster_v3.Cluster_EdsClusterConfig {
 	return &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
-		EdsConfig: e.GetConfigSource(),
-		ServiceName: xds.ClusterLoadAssignmentName(
-			types.NamespacedName{Name: service.Weighted.ServiceName, Namespace: service.Weighted.ServiceNamespace},
-			service.Weighted.ServicePort.Name,
-		),
+		EdsConfig:   e.GetConfigSource(),
+		ServiceName: service.Weighted.ClusterLoadAssignmentName(),
 	}
 }
 
@@ -20,6 +20,8 @@ import (
 	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_transport_socket_proxy_protocol_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	envoy_transport_socket_raw_buffer_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/raw_buffer/v3"
 	envoy_upstream_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/stretchr/testify/assert"
@@ -159,6 +161,68 @@ func TestCluster(t *testing.T) {
 				},
 			},
 		},
+		"serviceimport": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Weighted: dag.WeightedService{
+						Weight:           1,
+						ServiceName:      s1.Name,
+						ServiceNamespace: s1.Namespace,
+						ServicePort:      s1.Spec.Ports[0],
+						HealthPort:       s1.Spec.Ports[0],
+						Imported:         true,
+					},
+				},
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/fbef96fb48",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   edsConfig,
+					ServiceName: "serviceimport/default/kuard/http",
+				},
+			},
+		},
+		"stat prefix": {
+			cluster: &dag.Cluster{
+				Upstream:   service(s1),
+				StatPrefix: "httpproxy_default_kuard",
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/da39a3ee5e",
+				AltStatName:          "httpproxy_default_kuard_default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   edsConfig,
+					ServiceName: "default/kuard/http",
+				},
+			},
+		},
+		"traffic shift": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				TrafficShift: &dag.TrafficShift{
+					ClusterLoadAssignmentName: "trafficshift/default/proxy/kuard/kuard-canary/443/443",
+					Canary:                    service(s1),
+					Weight:                    10,
+				},
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/4d0a519ef3",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   edsConfig,
+					ServiceName: "trafficshift/default/proxy/kuard/kuard-canary/443/443",
+				},
+				CommonLbConfig: &envoy_config_cluster_v3.Cluster_CommonLbConfig{
+					LocalityConfigSpecifier: &envoy_config_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+						LocalityWeightedLbConfig: &envoy_config_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig{},
+					},
+				},
+			},
+		},
 		"h2c upstream": {
 			cluster: &dag.Cluster{
 				Upstream: service(s1, "h2c"),
@@ -212,6 +276,66 @@ func TestCluster(t *testing.T) {
 				},
 			},
 		},
+		"upstream proxy protocol": {
+			cluster: &dag.Cluster{
+				Upstream:              service(s1),
+				UpstreamProxyProtocol: "v2",
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/a1047eab10",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   edsConfig,
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: &envoy_config_core_v3.TransportSocket{
+					Name: "envoy.transport_sockets.upstream_proxy_protocol",
+					ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+						TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_proxy_protocol_v3.ProxyProtocolUpstreamTransport{
+							Config: &envoy_config_core_v3.ProxyProtocolConfig{
+								Version: envoy_config_core_v3.ProxyProtocolConfig_V2,
+							},
+							TransportSocket: &envoy_config_core_v3.TransportSocket{
+								Name: "envoy.transport_sockets.raw_buffer",
+								ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+									TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_raw_buffer_v3.RawBuffer{}),
+								},
+							},
+						}),
+					},
+				},
+			},
+		},
+		"tls upstream with proxy protocol": {
+			cluster: &dag.Cluster{
+				Upstream:              service(s1, "tls"),
+				Protocol:              "tls",
+				UpstreamProxyProtocol: "v1",
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/d7cdcda189",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   edsConfig,
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: &envoy_config_core_v3.TransportSocket{
+					Name: "envoy.transport_sockets.upstream_proxy_protocol",
+					ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+						TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_proxy_protocol_v3.ProxyProtocolUpstreamTransport{
+							Config: &envoy_config_core_v3.ProxyProtocolConfig{
+								Version: envoy_config_core_v3.ProxyProtocolConfig_V1,
+							},
+							TransportSocket: UpstreamTLSTransportSocket(
+								envoyGen.UpstreamTLSContext(nil, "", nil, nil),
+							),
+						}),
+					},
+				},
+			},
+		},
 		"externalName service": {
 			cluster: &dag.Cluster{
 				Upstream: service(s2),
@@ -1156,6 +1280,33 @@ func TestClustername(t *testing.T) {
 		want: "default/backend/80/5c26077e1d",
 	})
 
+	shift := func(weight uint32) *dag.Cluster {
+		return &dag.Cluster{
+			Upstream: &dag.Service{
+				Weighted: dag.WeightedService{
+					ServiceName:      "backend",
+					ServiceNamespace: "default",
+					ServicePort:      core_v1.ServicePort{Port: 80},
+				},
+			},
+			TrafficShift: &dag.TrafficShift{
+				ClusterLoadAssignmentName: "trafficshift/default/proxy/backend/canary/80/80",
+				Weight:                    weight,
+			},
+		}
+	}
+
+	// Each step of a traffic shift only reweights the endpoints
+	// of the cluster, which keeps its name.
+	run(t, "traffic shift", testcase{
+		cluster: shift(10),
+		want:    "default/backend/80/082eb8894b",
+	})
+	run(t, "traffic shift step", testcase{
+		cluster: shift(50),
+		want:    "default/backend/80/082eb8894b",
+	})
+
 	cluster1 := &dag.Cluster{
 		Upstream: &dag.Service{
 			Weighted: dag.WeightedService{
@@ -16,11 +16,13 @@ package v3
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	envoy_config_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	envoy_config_common_mutation_rules_v3 "github.com/envoyproxy/go-control-plane/envoy/config/common/mutation_rules/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_compression_brotli_compressor_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/brotli/compressor/v3"
@@ -41,6 +43,7 @@ import (
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_filter_network_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"google.golang.org/protobuf/proto"
@@ -158,16 +161,18 @@ func Listener(name, address string, port int, perConnectionBufferLimitBytes *uin
 }
 
 const (
-	CORSFilterName            string = "envoy.filters.http.cors"
-	LocalRateLimitFilterName  string = "envoy.filters.http.local_ratelimit"
-	GlobalRateLimitFilterName string = "envoy.filters.http.ratelimit"
-	RBACFilterName            string = "envoy.filters.http.rbac"
-	ExtAuthzFilterName        string = "envoy.filters.http.ext_authz"
-	JWTAuthnFilterName        string = "envoy.filters.http.jwt_authn"
-	LuaFilterName             string = "envoy.filters.http.lua"
-	CompressorFilterName      string = "envoy.filters.http.compressor"
-	GRPCWebFilterName         string = "envoy.filters.http.grpc_web"
-	GRPCStatsFilterName       string = "envoy.filters.http.grpc_stats"
+	CORSFilterName                string = "envoy.filters.http.cors"
+	LocalRateLimitFilterName      string = "envoy.filters.http.local_ratelimit"
+	GlobalRateLimitFilterName     string = "envoy.filters.http.ratelimit"
+	RBACFilterName                string = "envoy.filters.http.rbac"
+	ExtAuthzFilterName            string = "envoy.filters.http.ext_authz"
+	JWTAuthnFilterName            string = "envoy.filters.http.jwt_authn"
+	JWTClaimsFilterName           string = "jwt_claims"
+	AuthorizationPolicyFilterName string = "authorization_policy"
+	LuaFilterName                 string = "envoy.filters.http.lua"
+	CompressorFilterName          string = "envoy.filters.http.compressor"
+	GRPCWebFilterName             string = "envoy.filters.http.grpc_web"
+	GRPCStatsFilterName           string = "envoy.filters.http.grpc_stats"
 )
 
 type httpConnectionManagerBuilder struct {
@@ -644,8 +649,13 @@ func (e *EnvoyGen) HTTPConnectionManagerBuilder() *httpConnectionManagerBuilder
 	}
 }
 
-// TCPProxy creates a new TCPProxy filter.
+// TCPProxy creates a new TCPProxy filter. Its statistics are named
+// after the proxy's StatPrefix, if set, or statPrefix otherwise.
 func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*envoy_config_accesslog_v3.AccessLog) *envoy_config_listener_v3.Filter {
+	if proxy.StatPrefix != "" {
+		statPrefix = proxy.StatPrefix
+	}
+
 	// Set the idle timeout in seconds for connections through a TCP Proxy type filter.
 	// The value of two and a half hours for reasons documented at
 	// https://github.com/projectcontour/contour/issues/1074
@@ -655,6 +665,12 @@ func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*envoy_conf
 		AccessLog:   accesslogger,
 		IdleTimeout: durationpb.New(9001 * time.Second),
 	}
+	if !proxy.IdleTimeout.UseDefault() {
+		tcpProxy.IdleTimeout = envoy.Timeout(proxy.IdleTimeout)
+	}
+	if proxy.MaxConnectAttempts > 0 {
+		tcpProxy.MaxConnectAttempts = wrapperspb.UInt32(proxy.MaxConnectAttempts)
+	}
 
 	var totalWeight uint32
 	var keepClusters []*dag.Cluster
@@ -841,9 +857,6 @@ end
 // requested parameters.
 func FilterExternalAuthz(externalAuthorization *dag.ExternalAuthorization) *envoy_filter_network_http_connection_manager_v3.HttpFilter {
 	authConfig := envoy_filter_http_ext_authz_v3.ExtAuthz{
-		Services: &envoy_filter_http_ext_authz_v3.ExtAuthz_GrpcService{
-			GrpcService: grpcService(externalAuthorization.AuthorizationService.Name, externalAuthorization.AuthorizationService.SNI, externalAuthorization.AuthorizationResponseTimeout),
-		},
 		// Pretty sure we always want this. Why have an
 		// external auth service if it is not going to affect
 		// routing decisions?
@@ -859,6 +872,28 @@ func FilterExternalAuthz(externalAuthorization *dag.ExternalAuthorization) *envo
 		TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
 	}
 
+	forwarding := externalAuthorization.AuthorizationForwarding
+	if forwarding == nil {
+		forwarding = &dag.AuthorizationForwarding{}
+	}
+
+	if httpService := externalAuthorization.AuthorizationHTTPService; httpService != nil {
+		authConfig.Services = &envoy_filter_http_ext_authz_v3.ExtAuthz_HttpService{
+			HttpService: httpAuthzService(externalAuthorization.AuthorizationService.Name, externalAuthorization.AuthorizationService.SNI, externalAuthorization.AuthorizationResponseTimeout, httpService, forwarding),
+		}
+	} else {
+		authConfig.Services = &envoy_filter_http_ext_authz_v3.ExtAuthz_GrpcService{
+			GrpcService: grpcService(externalAuthorization.AuthorizationService.Name, externalAuthorization.AuthorizationService.SNI, externalAuthorization.AuthorizationResponseTimeout),
+		}
+		authConfig.DecoderHeaderMutationRules = headerMutationRules(forwarding.AllowedUpstreamHeaders)
+	}
+
+	authConfig.AllowedHeaders = headerMatchers(forwarding.AllowedRequestHeaders)
+	if len(forwarding.MetadataNamespaces) > 0 {
+		authConfig.MetadataContextNamespaces = forwarding.MetadataNamespaces
+	}
+	authConfig.RouteMetadataContextNamespaces = forwarding.RouteMetadataNamespaces
+
 	if externalAuthorization.AuthorizationServerWithRequestBody != nil {
 		authConfig.WithRequestBody = &envoy_filter_http_ext_authz_v3.BufferSettings{
 			MaxRequestBytes:     externalAuthorization.AuthorizationServerWithRequestBody.MaxRequestBytes,
@@ -888,16 +923,19 @@ func FilterJWTAuthN(jwtProviders []dag.JWTProvider) *envoy_filter_network_http_c
 	}
 
 	for _, provider := range jwtProviders {
-		provider := provider
-		var cacheDuration *durationpb.Duration
-		if provider.RemoteJWKS.CacheDuration != nil {
-			cacheDuration = durationpb.New(*provider.RemoteJWKS.CacheDuration)
-		}
-
-		jwtConfig.Providers[provider.Name] = &envoy_filter_http_jwt_authn_v3.JwtProvider{
+		jwtProvider := &envoy_filter_http_jwt_authn_v3.JwtProvider{
 			Issuer:    provider.Issuer,
 			Audiences: provider.Audiences,
-			JwksSourceSpecifier: &envoy_filter_http_jwt_authn_v3.JwtProvider_RemoteJwks{
+			Forward:   provider.ForwardJWT,
+		}
+
+		if provider.RemoteJWKS != nil {
+			var cacheDuration *durationpb.Duration
+			if provider.RemoteJWKS.CacheDuration != nil {
+				cacheDuration = durationpb.New(*provider.RemoteJWKS.CacheDuration)
+			}
+
+			jwtProvider.JwksSourceSpecifier = &envoy_filter_http_jwt_authn_v3.JwtProvider_RemoteJwks{
 				RemoteJwks: &envoy_filter_http_jwt_authn_v3.RemoteJwks{
 					HttpUri: &envoy_config_core_v3.HttpUri{
 						Uri: provider.RemoteJWKS.URI,
@@ -908,10 +946,32 @@ func FilterJWTAuthN(jwtProviders []dag.JWTProvider) *envoy_filter_network_http_c
 					},
 					CacheDuration: cacheDuration,
 				},
-			},
-			Forward: provider.ForwardJWT,
+			}
+		} else {
+			jwtProvider.JwksSourceSpecifier = &envoy_filter_http_jwt_authn_v3.JwtProvider_LocalJwks{
+				LocalJwks: &envoy_config_core_v3.DataSource{
+					Specifier: &envoy_config_core_v3.DataSource_InlineString{
+						InlineString: provider.LocalJWKS,
+					},
+				},
+			}
+		}
+
+		for _, claimToHeader := range provider.ClaimToHeaders {
+			jwtProvider.ClaimToHeaders = append(jwtProvider.ClaimToHeaders, &envoy_filter_http_jwt_authn_v3.JwtClaimToHeader{
+				HeaderName: claimToHeader.HeaderName,
+				ClaimName:  claimToHeader.Claim,
+			})
+		}
+
+		// The JWT payload is stored in the request metadata, keyed
+		// by the provider name, so that routes can match its claims.
+		if provider.PayloadInMetadata {
+			jwtProvider.PayloadInMetadata = provider.Name
 		}
 
+		jwtConfig.Providers[provider.Name] = jwtProvider
+
 		// Set up a requirement map so that per-route filter config can refer
 		// to a requirement by name. This is nicer than specifying rules here,
 		// because it likely results in less Envoy config overall (don't have
@@ -933,6 +993,42 @@ func FilterJWTAuthN(jwtProviders []dag.JWTProvider) *envoy_filter_network_http_c
 	}
 }
 
+// FilterJWTClaims returns an RBAC filter that routes use to match the
+// claims of JWTs validated by the `jwt_authn` filter, or nil if no
+// routes match claims.
+func FilterJWTClaims(jwtProviders []dag.JWTProvider) *envoy_filter_network_http_connection_manager_v3.HttpFilter {
+	for _, provider := range jwtProviders {
+		if provider.PayloadInMetadata {
+			return &envoy_filter_network_http_connection_manager_v3.HttpFilter{
+				Name: JWTClaimsFilterName,
+				ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_rbac_v3.RBAC{}),
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
+// FilterAuthorizationPolicy returns an RBAC filter that routes use to
+// apply their authorization policies, or nil if none of the virtual hosts
+// have routes with authorization policies.
+func FilterAuthorizationPolicy(vhosts ...*dag.VirtualHost) *envoy_filter_network_http_connection_manager_v3.HttpFilter {
+	for _, vh := range vhosts {
+		if vh.HasRBACPolicy() {
+			return &envoy_filter_network_http_connection_manager_v3.HttpFilter{
+				Name: AuthorizationPolicyFilterName,
+				ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_rbac_v3.RBAC{}),
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
 // FilterChainTLS returns a TLS enabled envoy_config_listener_v3.FilterChain.
 func FilterChainTLS(domain string, downstream *envoy_transport_socket_tls_v3.DownstreamTlsContext, filters []*envoy_config_listener_v3.Filter) *envoy_config_listener_v3.FilterChain {
 	fc := &envoy_config_listener_v3.FilterChain{
@@ -992,6 +1088,84 @@ func grpcService(clusterName, sni string, timeout timeout.Setting) *envoy_config
 	}
 }
 
+// defaultHTTPAuthzTimeout is the timeout of requests to HTTP authorization
+// services when none is configured, matching the ext_authz gRPC default.
+const defaultHTTPAuthzTimeout = 200 * time.Millisecond
+
+func httpAuthzService(clusterName, sni string, timeout timeout.Setting, httpService *dag.AuthorizationHTTPService, forwarding *dag.AuthorizationForwarding) *envoy_filter_http_ext_authz_v3.HttpService {
+	authority := strings.ReplaceAll(clusterName, "/", ".")
+	if sni != "" {
+		authority = sni
+	}
+
+	// The server URI is required, but only its cluster and
+	// timeout are used to send the authorization request.
+	requestTimeout := envoy.Timeout(timeout)
+	if requestTimeout == nil {
+		requestTimeout = durationpb.New(defaultHTTPAuthzTimeout)
+	}
+
+	service := &envoy_filter_http_ext_authz_v3.HttpService{
+		ServerUri: &envoy_config_core_v3.HttpUri{
+			Uri: "http://" + authority,
+			HttpUpstreamType: &envoy_config_core_v3.HttpUri_Cluster{
+				Cluster: clusterName,
+			},
+			Timeout: requestTimeout,
+		},
+		PathPrefix: httpService.PathPrefix,
+	}
+
+	if len(forwarding.AllowedUpstreamHeaders) > 0 || len(forwarding.AllowedClientHeaders) > 0 {
+		service.AuthorizationResponse = &envoy_filter_http_ext_authz_v3.AuthorizationResponse{
+			AllowedUpstreamHeaders: headerMatchers(forwarding.AllowedUpstreamHeaders),
+			AllowedClientHeaders:   headerMatchers(forwarding.AllowedClientHeaders),
+		}
+	}
+
+	return service
+}
+
+// headerMutationRules returns HeaderMutationRules that only allow
+// the given headers to be set, or nil if there are none.
+func headerMutationRules(headers []string) *envoy_config_common_mutation_rules_v3.HeaderMutationRules {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(headers))
+	for _, header := range headers {
+		names = append(names, regexp.QuoteMeta(strings.ToLower(header)))
+	}
+
+	// DisallowAll would also drop the allowed headers, so disallow
+	// every header by expression instead, which AllowExpression
+	// takes precedence over.
+	return &envoy_config_common_mutation_rules_v3.HeaderMutationRules{
+		AllowExpression:    safeRegexMatch("^(" + strings.Join(names, "|") + ")$"),
+		DisallowExpression: safeRegexMatch(".*"),
+	}
+}
+
+// headerMatchers returns a ListStringMatcher matching
+// the given header names, or nil if there are none.
+func headerMatchers(headers []string) *envoy_matcher_v3.ListStringMatcher {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	matchers := &envoy_matcher_v3.ListStringMatcher{}
+	for _, header := range headers {
+		matchers.Patterns = append(matchers.Patterns, &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
+				Exact: header,
+			},
+			IgnoreCase: true,
+		})
+	}
+	return matchers
+}
+
 // ListenerFilters returns a []*envoy_config_listener_v3.ListenerFilter for the supplied listener filters.
 func ListenerFilters(filters ...*envoy_config_listener_v3.ListenerFilter) []*envoy_config_listener_v3.ListenerFilter {
 	return filters
@@ -262,6 +262,19 @@ func TestDownstreamTLSContext(t *testing.T) {
 		},
 	}
 
+	ecdsaServerSecret := &dag.Secret{
+		Object: &core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tls-cert-ecdsa",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				core_v1.TLSCertKey:       []byte("ecdsa-cert"),
+				core_v1.TLSPrivateKeyKey: []byte("ecdsa-key"),
+			},
+		},
+	}
+
 	cipherSuites := []string{
 		"[ECDHE-ECDSA-AES128-GCM-SHA256|ECDHE-ECDSA-CHACHA20-POLY1305]",
 		"ECDHE-ECDSA-AES256-GCM-SHA384",
@@ -482,7 +495,7 @@ func TestDownstreamTLSContext(t *testing.T) {
 		want *envoy_transport_socket_tls_v3.DownstreamTlsContext
 	}{
 		"TLS context without client authentication": {
-			envoyGen.DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, nil, "h2", "http/1.1"),
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, nil, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
 				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 					TlsParams:                      tlsParams,
@@ -491,8 +504,21 @@ func TestDownstreamTLSContext(t *testing.T) {
 				},
 			},
 		},
+		"TLS context with multiple certificates": {
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret, ecdsaServerSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, nil, "h2", "http/1.1"),
+			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
+				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
+					TlsParams: tlsParams,
+					TlsCertificateSdsSecretConfigs: append(tlsCertificateSdsSecretConfigs, &envoy_transport_socket_tls_v3.SdsSecretConfig{
+						Name:      envoy.Secretname(ecdsaServerSecret),
+						SdsConfig: tlsCertificateSdsSecretConfigs[0].SdsConfig,
+					}),
+					AlpnProtocols: alpnProtocols,
+				},
+			},
+		},
 		"TLS context with client authentication": {
-			envoyGen.DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContext, "h2", "http/1.1"),
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContext, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
 				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 					TlsParams:                      tlsParams,
@@ -504,7 +530,7 @@ func TestDownstreamTLSContext(t *testing.T) {
 			},
 		},
 		"Downstream validation shall not support subjectName validation": {
-			envoyGen.DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextWithSubjectName, "h2", "http/1.1"),
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextWithSubjectName, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
 				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 					TlsParams:                      tlsParams,
@@ -516,7 +542,7 @@ func TestDownstreamTLSContext(t *testing.T) {
 			},
 		},
 		"skip client cert validation": {
-			envoyGen.DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextSkipClientCertValidation, "h2", "http/1.1"),
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextSkipClientCertValidation, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
 				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 					TlsParams:                      tlsParams,
@@ -528,7 +554,7 @@ func TestDownstreamTLSContext(t *testing.T) {
 			},
 		},
 		"skip client cert validation with ca": {
-			envoyGen.DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextSkipClientCertValidationWithCA, "h2", "http/1.1"),
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextSkipClientCertValidationWithCA, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
 				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 					TlsParams:                      tlsParams,
@@ -540,7 +566,7 @@ func TestDownstreamTLSContext(t *testing.T) {
 			},
 		},
 		"optional client cert validation with ca": {
-			envoyGen.DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextOptionalClientCertValidationWithCA, "h2", "http/1.1"),
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextOptionalClientCertValidationWithCA, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
 				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 					TlsParams:                      tlsParams,
@@ -552,7 +578,7 @@ func TestDownstreamTLSContext(t *testing.T) {
 			},
 		},
 		"Downstream validation with CRL check": {
-			envoyGen.DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextWithCRLCheck, "h2", "http/1.1"),
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextWithCRLCheck, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
 				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 					TlsParams:                      tlsParams,
@@ -564,7 +590,7 @@ func TestDownstreamTLSContext(t *testing.T) {
 			},
 		},
 		"Downstream validation with CRL check but only for leaf-certificate": {
-			envoyGen.DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextWithCRLCheckOnlyLeaf, "h2", "http/1.1"),
+			envoyGen.DownstreamTLSContext([]*dag.Secret{serverSecret}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextWithCRLCheckOnlyLeaf, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
 				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
 					TlsParams:                      tlsParams,
@@ -1636,6 +1662,25 @@ func TestTCPProxy(t *testing.T) {
 				},
 			},
 		},
+		"single cluster, stat prefix": {
+			proxy: &dag.TCPProxy{
+				Clusters:   []*dag.Cluster{c1},
+				StatPrefix: "httpproxy_default_example",
+			},
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_tcp_proxy_v3.TcpProxy{
+						StatPrefix: "httpproxy_default_example",
+						ClusterSpecifier: &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
+							Cluster: envoy.Clustername(c1),
+						},
+						AccessLog:   FileAccessLogEnvoy(accessLogPath, "", nil, contour_v1alpha1.LogLevelInfo),
+						IdleTimeout: durationpb.New(9001 * time.Second),
+					}),
+				},
+			},
+		},
 		"three clusters, one has no weight specified": {
 			proxy: &dag.TCPProxy{
 				Clusters: []*dag.Cluster{c1, c2, c3},
@@ -1735,6 +1780,46 @@ func TestTCPProxy(t *testing.T) {
 				},
 			},
 		},
+		"idle timeout and max connect attempts": {
+			proxy: &dag.TCPProxy{
+				Clusters:           []*dag.Cluster{c1},
+				IdleTimeout:        timeout.DurationSetting(10 * time.Minute),
+				MaxConnectAttempts: 3,
+			},
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_tcp_proxy_v3.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
+							Cluster: envoy.Clustername(c1),
+						},
+						AccessLog:          FileAccessLogEnvoy(accessLogPath, "", nil, contour_v1alpha1.LogLevelInfo),
+						IdleTimeout:        durationpb.New(10 * time.Minute),
+						MaxConnectAttempts: wrapperspb.UInt32(3),
+					}),
+				},
+			},
+		},
+		"idle timeout disabled": {
+			proxy: &dag.TCPProxy{
+				Clusters:    []*dag.Cluster{c1},
+				IdleTimeout: timeout.DisabledSetting(),
+			},
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_tcp_proxy_v3.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
+							Cluster: envoy.Clustername(c1),
+						},
+						AccessLog:   FileAccessLogEnvoy(accessLogPath, "", nil, contour_v1alpha1.LogLevelInfo),
+						IdleTimeout: durationpb.New(0),
+					}),
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -17,9 +17,11 @@ import (
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_common_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
 	envoy_filter_http_local_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
 	envoy_filter_http_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_type_metadata_v3 "github.com/envoyproxy/go-control-plane/envoy/type/metadata/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -67,58 +69,185 @@ func localRateLimitConfig(config *dag.LocalRateLimitPolicy, statPrefix string) *
 		c.Status = &envoy_type_v3.HttpStatus{Code: envoy_type_v3.StatusCode(config.ResponseStatusCode)} //nolint:gosec // disable G115
 	}
 
+	// Requests that match a descriptor only consume tokens from the
+	// descriptor's token bucket, so they are limited separately from
+	// the rest of the requests.
+	if len(config.Descriptors) > 0 {
+		c.AlwaysConsumeDefaultTokenBucket = wrapperspb.Bool(false)
+	}
+
+	// Descriptor entries without a value are wildcards, for which Envoy
+	// keeps a token bucket per distinct value in an LRU cache.
+	if config.MaxDynamicDescriptors > 0 {
+		c.MaxDynamicDescriptors = wrapperspb.UInt32(config.MaxDynamicDescriptors)
+	}
+
+	for _, d := range config.Descriptors {
+		descriptor := &envoy_common_ratelimit_v3.LocalRateLimitDescriptor{
+			TokenBucket: &envoy_type_v3.TokenBucket{
+				MaxTokens:     d.MaxTokens,
+				TokensPerFill: wrapperspb.UInt32(d.TokensPerFill),
+				FillInterval:  durationpb.New(d.FillInterval),
+			},
+		}
+
+		var entries []dag.RateLimitDescriptorEntry
+		for _, entry := range d.Entries {
+			key, value := localRateLimitDescriptorEntry(entry)
+			descriptor.Entries = append(descriptor.Entries, &envoy_common_ratelimit_v3.RateLimitDescriptor_Entry{
+				Key:   key,
+				Value: value,
+			})
+			entries = append(entries, entry.RateLimitDescriptorEntry)
+		}
+
+		c.Descriptors = append(c.Descriptors, descriptor)
+		c.RateLimits = append(c.RateLimits, &envoy_config_route_v3.RateLimit{
+			Actions: rateLimitActions(entries),
+		})
+	}
+
 	return protobuf.MustMarshalAny(c)
 }
 
+// localRateLimitDescriptorEntry returns the key and value that the
+// rate limit action for the given entry generates for a matching
+// request.
+func localRateLimitDescriptorEntry(entry dag.LocalRateLimitDescriptorEntry) (string, string) {
+	switch {
+	case entry.GenericKey != nil:
+		if entry.GenericKey.Key == "" {
+			return "generic_key", entry.GenericKey.Value
+		}
+		return entry.GenericKey.Key, entry.GenericKey.Value
+	case entry.HeaderMatch != nil:
+		return entry.HeaderMatch.Key, entry.Value
+	case entry.HeaderValueMatch != nil:
+		return "header_match", entry.HeaderValueMatch.Value
+	case entry.RemoteAddress != nil:
+		return "remote_address", entry.Value
+	case entry.MaskedRemoteAddress != nil:
+		return "masked_cidr", entry.Value
+	case entry.QueryParameter != nil:
+		return entry.QueryParameter.Key, entry.Value
+	case entry.DestinationCluster != nil:
+		return "destination_cluster", entry.Value
+	default:
+		return "", ""
+	}
+}
+
 // GlobalRateLimits converts DAG RateLimitDescriptors to Envoy RateLimits.
 func GlobalRateLimits(descriptors []*dag.RateLimitDescriptor) []*envoy_config_route_v3.RateLimit {
 	var rateLimits []*envoy_config_route_v3.RateLimit
 	for _, descriptor := range descriptors {
-		var rl envoy_config_route_v3.RateLimit
-
-		for _, entry := range descriptor.Entries {
-			switch {
-			case entry.GenericKey != nil:
-				rl.Actions = append(rl.Actions, &envoy_config_route_v3.RateLimit_Action{
-					ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_GenericKey_{
-						GenericKey: &envoy_config_route_v3.RateLimit_Action_GenericKey{
-							DescriptorKey:   entry.GenericKey.Key,
-							DescriptorValue: entry.GenericKey.Value,
-						},
-					},
-				})
-			case entry.HeaderMatch != nil:
-				rl.Actions = append(rl.Actions, &envoy_config_route_v3.RateLimit_Action{
-					ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RequestHeaders_{
-						RequestHeaders: &envoy_config_route_v3.RateLimit_Action_RequestHeaders{
-							HeaderName:    entry.HeaderMatch.HeaderName,
-							DescriptorKey: entry.HeaderMatch.Key,
-						},
+		rateLimits = append(rateLimits, &envoy_config_route_v3.RateLimit{
+			Actions: rateLimitActions(descriptor.Entries),
+		})
+	}
+
+	return rateLimits
+}
+
+// rateLimitActions converts DAG RateLimitDescriptorEntries to the
+// Envoy RateLimit Actions that generate them.
+func rateLimitActions(entries []dag.RateLimitDescriptorEntry) []*envoy_config_route_v3.RateLimit_Action {
+	var actions []*envoy_config_route_v3.RateLimit_Action
+	for _, entry := range entries {
+		switch {
+		case entry.GenericKey != nil:
+			actions = append(actions, &envoy_config_route_v3.RateLimit_Action{
+				ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_GenericKey_{
+					GenericKey: &envoy_config_route_v3.RateLimit_Action_GenericKey{
+						DescriptorKey:   entry.GenericKey.Key,
+						DescriptorValue: entry.GenericKey.Value,
 					},
-				})
-			case entry.HeaderValueMatch != nil:
-				rl.Actions = append(rl.Actions, &envoy_config_route_v3.RateLimit_Action{
-					ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_HeaderValueMatch_{
-						HeaderValueMatch: &envoy_config_route_v3.RateLimit_Action_HeaderValueMatch{
-							DescriptorValue: entry.HeaderValueMatch.Value,
-							ExpectMatch:     wrapperspb.Bool(entry.HeaderValueMatch.ExpectMatch),
-							Headers:         headerMatcher(entry.HeaderValueMatch.Headers),
-						},
+				},
+			})
+		case entry.HeaderMatch != nil:
+			actions = append(actions, &envoy_config_route_v3.RateLimit_Action{
+				ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RequestHeaders_{
+					RequestHeaders: &envoy_config_route_v3.RateLimit_Action_RequestHeaders{
+						HeaderName:    entry.HeaderMatch.HeaderName,
+						DescriptorKey: entry.HeaderMatch.Key,
 					},
-				})
-			case entry.RemoteAddress != nil:
-				rl.Actions = append(rl.Actions, &envoy_config_route_v3.RateLimit_Action{
-					ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RemoteAddress_{
-						RemoteAddress: &envoy_config_route_v3.RateLimit_Action_RemoteAddress{},
+				},
+			})
+		case entry.HeaderValueMatch != nil:
+			actions = append(actions, &envoy_config_route_v3.RateLimit_Action{
+				ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_HeaderValueMatch_{
+					HeaderValueMatch: &envoy_config_route_v3.RateLimit_Action_HeaderValueMatch{
+						DescriptorValue: entry.HeaderValueMatch.Value,
+						ExpectMatch:     wrapperspb.Bool(entry.HeaderValueMatch.ExpectMatch),
+						Headers:         headerMatcher(entry.HeaderValueMatch.Headers),
 					},
-				})
+				},
+			})
+		case entry.RemoteAddress != nil:
+			actions = append(actions, &envoy_config_route_v3.RateLimit_Action{
+				ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RemoteAddress_{
+					RemoteAddress: &envoy_config_route_v3.RateLimit_Action_RemoteAddress{},
+				},
+			})
+		case entry.MaskedRemoteAddress != nil:
+			masked := &envoy_config_route_v3.RateLimit_Action_MaskedRemoteAddress{}
+			if entry.MaskedRemoteAddress.V4PrefixLength > 0 {
+				masked.V4PrefixMaskLen = wrapperspb.UInt32(entry.MaskedRemoteAddress.V4PrefixLength)
 			}
+			if entry.MaskedRemoteAddress.V6PrefixLength > 0 {
+				masked.V6PrefixMaskLen = wrapperspb.UInt32(entry.MaskedRemoteAddress.V6PrefixLength)
+			}
+			actions = append(actions, &envoy_config_route_v3.RateLimit_Action{
+				ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_MaskedRemoteAddress_{
+					MaskedRemoteAddress: masked,
+				},
+			})
+		case entry.QueryParameter != nil:
+			actions = append(actions, &envoy_config_route_v3.RateLimit_Action{
+				ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_QueryParameters_{
+					QueryParameters: &envoy_config_route_v3.RateLimit_Action_QueryParameters{
+						QueryParameterName: entry.QueryParameter.ParameterName,
+						DescriptorKey:      entry.QueryParameter.Key,
+					},
+				},
+			})
+		case entry.Metadata != nil:
+			actions = append(actions, &envoy_config_route_v3.RateLimit_Action{
+				ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_Metadata{
+					Metadata: &envoy_config_route_v3.RateLimit_Action_MetaData{
+						DescriptorKey: entry.Metadata.Key,
+						MetadataKey:   metadataKey(entry.Metadata.Filter, entry.Metadata.Path),
+						DefaultValue:  entry.Metadata.DefaultValue,
+						Source:        envoy_config_route_v3.RateLimit_Action_MetaData_DYNAMIC,
+					},
+				},
+			})
+		case entry.DestinationCluster != nil:
+			actions = append(actions, &envoy_config_route_v3.RateLimit_Action{
+				ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_DestinationCluster_{
+					DestinationCluster: &envoy_config_route_v3.RateLimit_Action_DestinationCluster{},
+				},
+			})
 		}
-
-		rateLimits = append(rateLimits, &rl)
 	}
 
-	return rateLimits
+	return actions
+}
+
+// metadataKey returns a MetadataKey for the given metadata
+// namespace and path of keys.
+func metadataKey(filter string, path []string) *envoy_type_metadata_v3.MetadataKey {
+	key := &envoy_type_metadata_v3.MetadataKey{
+		Key: filter,
+	}
+	for _, segment := range path {
+		key.Path = append(key.Path, &envoy_type_metadata_v3.MetadataKey_PathSegment{
+			Segment: &envoy_type_metadata_v3.MetadataKey_PathSegment_Key{
+				Key: segment,
+			},
+		})
+	}
+	return key
 }
 
 // GlobalRateLimitConfig stores configuration for
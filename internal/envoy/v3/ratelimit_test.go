@@ -20,10 +20,12 @@ import (
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_common_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
 	envoy_filter_http_local_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
 	envoy_filter_http_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_type_metadata_v3 "github.com/envoyproxy/go-control-plane/envoy/type/metadata/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/stretchr/testify/assert"
@@ -86,6 +88,196 @@ func TestLocalRateLimitConfig(t *testing.T) {
 					},
 				}),
 		},
+		"descriptors": {
+			policy: &dag.LocalRateLimitPolicy{
+				MaxTokens:     100,
+				TokensPerFill: 100,
+				FillInterval:  time.Second,
+				Descriptors: []*dag.LocalRateLimitDescriptor{
+					{
+						Entries: []dag.LocalRateLimitDescriptorEntry{
+							{
+								RateLimitDescriptorEntry: dag.RateLimitDescriptorEntry{
+									RemoteAddress: &dag.RemoteAddressDescriptorEntry{},
+								},
+								Value: "10.0.0.1",
+							},
+						},
+						MaxTokens:     5,
+						TokensPerFill: 5,
+						FillInterval:  time.Minute,
+					},
+					{
+						Entries: []dag.LocalRateLimitDescriptorEntry{
+							{
+								RateLimitDescriptorEntry: dag.RateLimitDescriptorEntry{
+									HeaderMatch: &dag.HeaderMatchDescriptorEntry{
+										HeaderName: "X-Tenant",
+										Key:        "tenant",
+									},
+								},
+								Value: "free",
+							},
+							{
+								RateLimitDescriptorEntry: dag.RateLimitDescriptorEntry{
+									GenericKey: &dag.GenericKeyDescriptorEntry{
+										Value: "api",
+									},
+								},
+							},
+						},
+						MaxTokens:     20,
+						TokensPerFill: 10,
+						FillInterval:  time.Second,
+					},
+				},
+			},
+			statPrefix: "stat-prefix",
+			want: protobuf.MustMarshalAny(
+				&envoy_filter_http_local_ratelimit_v3.LocalRateLimit{
+					StatPrefix: "stat-prefix",
+					TokenBucket: &envoy_type_v3.TokenBucket{
+						MaxTokens:     100,
+						TokensPerFill: wrapperspb.UInt32(100),
+						FillInterval:  durationpb.New(time.Second),
+					},
+					FilterEnabled: &envoy_config_core_v3.RuntimeFractionalPercent{
+						DefaultValue: &envoy_type_v3.FractionalPercent{
+							Numerator:   100,
+							Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+						},
+					},
+					FilterEnforced: &envoy_config_core_v3.RuntimeFractionalPercent{
+						DefaultValue: &envoy_type_v3.FractionalPercent{
+							Numerator:   100,
+							Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+						},
+					},
+					AlwaysConsumeDefaultTokenBucket: wrapperspb.Bool(false),
+					Descriptors: []*envoy_common_ratelimit_v3.LocalRateLimitDescriptor{
+						{
+							Entries: []*envoy_common_ratelimit_v3.RateLimitDescriptor_Entry{
+								{Key: "remote_address", Value: "10.0.0.1"},
+							},
+							TokenBucket: &envoy_type_v3.TokenBucket{
+								MaxTokens:     5,
+								TokensPerFill: wrapperspb.UInt32(5),
+								FillInterval:  durationpb.New(time.Minute),
+							},
+						},
+						{
+							Entries: []*envoy_common_ratelimit_v3.RateLimitDescriptor_Entry{
+								{Key: "tenant", Value: "free"},
+								{Key: "generic_key", Value: "api"},
+							},
+							TokenBucket: &envoy_type_v3.TokenBucket{
+								MaxTokens:     20,
+								TokensPerFill: wrapperspb.UInt32(10),
+								FillInterval:  durationpb.New(time.Second),
+							},
+						},
+					},
+					RateLimits: []*envoy_config_route_v3.RateLimit{
+						{
+							Actions: []*envoy_config_route_v3.RateLimit_Action{
+								{
+									ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RemoteAddress_{
+										RemoteAddress: &envoy_config_route_v3.RateLimit_Action_RemoteAddress{},
+									},
+								},
+							},
+						},
+						{
+							Actions: []*envoy_config_route_v3.RateLimit_Action{
+								{
+									ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RequestHeaders_{
+										RequestHeaders: &envoy_config_route_v3.RateLimit_Action_RequestHeaders{
+											HeaderName:    "X-Tenant",
+											DescriptorKey: "tenant",
+										},
+									},
+								},
+								{
+									ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_GenericKey_{
+										GenericKey: &envoy_config_route_v3.RateLimit_Action_GenericKey{
+											DescriptorValue: "api",
+										},
+									},
+								},
+							},
+						},
+					},
+				}),
+		},
+		"wildcard descriptor": {
+			policy: &dag.LocalRateLimitPolicy{
+				MaxTokens:             100,
+				TokensPerFill:         100,
+				FillInterval:          time.Second,
+				MaxDynamicDescriptors: 1000,
+				Descriptors: []*dag.LocalRateLimitDescriptor{
+					{
+						Entries: []dag.LocalRateLimitDescriptorEntry{
+							{
+								RateLimitDescriptorEntry: dag.RateLimitDescriptorEntry{
+									RemoteAddress: &dag.RemoteAddressDescriptorEntry{},
+								},
+							},
+						},
+						MaxTokens:     5,
+						TokensPerFill: 5,
+						FillInterval:  time.Minute,
+					},
+				},
+			},
+			statPrefix: "stat-prefix",
+			want: protobuf.MustMarshalAny(
+				&envoy_filter_http_local_ratelimit_v3.LocalRateLimit{
+					StatPrefix: "stat-prefix",
+					TokenBucket: &envoy_type_v3.TokenBucket{
+						MaxTokens:     100,
+						TokensPerFill: wrapperspb.UInt32(100),
+						FillInterval:  durationpb.New(time.Second),
+					},
+					FilterEnabled: &envoy_config_core_v3.RuntimeFractionalPercent{
+						DefaultValue: &envoy_type_v3.FractionalPercent{
+							Numerator:   100,
+							Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+						},
+					},
+					FilterEnforced: &envoy_config_core_v3.RuntimeFractionalPercent{
+						DefaultValue: &envoy_type_v3.FractionalPercent{
+							Numerator:   100,
+							Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+						},
+					},
+					AlwaysConsumeDefaultTokenBucket: wrapperspb.Bool(false),
+					MaxDynamicDescriptors:           wrapperspb.UInt32(1000),
+					Descriptors: []*envoy_common_ratelimit_v3.LocalRateLimitDescriptor{
+						{
+							Entries: []*envoy_common_ratelimit_v3.RateLimitDescriptor_Entry{
+								{Key: "remote_address"},
+							},
+							TokenBucket: &envoy_type_v3.TokenBucket{
+								MaxTokens:     5,
+								TokensPerFill: wrapperspb.UInt32(5),
+								FillInterval:  durationpb.New(time.Minute),
+							},
+						},
+					},
+					RateLimits: []*envoy_config_route_v3.RateLimit{
+						{
+							Actions: []*envoy_config_route_v3.RateLimit_Action{
+								{
+									ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RemoteAddress_{
+										RemoteAddress: &envoy_config_route_v3.RateLimit_Action_RemoteAddress{},
+									},
+								},
+							},
+						},
+					},
+				}),
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -235,6 +427,100 @@ func TestGlobalRateLimits(t *testing.T) {
 				},
 			},
 		},
+		"query parameter, metadata, masked remote address and destination cluster descriptors": {
+			descriptors: []*dag.RateLimitDescriptor{
+				{
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							QueryParameter: &dag.QueryParameterDescriptorEntry{
+								ParameterName: "api_key",
+								Key:           "api-key",
+							},
+						},
+						{
+							Metadata: &dag.MetadataDescriptorEntry{
+								Filter:       "envoy.filters.http.jwt_authn",
+								Path:         []string{"provider-1", "sub"},
+								Key:          "subject",
+								DefaultValue: "anonymous",
+							},
+						},
+					},
+				},
+				{
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							MaskedRemoteAddress: &dag.MaskedRemoteAddressDescriptorEntry{},
+						},
+						{
+							MaskedRemoteAddress: &dag.MaskedRemoteAddressDescriptorEntry{
+								V4PrefixLength: 24,
+								V6PrefixLength: 64,
+							},
+						},
+						{
+							DestinationCluster: &dag.DestinationClusterDescriptorEntry{},
+						},
+					},
+				},
+			},
+			want: []*envoy_config_route_v3.RateLimit{
+				{
+					Actions: []*envoy_config_route_v3.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_QueryParameters_{
+								QueryParameters: &envoy_config_route_v3.RateLimit_Action_QueryParameters{
+									QueryParameterName: "api_key",
+									DescriptorKey:      "api-key",
+								},
+							},
+						},
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_Metadata{
+								Metadata: &envoy_config_route_v3.RateLimit_Action_MetaData{
+									DescriptorKey: "subject",
+									MetadataKey: &envoy_type_metadata_v3.MetadataKey{
+										Key: "envoy.filters.http.jwt_authn",
+										Path: []*envoy_type_metadata_v3.MetadataKey_PathSegment{
+											{
+												Segment: &envoy_type_metadata_v3.MetadataKey_PathSegment_Key{Key: "provider-1"},
+											},
+											{
+												Segment: &envoy_type_metadata_v3.MetadataKey_PathSegment_Key{Key: "sub"},
+											},
+										},
+									},
+									DefaultValue: "anonymous",
+									Source:       envoy_config_route_v3.RateLimit_Action_MetaData_DYNAMIC,
+								},
+							},
+						},
+					},
+				},
+				{
+					Actions: []*envoy_config_route_v3.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_MaskedRemoteAddress_{
+								MaskedRemoteAddress: &envoy_config_route_v3.RateLimit_Action_MaskedRemoteAddress{},
+							},
+						},
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_MaskedRemoteAddress_{
+								MaskedRemoteAddress: &envoy_config_route_v3.RateLimit_Action_MaskedRemoteAddress{
+									V4PrefixMaskLen: wrapperspb.UInt32(24),
+									V6PrefixMaskLen: wrapperspb.UInt32(64),
+								},
+							},
+						},
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_DestinationCluster_{
+								DestinationCluster: &envoy_config_route_v3.RateLimit_Action_DestinationCluster{},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sort"
 	"strings"
 	"text/template"
@@ -52,6 +53,7 @@ func VirtualHostAndRoutes(vh *dag.VirtualHost, dagRoutes []*dag.Route, secure bo
 	}
 
 	evh := VirtualHost(vh.Name, envoyRoutes...)
+	evh.VirtualClusters = virtualClusters(dagRoutes, secure)
 
 	if vh.CORSPolicy != nil {
 		if evh.TypedPerFilterConfig == nil {
@@ -110,8 +112,9 @@ func getRouteMetadata(dagRoute *dag.Route) *envoy_config_core_v3.Metadata {
 // buildRoute converts a DAG route to an Envoy route.
 func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_config_route_v3.Route {
 	route := &envoy_config_route_v3.Route{
-		Match:    RouteMatch(dagRoute),
-		Metadata: getRouteMetadata(dagRoute),
+		Match:      RouteMatch(dagRoute),
+		Metadata:   getRouteMetadata(dagRoute),
+		StatPrefix: dagRoute.StatPrefix,
 	}
 
 	switch {
@@ -176,6 +179,12 @@ func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_confi
 			route.TypedPerFilterConfig[JWTAuthnFilterName] = protobuf.MustMarshalAny(&envoy_filter_http_jwt_authn_v3.PerRouteConfig{
 				RequirementSpecifier: &envoy_filter_http_jwt_authn_v3.PerRouteConfig_RequirementName{RequirementName: dagRoute.JWTProvider},
 			})
+
+			if len(dagRoute.JWTRequiredClaims) > 0 {
+				route.TypedPerFilterConfig[JWTClaimsFilterName] = protobuf.MustMarshalAny(
+					jwtClaimsConfig(dagRoute.JWTProvider, dagRoute.JWTRequiredClaims),
+				)
+			}
 		}
 
 		// If IP filtering is enabled, add per-route filtering
@@ -185,6 +194,13 @@ func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_confi
 			)
 		}
 
+		// If an authorization policy is set, add per-route filtering
+		if dagRoute.RBACPolicy != nil {
+			route.TypedPerFilterConfig[AuthorizationPolicyFilterName] = protobuf.MustMarshalAny(
+				rbacPolicyConfig(dagRoute.RBACPolicy, dagRoute.JWTProvider),
+			)
+		}
+
 		// Remove empty map if no per-filter config was added.
 		if len(route.TypedPerFilterConfig) == 0 {
 			route.TypedPerFilterConfig = nil
@@ -228,44 +244,175 @@ func ipFilterConfig(allow bool, rules []dag.IPFilterRule) *envoy_filter_http_rba
 	principals := make([]*envoy_config_rbac_v3.Principal, 0, len(rules))
 
 	for _, f := range rules {
-		var principal *envoy_config_rbac_v3.Principal
+		principals = append(principals, ipFilterPrincipal(f))
+	}
 
-		prefixLen, _ := f.CIDR.Mask.Size()
-		cidr := &envoy_config_core_v3.CidrRange{
-			AddressPrefix: f.CIDR.IP.String(),
-			PrefixLen:     wrapperspb.UInt32(uint32(prefixLen)), //nolint:gosec // disable G115
+	return &envoy_filter_http_rbac_v3.RBACPerRoute{
+		Rbac: &envoy_filter_http_rbac_v3.RBAC{
+			Rules: &envoy_config_rbac_v3.RBAC{
+				Action: action,
+				Policies: map[string]*envoy_config_rbac_v3.Policy{
+					"ip-rules": {
+						Permissions: []*envoy_config_rbac_v3.Permission{
+							{
+								Rule: &envoy_config_rbac_v3.Permission_Any{Any: true},
+							},
+						},
+						Principals: principals,
+					},
+				},
+			},
+		},
+	}
+}
+
+// ipFilterPrincipal returns an RBAC principal matching the rule's CIDR.
+func ipFilterPrincipal(f dag.IPFilterRule) *envoy_config_rbac_v3.Principal {
+	prefixLen, _ := f.CIDR.Mask.Size()
+	cidr := &envoy_config_core_v3.CidrRange{
+		AddressPrefix: f.CIDR.IP.String(),
+		PrefixLen:     wrapperspb.UInt32(uint32(prefixLen)), //nolint:gosec // disable G115
+	}
+
+	// Note that `source_ip` is not supported: `source_ip` respects
+	// PROXY, but not X-Forwarded-For.
+	if f.Remote {
+		return &envoy_config_rbac_v3.Principal{
+			Identifier: &envoy_config_rbac_v3.Principal_RemoteIp{
+				RemoteIp: cidr,
+			},
 		}
+	}
+
+	return &envoy_config_rbac_v3.Principal{
+		Identifier: &envoy_config_rbac_v3.Principal_DirectRemoteIp{
+			DirectRemoteIp: cidr,
+		},
+	}
+}
+
+// jwtClaimsConfig returns a per-route RBAC config that only allows
+// requests whose JWT, validated by the named provider, has all of
+// the required claims.
+func jwtClaimsConfig(provider string, claims []dag.JWTRequiredClaim) *envoy_filter_http_rbac_v3.RBACPerRoute {
+	principals := make([]*envoy_config_rbac_v3.Principal, 0, len(claims))
+
+	for _, claim := range claims {
+		principals = append(principals, jwtClaimPrincipal(provider, claim))
+	}
+
+	return &envoy_filter_http_rbac_v3.RBACPerRoute{
+		Rbac: &envoy_filter_http_rbac_v3.RBAC{
+			Rules: &envoy_config_rbac_v3.RBAC{
+				Action: envoy_config_rbac_v3.RBAC_ALLOW,
+				Policies: map[string]*envoy_config_rbac_v3.Policy{
+					"jwt-claims": {
+						Permissions: []*envoy_config_rbac_v3.Permission{
+							{
+								Rule: &envoy_config_rbac_v3.Permission_Any{Any: true},
+							},
+						},
+						Principals: []*envoy_config_rbac_v3.Principal{
+							{
+								Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+									AndIds: &envoy_config_rbac_v3.Principal_Set{
+										Ids: principals,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jwtClaimPrincipal returns an RBAC principal matching requests whose
+// JWT, validated by the named provider, has one of the claim's values.
+func jwtClaimPrincipal(provider string, claim dag.JWTRequiredClaim) *envoy_config_rbac_v3.Principal {
+	path := []*envoy_matcher_v3.MetadataMatcher_PathSegment{{
+		Segment: &envoy_matcher_v3.MetadataMatcher_PathSegment_Key{Key: provider},
+	}}
+	for _, key := range strings.Split(claim.Name, ".") {
+		path = append(path, &envoy_matcher_v3.MetadataMatcher_PathSegment{
+			Segment: &envoy_matcher_v3.MetadataMatcher_PathSegment_Key{Key: key},
+		})
+	}
 
-		if f.Remote {
-			principal = &envoy_config_rbac_v3.Principal{
-				Identifier: &envoy_config_rbac_v3.Principal_RemoteIp{
-					RemoteIp: cidr,
+	// The claim either has one of the values, or is
+	// a list containing one of the values.
+	values := make([]*envoy_matcher_v3.ValueMatcher, 0, len(claim.Values))
+	for _, value := range claim.Values {
+		values = append(values, &envoy_matcher_v3.ValueMatcher{
+			MatchPattern: &envoy_matcher_v3.ValueMatcher_StringMatch{
+				StringMatch: &envoy_matcher_v3.StringMatcher{
+					MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: value},
 				},
-			}
-		} else {
-			principal = &envoy_config_rbac_v3.Principal{
-				Identifier: &envoy_config_rbac_v3.Principal_DirectRemoteIp{
-					DirectRemoteIp: cidr,
+			},
+		})
+	}
+
+	matchers := append([]*envoy_matcher_v3.ValueMatcher{}, values...)
+	for _, value := range values {
+		matchers = append(matchers, &envoy_matcher_v3.ValueMatcher{
+			MatchPattern: &envoy_matcher_v3.ValueMatcher_ListMatch{
+				ListMatch: &envoy_matcher_v3.ListMatcher{
+					MatchPattern: &envoy_matcher_v3.ListMatcher_OneOf{OneOf: value},
 				},
-			}
-		}
-		// Note that `source_ip` is not supported: `source_ip` respects
-		// PROXY, but not X-Forwarded-For.
-		principals = append(principals, principal)
+			},
+		})
+	}
+
+	return &envoy_config_rbac_v3.Principal{
+		Identifier: &envoy_config_rbac_v3.Principal_Metadata{
+			Metadata: &envoy_matcher_v3.MetadataMatcher{
+				Filter: JWTAuthnFilterName,
+				Path:   path,
+				Value: &envoy_matcher_v3.ValueMatcher{
+					MatchPattern: &envoy_matcher_v3.ValueMatcher_OrMatch{
+						OrMatch: &envoy_matcher_v3.OrMatcher{
+							ValueMatchers: matchers,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// rbacPolicyConfig returns a per-route RBAC config that allows requests
+// matching one of the policy's allow rules, if any, and none of its deny
+// rules. JWT claims are matched against the JWT validated by the named
+// provider.
+func rbacPolicyConfig(policy *dag.RBACPolicy, jwtProvider string) *envoy_filter_http_rbac_v3.RBACPerRoute {
+	var ids []*envoy_config_rbac_v3.Principal
+
+	if len(policy.Allow) > 0 {
+		ids = append(ids, orIDs(rbacRulePrincipals(policy.Allow, jwtProvider)))
+	}
+	if len(policy.Deny) > 0 {
+		ids = append(ids, &envoy_config_rbac_v3.Principal{
+			Identifier: &envoy_config_rbac_v3.Principal_NotId{
+				NotId: orIDs(rbacRulePrincipals(policy.Deny, jwtProvider)),
+			},
+		})
 	}
 
 	return &envoy_filter_http_rbac_v3.RBACPerRoute{
 		Rbac: &envoy_filter_http_rbac_v3.RBAC{
 			Rules: &envoy_config_rbac_v3.RBAC{
-				Action: action,
+				Action: envoy_config_rbac_v3.RBAC_ALLOW,
 				Policies: map[string]*envoy_config_rbac_v3.Policy{
-					"ip-rules": {
+					"authorization-policy": {
 						Permissions: []*envoy_config_rbac_v3.Permission{
 							{
 								Rule: &envoy_config_rbac_v3.Permission_Any{Any: true},
 							},
 						},
-						Principals: principals,
+						Principals: []*envoy_config_rbac_v3.Principal{
+							andIDs(ids),
+						},
 					},
 				},
 			},
@@ -273,6 +420,127 @@ func ipFilterConfig(allow bool, rules []dag.IPFilterRule) *envoy_filter_http_rba
 	}
 }
 
+// rbacRulePrincipals returns an RBAC principal for each rule, matching
+// requests that match all of the rule's conditions.
+func rbacRulePrincipals(rules []dag.RBACRule, jwtProvider string) []*envoy_config_rbac_v3.Principal {
+	principals := make([]*envoy_config_rbac_v3.Principal, 0, len(rules))
+
+	for _, rule := range rules {
+		var ids []*envoy_config_rbac_v3.Principal
+
+		if len(rule.Methods) > 0 {
+			methods := make([]*envoy_config_rbac_v3.Principal, 0, len(rule.Methods))
+			for _, method := range rule.Methods {
+				methods = append(methods, &envoy_config_rbac_v3.Principal{
+					Identifier: &envoy_config_rbac_v3.Principal_Header{
+						Header: &envoy_config_route_v3.HeaderMatcher{
+							Name: ":method",
+							HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+								StringMatch: &envoy_matcher_v3.StringMatcher{
+									MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: method},
+								},
+							},
+						},
+					},
+				})
+			}
+			ids = append(ids, orIDs(methods))
+		}
+
+		if len(rule.Paths) > 0 {
+			paths := make([]*envoy_config_rbac_v3.Principal, 0, len(rule.Paths))
+			for _, path := range rule.Paths {
+				paths = append(paths, &envoy_config_rbac_v3.Principal{
+					Identifier: &envoy_config_rbac_v3.Principal_UrlPath{
+						UrlPath: &envoy_matcher_v3.PathMatcher{
+							Rule: &envoy_matcher_v3.PathMatcher_Path{
+								Path: pathStringMatcher(path),
+							},
+						},
+					},
+				})
+			}
+			ids = append(ids, orIDs(paths))
+		}
+
+		for _, header := range headerMatcher(rule.Headers) {
+			ids = append(ids, &envoy_config_rbac_v3.Principal{
+				Identifier: &envoy_config_rbac_v3.Principal_Header{
+					Header: header,
+				},
+			})
+		}
+
+		if len(rule.ClientCertificateNames) > 0 {
+			names := make([]*envoy_config_rbac_v3.Principal, 0, len(rule.ClientCertificateNames))
+			for _, name := range rule.ClientCertificateNames {
+				names = append(names, &envoy_config_rbac_v3.Principal{
+					Identifier: &envoy_config_rbac_v3.Principal_Authenticated_{
+						Authenticated: &envoy_config_rbac_v3.Principal_Authenticated{
+							PrincipalName: &envoy_matcher_v3.StringMatcher{
+								MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: name},
+							},
+						},
+					},
+				})
+			}
+			ids = append(ids, orIDs(names))
+		}
+
+		for _, claim := range rule.JWTClaims {
+			ids = append(ids, jwtClaimPrincipal(jwtProvider, claim))
+		}
+
+		if len(rule.SourceIPs) > 0 {
+			sources := make([]*envoy_config_rbac_v3.Principal, 0, len(rule.SourceIPs))
+			for _, source := range rule.SourceIPs {
+				sources = append(sources, ipFilterPrincipal(source))
+			}
+			ids = append(ids, orIDs(sources))
+		}
+
+		principals = append(principals, andIDs(ids))
+	}
+
+	return principals
+}
+
+// pathStringMatcher returns a string matcher for the path condition.
+func pathStringMatcher(cond dag.MatchCondition) *envoy_matcher_v3.StringMatcher {
+	switch c := cond.(type) {
+	case *dag.ExactMatchCondition:
+		return &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: c.Path},
+		}
+	case *dag.RegexMatchCondition:
+		return &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{SafeRegex: safeRegexMatch(c.Regex)},
+		}
+	case *dag.PrefixMatchCondition:
+		return &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: c.Prefix},
+		}
+	default:
+		return nil
+	}
+}
+
+func andIDs(ids []*envoy_config_rbac_v3.Principal) *envoy_config_rbac_v3.Principal {
+	return &envoy_config_rbac_v3.Principal{
+		Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+			AndIds: &envoy_config_rbac_v3.Principal_Set{Ids: ids},
+		},
+	}
+}
+
+func orIDs(ids []*envoy_config_rbac_v3.Principal) *envoy_config_rbac_v3.Principal {
+	return &envoy_config_rbac_v3.Principal{
+		Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+			OrIds: &envoy_config_rbac_v3.Principal_Set{Ids: ids},
+		},
+	}
+}
+
 // RouteMatch creates a *envoy_config_route_v3.RouteMatch for the supplied *dag.Route.
 func RouteMatch(route *dag.Route) *envoy_config_route_v3.RouteMatch {
 	routeMatch := PathRouteMatch(route.PathMatchCondition)
@@ -325,6 +593,88 @@ func PathRouteMatch(pathMatchCondition dag.MatchCondition) *envoy_config_route_v
 	}
 }
 
+// virtualClusters returns a virtual cluster for each DAG route with a
+// stat prefix that forwards requests upstream. Virtual clusters are
+// named after the stat prefix, with a _2, _3, ... suffix for the second
+// and later routes of a routing object, since their names must be
+// unique within the virtual host. Envoy attributes a request to the
+// first virtual cluster that matches it, so they are in route order.
+func virtualClusters(dagRoutes []*dag.Route, secure bool) []*envoy_config_route_v3.VirtualCluster {
+	var vclusters []*envoy_config_route_v3.VirtualCluster
+	seen := map[string]int{}
+	for _, route := range dagRoutes {
+		switch {
+		case route.StatPrefix == "", len(route.Clusters) == 0:
+			continue
+		case route.HTTPSUpgrade && !secure, route.DirectResponse != nil, route.Redirect != nil:
+			continue
+		}
+
+		var headers []*envoy_config_route_v3.HeaderMatcher
+		if path := pathHeaderMatcher(route.PathMatchCondition); path != nil {
+			headers = append(headers, path)
+		}
+		headers = append(headers, headerMatcher(route.HeaderMatchConditions)...)
+
+		name := route.StatPrefix
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", name, n)
+		}
+
+		vclusters = append(vclusters, &envoy_config_route_v3.VirtualCluster{
+			Name:    name,
+			Headers: headers,
+		})
+	}
+	return vclusters
+}
+
+// pathHeaderMatcher returns a matcher on the :path pseudo-header that is
+// equivalent to the path match condition of a route. Unlike the path a
+// route matches, :path includes the query string. Query parameter match
+// conditions are not represented.
+func pathHeaderMatcher(pathMatchCondition dag.MatchCondition) *envoy_config_route_v3.HeaderMatcher {
+	var matcher *envoy_matcher_v3.StringMatcher
+
+	switch c := pathMatchCondition.(type) {
+	case *dag.RegexMatchCondition:
+		matcher = &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{
+				SafeRegex: safeRegexMatch("^(?:" + c.Regex + `)(\?.*)?`),
+			},
+		}
+	case *dag.PrefixMatchCondition:
+		prefix := strings.TrimRight(c.Prefix, "/")
+		if c.PrefixMatchType != dag.PrefixMatchSegment || prefix == "" {
+			matcher = &envoy_matcher_v3.StringMatcher{
+				MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: c.Prefix},
+			}
+			break
+		}
+		matcher = &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{
+				SafeRegex: safeRegexMatch("^" + regexp.QuoteMeta(prefix) + `([/?].*)?`),
+			},
+		}
+	case *dag.ExactMatchCondition:
+		matcher = &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{
+				SafeRegex: safeRegexMatch("^" + regexp.QuoteMeta(c.Path) + `(\?.*)?`),
+			},
+		}
+	default:
+		return nil
+	}
+
+	return &envoy_config_route_v3.HeaderMatcher{
+		Name: ":path",
+		HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+			StringMatch: matcher,
+		},
+	}
+}
+
 // routeDirectResponse creates a *envoy_config_route_v3.Route_DirectResponse for the
 // http status code and body supplied. This allows a direct response to a route request
 // with an HTTP status code without needing to route to a specific cluster.
@@ -1058,6 +1058,30 @@ func TestBuildRouteWithDirectResponse(t *testing.T) {
 				},
 			},
 		},
+		"direct-response-with-stat-prefix": {
+			dagRoute: &dag.Route{
+				DirectResponse: &dag.DirectResponse{
+					StatusCode: 200,
+				},
+				PathMatchCondition: &dag.PrefixMatchCondition{
+					Prefix:          "/foo",
+					PrefixMatchType: dag.PrefixMatchString,
+				},
+				StatPrefix: "httpproxy_default_simple",
+			},
+			vhostName: "example",
+			secure:    false,
+			want: &envoy_config_route_v3.Route{
+				TypedPerFilterConfig: map[string]*anypb.Any{},
+				Action:               routeDirectResponse(&dag.DirectResponse{StatusCode: 200}),
+				Match: &envoy_config_route_v3.RouteMatch{
+					PathSpecifier: &envoy_config_route_v3.RouteMatch_Prefix{
+						Prefix: "/foo",
+					},
+				},
+				StatPrefix: "httpproxy_default_simple",
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -1298,6 +1322,200 @@ func TestVirtualHost(t *testing.T) {
 	}
 }
 
+func TestVirtualClusters(t *testing.T) {
+	cluster := &dag.Cluster{
+		Upstream: &dag.Service{
+			Weighted: dag.WeightedService{
+				ServiceName:      "kuard",
+				ServiceNamespace: "default",
+				ServicePort:      core_v1.ServicePort{Port: 8080},
+			},
+		},
+	}
+
+	pathMatcher := func(m *envoy_matcher_v3.StringMatcher) *envoy_config_route_v3.HeaderMatcher {
+		return &envoy_config_route_v3.HeaderMatcher{
+			Name:                 ":path",
+			HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{StringMatch: m},
+		}
+	}
+	regexMatcher := func(regex string) *envoy_config_route_v3.HeaderMatcher {
+		return pathMatcher(&envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{SafeRegex: safeRegexMatch(regex)},
+		})
+	}
+
+	tests := map[string]struct {
+		routes []*dag.Route
+		secure bool
+		want   []*envoy_config_route_v3.VirtualCluster
+	}{
+		"no stat prefix": {
+			routes: []*dag.Route{{
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+				Clusters:           []*dag.Cluster{cluster},
+			}},
+			want: nil,
+		},
+		"string prefix": {
+			routes: []*dag.Route{{
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/api", PrefixMatchType: dag.PrefixMatchString},
+				Clusters:           []*dag.Cluster{cluster},
+				StatPrefix:         "httpproxy_default_simple",
+			}},
+			want: []*envoy_config_route_v3.VirtualCluster{{
+				Name: "httpproxy_default_simple",
+				Headers: []*envoy_config_route_v3.HeaderMatcher{
+					pathMatcher(&envoy_matcher_v3.StringMatcher{
+						MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: "/api"},
+					}),
+				},
+			}},
+		},
+		"segment prefix": {
+			routes: []*dag.Route{{
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/api.v1/", PrefixMatchType: dag.PrefixMatchSegment},
+				Clusters:           []*dag.Cluster{cluster},
+				StatPrefix:         "httproute_default_simple",
+			}},
+			want: []*envoy_config_route_v3.VirtualCluster{{
+				Name:    "httproute_default_simple",
+				Headers: []*envoy_config_route_v3.HeaderMatcher{regexMatcher(`^/api\.v1([/?].*)?`)},
+			}},
+		},
+		"exact path and header": {
+			routes: []*dag.Route{{
+				PathMatchCondition: &dag.ExactMatchCondition{Path: "/login"},
+				HeaderMatchConditions: []dag.HeaderMatchCondition{{
+					Name:      "x-tenant",
+					MatchType: dag.HeaderMatchTypePresent,
+				}},
+				Clusters:   []*dag.Cluster{cluster},
+				StatPrefix: "ingress_default_simple",
+			}},
+			want: []*envoy_config_route_v3.VirtualCluster{{
+				Name: "ingress_default_simple",
+				Headers: []*envoy_config_route_v3.HeaderMatcher{
+					regexMatcher(`^/login(\?.*)?`),
+					{
+						Name:                 "x-tenant",
+						HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_PresentMatch{PresentMatch: true},
+					},
+				},
+			}},
+		},
+		"regex path": {
+			routes: []*dag.Route{{
+				PathMatchCondition: &dag.RegexMatchCondition{Regex: "/v1|/v2"},
+				Clusters:           []*dag.Cluster{cluster},
+				StatPrefix:         "httpproxy_default_simple",
+			}},
+			want: []*envoy_config_route_v3.VirtualCluster{{
+				Name:    "httpproxy_default_simple",
+				Headers: []*envoy_config_route_v3.HeaderMatcher{regexMatcher(`^(?:/v1|/v2)(\?.*)?`)},
+			}},
+		},
+		"several routes of a routing object": {
+			routes: []*dag.Route{
+				{
+					PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/api", PrefixMatchType: dag.PrefixMatchString},
+					Clusters:           []*dag.Cluster{cluster},
+					StatPrefix:         "httpproxy_default_simple",
+				},
+				{
+					PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/", PrefixMatchType: dag.PrefixMatchString},
+					Clusters:           []*dag.Cluster{cluster},
+					StatPrefix:         "httproute_default_simple",
+				},
+				{
+					PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/admin", PrefixMatchType: dag.PrefixMatchString},
+					Clusters:           []*dag.Cluster{cluster},
+					StatPrefix:         "httpproxy_default_simple",
+				},
+				{
+					PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/static", PrefixMatchType: dag.PrefixMatchString},
+					Clusters:           []*dag.Cluster{cluster},
+					StatPrefix:         "httpproxy_default_simple",
+				},
+			},
+			want: []*envoy_config_route_v3.VirtualCluster{
+				{
+					Name: "httpproxy_default_simple",
+					Headers: []*envoy_config_route_v3.HeaderMatcher{
+						pathMatcher(&envoy_matcher_v3.StringMatcher{
+							MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: "/api"},
+						}),
+					},
+				},
+				{
+					Name: "httproute_default_simple",
+					Headers: []*envoy_config_route_v3.HeaderMatcher{
+						pathMatcher(&envoy_matcher_v3.StringMatcher{
+							MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: "/"},
+						}),
+					},
+				},
+				{
+					Name: "httpproxy_default_simple_2",
+					Headers: []*envoy_config_route_v3.HeaderMatcher{
+						pathMatcher(&envoy_matcher_v3.StringMatcher{
+							MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: "/admin"},
+						}),
+					},
+				},
+				{
+					Name: "httpproxy_default_simple_3",
+					Headers: []*envoy_config_route_v3.HeaderMatcher{
+						pathMatcher(&envoy_matcher_v3.StringMatcher{
+							MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: "/static"},
+						}),
+					},
+				},
+			},
+		},
+		"routes that do not forward upstream are skipped": {
+			routes: []*dag.Route{
+				{
+					PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+					Clusters:           []*dag.Cluster{cluster},
+					HTTPSUpgrade:       true,
+					StatPrefix:         "httpproxy_default_simple",
+				},
+				{
+					PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+					DirectResponse:     &dag.DirectResponse{StatusCode: 200},
+					StatPrefix:         "httpproxy_default_simple",
+				},
+			},
+			want: nil,
+		},
+		"secure upgrade routes forward upstream": {
+			routes: []*dag.Route{{
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+				Clusters:           []*dag.Cluster{cluster},
+				HTTPSUpgrade:       true,
+				StatPrefix:         "httpproxy_default_simple",
+			}},
+			secure: true,
+			want: []*envoy_config_route_v3.VirtualCluster{{
+				Name: "httpproxy_default_simple",
+				Headers: []*envoy_config_route_v3.HeaderMatcher{
+					pathMatcher(&envoy_matcher_v3.StringMatcher{
+						MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: "/"},
+					}),
+				},
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := VirtualHostAndRoutes(&dag.VirtualHost{Name: "www.example.com"}, tc.routes, tc.secure)
+			protobuf.ExpectEqual(t, tc.want, got.VirtualClusters)
+		})
+	}
+}
+
 func TestCORSVirtualHost(t *testing.T) {
 	tests := map[string]struct {
 		hostname string
@@ -1997,6 +2215,202 @@ func TestIPFilters(t *testing.T) {
 	}
 }
 
+func TestRBACPolicyConfig(t *testing.T) {
+	tests := map[string]struct {
+		policy *dag.RBACPolicy
+		want   []*envoy_config_rbac_v3.Principal
+	}{
+		"deny only": {
+			policy: &dag.RBACPolicy{
+				Deny: []dag.RBACRule{{
+					Methods: []string{"DELETE"},
+				}},
+			},
+			want: []*envoy_config_rbac_v3.Principal{{
+				Identifier: &envoy_config_rbac_v3.Principal_NotId{
+					NotId: &envoy_config_rbac_v3.Principal{
+						Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+							OrIds: &envoy_config_rbac_v3.Principal_Set{
+								Ids: []*envoy_config_rbac_v3.Principal{{
+									Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+										AndIds: &envoy_config_rbac_v3.Principal_Set{
+											Ids: []*envoy_config_rbac_v3.Principal{{
+												Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+													OrIds: &envoy_config_rbac_v3.Principal_Set{
+														Ids: []*envoy_config_rbac_v3.Principal{{
+															Identifier: &envoy_config_rbac_v3.Principal_Header{
+																Header: &envoy_config_route_v3.HeaderMatcher{
+																	Name: ":method",
+																	HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+																		StringMatch: &envoy_matcher_v3.StringMatcher{
+																			MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "DELETE"},
+																		},
+																	},
+																},
+															},
+														}},
+													},
+												},
+											}},
+										},
+									},
+								}},
+							},
+						},
+					},
+				},
+			}},
+		},
+		"allow with all conditions": {
+			policy: &dag.RBACPolicy{
+				Allow: []dag.RBACRule{{
+					Paths: []dag.MatchCondition{
+						&dag.PrefixMatchCondition{Prefix: "/api"},
+						&dag.RegexMatchCondition{Regex: "/v[0-9]+/.*"},
+					},
+					Headers: []dag.HeaderMatchCondition{{
+						Name:      "x-tenant",
+						Value:     "acme",
+						MatchType: dag.HeaderMatchTypeExact,
+					}},
+					ClientCertificateNames: []string{"admin.example.com"},
+					JWTClaims: []dag.JWTRequiredClaim{{
+						Name:   "role",
+						Values: []string{"admin"},
+					}},
+					SourceIPs: []dag.IPFilterRule{{
+						Remote: false,
+						CIDR: net.IPNet{
+							IP:   net.IPv4(10, 0, 0, 0),
+							Mask: net.CIDRMask(8, 32),
+						},
+					}},
+				}},
+			},
+			want: []*envoy_config_rbac_v3.Principal{{
+				Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+					OrIds: &envoy_config_rbac_v3.Principal_Set{
+						Ids: []*envoy_config_rbac_v3.Principal{{
+							Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+								AndIds: &envoy_config_rbac_v3.Principal_Set{
+									Ids: []*envoy_config_rbac_v3.Principal{
+										{
+											Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+												OrIds: &envoy_config_rbac_v3.Principal_Set{
+													Ids: []*envoy_config_rbac_v3.Principal{
+														{
+															Identifier: &envoy_config_rbac_v3.Principal_UrlPath{
+																UrlPath: &envoy_matcher_v3.PathMatcher{
+																	Rule: &envoy_matcher_v3.PathMatcher_Path{
+																		Path: &envoy_matcher_v3.StringMatcher{
+																			MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{Prefix: "/api"},
+																		},
+																	},
+																},
+															},
+														},
+														{
+															Identifier: &envoy_config_rbac_v3.Principal_UrlPath{
+																UrlPath: &envoy_matcher_v3.PathMatcher{
+																	Rule: &envoy_matcher_v3.PathMatcher_Path{
+																		Path: &envoy_matcher_v3.StringMatcher{
+																			MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{
+																				SafeRegex: &envoy_matcher_v3.RegexMatcher{Regex: "/v[0-9]+/.*"},
+																			},
+																		},
+																	},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+										{
+											Identifier: &envoy_config_rbac_v3.Principal_Header{
+												Header: &envoy_config_route_v3.HeaderMatcher{
+													Name: "x-tenant",
+													HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+														StringMatch: &envoy_matcher_v3.StringMatcher{
+															MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "acme"},
+														},
+													},
+												},
+											},
+										},
+										{
+											Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+												OrIds: &envoy_config_rbac_v3.Principal_Set{
+													Ids: []*envoy_config_rbac_v3.Principal{{
+														Identifier: &envoy_config_rbac_v3.Principal_Authenticated_{
+															Authenticated: &envoy_config_rbac_v3.Principal_Authenticated{
+																PrincipalName: &envoy_matcher_v3.StringMatcher{
+																	MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "admin.example.com"},
+																},
+															},
+														},
+													}},
+												},
+											},
+										},
+										jwtClaimPrincipal("provider-1", dag.JWTRequiredClaim{
+											Name:   "role",
+											Values: []string{"admin"},
+										}),
+										{
+											Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+												OrIds: &envoy_config_rbac_v3.Principal_Set{
+													Ids: []*envoy_config_rbac_v3.Principal{{
+														Identifier: &envoy_config_rbac_v3.Principal_DirectRemoteIp{
+															DirectRemoteIp: &envoy_config_core_v3.CidrRange{
+																AddressPrefix: "10.0.0.0",
+																PrefixLen:     wrapperspb.UInt32(8),
+															},
+														},
+													}},
+												},
+											},
+										},
+									},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			want := &envoy_filter_http_rbac_v3.RBACPerRoute{
+				Rbac: &envoy_filter_http_rbac_v3.RBAC{
+					Rules: &envoy_config_rbac_v3.RBAC{
+						Action: envoy_config_rbac_v3.RBAC_ALLOW,
+						Policies: map[string]*envoy_config_rbac_v3.Policy{
+							"authorization-policy": {
+								Permissions: []*envoy_config_rbac_v3.Permission{
+									{
+										Rule: &envoy_config_rbac_v3.Permission_Any{Any: true},
+									},
+								},
+								Principals: []*envoy_config_rbac_v3.Principal{{
+									Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+										AndIds: &envoy_config_rbac_v3.Principal_Set{
+											Ids: tc.want,
+										},
+									},
+								}},
+							},
+						},
+					},
+				},
+			}
+			protobuf.ExpectEqual(t, want, rbacPolicyConfig(tc.policy, "provider-1"))
+		})
+	}
+}
+
 func TestUpgradeHTTPS(t *testing.T) {
 	got := UpgradeHTTPS()
 	want := &envoy_config_route_v3.Route_Redirect{
@@ -23,21 +23,31 @@ import (
 
 // Secret creates new envoy_transport_socket_tls_v3.Secret from secret.
 func Secret(s *dag.Secret) *envoy_transport_socket_tls_v3.Secret {
+	tlsCertificate := &envoy_transport_socket_tls_v3.TlsCertificate{
+		PrivateKey: &envoy_config_core_v3.DataSource{
+			Specifier: &envoy_config_core_v3.DataSource_InlineBytes{
+				InlineBytes: s.PrivateKey(),
+			},
+		},
+		CertificateChain: &envoy_config_core_v3.DataSource{
+			Specifier: &envoy_config_core_v3.DataSource_InlineBytes{
+				InlineBytes: s.Cert(),
+			},
+		},
+	}
+
+	if staple := s.OCSPStaple(); len(staple) > 0 {
+		tlsCertificate.OcspStaple = &envoy_config_core_v3.DataSource{
+			Specifier: &envoy_config_core_v3.DataSource_InlineBytes{
+				InlineBytes: staple,
+			},
+		}
+	}
+
 	return &envoy_transport_socket_tls_v3.Secret{
 		Name: envoy.Secretname(s),
 		Type: &envoy_transport_socket_tls_v3.Secret_TlsCertificate{
-			TlsCertificate: &envoy_transport_socket_tls_v3.TlsCertificate{
-				PrivateKey: &envoy_config_core_v3.DataSource{
-					Specifier: &envoy_config_core_v3.DataSource_InlineBytes{
-						InlineBytes: s.PrivateKey(),
-					},
-				},
-				CertificateChain: &envoy_config_core_v3.DataSource{
-					Specifier: &envoy_config_core_v3.DataSource_InlineBytes{
-						InlineBytes: s.Cert(),
-					},
-				},
-			},
+			TlsCertificate: tlsCertificate,
 		},
 	}
 }
@@ -63,6 +63,43 @@ func TestSecret(t *testing.T) {
 				},
 			},
 		},
+		"secret with ocsp staple": {
+			secret: &dag.Secret{
+				Object: &core_v1.Secret{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "stapled",
+						Namespace: "default",
+					},
+					Data: map[string][]byte{
+						core_v1.TLSCertKey:       []byte("cert"),
+						core_v1.TLSPrivateKeyKey: []byte("key"),
+						dag.OCSPStapleKey:        []byte("staple"),
+					},
+				},
+			},
+			want: &envoy_transport_socket_tls_v3.Secret{
+				Name: "default/stapled/cd1b506996",
+				Type: &envoy_transport_socket_tls_v3.Secret_TlsCertificate{
+					TlsCertificate: &envoy_transport_socket_tls_v3.TlsCertificate{
+						PrivateKey: &envoy_config_core_v3.DataSource{
+							Specifier: &envoy_config_core_v3.DataSource_InlineBytes{
+								InlineBytes: []byte("key"),
+							},
+						},
+						CertificateChain: &envoy_config_core_v3.DataSource{
+							Specifier: &envoy_config_core_v3.DataSource_InlineBytes{
+								InlineBytes: []byte("cert"),
+							},
+						},
+						OcspStaple: &envoy_config_core_v3.DataSource{
+							Specifier: &envoy_config_core_v3.DataSource_InlineBytes{
+								InlineBytes: []byte("staple"),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -38,6 +38,8 @@ const (
 // The listeners are configured to serve:
 //   - prometheus metrics on /stats (either over HTTP or HTTPS)
 //   - readiness probe on /ready (always over HTTP)
+//   - cluster status on /clusters (only over HTTP), which Contour
+//     reads the error rates of traffic shifts from
 func (e *EnvoyGen) StatsListeners(metrics contour_v1alpha1.MetricsConfig, health contour_v1alpha1.HealthConfig) []*envoy_config_listener_v3.Listener {
 	var listeners []*envoy_config_listener_v3.Listener
 
@@ -69,6 +71,7 @@ func (e *EnvoyGen) StatsListeners(metrics contour_v1alpha1.MetricsConfig, health
 				"/ready",
 				"/stats",
 				"/stats/prometheus",
+				"/clusters",
 			)),
 		}}
 
@@ -78,7 +81,7 @@ func (e *EnvoyGen) StatsListeners(metrics contour_v1alpha1.MetricsConfig, health
 			Name:          "stats",
 			Address:       SocketAddress(metrics.Address, metrics.Port),
 			SocketOptions: NewSocketOptions().TCPKeepalive().Build(),
-			FilterChains:  filterChain("stats", nil, routeForAdminInterface("/stats", "/stats/prometheus")),
+			FilterChains:  filterChain("stats", nil, routeForAdminInterface("/stats", "/stats/prometheus", "/clusters")),
 		}, {
 			Name:          "health",
 			Address:       SocketAddress(health.Address, health.Port),
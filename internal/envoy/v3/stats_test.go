@@ -115,6 +115,34 @@ func TestStatsListeners(t *testing.T) {
 		},
 	}
 
+	clustersRoute := &envoy_config_route_v3.Route{
+		Match: &envoy_config_route_v3.RouteMatch{
+			PathSpecifier: &envoy_config_route_v3.RouteMatch_Path{
+				Path: "/clusters",
+			},
+			Headers: []*envoy_config_route_v3.HeaderMatcher{
+				{
+					Name: ":method",
+					HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+						StringMatch: &envoy_matcher_v3.StringMatcher{
+							IgnoreCase: true,
+							MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
+								Exact: "GET",
+							},
+						},
+					},
+				},
+			},
+		},
+		Action: &envoy_config_route_v3.Route_Route{
+			Route: &envoy_config_route_v3.RouteAction{
+				ClusterSpecifier: &envoy_config_route_v3.RouteAction_Cluster{
+					Cluster: "envoy-admin",
+				},
+			},
+		},
+	}
+
 	envoyGen := NewEnvoyGen(EnvoyGenOpt{
 		XDSClusterName: DefaultXDSClusterName,
 	})
@@ -151,7 +179,7 @@ func TestStatsListeners(t *testing.T) {
 									VirtualHosts: []*envoy_config_route_v3.VirtualHost{{
 										Name:    "backend",
 										Domains: []string{"*"},
-										Routes:  []*envoy_config_route_v3.Route{readyRoute, statsRoute, prometheusStatsRoute},
+										Routes:  []*envoy_config_route_v3.Route{readyRoute, statsRoute, prometheusStatsRoute, clustersRoute},
 									}},
 								},
 							},
@@ -381,7 +409,7 @@ func TestStatsListeners(t *testing.T) {
 									VirtualHosts: []*envoy_config_route_v3.VirtualHost{{
 										Name:    "backend",
 										Domains: []string{"*"},
-										Routes:  []*envoy_config_route_v3.Route{statsRoute, prometheusStatsRoute},
+										Routes:  []*envoy_config_route_v3.Route{statsRoute, prometheusStatsRoute, clustersRoute},
 									}},
 								},
 							},
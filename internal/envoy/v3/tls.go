@@ -15,6 +15,8 @@ package v3
 
 import (
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 )
 
 func ParseTLSVersion(version string) envoy_transport_socket_tls_v3.TlsParameters_TlsProtocol {
@@ -27,3 +29,16 @@ func ParseTLSVersion(version string) envoy_transport_socket_tls_v3.TlsParameters
 		return envoy_transport_socket_tls_v3.TlsParameters_TLS_AUTO
 	}
 }
+
+// OCSPStaplePolicy returns the Envoy OCSP staple policy for the given
+// policy, defaulting to lenient stapling.
+func OCSPStaplePolicy(policy contour_v1alpha1.OCSPStaplePolicy) envoy_transport_socket_tls_v3.DownstreamTlsContext_OcspStaplePolicy {
+	switch policy {
+	case contour_v1alpha1.StrictOCSPStaplePolicy:
+		return envoy_transport_socket_tls_v3.DownstreamTlsContext_STRICT_STAPLING
+	case contour_v1alpha1.MustStapleOCSPStaplePolicy:
+		return envoy_transport_socket_tls_v3.DownstreamTlsContext_MUST_STAPLE
+	default:
+		return envoy_transport_socket_tls_v3.DownstreamTlsContext_LENIENT_STAPLING
+	}
+}
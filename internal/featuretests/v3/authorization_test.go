@@ -19,14 +19,17 @@ import (
 	"testing"
 	"time"
 
+	envoy_config_common_mutation_rules_v3 "github.com/envoyproxy/go-control-plane/envoy/config/common/mutation_rules/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_filter_http_ext_authz_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"google.golang.org/protobuf/types/known/durationpb"
 	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -562,6 +565,250 @@ func authzWithRequestBodyBufferSettings(t *testing.T, rh ResourceEventHandlerWra
 	}).Status(p).IsValid()
 }
 
+func authzHTTPService(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
+	const fqdn = "httpservice.projectcontour.io"
+
+	p := fixture.NewProxy("proxy").
+		WithFQDN(fqdn).
+		WithCertificate("certificate").
+		WithAuthServer(contour_v1.AuthorizationServer{
+			ExtensionServiceRef: contour_v1.ExtensionServiceReference{
+				Namespace: "auth",
+				Name:      "extension",
+			},
+			HTTPService: &contour_v1.AuthorizationHTTPService{
+				PathPrefix: "/verify",
+			},
+			AllowedRequestHeaders:  []string{"Cookie"},
+			AllowedUpstreamHeaders: []string{"X-User"},
+			AllowedClientHeaders:   []string{"Location"},
+		}).
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{Name: "app-server", Port: 80}},
+			}},
+		})
+
+	rh.OnAdd(p)
+
+	exact := func(header string) *envoy_matcher_v3.ListStringMatcher {
+		return &envoy_matcher_v3.ListStringMatcher{
+			Patterns: []*envoy_matcher_v3.StringMatcher{{
+				MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: header},
+				IgnoreCase:   true,
+			}},
+		}
+	}
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			defaultHTTPListener(),
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{
+					filterchaintls(fqdn, featuretests.TLSSecret(t, "certificate", &featuretests.ServerCertificate),
+						authzFilterFor(
+							fqdn,
+							&envoy_filter_http_ext_authz_v3.ExtAuthz{
+								Services: &envoy_filter_http_ext_authz_v3.ExtAuthz_HttpService{
+									HttpService: &envoy_filter_http_ext_authz_v3.HttpService{
+										ServerUri: &envoy_config_core_v3.HttpUri{
+											Uri: "http://extension.auth.extension",
+											HttpUpstreamType: &envoy_config_core_v3.HttpUri_Cluster{
+												Cluster: "extension/auth/extension",
+											},
+											Timeout: durationpb.New(defaultResponseTimeout),
+										},
+										PathPrefix: "/verify",
+										AuthorizationResponse: &envoy_filter_http_ext_authz_v3.AuthorizationResponse{
+											AllowedUpstreamHeaders: exact("X-User"),
+											AllowedClientHeaders:   exact("Location"),
+										},
+									},
+								},
+								AllowedHeaders:         exact("Cookie"),
+								ClearRouteCache:        true,
+								IncludePeerCertificate: true,
+								StatusOnError: &envoy_type_v3.HttpStatus{
+									Code: envoy_type_v3.StatusCode_Forbidden,
+								},
+								TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+							},
+						),
+						nil, "h2", "http/1.1"),
+				},
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+			statsListener()),
+	}).Status(p).IsValid()
+}
+
+func authzHTTP1Protocol(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
+	const fqdn = "http1.projectcontour.io"
+
+	rh.OnAdd(&contour_v1alpha1.ExtensionService{
+		ObjectMeta: fixture.ObjectMeta("auth/http1"),
+		Spec: contour_v1alpha1.ExtensionServiceSpec{
+			Protocol: ptr.To("http/1.1"),
+			Services: []contour_v1alpha1.ExtensionServiceTarget{
+				{Name: "oidc-server", Port: 8081},
+			},
+			TimeoutPolicy: &contour_v1.TimeoutPolicy{
+				Response: defaultResponseTimeout.String(),
+			},
+		},
+	})
+
+	p := fixture.NewProxy("proxy").
+		WithFQDN(fqdn).
+		WithCertificate("certificate").
+		WithAuthServer(contour_v1.AuthorizationServer{
+			ExtensionServiceRef: contour_v1.ExtensionServiceReference{
+				Namespace: "auth",
+				Name:      "http1",
+			},
+		}).
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{Name: "app-server", Port: 80}},
+			}},
+		})
+
+	rh.OnAdd(p)
+
+	// The gRPC authorization API can't be used over HTTP/1.1.
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl:   listenerType,
+		Resources: resources(t, statsListener()),
+	}).Status(p).HasError(contour_v1.ConditionTypeAuthError, "AuthProtocolNotValid",
+		`Spec.Virtualhost.Authorization.ServiceRef extension service uses the "http/1.1" protocol, which is only supported with httpService`)
+
+	p.Spec.VirtualHost.Authorization.HTTPService = &contour_v1.AuthorizationHTTPService{}
+	rh.OnDelete(p)
+	rh.OnAdd(p)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			defaultHTTPListener(),
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{
+					filterchaintls(fqdn, featuretests.TLSSecret(t, "certificate", &featuretests.ServerCertificate),
+						authzFilterFor(
+							fqdn,
+							&envoy_filter_http_ext_authz_v3.ExtAuthz{
+								Services: &envoy_filter_http_ext_authz_v3.ExtAuthz_HttpService{
+									HttpService: &envoy_filter_http_ext_authz_v3.HttpService{
+										ServerUri: &envoy_config_core_v3.HttpUri{
+											Uri: "http://extension.auth.http1",
+											HttpUpstreamType: &envoy_config_core_v3.HttpUri_Cluster{
+												Cluster: "extension/auth/http1",
+											},
+											Timeout: durationpb.New(defaultResponseTimeout),
+										},
+									},
+								},
+								ClearRouteCache:        true,
+								IncludePeerCertificate: true,
+								StatusOnError: &envoy_type_v3.HttpStatus{
+									Code: envoy_type_v3.StatusCode_Forbidden,
+								},
+								TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+							},
+						),
+						nil, "h2", "http/1.1"),
+				},
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+			statsListener()),
+	}).Status(p).IsValid()
+}
+
+func authzForwarding(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
+	const fqdn = "forwarding.projectcontour.io"
+
+	p := fixture.NewProxy("proxy").
+		WithFQDN(fqdn).
+		WithCertificate("certificate").
+		WithAuthServer(contour_v1.AuthorizationServer{
+			ExtensionServiceRef: contour_v1.ExtensionServiceReference{
+				Namespace: "auth",
+				Name:      "extension",
+			},
+			AllowedRequestHeaders:   []string{"Authorization", "Cookie"},
+			AllowedUpstreamHeaders:  []string{"X-User-ID", "X-Groups"},
+			MetadataNamespaces:      []string{"envoy.filters.http.jwt_authn"},
+			RouteMetadataNamespaces: []string{"example.com"},
+		}).
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{Name: "app-server", Port: 80}},
+			}},
+		})
+
+	rh.OnAdd(p)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			defaultHTTPListener(),
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{
+					filterchaintls(fqdn, featuretests.TLSSecret(t, "certificate", &featuretests.ServerCertificate),
+						authzFilterFor(
+							fqdn,
+							&envoy_filter_http_ext_authz_v3.ExtAuthz{
+								Services:        grpcCluster("extension/auth/extension"),
+								ClearRouteCache: true,
+								AllowedHeaders: &envoy_matcher_v3.ListStringMatcher{
+									Patterns: []*envoy_matcher_v3.StringMatcher{{
+										MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "Authorization"},
+										IgnoreCase:   true,
+									}, {
+										MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "Cookie"},
+										IgnoreCase:   true,
+									}},
+								},
+								DecoderHeaderMutationRules: &envoy_config_common_mutation_rules_v3.HeaderMutationRules{
+									AllowExpression: &envoy_matcher_v3.RegexMatcher{
+										Regex: "^(x-user-id|x-groups)$",
+									},
+									DisallowExpression: &envoy_matcher_v3.RegexMatcher{
+										Regex: ".*",
+									},
+								},
+								MetadataContextNamespaces:      []string{"envoy.filters.http.jwt_authn"},
+								RouteMetadataContextNamespaces: []string{"example.com"},
+								IncludePeerCertificate:         true,
+								StatusOnError: &envoy_type_v3.HttpStatus{
+									Code: envoy_type_v3.StatusCode_Forbidden,
+								},
+								TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+							},
+						),
+						nil, "h2", "http/1.1"),
+				},
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+			statsListener()),
+	}).Status(p).IsValid()
+}
+
 func TestAuthorization(t *testing.T) {
 	subtests := map[string]func(*testing.T, ResourceEventHandlerWrapper, *Contour){
 		"MissingExtension":                   authzInvalidReference,
@@ -572,6 +819,9 @@ func TestAuthorization(t *testing.T) {
 		"ResponseTimeout":                    authzResponseTimeout,
 		"InvalidResponseTimeout":             authzInvalidResponseTimeout,
 		"AuthzWithRequestBodyBufferSettings": authzWithRequestBodyBufferSettings,
+		"HTTPService":                        authzHTTPService,
+		"Forwarding":                         authzForwarding,
+		"HTTP1Protocol":                      authzHTTP1Protocol,
 	}
 
 	for n, f := range subtests {
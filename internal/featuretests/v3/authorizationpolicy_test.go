@@ -0,0 +1,231 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_config_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_filter_http_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/protobuf"
+	xdscache_v3 "github.com/projectcontour/contour/internal/xdscache/v3"
+)
+
+func rbacPerRoute(ids ...*envoy_config_rbac_v3.Principal) *envoy_filter_http_rbac_v3.RBACPerRoute {
+	return &envoy_filter_http_rbac_v3.RBACPerRoute{
+		Rbac: &envoy_filter_http_rbac_v3.RBAC{
+			Rules: &envoy_config_rbac_v3.RBAC{
+				Action: envoy_config_rbac_v3.RBAC_ALLOW,
+				Policies: map[string]*envoy_config_rbac_v3.Policy{
+					"authorization-policy": {
+						Permissions: []*envoy_config_rbac_v3.Permission{
+							{
+								Rule: &envoy_config_rbac_v3.Permission_Any{Any: true},
+							},
+						},
+						Principals: []*envoy_config_rbac_v3.Principal{{
+							Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+								AndIds: &envoy_config_rbac_v3.Principal_Set{Ids: ids},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func orPrincipal(ids ...*envoy_config_rbac_v3.Principal) *envoy_config_rbac_v3.Principal {
+	return &envoy_config_rbac_v3.Principal{
+		Identifier: &envoy_config_rbac_v3.Principal_OrIds{
+			OrIds: &envoy_config_rbac_v3.Principal_Set{Ids: ids},
+		},
+	}
+}
+
+func andPrincipal(ids ...*envoy_config_rbac_v3.Principal) *envoy_config_rbac_v3.Principal {
+	return &envoy_config_rbac_v3.Principal{
+		Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+			AndIds: &envoy_config_rbac_v3.Principal_Set{Ids: ids},
+		},
+	}
+}
+
+func TestAuthorizationPolicy(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	envoyGen := envoy_v3.NewEnvoyGen(envoy_v3.EnvoyGenOpt{
+		XDSClusterName: envoy_v3.DefaultXDSClusterName,
+	})
+
+	s1 := fixture.NewService("backend").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)})
+	rh.OnAdd(s1)
+
+	hp1 := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "authorization-policy",
+			Namespace:       s1.Namespace,
+			ResourceVersion: "1",
+			Generation:      1,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "test1.test.com",
+				AuthorizationPolicy: &contour_v1.RBACPolicy{
+					Deny: []contour_v1.RBACRule{{
+						Methods: []string{"DELETE"},
+					}},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+			}, {
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/admin",
+				}},
+				Services: []contour_v1.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+				AuthorizationPolicy: &contour_v1.RBACPolicy{
+					Allow: []contour_v1.RBACRule{{
+						SourceIPs: []contour_v1.IPFilterPolicy{{
+							Source: contour_v1.IPFilterSourceRemote,
+							CIDR:   "10.0.0.0/8",
+						}},
+					}},
+				},
+			}},
+		},
+	}
+	rh.OnAdd(hp1)
+
+	httpListener := defaultHTTPListener()
+	httpListener.FilterChains = envoy_v3.FilterChains(
+		envoyGen.HTTPConnectionManagerBuilder().
+			RouteConfigName(xdscache_v3.ENVOY_HTTP_LISTENER).
+			MetricsPrefix(xdscache_v3.ENVOY_HTTP_LISTENER).
+			AccessLoggers(envoy_v3.FileAccessLogEnvoy(xdscache_v3.DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo)).
+			DefaultFilters().
+			AddFilter(&envoy_filter_network_http_connection_manager_v3.HttpFilter{
+				Name: envoy_v3.AuthorizationPolicyFilterName,
+				ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_rbac_v3.RBAC{}),
+				},
+			}).
+			Get(),
+	)
+
+	c.Request(listenerType, xdscache_v3.ENVOY_HTTP_LISTENER).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t, httpListener),
+		TypeUrl:   listenerType,
+	})
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http", envoy_v3.VirtualHost(hp1.Spec.VirtualHost.Fqdn,
+				&envoy_config_route_v3.Route{
+					Match:  routePrefix("/admin"),
+					Action: routeCluster("default/backend/80/da39a3ee5e"),
+					TypedPerFilterConfig: withFilterConfig(envoy_v3.AuthorizationPolicyFilterName, rbacPerRoute(
+						orPrincipal(andPrincipal(orPrincipal(&envoy_config_rbac_v3.Principal{
+							Identifier: &envoy_config_rbac_v3.Principal_RemoteIp{
+								RemoteIp: &envoy_config_core_v3.CidrRange{
+									AddressPrefix: "10.0.0.0",
+									PrefixLen:     wrapperspb.UInt32(8),
+								},
+							},
+						}))),
+					)),
+				},
+				&envoy_config_route_v3.Route{
+					Match:  routePrefix("/"),
+					Action: routeCluster("default/backend/80/da39a3ee5e"),
+					TypedPerFilterConfig: withFilterConfig(envoy_v3.AuthorizationPolicyFilterName, rbacPerRoute(
+						&envoy_config_rbac_v3.Principal{
+							Identifier: &envoy_config_rbac_v3.Principal_NotId{
+								NotId: orPrincipal(andPrincipal(orPrincipal(&envoy_config_rbac_v3.Principal{
+									Identifier: &envoy_config_rbac_v3.Principal_Header{
+										Header: &envoy_config_route_v3.HeaderMatcher{
+											Name: ":method",
+											HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+												StringMatch: &envoy_matcher_v3.StringMatcher{
+													MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "DELETE"},
+												},
+											},
+										},
+									},
+								}))),
+							},
+						},
+					)),
+				},
+			)),
+		),
+		TypeUrl: routeType,
+	})
+
+	// Removing the policies removes the filter.
+	hp2 := hp1.DeepCopy()
+	hp2.ResourceVersion = "2"
+	hp2.Generation = 2
+	hp2.Spec.VirtualHost.AuthorizationPolicy = nil
+	hp2.Spec.Routes[1].AuthorizationPolicy = nil
+	rh.OnUpdate(hp1, hp2)
+
+	httpListener.FilterChains = envoy_v3.FilterChains(
+		envoyGen.HTTPConnectionManagerBuilder().
+			RouteConfigName(xdscache_v3.ENVOY_HTTP_LISTENER).
+			MetricsPrefix(xdscache_v3.ENVOY_HTTP_LISTENER).
+			AccessLoggers(envoy_v3.FileAccessLogEnvoy(xdscache_v3.DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo)).
+			DefaultFilters().
+			Get(),
+	)
+
+	c.Request(listenerType, xdscache_v3.ENVOY_HTTP_LISTENER).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t, httpListener),
+		TypeUrl:   listenerType,
+	})
+
+	// An invalid rule is reported on the HTTPProxy.
+	hp3 := hp2.DeepCopy()
+	hp3.ResourceVersion = "3"
+	hp3.Generation = 3
+	hp3.Spec.Routes[1].AuthorizationPolicy = &contour_v1.RBACPolicy{
+		Allow: []contour_v1.RBACRule{{}},
+	}
+	rh.OnUpdate(hp2, hp3)
+
+	c.Status(hp3).HasError(contour_v1.ConditionTypeAuthorizationPolicyError, "AuthorizationPolicyNotValid",
+		"Spec.Routes.AuthorizationPolicy.Allow[0] is invalid: at least one condition must be specified")
+}
@@ -26,6 +26,7 @@ import (
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_filter_http_ext_authz_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
 	envoy_filter_http_jwt_authn_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	envoy_filter_http_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_filter_network_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
@@ -426,6 +427,12 @@ func weightedClusters(clusters []weightedCluster) *envoy_config_route_v3.Weighte
 	return &wc
 }
 
+// withLocality sets the locality of the endpoints to the given sub zone.
+func withLocality(endpoints *envoy_config_endpoint_v3.LocalityLbEndpoints, subZone string) *envoy_config_endpoint_v3.LocalityLbEndpoints {
+	endpoints.Locality = &envoy_config_core_v3.Locality{SubZone: subZone}
+	return endpoints
+}
+
 // appendFilterChains is a helper to turn variadic FilterChain arguments into the corresponding  slice.
 func appendFilterChains(chains ...*envoy_config_listener_v3.FilterChain) []*envoy_config_listener_v3.FilterChain {
 	return chains
@@ -438,7 +445,7 @@ func filterchaintls(domain string, secret *core_v1.Secret, filter *envoy_config_
 		envoy_v3.NewEnvoyGen(envoy_v3.EnvoyGenOpt{
 			XDSClusterName: envoy_v3.DefaultXDSClusterName,
 		}).DownstreamTLSContext(
-			&dag.Secret{Object: secret},
+			[]*dag.Secret{{Object: secret}},
 			envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2,
 			envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 			nil,
@@ -455,7 +462,7 @@ func filterchaintlsfallback(fallbackSecret *core_v1.Secret, peerValidationContex
 	})
 	return envoy_v3.FilterChainTLSFallback(
 		envoyGen.DownstreamTLSContext(
-			&dag.Secret{Object: fallbackSecret},
+			[]*dag.Secret{{Object: fallbackSecret}},
 			envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2,
 			envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 			nil,
@@ -480,7 +487,7 @@ func filterchaintlsfallbackauthz(fallbackSecret *core_v1.Secret, authz *envoy_fi
 	})
 	return envoy_v3.FilterChainTLSFallback(
 		envoyGen.DownstreamTLSContext(
-			&dag.Secret{Object: fallbackSecret},
+			[]*dag.Secret{{Object: fallbackSecret}},
 			envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2,
 			envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 			nil,
@@ -605,6 +612,36 @@ func jwtAuthnFilterFor(
 		Get()
 }
 
+// jwtAuthnClaimsFilterFor is like jwtAuthnFilterFor, but also adds
+// the RBAC filter used by routes to match JWT claims.
+func jwtAuthnClaimsFilterFor(
+	vhost string,
+	jwt *envoy_filter_http_jwt_authn_v3.JwtAuthentication,
+) *envoy_config_listener_v3.Filter {
+	envoyGen := envoy_v3.NewEnvoyGen(envoy_v3.EnvoyGenOpt{
+		XDSClusterName: envoy_v3.DefaultXDSClusterName,
+	})
+	return envoyGen.HTTPConnectionManagerBuilder().
+		AddFilter(envoy_v3.FilterMisdirectedRequests(vhost)).
+		DefaultFilters().
+		AddFilter(&envoy_filter_network_http_connection_manager_v3.HttpFilter{
+			Name: envoy_v3.JWTAuthnFilterName,
+			ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+				TypedConfig: protobuf.MustMarshalAny(jwt),
+			},
+		}).
+		AddFilter(&envoy_filter_network_http_connection_manager_v3.HttpFilter{
+			Name: envoy_v3.JWTClaimsFilterName,
+			ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+				TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_rbac_v3.RBAC{}),
+			},
+		}).
+		RouteConfigName(path.Join("https", vhost)).
+		MetricsPrefix(xdscache_v3.ENVOY_HTTPS_LISTENER).
+		AccessLoggers(envoy_v3.FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo)).
+		Get()
+}
+
 func tcpproxy(statPrefix, cluster string) *envoy_config_listener_v3.Filter {
 	return &envoy_config_listener_v3.Filter{
 		Name: wellknown.TCPProxy,
@@ -70,8 +70,8 @@ func extBasic(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
 		Resources: resources(t, &envoy_config_endpoint_v3.ClusterLoadAssignment{
 			ClusterName: "extension/ns/ext",
 			Endpoints: []*envoy_config_endpoint_v3.LocalityLbEndpoints{
-				envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.20", 8081))[0],
-				envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.21", 8082))[0],
+				withLocality(envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.20", 8081))[0], "ns/svc1"),
+				withLocality(envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.21", 8082))[0], "ns/svc2"),
 			},
 		}),
 	})
@@ -99,6 +99,52 @@ func extCleartext(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
 	})
 }
 
+func extHTTP1(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
+	rh.OnAdd(&contour_v1alpha1.ExtensionService{
+		ObjectMeta: fixture.ObjectMeta("ns/ext"),
+		Spec: contour_v1alpha1.ExtensionServiceSpec{
+			Protocol: ptr.To("http/1.1"),
+			Services: []contour_v1alpha1.ExtensionServiceTarget{
+				{Name: "svc1", Port: 8081},
+			},
+		},
+	})
+
+	c.Request(clusterType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: clusterType,
+		Resources: resources(t,
+			cluster("extension/ns/ext", "extension/ns/ext", "extension_ns_ext"),
+		),
+	})
+
+	rh.OnAdd(&contour_v1alpha1.ExtensionService{
+		ObjectMeta: fixture.ObjectMeta("ns/ext"),
+		Spec: contour_v1alpha1.ExtensionServiceSpec{
+			Protocol: ptr.To("tls"),
+			Services: []contour_v1alpha1.ExtensionServiceTarget{
+				{Name: "svc1", Port: 8081},
+			},
+		},
+	})
+
+	// TLS without HTTP/2 ALPN.
+	c.Request(clusterType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: clusterType,
+		Resources: resources(t,
+			DefaultCluster(
+				cluster("extension/ns/ext", "extension/ns/ext", "extension_ns_ext"),
+				&envoy_config_cluster_v3.Cluster{
+					TransportSocket: envoy_v3.UpstreamTLSTransportSocket(
+						&envoy_transport_socket_tls_v3.UpstreamTlsContext{
+							CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{},
+						},
+					),
+				},
+			),
+		),
+	})
+}
+
 func extUpstreamValidation(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
 	ext := &contour_v1alpha1.ExtensionService{
 		ObjectMeta: fixture.ObjectMeta("ns/ext"),
@@ -697,6 +743,7 @@ func TestExtensionService(t *testing.T) {
 	subtests := map[string]func(*testing.T, ResourceEventHandlerWrapper, *Contour){
 		"Basic":                         extBasic,
 		"Cleartext":                     extCleartext,
+		"HTTP1":                         extHTTP1,
 		"UpstreamValidation":            extUpstreamValidation,
 		"ExternalName":                  extExternalName,
 		"IdleConnectionTimeout":         extIdleConnectionTimeout,
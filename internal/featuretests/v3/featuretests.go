@@ -117,7 +117,7 @@ func setup(t *testing.T, opts ...any) (ResourceEventHandlerWrapper, *Contour, fu
 		}
 	}
 
-	snapshotHandler := xdscache_v3.NewSnapshotHandler(resources, log)
+	snapshotHandler := xdscache_v3.NewSnapshotHandler(resources, nil, log)
 	et.SetObserver(snapshotHandler)
 
 	registry := prometheus.NewRegistry()
@@ -21,15 +21,19 @@ import (
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_config_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_filter_http_jwt_authn_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	envoy_filter_http_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_type_metadata_v3 "github.com/envoyproxy/go-control-plane/envoy/type/metadata/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
@@ -99,7 +103,7 @@ func TestJWTVerification(t *testing.T) {
 					{
 						Name:   "provider-1",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -245,7 +249,7 @@ func TestJWTVerification(t *testing.T) {
 					{
 						Name:   "provider-1",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -353,7 +357,7 @@ func TestJWTVerification(t *testing.T) {
 						Name:    "provider-1",
 						Default: true,
 						Issuer:  "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -461,7 +465,7 @@ func TestJWTVerification(t *testing.T) {
 						Name:    "provider-1",
 						Default: true,
 						Issuer:  "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -470,7 +474,7 @@ func TestJWTVerification(t *testing.T) {
 					{
 						Name:   "provider-2",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -586,7 +590,7 @@ func TestJWTVerification(t *testing.T) {
 					{
 						Name:   "provider-1",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com:8443/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -731,7 +735,7 @@ func TestJWTVerification(t *testing.T) {
 					{
 						Name:   "provider-1",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI: "https://jwt.example.com/jwks.json",
 							UpstreamValidation: &contour_v1.UpstreamValidation{
 								CACertificate: "cacert",
@@ -897,7 +901,7 @@ func TestJWTVerification(t *testing.T) {
 					{
 						Name:   "provider-1",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:             "https://jwt.example.com:8443/jwks.json",
 							Timeout:         "7s",
 							CacheDuration:   "30s",
@@ -1042,7 +1046,7 @@ func TestJWTVerification(t *testing.T) {
 					{
 						Name:   "provider-1",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -1246,7 +1250,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 					{
 						Name:   "provider-1",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -1402,7 +1406,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 					{
 						Name:   "provider-1",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -1520,7 +1524,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 						Name:    "provider-1",
 						Default: true,
 						Issuer:  "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -1638,7 +1642,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 						Name:    "provider-1",
 						Default: true,
 						Issuer:  "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -1647,7 +1651,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 					{
 						Name:   "provider-2",
 						Issuer: "issuer.jwt.example.com",
-						RemoteJWKS: contour_v1.RemoteJWKS{
+						RemoteJWKS: &contour_v1.RemoteJWKS{
 							URI:           "https://jwt.example.com/jwks.json",
 							Timeout:       "7s",
 							CacheDuration: "30s",
@@ -1761,3 +1765,359 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 		),
 	})
 }
+
+func TestJWTVerification_LocalJWKS(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	sec1 := featuretests.TLSSecret(t, "secret", &featuretests.ServerCertificate)
+	rh.OnAdd(sec1)
+
+	s1 := fixture.NewService("s1").
+		WithPorts(core_v1.ServicePort{Name: "http", Port: 80})
+	rh.OnAdd(s1)
+
+	jwks1 := &core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "jwks", Namespace: "default", ResourceVersion: "1"},
+		Data:       map[string]string{"jwks.json": `{"keys":[]}`},
+	}
+	rh.OnAdd(jwks1)
+
+	proxy1 := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "jwt.example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: "secret",
+				},
+				JWTProviders: []contour_v1.JWTProvider{
+					{
+						Name:      "provider-1",
+						Default:   true,
+						LocalJWKS: &contour_v1.LocalJWKS{Name: "jwks"},
+						ClaimToHeaders: []contour_v1.JWTClaimToHeader{
+							{HeaderName: "x-jwt-sub", Claim: "sub"},
+						},
+					},
+				},
+			},
+			Routes: []contour_v1.Route{
+				{
+					Conditions: []contour_v1.MatchCondition{{Prefix: "/admin"}},
+					Services: []contour_v1.Service{{
+						Name: s1.Name,
+						Port: 80,
+					}},
+					JWTVerificationPolicy: &contour_v1.JWTVerificationPolicy{
+						RequiredClaims: []contour_v1.JWTRequiredClaim{
+							{Name: "groups", Values: []string{"admin"}},
+						},
+					},
+				},
+				{
+					Services: []contour_v1.Service{{
+						Name: s1.Name,
+						Port: 80,
+					}},
+				},
+			},
+		})
+	rh.OnAdd(proxy1)
+
+	jwtAuthn := func(jwks string) *envoy_filter_http_jwt_authn_v3.JwtAuthentication {
+		return &envoy_filter_http_jwt_authn_v3.JwtAuthentication{
+			Providers: map[string]*envoy_filter_http_jwt_authn_v3.JwtProvider{
+				"provider-1": {
+					JwksSourceSpecifier: &envoy_filter_http_jwt_authn_v3.JwtProvider_LocalJwks{
+						LocalJwks: &envoy_config_core_v3.DataSource{
+							Specifier: &envoy_config_core_v3.DataSource_InlineString{
+								InlineString: jwks,
+							},
+						},
+					},
+					ClaimToHeaders: []*envoy_filter_http_jwt_authn_v3.JwtClaimToHeader{
+						{HeaderName: "x-jwt-sub", ClaimName: "sub"},
+					},
+					PayloadInMetadata: "provider-1",
+				},
+			},
+			RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
+				"provider-1": {
+					RequiresType: &envoy_filter_http_jwt_authn_v3.JwtRequirement_ProviderName{
+						ProviderName: "provider-1",
+					},
+				},
+			},
+		}
+	}
+
+	requirement := protobuf.MustMarshalAny(&envoy_filter_http_jwt_authn_v3.PerRouteConfig{
+		RequirementSpecifier: &envoy_filter_http_jwt_authn_v3.PerRouteConfig_RequirementName{RequirementName: "provider-1"},
+	})
+
+	admin := &envoy_matcher_v3.ValueMatcher{
+		MatchPattern: &envoy_matcher_v3.ValueMatcher_StringMatch{
+			StringMatch: &envoy_matcher_v3.StringMatcher{
+				MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "admin"},
+			},
+		},
+	}
+
+	// The JWKS is inlined, the claim is copied to a header
+	// and the /admin route requires the "groups" claim.
+	c.Request(listenerType, "ingress_https").Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: appendFilterChains(
+					filterchaintls("jwt.example.com", sec1,
+						jwtAuthnClaimsFilterFor("jwt.example.com", jwtAuthn(`{"keys":[]}`)),
+						nil, "h2", "http/1.1"),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+		),
+	}).Request(routeType, "https/jwt.example.com").Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: routeType,
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration(
+				"https/jwt.example.com",
+				envoy_v3.VirtualHost("jwt.example.com",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/admin"),
+						Action: routeCluster("default/s1/80/da39a3ee5e"),
+						TypedPerFilterConfig: map[string]*anypb.Any{
+							envoy_v3.JWTAuthnFilterName: requirement,
+							envoy_v3.JWTClaimsFilterName: protobuf.MustMarshalAny(&envoy_filter_http_rbac_v3.RBACPerRoute{
+								Rbac: &envoy_filter_http_rbac_v3.RBAC{
+									Rules: &envoy_config_rbac_v3.RBAC{
+										Action: envoy_config_rbac_v3.RBAC_ALLOW,
+										Policies: map[string]*envoy_config_rbac_v3.Policy{
+											"jwt-claims": {
+												Permissions: []*envoy_config_rbac_v3.Permission{
+													{Rule: &envoy_config_rbac_v3.Permission_Any{Any: true}},
+												},
+												Principals: []*envoy_config_rbac_v3.Principal{{
+													Identifier: &envoy_config_rbac_v3.Principal_AndIds{
+														AndIds: &envoy_config_rbac_v3.Principal_Set{
+															Ids: []*envoy_config_rbac_v3.Principal{{
+																Identifier: &envoy_config_rbac_v3.Principal_Metadata{
+																	Metadata: &envoy_matcher_v3.MetadataMatcher{
+																		Filter: envoy_v3.JWTAuthnFilterName,
+																		Path: []*envoy_matcher_v3.MetadataMatcher_PathSegment{
+																			{Segment: &envoy_matcher_v3.MetadataMatcher_PathSegment_Key{Key: "provider-1"}},
+																			{Segment: &envoy_matcher_v3.MetadataMatcher_PathSegment_Key{Key: "groups"}},
+																		},
+																		Value: &envoy_matcher_v3.ValueMatcher{
+																			MatchPattern: &envoy_matcher_v3.ValueMatcher_OrMatch{
+																				OrMatch: &envoy_matcher_v3.OrMatcher{
+																					ValueMatchers: []*envoy_matcher_v3.ValueMatcher{
+																						admin,
+																						{
+																							MatchPattern: &envoy_matcher_v3.ValueMatcher_ListMatch{
+																								ListMatch: &envoy_matcher_v3.ListMatcher{
+																									MatchPattern: &envoy_matcher_v3.ListMatcher_OneOf{OneOf: admin},
+																								},
+																							},
+																						},
+																					},
+																				},
+																			},
+																		},
+																	},
+																},
+															}},
+														},
+													},
+												}},
+											},
+										},
+									},
+								},
+							}),
+						},
+					},
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/s1/80/da39a3ee5e"),
+						TypedPerFilterConfig: map[string]*anypb.Any{
+							envoy_v3.JWTAuthnFilterName: requirement,
+						},
+					},
+				),
+			),
+		),
+	}).Status(proxy1).IsValid()
+
+	// Updating the ConfigMap updates the inlined JWKS.
+	jwks2 := jwks1.DeepCopy()
+	jwks2.ResourceVersion = "2"
+	jwks2.Data["jwks.json"] = `{"keys":[{"kty":"oct"}]}`
+	rh.OnUpdate(jwks1, jwks2)
+
+	c.Request(listenerType, "ingress_https").Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: appendFilterChains(
+					filterchaintls("jwt.example.com", sec1,
+						jwtAuthnClaimsFilterFor("jwt.example.com", jwtAuthn(`{"keys":[{"kty":"oct"}]}`)),
+						nil, "h2", "http/1.1"),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+		),
+	})
+
+	// Removing the ConfigMap invalidates the HTTPProxy.
+	rh.OnDelete(jwks2)
+
+	c.Request(listenerType, "ingress_https").Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl:   listenerType,
+		Resources: nil,
+	}).Status(proxy1).HasError(contour_v1.ConditionTypeJWTVerificationError, "LocalJWKSInvalid",
+		`Spec.VirtualHost.JWTProviders.LocalJWKS ConfigMap "jwks" is invalid: ConfigMap not found or is missing the "jwks.json" key`)
+}
+
+func TestJWTVerification_RateLimitClaims(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	sec1 := featuretests.TLSSecret(t, "secret", &featuretests.ServerCertificate)
+	rh.OnAdd(sec1)
+
+	s1 := fixture.NewService("s1").
+		WithPorts(core_v1.ServicePort{Name: "http", Port: 80})
+	rh.OnAdd(s1)
+
+	jwks := &core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "jwks", Namespace: "default"},
+		Data:       map[string]string{"jwks.json": `{"keys":[]}`},
+	}
+	rh.OnAdd(jwks)
+
+	proxy1 := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "jwt.example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: "secret",
+				},
+				JWTProviders: []contour_v1.JWTProvider{
+					{
+						Name:      "provider-1",
+						Default:   true,
+						LocalJWKS: &contour_v1.LocalJWKS{Name: "jwks"},
+					},
+				},
+			},
+			Routes: []contour_v1.Route{
+				{
+					Services: []contour_v1.Service{{
+						Name: s1.Name,
+						Port: 80,
+					}},
+					RateLimitPolicy: &contour_v1.RateLimitPolicy{
+						Global: &contour_v1.GlobalRateLimitPolicy{
+							Descriptors: []contour_v1.RateLimitDescriptor{{
+								Entries: []contour_v1.RateLimitDescriptorEntry{{
+									Metadata: &contour_v1.MetadataDescriptor{
+										Filter:        envoy_v3.JWTAuthnFilterName,
+										Path:          []string{"provider-1", "sub"},
+										DescriptorKey: "subject",
+									},
+								}},
+							}},
+						},
+					},
+				},
+			},
+		})
+	rh.OnAdd(proxy1)
+
+	// The rate limit descriptor reads the "sub" claim, so the
+	// provider writes the JWT payload to the request metadata.
+	c.Request(listenerType, "ingress_https").Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: appendFilterChains(
+					filterchaintls("jwt.example.com", sec1,
+						jwtAuthnClaimsFilterFor("jwt.example.com", &envoy_filter_http_jwt_authn_v3.JwtAuthentication{
+							Providers: map[string]*envoy_filter_http_jwt_authn_v3.JwtProvider{
+								"provider-1": {
+									JwksSourceSpecifier: &envoy_filter_http_jwt_authn_v3.JwtProvider_LocalJwks{
+										LocalJwks: &envoy_config_core_v3.DataSource{
+											Specifier: &envoy_config_core_v3.DataSource_InlineString{
+												InlineString: `{"keys":[]}`,
+											},
+										},
+									},
+									PayloadInMetadata: "provider-1",
+								},
+							},
+							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
+								"provider-1": {
+									RequiresType: &envoy_filter_http_jwt_authn_v3.JwtRequirement_ProviderName{
+										ProviderName: "provider-1",
+									},
+								},
+							},
+						}),
+						nil, "h2", "http/1.1"),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+		),
+	}).Request(routeType, "https/jwt.example.com").Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: routeType,
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration(
+				"https/jwt.example.com",
+				envoy_v3.VirtualHost("jwt.example.com",
+					&envoy_config_route_v3.Route{
+						Match: routePrefix("/"),
+						Action: routeCluster("default/s1/80/da39a3ee5e", func(r *envoy_config_route_v3.Route_Route) {
+							r.Route.RateLimits = []*envoy_config_route_v3.RateLimit{{
+								Actions: []*envoy_config_route_v3.RateLimit_Action{{
+									ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_Metadata{
+										Metadata: &envoy_config_route_v3.RateLimit_Action_MetaData{
+											DescriptorKey: "subject",
+											MetadataKey: &envoy_type_metadata_v3.MetadataKey{
+												Key: envoy_v3.JWTAuthnFilterName,
+												Path: []*envoy_type_metadata_v3.MetadataKey_PathSegment{
+													{Segment: &envoy_type_metadata_v3.MetadataKey_PathSegment_Key{Key: "provider-1"}},
+													{Segment: &envoy_type_metadata_v3.MetadataKey_PathSegment_Key{Key: "sub"}},
+												},
+											},
+										},
+									},
+								}},
+							}}
+						}),
+						TypedPerFilterConfig: map[string]*anypb.Any{
+							envoy_v3.JWTAuthnFilterName: protobuf.MustMarshalAny(&envoy_filter_http_jwt_authn_v3.PerRouteConfig{
+								RequirementSpecifier: &envoy_filter_http_jwt_authn_v3.PerRouteConfig_RequirementName{RequirementName: "provider-1"},
+							}),
+						},
+					},
+				),
+			),
+		),
+	}).Status(proxy1).IsValid()
+}
@@ -20,6 +20,7 @@ import (
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/tsaarni/certyaml"
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -402,7 +403,7 @@ func TestHTTPProxyTLSListener(t *testing.T) {
 			envoy_v3.FilterChainTLS(
 				"kuard.example.com",
 				envoyGen.DownstreamTLSContext(
-					&dag.Secret{Object: secret1},
+					[]*dag.Secret{{Object: secret1}},
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 					nil,
@@ -480,7 +481,7 @@ func TestTLSListenerCipherSuites(t *testing.T) {
 			envoy_v3.FilterChainTLS(
 				"kuard.example.com",
 				envoyGen.DownstreamTLSContext(
-					&dag.Secret{Object: secret1},
+					[]*dag.Secret{{Object: secret1}},
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2,
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2,
 					[]string{"ECDHE-ECDSA-AES256-GCM-SHA384"},
@@ -979,6 +980,103 @@ func TestHTTPProxyHTTPS(t *testing.T) {
 	})
 }
 
+func TestHTTPProxyHTTPSMultipleCertificates(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rsaServerCertificate := certyaml.Certificate{
+		Issuer:          &featuretests.CACertificate,
+		Subject:         "CN=www.example.com",
+		SubjectAltNames: []string{"DNS:www.example.com"},
+		KeyType:         certyaml.KeyTypeRSA,
+	}
+
+	s1 := featuretests.TLSSecret(t, "secret-ecdsa", &featuretests.ServerCertificate)
+	s2 := featuretests.TLSSecret(t, "secret-rsa", &rsaServerCertificate)
+
+	p1 := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName:            "secret-ecdsa",
+					AdditionalSecretNames: []string{"secret-rsa"},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	rh.OnAdd(s1)
+	rh.OnAdd(s2)
+	rh.OnAdd(fixture.NewService("kuard").
+		WithPorts(core_v1.ServicePort{Name: "http", Port: 8080}))
+	rh.OnAdd(p1)
+
+	ingressHTTPS := &envoy_config_listener_v3.Listener{
+		Name:    "ingress_https",
+		Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+		ListenerFilters: envoy_v3.ListenerFilters(
+			envoy_v3.TLSInspector(),
+		),
+		FilterChains: []*envoy_config_listener_v3.FilterChain{
+			envoy_v3.FilterChainTLS(
+				"example.com",
+				envoy_v3.NewEnvoyGen(envoy_v3.EnvoyGenOpt{
+					XDSClusterName: envoy_v3.DefaultXDSClusterName,
+				}).DownstreamTLSContext(
+					[]*dag.Secret{{Object: s1}, {Object: s2}},
+					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2,
+					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
+					nil,
+					nil,
+					"h2", "http/1.1"),
+				envoy_v3.Filters(httpsFilterFor("example.com")),
+			),
+		},
+		SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+	}
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			defaultHTTPListener(),
+			ingressHTTPS,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	// Both secrets are served over SDS.
+	c.Request(secretType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.Secret(&dag.Secret{Object: s1}),
+			envoy_v3.Secret(&dag.Secret{Object: s2}),
+		),
+		TypeUrl: secretType,
+	})
+
+	// Removing the additional secret invalidates the vhost.
+	rh.OnDelete(s2)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+}
+
 func TestHTTPProxyTLSVersion(t *testing.T) {
 	rh, c, done := setup(t, func(conf *xdscache_v3.ListenerConfig) {
 		conf.MinimumTLSVersion = "1.2"
@@ -1030,7 +1128,7 @@ func TestHTTPProxyTLSVersion(t *testing.T) {
 			envoy_v3.FilterChainTLS(
 				"kuard.example.com",
 				envoyGen.DownstreamTLSContext(
-					&dag.Secret{Object: secret1},
+					[]*dag.Secret{{Object: secret1}},
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2,
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 					nil,
@@ -1088,7 +1186,7 @@ func TestHTTPProxyTLSVersion(t *testing.T) {
 			envoy_v3.FilterChainTLS(
 				"kuard.example.com",
 				envoyGen.DownstreamTLSContext(
-					&dag.Secret{Object: secret1},
+					[]*dag.Secret{{Object: secret1}},
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 					nil,
@@ -1627,7 +1725,7 @@ func TestSocketOptions(t *testing.T) {
 					envoy_v3.FilterChainTLS(
 						"kuard.example.com",
 						envoyGen.DownstreamTLSContext(
-							&dag.Secret{Object: secret1},
+							[]*dag.Secret{{Object: secret1}},
 							envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2,
 							envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 							nil,
@@ -15,18 +15,26 @@ package v3
 
 import (
 	"testing"
+	"time"
 
+	envoy_config_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_filter_network_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/featuretests"
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/protobuf"
 )
 
 func TestTCPProxy(t *testing.T) {
@@ -897,3 +905,103 @@ func TestTCPProxyInvalidLoadBalancerPolicy(t *testing.T) {
 		TypeUrl: clusterType,
 	})
 }
+
+func TestTCPProxyIdleTimeoutAndAccessLog(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	svc := fixture.NewService("backend").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)})
+
+	rh.OnAdd(svc)
+
+	hp1 := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "simple",
+			Namespace:       svc.Namespace,
+			ResourceVersion: "1",
+			Generation:      1,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "kuard-tcp.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				IdleTimeout:        "10m",
+				MaxConnectAttempts: 3,
+				AccessLog: &contour_v1.TCPProxyAccessLog{
+					Format: "%UPSTREAM_HOST%\n",
+				},
+				Services: []contour_v1.Service{{
+					Name: svc.Name,
+					Port: 80,
+				}},
+			},
+		},
+	}
+	rh.OnAdd(hp1)
+
+	tcpProxyFilter := func(accessLog []*envoy_config_accesslog_v3.AccessLog) *envoy_config_listener_v3.Filter {
+		return &envoy_config_listener_v3.Filter{
+			Name: wellknown.TCPProxy,
+			ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+				TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_tcp_proxy_v3.TcpProxy{
+					StatPrefix: "ingress_https",
+					ClusterSpecifier: &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
+						Cluster: "default/backend/80/da39a3ee5e",
+					},
+					AccessLog:          accessLog,
+					IdleTimeout:        durationpb.New(10 * time.Minute),
+					MaxConnectAttempts: wrapperspb.UInt32(3),
+				}),
+			},
+		}
+	}
+
+	httpsListener := func(filter *envoy_config_listener_v3.Filter) *envoy_config_listener_v3.Listener {
+		return &envoy_config_listener_v3.Listener{
+			Name:    "ingress_https",
+			Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+			FilterChains: []*envoy_config_listener_v3.FilterChain{{
+				Filters: envoy_v3.Filters(filter),
+				FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+					ServerNames: []string{"kuard-tcp.example.com"},
+				},
+			}},
+			ListenerFilters: envoy_v3.ListenerFilters(
+				envoy_v3.TLSInspector(),
+			),
+			SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+		}
+	}
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			httpsListener(tcpProxyFilter(
+				envoy_v3.FileAccessLogEnvoy("/dev/stdout", "%UPSTREAM_HOST%\n", nil, contour_v1alpha1.LogLevelInfo),
+			)),
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	// Disabling the access log removes it from the tcp_proxy filter.
+	hp2 := hp1.DeepCopy()
+	hp2.ResourceVersion = "2"
+	hp2.Generation = 2
+	hp2.Spec.TCPProxy.AccessLog = &contour_v1.TCPProxyAccessLog{
+		Disabled: true,
+	}
+	rh.OnUpdate(hp1, hp2)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			httpsListener(tcpProxyFilter(nil)),
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+}
@@ -20,6 +20,7 @@ import (
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
@@ -282,3 +283,135 @@ func TestTLSCertificateDelegation(t *testing.T) {
 		TypeUrl: listenerType,
 	})
 }
+
+func TestTLSCertificateDelegationSelectors(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	sec1 := featuretests.TLSSecret(t, "secret/wildcard-example-com", &featuretests.ServerCertificate)
+	rh.OnAdd(sec1)
+
+	s1 := fixture.NewService("kuard").
+		WithPorts(core_v1.ServicePort{Port: 8080})
+	rh.OnAdd(s1)
+
+	ns1 := &core_v1.Namespace{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            s1.Namespace,
+			ResourceVersion: "1",
+		},
+	}
+	rh.OnAdd(ns1)
+
+	// add an httpproxy in a different namespace mentioning secret/wildcard-example-com.
+	p1 := &contour_v1.HTTPProxy{
+		ObjectMeta: fixture.ObjectMeta("simple"),
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: sec1.Namespace + "/" + sec1.Name,
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: matchconditions(prefixMatchCondition("/")),
+				Services: []contour_v1.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+	rh.OnAdd(p1)
+
+	// t1 delegates the wildcard secrets to tenant namespaces.
+	t1 := &contour_v1.TLSCertificateDelegation{
+		ObjectMeta: fixture.ObjectMeta("secret/delegation"),
+		Spec: contour_v1.TLSCertificateDelegationSpec{
+			Delegations: []contour_v1.CertificateDelegation{{
+				SecretName: "wildcard-*",
+				TargetNamespaceSelector: &meta_v1.LabelSelector{
+					MatchLabels: map[string]string{"tenant": "true"},
+				},
+			}},
+		},
+	}
+	rh.OnAdd(t1)
+
+	// the namespace of the httpproxy is not a tenant namespace.
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	// labelling the namespace delegates the secret to it.
+	ns2 := ns1.DeepCopy()
+	ns2.ResourceVersion = "2"
+	ns2.Labels = map[string]string{"tenant": "true"}
+	rh.OnUpdate(ns1, ns2)
+
+	ingressHTTPS := &envoy_config_listener_v3.Listener{
+		Name:    "ingress_https",
+		Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+		ListenerFilters: envoy_v3.ListenerFilters(
+			envoy_v3.TLSInspector(),
+		),
+		FilterChains: appendFilterChains(
+			filterchaintls("example.com", sec1,
+				httpsFilterFor("example.com"),
+				nil, "h2", "http/1.1"),
+		),
+		SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+	}
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			defaultHTTPListener(),
+			ingressHTTPS,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	// t2 delegates the secrets labelled as delegated to all namespaces.
+	t2 := &contour_v1.TLSCertificateDelegation{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:       t1.Name,
+			Namespace:  t1.Namespace,
+			Generation: 2,
+		},
+		Spec: contour_v1.TLSCertificateDelegationSpec{
+			Delegations: []contour_v1.CertificateDelegation{{
+				SecretSelector: &meta_v1.LabelSelector{
+					MatchLabels: map[string]string{"delegated": "true"},
+				},
+				TargetNamespaces: []string{"*"},
+			}},
+		},
+	}
+	rh.OnUpdate(t1, t2)
+
+	// the secret is not labelled as delegated.
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	sec2 := sec1.DeepCopy()
+	sec2.ResourceVersion = "2"
+	sec2.Labels = map[string]string{"delegated": "true"}
+	rh.OnUpdate(sec1, sec2)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			defaultHTTPListener(),
+			ingressHTTPS,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+}
@@ -129,7 +129,7 @@ func TestTLSProtocolVersion(t *testing.T) {
 			envoy_v3.FilterChainTLS(
 				"kuard.example.com",
 				envoyGen.DownstreamTLSContext(
-					&dag.Secret{Object: sec1},
+					[]*dag.Secret{{Object: sec1}},
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 					envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
 					nil,
@@ -0,0 +1,138 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_config_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	core_v1 "k8s.io/api/core/v1"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/featuretests"
+	"github.com/projectcontour/contour/internal/fixture"
+)
+
+func TestTrafficShift(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("kuard").WithPorts(core_v1.ServicePort{Port: 80}))
+	rh.OnAdd(fixture.NewService("kuard-canary").WithPorts(core_v1.ServicePort{Port: 8080}))
+	rh.OnAdd(featuretests.Endpoints("default", "kuard", core_v1.EndpointSubset{
+		Addresses: featuretests.Addresses("10.0.0.1"),
+		Ports:     featuretests.Ports(featuretests.Port("", 80)),
+	}))
+	rh.OnAdd(featuretests.Endpoints("default", "kuard-canary", core_v1.EndpointSubset{
+		Addresses: featuretests.Addresses("10.0.0.2"),
+		Ports:     featuretests.Ports(featuretests.Port("", 8080)),
+	}))
+
+	policy := &contour_v1.TrafficShiftPolicy{
+		Stable:   "kuard",
+		Canary:   "kuard-canary",
+		Steps:    []uint32{10, 50},
+		Interval: "1m",
+	}
+
+	// The status of the shift is all that is left of its progress
+	// when Contour restarts, so the DAG weights the services by it.
+	proxy1 := fixture.NewProxy("kuard").
+		WithFQDN("kuard.example.com").
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{
+					{Name: "kuard", Port: 80},
+					{Name: "kuard-canary", Port: 8080},
+				},
+				TrafficShiftPolicy: policy,
+			}},
+		})
+	proxy1.Status.TrafficShifts = []contour_v1.TrafficShiftStatus{{
+		Stable:     "kuard",
+		Canary:     "kuard-canary",
+		PolicyHash: policy.Hash(),
+		Step:       0,
+		Weight:     10,
+		Phase:      contour_v1.TrafficShiftPhaseProgressing,
+	}}
+	rh.OnAdd(proxy1)
+
+	shiftCluster := DefaultCluster(
+		cluster("default/kuard/80/f769ec0e84", "trafficshift/default/kuard/kuard/kuard-canary/80/8080", "default_kuard_80"),
+		&envoy_config_cluster_v3.Cluster{
+			CommonLbConfig: &envoy_config_cluster_v3.Cluster_CommonLbConfig{
+				HealthyPanicThreshold: envoy_v3.ClusterCommonLBConfig().HealthyPanicThreshold,
+				LocalityConfigSpecifier: &envoy_config_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+					LocalityWeightedLbConfig: &envoy_config_cluster_v3.Cluster_CommonLbConfig_LocalityWeightedLbConfig{},
+				},
+			},
+		},
+	)
+
+	c.Request(clusterType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t, shiftCluster),
+		TypeUrl:   clusterType,
+	})
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("kuard.example.com",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/kuard/80/f769ec0e84"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	shiftEndpoints := func(stableWeight, canaryWeight uint32) *envoy_config_endpoint_v3.ClusterLoadAssignment {
+		return &envoy_config_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "trafficshift/default/kuard/kuard/kuard-canary/80/8080",
+			Endpoints: []*envoy_config_endpoint_v3.LocalityLbEndpoints{
+				withLocality(envoy_v3.WeightedEndpoints(stableWeight, envoy_v3.SocketAddress("10.0.0.1", 80))[0], "default/kuard"),
+				withLocality(envoy_v3.WeightedEndpoints(canaryWeight, envoy_v3.SocketAddress("10.0.0.2", 8080))[0], "default/kuard-canary"),
+			},
+		}
+	}
+
+	c.Request(endpointType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t, shiftEndpoints(90, 10)),
+		TypeUrl:   endpointType,
+	})
+
+	// Moving to the next step only reweights the endpoints.
+	proxy2 := proxy1.DeepCopy()
+	proxy2.ResourceVersion = "2"
+	proxy2.Status.TrafficShifts[0].Step = 1
+	proxy2.Status.TrafficShifts[0].Weight = 50
+	rh.OnUpdate(proxy1, proxy2)
+
+	c.Request(endpointType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t, shiftEndpoints(50, 50)),
+		TypeUrl:   endpointType,
+	})
+
+	c.Request(clusterType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t, shiftCluster),
+		TypeUrl:   clusterType,
+	})
+}
@@ -17,8 +17,12 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 )
 
+// KindListenerSet is the kind of the experimental ListenerSet.
+const KindListenerSet = "XListenerSet"
+
 func CertificateRef(name, namespace string) gatewayapi_v1.SecretObjectReference {
 	secretRef := gatewayapi_v1.SecretObjectReference{
 		Group: ptr.To(gatewayapi_v1.Group("")),
@@ -61,6 +65,20 @@ func GatewayListenerParentRef(namespace, name, listener string, port uint16) gat
 	return parentRef
 }
 
+func ListenerSetParentRef(namespace, name string) gatewayapi_v1.ParentReference {
+	parentRef := gatewayapi_v1.ParentReference{
+		Group: ptr.To(gatewayapi_v1.Group(gatewayapi_x_v1alpha1.GroupName)),
+		Kind:  ptr.To(gatewayapi_v1.Kind(KindListenerSet)),
+		Name:  gatewayapi_v1.ObjectName(name),
+	}
+
+	if namespace != "" {
+		parentRef.Namespace = ptr.To(gatewayapi_v1.Namespace(namespace))
+	}
+
+	return parentRef
+}
+
 func ServiceBackendObjectRef(name string, port uint16) gatewayapi_v1.BackendObjectReference {
 	return gatewayapi_v1.BackendObjectReference{
 		Group: ptr.To(gatewayapi_v1.Group("")),
@@ -202,3 +220,43 @@ func IsRefToGateway(parentRef gatewayapi_v1.ParentReference, gateway types.Names
 
 	return string(parentRef.Name) == gateway.Name
 }
+
+// IsRefToListenerSet returns whether the provided parent ref of a route in
+// routeNamespace is a reference to a ListenerSet with the given
+// namespace/name, irrespective of whether a section/listener name has
+// been specified.
+func IsRefToListenerSet(parentRef gatewayapi_v1.ParentReference, routeNamespace string, listenerSet types.NamespacedName) bool {
+	if parentRef.Group == nil || string(*parentRef.Group) != gatewayapi_x_v1alpha1.GroupName {
+		return false
+	}
+
+	if parentRef.Kind == nil || string(*parentRef.Kind) != KindListenerSet {
+		return false
+	}
+
+	if string(ptr.Deref(parentRef.Namespace, gatewayapi_v1.Namespace(routeNamespace))) != listenerSet.Namespace {
+		return false
+	}
+
+	return string(parentRef.Name) == listenerSet.Name
+}
+
+// IsListenerSetOfGateway returns whether the parent ref of the provided
+// ListenerSet is a reference to a Gateway with the given namespace/name.
+func IsListenerSetOfGateway(listenerSet *gatewayapi_x_v1alpha1.XListenerSet, gateway types.NamespacedName) bool {
+	parentRef := listenerSet.Spec.ParentRef
+
+	if parentRef.Group != nil && string(*parentRef.Group) != gatewayapi_v1.GroupName {
+		return false
+	}
+
+	if parentRef.Kind != nil && string(*parentRef.Kind) != "Gateway" {
+		return false
+	}
+
+	if string(ptr.Deref(parentRef.Namespace, gatewayapi_v1.Namespace(listenerSet.Namespace))) != gateway.Namespace {
+		return false
+	}
+
+	return string(parentRef.Name) == gateway.Name
+}
@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/ptr"
@@ -44,6 +45,28 @@ type ValidateListenersResult struct {
 	// InvalidListenerConditions is a map from Gateway Listener name
 	// to a condition to set, if the Listener is invalid.
 	InvalidListenerConditions map[gatewayapi_v1.SectionName]meta_v1.Condition
+
+	// ListenerSets holds the results for the Listeners of
+	// each ListenerSet, keyed by ListenerSet namespace/name.
+	ListenerSets map[types.NamespacedName]*ListenerSetResult
+}
+
+// ListenerSetResult holds the results for the Listeners of a ListenerSet.
+type ListenerSetResult struct {
+	// ListenerNames is a map from ListenerSet Listener name
+	// to DAG/Envoy Listener name.
+	ListenerNames map[string]string
+
+	// InvalidListenerConditions is a map from ListenerSet Listener
+	// name to a condition to set, if the Listener is invalid.
+	InvalidListenerConditions map[gatewayapi_v1.SectionName]meta_v1.Condition
+}
+
+// ListenerSetListeners holds the Listeners of a ListenerSet
+// attached to a Gateway.
+type ListenerSetListeners struct {
+	ListenerSet types.NamespacedName
+	Listeners   []gatewayapi_v1.Listener
 }
 
 type ListenerPort struct {
@@ -72,7 +95,11 @@ func conflictedCondition(reason gatewayapi_v1.ListenerConditionReason, msg strin
 // It returns a Listener name map, the ports to use, and conditions for all invalid listeners.
 // If a listener is not in the "InvalidListenerConditions" map, it is assumed to be valid according
 // to the above rules.
-func ValidateListeners(listeners []gatewayapi_v1.Listener) ValidateListenersResult {
+//
+// The Listeners of the given ListenerSets are validated after, and so
+// lose any conflict with, the Gateway's listeners and the Listeners of
+// the ListenerSets before them. Their results are in "ListenerSets".
+func ValidateListeners(listeners []gatewayapi_v1.Listener, listenerSets ...ListenerSetListeners) ValidateListenersResult {
 	// TLS-based protocols that can all exist on the same port.
 	compatibleTLSProtocols := sets.New(
 		gatewayapi_v1.HTTPSProtocolType,
@@ -85,11 +112,49 @@ func ValidateListeners(listeners []gatewayapi_v1.Listener) ValidateListenersResu
 		InvalidListenerConditions: map[gatewayapi_v1.SectionName]meta_v1.Condition{},
 	}
 
-	for i, listener := range listeners {
+	// Merge the Listeners of the Gateway and of its ListenerSets, each
+	// with the maps of its owner's results.
+	type ownedListener struct {
+		gatewayapi_v1.Listener
+
+		listenerNames             map[string]string
+		invalidListenerConditions map[gatewayapi_v1.SectionName]meta_v1.Condition
+	}
+
+	var merged []ownedListener
+	for _, listener := range listeners {
+		merged = append(merged, ownedListener{
+			Listener:                  listener,
+			listenerNames:             result.ListenerNames,
+			invalidListenerConditions: result.InvalidListenerConditions,
+		})
+	}
+
+	for _, listenerSet := range listenerSets {
+		if result.ListenerSets == nil {
+			result.ListenerSets = map[types.NamespacedName]*ListenerSetResult{}
+		}
+
+		listenerSetResult := &ListenerSetResult{
+			ListenerNames:             map[string]string{},
+			InvalidListenerConditions: map[gatewayapi_v1.SectionName]meta_v1.Condition{},
+		}
+		result.ListenerSets[listenerSet.ListenerSet] = listenerSetResult
+
+		for _, listener := range listenerSet.Listeners {
+			merged = append(merged, ownedListener{
+				Listener:                  listener,
+				listenerNames:             listenerSetResult.ListenerNames,
+				invalidListenerConditions: listenerSetResult.InvalidListenerConditions,
+			})
+		}
+	}
+
+	for i, listener := range merged {
 		// Check for a valid hostname.
 		if hostname := ptr.Deref(listener.Hostname, ""); len(hostname) > 0 {
 			if err := IsValidHostname(string(hostname)); err != nil {
-				result.InvalidListenerConditions[listener.Name] = meta_v1.Condition{
+				listener.invalidListenerConditions[listener.Name] = meta_v1.Condition{
 					Type:    string(gatewayapi_v1.ListenerConditionProgrammed),
 					Status:  meta_v1.ConditionFalse,
 					Reason:  string(gatewayapi_v1.ListenerReasonInvalid),
@@ -103,7 +168,7 @@ func ValidateListeners(listeners []gatewayapi_v1.Listener) ValidateListenersResu
 		switch listener.Protocol {
 		case gatewayapi_v1.HTTPProtocolType, gatewayapi_v1.HTTPSProtocolType, gatewayapi_v1.TLSProtocolType, gatewayapi_v1.TCPProtocolType, ContourHTTPSProtocolType:
 		default:
-			result.InvalidListenerConditions[listener.Name] = meta_v1.Condition{
+			listener.invalidListenerConditions[listener.Name] = meta_v1.Condition{
 				Type:    string(gatewayapi_v1.ListenerConditionAccepted),
 				Status:  meta_v1.ConditionFalse,
 				Reason:  string(gatewayapi_v1.ListenerReasonUnsupportedProtocol),
@@ -118,14 +183,14 @@ func ValidateListeners(listeners []gatewayapi_v1.Listener) ValidateListenersResu
 			// order to take precedence, i.e. to be accepted and
 			// programmed, when there is a conflict.
 			for j := range i {
-				otherListener := listeners[j]
+				otherListener := merged[j]
 
 				if listener.Port != otherListener.Port {
 					// Port ranges 57536-58558 and 58559-59581 both map to container ports
 					// 1024-2046, since we can't listen on ports 1-1023 in the Envoy container.
 					// If there are conflicting container ports, the listener can't be accepted.
 					if toContainerPort(listener.Port) == toContainerPort(otherListener.Port) {
-						result.InvalidListenerConditions[listener.Name] = meta_v1.Condition{
+						listener.invalidListenerConditions[listener.Name] = meta_v1.Condition{
 							Type:    string(gatewayapi_v1.ListenerConditionAccepted),
 							Status:  meta_v1.ConditionFalse,
 							Reason:  string(gatewayapi_v1.ListenerReasonPortUnavailable),
@@ -142,24 +207,24 @@ func ValidateListeners(listeners []gatewayapi_v1.Listener) ValidateListenersResu
 				switch {
 				case listener.Protocol == gatewayapi_v1.HTTPProtocolType:
 					if otherListener.Protocol != gatewayapi_v1.HTTPProtocolType {
-						result.InvalidListenerConditions[listener.Name] = conflictedCondition(gatewayapi_v1.ListenerReasonProtocolConflict, "All Listener protocols for a given port must be compatible")
+						listener.invalidListenerConditions[listener.Name] = conflictedCondition(gatewayapi_v1.ListenerReasonProtocolConflict, "All Listener protocols for a given port must be compatible")
 						return true
 					}
 				case compatibleTLSProtocols.Has(listener.Protocol):
 					if !compatibleTLSProtocols.Has(otherListener.Protocol) {
-						result.InvalidListenerConditions[listener.Name] = conflictedCondition(gatewayapi_v1.ListenerReasonProtocolConflict, "All Listener protocols for a given port must be compatible")
+						listener.invalidListenerConditions[listener.Name] = conflictedCondition(gatewayapi_v1.ListenerReasonProtocolConflict, "All Listener protocols for a given port must be compatible")
 						return true
 					}
 				case listener.Protocol == gatewayapi_v1.TCPProtocolType:
 					if otherListener.Protocol != gatewayapi_v1.TCPProtocolType {
-						result.InvalidListenerConditions[listener.Name] = conflictedCondition(gatewayapi_v1.ListenerReasonProtocolConflict, "All Listener protocols for a given port must be compatible")
+						listener.invalidListenerConditions[listener.Name] = conflictedCondition(gatewayapi_v1.ListenerReasonProtocolConflict, "All Listener protocols for a given port must be compatible")
 						return true
 					}
 				}
 
 				// Hostname conflict
 				if ptr.Deref(listener.Hostname, "") == ptr.Deref(otherListener.Hostname, "") {
-					result.InvalidListenerConditions[listener.Name] = conflictedCondition(gatewayapi_v1.ListenerReasonHostnameConflict, "All Listener hostnames for a given port must be unique")
+					listener.invalidListenerConditions[listener.Name] = conflictedCondition(gatewayapi_v1.ListenerReasonHostnameConflict, "All Listener hostnames for a given port must be unique")
 					return true
 				}
 			}
@@ -183,7 +248,7 @@ func ValidateListeners(listeners []gatewayapi_v1.Listener) ValidateListenersResu
 		}
 		envoyListenerName := fmt.Sprintf("%s-%d", protocol, listener.Port)
 
-		result.ListenerNames[string(listener.Name)] = envoyListenerName
+		listener.listenerNames[string(listener.Name)] = envoyListenerName
 
 		// Add the port to the list if it hasn't been added already.
 		found := false
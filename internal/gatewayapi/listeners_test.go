@@ -18,6 +18,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
 )
@@ -608,4 +609,86 @@ func TestValidateListeners(t *testing.T) {
 			},
 		}, res.InvalidListenerConditions)
 	})
+
+	t.Run("ListenerSet listeners are merged with, and lose conflicts to, the Gateway's listeners", func(t *testing.T) {
+		listeners := []gatewayapi_v1.Listener{
+			{
+				Name:     "http",
+				Protocol: gatewayapi_v1.HTTPProtocolType,
+				Port:     80,
+				Hostname: ptr.To(gatewayapi_v1.Hostname("local.projectcontour.io")),
+			},
+		}
+
+		listenerSet1 := types.NamespacedName{Namespace: "projectcontour", Name: "listenerset-1"}
+		listenerSet2 := types.NamespacedName{Namespace: "projectcontour", Name: "listenerset-2"}
+
+		res := ValidateListeners(listeners,
+			ListenerSetListeners{
+				ListenerSet: listenerSet1,
+				Listeners: []gatewayapi_v1.Listener{
+					{
+						// Same name as the Gateway's listener, but
+						// names are scoped to their owner.
+						Name:     "http",
+						Protocol: gatewayapi_v1.HTTPProtocolType,
+						Port:     80,
+						Hostname: ptr.To(gatewayapi_v1.Hostname("other.projectcontour.io")),
+					},
+					{
+						Name:     "http-conflict",
+						Protocol: gatewayapi_v1.HTTPProtocolType,
+						Port:     80,
+						Hostname: ptr.To(gatewayapi_v1.Hostname("local.projectcontour.io")),
+					},
+					{
+						Name:     "tls",
+						Protocol: gatewayapi_v1.TLSProtocolType,
+						Port:     443,
+					},
+				},
+			},
+			ListenerSetListeners{
+				ListenerSet: listenerSet2,
+				Listeners: []gatewayapi_v1.Listener{
+					{
+						Name:     "tls",
+						Protocol: gatewayapi_v1.TLSProtocolType,
+						Port:     443,
+					},
+					{
+						Name:     "tcp",
+						Protocol: gatewayapi_v1.TCPProtocolType,
+						Port:     80,
+					},
+				},
+			},
+		)
+
+		assert.ElementsMatch(t, res.Ports, []ListenerPort{
+			{Name: "http-80", Port: 80, ContainerPort: 8080, Protocol: "http"},
+			{Name: "https-443", Port: 443, ContainerPort: 8443, Protocol: "https"},
+		})
+		assert.Equal(t, map[string]string{"http": "http-80"}, res.ListenerNames)
+		assert.Empty(t, res.InvalidListenerConditions)
+
+		assert.Equal(t, map[types.NamespacedName]*ListenerSetResult{
+			listenerSet1: {
+				ListenerNames: map[string]string{
+					"http": "http-80",
+					"tls":  "https-443",
+				},
+				InvalidListenerConditions: map[gatewayapi_v1.SectionName]meta_v1.Condition{
+					"http-conflict": conflictedCondition(gatewayapi_v1.ListenerReasonHostnameConflict, "All Listener hostnames for a given port must be unique"),
+				},
+			},
+			listenerSet2: {
+				ListenerNames: map[string]string{},
+				InvalidListenerConditions: map[gatewayapi_v1.SectionName]meta_v1.Condition{
+					"tls": conflictedCondition(gatewayapi_v1.ListenerReasonHostnameConflict, "All Listener hostnames for a given port must be unique"),
+					"tcp": conflictedCondition(gatewayapi_v1.ListenerReasonProtocolConflict, "All Listener protocols for a given port must be compatible"),
+				},
+			},
+		}, res.ListenerSets)
+	})
 }
@@ -0,0 +1,91 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+import (
+	"sort"
+
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+)
+
+// SortListenerSets sorts ListenerSets in precedence order, i.e. by
+// creation timestamp and then by namespace/name, so the oldest
+// ListenerSet comes first.
+func SortListenerSets(listenerSets []*gatewayapi_x_v1alpha1.XListenerSet) {
+	sort.SliceStable(listenerSets, func(i, j int) bool {
+		if listenerSets[i].CreationTimestamp.Equal(&listenerSets[j].CreationTimestamp) {
+			return namespacedNameOf(listenerSets[i]).String() < namespacedNameOf(listenerSets[j]).String()
+		}
+		return listenerSets[i].CreationTimestamp.Before(&listenerSets[j].CreationTimestamp)
+	})
+}
+
+// ListenerSetAllowed returns true if the Gateway's allowedListeners
+// allow the ListenerSet to attach to it. The ListenerSet's namespace
+// is only needed for a namespace selector, and can be nil if it is not
+// known. No ListenerSets are allowed by default.
+func ListenerSetAllowed(gateway *gatewayapi_v1.Gateway, listenerSet *gatewayapi_x_v1alpha1.XListenerSet, namespace *core_v1.Namespace) bool {
+	if gateway.Spec.AllowedListeners == nil || gateway.Spec.AllowedListeners.Namespaces == nil {
+		return false
+	}
+
+	namespaces := gateway.Spec.AllowedListeners.Namespaces
+
+	switch ptr.Deref(namespaces.From, gatewayapi_v1.NamespacesFromNone) {
+	case gatewayapi_v1.NamespacesFromAll:
+		return true
+	case gatewayapi_v1.NamespacesFromSame:
+		return listenerSet.Namespace == gateway.Namespace
+	case gatewayapi_v1.NamespacesFromSelector:
+		if namespaces.Selector == nil || namespace == nil {
+			return false
+		}
+
+		selector, err := meta_v1.LabelSelectorAsSelector(namespaces.Selector)
+		if err != nil {
+			return false
+		}
+
+		return selector.Matches(labels.Set(namespace.Labels))
+	default:
+		return false
+	}
+}
+
+// ListenersOfListenerSet returns the listeners of the
+// ListenerSet as Gateway listeners.
+func ListenersOfListenerSet(listenerSet *gatewayapi_x_v1alpha1.XListenerSet) []gatewayapi_v1.Listener {
+	listeners := make([]gatewayapi_v1.Listener, 0, len(listenerSet.Spec.Listeners))
+	for _, listener := range listenerSet.Spec.Listeners {
+		listeners = append(listeners, gatewayapi_v1.Listener{
+			Name:          listener.Name,
+			Hostname:      listener.Hostname,
+			Port:          listener.Port,
+			Protocol:      listener.Protocol,
+			TLS:           listener.TLS,
+			AllowedRoutes: listener.AllowedRoutes,
+		})
+	}
+	return listeners
+}
+
+func namespacedNameOf(listenerSet *gatewayapi_x_v1alpha1.XListenerSet) types.NamespacedName {
+	return types.NamespacedName{Namespace: listenerSet.Namespace, Name: listenerSet.Name}
+}
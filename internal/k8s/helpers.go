@@ -28,6 +28,8 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -54,6 +56,13 @@ func isStatusEqual(objA, objB any) bool {
 				return true
 			}
 		}
+	case *contour_v1.TLSCertificateDelegation:
+		if b, ok := objB.(*contour_v1.TLSCertificateDelegation); ok {
+			if cmp.Equal(a.Status, b.Status,
+				cmpopts.IgnoreFields(contour_v1.Condition{}, "LastTransitionTime")) {
+				return true
+			}
+		}
 	case *contour_v1alpha1.ExtensionService:
 		if b, ok := objB.(*contour_v1alpha1.ExtensionService); ok {
 			if cmp.Equal(a.Status, b.Status,
@@ -61,6 +70,13 @@ func isStatusEqual(objA, objB any) bool {
 				return true
 			}
 		}
+	case *contour_v1alpha1.ContourConfiguration:
+		if b, ok := objB.(*contour_v1alpha1.ContourConfiguration); ok {
+			if cmp.Equal(a.Status, b.Status,
+				cmpopts.IgnoreFields(contour_v1.Condition{}, "LastTransitionTime")) {
+				return true
+			}
+		}
 	case *gatewayapi_v1.GatewayClass:
 		if b, ok := objB.(*gatewayapi_v1.GatewayClass); ok {
 			if cmp.Equal(a.Status, b.Status,
@@ -120,17 +136,29 @@ func IsObjectEqual(oldObj, newObj client.Object) (bool, error) {
 		*gatewayapi_v1alpha2.TLSRoute,
 		*gatewayapi_v1.GRPCRoute,
 		*gatewayapi_v1alpha2.TCPRoute,
-		*gatewayapi_v1alpha3.BackendTLSPolicy:
+		*gatewayapi_v1alpha3.BackendTLSPolicy,
+		*gatewayapi_x_v1alpha1.XBackendTrafficPolicy,
+		*gatewayapi_x_v1alpha1.XListenerSet:
 		return isGenerationEqual(oldObj, newObj), nil
 
 	// Slow path: compare the content of the objects.
-	case *contour_v1.HTTPProxy,
-		*networking_v1.Ingress:
+	case *contour_v1.HTTPProxy:
+		if newObj, ok := newObj.(*contour_v1.HTTPProxy); ok {
+			// The weights of traffic shift policies are read from
+			// the status, so changes to it must rebuild the DAG.
+			return isGenerationEqual(oldObj, newObj) &&
+				apiequality.Semantic.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()) &&
+				apiequality.Semantic.DeepEqual(oldObj.Status.TrafficShifts, newObj.Status.TrafficShifts), nil
+		}
+	case *networking_v1.Ingress:
 		return isGenerationEqual(oldObj, newObj) &&
 			apiequality.Semantic.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()), nil
 	case *core_v1.Secret:
 		if newObj, ok := newObj.(*core_v1.Secret); ok {
-			return reflect.DeepEqual(oldObj.Data, newObj.Data), nil
+			// Labels are compared since TLSCertificateDelegations
+			// may select secrets by label.
+			return reflect.DeepEqual(oldObj.Data, newObj.Data) &&
+				apiequality.Semantic.DeepEqual(oldObj.Labels, newObj.Labels), nil
 		}
 	case *core_v1.ConfigMap:
 		if newObj, ok := newObj.(*core_v1.ConfigMap); ok {
@@ -142,6 +170,11 @@ func IsObjectEqual(oldObj, newObj client.Object) (bool, error) {
 				apiequality.Semantic.DeepEqual(oldObj.Status, newObj.Status) &&
 				apiequality.Semantic.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()), nil
 		}
+	case *mcs_v1alpha1.ServiceImport:
+		if newObj, ok := newObj.(*mcs_v1alpha1.ServiceImport); ok {
+			return apiequality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec) &&
+				apiequality.Semantic.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()), nil
+		}
 	case *core_v1.Endpoints:
 		if newObj, ok := newObj.(*core_v1.Endpoints); ok {
 			return apiequality.Semantic.DeepEqual(oldObj.Subsets, newObj.Subsets), nil
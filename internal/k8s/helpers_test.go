@@ -29,6 +29,7 @@ import (
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -50,6 +51,11 @@ func TestIsObjectEqual(t *testing.T) {
 			filename: "testdata/secret-metadata-change.yaml",
 			equals:   true,
 		},
+		{
+			name:     "Secret with label change",
+			filename: "testdata/secret-label-change.yaml",
+			equals:   false,
+		},
 		{
 			name:     "ConfigMap with content change",
 			filename: "testdata/configmap-content-change.yaml",
@@ -70,6 +76,16 @@ func TestIsObjectEqual(t *testing.T) {
 			filename: "testdata/service-annotation-change.yaml",
 			equals:   false,
 		},
+		{
+			name:     "ServiceImport with status change",
+			filename: "testdata/serviceimport-status-change.yaml",
+			equals:   true,
+		},
+		{
+			name:     "ServiceImport with spec change",
+			filename: "testdata/serviceimport-spec-change.yaml",
+			equals:   false,
+		},
 		{
 			name:     "Endpoint with content change",
 			filename: "testdata/endpoint-content-change.yaml",
@@ -80,6 +96,16 @@ func TestIsObjectEqual(t *testing.T) {
 			filename: "testdata/httpproxy-annotation-change.yaml",
 			equals:   false,
 		},
+		{
+			name:     "HTTPProxy with status change",
+			filename: "testdata/httpproxy-status-change.yaml",
+			equals:   true,
+		},
+		{
+			name:     "HTTPProxy with traffic shift status change",
+			filename: "testdata/httpproxy-trafficshift-status-change.yaml",
+			equals:   false,
+		},
 		{
 			name:     "Ingress with annotation change",
 			filename: "testdata/ingress-annotation-change.yaml",
@@ -96,6 +122,7 @@ func TestIsObjectEqual(t *testing.T) {
 	_ = core_v1.AddToScheme(scheme)
 	_ = networking_v1.AddToScheme(scheme)
 	_ = contour_v1.AddKnownTypes(scheme)
+	_ = mcs_v1alpha1.Install(scheme)
 
 	deserializer := serializer.NewCodecFactory(scheme).UniversalDeserializer()
 
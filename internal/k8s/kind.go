@@ -23,6 +23,8 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -45,6 +47,8 @@ func KindOf(obj any) string {
 			return "Secret"
 		case *core_v1.Service:
 			return "Service"
+		case *mcs_v1alpha1.ServiceImport:
+			return "ServiceImport"
 		case *core_v1.Endpoints:
 			return "Endpoints"
 		case *networking_v1.Ingress:
@@ -67,6 +71,10 @@ func KindOf(obj any) string {
 			return "ReferenceGrant"
 		case *gatewayapi_v1alpha3.BackendTLSPolicy:
 			return "BackendTLSPolicy"
+		case *gatewayapi_x_v1alpha1.XBackendTrafficPolicy:
+			return "XBackendTrafficPolicy"
+		case *gatewayapi_x_v1alpha1.XListenerSet:
+			return "XListenerSet"
 		case *contour_v1.TLSCertificateDelegation:
 			return "TLSCertificateDelegation"
 		case *contour_v1alpha1.ExtensionService:
@@ -103,6 +111,8 @@ func VersionOf(obj any) string {
 			return contour_v1.GroupVersion.String()
 		case *contour_v1alpha1.ExtensionService:
 			return contour_v1alpha1.GroupVersion.String()
+		case *mcs_v1alpha1.ServiceImport:
+			return mcs_v1alpha1.GroupVersion.String()
 		case *unstructured.Unstructured:
 			return obj.GetAPIVersion()
 		default:
@@ -24,6 +24,8 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
@@ -53,6 +55,9 @@ func TestKindOf(t *testing.T) {
 		{"GatewayClass", &gatewayapi_v1.GatewayClass{}},
 		{"ReferenceGrant", &gatewayapi_v1beta1.ReferenceGrant{}},
 		{"BackendTLSPolicy", &gatewayapi_v1alpha3.BackendTLSPolicy{}},
+		{"XBackendTrafficPolicy", &gatewayapi_x_v1alpha1.XBackendTrafficPolicy{}},
+		{"XListenerSet", &gatewayapi_x_v1alpha1.XListenerSet{}},
+		{"ServiceImport", &mcs_v1alpha1.ServiceImport{}},
 		{
 			"Foo", &unstructured.Unstructured{
 				Object: map[string]any{
@@ -80,6 +85,7 @@ func TestVersionOf(t *testing.T) {
 		{"projectcontour.io/v1", &contour_v1.HTTPProxy{}},
 		{"projectcontour.io/v1", &contour_v1.TLSCertificateDelegation{}},
 		{"projectcontour.io/v1alpha1", &contour_v1alpha1.ExtensionService{}},
+		{"multicluster.x-k8s.io/v1alpha1", &mcs_v1alpha1.ServiceImport{}},
 		{
 			"test.projectcontour.io/v1", &unstructured.Unstructured{
 				Object: map[string]any{
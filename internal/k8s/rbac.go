@@ -16,14 +16,22 @@ package k8s
 // +kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=get;list;watch
 // +kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses/status,verbs=create;get;update
 
+// Add RBAC policy to record events on invalid routing objects.
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
 // +kubebuilder:rbac:groups="projectcontour.io",resources=httpproxies;tlscertificatedelegations;extensionservices;contourconfigurations,verbs=get;list;watch
-// +kubebuilder:rbac:groups="projectcontour.io",resources=httpproxies/status;extensionservices/status;contourconfigurations/status,verbs=create;get;update
+// +kubebuilder:rbac:groups="projectcontour.io",resources=httpproxies/status;tlscertificatedelegations/status;extensionservices/status;contourconfigurations/status,verbs=create;get;update
 
 // +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gatewayclasses;gateways;httproutes;tlsroutes;grpcroutes;tcproutes;referencegrants;backendtlspolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gatewayclasses/status;gateways/status;httproutes/status;tlsroutes/status;grpcroutes/status;tcproutes/status;backendtlspolicies/status,verbs=update
 
+// +kubebuilder:rbac:groups="gateway.networking.x-k8s.io",resources=xbackendtrafficpolicies;xlistenersets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="gateway.networking.x-k8s.io",resources=xbackendtrafficpolicies/status;xlistenersets/status,verbs=update
+
 // +kubebuilder:rbac:groups="",resources=secrets;endpoints;services;namespaces;configmaps,verbs=get;list;watch
 
+// +kubebuilder:rbac:groups="multicluster.x-k8s.io",resources=serviceimports,verbs=get;list;watch
+
 // Add RBAC policy to support leader election.
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;get;update,namespace=projectcontour
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=create;get;update,namespace=projectcontour
@@ -48,8 +48,16 @@ type Metrics struct {
 	statusUpdateNoop            *prometheus.CounterVec
 	statusUpdateDurationSeconds *prometheus.SummaryVec
 
+	certificateExpiryGauge *prometheus.GaugeVec
+
+	xdsConnectedClientsGauge *prometheus.GaugeVec
+	xdsSnapshotSetSeconds    prometheus.Histogram
+	xdsAckSeconds            *prometheus.HistogramVec
+	xdsNackTotal             *prometheus.CounterVec
+
 	// Keep a local cache of metrics for comparison on updates
-	proxyMetricCache *RouteMetric
+	proxyMetricCache       *RouteMetric
+	certificateMetricCache map[CertificateMeta]time.Time
 }
 
 // RouteMetric stores various metrics for HTTPProxy objects
@@ -66,6 +74,12 @@ type Meta struct {
 	VHost, Namespace string
 }
 
+// CertificateMeta holds the namespace, name and usage of a Secret
+// containing certificates.
+type CertificateMeta struct {
+	Namespace, Name, Type string
+}
+
 const (
 	BuildInfoGauge = "contour_build_info"
 
@@ -88,6 +102,13 @@ const (
 	statusUpdateConflict        = "contour_status_update_conflict_total"
 	statusUpdateNoop            = "contour_status_update_noop_total"
 	statusUpdateDurationSeconds = "contour_status_update_duration_seconds"
+
+	TLSCertificateExpiryGauge = "contour_tls_certificate_expiry_timestamp_seconds"
+
+	XDSConnectedClientsGauge = "contour_xds_connected_clients"
+	XDSSnapshotSetSeconds    = "contour_xds_snapshot_set_seconds"
+	XDSAckSeconds            = "contour_xds_ack_seconds"
+	XDSNackTotal             = "contour_xds_nack_total"
 )
 
 // NewMetrics creates a new set of metrics and registers them with
@@ -240,6 +261,43 @@ func NewMetrics(registry *prometheus.Registry) *Metrics {
 			},
 			[]string{"kind", "error"},
 		),
+		certificateExpiryGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: TLSCertificateExpiryGauge,
+				Help: "Expiry time, in seconds since the Unix epoch, of the earliest expiring certificate in each Secret referenced by a valid object. Type is one of serving, client or ca.",
+			},
+			[]string{"namespace", "name", "type"},
+		),
+		certificateMetricCache: map[CertificateMeta]time.Time{},
+		xdsConnectedClientsGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: XDSConnectedClientsGauge,
+				Help: "Number of Envoy nodes connected to the xDS server by resource type URL and node cluster.",
+			},
+			[]string{"type_url", "node_cluster"},
+		),
+		xdsSnapshotSetSeconds: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    XDSSnapshotSetSeconds,
+				Help:    "Duration in seconds from a DAG rebuild until the xDS snapshot built from it is set.",
+				Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+			},
+		),
+		xdsAckSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    XDSAckSeconds,
+				Help:    "Duration in seconds from an xDS snapshot being set until an Envoy node ACKs it, by resource type URL.",
+				Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+			},
+			[]string{"type_url"},
+		),
+		xdsNackTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: XDSNackTotal,
+				Help: "Total number of xDS responses rejected (NACKed) by Envoy nodes by resource type URL and node cluster.",
+			},
+			[]string{"type_url", "node_cluster"},
+		),
 	}
 	m.buildInfoGauge.WithLabelValues(build.Branch, build.Sha, build.Version).Set(1)
 	m.register(registry)
@@ -267,6 +325,11 @@ func (m *Metrics) register(registry *prometheus.Registry) {
 		m.statusUpdateConflict,
 		m.statusUpdateNoop,
 		m.statusUpdateDurationSeconds,
+		m.certificateExpiryGauge,
+		m.xdsConnectedClientsGauge,
+		m.xdsSnapshotSetSeconds,
+		m.xdsAckSeconds,
+		m.xdsNackTotal,
 	)
 }
 
@@ -297,6 +360,11 @@ func (m *Metrics) Zero() {
 	m.SetStatusUpdateFailed("kind")
 	m.SetStatusUpdateConflict("kind")
 	m.SetStatusUpdateDuration(time.Nanosecond, "kind", false)
+	m.SetCertificateExpiryMetric(map[CertificateMeta]time.Time{{}: time.Now()})
+	m.SetXDSConnectedClients("type_url", "node_cluster", 0)
+	m.SetXDSSnapshotSetDuration(0)
+	m.SetXDSAckDuration("type_url", 0)
+	m.SetXDSNack("type_url", "node_cluster")
 
 	m.CacheHandlerOnUpdateSummary.Observe(0)
 	m.DAGRebuildSeconds.Observe(0)
@@ -370,6 +438,45 @@ func (m *Metrics) SetHTTPProxyMetric(metrics RouteMetric) {
 	}
 }
 
+// SetCertificateExpiryMetric records the expiry time of the certificates
+// in each Secret, removing any Secrets that are no longer referenced.
+func (m *Metrics) SetCertificateExpiryMetric(expiry map[CertificateMeta]time.Time) {
+	for meta, ts := range expiry {
+		m.certificateExpiryGauge.WithLabelValues(meta.Namespace, meta.Name, meta.Type).Set(float64(ts.Unix()))
+		delete(m.certificateMetricCache, meta)
+	}
+
+	// All metrics processed, now remove what's left as they are not needed
+	for meta := range m.certificateMetricCache {
+		m.certificateExpiryGauge.DeleteLabelValues(meta.Namespace, meta.Name, meta.Type)
+	}
+
+	m.certificateMetricCache = expiry
+}
+
+// SetXDSConnectedClients adds delta to the number of Envoy nodes connected
+// to the xDS server for the given type URL and node cluster.
+func (m *Metrics) SetXDSConnectedClients(typeURL, nodeCluster string, delta int) {
+	m.xdsConnectedClientsGauge.WithLabelValues(typeURL, nodeCluster).Add(float64(delta))
+}
+
+// SetXDSSnapshotSetDuration records the time from a DAG rebuild until the
+// xDS snapshot built from it was set.
+func (m *Metrics) SetXDSSnapshotSetDuration(duration time.Duration) {
+	m.xdsSnapshotSetSeconds.Observe(duration.Seconds())
+}
+
+// SetXDSAckDuration records the time from an xDS snapshot being set until
+// an Envoy node ACKed it.
+func (m *Metrics) SetXDSAckDuration(typeURL string, duration time.Duration) {
+	m.xdsAckSeconds.WithLabelValues(typeURL).Observe(duration.Seconds())
+}
+
+// SetXDSNack records an xDS response rejected by an Envoy node.
+func (m *Metrics) SetXDSNack(typeURL, nodeCluster string) {
+	m.xdsNackTotal.WithLabelValues(typeURL, nodeCluster).Inc()
+}
+
 func (m *Metrics) SetStatusUpdateTotal(kind string) {
 	m.statusUpdateTotal.With(prometheus.Labels{"kind": kind}).Inc()
 }
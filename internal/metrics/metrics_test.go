@@ -748,3 +748,50 @@ func TestSetDAGCacheObjectMetric(t *testing.T) {
 		})
 	}
 }
+
+func TestSetCertificateExpiryMetric(t *testing.T) {
+	serving := CertificateMeta{Namespace: "default", Name: "tls", Type: "serving"}
+	ca := CertificateMeta{Namespace: "default", Name: "ca", Type: "ca"}
+	expiry := time.Date(2029, 11, 17, 20, 34, 58, 0, time.UTC)
+
+	gauge := func(meta CertificateMeta) *io_prometheus_client.Metric {
+		return &io_prometheus_client.Metric{
+			Label: []*io_prometheus_client.LabelPair{
+				{Name: ptr.To("name"), Value: ptr.To(meta.Name)},
+				{Name: ptr.To("namespace"), Value: ptr.To(meta.Namespace)},
+				{Name: ptr.To("type"), Value: ptr.To(meta.Type)},
+			},
+			Gauge: &io_prometheus_client.Gauge{
+				Value: ptr.To(float64(expiry.Unix())),
+			},
+		}
+	}
+
+	r := prometheus.NewRegistry()
+	m := NewMetrics(r)
+
+	gather := func() []*io_prometheus_client.Metric {
+		gathering, err := r.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, mf := range gathering {
+			if mf.GetName() == TLSCertificateExpiryGauge {
+				return mf.Metric
+			}
+		}
+		return nil
+	}
+
+	m.SetCertificateExpiryMetric(map[CertificateMeta]time.Time{
+		serving: expiry,
+		ca:      expiry,
+	})
+	assert.Equal(t, []*io_prometheus_client.Metric{gauge(ca), gauge(serving)}, gather())
+
+	// Secrets no longer referenced are removed.
+	m.SetCertificateExpiryMetric(map[CertificateMeta]time.Time{
+		serving: expiry,
+	})
+	assert.Equal(t, []*io_prometheus_client.Metric{gauge(serving)}, gather())
+}
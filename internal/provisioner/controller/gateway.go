@@ -18,10 +18,13 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -31,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/gatewayapi"
@@ -51,6 +55,10 @@ type gatewayReconciler struct {
 	envoyImage        string
 	client            client.Client
 	log               logr.Logger
+
+	// listenerSetsAvailable is true if the XListenerSet
+	// CRD is installed in the cluster.
+	listenerSetsAvailable bool
 }
 
 func NewGatewayController(mgr manager.Manager, gatewayController, contourImage, envoyImage string) (controller.Controller, error) {
@@ -86,6 +94,19 @@ func NewGatewayController(mgr manager.Manager, gatewayController, contourImage,
 		return nil, err
 	}
 
+	// Watch XListenerSets, if installed, so we can trigger reconciles
+	// for their parent Gateways when the ports they listen on change.
+	if _, err := mgr.GetRESTMapper().RESTMapping(schema.GroupKind{Group: gatewayapi_x_v1alpha1.GroupName, Kind: gatewayapi.KindListenerSet}); err == nil {
+		r.listenerSetsAvailable = true
+
+		if err := c.Watch(
+			source.Kind(mgr.GetCache(), &gatewayapi_x_v1alpha1.XListenerSet{},
+				handler.TypedEnqueueRequestsFromMapFunc(r.getListenerSetGateway)),
+		); err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
@@ -144,6 +165,66 @@ func (r *gatewayReconciler) getGatewayClassGateways(ctx context.Context, gateway
 	return reconciles
 }
 
+// getListenerSetGateway returns a reconcile request for the parent
+// Gateway of the provided ListenerSet.
+func (r *gatewayReconciler) getListenerSetGateway(_ context.Context, listenerSet *gatewayapi_x_v1alpha1.XListenerSet) []reconcile.Request {
+	parentRef := listenerSet.Spec.ParentRef
+
+	if ptr.Deref(parentRef.Group, gatewayapi_v1.GroupName) != gatewayapi_v1.GroupName || ptr.Deref(parentRef.Kind, "Gateway") != "Gateway" {
+		return nil
+	}
+
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: string(ptr.Deref(parentRef.Namespace, gatewayapi_v1.Namespace(listenerSet.Namespace))),
+			Name:      string(parentRef.Name),
+		},
+	}}
+}
+
+// getListenerSetListeners returns the listeners of the ListenerSets that
+// the provided Gateway allows, in precedence order.
+func (r *gatewayReconciler) getListenerSetListeners(ctx context.Context, gateway *gatewayapi_v1.Gateway) ([]gatewayapi.ListenerSetListeners, error) {
+	if !r.listenerSetsAvailable {
+		return nil, nil
+	}
+
+	var listenerSetList gatewayapi_x_v1alpha1.XListenerSetList
+	if err := r.client.List(ctx, &listenerSetList); err != nil {
+		return nil, err
+	}
+
+	var listenerSets []*gatewayapi_x_v1alpha1.XListenerSet
+	for i := range listenerSetList.Items {
+		if gatewayapi.IsListenerSetOfGateway(&listenerSetList.Items[i], client.ObjectKeyFromObject(gateway)) {
+			listenerSets = append(listenerSets, &listenerSetList.Items[i])
+		}
+	}
+	gatewayapi.SortListenerSets(listenerSets)
+
+	var listenerSetListeners []gatewayapi.ListenerSetListeners
+	for _, listenerSet := range listenerSets {
+		namespace := &core_v1.Namespace{}
+		if err := r.client.Get(ctx, client.ObjectKey{Name: listenerSet.Namespace}, namespace); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			namespace = nil
+		}
+
+		if !gatewayapi.ListenerSetAllowed(gateway, listenerSet, namespace) {
+			continue
+		}
+
+		listenerSetListeners = append(listenerSetListeners, gatewayapi.ListenerSetListeners{
+			ListenerSet: client.ObjectKeyFromObject(listenerSet),
+			Listeners:   gatewayapi.ListenersOfListenerSet(listenerSet),
+		})
+	}
+
+	return listenerSetListeners, nil
+}
+
 func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.log.WithValues("gateway-namespace", req.Namespace, "gateway-name", req.Name)
 
@@ -196,9 +277,14 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}
 
-	// Validate listener ports and hostnames to get
-	// the ports to program.
-	for _, listenerPort := range gatewayapi.ValidateListeners(gateway.Spec.Listeners).Ports {
+	listenerSetListeners, err := r.getListenerSetListeners(ctx, gateway)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error getting gateway's listener sets: %w", err)
+	}
+
+	// Validate listener ports and hostnames of the Gateway
+	// and the ListenerSets it allows to get the ports to program.
+	for _, listenerPort := range gatewayapi.ValidateListeners(gateway.Spec.Listeners, listenerSetListeners...).Ports {
 		contourModel.Spec.NetworkPublishing.Envoy.Ports = append(contourModel.Spec.NetworkPublishing.Envoy.Ports, model.Port{
 			Name:          listenerPort.Name,
 			ServicePort:   listenerPort.Port,
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/provisioner"
@@ -95,7 +96,7 @@ func TestGatewayReconcile(t *testing.T) {
 		}
 	}
 
-	makeGatewayWithAddrs := func(addrs []gatewayapi_v1.GatewayAddress) *gatewayapi_v1.Gateway {
+	makeGatewayWithAddrs := func(addrs []gatewayapi_v1.GatewaySpecAddress) *gatewayapi_v1.Gateway {
 		gtw := makeGateway()
 		gtw.Spec.Addresses = addrs
 		return gtw
@@ -111,6 +112,7 @@ func TestGatewayReconcile(t *testing.T) {
 		gatewayClass       *gatewayapi_v1.GatewayClass
 		gatewayClassParams *contour_v1alpha1.ContourDeployment
 		gateway            *gatewayapi_v1.Gateway
+		listenerSets       []*gatewayapi_x_v1alpha1.XListenerSet
 		req                *reconcile.Request
 		assertions         func(t *testing.T, r *gatewayReconciler, gw *gatewayapi_v1.Gateway, reconcileErr error)
 	}{
@@ -220,7 +222,7 @@ func TestGatewayReconcile(t *testing.T) {
 		},
 		"A gateway with one IP address results in an Envoy service with loadBalancerIP set to that IP address": {
 			gatewayClass: reconcilableGatewayClass("gatewayclass-1", controller),
-			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewayAddress{
+			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewaySpecAddress{
 				{
 					Type:  ptr.To(gatewayapi_v1.IPAddressType),
 					Value: "172.18.255.207",
@@ -234,7 +236,7 @@ func TestGatewayReconcile(t *testing.T) {
 		},
 		"A gateway with two IP addresses results in an Envoy service with loadBalancerIP set to the first IP address": {
 			gatewayClass: reconcilableGatewayClass("gatewayclass-1", controller),
-			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewayAddress{
+			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewaySpecAddress{
 				{
 					Type:  ptr.To(gatewayapi_v1.IPAddressType),
 					Value: "172.18.255.207",
@@ -251,7 +253,7 @@ func TestGatewayReconcile(t *testing.T) {
 		},
 		"A gateway with one Hostname address results in an Envoy service with loadBalancerIP set to that hostname": {
 			gatewayClass: reconcilableGatewayClass("gatewayclass-1", controller),
-			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewayAddress{
+			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewaySpecAddress{
 				{
 					Type:  ptr.To(gatewayapi_v1.HostnameAddressType),
 					Value: "projectcontour.io",
@@ -264,7 +266,7 @@ func TestGatewayReconcile(t *testing.T) {
 		},
 		"A gateway with two Hostname addresses results in an Envoy service with loadBalancerIP set to the first hostname": {
 			gatewayClass: reconcilableGatewayClass("gatewayclass-1", controller),
-			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewayAddress{
+			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewaySpecAddress{
 				{
 					Type:  ptr.To(gatewayapi_v1.HostnameAddressType),
 					Value: "projectcontour.io",
@@ -281,7 +283,7 @@ func TestGatewayReconcile(t *testing.T) {
 		},
 		"A gateway with one custom address type results in an Envoy service with no loadBalancerIP": {
 			gatewayClass: reconcilableGatewayClass("gatewayclass-1", controller),
-			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewayAddress{
+			gateway: makeGatewayWithAddrs([]gatewayapi_v1.GatewaySpecAddress{
 				{
 					Type:  ptr.To(gatewayapi_v1.AddressType("acme.io/CustomAddressType")),
 					Value: "custom-address-types-are-not-supported",
@@ -607,6 +609,95 @@ func TestGatewayReconcile(t *testing.T) {
 				})
 			},
 		},
+		"The Envoy service's ports are derived from the Gateway's listeners and the ListenerSets it allows": {
+			gatewayClass: reconcilableGatewayClass("gatewayclass-1", controller),
+			gateway: func() *gatewayapi_v1.Gateway {
+				gtw := makeGatewayWithListeners([]gatewayapi_v1.Listener{
+					{
+						Name:     "listener-1",
+						Protocol: gatewayapi_v1.HTTPProtocolType,
+						Port:     80,
+					},
+				})
+				gtw.Spec.AllowedListeners = &gatewayapi_v1.AllowedListeners{
+					Namespaces: &gatewayapi_v1.ListenerNamespaces{
+						From: ptr.To(gatewayapi_v1.NamespacesFromSame),
+					},
+				}
+				return gtw
+			}(),
+			listenerSets: []*gatewayapi_x_v1alpha1.XListenerSet{
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Namespace: "gateway-1",
+						Name:      "listenerset-1",
+					},
+					Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+						ParentRef: gatewayapi_x_v1alpha1.ParentGatewayReference{
+							Name: "gateway-1",
+						},
+						Listeners: []gatewayapi_x_v1alpha1.ListenerEntry{
+							{
+								Name:     "listener-1",
+								Protocol: gatewayapi_v1.HTTPSProtocolType,
+								Port:     8443,
+							},
+							// listener-2 will be ignored because it conflicts with the Gateway's listener-1
+							{
+								Name:     "listener-2",
+								Protocol: gatewayapi_v1.TCPProtocolType,
+								Port:     80,
+							},
+						},
+					},
+				},
+				// listenerset-2 will be ignored because the Gateway does not allow its namespace
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Namespace: "other",
+						Name:      "listenerset-2",
+					},
+					Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+						ParentRef: gatewayapi_x_v1alpha1.ParentGatewayReference{
+							Namespace: ptr.To(gatewayapi_v1.Namespace("gateway-1")),
+							Name:      "gateway-1",
+						},
+						Listeners: []gatewayapi_x_v1alpha1.ListenerEntry{
+							{
+								Name:     "listener-1",
+								Protocol: gatewayapi_v1.HTTPProtocolType,
+								Port:     8081,
+							},
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, r *gatewayReconciler, gw *gatewayapi_v1.Gateway, reconcileErr error) {
+				require.NoError(t, reconcileErr)
+				// Get the expected Envoy service from the client.
+				envoyService := &core_v1.Service{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Namespace: gw.Namespace,
+						Name:      "envoy-" + gw.Name,
+					},
+				}
+				require.NoError(t, r.client.Get(context.Background(), keyFor(envoyService), envoyService))
+
+				require.Len(t, envoyService.Spec.Ports, 2)
+				assert.Contains(t, envoyService.Spec.Ports, core_v1.ServicePort{
+					Name:       "http-80",
+					Protocol:   core_v1.ProtocolTCP,
+					Port:       80,
+					TargetPort: intstr.IntOrString{IntVal: 8080},
+				})
+				assert.Contains(t, envoyService.Spec.Ports, core_v1.ServicePort{
+					Name:       "https-8443",
+					Protocol:   core_v1.ProtocolTCP,
+					Port:       8443,
+					TargetPort: intstr.IntOrString{IntVal: 16443},
+				})
+			},
+		},
 		"If ContourDeployment.Spec.Contour.Replicas is not specified, the Contour deployment defaults to 2 replicas": {
 			gatewayClass: reconcilableGatewayClassWithParams("gatewayclass-1", controller),
 			gatewayClassParams: &contour_v1alpha1.ContourDeployment{
@@ -1415,11 +1506,15 @@ func TestGatewayReconcile(t *testing.T) {
 				client.WithObjects(tc.gateway)
 				client.WithStatusSubresource(tc.gateway)
 			}
+			for _, listenerSet := range tc.listenerSets {
+				client.WithObjects(listenerSet)
+			}
 
 			r := &gatewayReconciler{
-				gatewayController: controller,
-				client:            client.Build(),
-				log:               logr.Discard(),
+				gatewayController:     controller,
+				client:                client.Build(),
+				log:                   logr.Discard(),
+				listenerSetsAvailable: true,
 			}
 
 			var req reconcile.Request
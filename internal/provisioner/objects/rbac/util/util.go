@@ -21,6 +21,8 @@ import (
 	networking_v1 "k8s.io/api/networking/v1"
 	rbac_v1 "k8s.io/api/rbac/v1"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/provisioner/model"
@@ -30,14 +32,17 @@ import (
 const contourV1GroupName = "projectcontour.io"
 
 var (
-	GatewayGroupNamespacedResource       = []string{"gateways", "httproutes", "tlsroutes", "grpcroutes", "tcproutes", "referencegrants", "backendtlspolicies"}
-	GatewayGroupNamespacedResourceStatus = []string{"gateways/status", "httproutes/status", "tlsroutes/status", "grpcroutes/status", "tcproutes/status", "backendtlspolicies/status"}
-	ContourGroupNamespacedResource       = []string{"httpproxies", "tlscertificatedelegations", "extensionservices", "contourconfigurations"}
-	ContourGroupNamespacedResourceStatus = []string{"httpproxies/status", "extensionservices/status", "contourconfigurations/status"}
+	GatewayGroupNamespacedResource        = []string{"gateways", "httproutes", "tlsroutes", "grpcroutes", "tcproutes", "referencegrants", "backendtlspolicies"}
+	GatewayGroupNamespacedResourceStatus  = []string{"gateways/status", "httproutes/status", "tlsroutes/status", "grpcroutes/status", "tcproutes/status", "backendtlspolicies/status"}
+	GatewayXGroupNamespacedResource       = []string{"xbackendtrafficpolicies", "xlistenersets"}
+	GatewayXGroupNamespacedResourceStatus = []string{"xbackendtrafficpolicies/status", "xlistenersets/status"}
+	ContourGroupNamespacedResource        = []string{"httpproxies", "tlscertificatedelegations", "extensionservices", "contourconfigurations"}
+	ContourGroupNamespacedResourceStatus  = []string{"httpproxies/status", "tlscertificatedelegations/status", "extensionservices/status", "contourconfigurations/status"}
 )
 
 var (
 	createGetUpdate = []string{"create", "get", "update"}
+	createPatch     = []string{"create", "patch"}
 	getListWatch    = []string{"get", "list", "watch"}
 	update          = []string{"update"}
 )
@@ -62,15 +67,25 @@ func NamespacedResourcePolicyRules(resourcesToSkip []contour_v1.Feature) []rbac_
 		// Discovery Contour-watched resources.
 		PolicyRuleFor(discovery_v1.GroupName, getListWatch, "endpointslices"),
 
+		// Multi-cluster ServiceImports, watched when the multiClusterServices feature flag is enabled.
+		PolicyRuleFor(mcs_v1alpha1.GroupName, getListWatch, "serviceimports"),
+
 		// Gateway API resources.
 		// Note, ReferenceGrant does not currently have a .status field so it's omitted from the status rule.
 		PolicyRuleFor(gatewayapi_v1.GroupName, getListWatch, filterResources(resourcesToSkip, GatewayGroupNamespacedResource...)...),
 		PolicyRuleFor(gatewayapi_v1.GroupName, update, filterResources(resourcesToSkip, GatewayGroupNamespacedResourceStatus...)...),
 
+		// Experimental Gateway API resources.
+		PolicyRuleFor(gatewayapi_x_v1alpha1.GroupName, getListWatch, filterResources(resourcesToSkip, GatewayXGroupNamespacedResource...)...),
+		PolicyRuleFor(gatewayapi_x_v1alpha1.GroupName, update, filterResources(resourcesToSkip, GatewayXGroupNamespacedResourceStatus...)...),
+
 		// Ingress resources.
 		PolicyRuleFor(networking_v1.GroupName, getListWatch, "ingresses"),
 		PolicyRuleFor(networking_v1.GroupName, createGetUpdate, "ingresses/status"),
 
+		// Events recorded on invalid routing objects.
+		PolicyRuleFor(core_v1.GroupName, createPatch, "events"),
+
 		// Contour CRDs.
 		PolicyRuleFor(contourV1GroupName, getListWatch, filterResources(resourcesToSkip, ContourGroupNamespacedResource...)...),
 		PolicyRuleFor(contourV1GroupName, createGetUpdate, filterResources(resourcesToSkip, ContourGroupNamespacedResourceStatus...)...),
@@ -23,12 +23,14 @@ import (
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	"github.com/projectcontour/contour/internal/gatewayapi"
 )
 
 // BackendTLSPolicyStatusUpdate represents an atomic update to a
-// BackendTLSPolicy's status.
+// BackendTLSPolicy's status. It is also used for XBackendTrafficPolicies,
+// which have the same status.
 type BackendTLSPolicyStatusUpdate struct {
 	FullName               types.NamespacedName
 	PolicyAncestorStatuses []*gatewayapi_v1alpha2.PolicyAncestorStatus
@@ -118,13 +120,25 @@ func (b *BackendTLSPolicyStatusUpdate) ConditionsForAncestorRef(ancestorRef gate
 }
 
 func (b *BackendTLSPolicyStatusUpdate) Mutate(obj client.Object) client.Object {
-	o, ok := obj.(*gatewayapi_v1alpha3.BackendTLSPolicy)
-	if !ok {
+	switch o := obj.(type) {
+	case *gatewayapi_v1alpha3.BackendTLSPolicy:
+		btp := o.DeepCopy()
+		btp.Status.Ancestors = b.mergeAncestorStatuses(o.Status.Ancestors)
+		return btp
+	case *gatewayapi_x_v1alpha1.XBackendTrafficPolicy:
+		blp := o.DeepCopy()
+		blp.Status.Ancestors = b.mergeAncestorStatuses(o.Status.Ancestors)
+		return blp
+	default:
 		panic(fmt.Sprintf("Unsupported %T object %s/%s in status mutator",
 			obj, b.FullName.Namespace, b.FullName.Name,
 		))
 	}
+}
 
+// mergeAncestorStatuses returns the PolicyAncestorStatuses of this update
+// followed by the existing ones for other Gateways.
+func (b *BackendTLSPolicyStatusUpdate) mergeAncestorStatuses(existing []gatewayapi_v1alpha2.PolicyAncestorStatus) []gatewayapi_v1alpha2.PolicyAncestorStatus {
 	var newPolicyAncestorStatuses []gatewayapi_v1alpha2.PolicyAncestorStatus
 	for _, pas := range b.PolicyAncestorStatuses {
 		for i := range pas.Conditions {
@@ -137,16 +151,12 @@ func (b *BackendTLSPolicyStatusUpdate) Mutate(obj client.Object) client.Object {
 		newPolicyAncestorStatuses = append(newPolicyAncestorStatuses, *pas)
 	}
 
-	btp := o.DeepCopy()
-
 	// Get all the PolicyAncestorStatuses that are for other Gateways.
-	for _, pas := range o.Status.Ancestors {
+	for _, pas := range existing {
 		if !gatewayapi.IsRefToGateway(pas.AncestorRef, b.GatewayRef) {
 			newPolicyAncestorStatuses = append(newPolicyAncestorStatuses, pas)
 		}
 	}
 
-	btp.Status.Ancestors = newPolicyAncestorStatuses
-
-	return btp
+	return newPolicyAncestorStatuses
 }
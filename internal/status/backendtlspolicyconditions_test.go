@@ -22,6 +22,7 @@ import (
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/gatewayapi"
@@ -134,3 +135,92 @@ func TestBackendTLSPolicyMutate(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, wantBackendTLSPolicy, btp, 1)
 }
+
+func TestXBackendTrafficPolicyMutate(t *testing.T) {
+	testTransitionTime := meta_v1.NewTime(time.Now())
+	var testGeneration int64 = 3
+
+	bsu := BackendTLSPolicyStatusUpdate{
+		FullName:       k8s.NamespacedNameFrom("test/test"),
+		GatewayRef:     k8s.NamespacedNameFrom("projectcontour/contour"),
+		Generation:     testGeneration,
+		TransitionTime: testTransitionTime,
+		PolicyAncestorStatuses: []*gatewayapi_v1alpha2.PolicyAncestorStatus{
+			{
+				AncestorRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				Conditions: []meta_v1.Condition{
+					{
+						Type:    string(gatewayapi_v1alpha2.PolicyConditionAccepted),
+						Status:  contour_v1.ConditionTrue,
+						Reason:  string(gatewayapi_v1alpha2.PolicyReasonAccepted),
+						Message: "Accepted XBackendTrafficPolicy",
+					},
+				},
+			},
+		},
+	}
+
+	otherGatewayStatus := gatewayapi_v1alpha2.PolicyAncestorStatus{
+		AncestorRef: gatewayapi.GatewayParentRef("externalgateway", "some-gateway"),
+		Conditions: []meta_v1.Condition{
+			{
+				Type:    string(gatewayapi_v1alpha2.PolicyConditionAccepted),
+				Status:  contour_v1.ConditionTrue,
+				Reason:  string(gatewayapi_v1alpha2.PolicyReasonAccepted),
+				Message: "This was added by some other gateway and should not be removed.",
+			},
+		},
+	}
+
+	blp := &gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Status: gatewayapi_v1alpha2.PolicyStatus{
+			Ancestors: []gatewayapi_v1alpha2.PolicyAncestorStatus{
+				{
+					// A stale status from this Gateway is replaced.
+					AncestorRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []meta_v1.Condition{
+						{
+							Type:   string(gatewayapi_v1alpha2.PolicyConditionAccepted),
+							Status: contour_v1.ConditionFalse,
+							Reason: string(gatewayapi_v1alpha2.PolicyReasonInvalid),
+						},
+					},
+				},
+				otherGatewayStatus,
+			},
+		},
+	}
+
+	want := &gatewayapi_x_v1alpha1.XBackendTrafficPolicy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Status: gatewayapi_v1alpha2.PolicyStatus{
+			Ancestors: []gatewayapi_v1alpha2.PolicyAncestorStatus{
+				{
+					AncestorRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []meta_v1.Condition{
+						{
+							ObservedGeneration: testGeneration,
+							LastTransitionTime: testTransitionTime,
+							Type:               string(gatewayapi_v1alpha2.PolicyConditionAccepted),
+							Status:             contour_v1.ConditionTrue,
+							Reason:             string(gatewayapi_v1alpha2.PolicyReasonAccepted),
+							Message:            "Accepted XBackendTrafficPolicy",
+						},
+					},
+				},
+				otherGatewayStatus,
+			},
+		},
+	}
+
+	got, ok := bsu.Mutate(blp).(*gatewayapi_x_v1alpha1.XBackendTrafficPolicy)
+	require.True(t, ok)
+	assert.Equal(t, want, got)
+}
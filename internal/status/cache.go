@@ -16,6 +16,7 @@
 package status
 
 import (
+	"sort"
 	"time"
 
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/k8s"
@@ -37,15 +39,16 @@ type ConditionType string
 const ValidCondition ConditionType = "Valid"
 
 // NewCache creates a new Cache for holding status updates.
-func NewCache(gateway types.NamespacedName, gatewayController gatewayapi_v1.GatewayController) Cache {
+func NewCache(gatewayController gatewayapi_v1.GatewayController) Cache {
 	return Cache{
-		gatewayRef:              gateway,
-		gatewayController:       gatewayController,
-		proxyUpdates:            make(map[types.NamespacedName]*ProxyUpdate),
-		gatewayUpdates:          make(map[types.NamespacedName]*GatewayStatusUpdate),
-		routeUpdates:            make(map[types.NamespacedName]*RouteStatusUpdate),
-		backendTLSPolicyUpdates: make(map[types.NamespacedName]*BackendTLSPolicyStatusUpdate),
-		entries:                 make(map[string]map[types.NamespacedName]CacheEntry),
+		gatewayController:           gatewayController,
+		proxyUpdates:                make(map[types.NamespacedName]*ProxyUpdate),
+		gatewayUpdates:              make(map[types.NamespacedName]*GatewayStatusUpdate),
+		listenerSetUpdates:          make(map[types.NamespacedName]*ListenerSetStatusUpdate),
+		routeUpdates:                make(map[gatewayObjectKey]*RouteStatusUpdate),
+		backendTLSPolicyUpdates:     make(map[gatewayObjectKey]*BackendTLSPolicyStatusUpdate),
+		backendTrafficPolicyUpdates: make(map[gatewayObjectKey]*BackendTLSPolicyStatusUpdate),
+		entries:                     make(map[string]map[types.NamespacedName]CacheEntry),
 	}
 }
 
@@ -58,16 +61,23 @@ type CacheEntry interface {
 // It holds a per-Kind cache, and is intended to be accessed with a
 // KindAccessor.
 type Cache struct {
-	gatewayRef        types.NamespacedName
 	gatewayController gatewayapi_v1.GatewayController
 
-	proxyUpdates            map[types.NamespacedName]*ProxyUpdate
-	gatewayUpdates          map[types.NamespacedName]*GatewayStatusUpdate
-	routeUpdates            map[types.NamespacedName]*RouteStatusUpdate
-	backendTLSPolicyUpdates map[types.NamespacedName]*BackendTLSPolicyStatusUpdate
+	proxyUpdates       map[types.NamespacedName]*ProxyUpdate
+	gatewayUpdates     map[types.NamespacedName]*GatewayStatusUpdate
+	listenerSetUpdates map[types.NamespacedName]*ListenerSetStatusUpdate
+
+	// Route and policy updates are kept per Gateway, as each
+	// Gateway's update only replaces that Gateway's statuses.
+	routeUpdates                map[gatewayObjectKey]*RouteStatusUpdate
+	backendTLSPolicyUpdates     map[gatewayObjectKey]*BackendTLSPolicyStatusUpdate
+	backendTrafficPolicyUpdates map[gatewayObjectKey]*BackendTLSPolicyStatusUpdate
 
 	// Map of cache entry maps, keyed on Kind.
 	entries map[string]map[types.NamespacedName]CacheEntry
+
+	// Warnings about objects which have no status.
+	warnings []Warning
 }
 
 // Get returns a pointer to a the cache entry if it exists, nil
@@ -100,11 +110,31 @@ func (c *Cache) Put(obj meta_v1.Object, e CacheEntry) {
 func (c *Cache) GetStatusUpdates() []k8s.StatusUpdate {
 	var flattened []k8s.StatusUpdate
 
-	for fullname, backendTLSPolicyUpdate := range c.backendTLSPolicyUpdates {
+	backendTLSPolicyMutators := map[types.NamespacedName][]k8s.StatusMutator{}
+	for _, key := range sortedGatewayObjectKeys(c.backendTLSPolicyUpdates) {
+		backendTLSPolicyMutators[key.object] = append(backendTLSPolicyMutators[key.object], c.backendTLSPolicyUpdates[key])
+	}
+
+	for fullname, mutators := range backendTLSPolicyMutators {
 		update := k8s.StatusUpdate{
 			NamespacedName: fullname,
 			Resource:       &gatewayapi_v1alpha3.BackendTLSPolicy{},
-			Mutator:        backendTLSPolicyUpdate,
+			Mutator:        chainMutators(mutators),
+		}
+
+		flattened = append(flattened, update)
+	}
+
+	backendTrafficPolicyMutators := map[types.NamespacedName][]k8s.StatusMutator{}
+	for _, key := range sortedGatewayObjectKeys(c.backendTrafficPolicyUpdates) {
+		backendTrafficPolicyMutators[key.object] = append(backendTrafficPolicyMutators[key.object], c.backendTrafficPolicyUpdates[key])
+	}
+
+	for fullname, mutators := range backendTrafficPolicyMutators {
+		update := k8s.StatusUpdate{
+			NamespacedName: fullname,
+			Resource:       &gatewayapi_x_v1alpha1.XBackendTrafficPolicy{},
+			Mutator:        chainMutators(mutators),
 		}
 
 		flattened = append(flattened, update)
@@ -120,11 +150,21 @@ func (c *Cache) GetStatusUpdates() []k8s.StatusUpdate {
 		flattened = append(flattened, update)
 	}
 
-	for fullname, routeUpdate := range c.routeUpdates {
+	routeUpdates := map[types.NamespacedName][]*RouteStatusUpdate{}
+	for _, key := range sortedGatewayObjectKeys(c.routeUpdates) {
+		routeUpdates[key.object] = append(routeUpdates[key.object], c.routeUpdates[key])
+	}
+
+	for fullname, updates := range routeUpdates {
+		var mutators []k8s.StatusMutator
+		for _, routeUpdate := range updates {
+			mutators = append(mutators, routeUpdate)
+		}
+
 		update := k8s.StatusUpdate{
 			NamespacedName: fullname,
-			Resource:       routeUpdate.Resource,
-			Mutator:        routeUpdate,
+			Resource:       updates[0].Resource,
+			Mutator:        chainMutators(mutators),
 		}
 
 		flattened = append(flattened, update)
@@ -140,6 +180,16 @@ func (c *Cache) GetStatusUpdates() []k8s.StatusUpdate {
 		flattened = append(flattened, update)
 	}
 
+	for fullname, lsUpdate := range c.listenerSetUpdates {
+		update := k8s.StatusUpdate{
+			NamespacedName: fullname,
+			Resource:       &gatewayapi_x_v1alpha1.XListenerSet{},
+			Mutator:        lsUpdate,
+		}
+
+		flattened = append(flattened, update)
+	}
+
 	for _, byKind := range c.entries {
 		for _, e := range byKind {
 			flattened = append(flattened, e.AsStatusUpdate())
@@ -170,6 +220,15 @@ func (c *Cache) GetGatewayUpdates() []*GatewayStatusUpdate {
 	return allUpdates
 }
 
+// GetListenerSetUpdates gets the underlying ListenerSetStatusUpdate objects from the cache.
+func (c *Cache) GetListenerSetUpdates() []*ListenerSetStatusUpdate {
+	var allUpdates []*ListenerSetStatusUpdate
+	for _, conditionsUpdate := range c.listenerSetUpdates {
+		allUpdates = append(allUpdates, conditionsUpdate)
+	}
+	return allUpdates
+}
+
 // GetRouteUpdates gets the underlying RouteConditionsUpdate objects from the cache.
 func (c *Cache) GetRouteUpdates() []*RouteStatusUpdate {
 	var allUpdates []*RouteStatusUpdate
@@ -188,6 +247,15 @@ func (c *Cache) GetBackendTLSPolicyUpdates() []*BackendTLSPolicyStatusUpdate {
 	return allUpdates
 }
 
+// GetBackendTrafficPolicyUpdates gets the underlying XBackendTrafficPolicy status updates from the cache.
+func (c *Cache) GetBackendTrafficPolicyUpdates() []*BackendTLSPolicyStatusUpdate {
+	var allUpdates []*BackendTLSPolicyStatusUpdate
+	for _, conditionsUpdate := range c.backendTrafficPolicyUpdates {
+		allUpdates = append(allUpdates, conditionsUpdate)
+	}
+	return allUpdates
+}
+
 // GatewayStatusAccessor returns a GatewayStatusUpdate that allows a client to build up a list of
 // status changes as well as a function to commit the change back to the cache when everything
 // is done. The commit function pattern is used so that the GatewayStatusUpdate does not need
@@ -209,6 +277,26 @@ func (c *Cache) GatewayStatusAccessor(nsName types.NamespacedName, generation in
 	}
 }
 
+// ListenerSetStatusAccessor returns a ListenerSetStatusUpdate that allows a client to build up
+// a list of status changes as well as a function to commit the change back to the cache when
+// everything is done, in the same way as GatewayStatusAccessor.
+func (c *Cache) ListenerSetStatusAccessor(nsName types.NamespacedName, generation int64, ls *gatewayapi_x_v1alpha1.ListenerSetStatus) (*ListenerSetStatusUpdate, func()) {
+	lu := &ListenerSetStatusUpdate{
+		FullName:           nsName,
+		Conditions:         make(map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition),
+		ExistingConditions: getListenerSetConditions(ls),
+		Generation:         generation,
+		TransitionTime:     meta_v1.NewTime(time.Now()),
+	}
+
+	return lu, func() {
+		if len(lu.Conditions) == 0 && len(lu.ListenerStatus) == 0 {
+			return
+		}
+		c.listenerSetUpdates[lu.FullName] = lu
+	}
+}
+
 // ProxyAccessor returns a ProxyUpdate that allows a client to build up a list of
 // errors and warnings to go onto the proxy as conditions, and a function to commit the change
 // back to the cache when everything is done.
@@ -217,41 +305,66 @@ func (c *Cache) GatewayStatusAccessor(nsName types.NamespacedName, generation in
 func (c *Cache) ProxyAccessor(proxy *contour_v1.HTTPProxy) (*ProxyUpdate, func()) {
 	pu := &ProxyUpdate{
 		Fullname:       k8s.NamespacedNameOf(proxy),
+		UID:            proxy.UID,
 		Generation:     proxy.Generation,
 		TransitionTime: meta_v1.NewTime(time.Now()),
 		Conditions:     make(map[ConditionType]*contour_v1.DetailedCondition),
 	}
 
 	return pu, func() {
-		if len(pu.Conditions) == 0 {
-			return
-		}
+		c.commitProxyUpdate(pu)
+	}
+}
 
-		_, ok := c.proxyUpdates[pu.Fullname]
-		if ok {
-			// When we're committing, if we already have a Valid Condition with an error, and we're trying to
-			// set the object back to Valid, skip the commit, as we've visited too far down.
-			// If this is removed, the status reporting for when a parent delegates to a child that delegates to itself
-			// will not work. Yes, I know, problems everywhere. I'm sorry.
-			// TODO(youngnick)#2968: This issue has more details.
-			if c.proxyUpdates[pu.Fullname].Conditions[ValidCondition].Status == contour_v1.ConditionFalse {
-				if pu.Conditions[ValidCondition].Status == contour_v1.ConditionTrue {
-					return
-				}
+// CommitProxyUpdate commits a copy of a ProxyUpdate built with another
+// Cache's ProxyAccessor, as if it had been built with this Cache's.
+func (c *Cache) CommitProxyUpdate(update *ProxyUpdate) {
+	pu := &ProxyUpdate{
+		Fullname:       update.Fullname,
+		UID:            update.UID,
+		Generation:     update.Generation,
+		TransitionTime: meta_v1.NewTime(time.Now()),
+		Vhost:          update.Vhost,
+		Conditions:     make(map[ConditionType]*contour_v1.DetailedCondition, len(update.Conditions)),
+	}
+	for condType, cond := range update.Conditions {
+		pu.Conditions[condType] = cond.DeepCopy()
+	}
+
+	c.commitProxyUpdate(pu)
+}
+
+func (c *Cache) commitProxyUpdate(pu *ProxyUpdate) {
+	if len(pu.Conditions) == 0 {
+		return
+	}
+
+	_, ok := c.proxyUpdates[pu.Fullname]
+	if ok {
+		// When we're committing, if we already have a Valid Condition with an error, and we're trying to
+		// set the object back to Valid, skip the commit, as we've visited too far down.
+		// If this is removed, the status reporting for when a parent delegates to a child that delegates to itself
+		// will not work. Yes, I know, problems everywhere. I'm sorry.
+		// TODO(youngnick)#2968: This issue has more details.
+		if c.proxyUpdates[pu.Fullname].Conditions[ValidCondition].Status == contour_v1.ConditionFalse {
+			if pu.Conditions[ValidCondition].Status == contour_v1.ConditionTrue {
+				return
 			}
 		}
-		c.proxyUpdates[pu.Fullname] = pu
 	}
+	c.proxyUpdates[pu.Fullname] = pu
 }
 
 // RouteConditionsAccessor returns a RouteStatusUpdate that allows a client to build up a list of
 // meta_v1.Conditions as well as a function to commit the change back to the cache when everything
 // is done. The commit function pattern is used so that the RouteStatusUpdate does not need
 // to know anything the cache internals.
-func (c *Cache) RouteConditionsAccessor(nsName types.NamespacedName, generation int64, resource client.Object) (*RouteStatusUpdate, func()) {
+// The RouteStatusUpdate holds the parent statuses for the given Gateway.
+func (c *Cache) RouteConditionsAccessor(gateway, nsName types.NamespacedName, uid types.UID, generation int64, resource client.Object) (*RouteStatusUpdate, func()) {
 	pu := &RouteStatusUpdate{
 		FullName:          nsName,
-		GatewayRef:        c.gatewayRef,
+		UID:               uid,
+		GatewayRef:        gateway,
 		GatewayController: c.gatewayController,
 		Generation:        generation,
 		TransitionTime:    meta_v1.NewTime(time.Now()),
@@ -262,7 +375,7 @@ func (c *Cache) RouteConditionsAccessor(nsName types.NamespacedName, generation
 		if len(pu.RouteParentStatuses) == 0 {
 			return
 		}
-		c.routeUpdates[pu.FullName] = pu
+		c.routeUpdates[gatewayObjectKey{gateway: gateway, object: pu.FullName}] = pu
 	}
 }
 
@@ -270,10 +383,11 @@ func (c *Cache) RouteConditionsAccessor(nsName types.NamespacedName, generation
 // to build up a list of metav1.Conditions as well as a function to commit the change back to the
 // cache when everything is done. The commit function pattern is used so that the
 // BackendTLSPolicyStatusUpdate does not need to know anything the cache internals.
-func (c *Cache) BackendTLSPolicyConditionsAccessor(nsName types.NamespacedName, generation int64) (*BackendTLSPolicyStatusUpdate, func()) {
+// The BackendTLSPolicyStatusUpdate holds the ancestor statuses for the given Gateway.
+func (c *Cache) BackendTLSPolicyConditionsAccessor(gateway, nsName types.NamespacedName, generation int64) (*BackendTLSPolicyStatusUpdate, func()) {
 	pu := &BackendTLSPolicyStatusUpdate{
 		FullName:          nsName,
-		GatewayRef:        c.gatewayRef,
+		GatewayRef:        gateway,
 		GatewayController: c.gatewayController,
 		Generation:        generation,
 		TransitionTime:    meta_v1.NewTime(time.Now()),
@@ -283,6 +397,65 @@ func (c *Cache) BackendTLSPolicyConditionsAccessor(nsName types.NamespacedName,
 		if len(pu.PolicyAncestorStatuses) == 0 {
 			return
 		}
-		c.backendTLSPolicyUpdates[pu.FullName] = pu
+		c.backendTLSPolicyUpdates[gatewayObjectKey{gateway: gateway, object: pu.FullName}] = pu
 	}
 }
+
+// BackendTrafficPolicyConditionsAccessor returns a BackendTLSPolicyStatusUpdate for a
+// XBackendTrafficPolicy, as both policies share the same status, and a function to
+// commit the change back to the cache when everything is done.
+// The BackendTLSPolicyStatusUpdate holds the ancestor statuses for the given Gateway.
+func (c *Cache) BackendTrafficPolicyConditionsAccessor(gateway, nsName types.NamespacedName, generation int64) (*BackendTLSPolicyStatusUpdate, func()) {
+	pu := &BackendTLSPolicyStatusUpdate{
+		FullName:          nsName,
+		GatewayRef:        gateway,
+		GatewayController: c.gatewayController,
+		Generation:        generation,
+		TransitionTime:    meta_v1.NewTime(time.Now()),
+	}
+
+	return pu, func() {
+		if len(pu.PolicyAncestorStatuses) == 0 {
+			return
+		}
+		c.backendTrafficPolicyUpdates[gatewayObjectKey{gateway: gateway, object: pu.FullName}] = pu
+	}
+}
+
+// gatewayObjectKey identifies the status update of an object for a Gateway.
+type gatewayObjectKey struct {
+	gateway types.NamespacedName
+	object  types.NamespacedName
+}
+
+// sortedGatewayObjectKeys returns the keys of m, sorted by object
+// and then by Gateway.
+func sortedGatewayObjectKeys[V any](m map[gatewayObjectKey]V) []gatewayObjectKey {
+	keys := make([]gatewayObjectKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].object != keys[j].object {
+			return keys[i].object.String() < keys[j].object.String()
+		}
+		return keys[i].gateway.String() < keys[j].gateway.String()
+	})
+	return keys
+}
+
+// chainMutators returns a StatusMutator that applies each of the given
+// mutators in turn, so the updates of several Gateways to an object are
+// written together.
+func chainMutators(mutators []k8s.StatusMutator) k8s.StatusMutator {
+	if len(mutators) == 1 {
+		return mutators[0]
+	}
+
+	return k8s.StatusMutatorFunc(func(obj client.Object) client.Object {
+		for _, m := range mutators {
+			obj = m.Mutate(obj)
+		}
+		return obj
+	})
+}
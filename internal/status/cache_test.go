@@ -14,16 +14,20 @@
 package status
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/gatewayapi"
 	"github.com/projectcontour/contour/internal/k8s"
 )
 
@@ -54,7 +58,7 @@ func TestCacheAcquisition(t *testing.T) {
 	httpRoute := &gatewayapi_v1.HTTPRoute{
 		ObjectMeta: fixture.ObjectMeta("test/httproute"),
 	}
-	cache := NewCache(types.NamespacedName{Name: "contour", Namespace: "projectcontour"}, "")
+	cache := NewCache("")
 
 	// Initial acquisition should be nil.
 	assert.Nil(t, cache.Get(proxy))
@@ -81,3 +85,44 @@ func TestCacheAcquisition(t *testing.T) {
 	assert.Len(t, cache.entries["ExtensionService"], 1)
 	assert.Len(t, cache.entries["HTTPRoute"], 1)
 }
+
+func TestCacheRouteUpdatesForSeveralGateways(t *testing.T) {
+	cache := NewCache("projectcontour.io/contour")
+
+	route := &gatewayapi_v1.HTTPRoute{
+		ObjectMeta: fixture.ObjectMeta("test/httproute"),
+	}
+	gateway1 := types.NamespacedName{Namespace: "projectcontour", Name: "gateway-1"}
+	gateway2 := types.NamespacedName{Namespace: "projectcontour", Name: "gateway-2"}
+	other := types.NamespacedName{Namespace: "projectcontour", Name: "other"}
+
+	for _, gateway := range []types.NamespacedName{gateway1, gateway2} {
+		ru, commit := cache.RouteConditionsAccessor(gateway, k8s.NamespacedNameOf(route), route.UID, route.Generation, &gatewayapi_v1.HTTPRoute{})
+		ru.StatusUpdateFor(gatewayapi.GatewayParentRef(gateway.Namespace, gateway.Name)).
+			AddCondition(gatewayapi_v1.RouteConditionAccepted, meta_v1.ConditionTrue, gatewayapi_v1.RouteReasonAccepted, "Accepted HTTPRoute")
+		commit()
+	}
+
+	assert.Len(t, cache.GetRouteUpdates(), 2)
+
+	// The updates of both Gateways are written together,
+	// keeping the statuses of other Gateways.
+	updates := cache.GetStatusUpdates()
+	require.Len(t, updates, 1)
+	assert.Equal(t, k8s.NamespacedNameOf(route), updates[0].NamespacedName)
+
+	existing := route.DeepCopy()
+	existing.Status.Parents = []gatewayapi_v1.RouteParentStatus{
+		{ParentRef: gatewayapi.GatewayParentRef(gateway1.Namespace, gateway1.Name)},
+		{ParentRef: gatewayapi.GatewayParentRef(other.Namespace, other.Name)},
+	}
+
+	updated, ok := updates[0].Mutator.Mutate(existing).(*gatewayapi_v1.HTTPRoute)
+	require.True(t, ok)
+
+	var parents []string
+	for _, rps := range updated.Status.Parents {
+		parents = append(parents, string(rps.ParentRef.Name)+":"+strconv.Itoa(len(rps.Conditions)))
+	}
+	assert.ElementsMatch(t, []string{"gateway-1:1", "gateway-2:1", "other:0"}, parents)
+}
@@ -59,6 +59,7 @@ type ExtensionCacheEntry struct {
 	ConditionCache
 
 	Name           types.NamespacedName
+	UID            types.UID
 	Generation     int64
 	TransitionTime meta_v1.Time
 }
@@ -112,6 +113,7 @@ func ExtensionAccessor(c *Cache, ext *contour_v1alpha1.ExtensionService) (*Exten
 	if entry == nil {
 		entry = &ExtensionCacheEntry{
 			Name:           k8s.NamespacedNameOf(ext),
+			UID:            ext.GetUID(),
 			Generation:     ext.GetGeneration(),
 			TransitionTime: meta_v1.NewTime(time.Now()),
 		}
@@ -26,6 +26,25 @@ import (
 
 const MessageValidGateway = "Valid Gateway"
 
+// ListenerConditionCertificateValid is a Contour-specific Listener condition
+// reporting on the expiry of the Listener's TLS certificate. It is only set
+// when the certificate has expired (status False) or expires soon (status True).
+const ListenerConditionCertificateValid gatewayapi_v1.ListenerConditionType = "CertificateValid"
+
+// GatewayConditionDataPlaneReady is a Contour-specific Gateway condition
+// reporting whether enough Envoys have acknowledged the configuration
+// built from the Gateway's current generation.
+const GatewayConditionDataPlaneReady gatewayapi_v1.GatewayConditionType = "DataPlaneReady"
+
+const (
+	// GatewayReasonAcknowledged is used with the DataPlaneReady condition
+	// when enough Envoys have acknowledged the Gateway's configuration.
+	GatewayReasonAcknowledged gatewayapi_v1.GatewayConditionReason = "Acknowledged"
+	// GatewayReasonPending is used with the DataPlaneReady condition
+	// while waiting for Envoys to acknowledge the Gateway's configuration.
+	GatewayReasonPending gatewayapi_v1.GatewayConditionReason = "Pending"
+)
+
 // GatewayStatusUpdate represents an atomic update to a
 // Gateway's status.
 type GatewayStatusUpdate struct {
@@ -61,36 +80,11 @@ func (gatewayUpdate *GatewayStatusUpdate) AddCondition(
 }
 
 func (gatewayUpdate *GatewayStatusUpdate) SetListenerSupportedKinds(listenerName string, kinds []gatewayapi_v1.Kind) {
-	if gatewayUpdate.ListenerStatus == nil {
-		gatewayUpdate.ListenerStatus = map[string]*gatewayapi_v1.ListenerStatus{}
-	}
-	if gatewayUpdate.ListenerStatus[listenerName] == nil {
-		gatewayUpdate.ListenerStatus[listenerName] = &gatewayapi_v1.ListenerStatus{
-			Name: gatewayapi_v1.SectionName(listenerName),
-		}
-	}
-
-	for _, kind := range kinds {
-		groupKind := gatewayapi_v1.RouteGroupKind{
-			Group: ptr.To(gatewayapi_v1.Group(gatewayapi_v1.GroupName)),
-			Kind:  kind,
-		}
-
-		gatewayUpdate.ListenerStatus[listenerName].SupportedKinds = append(gatewayUpdate.ListenerStatus[listenerName].SupportedKinds, groupKind)
-	}
+	setListenerSupportedKinds(listenerStatusFor(&gatewayUpdate.ListenerStatus, listenerName), kinds)
 }
 
 func (gatewayUpdate *GatewayStatusUpdate) SetListenerAttachedRoutes(listenerName string, numRoutes int32) {
-	if gatewayUpdate.ListenerStatus == nil {
-		gatewayUpdate.ListenerStatus = map[string]*gatewayapi_v1.ListenerStatus{}
-	}
-	if gatewayUpdate.ListenerStatus[listenerName] == nil {
-		gatewayUpdate.ListenerStatus[listenerName] = &gatewayapi_v1.ListenerStatus{
-			Name: gatewayapi_v1.SectionName(listenerName),
-		}
-	}
-
-	gatewayUpdate.ListenerStatus[listenerName].AttachedRoutes = numRoutes
+	listenerStatusFor(&gatewayUpdate.ListenerStatus, listenerName).AttachedRoutes = numRoutes
 }
 
 // AddListenerCondition adds a Condition for the specified listener.
@@ -101,17 +95,51 @@ func (gatewayUpdate *GatewayStatusUpdate) AddListenerCondition(
 	reason gatewayapi_v1.ListenerConditionReason,
 	message string,
 ) meta_v1.Condition {
-	if gatewayUpdate.ListenerStatus == nil {
-		gatewayUpdate.ListenerStatus = map[string]*gatewayapi_v1.ListenerStatus{}
+	return addListenerCondition(listenerStatusFor(&gatewayUpdate.ListenerStatus, listenerName), gatewayUpdate.Generation, cond, status, reason, message)
+}
+
+// ListenerConditions returns the Conditions added for the specified listener.
+func (gatewayUpdate *GatewayStatusUpdate) ListenerConditions(listenerName string) []meta_v1.Condition {
+	if listenerStatus := gatewayUpdate.ListenerStatus[listenerName]; listenerStatus != nil {
+		return listenerStatus.Conditions
 	}
-	if gatewayUpdate.ListenerStatus[listenerName] == nil {
-		gatewayUpdate.ListenerStatus[listenerName] = &gatewayapi_v1.ListenerStatus{
+	return nil
+}
+
+// listenerStatusFor returns the status of the named listener
+// in listenerStatus, adding it if it does not exist yet.
+func listenerStatusFor(listenerStatus *map[string]*gatewayapi_v1.ListenerStatus, listenerName string) *gatewayapi_v1.ListenerStatus {
+	if *listenerStatus == nil {
+		*listenerStatus = map[string]*gatewayapi_v1.ListenerStatus{}
+	}
+	if (*listenerStatus)[listenerName] == nil {
+		(*listenerStatus)[listenerName] = &gatewayapi_v1.ListenerStatus{
 			Name: gatewayapi_v1.SectionName(listenerName),
 		}
 	}
 
-	listenerStatus := gatewayUpdate.ListenerStatus[listenerName]
+	return (*listenerStatus)[listenerName]
+}
+
+func setListenerSupportedKinds(listenerStatus *gatewayapi_v1.ListenerStatus, kinds []gatewayapi_v1.Kind) {
+	for _, kind := range kinds {
+		groupKind := gatewayapi_v1.RouteGroupKind{
+			Group: ptr.To(gatewayapi_v1.Group(gatewayapi_v1.GroupName)),
+			Kind:  kind,
+		}
+
+		listenerStatus.SupportedKinds = append(listenerStatus.SupportedKinds, groupKind)
+	}
+}
 
+func addListenerCondition(
+	listenerStatus *gatewayapi_v1.ListenerStatus,
+	generation int64,
+	cond gatewayapi_v1.ListenerConditionType,
+	status meta_v1.ConditionStatus,
+	reason gatewayapi_v1.ListenerConditionReason,
+	message string,
+) meta_v1.Condition {
 	idx := -1
 	for i, existing := range listenerStatus.Conditions {
 		if existing.Type == string(cond) {
@@ -127,7 +155,7 @@ func (gatewayUpdate *GatewayStatusUpdate) AddListenerCondition(
 		Type:               string(cond),
 		Message:            message,
 		LastTransitionTime: meta_v1.NewTime(time.Now()),
-		ObservedGeneration: gatewayUpdate.Generation,
+		ObservedGeneration: generation,
 	}
 
 	if idx > -1 {
@@ -0,0 +1,173 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"fmt"
+	"time"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+)
+
+const MessageValidListenerSet = "Valid ListenerSet"
+
+// ListenerSetReasonParentNotProgrammed is used with the Programmed condition
+// of a ListenerSet when its parent Gateway is not programmed.
+const ListenerSetReasonParentNotProgrammed gatewayapi_x_v1alpha1.ListenerSetConditionReason = "ParentNotProgrammed"
+
+// ListenerSetStatusUpdate represents an atomic update to a
+// ListenerSet's status.
+type ListenerSetStatusUpdate struct {
+	FullName           types.NamespacedName
+	Conditions         map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition
+	ExistingConditions map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition
+	ListenerStatus     map[string]*gatewayapi_v1.ListenerStatus
+	Generation         int64
+	TransitionTime     meta_v1.Time
+}
+
+// AddCondition returns a meta_v1.Condition for a given ListenerSetConditionType.
+func (listenerSetUpdate *ListenerSetStatusUpdate) AddCondition(
+	cond gatewayapi_x_v1alpha1.ListenerSetConditionType,
+	status meta_v1.ConditionStatus,
+	reason gatewayapi_x_v1alpha1.ListenerSetConditionReason,
+	message string,
+) meta_v1.Condition {
+	if c, ok := listenerSetUpdate.Conditions[cond]; ok {
+		message = fmt.Sprintf("%s, %s", c.Message, message)
+	}
+
+	newCond := meta_v1.Condition{
+		Reason:             string(reason),
+		Status:             status,
+		Type:               string(cond),
+		Message:            message,
+		LastTransitionTime: meta_v1.NewTime(time.Now()),
+		ObservedGeneration: listenerSetUpdate.Generation,
+	}
+	listenerSetUpdate.Conditions[cond] = newCond
+	return newCond
+}
+
+func (listenerSetUpdate *ListenerSetStatusUpdate) SetListenerSupportedKinds(listenerName string, kinds []gatewayapi_v1.Kind) {
+	setListenerSupportedKinds(listenerStatusFor(&listenerSetUpdate.ListenerStatus, listenerName), kinds)
+}
+
+func (listenerSetUpdate *ListenerSetStatusUpdate) SetListenerAttachedRoutes(listenerName string, numRoutes int32) {
+	listenerStatusFor(&listenerSetUpdate.ListenerStatus, listenerName).AttachedRoutes = numRoutes
+}
+
+// AddListenerCondition adds a Condition for the specified listener.
+func (listenerSetUpdate *ListenerSetStatusUpdate) AddListenerCondition(
+	listenerName string,
+	cond gatewayapi_v1.ListenerConditionType,
+	status meta_v1.ConditionStatus,
+	reason gatewayapi_v1.ListenerConditionReason,
+	message string,
+) meta_v1.Condition {
+	return addListenerCondition(listenerStatusFor(&listenerSetUpdate.ListenerStatus, listenerName), listenerSetUpdate.Generation, cond, status, reason, message)
+}
+
+// ListenerConditions returns the Conditions added for the specified listener.
+func (listenerSetUpdate *ListenerSetStatusUpdate) ListenerConditions(listenerName string) []meta_v1.Condition {
+	if listenerStatus := listenerSetUpdate.ListenerStatus[listenerName]; listenerStatus != nil {
+		return listenerStatus.Conditions
+	}
+	return nil
+}
+
+func getListenerSetConditions(ls *gatewayapi_x_v1alpha1.ListenerSetStatus) map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition {
+	conditions := make(map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition)
+	for _, cond := range ls.Conditions {
+		if _, ok := conditions[gatewayapi_x_v1alpha1.ListenerSetConditionType(cond.Type)]; !ok {
+			conditions[gatewayapi_x_v1alpha1.ListenerSetConditionType(cond.Type)] = cond
+		}
+	}
+	return conditions
+}
+
+func (listenerSetUpdate *ListenerSetStatusUpdate) Mutate(obj client.Object) client.Object {
+	o, ok := obj.(*gatewayapi_x_v1alpha1.XListenerSet)
+	if !ok {
+		panic(fmt.Sprintf("Unsupported %T object %s/%s in ListenerSetStatusUpdate status mutator",
+			obj, listenerSetUpdate.FullName.Namespace, listenerSetUpdate.FullName.Name,
+		))
+	}
+
+	updated := o.DeepCopy()
+
+	var conditionsToWrite []meta_v1.Condition
+
+	for _, cond := range listenerSetUpdate.Conditions {
+		cond.ObservedGeneration = listenerSetUpdate.Generation
+		cond.LastTransitionTime = listenerSetUpdate.TransitionTime
+
+		// Keep a newer Condition of the same type, as
+		// our observation of it is stale.
+		var newerConditionExists bool
+		for _, existingCond := range listenerSetUpdate.ExistingConditions {
+			if existingCond.Type != cond.Type {
+				continue
+			}
+
+			if existingCond.ObservedGeneration > cond.ObservedGeneration {
+				conditionsToWrite = append(conditionsToWrite, existingCond)
+				newerConditionExists = true
+				break
+			}
+		}
+
+		if !newerConditionExists {
+			conditionsToWrite = append(conditionsToWrite, cond)
+		}
+	}
+
+	updated.Status.Conditions = conditionsToWrite
+
+	// Overwrite all listener statuses, in the order of the
+	// listeners in the spec, since we re-compute all of them
+	// for each ListenerSet status update.
+	var listenerStatusToWrite []gatewayapi_x_v1alpha1.ListenerEntryStatus
+	for _, listener := range updated.Spec.Listeners {
+		status := listenerSetUpdate.ListenerStatus[string(listener.Name)]
+		if status == nil {
+			continue
+		}
+
+		entryStatus := gatewayapi_x_v1alpha1.ListenerEntryStatus{
+			Name:           status.Name,
+			Port:           listener.Port,
+			SupportedKinds: status.SupportedKinds,
+			AttachedRoutes: status.AttachedRoutes,
+			Conditions:     status.Conditions,
+		}
+		if entryStatus.Conditions == nil {
+			// Conditions is a required field so we have to specify an empty slice here
+			entryStatus.Conditions = []meta_v1.Condition{}
+		}
+		if entryStatus.SupportedKinds == nil {
+			// SupportedKinds is a required field so we have to specify an empty slice here
+			entryStatus.SupportedKinds = []gatewayapi_v1.RouteGroupKind{}
+		}
+		listenerStatusToWrite = append(listenerStatusToWrite, entryStatus)
+	}
+
+	updated.Status.Listeners = listenerStatusToWrite
+
+	return updated
+}
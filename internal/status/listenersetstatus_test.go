@@ -0,0 +1,150 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+func TestListenerSetAddCondition(t *testing.T) {
+	listenerSetUpdate := ListenerSetStatusUpdate{
+		FullName:   k8s.NamespacedNameFrom("test/test"),
+		Conditions: make(map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition),
+		Generation: 7,
+	}
+
+	got := listenerSetUpdate.AddCondition(
+		gatewayapi_x_v1alpha1.ListenerSetConditionAccepted,
+		meta_v1.ConditionTrue,
+		gatewayapi_x_v1alpha1.ListenerSetReasonAccepted,
+		"ListenerSet is accepted",
+	)
+
+	assert.Equal(t, "ListenerSet is accepted", got.Message)
+	assert.Equal(t, string(gatewayapi_x_v1alpha1.ListenerSetReasonAccepted), got.Reason)
+	assert.Equal(t, string(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted), got.Type)
+	assert.Equal(t, meta_v1.ConditionTrue, got.Status)
+	assert.Equal(t, int64(7), got.ObservedGeneration)
+}
+
+func TestListenerSetAddListenerCondition(t *testing.T) {
+	var lsu ListenerSetStatusUpdate
+
+	assert.Empty(t, lsu.ListenerConditions("listener-1"))
+
+	lsu.AddListenerCondition("listener-1", gatewayapi_v1.ListenerConditionProgrammed, meta_v1.ConditionFalse, gatewayapi_v1.ListenerReasonInvalid, "message 1")
+	res := lsu.AddListenerCondition("listener-1", gatewayapi_v1.ListenerConditionProgrammed, meta_v1.ConditionFalse, gatewayapi_v1.ListenerReasonInvalid, "message 2")
+	assert.Equal(t, "message 1, message 2", res.Message)
+
+	require.Len(t, lsu.ListenerConditions("listener-1"), 1)
+	assert.Equal(t, "message 1, message 2", lsu.ListenerConditions("listener-1")[0].Message)
+	assert.Empty(t, lsu.ListenerConditions("listener-2"))
+}
+
+func TestListenerSetMutate(t *testing.T) {
+	transitionTime := meta_v1.NewTime(meta_v1.Now().Add(-1))
+
+	lsu := ListenerSetStatusUpdate{
+		FullName: k8s.NamespacedNameFrom("test/test"),
+		Conditions: map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition{
+			gatewayapi_x_v1alpha1.ListenerSetConditionAccepted: {
+				Type:   string(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted),
+				Status: meta_v1.ConditionTrue,
+				Reason: string(gatewayapi_x_v1alpha1.ListenerSetReasonAccepted),
+			},
+			gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed: {
+				Type:   string(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed),
+				Status: meta_v1.ConditionTrue,
+				Reason: string(gatewayapi_x_v1alpha1.ListenerSetReasonProgrammed),
+			},
+		},
+		ExistingConditions: map[gatewayapi_x_v1alpha1.ListenerSetConditionType]meta_v1.Condition{
+			gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed: {
+				Type:               string(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed),
+				Status:             meta_v1.ConditionFalse,
+				Reason:             string(gatewayapi_x_v1alpha1.ListenerSetReasonPending),
+				ObservedGeneration: 2,
+			},
+		},
+		Generation:     1,
+		TransitionTime: transitionTime,
+	}
+	lsu.SetListenerSupportedKinds("http", []gatewayapi_v1.Kind{"HTTPRoute"})
+	lsu.SetListenerAttachedRoutes("http", 3)
+	lsu.SetListenerSupportedKinds("tls", []gatewayapi_v1.Kind{"TLSRoute"})
+	// A listener status for a Listener not in the spec is not written.
+	lsu.SetListenerAttachedRoutes("removed", 1)
+
+	listenerSet := &gatewayapi_x_v1alpha1.XListenerSet{
+		Spec: gatewayapi_x_v1alpha1.ListenerSetSpec{
+			Listeners: []gatewayapi_x_v1alpha1.ListenerEntry{
+				{Name: "tls", Port: 443, Protocol: gatewayapi_v1.TLSProtocolType},
+				{Name: "no-status", Port: 8080, Protocol: gatewayapi_v1.HTTPProtocolType},
+				{Name: "http", Port: 80, Protocol: gatewayapi_v1.HTTPProtocolType},
+			},
+		},
+	}
+
+	got, ok := lsu.Mutate(listenerSet).(*gatewayapi_x_v1alpha1.XListenerSet)
+	require.True(t, ok)
+
+	assert.ElementsMatch(t, []meta_v1.Condition{
+		{
+			Type:               string(gatewayapi_x_v1alpha1.ListenerSetConditionAccepted),
+			Status:             meta_v1.ConditionTrue,
+			Reason:             string(gatewayapi_x_v1alpha1.ListenerSetReasonAccepted),
+			ObservedGeneration: 1,
+			LastTransitionTime: transitionTime,
+		},
+		// The existing condition is newer than ours, so is kept.
+		{
+			Type:               string(gatewayapi_x_v1alpha1.ListenerSetConditionProgrammed),
+			Status:             meta_v1.ConditionFalse,
+			Reason:             string(gatewayapi_x_v1alpha1.ListenerSetReasonPending),
+			ObservedGeneration: 2,
+		},
+	}, got.Status.Conditions)
+
+	assert.Equal(t, []gatewayapi_x_v1alpha1.ListenerEntryStatus{
+		{
+			Name: "tls",
+			Port: 443,
+			SupportedKinds: []gatewayapi_v1.RouteGroupKind{
+				{Group: ptr.To(gatewayapi_v1.Group(gatewayapi_v1.GroupName)), Kind: "TLSRoute"},
+			},
+			Conditions: []meta_v1.Condition{},
+		},
+		{
+			Name:           "http",
+			Port:           80,
+			AttachedRoutes: 3,
+			SupportedKinds: []gatewayapi_v1.RouteGroupKind{
+				{Group: ptr.To(gatewayapi_v1.Group(gatewayapi_v1.GroupName)), Kind: "HTTPRoute"},
+			},
+			Conditions: []meta_v1.Condition{},
+		},
+	}, got.Status.Listeners)
+
+	// The original object is not modified.
+	assert.Empty(t, listenerSet.Status.Listeners)
+}
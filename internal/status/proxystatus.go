@@ -34,6 +34,7 @@ const (
 // ProxyUpdate holds status updates for a particular HTTPProxy object
 type ProxyUpdate struct {
 	Fullname       types.NamespacedName
+	UID            types.UID
 	Generation     int64
 	TransitionTime meta_v1.Time
 	Vhost          string
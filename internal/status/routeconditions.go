@@ -48,12 +48,17 @@ const (
 // Route's status.
 type RouteStatusUpdate struct {
 	FullName            types.NamespacedName
+	UID                 types.UID
 	RouteParentStatuses []*gatewayapi_v1.RouteParentStatus
 	GatewayRef          types.NamespacedName
 	GatewayController   gatewayapi_v1.GatewayController
 	Resource            client.Object
 	Generation          int64
 	TransitionTime      meta_v1.Time
+
+	// ListenerSetRefs are the ListenerSets attached to the Gateway,
+	// whose parent statuses are also replaced by this update.
+	ListenerSetRefs []types.NamespacedName
 }
 
 // RouteParentStatusUpdate helps update a specific
@@ -142,6 +147,22 @@ func (r *RouteStatusUpdate) ConditionsForParentRef(parentRef gatewayapi_v1.Paren
 	return nil
 }
 
+// isRefToGateway returns whether the parent ref is to the Gateway,
+// or to one of the ListenerSets attached to it.
+func (r *RouteStatusUpdate) isRefToGateway(parentRef gatewayapi_v1.ParentReference) bool {
+	if gatewayapi.IsRefToGateway(parentRef, r.GatewayRef) {
+		return true
+	}
+
+	for _, listenerSet := range r.ListenerSetRefs {
+		if gatewayapi.IsRefToListenerSet(parentRef, r.FullName.Namespace, listenerSet) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 	var newRouteParentStatuses []gatewayapi_v1.RouteParentStatus
 
@@ -162,7 +183,7 @@ func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 
 		// Get all the RouteParentStatuses that are for other Gateways.
 		for _, rps := range o.Status.Parents {
-			if !gatewayapi.IsRefToGateway(rps.ParentRef, r.GatewayRef) {
+			if !r.isRefToGateway(rps.ParentRef) {
 				newRouteParentStatuses = append(newRouteParentStatuses, rps)
 			}
 		}
@@ -175,7 +196,7 @@ func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 
 		// Get all the RouteParentStatuses that are for other Gateways.
 		for _, rps := range o.Status.Parents {
-			if !gatewayapi.IsRefToGateway(rps.ParentRef, r.GatewayRef) {
+			if !r.isRefToGateway(rps.ParentRef) {
 				newRouteParentStatuses = append(newRouteParentStatuses, rps)
 			}
 		}
@@ -188,7 +209,7 @@ func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 
 		// Get all the RouteParentStatuses that are for other Gateways.
 		for _, rps := range o.Status.Parents {
-			if !gatewayapi.IsRefToGateway(rps.ParentRef, r.GatewayRef) {
+			if !r.isRefToGateway(rps.ParentRef) {
 				newRouteParentStatuses = append(newRouteParentStatuses, rps)
 			}
 		}
@@ -202,7 +223,7 @@ func (r *RouteStatusUpdate) Mutate(obj client.Object) client.Object {
 
 		// Get all the RouteParentStatuses that are for other Gateways.
 		for _, rps := range o.Status.Parents {
-			if !gatewayapi.IsRefToGateway(rps.ParentRef, r.GatewayRef) {
+			if !r.isRefToGateway(rps.ParentRef) {
 				newRouteParentStatuses = append(newRouteParentStatuses, rps)
 			}
 		}
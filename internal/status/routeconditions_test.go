@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/projectcontour/contour/internal/gatewayapi"
@@ -48,6 +49,45 @@ func TestHTTPRouteAddCondition(t *testing.T) {
 	assert.EqualValues(t, 7, got.ObservedGeneration)
 }
 
+func TestHTTPRouteMutateListenerSetRefs(t *testing.T) {
+	gatewayRef := gatewayapi.GatewayParentRef("projectcontour", "contour")
+	listenerSetRef := gatewayapi.ListenerSetParentRef("", "listenerset")
+	otherGatewayRef := gatewayapi.GatewayParentRef("projectcontour", "other")
+	otherListenerSetRef := gatewayapi.ListenerSetParentRef("", "other")
+
+	routeUpdate := RouteStatusUpdate{
+		FullName:        k8s.NamespacedNameFrom("default/test"),
+		GatewayRef:      types.NamespacedName{Namespace: "projectcontour", Name: "contour"},
+		ListenerSetRefs: []types.NamespacedName{{Namespace: "default", Name: "listenerset"}},
+		Generation:      7,
+	}
+	routeUpdate.StatusUpdateFor(gatewayRef).AddCondition(gatewayapi_v1.RouteConditionAccepted, meta_v1.ConditionTrue, "Valid", "Valid HTTPRoute")
+
+	route := &gatewayapi_v1.HTTPRoute{
+		Status: gatewayapi_v1.HTTPRouteStatus{
+			RouteStatus: gatewayapi_v1.RouteStatus{
+				Parents: []gatewayapi_v1.RouteParentStatus{
+					{ParentRef: gatewayRef},
+					{ParentRef: listenerSetRef},
+					{ParentRef: otherGatewayRef},
+					{ParentRef: otherListenerSetRef},
+				},
+			},
+		},
+	}
+
+	got, ok := routeUpdate.Mutate(route).(*gatewayapi_v1.HTTPRoute)
+	require.True(t, ok)
+
+	// The statuses for the Gateway and its ListenerSet are replaced,
+	// the statuses for other parents are kept.
+	var gotParentRefs []gatewayapi_v1.ParentReference
+	for _, rps := range got.Status.Parents {
+		gotParentRefs = append(gotParentRefs, rps.ParentRef)
+	}
+	assert.Equal(t, []gatewayapi_v1.ParentReference{gatewayRef, otherGatewayRef, otherListenerSetRef}, gotParentRefs)
+}
+
 func newCondition(t string, status meta_v1.ConditionStatus, reason, msg string, lt time.Time) meta_v1.Condition {
 	return meta_v1.Condition{
 		Type:               t,
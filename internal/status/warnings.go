@@ -0,0 +1,128 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+)
+
+// Warning is a problem with a routing object, to be recorded
+// as a Warning event on the object.
+type Warning struct {
+	// Object is the object the problem applies to. Only its
+	// type, name, namespace and UID are set.
+	Object client.Object
+
+	Reason  string
+	Message string
+}
+
+// Warn adds a Warning for the given object. It is used for kinds,
+// such as Ingress, which have no status of their own to report
+// problems in.
+func (c *Cache) Warn(obj client.Object, reason, message string) {
+	c.warnings = append(c.warnings, Warning{
+		Object:  obj,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// GetWarnings returns the Warnings added with Warn, along with a
+// Warning for each error on the Valid condition of HTTPProxies and
+// ExtensionServices, and for each false condition of the parent
+// statuses of Gateway API routes.
+func (c *Cache) GetWarnings() []Warning {
+	warnings := append([]Warning(nil), c.warnings...)
+
+	for _, pu := range c.proxyUpdates {
+		cond, ok := pu.Conditions[ValidCondition]
+		if !ok {
+			continue
+		}
+
+		proxy := &contour_v1.HTTPProxy{}
+		setObjectMeta(proxy, pu.Fullname.Namespace, pu.Fullname.Name, pu.UID)
+		warnings = append(warnings, conditionWarnings(proxy, cond)...)
+	}
+
+	for _, ru := range c.routeUpdates {
+		if ru.Resource == nil {
+			continue
+		}
+
+		route, ok := ru.Resource.DeepCopyObject().(client.Object)
+		if !ok {
+			continue
+		}
+		setObjectMeta(route, ru.FullName.Namespace, ru.FullName.Name, ru.UID)
+
+		for _, rps := range ru.RouteParentStatuses {
+			for _, cond := range rps.Conditions {
+				if cond.Status != meta_v1.ConditionFalse {
+					continue
+				}
+				warnings = append(warnings, Warning{
+					Object:  route,
+					Reason:  cond.Reason,
+					Message: cond.Message,
+				})
+			}
+		}
+	}
+
+	for _, byKind := range c.entries {
+		for _, e := range byKind {
+			entry, ok := e.(*ExtensionCacheEntry)
+			if !ok {
+				continue
+			}
+
+			cond, ok := entry.Conditions[ValidCondition]
+			if !ok {
+				continue
+			}
+
+			ext := &contour_v1alpha1.ExtensionService{}
+			setObjectMeta(ext, entry.Name.Namespace, entry.Name.Name, entry.UID)
+			warnings = append(warnings, conditionWarnings(ext, cond)...)
+		}
+	}
+
+	return warnings
+}
+
+// conditionWarnings returns a Warning for each error of the given condition.
+func conditionWarnings(obj client.Object, cond *contour_v1.DetailedCondition) []Warning {
+	var warnings []Warning
+	for _, e := range cond.Errors {
+		warnings = append(warnings, Warning{
+			Object:  obj,
+			Reason:  e.Reason,
+			Message: e.Message,
+		})
+	}
+	return warnings
+}
+
+func setObjectMeta(obj client.Object, namespace, name string, uid types.UID) {
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetUID(uid)
+}
@@ -0,0 +1,103 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networking_v1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+func TestGetWarnings(t *testing.T) {
+	cache := NewCache("projectcontour.io/contour")
+
+	ingress := &networking_v1.Ingress{
+		ObjectMeta: fixture.ObjectMeta("default/ingress"),
+	}
+	cache.Warn(ingress, "RouteNotValid", "path is not valid")
+
+	proxy := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "proxy", UID: "proxy-uid"},
+	}
+	pa, commit := cache.ProxyAccessor(proxy)
+	pa.ConditionFor(ValidCondition).AddError(contour_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid", "route.timeoutPolicy failed to parse")
+	commit()
+
+	validProxy := &contour_v1.HTTPProxy{
+		ObjectMeta: fixture.ObjectMeta("default/valid"),
+	}
+	pa, commit = cache.ProxyAccessor(validProxy)
+	pa.ConditionFor(ValidCondition)
+	commit()
+
+	route := &gatewayapi_v1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "route", UID: "route-uid"},
+	}
+	ru, commit := cache.RouteConditionsAccessor(types.NamespacedName{Name: "contour", Namespace: "projectcontour"}, k8s.NamespacedNameOf(route), route.UID, route.Generation, &gatewayapi_v1.HTTPRoute{})
+	rpsu := ru.StatusUpdateFor(gatewayapi_v1.ParentReference{Name: "contour"})
+	rpsu.AddCondition(gatewayapi_v1.RouteConditionAccepted, meta_v1.ConditionTrue, gatewayapi_v1.RouteReasonAccepted, "Accepted HTTPRoute")
+	rpsu.AddCondition(gatewayapi_v1.RouteConditionResolvedRefs, meta_v1.ConditionFalse, gatewayapi_v1.RouteReasonBackendNotFound, "service \"kuard\" is invalid")
+	commit()
+
+	ext := &contour_v1alpha1.ExtensionService{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "ext", UID: "ext-uid"},
+	}
+	entry, commit := ExtensionAccessor(&cache, ext)
+	entry.ConditionFor(ValidCondition).AddError(contour_v1.ConditionTypeSpecError, "TimeoutPolicyNotValid", "spec.timeoutPolicy failed to parse")
+	commit()
+
+	warnings := cache.GetWarnings()
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Object.GetName() < warnings[j].Object.GetName()
+	})
+
+	assert.Equal(t, []Warning{
+		{
+			Object: &contour_v1alpha1.ExtensionService{
+				ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "ext", UID: "ext-uid"},
+			},
+			Reason:  "TimeoutPolicyNotValid",
+			Message: "spec.timeoutPolicy failed to parse",
+		},
+		{
+			Object:  ingress,
+			Reason:  "RouteNotValid",
+			Message: "path is not valid",
+		},
+		{
+			Object: &contour_v1.HTTPProxy{
+				ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "proxy", UID: "proxy-uid"},
+			},
+			Reason:  "TimeoutPolicyNotValid",
+			Message: "route.timeoutPolicy failed to parse",
+		},
+		{
+			Object: &gatewayapi_v1.HTTPRoute{
+				ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "route", UID: "route-uid"},
+			},
+			Reason:  string(gatewayapi_v1.RouteReasonBackendNotFound),
+			Message: "service \"kuard\" is invalid",
+		},
+	}, warnings)
+}
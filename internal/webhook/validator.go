@@ -0,0 +1,365 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook contains a validating admission webhook which rejects
+// Contour resources that Contour would report as invalid.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	admission_v1 "k8s.io/api/admission/v1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/contourconfig"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/status"
+)
+
+// unresolvedReferenceReasons are the reasons of the errors that are
+// caused by a reference to an object that does not exist, or is not
+// delegated, yet. These are not reported by the webhook since the
+// referenced objects are commonly created after the referring ones.
+var unresolvedReferenceReasons = map[string]bool{
+	"CACertificateNotDelegated":           true,
+	"DelegationNotPermitted":              true,
+	"ExtensionServiceNotFound":            true,
+	"FallbackNotDelegated":                true,
+	"FallbackNotValid":                    true,
+	"IncludeNotFound":                     true,
+	"LocalJWKSInvalid":                    true,
+	"Orphaned":                            true,
+	"RemoteJWKSCACertificateNotDelegated": true,
+	"SecretNotValid":                      true,
+	"ServiceUnresolvedReference":          true,
+	"TLSUpstreamValidation":               true,
+}
+
+// sources are the kinds of objects HTTPProxies and ExtensionServices
+// are validated against. HTTPProxies are validated against the other
+// HTTPProxies of their delegation tree only, see delegationTree.
+var sources = []client.ObjectList{
+	&contour_v1.TLSCertificateDelegationList{},
+	&contour_v1alpha1.ExtensionServiceList{},
+	&core_v1.ServiceList{},
+	&core_v1.SecretList{},
+	&core_v1.ConfigMapList{},
+	&core_v1.NamespaceList{},
+}
+
+// Validator is an admission.Handler which validates HTTPProxy,
+// ExtensionService and ContourConfiguration objects.
+//
+// HTTPProxies and ExtensionServices are validated by building a DAG
+// from the objects in the cluster, with the object being admitted in
+// place of its current version, and rejecting the object if Contour
+// would set any errors on its Valid condition. Only the HTTPProxies of
+// the delegation tree of an admitted HTTPProxy are added to the DAG.
+// ContourConfigurations are rejected if Contour would fail to start
+// with them.
+type Validator struct {
+	logrus.FieldLogger
+
+	// Client reads the objects that the objects being
+	// admitted are validated against. It should read from
+	// an informer cache, as every admission lists them.
+	Client client.Reader
+
+	// NewBuilder returns a DAG builder with an empty
+	// source, configured the way Contour builds the DAG.
+	NewBuilder func() *dag.Builder
+
+	decoder admission.Decoder
+}
+
+// NewValidator returns a Validator which decodes admitted objects
+// using the given scheme.
+func NewValidator(log logrus.FieldLogger, c client.Reader, scheme *runtime.Scheme, newBuilder func() *dag.Builder) *Validator {
+	return &Validator{
+		FieldLogger: log,
+		Client:      c,
+		NewBuilder:  newBuilder,
+		decoder:     admission.NewDecoder(scheme),
+	}
+}
+
+var _ admission.Handler = &Validator{}
+
+// Handle implements admission.Handler.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admission_v1.Create && req.Operation != admission_v1.Update {
+		return admission.Allowed("")
+	}
+
+	var (
+		errs []string
+		err  error
+	)
+
+	switch req.Kind.Kind {
+	case "HTTPProxy":
+		proxy := &contour_v1.HTTPProxy{}
+		if err := v.decode(req, proxy); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		errs, err = v.ValidateHTTPProxy(ctx, proxy)
+	case "ExtensionService":
+		ext := &contour_v1alpha1.ExtensionService{}
+		if err := v.decode(req, ext); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		errs, err = v.ValidateExtensionService(ctx, ext)
+	case "ContourConfiguration":
+		contourConfig := &contour_v1alpha1.ContourConfiguration{}
+		if err := v.decode(req, contourConfig); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := ValidateContourConfiguration(contourConfig); err != nil {
+			errs = append(errs, err.Error())
+		}
+	default:
+		return admission.Allowed("")
+	}
+
+	if err != nil {
+		v.WithError(err).WithField("kind", req.Kind.Kind).
+			WithField("name", req.Name).WithField("namespace", req.Namespace).
+			Error("failed to validate object")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if len(errs) > 0 {
+		return admission.Denied(strings.Join(errs, "; "))
+	}
+
+	return admission.Allowed("")
+}
+
+// decode decodes the object being admitted into obj, defaulting
+// its namespace to the namespace of the request.
+func (v *Validator) decode(req admission.Request, obj client.Object) error {
+	if err := v.decoder.Decode(req, obj); err != nil {
+		return err
+	}
+
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(req.Namespace)
+	}
+
+	return nil
+}
+
+// ValidateHTTPProxy returns the messages of the errors Contour
+// would set on the Valid condition of the given HTTPProxy.
+func (v *Validator) ValidateHTTPProxy(ctx context.Context, proxy *contour_v1.HTTPProxy) ([]string, error) {
+	proxies, err := v.delegationTree(ctx, proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := v.build(ctx, proxy, proxies...)
+	if err != nil {
+		return nil, err
+	}
+
+	name := k8s.NamespacedNameOf(proxy)
+	for _, pu := range d.StatusCache.GetProxyUpdates() {
+		if pu.Fullname == name {
+			return errorMessages(pu.Conditions[status.ValidCondition]), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ValidateExtensionService returns the messages of the errors Contour
+// would set on the Valid condition of the given ExtensionService.
+func (v *Validator) ValidateExtensionService(ctx context.Context, ext *contour_v1alpha1.ExtensionService) ([]string, error) {
+	d, err := v.build(ctx, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry := d.StatusCache.Get(ext); entry != nil {
+		return errorMessages(entry.ConditionFor(status.ValidCondition)), nil
+	}
+
+	return nil, nil
+}
+
+// ValidateContourConfiguration returns an error if Contour
+// would fail to start with the given ContourConfiguration.
+func ValidateContourConfiguration(contourConfig *contour_v1alpha1.ContourConfiguration) error {
+	spec, err := contourconfig.OverlayOnDefaults(contourConfig.Spec)
+	if err != nil {
+		return err
+	}
+
+	return spec.Validate()
+}
+
+// delegationTree returns the HTTPProxies, other than proxy, whose status
+// depends on proxy, or that the status of proxy depends on: the roots
+// including it, directly or not, the other roots with the same FQDNs, and
+// the HTTPProxies these roots and proxy include, directly or not.
+func (v *Validator) delegationTree(ctx context.Context, proxy *contour_v1.HTTPProxy) ([]client.Object, error) {
+	var list contour_v1.HTTPProxyList
+	if err := v.Client.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list %T: %w", &list, err)
+	}
+
+	name := k8s.NamespacedNameOf(proxy)
+	proxies := map[types.NamespacedName]*contour_v1.HTTPProxy{name: proxy}
+	for i := range list.Items {
+		if n := k8s.NamespacedNameOf(&list.Items[i]); n != name {
+			proxies[n] = &list.Items[i]
+		}
+	}
+
+	includes := func(p *contour_v1.HTTPProxy) []types.NamespacedName {
+		var names []types.NamespacedName
+		for _, include := range p.Spec.Includes {
+			namespace := include.Namespace
+			if namespace == "" {
+				namespace = p.Namespace
+			}
+			names = append(names, types.NamespacedName{Namespace: namespace, Name: include.Name})
+		}
+		return names
+	}
+
+	parents := map[types.NamespacedName][]types.NamespacedName{}
+	for n, p := range proxies {
+		for _, child := range includes(p) {
+			parents[child] = append(parents[child], n)
+		}
+	}
+
+	// Find the roots including proxy.
+	fqdns := map[string]bool{}
+	visited := map[types.NamespacedName]bool{}
+	for queue := []types.NamespacedName{name}; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		if visited[n] {
+			continue
+		}
+		visited[n] = true
+
+		if vhost := proxies[n].Spec.VirtualHost; vhost != nil {
+			fqdns[vhost.Fqdn] = true
+		}
+		queue = append(queue, parents[n]...)
+	}
+
+	// Include proxy, the roots with the FQDNs found and, since
+	// the roots including proxy have them, their descendants.
+	queue := []types.NamespacedName{name}
+	for n, p := range proxies {
+		if p.Spec.VirtualHost != nil && fqdns[p.Spec.VirtualHost.Fqdn] {
+			queue = append(queue, n)
+		}
+	}
+
+	tree := map[types.NamespacedName]bool{}
+	for ; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		if tree[n] {
+			continue
+		}
+		tree[n] = true
+
+		for _, child := range includes(proxies[n]) {
+			if _, ok := proxies[child]; ok {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	var objs []client.Object
+	for n := range tree {
+		if n != name {
+			objs = append(objs, proxies[n])
+		}
+	}
+
+	return objs, nil
+}
+
+// build builds a DAG from the objects in the cluster and extra,
+// replacing the current version of obj, if any, with obj.
+func (v *Validator) build(ctx context.Context, obj client.Object, extra ...client.Object) (*dag.DAG, error) {
+	builder := v.NewBuilder()
+
+	for _, item := range extra {
+		builder.Source.Insert(item)
+	}
+
+	name := k8s.NamespacedNameOf(obj)
+	for _, source := range sources {
+		list := source.DeepCopyObject().(client.ObjectList)
+		if err := v.Client.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("failed to list %T: %w", list, err)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			item, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			if reflect.TypeOf(item) == reflect.TypeOf(obj) && k8s.NamespacedNameOf(item) == name {
+				continue
+			}
+			builder.Source.Insert(item)
+		}
+	}
+
+	builder.Source.Insert(obj)
+
+	return builder.Build(), nil
+}
+
+// errorMessages returns the messages of the errors of the given
+// condition, other than those caused by unresolved references.
+func errorMessages(cond *contour_v1.DetailedCondition) []string {
+	if cond == nil {
+		return nil
+	}
+
+	var msgs []string
+	for _, e := range cond.Errors {
+		if unresolvedReferenceReasons[e.Reason] {
+			continue
+		}
+		msgs = append(msgs, e.Message)
+	}
+
+	return msgs
+}
@@ -0,0 +1,284 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admission_v1 "k8s.io/api/admission/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+func TestValidatorHandle(t *testing.T) {
+	service := &core_v1.Service{
+		ObjectMeta: fixture.ObjectMeta("default/kuard"),
+		Spec: core_v1.ServiceSpec{
+			Ports: []core_v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	existingProxy := &contour_v1.HTTPProxy{
+		ObjectMeta: fixture.ObjectMeta("default/existing"),
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{Fqdn: "existing.projectcontour.io"},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{Name: "kuard", Port: 8080}},
+			}},
+		},
+	}
+
+	proxy := func(fqdn string, route contour_v1.Route) *contour_v1.HTTPProxy {
+		return &contour_v1.HTTPProxy{
+			TypeMeta:   meta_v1.TypeMeta{APIVersion: "projectcontour.io/v1", Kind: "HTTPProxy"},
+			ObjectMeta: fixture.ObjectMeta("default/proxy"),
+			Spec: contour_v1.HTTPProxySpec{
+				VirtualHost: &contour_v1.VirtualHost{Fqdn: fqdn},
+				Routes:      []contour_v1.Route{route},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		operation   admission_v1.Operation
+		obj         client.Object
+		wantAllowed bool
+		wantMessage string
+	}{
+		"valid httpproxy": {
+			operation: admission_v1.Create,
+			obj: proxy("proxy.projectcontour.io", contour_v1.Route{
+				Services: []contour_v1.Service{{Name: "kuard", Port: 8080}},
+			}),
+			wantAllowed: true,
+		},
+		"httpproxy with invalid timeout policy": {
+			operation: admission_v1.Create,
+			obj: proxy("proxy.projectcontour.io", contour_v1.Route{
+				Services:      []contour_v1.Service{{Name: "kuard", Port: 8080}},
+				TimeoutPolicy: &contour_v1.TimeoutPolicy{Response: "invalid"},
+			}),
+			wantMessage: "route.timeoutPolicy failed to parse",
+		},
+		"httpproxy with duplicate fqdn": {
+			operation: admission_v1.Update,
+			obj: proxy("existing.projectcontour.io", contour_v1.Route{
+				Services: []contour_v1.Service{{Name: "kuard", Port: 8080}},
+			}),
+			wantMessage: `fqdn "existing.projectcontour.io" is used in multiple HTTPProxies`,
+		},
+		"httpproxy referencing missing service": {
+			operation: admission_v1.Create,
+			obj: proxy("proxy.projectcontour.io", contour_v1.Route{
+				Services: []contour_v1.Service{{Name: "missing", Port: 8080}},
+			}),
+			wantAllowed: true,
+		},
+		"httpproxy delete": {
+			operation: admission_v1.Delete,
+			obj: proxy("proxy.projectcontour.io", contour_v1.Route{
+				TimeoutPolicy: &contour_v1.TimeoutPolicy{Response: "invalid"},
+			}),
+			wantAllowed: true,
+		},
+		"extensionservice with invalid timeout policy": {
+			operation: admission_v1.Create,
+			obj: &contour_v1alpha1.ExtensionService{
+				TypeMeta:   meta_v1.TypeMeta{APIVersion: "projectcontour.io/v1alpha1", Kind: "ExtensionService"},
+				ObjectMeta: fixture.ObjectMeta("default/ext"),
+				Spec: contour_v1alpha1.ExtensionServiceSpec{
+					Services:      []contour_v1alpha1.ExtensionServiceTarget{{Name: "kuard", Port: 8080}},
+					TimeoutPolicy: &contour_v1.TimeoutPolicy{Response: "invalid"},
+				},
+			},
+			wantMessage: "spec.timeoutPolicy failed to parse",
+		},
+		"valid contourconfiguration": {
+			operation: admission_v1.Create,
+			obj: &contour_v1alpha1.ContourConfiguration{
+				TypeMeta:   meta_v1.TypeMeta{APIVersion: "projectcontour.io/v1alpha1", Kind: "ContourConfiguration"},
+				ObjectMeta: fixture.ObjectMeta("projectcontour/contour"),
+			},
+			wantAllowed: true,
+		},
+		"invalid contourconfiguration": {
+			operation: admission_v1.Update,
+			obj: &contour_v1alpha1.ContourConfiguration{
+				TypeMeta:   meta_v1.TypeMeta{APIVersion: "projectcontour.io/v1alpha1", Kind: "ContourConfiguration"},
+				ObjectMeta: fixture.ObjectMeta("projectcontour/contour"),
+				Spec: contour_v1alpha1.ContourConfigurationSpec{
+					Envoy: &contour_v1alpha1.EnvoyConfig{
+						Cluster: &contour_v1alpha1.ClusterParameters{
+							DNSLookupFamily: "invalid",
+						},
+					},
+				},
+			},
+			wantMessage: `invalid cluster dns family type "invalid"`,
+		},
+	}
+
+	scheme, err := k8s.NewContourScheme()
+	require.NoError(t, err)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(service.DeepCopy(), existingProxy.DeepCopy()).
+				Build()
+
+			log := fixture.NewTestLogger(t)
+			v := NewValidator(log, c, scheme, func() *dag.Builder {
+				return &dag.Builder{
+					Source: dag.KubernetesCache{
+						FieldLogger: log,
+					},
+					Processors: []dag.Processor{
+						&dag.ListenerProcessor{},
+						&dag.ExtensionServiceProcessor{FieldLogger: log},
+						&dag.HTTPProxyProcessor{},
+					},
+				}
+			})
+
+			resp := v.Handle(context.Background(), request(t, tc.operation, tc.obj))
+			assert.Equal(t, tc.wantAllowed, resp.Allowed)
+			if tc.wantMessage != "" {
+				require.NotNil(t, resp.Result)
+				assert.Contains(t, resp.Result.Message, tc.wantMessage)
+			}
+		})
+	}
+}
+
+func TestValidatorDelegationTree(t *testing.T) {
+	proxy := func(name, fqdn string, includes ...contour_v1.Include) *contour_v1.HTTPProxy {
+		p := &contour_v1.HTTPProxy{
+			ObjectMeta: fixture.ObjectMeta(name),
+			Spec: contour_v1.HTTPProxySpec{
+				Includes: includes,
+			},
+		}
+		if fqdn != "" {
+			p.Spec.VirtualHost = &contour_v1.VirtualHost{Fqdn: fqdn}
+		}
+		return p
+	}
+
+	objs := []client.Object{
+		proxy("default/root", "root.projectcontour.io",
+			contour_v1.Include{Name: "child", Namespace: "teams"},
+			contour_v1.Include{Name: "missing"}),
+		proxy("teams/child", "", contour_v1.Include{Name: "grandchild"}),
+		proxy("teams/grandchild", ""),
+		proxy("default/duplicate", "root.projectcontour.io"),
+		proxy("default/other", "other.projectcontour.io", contour_v1.Include{Name: "other-child"}),
+		proxy("default/other-child", ""),
+		proxy("default/orphan", ""),
+	}
+
+	tests := map[string]struct {
+		proxy *contour_v1.HTTPProxy
+		want  []string
+	}{
+		"included proxy": {
+			proxy: proxy("teams/grandchild", ""),
+			want:  []string{"default/duplicate", "default/root", "teams/child"},
+		},
+		"root proxy": {
+			proxy: proxy("default/other", "other.projectcontour.io", contour_v1.Include{Name: "other-child"}),
+			want:  []string{"default/other-child"},
+		},
+		"root proxy changing fqdn": {
+			proxy: proxy("default/other", "root.projectcontour.io"),
+			want:  []string{"default/duplicate", "default/root", "teams/child", "teams/grandchild"},
+		},
+		"new proxy": {
+			proxy: proxy("default/new", "new.projectcontour.io", contour_v1.Include{Name: "orphan"}),
+			want:  []string{"default/orphan"},
+		},
+		"orphaned proxy": {
+			proxy: proxy("default/orphan", ""),
+			want:  nil,
+		},
+	}
+
+	scheme, err := k8s.NewContourScheme()
+	require.NoError(t, err)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			v := NewValidator(fixture.NewTestLogger(t), c, scheme, nil)
+
+			tree, err := v.delegationTree(context.Background(), tc.proxy)
+			require.NoError(t, err)
+
+			var got []string
+			for _, obj := range tree {
+				got = append(got, k8s.NamespacedNameOf(obj).String())
+			}
+			assert.ElementsMatch(t, tc.want, got)
+		})
+	}
+}
+
+func request(t *testing.T, operation admission_v1.Operation, obj client.Object) admission.Request {
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	gvk := obj.GetObjectKind().GroupVersionKind()
+
+	req := admission.Request{
+		AdmissionRequest: admission_v1.AdmissionRequest{
+			Operation: operation,
+			Kind: meta_v1.GroupVersionKind{
+				Group:   gvk.Group,
+				Version: gvk.Version,
+				Kind:    gvk.Kind,
+			},
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+
+	if operation == admission_v1.Delete {
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	} else {
+		req.Object = runtime.RawExtension{Raw: raw}
+	}
+
+	return req
+}
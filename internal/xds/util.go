@@ -14,6 +14,7 @@
 package xds
 
 import (
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -38,3 +39,31 @@ func ClusterLoadAssignmentName(service types.NamespacedName, portName string) st
 
 	return strings.Join(name, "/")
 }
+
+// ServiceImportClusterLoadAssignmentName generates the name used for the EDS
+// ClusterLoadAssignment of a multi-cluster ServiceImport port. It is prefixed
+// so that it does not clash with the name for a Service of the same name.
+func ServiceImportClusterLoadAssignmentName(serviceImport types.NamespacedName, portName string) string {
+	return "serviceimport/" + ClusterLoadAssignmentName(serviceImport, portName)
+}
+
+// TrafficShiftClusterLoadAssignmentName generates the name used for the EDS
+// ClusterLoadAssignment that balances the traffic shift of an HTTPProxy
+// between a port of its stable Service and a port of its canary Service.
+func TrafficShiftClusterLoadAssignmentName(proxy types.NamespacedName, stable, canary string, stablePort, canaryPort int32) string {
+	return TrafficShiftClusterLoadAssignmentPrefix(proxy, stable, canary) +
+		strconv.Itoa(int(stablePort)) + "/" + strconv.Itoa(int(canaryPort))
+}
+
+// TrafficShiftClusterLoadAssignmentPrefix returns the prefix of the names of
+// the ClusterLoadAssignments of the traffic shifts of an HTTPProxy from the
+// stable to the canary Service, whichever their ports.
+func TrafficShiftClusterLoadAssignmentPrefix(proxy types.NamespacedName, stable, canary string) string {
+	return "trafficshift/" + strings.Join([]string{proxy.Namespace, proxy.Name, stable, canary}, "/") + "/"
+}
+
+// LocalitySubZone returns the sub zone of the locality holding the endpoints
+// of a Service in a ClusterLoadAssignment that balances several Services.
+func LocalitySubZone(service types.NamespacedName) string {
+	return service.Namespace + "/" + service.Name
+}
@@ -16,32 +16,65 @@ package v3
 import (
 	"context"
 	"fmt"
+	"net"
 
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	envoy_server_v3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/peer"
 )
 
 // NewRequestLoggingCallbacks returns an implementation of the Envoy xDS server
 // callbacks to provide request detail logging. Currently only xDS State of the
 // World callbacks are implemented.
 func NewRequestLoggingCallbacks(log logrus.FieldLogger) envoy_server_v3.Callbacks {
+	return NewCallbacks(log, nil)
+}
+
+// NewCallbacks returns an implementation of the Envoy xDS server callbacks
+// that logs request details like NewRequestLoggingCallbacks and, if
+// streamMetrics is not nil, records metrics about the connected Envoy
+// nodes. Currently only xDS State of the World callbacks are implemented.
+func NewCallbacks(log logrus.FieldLogger, streamMetrics *StreamMetrics) envoy_server_v3.Callbacks {
 	return &envoy_server_v3.CallbackFuncs{
-		StreamOpenFunc: func(_ context.Context, streamID int64, typeURL string) error {
+		StreamOpenFunc: func(ctx context.Context, streamID int64, typeURL string) error {
 			logStreamOpenDetails(log, streamID, typeURL)
+			if streamMetrics != nil {
+				streamMetrics.streamOpen(streamID, peerAddress(ctx))
+			}
 			return nil
 		},
 		StreamClosedFunc: func(streamID int64, node *envoy_config_core_v3.Node) {
 			logStreamClosedDetails(log, streamID, node)
+			if streamMetrics != nil {
+				streamMetrics.streamClosed(streamID)
+			}
 		},
-		StreamRequestFunc: func(_ int64, req *envoy_service_discovery_v3.DiscoveryRequest) error {
+		StreamRequestFunc: func(streamID int64, req *envoy_service_discovery_v3.DiscoveryRequest) error {
 			logDiscoveryRequestDetails(log, req)
+			if streamMetrics != nil {
+				streamMetrics.streamRequest(streamID, req)
+			}
 			return nil
 		},
 	}
 }
 
+// peerAddress returns the IP address of the gRPC peer of ctx, or the
+// empty string if it is unknown.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
 // Helper function for use in the Envoy xDS server callbacks to
 // log details of opened streams.
 func logStreamOpenDetails(l logrus.FieldLogger, streamID int64, typeURL string) {
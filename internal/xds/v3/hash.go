@@ -34,3 +34,28 @@ func (c ConstantHash) String() string {
 }
 
 var Hash = ConstantHash{}
+
+// NodeGroupHash is a node ID hasher that groups instances of Envoy
+// by their node cluster, set with Envoy's service-cluster flag. An
+// Envoy whose node cluster is one of the hash's groups is given that
+// group's snapshot; any other Envoy is given the CONSTANT_HASH_VALUE
+// snapshot.
+type NodeGroupHash struct {
+	groups map[string]struct{}
+}
+
+// NewNodeGroupHash returns a NodeGroupHash for the given groups.
+func NewNodeGroupHash(groups []string) NodeGroupHash {
+	h := NodeGroupHash{groups: map[string]struct{}{}}
+	for _, group := range groups {
+		h.groups[group] = struct{}{}
+	}
+	return h
+}
+
+func (h NodeGroupHash) ID(node *envoy_config_core_v3.Node) string {
+	if _, ok := h.groups[node.GetCluster()]; ok {
+		return node.GetCluster()
+	}
+	return CONSTANT_HASH_VALUE
+}
@@ -0,0 +1,256 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+
+	"github.com/projectcontour/contour/internal/metrics"
+)
+
+// StreamMetrics records metrics about the Envoy nodes connected to the
+// xDS server: the number of connected nodes, how long they take to ACK
+// a new snapshot and how often they NACK a response. It also tracks which
+// snapshots each node has ACKed, see NodesAcked, and the address each
+// node connects from, see NodeAddresses.
+type StreamMetrics struct {
+	metrics *metrics.Metrics
+
+	// now is used in place of time.Now, for testing.
+	now func() time.Time
+
+	mu        sync.Mutex
+	streams   map[int64]*streamState
+	snapshots map[string]snapshotVersion
+}
+
+// streamState holds the details of an open xDS stream.
+type streamState struct {
+	opened      time.Time
+	addr        string
+	nodeID      string
+	nodeCluster string
+
+	// acked holds the last version ACKed for each type URL
+	// requested on the stream.
+	acked map[string]string
+
+	// ackedSet holds the time the last snapshot ACKed for each
+	// type URL was set.
+	ackedSet map[string]time.Time
+}
+
+// snapshotVersion holds the version of the latest snapshot set for
+// a type URL, and when it was set.
+type snapshotVersion struct {
+	version string
+	set     time.Time
+}
+
+// NewStreamMetrics returns a StreamMetrics recording to m.
+func NewStreamMetrics(m *metrics.Metrics) *StreamMetrics {
+	return &StreamMetrics{
+		metrics:   m,
+		now:       time.Now,
+		streams:   map[int64]*streamState{},
+		snapshots: map[string]snapshotVersion{},
+	}
+}
+
+// SnapshotSet records that a snapshot with the given version was set
+// for the given type URLs, so the time Envoy takes to ACK it can be
+// measured. It should be called before the snapshot is set, so that
+// ACKs can't arrive before it is recorded.
+func (s *StreamMetrics) SnapshotSet(version string, typeURLs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for _, typeURL := range typeURLs {
+		s.snapshots[typeURL] = snapshotVersion{version: version, set: now}
+
+		// If a stream ACKed the version before it was recorded
+		// anyway, it still has to count as ACKing it.
+		for _, stream := range s.streams {
+			if stream.acked[typeURL] == version {
+				stream.ackedSet[typeURL] = now
+			}
+		}
+	}
+}
+
+func (s *StreamMetrics) streamOpen(streamID int64, addr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.streams[streamID] = &streamState{
+		opened:   s.now(),
+		addr:     addr,
+		acked:    map[string]string{},
+		ackedSet: map[string]time.Time{},
+	}
+}
+
+func (s *StreamMetrics) streamClosed(streamID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream, ok := s.streams[streamID]
+	if !ok {
+		return
+	}
+
+	for typeURL := range stream.acked {
+		s.metrics.SetXDSConnectedClients(typeURL, stream.nodeCluster, -1)
+	}
+	delete(s.streams, streamID)
+}
+
+func (s *StreamMetrics) streamRequest(streamID int64, req *envoy_service_discovery_v3.DiscoveryRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream, ok := s.streams[streamID]
+	if !ok {
+		return
+	}
+
+	// Envoy may only send the node on the first request of a stream.
+	if len(stream.acked) == 0 {
+		stream.nodeID = req.GetNode().GetId()
+		stream.nodeCluster = req.GetNode().GetCluster()
+	}
+
+	typeURL := req.GetTypeUrl()
+	lastAcked, ok := stream.acked[typeURL]
+	if !ok {
+		s.metrics.SetXDSConnectedClients(typeURL, stream.nodeCluster, 1)
+		stream.acked[typeURL] = ""
+	}
+
+	switch {
+	case req.GetResponseNonce() == "":
+		// Initial request, not a response to an update.
+	case req.GetErrorDetail() != nil:
+		s.metrics.SetXDSNack(typeURL, stream.nodeCluster)
+	case req.GetVersionInfo() != lastAcked:
+		stream.acked[typeURL] = req.GetVersionInfo()
+
+		snapshot, ok := s.snapshots[typeURL]
+		if !ok || snapshot.version != req.GetVersionInfo() {
+			return
+		}
+		stream.ackedSet[typeURL] = snapshot.set
+
+		// Only measure snapshots that were set while the stream was
+		// open, otherwise this measures how long the node took to
+		// connect rather than how long the update took to apply.
+		if !snapshot.set.Before(stream.opened) {
+			s.metrics.SetXDSAckDuration(typeURL, s.now().Sub(snapshot.set))
+		}
+	}
+}
+
+// SnapshotVersion returns the version of the latest snapshot set for
+// any of the given type URLs, and when it was set. The version is empty
+// if no snapshot was set for them.
+func (s *StreamMetrics) SnapshotVersion(typeURLs ...string) (string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest snapshotVersion
+	for _, typeURL := range typeURLs {
+		if snapshot, ok := s.snapshots[typeURL]; ok && !snapshot.set.Before(latest.set) {
+			latest = snapshot
+		}
+	}
+	return latest.version, latest.set
+}
+
+// NodesAcked returns the number of connected Envoy nodes that have
+// ACKed a snapshot set at or after since, for each of the given type
+// URLs the node has requested. Nodes that have requested none of the
+// type URLs are not counted. If cluster is not empty, only nodes of
+// that node cluster are counted.
+func (s *StreamMetrics) NodesAcked(cluster string, since time.Time, typeURLs ...string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A node may use a separate stream per type URL, so
+	// group the streams by node before checking them.
+	nodes := map[string][]*streamState{}
+	for _, stream := range s.streams {
+		if cluster != "" && stream.nodeCluster != cluster {
+			continue
+		}
+		nodes[stream.nodeID] = append(nodes[stream.nodeID], stream)
+	}
+
+	acked := 0
+	for _, streams := range nodes {
+		requested, ok := 0, true
+		for _, typeURL := range typeURLs {
+			var latest time.Time
+			var found bool
+			for _, stream := range streams {
+				if _, subscribed := stream.acked[typeURL]; !subscribed {
+					continue
+				}
+				found = true
+				if set := stream.ackedSet[typeURL]; set.After(latest) {
+					latest = set
+				}
+			}
+			if !found {
+				continue
+			}
+			requested++
+			if latest.Before(since) {
+				ok = false
+			}
+		}
+		if requested > 0 && ok {
+			acked++
+		}
+	}
+
+	return acked
+}
+
+// NodeAddresses returns the sorted IP addresses the connected Envoy
+// nodes connect from, one per node. Streams whose address is unknown,
+// or which have not identified their node yet, are ignored.
+func (s *StreamMetrics) NodeAddresses() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nodes := map[string]string{}
+	for _, stream := range s.streams {
+		if stream.addr == "" || stream.nodeID == "" {
+			continue
+		}
+		nodes[stream.nodeID] = stream.addr
+	}
+
+	addrs := make([]string, 0, len(nodes))
+	for _, addr := range nodes {
+		addrs = append(addrs, addr)
+	}
+	slices.Sort(addrs)
+	return slices.Compact(addrs)
+}
@@ -0,0 +1,245 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+	"time"
+
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/status"
+
+	"github.com/projectcontour/contour/internal/metrics"
+)
+
+func TestStreamMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	sm := NewStreamMetrics(metrics.NewMetrics(registry))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sm.now = func() time.Time { return now }
+
+	gather := func(name string) []*io_prometheus_client.Metric {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, mf := range families {
+			if mf.GetName() == name {
+				return mf.GetMetric()
+			}
+		}
+		return nil
+	}
+
+	connected := func() map[string]float64 {
+		got := map[string]float64{}
+		for _, m := range gather(metrics.XDSConnectedClientsGauge) {
+			got[m.GetLabel()[1].GetValue()+"/"+m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+		}
+		return got
+	}
+
+	// An ADS stream requesting clusters and listeners.
+	sm.streamOpen(1, "")
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		Node:    &envoy_config_core_v3.Node{Id: "envoy-1", Cluster: "contour"},
+		TypeUrl: envoy_resource_v3.ClusterType,
+	})
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		TypeUrl: envoy_resource_v3.ListenerType,
+	})
+
+	assert.Equal(t, map[string]float64{
+		envoy_resource_v3.ClusterType + "/contour":  1,
+		envoy_resource_v3.ListenerType + "/contour": 1,
+	}, connected())
+
+	// A snapshot is set and ACKed a second later.
+	now = now.Add(time.Minute)
+	sm.SnapshotSet("v1", envoy_resource_v3.ClusterType, envoy_resource_v3.ListenerType)
+	now = now.Add(time.Second)
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		TypeUrl:       envoy_resource_v3.ClusterType,
+		VersionInfo:   "v1",
+		ResponseNonce: "1",
+	})
+	// Repeated requests for an ACKed version are not measured again.
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		TypeUrl:       envoy_resource_v3.ClusterType,
+		VersionInfo:   "v1",
+		ResponseNonce: "2",
+	})
+
+	acks := gather(metrics.XDSAckSeconds)
+	require.Len(t, acks, 1)
+	assert.Equal(t, envoy_resource_v3.ClusterType, acks[0].GetLabel()[0].GetValue())
+	assert.Equal(t, uint64(1), acks[0].GetHistogram().GetSampleCount())
+	assert.InDelta(t, 1.0, acks[0].GetHistogram().GetSampleSum(), 0.0001)
+
+	// The listener update is rejected.
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		TypeUrl:       envoy_resource_v3.ListenerType,
+		ResponseNonce: "3",
+		ErrorDetail:   &status.Status{Message: "bad listener"},
+	})
+
+	nacks := gather(metrics.XDSNackTotal)
+	require.Len(t, nacks, 1)
+	assert.Equal(t, float64(1), nacks[0].GetCounter().GetValue())
+
+	// A stream opened after the snapshot was set doesn't measure it.
+	sm.streamOpen(2, "")
+	sm.streamRequest(2, &envoy_service_discovery_v3.DiscoveryRequest{
+		Node:          &envoy_config_core_v3.Node{Id: "envoy-2", Cluster: "contour"},
+		TypeUrl:       envoy_resource_v3.ClusterType,
+		VersionInfo:   "v1",
+		ResponseNonce: "1",
+	})
+	assert.Equal(t, uint64(1), gather(metrics.XDSAckSeconds)[0].GetHistogram().GetSampleCount())
+	assert.Equal(t, float64(2), connected()[envoy_resource_v3.ClusterType+"/contour"])
+
+	sm.streamClosed(1)
+	sm.streamClosed(2)
+
+	assert.Equal(t, map[string]float64{
+		envoy_resource_v3.ClusterType + "/contour":  0,
+		envoy_resource_v3.ListenerType + "/contour": 0,
+	}, connected())
+}
+
+func TestStreamMetricsNodesAcked(t *testing.T) {
+	sm := NewStreamMetrics(metrics.NewMetrics(prometheus.NewRegistry()))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sm.now = func() time.Time { return now }
+
+	ack := func(streamID int64, typeURL, version string) {
+		sm.streamRequest(streamID, &envoy_service_discovery_v3.DiscoveryRequest{
+			TypeUrl:       typeURL,
+			VersionInfo:   version,
+			ResponseNonce: version,
+		})
+	}
+
+	// envoy-1 uses ADS, envoy-2 uses a stream per type URL.
+	sm.streamOpen(1, "")
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		Node:    &envoy_config_core_v3.Node{Id: "envoy-1"},
+		TypeUrl: envoy_resource_v3.ListenerType,
+	})
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		TypeUrl: envoy_resource_v3.RouteType,
+	})
+	for id, typeURL := range map[int64]string{2: envoy_resource_v3.ListenerType, 3: envoy_resource_v3.RouteType} {
+		sm.streamOpen(id, "")
+		sm.streamRequest(id, &envoy_service_discovery_v3.DiscoveryRequest{
+			Node:    &envoy_config_core_v3.Node{Id: "envoy-2", Cluster: "projectcontour/gateway"},
+			TypeUrl: typeURL,
+		})
+	}
+
+	since := now
+	sm.SnapshotSet("v1", envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType)
+	assert.Equal(t, 0, sm.NodesAcked("", since, envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+
+	// Both type URLs must be ACKed.
+	ack(1, envoy_resource_v3.ListenerType, "v1")
+	assert.Equal(t, 0, sm.NodesAcked("", since, envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+	ack(1, envoy_resource_v3.RouteType, "v1")
+	assert.Equal(t, 1, sm.NodesAcked("", since, envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+
+	ack(2, envoy_resource_v3.ListenerType, "v1")
+	ack(3, envoy_resource_v3.RouteType, "v1")
+	assert.Equal(t, 2, sm.NodesAcked("", since, envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+
+	// Only nodes of the given cluster are counted.
+	assert.Equal(t, 1, sm.NodesAcked("projectcontour/gateway", since, envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+	assert.Equal(t, 0, sm.NodesAcked("projectcontour/other", since, envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+
+	// Snapshots set before since don't count.
+	assert.Equal(t, 0, sm.NodesAcked("", since.Add(time.Second), envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+
+	// Closed streams don't count.
+	sm.streamClosed(3)
+	assert.Equal(t, 2, sm.NodesAcked("", since, envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+	sm.streamClosed(2)
+	assert.Equal(t, 1, sm.NodesAcked("", since, envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType))
+}
+
+func TestStreamMetricsAckBeforeSnapshotSet(t *testing.T) {
+	sm := NewStreamMetrics(metrics.NewMetrics(prometheus.NewRegistry()))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sm.now = func() time.Time { return now }
+
+	sm.streamOpen(1, "")
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		Node:    &envoy_config_core_v3.Node{Id: "envoy-1"},
+		TypeUrl: envoy_resource_v3.ListenerType,
+	})
+
+	// Envoy ACKs the snapshot before it is recorded as set.
+	since := now
+	sm.streamRequest(1, &envoy_service_discovery_v3.DiscoveryRequest{
+		TypeUrl:       envoy_resource_v3.ListenerType,
+		VersionInfo:   "v1",
+		ResponseNonce: "1",
+	})
+	assert.Equal(t, 0, sm.NodesAcked("", since, envoy_resource_v3.ListenerType))
+
+	sm.SnapshotSet("v1", envoy_resource_v3.ListenerType)
+	assert.Equal(t, 1, sm.NodesAcked("", since, envoy_resource_v3.ListenerType))
+
+	// A later snapshot isn't ACKed by the earlier ACK.
+	now = now.Add(time.Second)
+	sm.SnapshotSet("v2", envoy_resource_v3.ListenerType)
+	assert.Equal(t, 0, sm.NodesAcked("", now, envoy_resource_v3.ListenerType))
+
+	version, set := sm.SnapshotVersion(envoy_resource_v3.ListenerType, envoy_resource_v3.RouteType)
+	assert.Equal(t, "v2", version)
+	assert.Equal(t, now, set)
+}
+
+func TestStreamMetricsNodeAddresses(t *testing.T) {
+	sm := NewStreamMetrics(metrics.NewMetrics(prometheus.NewRegistry()))
+
+	open := func(streamID int64, addr, nodeID string) {
+		sm.streamOpen(streamID, addr)
+		sm.streamRequest(streamID, &envoy_service_discovery_v3.DiscoveryRequest{
+			Node:    &envoy_config_core_v3.Node{Id: nodeID},
+			TypeUrl: envoy_resource_v3.ListenerType,
+		})
+	}
+
+	assert.Empty(t, sm.NodeAddresses())
+
+	// envoy-1 uses a stream per type URL from the same address.
+	open(1, "10.0.0.2", "envoy-1")
+	open(2, "10.0.0.2", "envoy-1")
+	open(3, "10.0.0.1", "envoy-2")
+	// Streams with no known address are ignored.
+	open(4, "", "envoy-3")
+	// Streams that have not identified their node yet are ignored.
+	sm.streamOpen(5, "10.0.0.3")
+
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, sm.NodeAddresses())
+
+	sm.streamClosed(3)
+	assert.Equal(t, []string{"10.0.0.2"}, sm.NodeAddresses())
+}
@@ -68,12 +68,25 @@ func (*ClusterCache) TypeURL() string { return resource.ClusterType }
 func (c *ClusterCache) OnChange(root *dag.DAG) {
 	clusters := map[string]*envoy_config_cluster_v3.Cluster{}
 
+	// The stat prefix of a cluster does not change its name, so
+	// a cluster shared by routing objects with different stat
+	// prefixes keeps its default stat name.
+	dagClusters := map[string]*dag.Cluster{}
 	for _, cluster := range root.GetClusters() {
 		name := envoy.Clustername(cluster)
-		if _, ok := clusters[name]; !ok {
-			clusters[name] = c.envoyGen.Cluster(cluster)
+		existing, ok := dagClusters[name]
+		switch {
+		case !ok:
+			dagClusters[name] = cluster
+		case existing.StatPrefix != cluster.StatPrefix && existing.StatPrefix != "":
+			shared := *existing
+			shared.StatPrefix = ""
+			dagClusters[name] = &shared
 		}
 	}
+	for name, cluster := range dagClusters {
+		clusters[name] = c.envoyGen.Cluster(cluster)
+	}
 
 	for name, ec := range root.GetExtensionClusters() {
 		if _, ok := clusters[name]; !ok {
@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/protobuf"
 )
@@ -762,6 +763,95 @@ func TestClusterVisit(t *testing.T) {
 	}
 }
 
+func TestClusterVisitStatPrefixes(t *testing.T) {
+	envoyGen := envoy_v3.NewEnvoyGen(envoy_v3.EnvoyGenOpt{
+		XDSClusterName: envoy_v3.DefaultXDSClusterName,
+	})
+	envoyConfigSource := envoyGen.GetConfigSource()
+
+	upstream := func(name string) *dag.Service {
+		return &dag.Service{
+			Weighted: dag.WeightedService{
+				ServiceName:      name,
+				ServiceNamespace: "default",
+				ServicePort:      core_v1.ServicePort{Port: 80},
+			},
+		}
+	}
+	route := func(clusters ...*dag.Cluster) *dag.Route {
+		return &dag.Route{
+			PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+			Clusters:           clusters,
+		}
+	}
+	shared, owned := upstream("shared"), upstream("owned")
+
+	root := &dag.DAG{
+		Listeners: map[string]*dag.Listener{
+			dag.HTTP_LISTENER_NAME: {
+				VirtualHosts: []*dag.VirtualHost{
+					{
+						Name: "a.example.com",
+						Routes: map[string]*dag.Route{
+							"a": route(&dag.Cluster{Upstream: shared, StatPrefix: "httpproxy_default_a"}),
+						},
+					},
+					{
+						Name: "b.example.com",
+						Routes: map[string]*dag.Route{
+							"b": route(&dag.Cluster{Upstream: shared, StatPrefix: "httpproxy_default_b"}),
+						},
+					},
+					{
+						Name: "c.example.com",
+						Routes: map[string]*dag.Route{
+							"c": route(&dag.Cluster{Upstream: owned, StatPrefix: "httpproxy_default_c"}),
+						},
+					},
+				},
+			},
+			dag.HTTPS_LISTENER_NAME: {
+				SecureVirtualHosts: []*dag.SecureVirtualHost{{
+					VirtualHost: dag.VirtualHost{
+						Name: "c.example.com",
+						Routes: map[string]*dag.Route{
+							"c": route(&dag.Cluster{Upstream: owned, StatPrefix: "httpproxy_default_c"}),
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	cc := ClusterCache{
+		envoyGen: envoyGen,
+	}
+	cc.OnChange(root)
+
+	// The shared cluster keeps its name and falls back to its
+	// default stat name, rather than being duplicated.
+	protobuf.ExpectEqual(t, clustermap(
+		&envoy_config_cluster_v3.Cluster{
+			Name:                 "default/owned/80/da39a3ee5e",
+			AltStatName:          "httpproxy_default_c_default_owned_80",
+			ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+			EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+				EdsConfig:   envoyConfigSource,
+				ServiceName: "default/owned",
+			},
+		},
+		&envoy_config_cluster_v3.Cluster{
+			Name:                 "default/shared/80/da39a3ee5e",
+			AltStatName:          "default_shared_80",
+			ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+			EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+				EdsConfig:   envoyConfigSource,
+				ServiceName: "default/shared",
+			},
+		},
+	), cc.values)
+}
+
 func service(ns, name string, ports ...core_v1.ServicePort) *core_v1.Service {
 	return serviceWithAnnotations(ns, name, nil, ports...)
 }
@@ -26,6 +26,7 @@ import (
 	discovery_v1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	"github.com/projectcontour/contour/internal/contour"
 	"github.com/projectcontour/contour/internal/dag"
@@ -126,12 +127,34 @@ type EndpointSliceCache struct {
 	// Index of ServiceClusters. ServiceClusters are indexed
 	// by the name of their Kubernetes Services. This makes it
 	// easy to determine which Endpoints affect which ServiceCluster.
-	services map[types.NamespacedName][]*dag.ServiceCluster
+	services map[serviceKey][]*dag.ServiceCluster
 
 	// Cache of endpointsSlices, indexed by Namespaced name of the associated service.
 	// the Inner map is a map[k,v] where k is the endpoint slice name and v is the
 	// endpoint slice itself.
-	endpointSlices map[types.NamespacedName]map[string]*discovery_v1.EndpointSlice
+	endpointSlices map[serviceKey]map[string]*discovery_v1.EndpointSlice
+}
+
+// serviceKey identifies the Service, or the multi-cluster ServiceImport,
+// that EndpointSlices belong to. A ServiceImport may have the same name
+// as a Service in the same namespace, but has its own EndpointSlices.
+type serviceKey struct {
+	types.NamespacedName
+	imported bool
+}
+
+// endpointSliceServiceKeys returns the keys of the Service and ServiceImport
+// that endpointSlice belongs to. EndpointSlices derived for a ServiceImport
+// are labelled with the name of the ServiceImport rather than a Service.
+func endpointSliceServiceKeys(endpointSlice *discovery_v1.EndpointSlice) []serviceKey {
+	var keys []serviceKey
+	if name, ok := endpointSlice.Labels[discovery_v1.LabelServiceName]; ok {
+		keys = append(keys, serviceKey{NamespacedName: types.NamespacedName{Namespace: endpointSlice.Namespace, Name: name}})
+	}
+	if name, ok := endpointSlice.Labels[mcs_v1alpha1.LabelServiceName]; ok {
+		keys = append(keys, serviceKey{NamespacedName: types.NamespacedName{Namespace: endpointSlice.Namespace, Name: name}, imported: true})
+	}
+	return keys
 }
 
 // Recalculate regenerates all the ClusterLoadAssignments from the
@@ -160,7 +183,7 @@ func (c *EndpointSliceCache) Recalculate() map[string]*envoy_config_endpoint_v3.
 		// Look up each service, and if we have endpointSlice for that service,
 		// attach them as a new LocalityEndpoints resource.
 		for _, w := range cluster.Services {
-			n := types.NamespacedName{Namespace: w.ServiceNamespace, Name: w.ServiceName}
+			n := serviceKey{NamespacedName: types.NamespacedName{Namespace: w.ServiceNamespace, Name: w.ServiceName}, imported: w.Imported}
 			if lb := c.RecalculateEndpoints(w.ServicePort, w.HealthPort, c.endpointSlices[n]); lb != nil {
 				// Append the new set of endpoints. Users are allowed to set the load
 				// balancing weight to 0, which we reflect to Envoy as nil in order to
@@ -168,6 +191,7 @@ func (c *EndpointSliceCache) Recalculate() map[string]*envoy_config_endpoint_v3.
 				cla.Endpoints = append(
 					cla.Endpoints,
 					&LocalityEndpoints{
+						Locality:            serviceLocality(cluster, w),
 						LbEndpoints:         lb,
 						LoadBalancingWeight: protobuf.UInt32OrNil(w.Weight),
 					},
@@ -190,7 +214,7 @@ func (c *EndpointSliceCache) SetClusters(clusters []*dag.ServiceCluster) error {
 
 	// Keep a local index to start with so that errors don't cause
 	// partial failure.
-	serviceIndex := map[types.NamespacedName][]*dag.ServiceCluster{}
+	serviceIndex := map[serviceKey][]*dag.ServiceCluster{}
 
 	// Reindex the cluster so that we can find them by service name.
 	for _, cluster := range clusters {
@@ -202,9 +226,12 @@ func (c *EndpointSliceCache) SetClusters(clusters []*dag.ServiceCluster) error {
 		cluster.Rebalance()
 
 		for _, s := range cluster.Services {
-			name := types.NamespacedName{
-				Namespace: s.ServiceNamespace,
-				Name:      s.ServiceName,
+			name := serviceKey{
+				NamespacedName: types.NamespacedName{
+					Namespace: s.ServiceNamespace,
+					Name:      s.ServiceName,
+				},
+				imported: s.Imported,
 			}
 
 			// Create the slice entry if we have not indexed this service yet.
@@ -231,21 +258,22 @@ func (c *EndpointSliceCache) UpdateEndpointSlice(endpointSlice *discovery_v1.End
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	name := types.NamespacedName{Namespace: endpointSlice.Namespace, Name: endpointSlice.Labels[discovery_v1.LabelServiceName]}
-
-	if c.endpointSlices[name] == nil {
-		c.endpointSlices[name] = make(map[string]*discovery_v1.EndpointSlice)
-	}
-	c.endpointSlices[name][endpointSlice.Name] = endpointSlice.DeepCopy()
+	var inUse bool
+	for _, name := range endpointSliceServiceKeys(endpointSlice) {
+		if c.endpointSlices[name] == nil {
+			c.endpointSlices[name] = make(map[string]*discovery_v1.EndpointSlice)
+		}
+		c.endpointSlices[name][endpointSlice.Name] = endpointSlice.DeepCopy()
 
-	// If any service clusters include this endpointSlice, mark them
-	// all as stale.
-	if affected := c.services[name]; len(affected) > 0 {
-		c.stale = append(c.stale, affected...)
-		return true
+		// If any service clusters include this endpointSlice, mark them
+		// all as stale.
+		if affected := c.services[name]; len(affected) > 0 {
+			c.stale = append(c.stale, affected...)
+			inUse = true
+		}
 	}
 
-	return false
+	return inUse
 }
 
 // DeleteEndpointSlice deletes endpointSlice from the cache. Any ServiceClusters
@@ -255,17 +283,19 @@ func (c *EndpointSliceCache) DeleteEndpointSlice(endpointSlice *discovery_v1.End
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	name := types.NamespacedName{Namespace: endpointSlice.Namespace, Name: endpointSlice.Labels[discovery_v1.LabelServiceName]}
-	delete(c.endpointSlices[name], endpointSlice.Name)
+	var inUse bool
+	for _, name := range endpointSliceServiceKeys(endpointSlice) {
+		delete(c.endpointSlices[name], endpointSlice.Name)
 
-	// If any service clusters include this endpointSlice, mark them
-	// all as stale.
-	if affected := c.services[name]; len(affected) > 0 {
-		c.stale = append(c.stale, affected...)
-		return true
+		// If any service clusters include this endpointSlice, mark them
+		// all as stale.
+		if affected := c.services[name]; len(affected) > 0 {
+			c.stale = append(c.stale, affected...)
+			inUse = true
+		}
 	}
 
-	return false
+	return inUse
 }
 
 // NewEndpointSliceTranslator allocates a new endpointsSlice translator.
@@ -275,8 +305,8 @@ func NewEndpointSliceTranslator(log logrus.FieldLogger) *EndpointSliceTranslator
 		entries:     map[string]*envoy_config_endpoint_v3.ClusterLoadAssignment{},
 		cache: EndpointSliceCache{
 			stale:          nil,
-			services:       map[types.NamespacedName][]*dag.ServiceCluster{},
-			endpointSlices: map[types.NamespacedName]map[string]*discovery_v1.EndpointSlice{},
+			services:       map[serviceKey][]*dag.ServiceCluster{},
+			endpointSlices: map[serviceKey]map[string]*discovery_v1.EndpointSlice{},
 		},
 	}
 }
@@ -16,12 +16,14 @@ package v3
 import (
 	"testing"
 
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 	core_v1 "k8s.io/api/core/v1"
 	discovery_v1 "k8s.io/api/discovery/v1"
 	"k8s.io/utils/ptr"
+	mcs_v1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	"github.com/projectcontour/contour/internal/dag"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
@@ -1068,6 +1070,12 @@ func TestEndpointSliceTranslatorWeightedService(t *testing.T) {
 	w1 := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.24", 8080))
 	w2 := envoy_v3.WeightedEndpoints(2, envoy_v3.SocketAddress("192.168.183.24", 8080))
 
+	// Each service is a locality of its own, so that Envoy can
+	// weight them.
+	w0[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight0"}
+	w1[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight1"}
+	w2[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight2"}
+
 	want := []proto.Message{
 		&envoy_config_endpoint_v3.ClusterLoadAssignment{
 			ClusterName: "default/weighted",
@@ -1131,6 +1139,12 @@ func TestEndpointSliceTranslatorDefaultWeightedService(t *testing.T) {
 	w1 := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.24", 8080))
 	w2 := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.24", 8080))
 
+	// Each service is a locality of its own, so that Envoy can
+	// weight them.
+	w0[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight0"}
+	w1[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight1"}
+	w2[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight2"}
+
 	want := []proto.Message{
 		&envoy_config_endpoint_v3.ClusterLoadAssignment{
 			ClusterName: "default/weighted",
@@ -1142,3 +1156,95 @@ func TestEndpointSliceTranslatorDefaultWeightedService(t *testing.T) {
 
 	protobuf.ExpectEqual(t, want, endpointSliceTranslator.Contents())
 }
+
+func TestEndpointSliceTranslatorServiceImport(t *testing.T) {
+	endpointSliceTranslator := NewEndpointSliceTranslator(fixture.NewTestLogger(t))
+	clusters := []*dag.ServiceCluster{
+		{
+			ClusterName: "serviceimport/default/simple/http",
+			Services: []dag.WeightedService{
+				{
+					Weight:           1,
+					ServiceName:      "simple",
+					ServiceNamespace: "default",
+					ServicePort:      core_v1.ServicePort{Name: "http"},
+					Imported:         true,
+				},
+			},
+		},
+		{
+			ClusterName: "default/simple/http",
+			Services: []dag.WeightedService{
+				{
+					Weight:           1,
+					ServiceName:      "simple",
+					ServiceNamespace: "default",
+					ServicePort:      core_v1.ServicePort{Name: "http"},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, endpointSliceTranslator.cache.SetClusters(clusters))
+
+	ports := []discovery_v1.EndpointPort{
+		{
+			Name:     ptr.To("http"),
+			Port:     ptr.To[int32](8080),
+			Protocol: ptr.To[core_v1.Protocol]("TCP"),
+		},
+	}
+
+	// The EndpointSlice of the local Service.
+	endpointSliceTranslator.OnAdd(endpointSlice("default", "simple-eps-fs23r", "simple", discovery_v1.AddressTypeIPv4, []discovery_v1.Endpoint{
+		{
+			Addresses: []string{"10.10.1.1"},
+		},
+	}, ports), false)
+
+	// The EndpointSlices derived for the ServiceImport are labelled
+	// with its name, rather than the name of a Service.
+	importedEndpointSlice := func(name, address string) *discovery_v1.EndpointSlice {
+		eps := endpointSlice("default", name, "", discovery_v1.AddressTypeIPv4, []discovery_v1.Endpoint{
+			{
+				Addresses: []string{address},
+			},
+		}, ports)
+		eps.Labels = map[string]string{
+			mcs_v1alpha1.LabelServiceName: "simple",
+		}
+		return eps
+	}
+
+	endpointSliceTranslator.OnAdd(importedEndpointSlice("imported-simple-cluster-a", "10.20.1.1"), false)
+
+	want := []proto.Message{
+		&envoy_config_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/simple/http",
+			Endpoints:   envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("10.10.1.1", 8080)),
+		},
+		&envoy_config_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "serviceimport/default/simple/http",
+			Endpoints:   envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("10.20.1.1", 8080)),
+		},
+	}
+
+	protobuf.ExpectEqual(t, want, endpointSliceTranslator.Contents())
+
+	// The endpoints fail over to another exporting cluster.
+	endpointSliceTranslator.OnDelete(importedEndpointSlice("imported-simple-cluster-a", "10.20.1.1"))
+	endpointSliceTranslator.OnAdd(importedEndpointSlice("imported-simple-cluster-b", "10.30.1.1"), false)
+
+	want = []proto.Message{
+		&envoy_config_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/simple/http",
+			Endpoints:   envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("10.10.1.1", 8080)),
+		},
+		&envoy_config_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "serviceimport/default/simple/http",
+			Endpoints:   envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("10.30.1.1", 8080)),
+		},
+	}
+
+	protobuf.ExpectEqual(t, want, endpointSliceTranslator.Contents())
+}
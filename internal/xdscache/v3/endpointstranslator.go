@@ -18,6 +18,7 @@ import (
 	"sort"
 	"sync"
 
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/sirupsen/logrus"
@@ -32,6 +33,7 @@ import (
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/protobuf"
 	"github.com/projectcontour/contour/internal/sorter"
+	"github.com/projectcontour/contour/internal/xds"
 )
 
 type (
@@ -147,6 +149,7 @@ func (c *EndpointsCache) Recalculate() map[string]*envoy_config_endpoint_v3.Clus
 				cla.Endpoints = append(
 					cla.Endpoints,
 					&LocalityEndpoints{
+						Locality:            serviceLocality(cluster, w),
 						LbEndpoints:         lb,
 						LoadBalancingWeight: protobuf.UInt32OrNil(w.Weight),
 					},
@@ -161,6 +164,19 @@ func (c *EndpointsCache) Recalculate() map[string]*envoy_config_endpoint_v3.Clus
 	return assignments
 }
 
+// serviceLocality returns the locality of the endpoints of the service
+// in the ClusterLoadAssignment of the cluster. The services of clusters
+// balancing several of them, such as traffic shifts, each get their own
+// locality so that Envoy can weight them.
+func serviceLocality(cluster *dag.ServiceCluster, w dag.WeightedService) *envoy_config_core_v3.Locality {
+	if len(cluster.Services) < 2 {
+		return nil
+	}
+	return &envoy_config_core_v3.Locality{
+		SubZone: xds.LocalitySubZone(types.NamespacedName{Namespace: w.ServiceNamespace, Name: w.ServiceName}),
+	}
+}
+
 // SetClusters replaces the cache of ServiceCluster resources. All
 // the added clusters will be marked stale.
 func (c *EndpointsCache) SetClusters(clusters []*dag.ServiceCluster) error {
@@ -849,6 +849,12 @@ func TestEndpointsTranslatorWeightedService(t *testing.T) {
 	w1 := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.24", 8080))
 	w2 := envoy_v3.WeightedEndpoints(2, envoy_v3.SocketAddress("192.168.183.24", 8080))
 
+	// Each service is a locality of its own, so that Envoy can
+	// weight them.
+	w0[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight0"}
+	w1[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight1"}
+	w2[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight2"}
+
 	want := []proto.Message{
 		&envoy_config_endpoint_v3.ClusterLoadAssignment{
 			ClusterName: "default/weighted",
@@ -907,6 +913,12 @@ func TestEndpointsTranslatorDefaultWeightedService(t *testing.T) {
 	w1 := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.24", 8080))
 	w2 := envoy_v3.WeightedEndpoints(1, envoy_v3.SocketAddress("192.168.183.24", 8080))
 
+	// Each service is a locality of its own, so that Envoy can
+	// weight them.
+	w0[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight0"}
+	w1[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight1"}
+	w2[0].Locality = &envoy_config_core_v3.Locality{SubZone: "default/weight2"}
+
 	want := []proto.Message{
 		&envoy_config_endpoint_v3.ClusterLoadAssignment{
 			ClusterName: "default/weighted",
@@ -78,6 +78,11 @@ type ListenerConfig struct {
 	// negotiating TLS 1.2.
 	CipherSuites []string
 
+	// OCSPStaplePolicy defines how Envoy TLS listeners use the OCSP
+	// responses stapled to serving certificates.
+	// If not set, defaults to lenient stapling.
+	OCSPStaplePolicy contour_v1alpha1.OCSPStaplePolicy
+
 	// DefaultHTTPVersions defines the default set of HTTP
 	// versions the proxy should accept. If not specified, all
 	// supported versions are accepted. This is applied to both
@@ -205,6 +210,8 @@ type GlobalExternalAuthConfig struct {
 	FailOpen        bool
 	Context         map[string]string
 	WithRequestBody *dag.AuthorizationServerBufferSettings
+	HTTPService     *dag.AuthorizationHTTPService
+	Forwarding      *dag.AuthorizationForwarding
 }
 
 // httpAccessLog returns the access log for the HTTP (non TLS)
@@ -261,6 +268,25 @@ func (lvc *ListenerConfig) newSecureAccessLog() []*envoy_config_accesslog_v3.Acc
 	}
 }
 
+// newTCPProxyAccessLog returns the access log for connections through
+// the given TCPProxy, defaulting to the secure listener's access log.
+func (lvc *ListenerConfig) newTCPProxyAccessLog(proxy *dag.TCPProxy) []*envoy_config_accesslog_v3.AccessLog {
+	switch {
+	case proxy.AccessLog == nil:
+		return lvc.newSecureAccessLog()
+	case proxy.AccessLog.Disabled:
+		return nil
+	case proxy.AccessLog.Format != "":
+		logging := contour_v1alpha1.EnvoyLogging{
+			AccessLogFormat:       contour_v1alpha1.EnvoyAccessLog,
+			AccessLogFormatString: proxy.AccessLog.Format,
+		}
+		return envoy_v3.FileAccessLogEnvoy(lvc.httpsAccessLog(), proxy.AccessLog.Format, logging.AccessLogFormatterExtensions(), lvc.AccessLogLevel)
+	default:
+		return lvc.newSecureAccessLog()
+	}
+}
+
 // minTLSVersion returns the requested minimum TLS protocol
 // version or envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2 if not configured.
 func (lvc *ListenerConfig) minTLSVersion() envoy_transport_socket_tls_v3.TlsParameters_TlsProtocol {
@@ -394,6 +420,7 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				StripTrailingHostDot(cfg.StripTrailingHostDot).
 				MaxRequestsPerConnection(cfg.MaxRequestsPerConnection).
 				HTTP2MaxConcurrentStreams(cfg.HTTP2MaxConcurrentStreams).
+				AddFilter(envoy_v3.FilterAuthorizationPolicy(listener.VirtualHosts...)).
 				AddFilter(httpGlobalExternalAuthConfig(cfg.GlobalExternalAuthConfig)).
 				Tracing(envoy_v3.TracingConfig(envoyTracingConfig(cfg.TracingConfig))).
 				AddFilter(envoy_v3.GlobalRateLimitFilter(envoyGlobalRateLimitConfig(cfg.RateLimitConfig))).
@@ -454,6 +481,8 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					AddFilter(envoy_v3.FilterMisdirectedRequests(vh.VirtualHost.Name)).
 					DefaultFilters().
 					AddFilter(envoy_v3.FilterJWTAuthN(vh.JWTProviders)).
+					AddFilter(envoy_v3.FilterJWTClaims(vh.JWTProviders)).
+					AddFilter(envoy_v3.FilterAuthorizationPolicy(&vh.VirtualHost)).
 					AddFilter(authzFilter).
 					RouteConfigName(httpsRouteConfigName(listener, vh.VirtualHost.Name)).
 					MetricsPrefix(listener.Name).
@@ -481,7 +510,7 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 
 				alpnProtos = envoy_v3.ProtoNamesForVersions(cfg.DefaultHTTPVersions...)
 			} else {
-				filters = envoy_v3.Filters(envoy_v3.TCPProxy(listener.Name, vh.TCPProxy, cfg.newSecureAccessLog()))
+				filters = envoy_v3.Filters(envoy_v3.TCPProxy(listener.Name, vh.TCPProxy, cfg.newTCPProxyAccessLog(vh.TCPProxy)))
 
 				// Do not offer ALPN for TCP proxying, since
 				// the protocols will be provided by the TCP
@@ -502,12 +531,13 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				}
 
 				downstreamTLS = c.envoyGen.DownstreamTLSContext(
-					vh.Secret,
+					vh.Secrets(),
 					minVer,
 					maxVer,
 					cfg.CipherSuites,
 					vh.DownstreamValidation,
 					alpnProtos...)
+				downstreamTLS.OcspStaplePolicy = envoy_v3.OCSPStaplePolicy(cfg.OCSPStaplePolicy)
 			}
 
 			listeners[listener.Name].FilterChains = append(listeners[listener.Name].FilterChains, envoy_v3.FilterChainTLS(vh.VirtualHost.Name, downstreamTLS, filters))
@@ -521,13 +551,14 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				// Construct the downstreamTLSContext passing the configured fallbackCertificate. The TLS min/max ProtocolVersion will use
 				// the value defined in the Contour Configuration file if defined.
 				downstreamTLS = c.envoyGen.DownstreamTLSContext(
-					vh.FallbackCertificate,
+					[]*dag.Secret{vh.FallbackCertificate},
 					cfg.minTLSVersion(),
 					cfg.maxTLSVersion(),
 					cfg.CipherSuites,
 					vh.DownstreamValidation,
 					alpnProtos...,
 				)
+				downstreamTLS.OcspStaplePolicy = envoy_v3.OCSPStaplePolicy(cfg.OCSPStaplePolicy)
 
 				var authzFilter *envoy_filter_network_http_connection_manager_v3.HttpFilter
 				if vh.ExternalAuthorization != nil {
@@ -537,6 +568,7 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				cm := c.envoyGen.HTTPConnectionManagerBuilder().
 					Compression(cfg.Compression).
 					DefaultFilters().
+					AddFilter(envoy_v3.FilterAuthorizationPolicy(fallbackVirtualHosts(listener)...)).
 					AddFilter(authzFilter).
 					RouteConfigName(fallbackCertRouteConfigName(listener)).
 					MetricsPrefix(listener.Name).
@@ -593,6 +625,18 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 	c.Update(listeners)
 }
 
+// fallbackVirtualHosts returns the virtual hosts whose routes
+// are served by the listener's fallback certificate filter chain.
+func fallbackVirtualHosts(listener *dag.Listener) []*dag.VirtualHost {
+	var vhosts []*dag.VirtualHost
+	for _, vh := range listener.SecureVirtualHosts {
+		if vh.FallbackCertificate != nil {
+			vhosts = append(vhosts, &vh.VirtualHost)
+		}
+	}
+	return vhosts
+}
+
 func httpGlobalExternalAuthConfig(config *GlobalExternalAuthConfig) *envoy_filter_network_http_connection_manager_v3.HttpFilter {
 	if config == nil {
 		return nil
@@ -606,6 +650,8 @@ func httpGlobalExternalAuthConfig(config *GlobalExternalAuthConfig) *envoy_filte
 		AuthorizationFailOpen:              config.FailOpen,
 		AuthorizationResponseTimeout:       config.ExtensionServiceConfig.Timeout,
 		AuthorizationServerWithRequestBody: config.WithRequestBody,
+		AuthorizationHTTPService:           config.HTTPService,
+		AuthorizationForwarding:            config.Forwarding,
 	})
 }
 
@@ -111,7 +111,7 @@ func TestListenerVisit(t *testing.T) {
 	jwtProvider := contour_v1.JWTProvider{
 		Name:   "provider-1",
 		Issuer: "issuer.jwt.example.com",
-		RemoteJWKS: contour_v1.RemoteJWKS{
+		RemoteJWKS: &contour_v1.RemoteJWKS{
 			URI:     "https://jwt.example.com/jwks.json",
 			Timeout: jwksTimeout,
 		},
@@ -862,6 +862,59 @@ func TestListenerVisit(t *testing.T) {
 				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
 			}),
 		},
+		"ocsp-staple-policy from config": {
+			ListenerConfig: ListenerConfig{
+				OCSPStaplePolicy: contour_v1alpha1.StrictOCSPStaplePolicy,
+			},
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName: "secret",
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				secret,
+				service,
+			},
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:          ENVOY_HTTP_LISTENER,
+				Address:       envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains:  envoy_v3.FilterChains(envoyGen.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo), 0)),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: func() *envoy_config_core_v3.TransportSocket {
+						tlsContext := envoyGen.DownstreamTLSContext([]*dag.Secret{{Object: secret}}, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, nil, "h2", "http/1.1")
+						tlsContext.OcspStaplePolicy = envoy_transport_socket_tls_v3.DownstreamTlsContext_STRICT_STAPLING
+						return envoy_v3.DownstreamTLSTransportSocket(tlsContext)
+					}(),
+					Filters: envoy_v3.Filters(httpsFilterFor("www.example.com")),
+				}},
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
 
 		"httpproxy with fallback certificate and with request timeout set": {
 			fallbackCertificate: &types.NamespacedName{
@@ -3422,7 +3475,7 @@ func TestListenerVisit(t *testing.T) {
 						AddFilter(envoy_v3.FilterJWTAuthN([]dag.JWTProvider{{
 							Name:   jwtProvider.Name,
 							Issuer: jwtProvider.Issuer,
-							RemoteJWKS: dag.RemoteJWKS{
+							RemoteJWKS: &dag.RemoteJWKS{
 								URI: jwtProvider.RemoteJWKS.URI,
 								Cluster: dag.DNSNameCluster{
 									Address: jwksURL.Hostname(),
@@ -3476,7 +3529,7 @@ func transportSocket(envoyGen *envoy_v3.EnvoyGen, secretName string, tlsMinProto
 	}
 
 	return envoy_v3.DownstreamTLSTransportSocket(
-		envoyGen.DownstreamTLSContext(secret, tlsMinProtoVersion, tlsMaxProtoVersion, cipherSuites, nil, alpnprotos...),
+		envoyGen.DownstreamTLSContext([]*dag.Secret{secret}, tlsMinProtoVersion, tlsMaxProtoVersion, cipherSuites, nil, alpnprotos...),
 	)
 }
 
@@ -15,6 +15,7 @@ package v3
 
 import (
 	"context"
+	"strings"
 
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	envoy_types "github.com/envoyproxy/go-control-plane/pkg/cache/types"
@@ -34,17 +35,30 @@ import (
 // generates and caches go-control-plane Snapshots.
 type SnapshotHandler struct {
 	resources    map[envoy_resource_v3.Type]xdscache.ResourceCache
+	nodeGroups   []string
 	defaultCache envoy_cache_v3.SnapshotCache
 	edsCache     envoy_cache_v3.SnapshotCache
 	mux          *envoy_cache_v3.MuxCache
 	log          logrus.FieldLogger
+
+	// lastResources holds the resources of the
+	// last snapshot set by OnChange.
+	lastResources map[envoy_resource_v3.Type][]envoy_types.Resource
+
+	streamMetrics *contour_xds_v3.StreamMetrics
 }
 
 // NewSnapshotHandler returns an instance of SnapshotHandler.
-func NewSnapshotHandler(resources []xdscache.ResourceCache, log logrus.FieldLogger) *SnapshotHandler {
+//
+// Envoys whose node cluster is one of the given node groups are sent
+// only the listeners and route configurations whose names are prefixed
+// with "<group>/", along with those not prefixed with any group's name.
+// Other Envoys are sent only the latter.
+func NewSnapshotHandler(resources []xdscache.ResourceCache, nodeGroups []string, log logrus.FieldLogger) *SnapshotHandler {
 	var (
-		defaultCache = envoy_cache_v3.NewSnapshotCache(false, &contour_xds_v3.Hash, log.WithField("context", "defaultCache"))
-		edsCache     = envoy_cache_v3.NewSnapshotCache(false, &contour_xds_v3.Hash, log.WithField("context", "edsCache"))
+		hash         = contour_xds_v3.NewNodeGroupHash(nodeGroups)
+		defaultCache = envoy_cache_v3.NewSnapshotCache(false, hash, log.WithField("context", "defaultCache"))
+		edsCache     = envoy_cache_v3.NewSnapshotCache(false, hash, log.WithField("context", "edsCache"))
 
 		mux = &envoy_cache_v3.MuxCache{
 			Caches: map[string]envoy_cache_v3.Cache{},
@@ -67,6 +81,7 @@ func NewSnapshotHandler(resources []xdscache.ResourceCache, log logrus.FieldLogg
 
 	sh := &SnapshotHandler{
 		resources:    parseResources(resources),
+		nodeGroups:   nodeGroups,
 		defaultCache: defaultCache,
 		edsCache:     edsCache,
 		mux:          mux,
@@ -86,6 +101,12 @@ func (s *SnapshotHandler) GetCache() envoy_cache_v3.Cache {
 	return s.mux
 }
 
+// SetStreamMetrics sets the StreamMetrics that are told about each
+// snapshot set, to measure how long Envoy takes to ACK it.
+func (s *SnapshotHandler) SetStreamMetrics(streamMetrics *contour_xds_v3.StreamMetrics) {
+	s.streamMetrics = streamMetrics
+}
+
 // Refresh is called when the EndpointsTranslator updates values
 // in its cache. It updates the EDS cache.
 func (s *SnapshotHandler) Refresh() {
@@ -101,19 +122,26 @@ func (s *SnapshotHandler) Refresh() {
 		return
 	}
 
-	if err := s.edsCache.SetSnapshot(context.Background(), contour_xds_v3.Hash.String(), snapshot); err != nil {
-		s.log.Errorf("failed to store snapshot version %q: %s", version, err)
-		return
+	// Envoy may ACK the snapshot before SetSnapshot returns,
+	// so record it first.
+	if s.streamMetrics != nil {
+		s.streamMetrics.SnapshotSet(version, envoy_resource_v3.EndpointType)
+	}
+
+	for _, key := range s.snapshotKeys() {
+		if err := s.edsCache.SetSnapshot(context.Background(), key, snapshot); err != nil {
+			s.log.Errorf("failed to store snapshot version %q: %s", version, err)
+			return
+		}
 	}
 }
 
 // OnChange is called when the DAG is rebuilt and a new snapshot is needed.
 // It creates and caches a new go-control-plane Snapshot based on the
-// contents of the Contour xDS resource caches.
+// contents of the Contour xDS resource caches. If the contents did not
+// change, the last snapshot is kept along with its version, so that
+// Envoy isn't sent the same resources again.
 func (s *SnapshotHandler) OnChange(*dag.DAG) {
-	// Generate new snapshot version.
-	version := uuid.NewString()
-
 	// Convert caches to envoy xDS Resources.
 	resources := map[envoy_resource_v3.Type][]envoy_types.Resource{}
 
@@ -126,16 +154,82 @@ func (s *SnapshotHandler) OnChange(*dag.DAG) {
 		resources[resourceType] = asResources(resourceCache.Contents())
 	}
 
-	snapshot, err := envoy_cache_v3.NewSnapshot(version, resources)
-	if err != nil {
-		s.log.Errorf("failed to generate snapshot version %q: %s", version, err)
+	if s.lastResources != nil && equalResources(resources, s.lastResources) {
 		return
 	}
 
-	if err := s.defaultCache.SetSnapshot(context.Background(), contour_xds_v3.Hash.String(), snapshot); err != nil {
-		s.log.Errorf("failed to store snapshot version %q: %s", version, err)
-		return
+	// Generate new snapshot version.
+	version := uuid.NewString()
+
+	snapshots := make(map[string]*envoy_cache_v3.Snapshot, len(s.nodeGroups)+1)
+	for _, key := range s.snapshotKeys() {
+		snapshot, err := envoy_cache_v3.NewSnapshot(version, s.nodeGroupResources(key, resources))
+		if err != nil {
+			s.log.Errorf("failed to generate snapshot version %q: %s", version, err)
+			return
+		}
+		snapshots[key] = snapshot
+	}
+
+	// Envoy may ACK the snapshot before SetSnapshot returns,
+	// so record it first.
+	if s.streamMetrics != nil {
+		typeURLs := make([]string, 0, len(resources))
+		for resourceType := range resources {
+			typeURLs = append(typeURLs, resourceType)
+		}
+		s.streamMetrics.SnapshotSet(version, typeURLs...)
 	}
+
+	for _, key := range s.snapshotKeys() {
+		if err := s.defaultCache.SetSnapshot(context.Background(), key, snapshots[key]); err != nil {
+			s.log.Errorf("failed to store snapshot version %q: %s", version, err)
+			return
+		}
+	}
+
+	s.lastResources = resources
+}
+
+// snapshotKeys returns the keys of the snapshots to set: the default
+// key, and the key of each node group.
+func (s *SnapshotHandler) snapshotKeys() []string {
+	return append([]string{contour_xds_v3.CONSTANT_HASH_VALUE}, s.nodeGroups...)
+}
+
+// nodeGroupResources returns the given resources which are sent to the
+// Envoys of the snapshot key: listeners and route configurations not of
+// another node group, and all other resources.
+func (s *SnapshotHandler) nodeGroupResources(key string, resources map[envoy_resource_v3.Type][]envoy_types.Resource) map[envoy_resource_v3.Type][]envoy_types.Resource {
+	if len(s.nodeGroups) == 0 {
+		return resources
+	}
+
+	grouped := make(map[envoy_resource_v3.Type][]envoy_types.Resource, len(resources))
+	for resourceType, typeResources := range resources {
+		if resourceType != envoy_resource_v3.ListenerType && resourceType != envoy_resource_v3.RouteType {
+			grouped[resourceType] = typeResources
+			continue
+		}
+
+		for _, resource := range typeResources {
+			if group := s.nodeGroupOf(envoy_cache_v3.GetResourceName(resource)); group == "" || group == key {
+				grouped[resourceType] = append(grouped[resourceType], resource)
+			}
+		}
+	}
+	return grouped
+}
+
+// nodeGroupOf returns the node group whose name prefixes the given
+// resource name, or "" if there is none.
+func (s *SnapshotHandler) nodeGroupOf(name string) string {
+	for _, group := range s.nodeGroups {
+		if strings.HasPrefix(name, group+"/") {
+			return group
+		}
+	}
+	return ""
 }
 
 // asResources converts the given slice of values (that implement the envoy_types.Resource
@@ -155,6 +249,27 @@ func asResources[T proto.Message](messages []T) []envoy_types.Resource {
 	return protos
 }
 
+// equalResources returns true if a and b hold equal resources
+// of each type, in the same order.
+func equalResources(a, b map[envoy_resource_v3.Type][]envoy_types.Resource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for resourceType, resources := range a {
+		other, ok := b[resourceType]
+		if !ok || len(resources) != len(other) {
+			return false
+		}
+		for i := range resources {
+			if !proto.Equal(resources[i], other[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // parseResources converts an []ResourceCache to a map[envoy_types.ResponseType]ResourceCache
 // for faster indexing when creating new snapshots.
 func parseResources(resources []xdscache.ResourceCache) map[envoy_resource_v3.Type]xdscache.ResourceCache {
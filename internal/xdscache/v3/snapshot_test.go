@@ -0,0 +1,108 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectcontour/contour/internal/fixture"
+	contour_xds_v3 "github.com/projectcontour/contour/internal/xds/v3"
+	"github.com/projectcontour/contour/internal/xdscache"
+)
+
+func TestSnapshotHandlerNodeGroups(t *testing.T) {
+	listeners := &ListenerCache{}
+	listeners.Update(map[string]*envoy_config_listener_v3.Listener{
+		"stats-health":                     {Name: "stats-health"},
+		"projectcontour/gateway-1/http-80": {Name: "projectcontour/gateway-1/http-80"},
+		"projectcontour/gateway-2/http-80": {Name: "projectcontour/gateway-2/http-80"},
+	})
+
+	routes := &RouteCache{}
+	routes.Update(map[string]*envoy_config_route_v3.RouteConfiguration{
+		"projectcontour/gateway-1/http-80": {Name: "projectcontour/gateway-1/http-80"},
+		"projectcontour/gateway-2/http-80": {Name: "projectcontour/gateway-2/http-80"},
+	})
+
+	clusters := &ClusterCache{}
+	clusters.Update(map[string]*envoy_config_cluster_v3.Cluster{
+		"default/kuard/80": {Name: "default/kuard/80"},
+	})
+
+	sh := NewSnapshotHandler(
+		[]xdscache.ResourceCache{listeners, routes, clusters},
+		[]string{"projectcontour/gateway-1", "projectcontour/gateway-2"},
+		fixture.NewTestLogger(t),
+	)
+
+	names := func(key, typeURL string) []string {
+		snapshot, err := sh.defaultCache.GetSnapshot(key)
+		require.NoError(t, err)
+
+		var names []string
+		for name := range snapshot.GetResources(typeURL) {
+			names = append(names, name)
+		}
+		return names
+	}
+
+	// Each node group gets its own listeners and routes, along with
+	// those of no node group, and all other resources.
+	assert.ElementsMatch(t, []string{"stats-health", "projectcontour/gateway-1/http-80"}, names("projectcontour/gateway-1", envoy_resource_v3.ListenerType))
+	assert.ElementsMatch(t, []string{"projectcontour/gateway-1/http-80"}, names("projectcontour/gateway-1", envoy_resource_v3.RouteType))
+	assert.ElementsMatch(t, []string{"default/kuard/80"}, names("projectcontour/gateway-1", envoy_resource_v3.ClusterType))
+
+	assert.ElementsMatch(t, []string{"stats-health", "projectcontour/gateway-2/http-80"}, names("projectcontour/gateway-2", envoy_resource_v3.ListenerType))
+	assert.ElementsMatch(t, []string{"projectcontour/gateway-2/http-80"}, names("projectcontour/gateway-2", envoy_resource_v3.RouteType))
+
+	// Other Envoys only get the resources of no node group.
+	assert.ElementsMatch(t, []string{"stats-health"}, names(contour_xds_v3.CONSTANT_HASH_VALUE, envoy_resource_v3.ListenerType))
+	assert.Empty(t, names(contour_xds_v3.CONSTANT_HASH_VALUE, envoy_resource_v3.RouteType))
+	assert.ElementsMatch(t, []string{"default/kuard/80"}, names(contour_xds_v3.CONSTANT_HASH_VALUE, envoy_resource_v3.ClusterType))
+}
+
+func TestSnapshotHandlerKeepsUnchangedSnapshot(t *testing.T) {
+	listeners := &ListenerCache{}
+	listeners.Update(map[string]*envoy_config_listener_v3.Listener{
+		"ingress_http": {Name: "ingress_http"},
+	})
+
+	sh := NewSnapshotHandler([]xdscache.ResourceCache{listeners}, nil, fixture.NewTestLogger(t))
+
+	version := func() string {
+		snapshot, err := sh.defaultCache.GetSnapshot(contour_xds_v3.CONSTANT_HASH_VALUE)
+		require.NoError(t, err)
+		return snapshot.GetVersion(envoy_resource_v3.ListenerType)
+	}
+
+	first := version()
+
+	// Rebuilding without changes keeps the snapshot and its version.
+	sh.OnChange(nil)
+	assert.Equal(t, first, version())
+
+	// Changed resources get a new version.
+	listeners.Update(map[string]*envoy_config_listener_v3.Listener{
+		"ingress_https": {Name: "ingress_https"},
+	})
+	sh.OnChange(nil)
+	assert.NotEqual(t, first, version())
+}
@@ -79,7 +79,11 @@ type Configuration struct {
 // Certificates contains a set of Certificates as []byte each holding
 // the CA Cert along with with Contour & Envoy Certs.
 type Certificates struct {
+	// CACertificate holds the CA bundle. The first certificate in the
+	// bundle is the CA that issued the Contour & Envoy Certs, any
+	// following certificates are previous CAs kept during CA rollover.
 	CACertificate      []byte
+	CAPrivateKey       []byte
 	ContourCertificate []byte
 	ContourPrivateKey  []byte
 	EnvoyCertificate   []byte
@@ -101,6 +105,128 @@ func GenerateCerts(config *Configuration) (*Certificates, error) {
 		return nil, err
 	}
 
+	return issueCerts(config, caCertPEM, caKeyPEM, expiry)
+}
+
+// RenewCerts issues new certificates for Contour & Envoy from an existing
+// CA, returning them as a *Certificates struct or error if encountered.
+// caCertPEM may be a CA bundle, in which case the first certificate must
+// match caKeyPEM and the whole bundle is carried over to the result.
+// The lifetime of the new certificates is capped at the expiry of the CA.
+func RenewCerts(config *Configuration, caCertPEM, caKeyPEM []byte) (*Certificates, error) {
+	if config == nil {
+		config = &Configuration{}
+	}
+
+	caExpiry, err := CertificateExpiry(caCertPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := time.Now().Add(24 * time.Duration(uint32OrDefault(config.Lifetime, DefaultCertificateLifetime)) * time.Hour) //nolint:gosec // disable G115
+	if expiry.After(caExpiry) {
+		expiry = caExpiry
+	}
+
+	return issueCerts(config, caCertPEM, caKeyPEM, expiry)
+}
+
+// RotateCA generates a new CA Certificate to replace the CA of the given
+// certificates. The still valid certificates of the current CA bundle are
+// appended to the new CA bundle, and the Contour & Envoy Certs are left
+// untouched, so that peers keep trusting each other until the Contour &
+// Envoy Certs are renewed from the new CA with RenewCerts.
+func RotateCA(config *Configuration, current *Certificates) (*Certificates, error) {
+	if config == nil {
+		config = &Configuration{}
+	}
+
+	now := time.Now()
+	expiry := now.Add(24 * time.Duration(uint32OrDefault(config.Lifetime, DefaultCertificateLifetime)) * time.Hour) //nolint:gosec // disable G115
+	caCertPEM, caKeyPEM, err := newCA("Project Contour", expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := parseCertificates(current.CACertificate)
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range previous {
+		if now.After(cert.NotAfter) {
+			continue
+		}
+		caCertPEM = append(caCertPEM, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Raw,
+		})...)
+	}
+
+	return &Certificates{
+		CACertificate:      caCertPEM,
+		CAPrivateKey:       caKeyPEM,
+		ContourCertificate: current.ContourCertificate,
+		ContourPrivateKey:  current.ContourPrivateKey,
+		EnvoyCertificate:   current.EnvoyCertificate,
+		EnvoyPrivateKey:    current.EnvoyPrivateKey,
+	}, nil
+}
+
+// IssuedBy returns true if the first certificate in certPEM has been
+// signed by the first certificate in caCertPEM.
+func IssuedBy(certPEM, caCertPEM []byte) bool {
+	certificates, err := parseCertificates(certPEM)
+	if err != nil || len(certificates) == 0 {
+		return false
+	}
+	ca, err := parseCertificates(caCertPEM)
+	if err != nil || len(ca) == 0 {
+		return false
+	}
+
+	return certificates[0].CheckSignatureFrom(ca[0]) == nil
+}
+
+// CertificateExpiry returns the expiry time of the first certificate in
+// the given PEM data.
+func CertificateExpiry(certPEM []byte) (time.Time, error) {
+	certificates, err := parseCertificates(certPEM)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(certificates) == 0 {
+		return time.Time{}, fmt.Errorf("no certificates found in PEM data")
+	}
+
+	return certificates[0].NotAfter, nil
+}
+
+// parseCertificates returns all the certificates in the given PEM data.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, cert)
+	}
+
+	return certificates, nil
+}
+
+// issueCerts issues certificates for Contour & Envoy from the given CA keypair.
+func issueCerts(config *Configuration, caCertPEM, caKeyPEM []byte, expiry time.Time) (*Certificates, error) {
 	contourCert, contourKey, err := newCert(caCertPEM,
 		caKeyPEM,
 		expiry,
@@ -125,6 +251,7 @@ func GenerateCerts(config *Configuration) (*Certificates, error) {
 
 	return &Certificates{
 		CACertificate:      caCertPEM,
+		CAPrivateKey:       caKeyPEM,
 		ContourCertificate: contourCert,
 		ContourPrivateKey:  contourKey,
 		EnvoyCertificate:   envoyCert,
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -152,6 +153,72 @@ func TestGeneratedCertsValid(t *testing.T) {
 	}
 }
 
+func TestRenewCerts(t *testing.T) {
+	generated, err := GenerateCerts(&Configuration{Lifetime: 30})
+	require.NoError(t, err)
+
+	renewed, err := RenewCerts(&Configuration{}, generated.CACertificate, generated.CAPrivateKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, generated.CACertificate, renewed.CACertificate)
+	assert.Equal(t, generated.CAPrivateKey, renewed.CAPrivateKey)
+	assert.NotEqual(t, generated.ContourCertificate, renewed.ContourCertificate)
+	assert.NotEqual(t, generated.EnvoyCertificate, renewed.EnvoyCertificate)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(renewed.CACertificate))
+	require.NoError(t, verifyCert(renewed.ContourCertificate, roots, "contour", time.Now()))
+	require.NoError(t, verifyCert(renewed.EnvoyCertificate, roots, "envoy", time.Now()))
+
+	// The renewed certificates must not outlive the CA.
+	caExpiry, err := CertificateExpiry(renewed.CACertificate)
+	require.NoError(t, err)
+	contourExpiry, err := CertificateExpiry(renewed.ContourCertificate)
+	require.NoError(t, err)
+	assert.False(t, contourExpiry.After(caExpiry))
+}
+
+func TestRotateCA(t *testing.T) {
+	generated, err := GenerateCerts(&Configuration{})
+	require.NoError(t, err)
+
+	rotated, err := RotateCA(&Configuration{}, generated)
+	require.NoError(t, err)
+
+	// Leaf certificates are kept until renewed from the new CA.
+	assert.Equal(t, generated.ContourCertificate, rotated.ContourCertificate)
+	assert.Equal(t, generated.EnvoyCertificate, rotated.EnvoyCertificate)
+	assert.NotEqual(t, generated.CAPrivateKey, rotated.CAPrivateKey)
+	assert.False(t, IssuedBy(rotated.ContourCertificate, rotated.CACertificate))
+
+	// The bundle holds both the new and the previous CA.
+	bundle, err := parseCertificates(rotated.CACertificate)
+	require.NoError(t, err)
+	require.Len(t, bundle, 2)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(rotated.CACertificate))
+	require.NoError(t, verifyCert(rotated.ContourCertificate, roots, "contour", time.Now()))
+
+	renewed, err := RenewCerts(&Configuration{}, rotated.CACertificate, rotated.CAPrivateKey)
+	require.NoError(t, err)
+	assert.True(t, IssuedBy(renewed.ContourCertificate, renewed.CACertificate))
+	assert.True(t, IssuedBy(renewed.EnvoyCertificate, renewed.CACertificate))
+	require.NoError(t, verifyCert(renewed.EnvoyCertificate, roots, "envoy", time.Now()))
+}
+
+func TestCertificateExpiry(t *testing.T) {
+	generated, err := GenerateCerts(&Configuration{Lifetime: 10})
+	require.NoError(t, err)
+
+	expiry, err := CertificateExpiry(generated.EnvoyCertificate)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*24*time.Hour), expiry, time.Minute)
+
+	_, err = CertificateExpiry([]byte("not a certificate"))
+	require.Error(t, err)
+}
+
 func verifyCert(certPEM []byte, roots *x509.CertPool, dnsname string, currentTime time.Time) error {
 	block, _ := pem.Decode(certPEM)
 	if block == nil {
@@ -29,12 +29,39 @@ import (
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 )
 
-// Validate ensures that GatewayRef namespace/name is specified.
+// Validate ensures that either GatewayRef namespace/name, or the namespace/name
+// of each of the Gateways and their Envoy Services, is specified.
 func (g *GatewayParameters) Validate() error {
-	if g != nil && (g.GatewayRef.Namespace == "" || g.GatewayRef.Name == "") {
+	if g == nil {
+		return nil
+	}
+
+	if len(g.Gateways) > 0 {
+		if g.GatewayRef != (NamespacedName{}) {
+			return fmt.Errorf("invalid Gateway parameters specified: gateway ref and gateways must not both be provided")
+		}
+
+		seen := map[NamespacedName]bool{}
+		for _, gw := range g.Gateways {
+			if gw.GatewayRef.Namespace == "" || gw.GatewayRef.Name == "" {
+				return fmt.Errorf("invalid Gateway parameters specified: gateways gateway ref namespace and name must be provided")
+			}
+			if gw.EnvoyService.Namespace == "" || gw.EnvoyService.Name == "" {
+				return fmt.Errorf("invalid Gateway parameters specified: gateways envoy service namespace and name must be provided")
+			}
+			if seen[gw.GatewayRef] {
+				return fmt.Errorf("invalid Gateway parameters specified: gateway %s/%s provided more than once", gw.GatewayRef.Namespace, gw.GatewayRef.Name)
+			}
+			seen[gw.GatewayRef] = true
+		}
+	} else if g.GatewayRef.Namespace == "" || g.GatewayRef.Name == "" {
 		return fmt.Errorf("invalid Gateway parameters specified: gateway ref namespace and name must be provided")
 	}
 
+	if g.DataPlaneReadyQuorum < 0 {
+		return fmt.Errorf("invalid Gateway parameters specified: data plane ready quorum must not be negative")
+	}
+
 	return nil
 }
 
@@ -91,6 +118,25 @@ const (
 	PassThroughServerHeader    ServerHeaderTransformationType = "pass_through"
 )
 
+// OCSPStaplePolicy defines how OCSP responses stapled to serving
+// certificates are used.
+type OCSPStaplePolicy string
+
+func (o OCSPStaplePolicy) Validate() error {
+	switch o {
+	case LenientOCSPStaplePolicy, StrictOCSPStaplePolicy, MustStapleOCSPStaplePolicy:
+		return nil
+	default:
+		return fmt.Errorf("invalid OCSP staple policy %q", o)
+	}
+}
+
+const (
+	LenientOCSPStaplePolicy    OCSPStaplePolicy = "lenient"
+	StrictOCSPStaplePolicy     OCSPStaplePolicy = "strict"
+	MustStapleOCSPStaplePolicy OCSPStaplePolicy = "must-staple"
+)
+
 // AccessLogType is the name of a supported access logging mechanism.
 type AccessLogType string
 
@@ -178,6 +224,21 @@ type TLSParameters struct {
 	// to be used when establishing TLS connection to upstream
 	// cluster.
 	ClientCertificate NamespacedName `yaml:"envoy-client-certificate,omitempty"`
+
+	// CertificateExpiryWarningPeriod defines how long before a serving
+	// certificate expires a warning condition is added to the HTTPProxies
+	// and Gateway Listeners referencing it. Expired certificates always
+	// get a warning condition.
+	CertificateExpiryWarningPeriod string `yaml:"certificate-expiry-warning-period,omitempty"`
+
+	// RejectExpiredCertificates defines whether expired serving
+	// certificates are treated as invalid TLS Secrets.
+	RejectExpiredCertificates bool `yaml:"reject-expired-certificates,omitempty"`
+
+	// OCSPStaplePolicy defines how Envoy listeners use the OCSP
+	// responses stapled to serving certificates. Valid values are
+	// "lenient" (the default), "strict" and "must-staple".
+	OCSPStaplePolicy OCSPStaplePolicy `yaml:"ocsp-staple-policy,omitempty"`
 }
 
 // ProtocolParameters holds configuration details for TLS protocol specifics.
@@ -208,6 +269,22 @@ func (t TLSParameters) Validate() error {
 		return fmt.Errorf("invalid TLS Protocol Parameters: %w", err)
 	}
 
+	if t.OCSPStaplePolicy != "" {
+		if err := t.OCSPStaplePolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid TLS parameters: %w", err)
+		}
+	}
+
+	if t.CertificateExpiryWarningPeriod != "" {
+		period, err := time.ParseDuration(t.CertificateExpiryWarningPeriod)
+		if err != nil {
+			return fmt.Errorf("invalid TLS certificate expiry warning period: %w", err)
+		}
+		if period < 0 {
+			return fmt.Errorf("invalid TLS certificate expiry warning period: must not be negative")
+		}
+	}
+
 	return nil
 }
 
@@ -227,7 +304,34 @@ type ServerParameters struct{}
 type GatewayParameters struct {
 	// GatewayRef defines the specific Gateway that this Contour
 	// instance corresponds to.
+	//
+	// Exactly one of GatewayRef or Gateways must be provided.
+	GatewayRef NamespacedName `yaml:"gatewayRef,omitempty"`
+
+	// Gateways defines several Gateways, of the same GatewayClass,
+	// that this Contour instance corresponds to. Each Gateway is
+	// served by the Envoys whose node cluster is the Gateway's
+	// "namespace/name".
+	//
+	// Exactly one of GatewayRef or Gateways must be provided.
+	Gateways []ServedGatewayParameters `yaml:"gateways,omitempty"`
+
+	// DataPlaneReadyQuorum is the number of Envoys that must acknowledge
+	// the configuration built from the Gateway's current generation before
+	// the Gateway's DataPlaneReady condition is set to true.
+	//
+	// If unset, the DataPlaneReady condition is not set.
+	DataPlaneReadyQuorum int `yaml:"dataPlaneReadyQuorum,omitempty"`
+}
+
+// ServedGatewayParameters holds the configuration for one of several
+// Gateways served by a Contour instance.
+type ServedGatewayParameters struct {
+	// GatewayRef defines the Gateway.
 	GatewayRef NamespacedName `yaml:"gatewayRef"`
+
+	// EnvoyService defines the Service of the Envoys serving the Gateway.
+	EnvoyService NamespacedName `yaml:"envoyService"`
 }
 
 // TimeoutParameters holds various configurable proxy timeout values.
@@ -703,6 +807,17 @@ type Parameters struct {
 	// useEndpointSlices - configures contour to fetch endpoint data
 	// from k8s endpoint slices. defaults to true,
 	// if false then reading endpoint data from the k8s endpoints.
+	// incrementalDAGRebuild - configures contour to reuse the parts
+	// of the DAG computed from root HTTPProxies whose dependencies
+	// did not change. defaults to false.
+	// multiClusterServices - configures contour to watch multi-cluster
+	// ServiceImports, so that HTTPProxy services and Gateway API
+	// backendRefs can reference them. Requires useEndpointSlices.
+	// defaults to false.
+	// routeStats - configures Envoy to emit route-level, virtual
+	// cluster, TCP proxy and upstream cluster statistics named after the
+	// HTTPProxy, Ingress or Gateway API route that defines each route or
+	// TCP proxy. defaults to false.
 	FeatureFlags []string `yaml:"featureFlags,omitempty"`
 }
 
@@ -778,6 +893,49 @@ type GlobalExternalAuthorization struct {
 	// WithRequestBody specifies configuration for sending the client request's body to authorization server.
 	// +optional
 	WithRequestBody *GlobalAuthorizationServerBufferSettings `yaml:"withRequestBody,omitempty"`
+	// HTTPService configures Envoy to check client requests by sending
+	// them to the authorization server as plain HTTP requests, rather
+	// than using the gRPC authorization API.
+	// +optional
+	HTTPService *GlobalAuthorizationHTTPService `yaml:"httpService,omitempty"`
+	// AllowedRequestHeaders lists the client request headers that are sent to the authorization server.
+	// If empty, all headers are sent to gRPC authorization servers.
+	// +optional
+	AllowedRequestHeaders []string `yaml:"allowedRequestHeaders,omitempty"`
+	// AllowedUpstreamHeaders lists the headers that the authorization server may set on an allowed
+	// client request before it is forwarded to the upstream service.
+	// +optional
+	AllowedUpstreamHeaders []string `yaml:"allowedUpstreamHeaders,omitempty"`
+	// AllowedClientHeaders lists the headers of a denied authorization response that are returned
+	// to the client. Only HTTP authorization servers are supported.
+	// +optional
+	AllowedClientHeaders []string `yaml:"allowedClientHeaders,omitempty"`
+	// MetadataNamespaces lists the dynamic metadata namespaces that are sent to the authorization server.
+	// +optional
+	MetadataNamespaces []string `yaml:"metadataNamespaces,omitempty"`
+	// RouteMetadataNamespaces lists the route metadata namespaces that are sent to the authorization server.
+	// +optional
+	RouteMetadataNamespaces []string `yaml:"routeMetadataNamespaces,omitempty"`
+}
+
+// GlobalAuthorizationHTTPService configures an authorization server that is reached over plain HTTP.
+type GlobalAuthorizationHTTPService struct {
+	// PathPrefix is prepended to the client request path when sending the authorization request.
+	// +optional
+	PathPrefix string `yaml:"pathPrefix,omitempty"`
+}
+
+// Validate ensures that the global external authorization configuration is valid.
+func (g GlobalExternalAuthorization) Validate() error {
+	if g.HTTPService == nil {
+		return nil
+	}
+
+	if g.HTTPService.PathPrefix != "" && !strings.HasPrefix(g.HTTPService.PathPrefix, "/") {
+		return fmt.Errorf("globalExtAuth.httpService.pathPrefix %q must start with \"/\"", g.HTTPService.PathPrefix)
+	}
+
+	return nil
 }
 
 // GlobalAuthorizationServerBufferSettings enables ExtAuthz filter to buffer client request data and send it as part of authorization request
@@ -1043,6 +1201,10 @@ func (p *Parameters) Validate() error {
 		return err
 	}
 
+	if err := p.GlobalExternalAuthorization.Validate(); err != nil {
+		return err
+	}
+
 	return p.Listener.Validate()
 }
 
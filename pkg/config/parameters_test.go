@@ -129,6 +129,15 @@ func TestValidateClusterDNSFamilyType(t *testing.T) {
 	require.NoError(t, AllClusterDNSFamily.Validate())
 }
 
+func TestValidateOCSPStaplePolicy(t *testing.T) {
+	require.Error(t, OCSPStaplePolicy("").Validate())
+	require.Error(t, OCSPStaplePolicy("always").Validate())
+
+	require.NoError(t, LenientOCSPStaplePolicy.Validate())
+	require.NoError(t, StrictOCSPStaplePolicy.Validate())
+	require.NoError(t, MustStapleOCSPStaplePolicy.Validate())
+}
+
 func TestValidateServerHeaderTranformationType(t *testing.T) {
 	require.Error(t, ServerHeaderTransformationType("").Validate())
 	require.Error(t, ServerHeaderTransformationType("foo").Validate())
@@ -193,6 +202,45 @@ func TestValidateGatewayParameters(t *testing.T) {
 	// Name is required
 	gw = &GatewayParameters{GatewayRef: NamespacedName{Namespace: "foo"}}
 	require.Error(t, gw.Validate())
+
+	// Quorum must not be negative
+	gw = &GatewayParameters{GatewayRef: NamespacedName{Namespace: "foo", Name: "bar"}, DataPlaneReadyQuorum: -1}
+	require.Error(t, gw.Validate())
+
+	// Several gateways may be provided instead of a gateway ref
+	gw = &GatewayParameters{Gateways: []ServedGatewayParameters{
+		{GatewayRef: NamespacedName{Namespace: "foo", Name: "bar"}, EnvoyService: NamespacedName{Namespace: "foo", Name: "envoy-bar"}},
+		{GatewayRef: NamespacedName{Namespace: "foo", Name: "baz"}, EnvoyService: NamespacedName{Namespace: "foo", Name: "envoy-baz"}},
+	}}
+	require.NoError(t, gw.Validate())
+
+	// Gateway ref and gateways are mutually exclusive
+	gw.GatewayRef = NamespacedName{Namespace: "foo", Name: "bar"}
+	require.Error(t, gw.Validate())
+	gw.GatewayRef = NamespacedName{}
+
+	// Envoy service is required
+	gw.Gateways[1].EnvoyService = NamespacedName{}
+	require.Error(t, gw.Validate())
+
+	// Gateways must be unique
+	gw.Gateways[1] = gw.Gateways[0]
+	require.Error(t, gw.Validate())
+}
+
+func TestValidateGlobalExternalAuthorization(t *testing.T) {
+	require.NoError(t, GlobalExternalAuthorization{}.Validate())
+
+	auth := GlobalExternalAuthorization{
+		ExtensionService: "projectcontour/authserver",
+		HTTPService: &GlobalAuthorizationHTTPService{
+			PathPrefix: "/auth",
+		},
+	}
+	require.NoError(t, auth.Validate())
+
+	auth.HTTPService.PathPrefix = "auth"
+	require.Error(t, auth.Validate())
 }
 
 func TestValidateHTTPVersionType(t *testing.T) {
@@ -373,6 +421,11 @@ tls:
   - NOTVALID
 `)
 
+	check(`
+tls:
+  ocsp-staple-policy: always
+`)
+
 	check(`
 timeouts:
   request-timeout: none
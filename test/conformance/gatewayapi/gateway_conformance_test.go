@@ -36,6 +36,7 @@ import (
 	gatewayapi_v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayapi_v1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayapi_x_v1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 	"sigs.k8s.io/gateway-api/conformance"
 	conformance_v1 "sigs.k8s.io/gateway-api/conformance/apis/v1"
 	"sigs.k8s.io/gateway-api/conformance/tests"
@@ -60,6 +61,7 @@ func TestGatewayConformance(t *testing.T) {
 	require.NoError(t, gatewayapi_v1alpha3.Install(client.Scheme()))
 	require.NoError(t, gatewayapi_v1beta1.Install(client.Scheme()))
 	require.NoError(t, gatewayapi_v1.Install(client.Scheme()))
+	require.NoError(t, gatewayapi_x_v1alpha1.Install(client.Scheme()))
 	require.NoError(t, apiextensions_v1.AddToScheme(client.Scheme()))
 
 	options := suite.ConformanceOptions{
@@ -528,6 +528,8 @@ var _ = Describe("HTTPProxy", func() {
 
 	f.NamespacedTest("httpproxy-local-rate-limiting-route", testLocalRateLimitingRoute)
 
+	f.NamespacedTest("httpproxy-local-rate-limiting-descriptors", testLocalRateLimitingDescriptors)
+
 	Context("global rate limiting", func() {
 		withRateLimitService := func(body e2e.NamespacedTestBody) e2e.NamespacedTestBody {
 			return func(namespace string) {
@@ -17,6 +17,7 @@ package httpproxy
 
 import (
 	"context"
+	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/stretchr/testify/require"
@@ -198,3 +199,86 @@ func testLocalRateLimitingRoute(namespace string) {
 		require.Truef(t, ok, "expected 200 response code for non-rate-limited route, got %d", res.StatusCode)
 	})
 }
+
+func testLocalRateLimitingDescriptors(namespace string) {
+	Specify("local rate limit descriptors without a value rate limit each value separately", func() {
+		t := f.T()
+
+		f.Fixtures.Echo.Deploy(namespace, "echo")
+
+		p := &contour_v1.HTTPProxy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "descriptorlocalratelimit",
+			},
+			Spec: contour_v1.HTTPProxySpec{
+				VirtualHost: &contour_v1.VirtualHost{
+					Fqdn: "descriptorlocalratelimit.projectcontour.io",
+				},
+				Routes: []contour_v1.Route{
+					{
+						Services: []contour_v1.Service{
+							{
+								Name: "echo",
+								Port: 80,
+							},
+						},
+						RateLimitPolicy: &contour_v1.RateLimitPolicy{
+							Local: &contour_v1.LocalRateLimitPolicy{
+								Requests: 100,
+								Unit:     "second",
+								Descriptors: []contour_v1.LocalRateLimitDescriptor{
+									{
+										Entries: []contour_v1.LocalRateLimitDescriptorEntry{
+											{
+												RateLimitDescriptorEntry: contour_v1.RateLimitDescriptorEntry{
+													RequestHeader: &contour_v1.RequestHeaderDescriptor{
+														HeaderName:    "X-Client",
+														DescriptorKey: "client",
+													},
+												},
+											},
+										},
+										Requests: 1,
+										Unit:     "hour",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		require.True(f.T(), f.CreateHTTPProxyAndWaitFor(p, e2e.HTTPProxyValid))
+
+		clientRequest := func(client string, statusCode int) {
+			t.Helper()
+
+			res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+				Host:        p.Spec.VirtualHost.Fqdn,
+				RequestOpts: []func(*http.Request){e2e.OptSetHeaders(map[string]string{"X-Client": client})},
+				Condition:   e2e.HasStatusCode(statusCode),
+			})
+			require.NotNil(t, res, "request never succeeded")
+			require.Truef(t, ok, "expected %d response code for client %q, got %d", statusCode, client, res.StatusCode)
+		}
+
+		// The first request of client "a" is allowed,
+		// and the next ones are rate limited.
+		clientRequest("a", 200)
+		clientRequest("a", 429)
+
+		// Client "b" has its own token bucket, so it
+		// is not rate limited by the requests of "a".
+		clientRequest("b", 200)
+
+		// Requests without the header use the token
+		// bucket of the policy.
+		res, ok := f.HTTP.RequestUntil(&e2e.HTTPRequestOpts{
+			Host:      p.Spec.VirtualHost.Fqdn,
+			Condition: e2e.HasStatusCode(200),
+		})
+		require.NotNil(t, res, "request never succeeded")
+		require.Truef(t, ok, "expected 200 response code, got %d", res.StatusCode)
+	})
+}